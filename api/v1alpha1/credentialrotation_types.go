@@ -0,0 +1,154 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CredentialRotationSpec defines the desired state of a CredentialRotation.
+// The operator periodically generates a new password for every listed user, applies it on the
+// units of the UnitSet through the unit-agent and stores it AES-encrypted in the Secret mounted
+// into the units, so that the unit-agent keeps working without restart.
+type CredentialRotationSpec struct {
+	// UnitSet is the name of the UnitSet, in the same namespace, whose users are rotated.
+	UnitSet string `json:"unitSet"`
+
+	// Type specifies the type of the units in the UnitSet.
+	// Supported types are mysql, postgresql, redis and mongodb.
+	Type UnitType `json:"type"`
+
+	// AdminUsername is the privileged account the unit-agent uses to change the passwords.
+	// It is only rotated on mysql and redis with RetainCurrentPassword, as the unit-agents of
+	// the other units keep logging in with its previous password until every unit applied it.
+	AdminUsername string `json:"adminUsername"`
+
+	// SecretName is the name of the Secret holding the AES-encrypted password files
+	// mounted into the units, one key per user.
+	SecretName string `json:"secretName"`
+
+	// Users lists the users whose password is rotated, such as the backup, replication,
+	// clone or monitor users. The key of each user in the Secret is the user name.
+	// +kubebuilder:validation:MinItems=1
+	Users []CredentialRotationUser `json:"users"`
+
+	// Interval is the time between two rotations of a user, defaults to 90 days.
+	// +kubebuilder:default="2160h"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// PasswordLength is the length of the generated passwords.
+	// +kubebuilder:validation:Minimum=16
+	// +kubebuilder:validation:Maximum=128
+	// +kubebuilder:default=32
+	// +optional
+	PasswordLength int `json:"passwordLength,omitempty"`
+
+	// RetainCurrentPassword keeps the previous password valid until the next rotation where the
+	// engine supports dual passwords (mysql RETAIN CURRENT PASSWORD, redis multiple ACL passwords).
+	// Other engines switch to the new password immediately.
+	// +kubebuilder:default=true
+	// +optional
+	RetainCurrentPassword *bool `json:"retainCurrentPassword,omitempty"`
+
+	// Suspend pauses the rotation.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// CredentialRotationUser describes a user whose password is rotated.
+type CredentialRotationUser struct {
+	// Name is the name of the database account and of its key in the Secret.
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	Name string `json:"name"`
+
+	// Host is the host part of a mysql account, defaults to "%".
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// AuthDatabase is the database of a mongodb user, defaults to "admin".
+	// +optional
+	AuthDatabase string `json:"authDatabase,omitempty"`
+}
+
+// CredentialRotationStatus defines the observed state of a CredentialRotation.
+type CredentialRotationStatus struct {
+	// Result indicates the outcome of the last reconciliation.
+	// +optional
+	Result Result `json:"result,omitempty"`
+
+	// Message contains details about the last reconciliation, such as error details.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Users reports the last rotation of every user.
+	// +optional
+	Users []CredentialRotationUserStatus `json:"users,omitempty"`
+
+	// NextRotationTime is the time the next user is due for rotation.
+	// +optional
+	NextRotationTime *metav1.Time `json:"nextRotationTime,omitempty"`
+}
+
+// CredentialRotationUserStatus reports the rotation of a user.
+type CredentialRotationUserStatus struct {
+	// Name is the name of the user.
+	Name string `json:"name"`
+
+	// LastRotationTime is the time the password of the user was last rotated.
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=crot
+// +kubebuilder:printcolumn:name="UNITSET",type=string,JSONPath=`.spec.unitSet`
+// +kubebuilder:printcolumn:name="TYPE",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="RESULT",type=string,JSONPath=`.status.result`
+// +kubebuilder:printcolumn:name="NEXT ROTATION",type="date",JSONPath=".status.nextRotationTime"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// CredentialRotation is the Schema for the credentialrotations API
+type CredentialRotation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CredentialRotationSpec   `json:"spec,omitempty"`
+	Status CredentialRotationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CredentialRotationList contains a list of CredentialRotation
+type CredentialRotationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CredentialRotation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CredentialRotation{}, &CredentialRotationList{})
+}
+
+// UserStatus returns the status of the user, or nil when the user was never rotated.
+func (r *CredentialRotation) UserStatus(name string) *CredentialRotationUserStatus {
+	for i := range r.Status.Users {
+		if r.Status.Users[i].Name == name {
+			return &r.Status.Users[i]
+		}
+	}
+
+	return nil
+}
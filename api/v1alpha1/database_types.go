@@ -0,0 +1,142 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatabaseSpec defines the desired state of a Database.
+// The operator creates the database through the unit-agent of every unit of the referenced UnitSet.
+// Deleting a Database never drops the database or its data.
+type DatabaseSpec struct {
+	// UnitSet is the name of the UnitSet, in the same namespace, whose units host the database.
+	UnitSet string `json:"unitSet"`
+
+	// Type specifies the type of the units in the UnitSet.
+	// Supported types are mysql, postgresql and clickhouse.
+	Type UnitType `json:"type"`
+
+	// AdminUsername is the privileged account the unit-agent uses to create the database.
+	// Its password is read from the secret mounted into the unit.
+	AdminUsername string `json:"adminUsername"`
+
+	// DatabaseName is the name of the database.
+	// Defaults to the name of the Database.
+	// +optional
+	DatabaseName string `json:"databaseName,omitempty"`
+
+	// Mysql holds options specific to mysql databases.
+	// +optional
+	Mysql *MysqlDatabaseOptions `json:"mysql,omitempty"`
+
+	// Postgresql holds options specific to postgresql databases.
+	// +optional
+	Postgresql *PostgresqlDatabaseOptions `json:"postgresql,omitempty"`
+
+	// ClickHouse holds options specific to clickhouse databases.
+	// +optional
+	ClickHouse *ClickHouseDatabaseOptions `json:"clickhouse,omitempty"`
+}
+
+// MysqlDatabaseOptions holds options specific to mysql databases.
+type MysqlDatabaseOptions struct {
+	// CharacterSet is the default character set of the database.
+	// +optional
+	CharacterSet string `json:"characterSet,omitempty"`
+
+	// Collation is the default collation of the database.
+	// +optional
+	Collation string `json:"collation,omitempty"`
+}
+
+// PostgresqlDatabaseOptions holds options specific to postgresql databases.
+type PostgresqlDatabaseOptions struct {
+	// Owner is the role owning the database.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// Encoding is the character set encoding of the database.
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// ClickHouseDatabaseOptions holds options specific to clickhouse databases.
+type ClickHouseDatabaseOptions struct {
+	// Engine is the database engine, for example Atomic or Replicated.
+	// +optional
+	Engine string `json:"engine,omitempty"`
+}
+
+// DatabaseStatus defines the observed state of a Database.
+type DatabaseStatus struct {
+	// Result indicates the outcome of the last synchronization.
+	// +optional
+	Result Result `json:"result,omitempty"`
+
+	// Message contains details about the last synchronization, such as error details.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation applied by the last successful synchronization.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Units lists the units synchronized by the last successful synchronization.
+	// +optional
+	Units []string `json:"units,omitempty"`
+
+	// LastSyncTime is the timestamp of the last synchronization.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=udb
+// +kubebuilder:printcolumn:name="UNITSET",type=string,JSONPath=`.spec.unitSet`
+// +kubebuilder:printcolumn:name="TYPE",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="RESULT",type=string,JSONPath=`.status.result`
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Database is the Schema for the databases API
+type Database struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseSpec   `json:"spec,omitempty"`
+	Status DatabaseStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseList contains a list of Database
+type DatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Database `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Database{}, &DatabaseList{})
+}
+
+// DatabaseName returns the name of the database managed by the Database.
+func (d *Database) DatabaseName() string {
+	if d.Spec.DatabaseName != "" {
+		return d.Spec.DatabaseName
+	}
+
+	return d.Name
+}
@@ -0,0 +1,207 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeletionPolicy defines what happens to the database object when the custom resource is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete removes the object from the database.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+
+	// DeletionPolicyRetain leaves the object in the database.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
+// DatabaseUserSpec defines the desired state of a DatabaseUser.
+// The operator creates the account on every unit of the referenced UnitSet through the unit-agent.
+// Replicated engines (mysql, postgresql, mongodb) only apply the change on the writable instance.
+type DatabaseUserSpec struct {
+	// UnitSet is the name of the UnitSet, in the same namespace, whose units host the user.
+	UnitSet string `json:"unitSet"`
+
+	// Type specifies the type of the units in the UnitSet.
+	// Supported types are mysql, postgresql, proxysql, redis, mongodb and clickhouse.
+	Type UnitType `json:"type"`
+
+	// AdminUsername is the privileged account the unit-agent uses to manage the user.
+	// Its password is read from the secret mounted into the unit.
+	AdminUsername string `json:"adminUsername"`
+
+	// Username is the name of the database account.
+	// Defaults to the name of the DatabaseUser.
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// PasswordSecretRef selects the key of a Secret, in the same namespace, holding the password.
+	// Changes to the Secret are applied to the account.
+	PasswordSecretRef corev1.SecretKeySelector `json:"passwordSecretRef"`
+
+	// Privileges lists the privileges granted to the user.
+	// For mongodb each privilege is the name of a role on the database.
+	// Privileges removed from the list are revoked on the next synchronization.
+	// +optional
+	Privileges []DatabasePrivilege `json:"privileges,omitempty"`
+
+	// Mysql holds options specific to mysql accounts.
+	// +optional
+	Mysql *MysqlUserOptions `json:"mysql,omitempty"`
+
+	// Proxysql holds options specific to proxysql mysql_users entries.
+	// +optional
+	Proxysql *ProxysqlUserOptions `json:"proxysql,omitempty"`
+
+	// Redis holds options specific to redis ACL users.
+	// +optional
+	Redis *RedisUserOptions `json:"redis,omitempty"`
+
+	// MongoDB holds options specific to mongodb users.
+	// +optional
+	MongoDB *MongoDBUserOptions `json:"mongodb,omitempty"`
+
+	// DeletionPolicy defines whether the account is dropped when the DatabaseUser is deleted.
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// DatabasePrivilege describes privileges granted on a database or table.
+type DatabasePrivilege struct {
+	// Database is the database the privileges apply to, "*" means all databases.
+	Database string `json:"database"`
+
+	// Table restricts the privileges to a table of the database.
+	// Empty means the whole database. For postgresql "*" means all tables of the public schema.
+	// +optional
+	Table string `json:"table,omitempty"`
+
+	// Privileges is the list of privileges, for example SELECT or ALL PRIVILEGES.
+	// +kubebuilder:validation:MinItems=1
+	Privileges []string `json:"privileges"`
+}
+
+// MysqlUserOptions holds options specific to mysql accounts.
+type MysqlUserOptions struct {
+	// Host is the host part of the account, defaults to "%".
+	// +optional
+	Host string `json:"host,omitempty"`
+}
+
+// ProxysqlUserOptions holds options specific to proxysql mysql_users entries.
+type ProxysqlUserOptions struct {
+	// DefaultHostgroup is the hostgroup queries are routed to when no query rule matches.
+	// +optional
+	DefaultHostgroup int64 `json:"defaultHostgroup,omitempty"`
+
+	// DefaultSchema is the schema selected when the connection is opened.
+	// +optional
+	DefaultSchema string `json:"defaultSchema,omitempty"`
+
+	// MaxConnections is the maximum number of frontend connections of the user.
+	// +optional
+	MaxConnections int64 `json:"maxConnections,omitempty"`
+}
+
+// RedisUserOptions holds options specific to redis ACL users.
+type RedisUserOptions struct {
+	// ACLRules are the ACL rules of the user, for example "~app:*" or "+@read".
+	// Password rules are not allowed, the password comes from PasswordSecretRef.
+	// +optional
+	ACLRules []string `json:"aclRules,omitempty"`
+}
+
+// MongoDBUserOptions holds options specific to mongodb users.
+type MongoDBUserOptions struct {
+	// AuthDatabase is the database the user is created in, defaults to "admin".
+	// +optional
+	AuthDatabase string `json:"authDatabase,omitempty"`
+}
+
+// DatabaseUserStatus defines the observed state of a DatabaseUser.
+type DatabaseUserStatus struct {
+	// Result indicates the outcome of the last synchronization.
+	// +optional
+	Result Result `json:"result,omitempty"`
+
+	// Message contains details about the last synchronization, such as error details.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation applied by the last successful synchronization.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// PasswordSecretVersion is the resource version of the password Secret
+	// applied by the last successful synchronization.
+	// +optional
+	PasswordSecretVersion string `json:"passwordSecretVersion,omitempty"`
+
+	// Units lists the units synchronized by the last successful synchronization.
+	// +optional
+	Units []string `json:"units,omitempty"`
+
+	// Privileges lists the privileges applied by the last successful synchronization,
+	// the ones no longer in the spec are revoked by the next one.
+	// +optional
+	Privileges []DatabasePrivilege `json:"privileges,omitempty"`
+
+	// LastSyncTime is the timestamp of the last synchronization.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=dbu
+// +kubebuilder:printcolumn:name="UNITSET",type=string,JSONPath=`.spec.unitSet`
+// +kubebuilder:printcolumn:name="TYPE",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="RESULT",type=string,JSONPath=`.status.result`
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DatabaseUser is the Schema for the databaseusers API
+type DatabaseUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseUserSpec   `json:"spec,omitempty"`
+	Status DatabaseUserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseUserList contains a list of DatabaseUser
+type DatabaseUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseUser{}, &DatabaseUserList{})
+}
+
+// AccountName returns the name of the database account managed by the DatabaseUser.
+func (u *DatabaseUser) AccountName() string {
+	if u.Spec.Username != "" {
+		return u.Spec.Username
+	}
+
+	return u.Name
+}
@@ -19,8 +19,8 @@ import (
 )
 
 // UnitType defines the type of unit this GrpcCall will interact with.
-// Currently supported types are "mysql", "proxysql", "redis", "redis-sentinel", "mongodb", "milvus", "postgresql" and "clickhouse".
-// +kubebuilder:validation:Enum=mysql;postgresql;proxysql;redis;redis-sentinel;mongodb;milvus;clickhouse
+// Currently supported types are "mysql", "proxysql", "redis", "redis-cluster", "redis-sentinel", "mongodb", "milvus", "postgresql" and "clickhouse".
+// +kubebuilder:validation:Enum=mysql;postgresql;proxysql;redis;redis-cluster;redis-sentinel;mongodb;milvus;clickhouse
 type UnitType string
 
 const (
@@ -36,6 +36,9 @@ const (
 	// RedisType represents a Redis unit.
 	RedisType UnitType = "redis"
 
+	// RedisClusterType represents a Redis unit running in cluster mode.
+	RedisClusterType UnitType = "redis-cluster"
+
 	// SentinelType represents a Redis Sentinel unit.
 	SentinelType UnitType = "redis-sentinel"
 
@@ -51,7 +54,7 @@ const (
 
 // Action defines the specific operation to be sent to the unit-agent.
 // Each action corresponds to a gRPC method exposed by the unit-agent.
-// +kubebuilder:validation:Enum=logical-backup;physical-backup;restore;gtid-purge;set-variable;clone;backup
+// +kubebuilder:validation:Enum=logical-backup;physical-backup;restore;gtid-purge;set-variable;clone;backup;add-node;replicate;rebalance;failover;cluster-health;monitor;remove-master;reset;list-masters;list-replicas;initiate;add-member;remove-member;set-member;step-down;replica-set-status;archive-oplog;list-backups;get-backup;delete-backup;backup-status;start;stop;restart;status
 type Action string
 
 const (
@@ -68,6 +71,8 @@ const (
 	GtidPurgeAction Action = "gtid-purge"
 
 	// SetVariableAction instructs the agent to set runtime configuration parameters.
+	// Parameters either carry a single key and value, or a "variables" map which
+	// is applied as one batch, optionally with "dryRun" to only report the diff.
 	SetVariableAction Action = "set-variable"
 
 	// CloneAction instructs the agent to perform a clone operation from another instance.
@@ -75,6 +80,87 @@ const (
 
 	// BackupAction instructs the agent to perform a backup operation from another instance.
 	BackupAction Action = "backup"
+
+	// AddNodeAction instructs the agent to join the empty unit to a Redis Cluster,
+	// as a replica when "masterId" is given.
+	AddNodeAction Action = "add-node"
+
+	// ReplicateAction instructs the agent to make the Redis Cluster node a replica of "masterId".
+	ReplicateAction Action = "replicate"
+
+	// RebalanceAction instructs the agent to move slots until every Redis Cluster master holds its share.
+	RebalanceAction Action = "rebalance"
+
+	// FailoverAction instructs the agent to promote the Redis Cluster replica with CLUSTER FAILOVER,
+	// or the Redis Sentinel to fail over "masterName" with SENTINEL FAILOVER.
+	FailoverAction Action = "failover"
+
+	// ClusterHealthAction instructs the agent to report the slot coverage and failing nodes of a Redis Cluster.
+	ClusterHealthAction Action = "cluster-health"
+
+	// MonitorAction instructs the Redis Sentinel to monitor the "masterName" replication group.
+	MonitorAction Action = "monitor"
+
+	// RemoveMasterAction instructs the Redis Sentinel to stop monitoring "masterName".
+	RemoveMasterAction Action = "remove-master"
+
+	// ResetAction instructs the Redis Sentinel to reset the masters matching "pattern".
+	ResetAction Action = "reset"
+
+	// ListMastersAction instructs the Redis Sentinel to report the monitored masters.
+	ListMastersAction Action = "list-masters"
+
+	// ListReplicasAction instructs the Redis Sentinel to report the replicas of "masterName".
+	ListReplicasAction Action = "list-replicas"
+
+	// InitiateAction instructs the MongoDB agent to initiate the replica set with "members".
+	InitiateAction Action = "initiate"
+
+	// AddMemberAction instructs the MongoDB agent to add "member" to the replica set.
+	AddMemberAction Action = "add-member"
+
+	// RemoveMemberAction instructs the MongoDB agent to remove the member at "host" from the replica set.
+	RemoveMemberAction Action = "remove-member"
+
+	// SetMemberAction instructs the MongoDB agent to change the priority, votes or hidden setting of "member".
+	SetMemberAction Action = "set-member"
+
+	// StepDownAction instructs the MongoDB agent to step down the primary of the replica set.
+	StepDownAction Action = "step-down"
+
+	// ReplicaSetStatusAction instructs the MongoDB agent to report the member states and replication lag.
+	ReplicaSetStatusAction Action = "replica-set-status"
+
+	// ArchiveOplogAction instructs the MongoDB agent to archive the oplog entries written since the last run,
+	// with interval_seconds it keeps archiving them until the GrpcCall is suspended or times out.
+	ArchiveOplogAction Action = "archive-oplog"
+
+	// ListBackupsAction instructs the Milvus agent to list the backups under the backup root path.
+	ListBackupsAction Action = "list-backups"
+
+	// GetBackupAction instructs the Milvus agent to describe a backup.
+	GetBackupAction Action = "get-backup"
+
+	// DeleteBackupAction instructs the Milvus agent to delete a backup.
+	DeleteBackupAction Action = "delete-backup"
+
+	// BackupStatusAction instructs the ClickHouse agent to report the progress of an asynchronous backup or restore.
+	BackupStatusAction Action = "backup-status"
+
+	// StartAction instructs the agent of a unit of any type to start its process.
+	StartAction Action = "start"
+
+	// StopAction instructs the agent of a unit of any type to stop its process, giving
+	// it "gracefulTimeoutSeconds" to stop. The unit is annotated so that the unit
+	// controller does not start the process again until a start or restart action.
+	StopAction Action = "stop"
+
+	// RestartAction instructs the agent of a unit of any type to stop its process,
+	// giving it "gracefulTimeoutSeconds" to stop, and start it again.
+	RestartAction Action = "restart"
+
+	// StatusAction instructs the agent of a unit of any type to report the state of its process.
+	StatusAction Action = "status"
 )
 
 // GrpcCallSpec defines the desired behavior of a GrpcCall custom resource.
@@ -82,31 +168,112 @@ const (
 type GrpcCallSpec struct {
 	// TargetUnit is the name of the target Unit custom resource.
 	// This identifies which unit's agent the request should be sent to.
-	TargetUnit string `json:"targetUnit"`
+	// Exactly one of TargetUnit and TargetUnitSet is set.
+	// +optional
+	TargetUnit string `json:"targetUnit,omitempty"`
+
+	// TargetUnitSet sends the request to units of a UnitSet chosen by a
+	// selector, instead of to the fixed TargetUnit.
+	// +optional
+	TargetUnitSet *UnitSetTarget `json:"targetUnitSet,omitempty"`
 
 	// Type specifies the type of the target unit (e.g., mysql, proxysql, postgresql).
 	// This helps the operator determine how to format and route the request.
 	Type UnitType `json:"type"`
 
-	// Action specifies which gRPC method should be called on the unit-agent.
-	Action Action `json:"action"`
+	// Action specifies which gRPC method should be called on the unit-agent,
+	// as an alias of a method of the unit type.
+	// Exactly one of Action and Method is set.
+	// +optional
+	Action Action `json:"action,omitempty"`
+
+	// Method is the full name of the unit-agent gRPC method to call, as
+	// "<package>.<Service>/<Method>", e.g. "mysql.MysqlOperation/PhysicalBackup".
+	// Methods the operator was not built with are resolved through the gRPC
+	// server reflection of the unit-agent, so that a new unit-agent RPC can be
+	// called without an operator release.
+	// +kubebuilder:validation:Pattern=`^/?[A-Za-z_][A-Za-z0-9_.]*/[A-Za-z_][A-Za-z0-9_]*$`
+	// +optional
+	Method string `json:"method,omitempty"`
 
 	// ttlSecondsAfterFinished limits the lifetime of a Grpc Call that has finished
 	// execution (either Complete or Failed). If this field is set,
 	// ttlSecondsAfterFinished after the Grpc Call finishes, it is eligible to be
-	// automatically deleted.
-	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished"`
+	// automatically deleted. GrpcCalls created for the units of a fanned out
+	// GrpcCall or for the steps of an OperationPlan leave it unset, they are
+	// deleted along with their owner.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// TimeoutSeconds limits how long each attempt of the gRPC call may run. An
+	// attempt still running after TimeoutSeconds is cancelled on the unit-agent
+	// and fails with the DeadlineExceeded code, finishing the call with the
+	// TimedOut result unless it is retried. No limit is applied when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// ActiveDeadlineSeconds limits how long the GrpcCall may be active, across
+	// all attempts and the backoff between them, counted from the first start.
+	// The running attempt is then cancelled and the call finishes TimedOut.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// RetryPolicy retries the gRPC call when an attempt fails with a retryable
+	// code. The call is attempted once when unset.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// Suspend cancels a GrpcCall which is not finished yet, together with the
+	// operation running on the unit-agent. Deleting the GrpcCall does the same.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
 
 	// Parameters provides a flexible map of key-value pairs used as arguments
-	// to the gRPC call. The exact keys depend on the action type.
+	// to the gRPC call. The exact keys depend on the action type, they are the
+	// fields of the request message of the method and validated against it.
 	// For example: {"BACKUP_MODE": "physical", "S3_BUCKET": "my-bucket"}
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Parameters map[string]apiextensionsv1.JSON `json:"parameters"`
 }
 
+// GrpcCode is the name of a gRPC status code, as in google.golang.org/grpc/codes.
+// +kubebuilder:validation:Enum=Canceled;Unknown;InvalidArgument;DeadlineExceeded;NotFound;AlreadyExists;PermissionDenied;ResourceExhausted;FailedPrecondition;Aborted;OutOfRange;Unimplemented;Internal;Unavailable;DataLoss;Unauthenticated
+type GrpcCode string
+
+// RetryPolicy defines how failed attempts of a GrpcCall are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, including the first one.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=3
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+
+	// BackoffSeconds is the delay before the second attempt, doubled for
+	// every further attempt.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	// +optional
+	BackoffSeconds int32 `json:"backoffSeconds,omitempty"`
+
+	// MaxBackoffSeconds caps the delay between two attempts.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=300
+	// +optional
+	MaxBackoffSeconds int32 `json:"maxBackoffSeconds,omitempty"`
+
+	// RetryableCodes are the gRPC codes of the failures which are retried.
+	// Defaults to Unavailable, DeadlineExceeded, ResourceExhausted and Aborted.
+	// The unit-agent being unreachable counts as Unavailable.
+	// +optional
+	RetryableCodes []GrpcCode `json:"retryableCodes,omitempty"`
+}
+
 // Result defines the outcome status of a GrpcCall execution.
 // It represents the final state of the gRPC request sent to the unit-agent.
-// +kubebuilder:validation:Enum=Success;Failed
+// +kubebuilder:validation:Enum=Success;Failed;Cancelled;TimedOut
 type Result string
 
 const (
@@ -115,15 +282,49 @@ const (
 
 	// FailedResult indicates that the gRPC call failed due to an error during execution.
 	FailedResult Result = "Failed"
+
+	// CancelledResult indicates that the gRPC call was cancelled before it completed.
+	CancelledResult Result = "Cancelled"
+
+	// TimedOutResult indicates that the gRPC call did not complete within its timeout.
+	TimedOutResult Result = "TimedOut"
+)
+
+// GrpcCallPhase is the lifecycle phase of a GrpcCall.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Cancelled
+type GrpcCallPhase string
+
+const (
+	// GrpcCallPending means the GrpcCall is accepted but the gRPC call is not started yet.
+	GrpcCallPending GrpcCallPhase = "Pending"
+
+	// GrpcCallRunning means the gRPC call is started and not finished yet.
+	GrpcCallRunning GrpcCallPhase = "Running"
+
+	// GrpcCallSucceeded means the gRPC call completed successfully.
+	GrpcCallSucceeded GrpcCallPhase = "Succeeded"
+
+	// GrpcCallFailed means the gRPC call failed or timed out.
+	GrpcCallFailed GrpcCallPhase = "Failed"
+
+	// GrpcCallCancelled means the gRPC call was cancelled.
+	GrpcCallCancelled GrpcCallPhase = "Cancelled"
 )
 
 // GrpcCallStatus defines the observed state of a GrpcCall.
 // It records the execution result and related information returned
 // by the unit-agent after invoking the specified gRPC action.
 type GrpcCallStatus struct {
+	// Phase is the lifecycle phase of the GrpcCall.
+	// Valid values: "Pending", "Running", "Succeeded", "Failed", "Cancelled".
+	// +optional
+	Phase GrpcCallPhase `json:"phase,omitempty"`
+
 	// Result indicates the final outcome of the gRPC call.
-	// Valid values: "Success", "Failed".
-	Result Result `json:"result"`
+	// Valid values: "Success", "Failed", "Cancelled", "TimedOut".
+	// It is empty until the gRPC call finishes.
+	// +optional
+	Result Result `json:"result,omitempty"`
 
 	// Message contains additional context about the result,
 	// such as error details, logs, or debug output.
@@ -136,11 +337,155 @@ type GrpcCallStatus struct {
 	// StartTime is the timestamp when the controller started processing the gRPC call.
 	// +optional
 	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// OperationID is the id of the unit-agent operation running the gRPC call,
+	// which the controller polls until it finishes. It is empty for calls the
+	// unit-agent ran synchronously.
+	// +optional
+	OperationID string `json:"operationID,omitempty"`
+
+	// NextAttemptTime is when the next attempt starts, while a failed attempt is backing off.
+	// +optional
+	NextAttemptTime *metav1.Time `json:"nextAttemptTime,omitempty"`
+
+	// Attempts is the history of the attempts of the gRPC call, oldest first.
+	// +optional
+	Attempts []GrpcCallAttempt `json:"attempts,omitempty"`
+
+	// Unit is the unit the current attempt is sent to, chosen from TargetUnitSet.
+	// +optional
+	Unit string `json:"unit,omitempty"`
+
+	// Units is the status of the GrpcCalls targeting each unit, for a call
+	// fanned out to all the selected units of TargetUnitSet.
+	// +optional
+	Units []GrpcCallUnitStatus `json:"units,omitempty"`
+
+	// Outputs holds the fields of the response of a succeeded gRPC call, keyed
+	// by their proto names, e.g. {"object": "mysql-0/full.xb", "size_bytes": 1048576}.
+	// Integer fields are numbers, messages and lists are kept as JSON.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Outputs map[string]apiextensionsv1.JSON `json:"outputs,omitempty"`
+}
+
+// UnitRole is the replication role of a unit.
+// +kubebuilder:validation:Enum=primary;replica
+type UnitRole string
+
+const (
+	// PrimaryRole is the unit accepting writes, e.g. a MySQL source or a PostgreSQL primary.
+	PrimaryRole UnitRole = "primary"
+
+	// ReplicaRole is a read-only unit replicating from the primary.
+	ReplicaRole UnitRole = "replica"
+)
+
+// UnitSelectPolicy picks the units a GrpcCall is sent to among the selected ones.
+// +kubebuilder:validation:Enum=First;LeastLagged;All
+type UnitSelectPolicy string
+
+const (
+	// FirstPolicy sends the call to the first selected unit by name.
+	FirstPolicy UnitSelectPolicy = "First"
+
+	// LeastLaggedPolicy sends the call to the selected replica lagging least
+	// behind its replication source.
+	LeastLaggedPolicy UnitSelectPolicy = "LeastLagged"
+
+	// AllPolicy fans the call out to every selected unit.
+	AllPolicy UnitSelectPolicy = "All"
+)
+
+// UnitSetTarget selects the units of a UnitSet a GrpcCall is sent to. The
+// units are selected again for every attempt, so that a retried call follows
+// a failover. A selection matching no unit fails the attempt as Unavailable,
+// retried under the RetryPolicy of the call.
+type UnitSetTarget struct {
+	// Name is the name of the UnitSet, in the namespace of the GrpcCall.
+	Name string `json:"name"`
+
+	// Role only selects the units of this replication role, as labelled on
+	// their pods by compose-operator. Units of any role are selected when unset.
+	// +optional
+	Role UnitRole `json:"role,omitempty"`
+
+	// Ready only selects the units in the Ready phase.
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// Policy picks the units the call is sent to among the selected ones.
+	// First picks the first unit by name. LeastLagged picks the replica lagging
+	// least behind its source, according to the compose-operator replication
+	// topology of MySQL or PostgreSQL, units of unknown lag coming last. The
+	// primary is never picked by LeastLagged.
+	// All creates a GrpcCall targeting each selected unit and aggregates
+	// their results in status.units.
+	// +kubebuilder:default=First
+	// +optional
+	Policy UnitSelectPolicy `json:"policy,omitempty"`
+}
+
+// GrpcCallUnitStatus is the status of the GrpcCall sent to one unit by a
+// GrpcCall fanned out to all the selected units of a UnitSet.
+type GrpcCallUnitStatus struct {
+	// Unit is the name of the unit.
+	Unit string `json:"unit"`
+
+	// GrpcCall is the name of the GrpcCall targeting the unit.
+	GrpcCall string `json:"grpcCall"`
+
+	// Phase is the phase of the GrpcCall targeting the unit.
+	// +optional
+	Phase GrpcCallPhase `json:"phase,omitempty"`
+
+	// Result is the result of the GrpcCall targeting the unit.
+	// +optional
+	Result Result `json:"result,omitempty"`
+
+	// Message is the message of the GrpcCall targeting the unit.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Outputs are the outputs of the GrpcCall targeting the unit.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Outputs map[string]apiextensionsv1.JSON `json:"outputs,omitempty"`
+}
+
+// GrpcCallAttempt records one attempt of a gRPC call.
+type GrpcCallAttempt struct {
+	// Attempt is the number of the attempt, starting at 1.
+	Attempt int32 `json:"attempt"`
+
+	// OperationID is the id of the unit-agent operation of the attempt.
+	// +optional
+	OperationID string `json:"operationID,omitempty"`
+
+	// Unit is the unit the attempt was sent to, when chosen from TargetUnitSet.
+	// +optional
+	Unit string `json:"unit,omitempty"`
+
+	// StartTime is when the attempt started.
+	StartTime metav1.Time `json:"startTime"`
+
+	// CompletionTime is when the attempt finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Code is the gRPC code the attempt failed with.
+	// +optional
+	Code GrpcCode `json:"code,omitempty"`
+
+	// Message describes the failure of the attempt.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=gc
+// +kubebuilder:printcolumn:name="PHASE",type=string,JSONPath=`.status.phase`
 // +kubebuilder:printcolumn:name="RESULT",type=string,JSONPath=`.status.result`
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 
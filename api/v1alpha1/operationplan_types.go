@@ -0,0 +1,333 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperationPlanSpec defines the desired state of an OperationPlan.
+// The steps of an OperationPlan run in order, a step marked Parallel runs
+// together with the steps before it, up to the first step not marked Parallel.
+// A step which fails aborts the plan, continues it or rolls the plan back
+// depending on its OnFailure policy. An OperationPlan runs once. Its name is
+// at most 63 characters, as its GrpcCalls are labelled with it.
+type OperationPlanSpec struct {
+	// Steps are the steps of the plan, in order.
+	// +kubebuilder:validation:MinItems=1
+	Steps []OperationStep `json:"steps"`
+
+	// Suspend cancels a plan which is not finished yet, together with its
+	// running steps. The steps left are skipped, rollbacks are not run.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// TTLSecondsAfterFinished limits the lifetime of a finished OperationPlan. It is
+	// deleted, together with the GrpcCalls of its steps, TTLSecondsAfterFinished
+	// after it finished.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// StepCondition decides whether a step runs.
+// +kubebuilder:validation:Enum=Succeeded;Failed;Always
+type StepCondition string
+
+const (
+	// SucceededCondition runs the step when no step before it aborted the plan.
+	SucceededCondition StepCondition = "Succeeded"
+
+	// FailedCondition runs the step only once a step before it aborted the plan,
+	// e.g. to collect diagnostics.
+	FailedCondition StepCondition = "Failed"
+
+	// AlwaysCondition runs the step whether a step before it aborted the plan
+	// or not, e.g. to start a stopped process again.
+	AlwaysCondition StepCondition = "Always"
+)
+
+// FailurePolicy decides what a failed step does to the plan.
+// +kubebuilder:validation:Enum=Abort;Continue;Rollback
+type FailurePolicy string
+
+const (
+	// AbortPolicy skips the steps left, except those running on failure, and fails the plan.
+	AbortPolicy FailurePolicy = "Abort"
+
+	// ContinuePolicy records the failure and runs the steps left as if the step succeeded.
+	ContinuePolicy FailurePolicy = "Continue"
+
+	// RollbackPolicy aborts the plan, then runs the rollback of every step
+	// which succeeded, in the reverse order of the steps.
+	RollbackPolicy FailurePolicy = "Rollback"
+)
+
+// LifecycleActionType is the action applied to the process of a unit.
+// +kubebuilder:validation:Enum=start;stop
+type LifecycleActionType string
+
+const (
+	// StartLifecycleAction starts the process of the unit.
+	StartLifecycleAction LifecycleActionType = "start"
+
+	// StopLifecycleAction stops the process of the unit.
+	StopLifecycleAction LifecycleActionType = "stop"
+)
+
+// LifecycleAction starts or stops the process of a unit through a GrpcCall
+// with the start or stop action, and waits for the unit to report the process
+// running or stopped. A stopped process is not started again by the unit
+// controller until a start, and a unit whose spec.startup is false cannot be
+// started.
+type LifecycleAction struct {
+	// Unit is the name of the unit, in the same namespace.
+	Unit string `json:"unit"`
+
+	// Action is start or stop.
+	Action LifecycleActionType `json:"action"`
+
+	// TimeoutSeconds bounds the GrpcCall and the wait for the process to be
+	// running or stopped.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=300
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// GrpcCallTemplate describes the GrpcCall created for a step.
+type GrpcCallTemplate struct {
+	// TargetUnit is the name of the unit the call is sent to.
+	// Exactly one of TargetUnit and TargetUnitSet is set.
+	// +optional
+	TargetUnit string `json:"targetUnit,omitempty"`
+
+	// TargetUnitSet sends the call to units of a UnitSet chosen by a selector.
+	// +optional
+	TargetUnitSet *UnitSetTarget `json:"targetUnitSet,omitempty"`
+
+	// Type is the type of the target unit.
+	Type UnitType `json:"type"`
+
+	// Action is the gRPC method called on the unit-agent, as an alias of a
+	// method of the unit type. Exactly one of Action and Method is set.
+	// +optional
+	Action Action `json:"action,omitempty"`
+
+	// Method is the full name of the unit-agent gRPC method to call, as
+	// "<package>.<Service>/<Method>".
+	// +kubebuilder:validation:Pattern=`^/?[A-Za-z_][A-Za-z0-9_.]*/[A-Za-z_][A-Za-z0-9_]*$`
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// TimeoutSeconds limits how long each attempt of the call may run.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// RetryPolicy retries the call when an attempt fails with a retryable code.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// Parameters are the arguments of the call.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Parameters map[string]apiextensionsv1.JSON `json:"parameters,omitempty"`
+}
+
+// OperationAction is the action of a step or of its rollback.
+// Exactly one of GrpcCall and Lifecycle is set.
+type OperationAction struct {
+	// GrpcCall sends a gRPC call to the unit-agent through a GrpcCall.
+	// +optional
+	GrpcCall *GrpcCallTemplate `json:"grpcCall,omitempty"`
+
+	// Lifecycle starts or stops the process of a unit.
+	// +optional
+	Lifecycle *LifecycleAction `json:"lifecycle,omitempty"`
+}
+
+// OperationStep is a step of an OperationPlan.
+type OperationStep struct {
+	// Name identifies the step within the plan. It must not end with
+	// "-rollback", which names the GrpcCall of the rollback of the step.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	OperationAction `json:",inline"`
+
+	// Parallel runs the step together with the step before it instead of after it.
+	// +optional
+	Parallel bool `json:"parallel,omitempty"`
+
+	// When decides whether the step runs, depending on whether a step before
+	// it aborted the plan. A step which does not run is Skipped.
+	// +kubebuilder:default=Succeeded
+	// +optional
+	When StepCondition `json:"when,omitempty"`
+
+	// OnFailure decides what the failure of the step does to the plan. A step
+	// cancelled outside the plan, e.g. by suspending its GrpcCall, fails.
+	// +kubebuilder:default=Abort
+	// +optional
+	OnFailure FailurePolicy `json:"onFailure,omitempty"`
+
+	// Rollback undoes the step once it succeeded, when a step fails with the
+	// Rollback policy afterwards.
+	// +optional
+	Rollback *OperationAction `json:"rollback,omitempty"`
+}
+
+// OperationPlanPhase is the lifecycle phase of an OperationPlan.
+// +kubebuilder:validation:Enum=Pending;Running;RollingBack;Succeeded;Failed;Cancelled
+type OperationPlanPhase string
+
+const (
+	// OperationPlanPending means the plan is accepted but no step is started yet.
+	OperationPlanPending OperationPlanPhase = "Pending"
+
+	// OperationPlanRunning means steps of the plan are running.
+	OperationPlanRunning OperationPlanPhase = "Running"
+
+	// OperationPlanRollingBack means a step failed and the succeeded steps are rolled back.
+	OperationPlanRollingBack OperationPlanPhase = "RollingBack"
+
+	// OperationPlanSucceeded means every step ran, or failed with the Continue policy.
+	OperationPlanSucceeded OperationPlanPhase = "Succeeded"
+
+	// OperationPlanFailed means a step aborted the plan.
+	OperationPlanFailed OperationPlanPhase = "Failed"
+
+	// OperationPlanCancelled means the plan was suspended before it finished.
+	OperationPlanCancelled OperationPlanPhase = "Cancelled"
+)
+
+// StepPhase is the lifecycle phase of a step, or of its rollback.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Skipped;Cancelled
+type StepPhase string
+
+const (
+	// StepPending means the step is not started yet.
+	StepPending StepPhase = "Pending"
+
+	// StepRunning means the step is started and not finished yet.
+	StepRunning StepPhase = "Running"
+
+	// StepSucceeded means the step succeeded.
+	StepSucceeded StepPhase = "Succeeded"
+
+	// StepFailed means the step failed or timed out.
+	StepFailed StepPhase = "Failed"
+
+	// StepSkipped means the step did not run, as its condition did not hold.
+	StepSkipped StepPhase = "Skipped"
+
+	// StepCancelled means the step was cancelled by suspending the plan, or
+	// outside the plan, which counts as a failure of the step.
+	StepCancelled StepPhase = "Cancelled"
+)
+
+// OperationActionStatus is the observed state of the action of a step, or of its rollback.
+type OperationActionStatus struct {
+	// Phase is the lifecycle phase of the action.
+	// +optional
+	Phase StepPhase `json:"phase,omitempty"`
+
+	// Message contains details about the action, such as error details.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// GrpcCall is the name of the GrpcCall of the action.
+	// +optional
+	GrpcCall string `json:"grpcCall,omitempty"`
+
+	// Outputs are the outputs of the GrpcCall of the action.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Outputs map[string]apiextensionsv1.JSON `json:"outputs,omitempty"`
+
+	// StartTime is when the action started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the action finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// OperationStepStatus is the observed state of a step.
+type OperationStepStatus struct {
+	// Name is the name of the step.
+	Name string `json:"name"`
+
+	OperationActionStatus `json:",inline"`
+
+	// Rollback is the state of the rollback of the step, once it started.
+	// +optional
+	Rollback *OperationActionStatus `json:"rollback,omitempty"`
+}
+
+// OperationPlanStatus defines the observed state of an OperationPlan.
+type OperationPlanStatus struct {
+	// Phase is the lifecycle phase of the plan.
+	// +optional
+	Phase OperationPlanPhase `json:"phase,omitempty"`
+
+	// Message summarizes the progress or the outcome of the plan.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Steps is the state of every step, in the order of spec.steps.
+	// +optional
+	Steps []OperationStepStatus `json:"steps,omitempty"`
+
+	// StartTime is when the first step started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the plan finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=opp
+// +kubebuilder:printcolumn:name="PHASE",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="MESSAGE",type=string,JSONPath=`.status.message`,priority=1
+// +kubebuilder:printcolumn:name="COMPLETED",type="date",JSONPath=".status.completionTime"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OperationPlan is the Schema for the operationplans API
+type OperationPlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperationPlanSpec   `json:"spec,omitempty"`
+	Status OperationPlanStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperationPlanList contains a list of OperationPlan
+type OperationPlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperationPlan `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperationPlan{}, &OperationPlanList{})
+}
@@ -0,0 +1,171 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProxysqlBackendSpec defines the desired state of a ProxysqlBackend.
+// The operator keeps the mysql_servers of every unit of a ProxySQL UnitSet in sync with the
+// units of a MySQL UnitSet. All backends are registered in the reader hostgroup and a
+// mysql_replication_hostgroups entry lets the ProxySQL monitor move the writable source into
+// the writer hostgroup, so that failovers are followed without another synchronization.
+type ProxysqlBackendSpec struct {
+	// UnitSet is the name of the ProxySQL UnitSet, in the same namespace, whose units are configured.
+	UnitSet string `json:"unitSet"`
+
+	// AdminUsername is the ProxySQL admin account the unit-agent uses.
+	// Its password is read from the secret mounted into the unit.
+	AdminUsername string `json:"adminUsername"`
+
+	// MysqlUnitSet is the name of the MySQL UnitSet, in the same namespace, whose units are the backends.
+	MysqlUnitSet string `json:"mysqlUnitSet"`
+
+	// MysqlPort is the port of the MySQL units.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=3306
+	// +optional
+	MysqlPort int64 `json:"mysqlPort,omitempty"`
+
+	// WriterHostgroup is the hostgroup of the writable source.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	// +optional
+	WriterHostgroup int64 `json:"writerHostgroup,omitempty"`
+
+	// ReaderHostgroup is the hostgroup of the read only replicas.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=20
+	// +optional
+	ReaderHostgroup int64 `json:"readerHostgroup,omitempty"`
+
+	// CheckType is the variable the ProxySQL monitor checks to tell the source from the replicas.
+	// +kubebuilder:validation:Enum=read_only;innodb_read_only;super_read_only
+	// +kubebuilder:default=read_only
+	// +optional
+	CheckType string `json:"checkType,omitempty"`
+
+	// MaxConnections is the maximum number of connections ProxySQL opens to each backend.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConnections int64 `json:"maxConnections,omitempty"`
+
+	// MaxReplicationLag shuns a replica whose lag exceeds the given seconds, 0 disables the check.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxReplicationLag int64 `json:"maxReplicationLag,omitempty"`
+
+	// QueryRules replaces the mysql_query_rules of the ProxySQL units when set.
+	// +optional
+	QueryRules []ProxysqlQueryRule `json:"queryRules,omitempty"`
+}
+
+// ProxysqlQueryRule describes a row of mysql_query_rules.
+type ProxysqlQueryRule struct {
+	// RuleID is the unique id of the rule, rules are evaluated in ascending order.
+	// +kubebuilder:validation:Minimum=1
+	RuleID int64 `json:"ruleId"`
+
+	// Active enables the rule.
+	// +kubebuilder:default=true
+	// +optional
+	Active *bool `json:"active,omitempty"`
+
+	// User matches the user of the client connection.
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// Schemaname matches the default schema of the client connection.
+	// +optional
+	Schemaname string `json:"schemaname,omitempty"`
+
+	// MatchDigest is a regular expression matched against the digest of the query.
+	// +optional
+	MatchDigest string `json:"matchDigest,omitempty"`
+
+	// MatchPattern is a regular expression matched against the text of the query.
+	// +optional
+	MatchPattern string `json:"matchPattern,omitempty"`
+
+	// DestinationHostgroup is the hostgroup the matching queries are routed to.
+	// +kubebuilder:validation:Minimum=0
+	DestinationHostgroup int64 `json:"destinationHostgroup"`
+
+	// Apply stops the evaluation of the following rules when the rule matches.
+	// +optional
+	Apply bool `json:"apply,omitempty"`
+
+	// Comment describes the rule.
+	// +optional
+	Comment string `json:"comment,omitempty"`
+}
+
+// ProxysqlBackendStatus defines the observed state of a ProxysqlBackend.
+type ProxysqlBackendStatus struct {
+	// Result indicates the outcome of the last synchronization.
+	// +optional
+	Result Result `json:"result,omitempty"`
+
+	// Message contains details about the last synchronization, such as error details.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation applied by the last successful synchronization.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Units lists the ProxySQL units synchronized by the last successful synchronization.
+	// +optional
+	Units []string `json:"units,omitempty"`
+
+	// Backends lists the host:port of the backends registered by the last successful synchronization.
+	// +optional
+	Backends []string `json:"backends,omitempty"`
+
+	// LastSyncTime is the timestamp of the last synchronization.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=psb
+// +kubebuilder:printcolumn:name="UNITSET",type=string,JSONPath=`.spec.unitSet`
+// +kubebuilder:printcolumn:name="MYSQL",type=string,JSONPath=`.spec.mysqlUnitSet`
+// +kubebuilder:printcolumn:name="RESULT",type=string,JSONPath=`.status.result`
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ProxysqlBackend is the Schema for the proxysqlbackends API
+type ProxysqlBackend struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProxysqlBackendSpec   `json:"spec,omitempty"`
+	Status ProxysqlBackendStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProxysqlBackendList contains a list of ProxysqlBackend
+type ProxysqlBackendList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProxysqlBackend `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProxysqlBackend{}, &ProxysqlBackendList{})
+}
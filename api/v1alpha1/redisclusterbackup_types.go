@@ -0,0 +1,208 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RedisClusterBackupSpec defines the desired state of a RedisClusterBackup.
+// The operator pauses the writes of every master of the Redis Cluster, forks a snapshot of each
+// shard within that window and resumes the writes, then uploads the snapshots together with a
+// cluster.json manifest holding the slot map, so that the backup is one point in time of the
+// whole cluster. A RedisClusterBackup runs once, a failed attempt is retried until BackoffLimit
+// attempts failed. Once the shards were snapshotted a retry only uploads the shards whose upload
+// did not succeed yet, the writes are never paused again.
+type RedisClusterBackupSpec struct {
+	// UnitSet is the name of the Redis Cluster UnitSet, in the same namespace, to back up.
+	UnitSet string `json:"unitSet"`
+
+	// Username is the Redis account the unit-agent uses.
+	// Its password is read from the secret mounted into the unit.
+	Username string `json:"username"`
+
+	// ObjectStorage is where the snapshots and the manifest are uploaded to.
+	ObjectStorage ObjectStorageSpec `json:"objectStorage"`
+
+	// Prefix is the object name prefix of the backup, defaults to <namespace>/<name>.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// PauseTimeout bounds the write pause of the masters. The backup fails when not every
+	// shard was snapshotted before the pause expired.
+	// +kubebuilder:default="10s"
+	// +optional
+	PauseTimeout metav1.Duration `json:"pauseTimeout,omitempty"`
+
+	// BackoffLimit is the number of failed attempts after which the backup is marked Failed.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// +optional
+	BackoffLimit int32 `json:"backoffLimit,omitempty"`
+}
+
+// RedisClusterBackupPhase is the step a RedisClusterBackup is at.
+// +kubebuilder:validation:Enum=Snapshotting;Uploading;Succeeded;Failed
+type RedisClusterBackupPhase string
+
+const (
+	// RedisClusterBackupSnapshotting pauses the writes of the masters and snapshots every shard.
+	RedisClusterBackupSnapshotting RedisClusterBackupPhase = "Snapshotting"
+
+	// RedisClusterBackupUploading uploads the shard snapshots and the manifest.
+	RedisClusterBackupUploading RedisClusterBackupPhase = "Uploading"
+
+	// RedisClusterBackupSucceeded means the snapshots and the manifest were uploaded.
+	RedisClusterBackupSucceeded RedisClusterBackupPhase = "Succeeded"
+
+	// RedisClusterBackupFailed means BackoffLimit attempts failed, the backup is not retried.
+	RedisClusterBackupFailed RedisClusterBackupPhase = "Failed"
+)
+
+// ObjectStorageSpec describes an S3 compatible bucket.
+type ObjectStorageSpec struct {
+	// Type is the type of the object storage.
+	// +kubebuilder:validation:Enum=minio
+	// +kubebuilder:default=minio
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Endpoint is the host:port of the object storage.
+	Endpoint string `json:"endpoint"`
+
+	// Bucket is the name of the bucket.
+	Bucket string `json:"bucket"`
+
+	// SSL enables TLS to the endpoint.
+	// +optional
+	SSL bool `json:"ssl,omitempty"`
+
+	// CredentialsSecret is the name of a Secret, in the same namespace, with the
+	// accessKey and secretKey keys.
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// RedisClusterShard describes a master shard of a Redis Cluster backup.
+type RedisClusterShard struct {
+	// NodeID is the cluster node id of the master the shard was taken from.
+	NodeID string `json:"nodeId"`
+
+	// Unit is the name of the unit holding the shard.
+	// +optional
+	Unit string `json:"unit,omitempty"`
+
+	// Slots lists the hash slot ranges of the shard, such as "0-5460".
+	Slots []string `json:"slots"`
+
+	// Keys is the number of keys of the shard when it was snapshotted.
+	// +optional
+	Keys int64 `json:"keys,omitempty"`
+
+	// Object is the object name of the shard snapshot.
+	Object string `json:"object"`
+}
+
+// RedisClusterTransfer tracks the unit-agent operation uploading the shard of a unit, or loading
+// the shard into a unit.
+type RedisClusterTransfer struct {
+	// Unit is the name of the unit.
+	Unit string `json:"unit"`
+
+	// LastSave is the LASTSAVE of the unit before its snapshot was forked, the upload waits
+	// for the save which followed it.
+	// +optional
+	LastSave int64 `json:"lastSave,omitempty"`
+
+	// OperationID is the unit-agent operation running the transfer.
+	// +optional
+	OperationID string `json:"operationId,omitempty"`
+
+	// Failures is the number of unit-agent operations of the transfer which failed.
+	// +optional
+	Failures int32 `json:"failures,omitempty"`
+
+	// Completed reports whether the transfer succeeded.
+	// +optional
+	Completed bool `json:"completed,omitempty"`
+}
+
+// RedisClusterBackupStatus defines the observed state of a RedisClusterBackup.
+type RedisClusterBackupStatus struct {
+	// Phase is the step the backup is at.
+	// +optional
+	Phase RedisClusterBackupPhase `json:"phase,omitempty"`
+
+	// Attempts is the number of failed attempts.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// Result indicates the outcome of the last attempt.
+	// +optional
+	Result Result `json:"result,omitempty"`
+
+	// Message contains details about the last attempt, such as error details.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Manifest is the object name of the manifest of a completed backup.
+	// +optional
+	Manifest string `json:"manifest,omitempty"`
+
+	// Shards lists the master shards of the backup once they were snapshotted.
+	// +optional
+	Shards []RedisClusterShard `json:"shards,omitempty"`
+
+	// Transfers tracks the upload of every shard, in the order of Shards.
+	// +optional
+	Transfers []RedisClusterTransfer `json:"transfers,omitempty"`
+
+	// StartTime is the timestamp when the controller started the backup.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the timestamp when the backup completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rcb
+// +kubebuilder:printcolumn:name="UNITSET",type=string,JSONPath=`.spec.unitSet`
+// +kubebuilder:printcolumn:name="PHASE",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="RESULT",type=string,JSONPath=`.status.result`
+// +kubebuilder:printcolumn:name="COMPLETED",type="date",JSONPath=".status.completionTime"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// RedisClusterBackup is the Schema for the redisclusterbackups API
+type RedisClusterBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisClusterBackupSpec   `json:"spec,omitempty"`
+	Status RedisClusterBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RedisClusterBackupList contains a list of RedisClusterBackup
+type RedisClusterBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisClusterBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RedisClusterBackup{}, &RedisClusterBackupList{})
+}
@@ -0,0 +1,133 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RedisClusterRestorePhase is the step a RedisClusterRestore is at.
+// +kubebuilder:validation:Enum=Stopping;Loading;Starting;Joining;Succeeded
+type RedisClusterRestorePhase string
+
+const (
+	// RedisClusterRestoreStopping stops the units of the UnitSet and waits until their processes stopped.
+	RedisClusterRestoreStopping RedisClusterRestorePhase = "Stopping"
+
+	// RedisClusterRestoreLoading resets the units and loads the shard snapshots, all units in parallel.
+	RedisClusterRestoreLoading RedisClusterRestorePhase = "Loading"
+
+	// RedisClusterRestoreStarting starts the units of the UnitSet.
+	RedisClusterRestoreStarting RedisClusterRestorePhase = "Starting"
+
+	// RedisClusterRestoreJoining joins the units into a cluster, assigns the slots and the replicas.
+	RedisClusterRestoreJoining RedisClusterRestorePhase = "Joining"
+
+	// RedisClusterRestoreSucceeded means the cluster serves every slot again.
+	RedisClusterRestoreSucceeded RedisClusterRestorePhase = "Succeeded"
+)
+
+// RedisClusterRestoreSpec defines the desired state of a RedisClusterRestore.
+// The operator stops the units of the UnitSet, loads every shard of a RedisClusterBackup into
+// its own unit, in unit name order, and starts them with a new cluster identity. The shard units
+// are joined into a cluster and assigned the slots of their shard, the remaining units are
+// assigned as replicas round robin. The UnitSet needs at least as many units as the backup has
+// shards, it may have more units than the backed up cluster.
+type RedisClusterRestoreSpec struct {
+	// UnitSet is the name of the Redis Cluster UnitSet, in the same namespace, to restore into.
+	UnitSet string `json:"unitSet"`
+
+	// Username is the Redis account the unit-agent uses.
+	// Its password is read from the secret mounted into the unit.
+	Username string `json:"username"`
+
+	// ObjectStorage is where the backup is stored.
+	ObjectStorage ObjectStorageSpec `json:"objectStorage"`
+
+	// Prefix is the object name prefix of the backup, the status.manifest of a
+	// RedisClusterBackup without the cluster.json suffix.
+	Prefix string `json:"prefix"`
+
+	// Port is the port of the Redis units.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=6379
+	// +optional
+	Port int64 `json:"port,omitempty"`
+}
+
+// RedisClusterRestoreStatus defines the observed state of a RedisClusterRestore.
+type RedisClusterRestoreStatus struct {
+	// Phase is the step the restore is at.
+	// +optional
+	Phase RedisClusterRestorePhase `json:"phase,omitempty"`
+
+	// Result indicates the outcome of the last reconciliation.
+	// +optional
+	Result Result `json:"result,omitempty"`
+
+	// Message contains details about the last reconciliation, such as error details.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Shards lists the shards of the backup together with the unit each is loaded into.
+	// +optional
+	Shards []RedisClusterShard `json:"shards,omitempty"`
+
+	// Replicas lists the units joining the cluster as replicas.
+	// +optional
+	Replicas []string `json:"replicas,omitempty"`
+
+	// Transfers tracks the loading of every unit, in unit name order.
+	// +optional
+	Transfers []RedisClusterTransfer `json:"transfers,omitempty"`
+
+	// StartTime is the timestamp when the controller started the restore.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the timestamp when the restore completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rcr
+// +kubebuilder:printcolumn:name="UNITSET",type=string,JSONPath=`.spec.unitSet`
+// +kubebuilder:printcolumn:name="PHASE",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="RESULT",type=string,JSONPath=`.status.result`
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// RedisClusterRestore is the Schema for the redisclusterrestores API
+type RedisClusterRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisClusterRestoreSpec   `json:"spec,omitempty"`
+	Status RedisClusterRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RedisClusterRestoreList contains a list of RedisClusterRestore
+type RedisClusterRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisClusterRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RedisClusterRestore{}, &RedisClusterRestoreList{})
+}
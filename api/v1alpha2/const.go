@@ -11,6 +11,13 @@ const (
 	AnnotationMainContainerName    = "kubectl.kubernetes.io/default-container"
 	AnnotationMainContainerVersion = "kubectl.kubernetes.io/default-container-version"
 	AnnotationForceDelete          = "unit-operator/force-delete"
+	// AnnotationProcessStoppedBy names the GrpcCall which stopped the process of the unit,
+	// the unit controller does not start the process while it is set, whatever spec.startup.
+	// It is removed by a GrpcCall starting or restarting the process.
+	AnnotationProcessStoppedBy = "unit-operator/process.stopped-by"
+	// AnnotationBootstrapPhase tracks a unit of a unitset bootstrapped from a backup,
+	// one of BootstrapRestoring, BootstrapRestored and BootstrapCompleted
+	AnnotationBootstrapPhase = "unit-operator/bootstrap.phase"
 	// AnnotationUnitsetNodeNameMap stores a JSON object mapping unit name -> node name (or "noneSet")
 	// Example: {"mysql-cluster-0":"node-a","mysql-cluster-1":"noneSet"}
 	AnnotationUnitsetNodeNameMap = "unit-operator/unit.node-name.map"
@@ -39,6 +46,12 @@ const (
 
 	AnnotationAesSecretKey = "unit-operator/secret.aes-secret-key"
 
+	// AnnotationRedisClusterReplicas is the number of replicas every master of a Redis Cluster UnitSet
+	// should have, the units of a scale out become replicas of the masters short of it before the
+	// remaining ones take over slots as masters.
+	// Example: "1"
+	AnnotationRedisClusterReplicas = "unit-operator/redis-cluster.replicas"
+
 	LabelProjectOwner = "unit-operator/owner"
 	LabelNamespace    = "unit-operator/namespace"
 	LabelUnitsCount   = "unit-operator/unitset.units.count"
@@ -83,3 +96,13 @@ const (
 	// Deprecated: It isn't being set since 2015 (74da3b14b0c0f658b3bb8d2def5094686d0e9095)
 	UnitUnknown UnitPhase = "Unknown"
 )
+
+// The phases of a unit bootstrapped from a backup, see AnnotationBootstrapPhase.
+const (
+	// BootstrapRestoring means the unit is stopped until the backup is restored into it
+	BootstrapRestoring = "Restoring"
+	// BootstrapRestored means the backup is restored and the unit is starting for the post-restore steps
+	BootstrapRestored = "Restored"
+	// BootstrapCompleted means the post-restore steps ran, the unit is initialized
+	BootstrapCompleted = "Completed"
+)
@@ -95,6 +95,59 @@ type UnitSetSpec struct {
 	// PodMonitor defines the configuration for pod monitor
 	// +optional
 	PodMonitor PodMonitorInfo `json:"podMonitor,omitempty"`
+
+	// Bootstrap defines how the units of a new unitset are initialized before their first start
+	// +optional
+	Bootstrap *BootstrapSpec `json:"bootstrap,omitempty"`
+}
+
+// BootstrapSpec defines how the units of a new unitset are initialized.
+type BootstrapSpec struct {
+
+	// FromBackup restores every unit from a backup before its first start,
+	// supported by the mysql, postgresql and redis unitset types
+	// +optional
+	FromBackup *BootstrapFromBackup `json:"fromBackup,omitempty"`
+}
+
+// BootstrapFromBackup defines the backup the units of a new unitset are restored from.
+type BootstrapFromBackup struct {
+
+	// BackupFile is the object name of the backup, for mysql the xtrabackup backup name
+	// +kubebuilder:validation:MinLength=1
+	BackupFile string `json:"backupFile"`
+
+	// ObjectStorage is where the backup is stored
+	ObjectStorage BackupObjectStorageSpec `json:"objectStorage"`
+
+	// Username runs the post-restore steps, e.g. GtidPurge for mysql.
+	// Its password is read from the secret mounted into the unit, defaults to ADM_USER
+	// +optional
+	Username string `json:"username,omitempty"`
+}
+
+// BackupObjectStorageSpec defines the S3 compatible object storage of a backup.
+type BackupObjectStorageSpec struct {
+
+	// Type is the type of the object storage
+	// +kubebuilder:validation:Enum=minio
+	// +kubebuilder:default=minio
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Endpoint is the host:port of the object storage
+	Endpoint string `json:"endpoint"`
+
+	// Bucket is the name of the bucket
+	Bucket string `json:"bucket"`
+
+	// SSL enables TLS to the endpoint
+	// +optional
+	SSL bool `json:"ssl,omitempty"`
+
+	// CredentialsSecret is the name of a Secret, in the same namespace, with the
+	// accessKey and secretKey keys
+	CredentialsSecret string `json:"credentialsSecret"`
 }
 
 type ExtraVolumeInfo struct {
@@ -334,6 +387,20 @@ type UnitSetStatus struct {
 	// UnitService the information of unit service
 	// +optional
 	UnitService UnitServiceStatus `json:"unitService,omitempty"`
+
+	// Operations the unit-agent operations the UnitSet waits for, the key names what the operation does
+	// +optional
+	Operations map[string]UnitSetOperation `json:"operations,omitempty"`
+}
+
+// UnitSetOperation is an operation running on the unit-agent of a unit
+type UnitSetOperation struct {
+
+	// Unit the name of the unit whose unit-agent runs the operation
+	Unit string `json:"unit"`
+
+	// ID the id of the operation on the unit-agent
+	ID string `json:"id"`
 }
 
 type ExternalServiceStatus struct {
@@ -23,6 +23,57 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupObjectStorageSpec) DeepCopyInto(out *BackupObjectStorageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupObjectStorageSpec.
+func (in *BackupObjectStorageSpec) DeepCopy() *BackupObjectStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupObjectStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapFromBackup) DeepCopyInto(out *BootstrapFromBackup) {
+	*out = *in
+	out.ObjectStorage = in.ObjectStorage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapFromBackup.
+func (in *BootstrapFromBackup) DeepCopy() *BootstrapFromBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapFromBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapSpec) DeepCopyInto(out *BootstrapSpec) {
+	*out = *in
+	if in.FromBackup != nil {
+		in, out := &in.FromBackup, &out.FromBackup
+		*out = new(BootstrapFromBackup)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapSpec.
+func (in *BootstrapSpec) DeepCopy() *BootstrapSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CAInfo) DeepCopyInto(out *CAInfo) {
 	*out = *in
@@ -663,6 +714,21 @@ func (in *UnitSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnitSetOperation) DeepCopyInto(out *UnitSetOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnitSetOperation.
+func (in *UnitSetOperation) DeepCopy() *UnitSetOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(UnitSetOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UnitSetSpec) DeepCopyInto(out *UnitSetSpec) {
 	*out = *in
@@ -708,6 +774,11 @@ func (in *UnitSetSpec) DeepCopyInto(out *UnitSetSpec) {
 	}
 	in.CertificateProfile.DeepCopyInto(&out.CertificateProfile)
 	in.PodMonitor.DeepCopyInto(&out.PodMonitor)
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(BootstrapSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnitSetSpec.
@@ -735,6 +806,13 @@ func (in *UnitSetStatus) DeepCopyInto(out *UnitSetStatus) {
 	in.ResourceSyncStatus.DeepCopyInto(&out.ResourceSyncStatus)
 	out.ExternalService = in.ExternalService
 	in.UnitService.DeepCopyInto(&out.UnitService)
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make(map[string]UnitSetOperation, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnitSetStatus.
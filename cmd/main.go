@@ -36,6 +36,7 @@ import (
 
 	certmanagerV1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	serviceMonitorv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	composev1alpha1 "github.com/upmio/compose-operator/api/v1alpha1"
 	apiextensionsV1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
@@ -65,6 +66,8 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(upmv1alpha2.AddToScheme(scheme))
 	utilruntime.Must(upmv1alpha1.AddToScheme(scheme))
+	// compose-operator replications tell the role and lag of the units GrpcCalls are sent to
+	utilruntime.Must(composev1alpha1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":20154",
@@ -0,0 +1,245 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+	"go.uber.org/zap"
+)
+
+// backupIDRE matches the ids of system.backups, generated UUIDs or the id setting
+var backupIDRE = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func (s *service) BackupStatus(ctx context.Context, req *BackupStatusRequest) (*BackupOperation, error) {
+	util.LogRequestSafely(s.logger, "clickhouse backup status", map[string]interface{}{
+		"username": req.GetUsername(),
+		"id":       req.GetId(),
+	})
+
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if err := validateIdentifier(req.GetUsername()); err != nil {
+		s.logger.Errorw("invalid clickhouse username", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	query, err := buildBackupStatusSQL(req.GetId())
+	if err != nil {
+		s.logger.Errorw("failed to build backup status query", zap.Error(err))
+		return nil, err
+	}
+
+	password, err := util.DecryptPlainTextPassword(req.GetUsername())
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	output, err := queryClickHouse(ctx, s.runner, readClickHouseConnection(), req.GetUsername(), password, query)
+	if err != nil {
+		s.logger.Errorw("failed to get backup status", zap.Error(err), zap.String("id", req.GetId()))
+		return nil, err
+	}
+
+	op, err := parseBackupStatusOutput(output)
+	if err != nil {
+		s.logger.Errorw("failed to parse backup status", zap.Error(err), zap.String("id", req.GetId()))
+		return nil, err
+	}
+
+	s.logger.Infow("get backup status clickhouse successfully", "id", op.GetId(), "status", op.GetStatus())
+	return op, nil
+}
+
+// runBackupOperation runs a BACKUP or RESTORE query, which prints the id and
+// status of the operation. A finished operation is completed with the sizes
+// recorded in system.backups, an ASYNC one only started.
+func (s *service) runBackupOperation(ctx context.Context, conn clickHouseConnection, username, password, query string) (*BackupOperation, error) {
+	output, err := queryClickHouse(ctx, s.runner, conn, username, password, query)
+	if err != nil {
+		return nil, err
+	}
+
+	op := parseBackupOperationOutput(output)
+	if op.GetId() == "" || !isBackupOperationFinished(op.GetStatus()) {
+		return op, nil
+	}
+
+	statusQuery, err := buildBackupStatusSQL(op.GetId())
+	if err != nil {
+		return op, nil
+	}
+
+	output, err = queryClickHouse(ctx, s.runner, conn, username, password, statusQuery)
+	if err == nil {
+		var status *BackupOperation
+		if status, err = parseBackupStatusOutput(output); err == nil {
+			return status, nil
+		}
+	}
+
+	s.logger.Warnw("failed to get backup sizes", zap.Error(err), zap.String("id", op.GetId()))
+	return op, nil
+}
+
+// buildBackupTargets returns the databases and tables of a BACKUP or RESTORE
+// query, ALL when none is given.
+func buildBackupTargets(targets []*BackupTarget) (string, error) {
+	if len(targets) == 0 {
+		return "ALL", nil
+	}
+
+	items := make([]string, 0, len(targets))
+	for _, target := range targets {
+		if err := validateBackupName(target.GetDatabase()); err != nil {
+			return "", err
+		}
+
+		renameDatabase := target.GetRenameDatabase()
+		if renameDatabase != "" {
+			if err := validateBackupName(renameDatabase); err != nil {
+				return "", err
+			}
+		}
+
+		if target.GetTable() == "" {
+			if target.GetRenameTable() != "" {
+				return "", fmt.Errorf("rename_table of database %s requires table", target.GetDatabase())
+			}
+
+			item := "DATABASE " + target.GetDatabase()
+			if renameDatabase != "" {
+				item += " AS " + renameDatabase
+			}
+			items = append(items, item)
+			continue
+		}
+
+		if err := validateBackupName(target.GetTable()); err != nil {
+			return "", err
+		}
+
+		item := fmt.Sprintf("TABLE %s.%s", target.GetDatabase(), target.GetTable())
+		if renameDatabase != "" || target.GetRenameTable() != "" {
+			if renameDatabase == "" {
+				renameDatabase = target.GetDatabase()
+			}
+
+			renameTable := target.GetRenameTable()
+			if renameTable == "" {
+				renameTable = target.GetTable()
+			} else if err := validateBackupName(renameTable); err != nil {
+				return "", err
+			}
+
+			item += fmt.Sprintf(" AS %s.%s", renameDatabase, renameTable)
+		}
+		items = append(items, item)
+	}
+
+	return strings.Join(items, ", "), nil
+}
+
+// validateBackupName validates a database or table name, which unlike the
+// identifiers of settings must not contain dots.
+func validateBackupName(name string) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	if strings.Contains(name, ".") {
+		return fmt.Errorf("invalid identifier %q", name)
+	}
+
+	return nil
+}
+
+func backupQuerySuffix(settings []string, async bool) string {
+	var suffix string
+	if len(settings) > 0 {
+		suffix = " SETTINGS " + strings.Join(settings, ", ")
+	}
+	if async {
+		suffix += " ASYNC"
+	}
+
+	return suffix
+}
+
+func buildBackupStatusSQL(id string) (string, error) {
+	if !backupIDRE.MatchString(id) {
+		return "", fmt.Errorf("invalid backup id %q", id)
+	}
+
+	// name is left out, it holds the S3 credentials on servers which do not mask them
+	return fmt.Sprintf("SELECT id, status, error, num_files, total_size, uncompressed_size, compressed_size, "+
+		"files_read, bytes_read, toUnixTimestamp(start_time), toUnixTimestamp(end_time) "+
+		"FROM system.backups WHERE id = %s FORMAT TabSeparated", quoteSQLString(id)), nil
+}
+
+// parseBackupOperationOutput parses the id and status row printed by BACKUP and RESTORE
+func parseBackupOperationOutput(output string) *BackupOperation {
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	id, status, _ := strings.Cut(line, "\t")
+
+	return &BackupOperation{Id: strings.TrimSpace(id), Status: strings.TrimSpace(status)}
+}
+
+// parseBackupStatusOutput parses the TabSeparated row of buildBackupStatusSQL
+func parseBackupStatusOutput(output string) (*BackupOperation, error) {
+	line := strings.TrimSpace(output)
+	if line == "" {
+		return nil, fmt.Errorf("backup not found")
+	}
+	line, _, _ = strings.Cut(line, "\n")
+
+	fields := strings.Split(line, "\t")
+	if len(fields) != 11 {
+		return nil, fmt.Errorf("unexpected system.backups row %q", line)
+	}
+
+	numbers := make([]int64, 0, 8)
+	for _, field := range fields[3:] {
+		n, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected system.backups row %q: %v", line, err)
+		}
+		numbers = append(numbers, n)
+	}
+
+	unescape := strings.NewReplacer(`\t`, "\t", `\n`, "\n", `\\`, `\`)
+	return &BackupOperation{
+		Id:               fields[0],
+		Status:           fields[1],
+		Error:            unescape.Replace(fields[2]),
+		NumFiles:         numbers[0],
+		TotalSize:        numbers[1],
+		UncompressedSize: numbers[2],
+		CompressedSize:   numbers[3],
+		FilesRead:        numbers[4],
+		BytesRead:        numbers[5],
+		StartTime:        numbers[6],
+		EndTime:          numbers[7],
+	}, nil
+}
+
+// isBackupOperationFinished reports whether the system.backups status is final
+func isBackupOperationFinished(status string) bool {
+	switch status {
+	case "BACKUP_CREATED", "RESTORED":
+		return true
+	default:
+		return IsBackupOperationFailed(status)
+	}
+}
+
+// IsBackupOperationFailed reports whether the system.backups status is a failure
+func IsBackupOperationFailed(status string) bool {
+	return strings.HasSuffix(status, "_FAILED") || status == "BACKUP_CANCELLED" || status == "RESTORE_CANCELLED"
+}
@@ -0,0 +1,143 @@
+package clickhouse
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBuildIncrementalAsyncBackupSQL(t *testing.T) {
+	query, err := buildBackupSQL(&LogicalBackupRequest{
+		BackupFile:    "backup-002",
+		BaseBackup:    "backup-001",
+		ObjectStorage: defaultObjectStorage(),
+		Targets: []*BackupTarget{
+			{Database: "sales", Table: "orders"},
+			{Database: "logs"},
+		},
+		Async: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "BACKUP TABLE sales.orders, DATABASE logs TO S3('https://s3.example.com/backups/backup-002', 'ak', 'sk') "+
+		"SETTINGS base_backup = S3('https://s3.example.com/backups/backup-001', 'ak', 'sk') ASYNC", query)
+
+	_, err = buildBackupSQL(&LogicalBackupRequest{BackupFile: "backup-001", BaseBackup: "backup-001", ObjectStorage: defaultObjectStorage()})
+	require.Error(t, err)
+}
+
+func TestBuildRestoreSQLWithRenames(t *testing.T) {
+	query, err := buildRestoreSQL(&RestoreRequest{
+		BackupFile:    "backup-001",
+		ObjectStorage: defaultObjectStorage(),
+		Targets: []*BackupTarget{
+			{Database: "sales", Table: "orders", RenameTable: "orders_restored"},
+			{Database: "sales", Table: "items", RenameDatabase: "archive"},
+			{Database: "logs", RenameDatabase: "logs_restored"},
+		},
+		AllowNonEmptyTables: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "RESTORE TABLE sales.orders AS sales.orders_restored, TABLE sales.items AS archive.items, "+
+		"DATABASE logs AS logs_restored FROM S3('https://s3.example.com/backups/backup-001', 'ak', 'sk') "+
+		"SETTINGS allow_non_empty_tables = true", query)
+}
+
+func TestBuildBackupTargetsRejectsInvalidTargets(t *testing.T) {
+	for _, target := range []*BackupTarget{
+		{},
+		{Database: "sales.orders"},
+		{Database: "sales", Table: "orders;"},
+		{Database: "sales", RenameTable: "orders"},
+		{Database: "sales", Table: "orders", RenameDatabase: "a b"},
+	} {
+		_, err := buildBackupTargets([]*BackupTarget{target})
+		require.Error(t, err, "%v", target)
+	}
+}
+
+func TestBuildBackupStatusSQL(t *testing.T) {
+	query, err := buildBackupStatusSQL("8c3a1f52-9d4e-4a6b-b7b0-3c1d2e4f5a6b")
+	require.NoError(t, err)
+	require.Contains(t, query, "FROM system.backups WHERE id = '8c3a1f52-9d4e-4a6b-b7b0-3c1d2e4f5a6b'")
+	require.NotContains(t, query, "name")
+
+	_, err = buildBackupStatusSQL("x' OR 1=1")
+	require.Error(t, err)
+}
+
+func TestParseBackupStatusOutput(t *testing.T) {
+	op, err := parseBackupStatusOutput("b1\tBACKUP_FAILED\tCode: 243. NOT_ENOUGH_SPACE\\ton disk\t12\t4096\t8192\t4000\t0\t0\t1714557600\t1714557660\n")
+	require.NoError(t, err)
+	require.Equal(t, "b1", op.GetId())
+	require.Equal(t, "BACKUP_FAILED", op.GetStatus())
+	require.Equal(t, "Code: 243. NOT_ENOUGH_SPACE\ton disk", op.GetError())
+	require.Equal(t, int64(12), op.GetNumFiles())
+	require.Equal(t, int64(4096), op.GetTotalSize())
+	require.Equal(t, int64(4000), op.GetCompressedSize())
+	require.Equal(t, int64(1714557660), op.GetEndTime())
+	require.True(t, IsBackupOperationFailed(op.GetStatus()))
+
+	_, err = parseBackupStatusOutput("")
+	require.EqualError(t, err, "backup not found")
+
+	_, err = parseBackupStatusOutput("b1\tBACKUP_CREATED\n")
+	require.Error(t, err)
+}
+
+func TestLogicalBackupReportsBackupSizes(t *testing.T) {
+	writeEncryptedPassword(t, "admin", "secret")
+
+	runner := &sequenceCommandRunner{outputs: []string{
+		"b1\tBACKUP_CREATED\n",
+		"b1\tBACKUP_CREATED\t\t12\t4096\t8192\t4000\t0\t0\t1714557600\t1714557660\n",
+	}}
+	s := &service{logger: zap.NewNop().Sugar(), slm: &fakeSLM{}, runner: runner}
+
+	op, err := s.LogicalBackup(context.Background(), &LogicalBackupRequest{
+		Username:      "admin",
+		BackupFile:    "backup-001",
+		ObjectStorage: defaultObjectStorage(),
+	})
+	require.NoError(t, err)
+	require.Len(t, runner.queries, 2)
+	require.Equal(t, expectedBackupSQL, runner.queries[0])
+	require.Equal(t, "BACKUP_CREATED", op.GetStatus())
+	require.Equal(t, int64(4096), op.GetTotalSize())
+}
+
+func TestAsyncRestoreReturnsOnceStarted(t *testing.T) {
+	writeEncryptedPassword(t, "admin", "secret")
+
+	runner := &sequenceCommandRunner{outputs: []string{"r1\tRESTORING\n"}}
+	s := &service{logger: zap.NewNop().Sugar(), slm: &fakeSLM{}, runner: runner}
+
+	op, err := s.Restore(context.Background(), &RestoreRequest{
+		Username:      "admin",
+		BackupFile:    "backup-001",
+		ObjectStorage: defaultObjectStorage(),
+		Async:         true,
+	})
+	require.NoError(t, err)
+	require.Len(t, runner.queries, 1)
+	require.Equal(t, expectedRestoreSQL+" ASYNC", runner.queries[0])
+	require.Equal(t, &BackupOperation{Id: "r1", Status: "RESTORING"}, op)
+}
+
+// sequenceCommandRunner prints one output per query, in order.
+type sequenceCommandRunner struct {
+	fakeCommandRunner
+
+	outputs []string
+}
+
+func (f *sequenceCommandRunner) ExecuteCommand(cmd *exec.Cmd, logPrefix string) error {
+	f.output = ""
+	if len(f.outputs) > 0 {
+		f.output, f.outputs = f.outputs[0], f.outputs[1:]
+	}
+
+	return f.fakeCommandRunner.ExecuteCommand(cmd, logPrefix)
+}
@@ -27,6 +27,13 @@ type LogicalBackupRequest struct {
 	BackupFile    string                 `protobuf:"bytes,1,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
 	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
 	ObjectStorage *common.ObjectStorage  `protobuf:"bytes,3,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
+	// base_backup is the backup_file of an earlier backup in the same bucket,
+	// only the data changed since it is backed up
+	BaseBackup string `protobuf:"bytes,4,opt,name=base_backup,json=baseBackup,proto3" json:"base_backup,omitempty"`
+	// targets limits the backup to databases and tables, all of them when empty
+	Targets []*BackupTarget `protobuf:"bytes,5,rep,name=targets,proto3" json:"targets,omitempty"`
+	// async returns once the backup started, BackupStatus reports its progress
+	Async         bool `protobuf:"varint,6,opt,name=async,proto3" json:"async,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,13 +89,40 @@ func (x *LogicalBackupRequest) GetObjectStorage() *common.ObjectStorage {
 	return nil
 }
 
+func (x *LogicalBackupRequest) GetBaseBackup() string {
+	if x != nil {
+		return x.BaseBackup
+	}
+	return ""
+}
+
+func (x *LogicalBackupRequest) GetTargets() []*BackupTarget {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *LogicalBackupRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
 type RestoreRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BackupFile    string                 `protobuf:"bytes,1,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
 	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
 	ObjectStorage *common.ObjectStorage  `protobuf:"bytes,3,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// targets limits the restore to databases and tables of the backup, all of
+	// them when empty
+	Targets []*BackupTarget `protobuf:"bytes,4,rep,name=targets,proto3" json:"targets,omitempty"`
+	// allow_non_empty_tables restores into tables which already hold data
+	AllowNonEmptyTables bool `protobuf:"varint,5,opt,name=allow_non_empty_tables,json=allowNonEmptyTables,proto3" json:"allow_non_empty_tables,omitempty"`
+	Async               bool `protobuf:"varint,6,opt,name=async,proto3" json:"async,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *RestoreRequest) Reset() {
@@ -142,6 +176,281 @@ func (x *RestoreRequest) GetObjectStorage() *common.ObjectStorage {
 	return nil
 }
 
+func (x *RestoreRequest) GetTargets() []*BackupTarget {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *RestoreRequest) GetAllowNonEmptyTables() bool {
+	if x != nil {
+		return x.AllowNonEmptyTables
+	}
+	return false
+}
+
+func (x *RestoreRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
+// BackupTarget is a database, or a table of it, as "DATABASE db" or
+// "TABLE db.table". The rename fields name it in the backup, or on restore
+// the database or table it is restored as.
+type BackupTarget struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Database       string                 `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Table          string                 `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	RenameDatabase string                 `protobuf:"bytes,3,opt,name=rename_database,json=renameDatabase,proto3" json:"rename_database,omitempty"`
+	RenameTable    string                 `protobuf:"bytes,4,opt,name=rename_table,json=renameTable,proto3" json:"rename_table,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BackupTarget) Reset() {
+	*x = BackupTarget{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupTarget) ProtoMessage() {}
+
+func (x *BackupTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupTarget.ProtoReflect.Descriptor instead.
+func (*BackupTarget) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BackupTarget) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *BackupTarget) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *BackupTarget) GetRenameDatabase() string {
+	if x != nil {
+		return x.RenameDatabase
+	}
+	return ""
+}
+
+func (x *BackupTarget) GetRenameTable() string {
+	if x != nil {
+		return x.RenameTable
+	}
+	return ""
+}
+
+type BackupStatusRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// id is returned by LogicalBackup and Restore
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupStatusRequest) Reset() {
+	*x = BackupStatusRequest{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupStatusRequest) ProtoMessage() {}
+
+func (x *BackupStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupStatusRequest.ProtoReflect.Descriptor instead.
+func (*BackupStatusRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BackupStatusRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *BackupStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// BackupOperation is the state of a backup or restore in system.backups.
+type BackupOperation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// status is the system.backups status, as CREATING_BACKUP, BACKUP_CREATED,
+	// BACKUP_FAILED, RESTORING, RESTORED or RESTORE_FAILED
+	Status   string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Error    string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	NumFiles int64  `protobuf:"varint,4,opt,name=num_files,json=numFiles,proto3" json:"num_files,omitempty"`
+	// total_size is the size of the backup in bytes, including the files of
+	// the base backup
+	TotalSize        int64 `protobuf:"varint,5,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	UncompressedSize int64 `protobuf:"varint,6,opt,name=uncompressed_size,json=uncompressedSize,proto3" json:"uncompressed_size,omitempty"`
+	CompressedSize   int64 `protobuf:"varint,7,opt,name=compressed_size,json=compressedSize,proto3" json:"compressed_size,omitempty"`
+	FilesRead        int64 `protobuf:"varint,8,opt,name=files_read,json=filesRead,proto3" json:"files_read,omitempty"`
+	BytesRead        int64 `protobuf:"varint,9,opt,name=bytes_read,json=bytesRead,proto3" json:"bytes_read,omitempty"`
+	// start_time and end_time are unix timestamps in seconds
+	StartTime     int64 `protobuf:"varint,10,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       int64 `protobuf:"varint,11,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupOperation) Reset() {
+	*x = BackupOperation{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupOperation) ProtoMessage() {}
+
+func (x *BackupOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupOperation.ProtoReflect.Descriptor instead.
+func (*BackupOperation) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BackupOperation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BackupOperation) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BackupOperation) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BackupOperation) GetNumFiles() int64 {
+	if x != nil {
+		return x.NumFiles
+	}
+	return 0
+}
+
+func (x *BackupOperation) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *BackupOperation) GetUncompressedSize() int64 {
+	if x != nil {
+		return x.UncompressedSize
+	}
+	return 0
+}
+
+func (x *BackupOperation) GetCompressedSize() int64 {
+	if x != nil {
+		return x.CompressedSize
+	}
+	return 0
+}
+
+func (x *BackupOperation) GetFilesRead() int64 {
+	if x != nil {
+		return x.FilesRead
+	}
+	return 0
+}
+
+func (x *BackupOperation) GetBytesRead() int64 {
+	if x != nil {
+		return x.BytesRead
+	}
+	return 0
+}
+
+func (x *BackupOperation) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *BackupOperation) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
 type SetVariableRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -153,7 +462,7 @@ type SetVariableRequest struct {
 
 func (x *SetVariableRequest) Reset() {
 	*x = SetVariableRequest{}
-	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[2]
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -165,7 +474,7 @@ func (x *SetVariableRequest) String() string {
 func (*SetVariableRequest) ProtoMessage() {}
 
 func (x *SetVariableRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[2]
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -178,7 +487,7 @@ func (x *SetVariableRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetVariableRequest.ProtoReflect.Descriptor instead.
 func (*SetVariableRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{2}
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SetVariableRequest) GetKey() string {
@@ -202,30 +511,987 @@ func (x *SetVariableRequest) GetUsername() string {
 	return ""
 }
 
-var File_pkg_agent_app_clickhouse_pb_clickhouse_proto protoreflect.FileDescriptor
+type SetVariablesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variables     map[string]string      `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	DryRun        bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDesc = "" +
-	"\n" +
-	",pkg/agent/app/clickhouse/pb/clickhouse.proto\x12\n" +
-	"clickhouse\x1a$pkg/agent/app/common/pb/common.proto\"\x91\x01\n" +
-	"\x14LogicalBackupRequest\x12\x1f\n" +
-	"\vbackup_file\x18\x01 \x01(\tR\n" +
-	"backupFile\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\x12<\n" +
-	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\"\x8b\x01\n" +
-	"\x0eRestoreRequest\x12\x1f\n" +
-	"\vbackup_file\x18\x01 \x01(\tR\n" +
-	"backupFile\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\x12<\n" +
-	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\"X\n" +
-	"\x12SetVariableRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1a\n" +
-	"\busername\x18\x03 \x01(\tR\busername2\xcb\x01\n" +
-	"\x13ClickHouseOperation\x12@\n" +
-	"\rLogicalBackup\x12 .clickhouse.LogicalBackupRequest\x1a\r.common.Empty\x124\n" +
-	"\aRestore\x12\x1a.clickhouse.RestoreRequest\x1a\r.common.Empty\x12<\n" +
-	"\vSetVariable\x12\x1e.clickhouse.SetVariableRequest\x1a\r.common.EmptyB9Z7github.com/upmio/unit-operator/pkg/agent/app/clickhouseb\x06proto3"
+func (x *SetVariablesRequest) Reset() {
+	*x = SetVariablesRequest{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariablesRequest) ProtoMessage() {}
+
+func (x *SetVariablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariablesRequest.ProtoReflect.Descriptor instead.
+func (*SetVariablesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetVariablesRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *SetVariablesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetVariablesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type Privilege struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Database      string                 `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Table         string                 `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Privileges    []string               `protobuf:"bytes,3,rep,name=privileges,proto3" json:"privileges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Privilege) Reset() {
+	*x = Privilege{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Privilege) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Privilege) ProtoMessage() {}
+
+func (x *Privilege) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Privilege.ProtoReflect.Descriptor instead.
+func (*Privilege) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Privilege) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *Privilege) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *Privilege) GetPrivileges() []string {
+	if x != nil {
+		return x.Privileges
+	}
+	return nil
+}
+
+type CreateUserRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Username   string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User       string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Password   string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Privileges []*Privilege           `protobuf:"bytes,4,rep,name=privileges,proto3" json:"privileges,omitempty"`
+	// revokes are the privileges removed from the user since the last call
+	Revokes       []*Privilege `protobuf:"bytes,5,rep,name=revokes,proto3" json:"revokes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPrivileges() []*Privilege {
+	if x != nil {
+		return x.Privileges
+	}
+	return nil
+}
+
+func (x *CreateUserRequest) GetRevokes() []*Privilege {
+	if x != nil {
+		return x.Revokes
+	}
+	return nil
+}
+
+type DropUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropUserRequest) Reset() {
+	*x = DropUserRequest{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropUserRequest) ProtoMessage() {}
+
+func (x *DropUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropUserRequest.ProtoReflect.Descriptor instead.
+func (*DropUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DropUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *DropUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type CreateDatabaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Database      string                 `protobuf:"bytes,2,opt,name=database,proto3" json:"database,omitempty"`
+	Engine        string                 `protobuf:"bytes,3,opt,name=engine,proto3" json:"engine,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDatabaseRequest) Reset() {
+	*x = CreateDatabaseRequest{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDatabaseRequest) ProtoMessage() {}
+
+func (x *CreateDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*CreateDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateDatabaseRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateDatabaseRequest) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *CreateDatabaseRequest) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+type HealthRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// max_delay_seconds is the replication delay a replica is reported behind
+	// at, 300 when unset
+	MaxDelaySeconds int64 `protobuf:"varint,2,opt,name=max_delay_seconds,json=maxDelaySeconds,proto3" json:"max_delay_seconds,omitempty"`
+	// max_queue_size reports replicas with a longer replication queue, no
+	// limit when unset
+	MaxQueueSize int64 `protobuf:"varint,3,opt,name=max_queue_size,json=maxQueueSize,proto3" json:"max_queue_size,omitempty"`
+	// min_free_disk_percent reports the disks with less free space, 10 when unset
+	MinFreeDiskPercent int64 `protobuf:"varint,4,opt,name=min_free_disk_percent,json=minFreeDiskPercent,proto3" json:"min_free_disk_percent,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *HealthRequest) Reset() {
+	*x = HealthRequest{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthRequest) ProtoMessage() {}
+
+func (x *HealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HealthRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *HealthRequest) GetMaxDelaySeconds() int64 {
+	if x != nil {
+		return x.MaxDelaySeconds
+	}
+	return 0
+}
+
+func (x *HealthRequest) GetMaxQueueSize() int64 {
+	if x != nil {
+		return x.MaxQueueSize
+	}
+	return 0
+}
+
+func (x *HealthRequest) GetMinFreeDiskPercent() int64 {
+	if x != nil {
+		return x.MinFreeDiskPercent
+	}
+	return 0
+}
+
+type ReplicaStatus struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Database         string                 `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Table            string                 `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	IsReadonly       bool                   `protobuf:"varint,3,opt,name=is_readonly,json=isReadonly,proto3" json:"is_readonly,omitempty"`
+	IsSessionExpired bool                   `protobuf:"varint,4,opt,name=is_session_expired,json=isSessionExpired,proto3" json:"is_session_expired,omitempty"`
+	AbsoluteDelay    int64                  `protobuf:"varint,5,opt,name=absolute_delay,json=absoluteDelay,proto3" json:"absolute_delay,omitempty"`
+	QueueSize        int64                  `protobuf:"varint,6,opt,name=queue_size,json=queueSize,proto3" json:"queue_size,omitempty"`
+	InsertsInQueue   int64                  `protobuf:"varint,7,opt,name=inserts_in_queue,json=insertsInQueue,proto3" json:"inserts_in_queue,omitempty"`
+	MergesInQueue    int64                  `protobuf:"varint,8,opt,name=merges_in_queue,json=mergesInQueue,proto3" json:"merges_in_queue,omitempty"`
+	// log_lag is the number of replication log entries not fetched yet
+	LogLag         int64 `protobuf:"varint,9,opt,name=log_lag,json=logLag,proto3" json:"log_lag,omitempty"`
+	ActiveReplicas int64 `protobuf:"varint,10,opt,name=active_replicas,json=activeReplicas,proto3" json:"active_replicas,omitempty"`
+	TotalReplicas  int64 `protobuf:"varint,11,opt,name=total_replicas,json=totalReplicas,proto3" json:"total_replicas,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReplicaStatus) Reset() {
+	*x = ReplicaStatus{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicaStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicaStatus) ProtoMessage() {}
+
+func (x *ReplicaStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicaStatus.ProtoReflect.Descriptor instead.
+func (*ReplicaStatus) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReplicaStatus) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *ReplicaStatus) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *ReplicaStatus) GetIsReadonly() bool {
+	if x != nil {
+		return x.IsReadonly
+	}
+	return false
+}
+
+func (x *ReplicaStatus) GetIsSessionExpired() bool {
+	if x != nil {
+		return x.IsSessionExpired
+	}
+	return false
+}
+
+func (x *ReplicaStatus) GetAbsoluteDelay() int64 {
+	if x != nil {
+		return x.AbsoluteDelay
+	}
+	return 0
+}
+
+func (x *ReplicaStatus) GetQueueSize() int64 {
+	if x != nil {
+		return x.QueueSize
+	}
+	return 0
+}
+
+func (x *ReplicaStatus) GetInsertsInQueue() int64 {
+	if x != nil {
+		return x.InsertsInQueue
+	}
+	return 0
+}
+
+func (x *ReplicaStatus) GetMergesInQueue() int64 {
+	if x != nil {
+		return x.MergesInQueue
+	}
+	return 0
+}
+
+func (x *ReplicaStatus) GetLogLag() int64 {
+	if x != nil {
+		return x.LogLag
+	}
+	return 0
+}
+
+func (x *ReplicaStatus) GetActiveReplicas() int64 {
+	if x != nil {
+		return x.ActiveReplicas
+	}
+	return 0
+}
+
+func (x *ReplicaStatus) GetTotalReplicas() int64 {
+	if x != nil {
+		return x.TotalReplicas
+	}
+	return 0
+}
+
+type ClusterReplica struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cluster       string                 `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	ShardNum      int64                  `protobuf:"varint,2,opt,name=shard_num,json=shardNum,proto3" json:"shard_num,omitempty"`
+	ReplicaNum    int64                  `protobuf:"varint,3,opt,name=replica_num,json=replicaNum,proto3" json:"replica_num,omitempty"`
+	HostName      string                 `protobuf:"bytes,4,opt,name=host_name,json=hostName,proto3" json:"host_name,omitempty"`
+	Port          int64                  `protobuf:"varint,5,opt,name=port,proto3" json:"port,omitempty"`
+	IsLocal       bool                   `protobuf:"varint,6,opt,name=is_local,json=isLocal,proto3" json:"is_local,omitempty"`
+	ErrorsCount   int64                  `protobuf:"varint,7,opt,name=errors_count,json=errorsCount,proto3" json:"errors_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterReplica) Reset() {
+	*x = ClusterReplica{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterReplica) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterReplica) ProtoMessage() {}
+
+func (x *ClusterReplica) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterReplica.ProtoReflect.Descriptor instead.
+func (*ClusterReplica) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ClusterReplica) GetCluster() string {
+	if x != nil {
+		return x.Cluster
+	}
+	return ""
+}
+
+func (x *ClusterReplica) GetShardNum() int64 {
+	if x != nil {
+		return x.ShardNum
+	}
+	return 0
+}
+
+func (x *ClusterReplica) GetReplicaNum() int64 {
+	if x != nil {
+		return x.ReplicaNum
+	}
+	return 0
+}
+
+func (x *ClusterReplica) GetHostName() string {
+	if x != nil {
+		return x.HostName
+	}
+	return ""
+}
+
+func (x *ClusterReplica) GetPort() int64 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *ClusterReplica) GetIsLocal() bool {
+	if x != nil {
+		return x.IsLocal
+	}
+	return false
+}
+
+func (x *ClusterReplica) GetErrorsCount() int64 {
+	if x != nil {
+		return x.ErrorsCount
+	}
+	return 0
+}
+
+type KeeperSession struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// configured is false when the server uses no Keeper or ZooKeeper
+	Configured           bool   `protobuf:"varint,1,opt,name=configured,proto3" json:"configured,omitempty"`
+	Host                 string `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Port                 int64  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	IsExpired            bool   `protobuf:"varint,4,opt,name=is_expired,json=isExpired,proto3" json:"is_expired,omitempty"`
+	SessionUptimeSeconds int64  `protobuf:"varint,5,opt,name=session_uptime_seconds,json=sessionUptimeSeconds,proto3" json:"session_uptime_seconds,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *KeeperSession) Reset() {
+	*x = KeeperSession{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeeperSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeeperSession) ProtoMessage() {}
+
+func (x *KeeperSession) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeeperSession.ProtoReflect.Descriptor instead.
+func (*KeeperSession) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *KeeperSession) GetConfigured() bool {
+	if x != nil {
+		return x.Configured
+	}
+	return false
+}
+
+func (x *KeeperSession) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *KeeperSession) GetPort() int64 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *KeeperSession) GetIsExpired() bool {
+	if x != nil {
+		return x.IsExpired
+	}
+	return false
+}
+
+func (x *KeeperSession) GetSessionUptimeSeconds() int64 {
+	if x != nil {
+		return x.SessionUptimeSeconds
+	}
+	return 0
+}
+
+type DiskUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	FreeSpace     int64                  `protobuf:"varint,3,opt,name=free_space,json=freeSpace,proto3" json:"free_space,omitempty"`
+	TotalSpace    int64                  `protobuf:"varint,4,opt,name=total_space,json=totalSpace,proto3" json:"total_space,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiskUsage) Reset() {
+	*x = DiskUsage{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiskUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskUsage) ProtoMessage() {}
+
+func (x *DiskUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskUsage.ProtoReflect.Descriptor instead.
+func (*DiskUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DiskUsage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DiskUsage) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DiskUsage) GetFreeSpace() int64 {
+	if x != nil {
+		return x.FreeSpace
+	}
+	return 0
+}
+
+func (x *DiskUsage) GetTotalSpace() int64 {
+	if x != nil {
+		return x.TotalSpace
+	}
+	return 0
+}
+
+type HealthResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Replicas []*ReplicaStatus       `protobuf:"bytes,1,rep,name=replicas,proto3" json:"replicas,omitempty"`
+	Clusters []*ClusterReplica      `protobuf:"bytes,2,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	Keeper   *KeeperSession         `protobuf:"bytes,3,opt,name=keeper,proto3" json:"keeper,omitempty"`
+	Disks    []*DiskUsage           `protobuf:"bytes,4,rep,name=disks,proto3" json:"disks,omitempty"`
+	// the problems found in each area, an area without problems is healthy
+	ReplicaProblems []string `protobuf:"bytes,5,rep,name=replica_problems,json=replicaProblems,proto3" json:"replica_problems,omitempty"`
+	KeeperProblems  []string `protobuf:"bytes,6,rep,name=keeper_problems,json=keeperProblems,proto3" json:"keeper_problems,omitempty"`
+	DiskProblems    []string `protobuf:"bytes,7,rep,name=disk_problems,json=diskProblems,proto3" json:"disk_problems,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthResponse) ProtoMessage() {}
+
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HealthResponse) GetReplicas() []*ReplicaStatus {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+func (x *HealthResponse) GetClusters() []*ClusterReplica {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+func (x *HealthResponse) GetKeeper() *KeeperSession {
+	if x != nil {
+		return x.Keeper
+	}
+	return nil
+}
+
+func (x *HealthResponse) GetDisks() []*DiskUsage {
+	if x != nil {
+		return x.Disks
+	}
+	return nil
+}
+
+func (x *HealthResponse) GetReplicaProblems() []string {
+	if x != nil {
+		return x.ReplicaProblems
+	}
+	return nil
+}
+
+func (x *HealthResponse) GetKeeperProblems() []string {
+	if x != nil {
+		return x.KeeperProblems
+	}
+	return nil
+}
+
+func (x *HealthResponse) GetDiskProblems() []string {
+	if x != nil {
+		return x.DiskProblems
+	}
+	return nil
+}
+
+var File_pkg_agent_app_clickhouse_pb_clickhouse_proto protoreflect.FileDescriptor
+
+const file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDesc = "" +
+	"\n" +
+	",pkg/agent/app/clickhouse/pb/clickhouse.proto\x12\n" +
+	"clickhouse\x1a$pkg/agent/app/common/pb/common.proto\"\xfc\x01\n" +
+	"\x14LogicalBackupRequest\x12\x1f\n" +
+	"\vbackup_file\x18\x01 \x01(\tR\n" +
+	"backupFile\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12<\n" +
+	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\x12\x1f\n" +
+	"\vbase_backup\x18\x04 \x01(\tR\n" +
+	"baseBackup\x122\n" +
+	"\atargets\x18\x05 \x03(\v2\x18.clickhouse.BackupTargetR\atargets\x12\x14\n" +
+	"\x05async\x18\x06 \x01(\bR\x05async\"\x8a\x02\n" +
+	"\x0eRestoreRequest\x12\x1f\n" +
+	"\vbackup_file\x18\x01 \x01(\tR\n" +
+	"backupFile\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12<\n" +
+	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\x122\n" +
+	"\atargets\x18\x04 \x03(\v2\x18.clickhouse.BackupTargetR\atargets\x123\n" +
+	"\x16allow_non_empty_tables\x18\x05 \x01(\bR\x13allowNonEmptyTables\x12\x14\n" +
+	"\x05async\x18\x06 \x01(\bR\x05async\"\x8c\x01\n" +
+	"\fBackupTarget\x12\x1a\n" +
+	"\bdatabase\x18\x01 \x01(\tR\bdatabase\x12\x14\n" +
+	"\x05table\x18\x02 \x01(\tR\x05table\x12'\n" +
+	"\x0frename_database\x18\x03 \x01(\tR\x0erenameDatabase\x12!\n" +
+	"\frename_table\x18\x04 \x01(\tR\vrenameTable\"A\n" +
+	"\x13BackupStatusRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"\xd9\x02\n" +
+	"\x0fBackupOperation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1b\n" +
+	"\tnum_files\x18\x04 \x01(\x03R\bnumFiles\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x05 \x01(\x03R\ttotalSize\x12+\n" +
+	"\x11uncompressed_size\x18\x06 \x01(\x03R\x10uncompressedSize\x12'\n" +
+	"\x0fcompressed_size\x18\a \x01(\x03R\x0ecompressedSize\x12\x1d\n" +
+	"\n" +
+	"files_read\x18\b \x01(\x03R\tfilesRead\x12\x1d\n" +
+	"\n" +
+	"bytes_read\x18\t \x01(\x03R\tbytesRead\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\n" +
+	" \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\v \x01(\x03R\aendTime\"X\n" +
+	"\x12SetVariableRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\"\xd6\x01\n" +
+	"\x13SetVariablesRequest\x12L\n" +
+	"\tvariables\x18\x01 \x03(\v2..clickhouse.SetVariablesRequest.VariablesEntryR\tvariables\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"]\n" +
+	"\tPrivilege\x12\x1a\n" +
+	"\bdatabase\x18\x01 \x01(\tR\bdatabase\x12\x14\n" +
+	"\x05table\x18\x02 \x01(\tR\x05table\x12\x1e\n" +
+	"\n" +
+	"privileges\x18\x03 \x03(\tR\n" +
+	"privileges\"\xc7\x01\n" +
+	"\x11CreateUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x125\n" +
+	"\n" +
+	"privileges\x18\x04 \x03(\v2\x15.clickhouse.PrivilegeR\n" +
+	"privileges\x12/\n" +
+	"\arevokes\x18\x05 \x03(\v2\x15.clickhouse.PrivilegeR\arevokes\"A\n" +
+	"\x0fDropUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\"g\n" +
+	"\x15CreateDatabaseRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
+	"\bdatabase\x18\x02 \x01(\tR\bdatabase\x12\x16\n" +
+	"\x06engine\x18\x03 \x01(\tR\x06engine\"\xb0\x01\n" +
+	"\rHealthRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12*\n" +
+	"\x11max_delay_seconds\x18\x02 \x01(\x03R\x0fmaxDelaySeconds\x12$\n" +
+	"\x0emax_queue_size\x18\x03 \x01(\x03R\fmaxQueueSize\x121\n" +
+	"\x15min_free_disk_percent\x18\x04 \x01(\x03R\x12minFreeDiskPercent\"\x91\x03\n" +
+	"\rReplicaStatus\x12\x1a\n" +
+	"\bdatabase\x18\x01 \x01(\tR\bdatabase\x12\x14\n" +
+	"\x05table\x18\x02 \x01(\tR\x05table\x12\x1f\n" +
+	"\vis_readonly\x18\x03 \x01(\bR\n" +
+	"isReadonly\x12,\n" +
+	"\x12is_session_expired\x18\x04 \x01(\bR\x10isSessionExpired\x12%\n" +
+	"\x0eabsolute_delay\x18\x05 \x01(\x03R\rabsoluteDelay\x12\x1d\n" +
+	"\n" +
+	"queue_size\x18\x06 \x01(\x03R\tqueueSize\x12(\n" +
+	"\x10inserts_in_queue\x18\a \x01(\x03R\x0einsertsInQueue\x12&\n" +
+	"\x0fmerges_in_queue\x18\b \x01(\x03R\rmergesInQueue\x12\x17\n" +
+	"\alog_lag\x18\t \x01(\x03R\x06logLag\x12'\n" +
+	"\x0factive_replicas\x18\n" +
+	" \x01(\x03R\x0eactiveReplicas\x12%\n" +
+	"\x0etotal_replicas\x18\v \x01(\x03R\rtotalReplicas\"\xd7\x01\n" +
+	"\x0eClusterReplica\x12\x18\n" +
+	"\acluster\x18\x01 \x01(\tR\acluster\x12\x1b\n" +
+	"\tshard_num\x18\x02 \x01(\x03R\bshardNum\x12\x1f\n" +
+	"\vreplica_num\x18\x03 \x01(\x03R\n" +
+	"replicaNum\x12\x1b\n" +
+	"\thost_name\x18\x04 \x01(\tR\bhostName\x12\x12\n" +
+	"\x04port\x18\x05 \x01(\x03R\x04port\x12\x19\n" +
+	"\bis_local\x18\x06 \x01(\bR\aisLocal\x12!\n" +
+	"\ferrors_count\x18\a \x01(\x03R\verrorsCount\"\xac\x01\n" +
+	"\rKeeperSession\x12\x1e\n" +
+	"\n" +
+	"configured\x18\x01 \x01(\bR\n" +
+	"configured\x12\x12\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x03 \x01(\x03R\x04port\x12\x1d\n" +
+	"\n" +
+	"is_expired\x18\x04 \x01(\bR\tisExpired\x124\n" +
+	"\x16session_uptime_seconds\x18\x05 \x01(\x03R\x14sessionUptimeSeconds\"s\n" +
+	"\tDiskUsage\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1d\n" +
+	"\n" +
+	"free_space\x18\x03 \x01(\x03R\tfreeSpace\x12\x1f\n" +
+	"\vtotal_space\x18\x04 \x01(\x03R\n" +
+	"totalSpace\"\xd8\x02\n" +
+	"\x0eHealthResponse\x125\n" +
+	"\breplicas\x18\x01 \x03(\v2\x19.clickhouse.ReplicaStatusR\breplicas\x126\n" +
+	"\bclusters\x18\x02 \x03(\v2\x1a.clickhouse.ClusterReplicaR\bclusters\x121\n" +
+	"\x06keeper\x18\x03 \x01(\v2\x19.clickhouse.KeeperSessionR\x06keeper\x12+\n" +
+	"\x05disks\x18\x04 \x03(\v2\x15.clickhouse.DiskUsageR\x05disks\x12)\n" +
+	"\x10replica_problems\x18\x05 \x03(\tR\x0freplicaProblems\x12'\n" +
+	"\x0fkeeper_problems\x18\x06 \x03(\tR\x0ekeeperProblems\x12#\n" +
+	"\rdisk_problems\x18\a \x03(\tR\fdiskProblems2\x8b\x05\n" +
+	"\x13ClickHouseOperation\x12N\n" +
+	"\rLogicalBackup\x12 .clickhouse.LogicalBackupRequest\x1a\x1b.clickhouse.BackupOperation\x12B\n" +
+	"\aRestore\x12\x1a.clickhouse.RestoreRequest\x1a\x1b.clickhouse.BackupOperation\x12L\n" +
+	"\fBackupStatus\x12\x1f.clickhouse.BackupStatusRequest\x1a\x1b.clickhouse.BackupOperation\x12?\n" +
+	"\x06Health\x12\x19.clickhouse.HealthRequest\x1a\x1a.clickhouse.HealthResponse\x12J\n" +
+	"\vSetVariable\x12\x1e.clickhouse.SetVariableRequest\x1a\x1b.common.SetVariableResponse\x12M\n" +
+	"\fSetVariables\x12\x1f.clickhouse.SetVariablesRequest\x1a\x1c.common.SetVariablesResponse\x12:\n" +
+	"\n" +
+	"CreateUser\x12\x1d.clickhouse.CreateUserRequest\x1a\r.common.Empty\x126\n" +
+	"\bDropUser\x12\x1b.clickhouse.DropUserRequest\x1a\r.common.Empty\x12B\n" +
+	"\x0eCreateDatabase\x12!.clickhouse.CreateDatabaseRequest\x1a\r.common.EmptyB9Z7github.com/upmio/unit-operator/pkg/agent/app/clickhouseb\x06proto3"
 
 var (
 	file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescOnce sync.Once
@@ -239,28 +1505,66 @@ func file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescGZIP() []byte {
 	return file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDescData
 }
 
-var file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pkg_agent_app_clickhouse_pb_clickhouse_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_pkg_agent_app_clickhouse_pb_clickhouse_proto_goTypes = []any{
-	(*LogicalBackupRequest)(nil), // 0: clickhouse.LogicalBackupRequest
-	(*RestoreRequest)(nil),       // 1: clickhouse.RestoreRequest
-	(*SetVariableRequest)(nil),   // 2: clickhouse.SetVariableRequest
-	(*common.ObjectStorage)(nil), // 3: common.ObjectStorage
-	(*common.Empty)(nil),         // 4: common.Empty
+	(*LogicalBackupRequest)(nil),        // 0: clickhouse.LogicalBackupRequest
+	(*RestoreRequest)(nil),              // 1: clickhouse.RestoreRequest
+	(*BackupTarget)(nil),                // 2: clickhouse.BackupTarget
+	(*BackupStatusRequest)(nil),         // 3: clickhouse.BackupStatusRequest
+	(*BackupOperation)(nil),             // 4: clickhouse.BackupOperation
+	(*SetVariableRequest)(nil),          // 5: clickhouse.SetVariableRequest
+	(*SetVariablesRequest)(nil),         // 6: clickhouse.SetVariablesRequest
+	(*Privilege)(nil),                   // 7: clickhouse.Privilege
+	(*CreateUserRequest)(nil),           // 8: clickhouse.CreateUserRequest
+	(*DropUserRequest)(nil),             // 9: clickhouse.DropUserRequest
+	(*CreateDatabaseRequest)(nil),       // 10: clickhouse.CreateDatabaseRequest
+	(*HealthRequest)(nil),               // 11: clickhouse.HealthRequest
+	(*ReplicaStatus)(nil),               // 12: clickhouse.ReplicaStatus
+	(*ClusterReplica)(nil),              // 13: clickhouse.ClusterReplica
+	(*KeeperSession)(nil),               // 14: clickhouse.KeeperSession
+	(*DiskUsage)(nil),                   // 15: clickhouse.DiskUsage
+	(*HealthResponse)(nil),              // 16: clickhouse.HealthResponse
+	nil,                                 // 17: clickhouse.SetVariablesRequest.VariablesEntry
+	(*common.ObjectStorage)(nil),        // 18: common.ObjectStorage
+	(*common.SetVariableResponse)(nil),  // 19: common.SetVariableResponse
+	(*common.SetVariablesResponse)(nil), // 20: common.SetVariablesResponse
+	(*common.Empty)(nil),                // 21: common.Empty
 }
 var file_pkg_agent_app_clickhouse_pb_clickhouse_proto_depIdxs = []int32{
-	3, // 0: clickhouse.LogicalBackupRequest.object_storage:type_name -> common.ObjectStorage
-	3, // 1: clickhouse.RestoreRequest.object_storage:type_name -> common.ObjectStorage
-	0, // 2: clickhouse.ClickHouseOperation.LogicalBackup:input_type -> clickhouse.LogicalBackupRequest
-	1, // 3: clickhouse.ClickHouseOperation.Restore:input_type -> clickhouse.RestoreRequest
-	2, // 4: clickhouse.ClickHouseOperation.SetVariable:input_type -> clickhouse.SetVariableRequest
-	4, // 5: clickhouse.ClickHouseOperation.LogicalBackup:output_type -> common.Empty
-	4, // 6: clickhouse.ClickHouseOperation.Restore:output_type -> common.Empty
-	4, // 7: clickhouse.ClickHouseOperation.SetVariable:output_type -> common.Empty
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	18, // 0: clickhouse.LogicalBackupRequest.object_storage:type_name -> common.ObjectStorage
+	2,  // 1: clickhouse.LogicalBackupRequest.targets:type_name -> clickhouse.BackupTarget
+	18, // 2: clickhouse.RestoreRequest.object_storage:type_name -> common.ObjectStorage
+	2,  // 3: clickhouse.RestoreRequest.targets:type_name -> clickhouse.BackupTarget
+	17, // 4: clickhouse.SetVariablesRequest.variables:type_name -> clickhouse.SetVariablesRequest.VariablesEntry
+	7,  // 5: clickhouse.CreateUserRequest.privileges:type_name -> clickhouse.Privilege
+	7,  // 6: clickhouse.CreateUserRequest.revokes:type_name -> clickhouse.Privilege
+	12, // 7: clickhouse.HealthResponse.replicas:type_name -> clickhouse.ReplicaStatus
+	13, // 8: clickhouse.HealthResponse.clusters:type_name -> clickhouse.ClusterReplica
+	14, // 9: clickhouse.HealthResponse.keeper:type_name -> clickhouse.KeeperSession
+	15, // 10: clickhouse.HealthResponse.disks:type_name -> clickhouse.DiskUsage
+	0,  // 11: clickhouse.ClickHouseOperation.LogicalBackup:input_type -> clickhouse.LogicalBackupRequest
+	1,  // 12: clickhouse.ClickHouseOperation.Restore:input_type -> clickhouse.RestoreRequest
+	3,  // 13: clickhouse.ClickHouseOperation.BackupStatus:input_type -> clickhouse.BackupStatusRequest
+	11, // 14: clickhouse.ClickHouseOperation.Health:input_type -> clickhouse.HealthRequest
+	5,  // 15: clickhouse.ClickHouseOperation.SetVariable:input_type -> clickhouse.SetVariableRequest
+	6,  // 16: clickhouse.ClickHouseOperation.SetVariables:input_type -> clickhouse.SetVariablesRequest
+	8,  // 17: clickhouse.ClickHouseOperation.CreateUser:input_type -> clickhouse.CreateUserRequest
+	9,  // 18: clickhouse.ClickHouseOperation.DropUser:input_type -> clickhouse.DropUserRequest
+	10, // 19: clickhouse.ClickHouseOperation.CreateDatabase:input_type -> clickhouse.CreateDatabaseRequest
+	4,  // 20: clickhouse.ClickHouseOperation.LogicalBackup:output_type -> clickhouse.BackupOperation
+	4,  // 21: clickhouse.ClickHouseOperation.Restore:output_type -> clickhouse.BackupOperation
+	4,  // 22: clickhouse.ClickHouseOperation.BackupStatus:output_type -> clickhouse.BackupOperation
+	16, // 23: clickhouse.ClickHouseOperation.Health:output_type -> clickhouse.HealthResponse
+	19, // 24: clickhouse.ClickHouseOperation.SetVariable:output_type -> common.SetVariableResponse
+	20, // 25: clickhouse.ClickHouseOperation.SetVariables:output_type -> common.SetVariablesResponse
+	21, // 26: clickhouse.ClickHouseOperation.CreateUser:output_type -> common.Empty
+	21, // 27: clickhouse.ClickHouseOperation.DropUser:output_type -> common.Empty
+	21, // 28: clickhouse.ClickHouseOperation.CreateDatabase:output_type -> common.Empty
+	20, // [20:29] is the sub-list for method output_type
+	11, // [11:20] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_pkg_agent_app_clickhouse_pb_clickhouse_proto_init() }
@@ -274,7 +1578,7 @@ func file_pkg_agent_app_clickhouse_pb_clickhouse_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDesc), len(file_pkg_agent_app_clickhouse_pb_clickhouse_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -20,18 +20,30 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	ClickHouseOperation_LogicalBackup_FullMethodName = "/clickhouse.ClickHouseOperation/LogicalBackup"
-	ClickHouseOperation_Restore_FullMethodName       = "/clickhouse.ClickHouseOperation/Restore"
-	ClickHouseOperation_SetVariable_FullMethodName   = "/clickhouse.ClickHouseOperation/SetVariable"
+	ClickHouseOperation_LogicalBackup_FullMethodName  = "/clickhouse.ClickHouseOperation/LogicalBackup"
+	ClickHouseOperation_Restore_FullMethodName        = "/clickhouse.ClickHouseOperation/Restore"
+	ClickHouseOperation_BackupStatus_FullMethodName   = "/clickhouse.ClickHouseOperation/BackupStatus"
+	ClickHouseOperation_Health_FullMethodName         = "/clickhouse.ClickHouseOperation/Health"
+	ClickHouseOperation_SetVariable_FullMethodName    = "/clickhouse.ClickHouseOperation/SetVariable"
+	ClickHouseOperation_SetVariables_FullMethodName   = "/clickhouse.ClickHouseOperation/SetVariables"
+	ClickHouseOperation_CreateUser_FullMethodName     = "/clickhouse.ClickHouseOperation/CreateUser"
+	ClickHouseOperation_DropUser_FullMethodName       = "/clickhouse.ClickHouseOperation/DropUser"
+	ClickHouseOperation_CreateDatabase_FullMethodName = "/clickhouse.ClickHouseOperation/CreateDatabase"
 )
 
 // ClickHouseOperationClient is the client API for ClickHouseOperation service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ClickHouseOperationClient interface {
-	LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*BackupOperation, error)
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*BackupOperation, error)
+	BackupStatus(ctx context.Context, in *BackupStatusRequest, opts ...grpc.CallOption) (*BackupOperation, error)
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error)
+	SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	DropUser(ctx context.Context, in *DropUserRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	CreateDatabase(ctx context.Context, in *CreateDatabaseRequest, opts ...grpc.CallOption) (*common.Empty, error)
 }
 
 type clickHouseOperationClient struct {
@@ -42,8 +54,8 @@ func NewClickHouseOperationClient(cc grpc.ClientConnInterface) ClickHouseOperati
 	return &clickHouseOperationClient{cc}
 }
 
-func (c *clickHouseOperationClient) LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *clickHouseOperationClient) LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*BackupOperation, error) {
+	out := new(BackupOperation)
 	err := c.cc.Invoke(ctx, ClickHouseOperation_LogicalBackup_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -51,8 +63,8 @@ func (c *clickHouseOperationClient) LogicalBackup(ctx context.Context, in *Logic
 	return out, nil
 }
 
-func (c *clickHouseOperationClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *clickHouseOperationClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*BackupOperation, error) {
+	out := new(BackupOperation)
 	err := c.cc.Invoke(ctx, ClickHouseOperation_Restore_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -60,8 +72,26 @@ func (c *clickHouseOperationClient) Restore(ctx context.Context, in *RestoreRequ
 	return out, nil
 }
 
-func (c *clickHouseOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *clickHouseOperationClient) BackupStatus(ctx context.Context, in *BackupStatusRequest, opts ...grpc.CallOption) (*BackupOperation, error) {
+	out := new(BackupOperation)
+	err := c.cc.Invoke(ctx, ClickHouseOperation_BackupStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clickHouseOperationClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, ClickHouseOperation_Health_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clickHouseOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error) {
+	out := new(common.SetVariableResponse)
 	err := c.cc.Invoke(ctx, ClickHouseOperation_SetVariable_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -69,13 +99,55 @@ func (c *clickHouseOperationClient) SetVariable(ctx context.Context, in *SetVari
 	return out, nil
 }
 
+func (c *clickHouseOperationClient) SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error) {
+	out := new(common.SetVariablesResponse)
+	err := c.cc.Invoke(ctx, ClickHouseOperation_SetVariables_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clickHouseOperationClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, ClickHouseOperation_CreateUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clickHouseOperationClient) DropUser(ctx context.Context, in *DropUserRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, ClickHouseOperation_DropUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clickHouseOperationClient) CreateDatabase(ctx context.Context, in *CreateDatabaseRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, ClickHouseOperation_CreateDatabase_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ClickHouseOperationServer is the server API for ClickHouseOperation service.
 // All implementations must embed UnimplementedClickHouseOperationServer
 // for forward compatibility
 type ClickHouseOperationServer interface {
-	LogicalBackup(context.Context, *LogicalBackupRequest) (*common.Empty, error)
-	Restore(context.Context, *RestoreRequest) (*common.Empty, error)
-	SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error)
+	LogicalBackup(context.Context, *LogicalBackupRequest) (*BackupOperation, error)
+	Restore(context.Context, *RestoreRequest) (*BackupOperation, error)
+	BackupStatus(context.Context, *BackupStatusRequest) (*BackupOperation, error)
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error)
+	SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error)
+	CreateUser(context.Context, *CreateUserRequest) (*common.Empty, error)
+	DropUser(context.Context, *DropUserRequest) (*common.Empty, error)
+	CreateDatabase(context.Context, *CreateDatabaseRequest) (*common.Empty, error)
 	mustEmbedUnimplementedClickHouseOperationServer()
 }
 
@@ -83,15 +155,33 @@ type ClickHouseOperationServer interface {
 type UnimplementedClickHouseOperationServer struct {
 }
 
-func (UnimplementedClickHouseOperationServer) LogicalBackup(context.Context, *LogicalBackupRequest) (*common.Empty, error) {
+func (UnimplementedClickHouseOperationServer) LogicalBackup(context.Context, *LogicalBackupRequest) (*BackupOperation, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LogicalBackup not implemented")
 }
-func (UnimplementedClickHouseOperationServer) Restore(context.Context, *RestoreRequest) (*common.Empty, error) {
+func (UnimplementedClickHouseOperationServer) Restore(context.Context, *RestoreRequest) (*BackupOperation, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
 }
-func (UnimplementedClickHouseOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error) {
+func (UnimplementedClickHouseOperationServer) BackupStatus(context.Context, *BackupStatusRequest) (*BackupOperation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BackupStatus not implemented")
+}
+func (UnimplementedClickHouseOperationServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedClickHouseOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetVariable not implemented")
 }
+func (UnimplementedClickHouseOperationServer) SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVariables not implemented")
+}
+func (UnimplementedClickHouseOperationServer) CreateUser(context.Context, *CreateUserRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedClickHouseOperationServer) DropUser(context.Context, *DropUserRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropUser not implemented")
+}
+func (UnimplementedClickHouseOperationServer) CreateDatabase(context.Context, *CreateDatabaseRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDatabase not implemented")
+}
 func (UnimplementedClickHouseOperationServer) mustEmbedUnimplementedClickHouseOperationServer() {}
 
 // UnsafeClickHouseOperationServer may be embedded to opt out of forward compatibility for this service.
@@ -141,6 +231,42 @@ func _ClickHouseOperation_Restore_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClickHouseOperation_BackupStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClickHouseOperationServer).BackupStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClickHouseOperation_BackupStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClickHouseOperationServer).BackupStatus(ctx, req.(*BackupStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClickHouseOperation_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClickHouseOperationServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClickHouseOperation_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClickHouseOperationServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ClickHouseOperation_SetVariable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetVariableRequest)
 	if err := dec(in); err != nil {
@@ -159,6 +285,78 @@ func _ClickHouseOperation_SetVariable_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClickHouseOperation_SetVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVariablesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClickHouseOperationServer).SetVariables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClickHouseOperation_SetVariables_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClickHouseOperationServer).SetVariables(ctx, req.(*SetVariablesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClickHouseOperation_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClickHouseOperationServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClickHouseOperation_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClickHouseOperationServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClickHouseOperation_DropUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClickHouseOperationServer).DropUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClickHouseOperation_DropUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClickHouseOperationServer).DropUser(ctx, req.(*DropUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClickHouseOperation_CreateDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDatabaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClickHouseOperationServer).CreateDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClickHouseOperation_CreateDatabase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClickHouseOperationServer).CreateDatabase(ctx, req.(*CreateDatabaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ClickHouseOperation_ServiceDesc is the grpc.ServiceDesc for ClickHouseOperation service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -174,10 +372,34 @@ var ClickHouseOperation_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Restore",
 			Handler:    _ClickHouseOperation_Restore_Handler,
 		},
+		{
+			MethodName: "BackupStatus",
+			Handler:    _ClickHouseOperation_BackupStatus_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _ClickHouseOperation_Health_Handler,
+		},
 		{
 			MethodName: "SetVariable",
 			Handler:    _ClickHouseOperation_SetVariable_Handler,
 		},
+		{
+			MethodName: "SetVariables",
+			Handler:    _ClickHouseOperation_SetVariables_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _ClickHouseOperation_CreateUser_Handler,
+		},
+		{
+			MethodName: "DropUser",
+			Handler:    _ClickHouseOperation_DropUser_Handler,
+		},
+		{
+			MethodName: "CreateDatabase",
+			Handler:    _ClickHouseOperation_CreateDatabase_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/agent/app/clickhouse/pb/clickhouse.proto",
@@ -0,0 +1,300 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMaxDelaySeconds    = 300
+	defaultMinFreeDiskPercent = 10
+
+	replicasHealthSQL = "SELECT database, table, is_readonly, is_session_expired, absolute_delay, queue_size, " +
+		"inserts_in_queue, merges_in_queue, log_max_index - log_pointer, active_replicas, total_replicas " +
+		"FROM system.replicas ORDER BY database, table FORMAT TabSeparated"
+	clustersHealthSQL = "SELECT cluster, shard_num, replica_num, host_name, port, is_local, errors_count " +
+		"FROM system.clusters ORDER BY cluster, shard_num, replica_num FORMAT TabSeparated"
+	keeperHealthSQL = "SELECT host, port, is_expired, session_uptime_elapsed_seconds " +
+		"FROM system.zookeeper_connection ORDER BY name = 'default' DESC LIMIT 1 FORMAT TabSeparated"
+	disksHealthSQL = "SELECT name, path, free_space, total_space FROM system.disks ORDER BY name FORMAT TabSeparated"
+)
+
+func (s *service) Health(ctx context.Context, req *HealthRequest) (*HealthResponse, error) {
+	util.LogRequestSafely(s.logger, "clickhouse health", map[string]interface{}{
+		"username":              req.GetUsername(),
+		"max_delay_seconds":     req.GetMaxDelaySeconds(),
+		"max_queue_size":        req.GetMaxQueueSize(),
+		"min_free_disk_percent": req.GetMinFreeDiskPercent(),
+	})
+
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if err := validateIdentifier(req.GetUsername()); err != nil {
+		s.logger.Errorw("invalid clickhouse username", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	password, err := util.DecryptPlainTextPassword(req.GetUsername())
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	conn := readClickHouseConnection()
+	outputs := make(map[string]string, 4)
+	for _, query := range []string{replicasHealthSQL, clustersHealthSQL, disksHealthSQL} {
+		if outputs[query], err = queryClickHouse(ctx, s.runner, conn, req.GetUsername(), password, query); err != nil {
+			s.logger.Errorw("failed to query health", zap.Error(err), zap.String("query", query))
+			return nil, err
+		}
+	}
+
+	resp := &HealthResponse{}
+	if resp.Replicas, err = parseReplicasOutput(outputs[replicasHealthSQL]); err != nil {
+		s.logger.Errorw("failed to parse system.replicas", zap.Error(err))
+		return nil, err
+	}
+	if resp.Clusters, err = parseClustersOutput(outputs[clustersHealthSQL]); err != nil {
+		s.logger.Errorw("failed to parse system.clusters", zap.Error(err))
+		return nil, err
+	}
+	if resp.Disks, err = parseDisksOutput(outputs[disksHealthSQL]); err != nil {
+		s.logger.Errorw("failed to parse system.disks", zap.Error(err))
+		return nil, err
+	}
+
+	// system.zookeeper_connection is missing before ClickHouse 23.3, the
+	// session is then reported unknown instead of failing the whole report
+	output, err := queryClickHouse(ctx, s.runner, conn, req.GetUsername(), password, keeperHealthSQL)
+	if err == nil {
+		resp.Keeper, err = parseKeeperOutput(output)
+	}
+	if err != nil {
+		s.logger.Warnw("failed to get keeper session", zap.Error(err))
+		resp.KeeperProblems = append(resp.KeeperProblems, fmt.Sprintf("keeper session unknown: %v", err))
+		resp.Keeper = &KeeperSession{}
+	}
+
+	evaluateHealth(resp, req)
+
+	s.logger.Infow("get health clickhouse successfully",
+		"replica_problems", len(resp.GetReplicaProblems()),
+		"keeper_problems", len(resp.GetKeeperProblems()),
+		"disk_problems", len(resp.GetDiskProblems()))
+	return resp, nil
+}
+
+// evaluateHealth records the problems of each area against the thresholds of the request.
+func evaluateHealth(resp *HealthResponse, req *HealthRequest) {
+	maxDelay := req.GetMaxDelaySeconds()
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxDelaySeconds
+	}
+	minFree := req.GetMinFreeDiskPercent()
+	if minFree <= 0 {
+		minFree = defaultMinFreeDiskPercent
+	}
+
+	var readonly, expired []string
+	for _, replica := range resp.GetReplicas() {
+		table := replica.GetDatabase() + "." + replica.GetTable()
+		switch {
+		case replica.GetIsSessionExpired():
+			expired = append(expired, table)
+		case replica.GetIsReadonly():
+			readonly = append(readonly, table)
+		}
+
+		if replica.GetAbsoluteDelay() > maxDelay {
+			resp.ReplicaProblems = append(resp.ReplicaProblems, fmt.Sprintf("%s is %ds behind", table, replica.GetAbsoluteDelay()))
+		}
+		if req.GetMaxQueueSize() > 0 && replica.GetQueueSize() > req.GetMaxQueueSize() {
+			resp.ReplicaProblems = append(resp.ReplicaProblems, fmt.Sprintf("%s has %d queued entries", table, replica.GetQueueSize()))
+		}
+	}
+	if len(readonly) > 0 {
+		resp.ReplicaProblems = append(resp.ReplicaProblems, fmt.Sprintf("readonly replicas [%s]", strings.Join(readonly, ",")))
+	}
+	if len(expired) > 0 {
+		resp.ReplicaProblems = append(resp.ReplicaProblems, fmt.Sprintf("replicas with an expired keeper session [%s]", strings.Join(expired, ",")))
+	}
+	sort.Strings(resp.ReplicaProblems)
+
+	keeper := resp.GetKeeper()
+	switch {
+	case keeper.GetIsExpired():
+		resp.KeeperProblems = append(resp.KeeperProblems, fmt.Sprintf("keeper session to %s:%d expired", keeper.GetHost(), keeper.GetPort()))
+	case !keeper.GetConfigured() && len(resp.GetReplicas()) > 0 && len(resp.GetKeeperProblems()) == 0:
+		resp.KeeperProblems = append(resp.KeeperProblems, "replicated tables exist but no keeper session is open")
+	}
+
+	for _, disk := range resp.GetDisks() {
+		if disk.GetTotalSpace() <= 0 {
+			continue
+		}
+
+		free := float64(disk.GetFreeSpace()) * 100 / float64(disk.GetTotalSpace())
+		if free < float64(minFree) {
+			resp.DiskProblems = append(resp.DiskProblems, fmt.Sprintf("disk %s has %.1f%% free space", disk.GetName(), free))
+		}
+	}
+}
+
+func parseReplicasOutput(output string) ([]*ReplicaStatus, error) {
+	rows, err := parseTabSeparated(output, 11)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make([]*ReplicaStatus, 0, len(rows))
+	for _, row := range rows {
+		numbers, err := parseTabSeparatedInts(row[4:])
+		if err != nil {
+			return nil, err
+		}
+
+		replicas = append(replicas, &ReplicaStatus{
+			Database:         row[0],
+			Table:            row[1],
+			IsReadonly:       parseTabSeparatedBool(row[2]),
+			IsSessionExpired: parseTabSeparatedBool(row[3]),
+			AbsoluteDelay:    numbers[0],
+			QueueSize:        numbers[1],
+			InsertsInQueue:   numbers[2],
+			MergesInQueue:    numbers[3],
+			LogLag:           numbers[4],
+			ActiveReplicas:   numbers[5],
+			TotalReplicas:    numbers[6],
+		})
+	}
+
+	return replicas, nil
+}
+
+func parseClustersOutput(output string) ([]*ClusterReplica, error) {
+	rows, err := parseTabSeparated(output, 7)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]*ClusterReplica, 0, len(rows))
+	for _, row := range rows {
+		numbers, err := parseTabSeparatedInts([]string{row[1], row[2], row[4], row[6]})
+		if err != nil {
+			return nil, err
+		}
+
+		clusters = append(clusters, &ClusterReplica{
+			Cluster:     row[0],
+			ShardNum:    numbers[0],
+			ReplicaNum:  numbers[1],
+			HostName:    row[3],
+			Port:        numbers[2],
+			IsLocal:     parseTabSeparatedBool(row[5]),
+			ErrorsCount: numbers[3],
+		})
+	}
+
+	return clusters, nil
+}
+
+func parseKeeperOutput(output string) (*KeeperSession, error) {
+	rows, err := parseTabSeparated(output, 4)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &KeeperSession{}, nil
+	}
+
+	numbers, err := parseTabSeparatedInts([]string{rows[0][1], rows[0][3]})
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeeperSession{
+		Configured:           true,
+		Host:                 rows[0][0],
+		Port:                 numbers[0],
+		IsExpired:            parseTabSeparatedBool(rows[0][2]),
+		SessionUptimeSeconds: numbers[1],
+	}, nil
+}
+
+func parseDisksOutput(output string) ([]*DiskUsage, error) {
+	rows, err := parseTabSeparated(output, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	disks := make([]*DiskUsage, 0, len(rows))
+	for _, row := range rows {
+		numbers, err := parseTabSeparatedInts(row[2:])
+		if err != nil {
+			return nil, err
+		}
+
+		disks = append(disks, &DiskUsage{Name: row[0], Path: row[1], FreeSpace: numbers[0], TotalSpace: numbers[1]})
+	}
+
+	return disks, nil
+}
+
+// parseTabSeparated splits TabSeparated output into rows of the given number of columns
+func parseTabSeparated(output string, columns int) ([][]string, error) {
+	unescape := strings.NewReplacer(`\t`, "\t", `\n`, "\n", `\\`, `\`)
+
+	var rows [][]string
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != columns {
+			return nil, fmt.Errorf("unexpected row %q, want %d columns", line, columns)
+		}
+		for i := range fields {
+			fields[i] = unescape.Replace(fields[i])
+		}
+		rows = append(rows, fields)
+	}
+
+	return rows, nil
+}
+
+// parseTabSeparatedInts parses integer columns, UInt64 values beyond int64 are capped
+func parseTabSeparatedInts(fields []string) ([]int64, error) {
+	numbers := make([]int64, 0, len(fields))
+	for _, field := range fields {
+		n, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			u, uerr := strconv.ParseUint(field, 10, 64)
+			if uerr != nil {
+				return nil, fmt.Errorf("invalid integer %q", field)
+			}
+			n = math.MaxInt64
+			if u < math.MaxInt64 {
+				n = int64(u)
+			}
+		}
+		numbers = append(numbers, n)
+	}
+
+	return numbers, nil
+}
+
+// parseTabSeparatedBool parses UInt8 and Bool columns
+func parseTabSeparatedBool(field string) bool {
+	return field == "1" || field == "true"
+}
@@ -0,0 +1,78 @@
+package clickhouse
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHealthReportsProblems(t *testing.T) {
+	writeEncryptedPassword(t, "admin", "secret")
+
+	runner := &sequenceCommandRunner{outputs: []string{
+		"sales\torders\t0\t0\t12\t3\t2\t1\t3\t2\t2\n" +
+			"sales\titems\t1\t0\t900\t250\t0\t250\t400\t1\t2\n",
+		"default\t1\t1\tch-0\t9000\t1\t0\n" +
+			"default\t1\t2\tch-1\t9000\t0\t4\n",
+		"default\t/var/lib/clickhouse/\t5368709120\t107374182400\n" +
+			"s3\t/var/lib/clickhouse/disks/s3/\t18446744073709551615\t0\n",
+		"keeper-0.keeper\t9181\t1\t3600\n",
+	}}
+	s := &service{logger: zap.NewNop().Sugar(), slm: &fakeSLM{}, runner: runner}
+
+	resp, err := s.Health(context.Background(), &HealthRequest{Username: "admin", MaxQueueSize: 100})
+	require.NoError(t, err)
+	require.Equal(t, []string{replicasHealthSQL, clustersHealthSQL, disksHealthSQL, keeperHealthSQL}, runner.queries)
+
+	require.Len(t, resp.GetReplicas(), 2)
+	require.Equal(t, &ReplicaStatus{
+		Database: "sales", Table: "items", IsReadonly: true, AbsoluteDelay: 900, QueueSize: 250,
+		MergesInQueue: 250, LogLag: 400, ActiveReplicas: 1, TotalReplicas: 2,
+	}, resp.GetReplicas()[1])
+	require.Len(t, resp.GetClusters(), 2)
+	require.Equal(t, int64(4), resp.GetClusters()[1].GetErrorsCount())
+	require.Equal(t, &KeeperSession{Configured: true, Host: "keeper-0.keeper", Port: 9181, IsExpired: true, SessionUptimeSeconds: 3600}, resp.GetKeeper())
+	require.Equal(t, int64(9223372036854775807), resp.GetDisks()[1].GetFreeSpace())
+
+	require.Equal(t, []string{
+		"readonly replicas [sales.items]",
+		"sales.items has 250 queued entries",
+		"sales.items is 900s behind",
+	}, resp.GetReplicaProblems())
+	require.Equal(t, []string{"keeper session to keeper-0.keeper:9181 expired"}, resp.GetKeeperProblems())
+	require.Equal(t, []string{"disk default has 5.0% free space"}, resp.GetDiskProblems())
+}
+
+func TestHealthWithoutKeeper(t *testing.T) {
+	writeEncryptedPassword(t, "admin", "secret")
+
+	runner := &sequenceCommandRunner{outputs: []string{"", "", "default\t/var/lib/clickhouse/\t50\t100\n", ""}}
+	s := &service{logger: zap.NewNop().Sugar(), slm: &fakeSLM{}, runner: runner}
+
+	resp, err := s.Health(context.Background(), &HealthRequest{Username: "admin"})
+	require.NoError(t, err)
+	require.False(t, resp.GetKeeper().GetConfigured())
+	require.Empty(t, resp.GetReplicaProblems())
+	require.Empty(t, resp.GetKeeperProblems())
+	require.Empty(t, resp.GetDiskProblems())
+
+	// replicated tables need a keeper session
+	evaluateHealth(resp, &HealthRequest{})
+	resp.Replicas = []*ReplicaStatus{{Database: "sales", Table: "orders"}}
+	evaluateHealth(resp, &HealthRequest{})
+	require.Equal(t, []string{"replicated tables exist but no keeper session is open"}, resp.GetKeeperProblems())
+}
+
+func TestParseTabSeparated(t *testing.T) {
+	rows, err := parseTabSeparated("a\\tb\tc\\\\d\n\n", 2)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"a\tb", `c\d`}}, rows)
+
+	_, err = parseTabSeparated("a\tb\tc\n", 2)
+	require.Error(t, err)
+
+	_, err = parseDisksOutput("default\t/\tmany\t100\n")
+	require.Error(t, err)
+}
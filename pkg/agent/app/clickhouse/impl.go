@@ -1,6 +1,7 @@
 package clickhouse
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/upmio/unit-operator/pkg/agent/app"
 	"github.com/upmio/unit-operator/pkg/agent/app/common"
 	"github.com/upmio/unit-operator/pkg/agent/app/slm"
@@ -28,6 +30,7 @@ var (
 	svr = &service{}
 
 	identifierRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+	privilegeRE  = regexp.MustCompile(`^[A-Z]+( [A-Z]+)*$`)
 )
 
 type commandRunner interface {
@@ -67,10 +70,17 @@ func (s *service) Registry(server *grpc.Server) {
 	RegisterClickHouseOperationServer(server, svr)
 }
 
-func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest) (*common.Empty, error) {
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterClickHouseOperationHandler(ctx, mux, conn)
+}
+
+func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest) (*BackupOperation, error) {
 	util.LogRequestSafely(s.logger, "clickhouse logical backup", map[string]interface{}{
 		"username":    req.GetUsername(),
 		"backup_file": req.GetBackupFile(),
+		"base_backup": req.GetBaseBackup(),
+		"targets":     req.GetTargets(),
+		"async":       req.GetAsync(),
 		"bucket":      req.GetObjectStorage().GetBucket(),
 		"endpoint":    req.GetObjectStorage().GetEndpoint(),
 		"access_key":  req.GetObjectStorage().GetAccessKey(),
@@ -87,7 +97,7 @@ func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest)
 		return nil, err
 	}
 
-	query, err := buildBackupSQL(req.GetObjectStorage(), req.GetBackupFile())
+	query, err := buildBackupSQL(req)
 	if err != nil {
 		s.logger.Errorw("failed to build backup query", zap.Error(err))
 		return nil, err
@@ -99,23 +109,27 @@ func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest)
 		return nil, err
 	}
 
-	if err := runClickHouseQuery(ctx, s.runner, readClickHouseConnection(), req.GetUsername(), password, query); err != nil {
+	op, err := s.runBackupOperation(ctx, readClickHouseConnection(), req.GetUsername(), password, query)
+	if err != nil {
 		s.logger.Errorw("failed to execute backup", zap.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("logical backup clickhouse successfully")
-	return nil, nil
+	s.logger.Infow("logical backup clickhouse successfully", "id", op.GetId(), "status", op.GetStatus())
+	return op, nil
 }
 
-func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Empty, error) {
+func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*BackupOperation, error) {
 	util.LogRequestSafely(s.logger, "clickhouse restore", map[string]interface{}{
-		"username":    req.GetUsername(),
-		"backup_file": req.GetBackupFile(),
-		"bucket":      req.GetObjectStorage().GetBucket(),
-		"endpoint":    req.GetObjectStorage().GetEndpoint(),
-		"access_key":  req.GetObjectStorage().GetAccessKey(),
-		"secret_key":  req.GetObjectStorage().GetSecretKey(),
+		"username":               req.GetUsername(),
+		"backup_file":            req.GetBackupFile(),
+		"targets":                req.GetTargets(),
+		"allow_non_empty_tables": req.GetAllowNonEmptyTables(),
+		"async":                  req.GetAsync(),
+		"bucket":                 req.GetObjectStorage().GetBucket(),
+		"endpoint":               req.GetObjectStorage().GetEndpoint(),
+		"access_key":             req.GetObjectStorage().GetAccessKey(),
+		"secret_key":             req.GetObjectStorage().GetSecretKey(),
 	})
 
 	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
@@ -128,7 +142,7 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 		return nil, err
 	}
 
-	query, err := buildRestoreSQL(req.GetObjectStorage(), req.GetBackupFile())
+	query, err := buildRestoreSQL(req)
 	if err != nil {
 		s.logger.Errorw("failed to build restore query", zap.Error(err))
 		return nil, err
@@ -140,16 +154,17 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 		return nil, err
 	}
 
-	if err := runClickHouseQuery(ctx, s.runner, readClickHouseConnection(), req.GetUsername(), password, query); err != nil {
+	op, err := s.runBackupOperation(ctx, readClickHouseConnection(), req.GetUsername(), password, query)
+	if err != nil {
 		s.logger.Errorw("failed to execute restore", zap.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("restore clickhouse successfully")
-	return nil, nil
+	s.logger.Infow("restore clickhouse successfully", "id", op.GetId(), "status", op.GetStatus())
+	return op, nil
 }
 
-func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.Empty, error) {
+func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.SetVariableResponse, error) {
 	util.LogRequestSafely(s.logger, "clickhouse set variable", map[string]interface{}{
 		"username": req.GetUsername(),
 		"key":      req.GetKey(),
@@ -178,12 +193,221 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 		return nil, err
 	}
 
-	if err := runClickHouseQuery(ctx, s.runner, readClickHouseConnection(), req.GetUsername(), password, query); err != nil {
+	conn := readClickHouseConnection()
+	previous, err := s.getSetting(ctx, conn, req.GetUsername(), password, req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runClickHouseQuery(ctx, s.runner, conn, req.GetUsername(), password, query); err != nil {
 		s.logger.Errorw("failed to execute set variable", zap.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("set variable clickhouse successfully")
+	// ALTER USER stores the setting in the access storage, new sessions of the user use it
+	current, err := s.getSetting(ctx, conn, req.GetUsername(), password, req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := common.NewSetVariableResponse(previous, current)
+	resp.Persisted = true
+
+	s.logger.Infow("set variable clickhouse successfully", "changed", resp.GetChanged())
+	return resp, nil
+}
+
+// getSetting reads a setting as seen by a new session of the user.
+func (s *service) getSetting(ctx context.Context, conn clickHouseConnection, username, password, key string) (string, error) {
+	output, err := queryClickHouse(ctx, s.runner, conn, username, password, buildGetSettingsSQL([]string{key}))
+	if err != nil {
+		s.logger.Errorw("failed to get setting", zap.Error(err), zap.String("key", key))
+		return "", err
+	}
+
+	value, ok := parseSettingsOutput(output)[key]
+	if !ok {
+		err := fmt.Errorf("unknown setting %q", key)
+		s.logger.Errorw("failed to get setting", zap.Error(err))
+		return "", err
+	}
+
+	return value, nil
+}
+
+func (s *service) SetVariables(ctx context.Context, req *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	util.LogRequestSafely(s.logger, "clickhouse set variables", map[string]interface{}{
+		"username":  req.GetUsername(),
+		"variables": req.GetVariables(),
+		"dry_run":   req.GetDryRun(),
+	})
+
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	keys := common.SortedVariableKeys(req.GetVariables())
+	query, err := buildSetVariablesSQL(req.GetUsername(), keys, req.GetVariables())
+	if err != nil {
+		s.logger.Errorw("failed to build set variables query", zap.Error(err))
+		return nil, err
+	}
+
+	password, err := util.DecryptPlainTextPassword(req.GetUsername())
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	// Read the current values, unknown settings fail the whole batch
+	conn := readClickHouseConnection()
+	output, err := queryClickHouse(ctx, s.runner, conn, req.GetUsername(), password, buildGetSettingsSQL(keys))
+	if err != nil {
+		s.logger.Errorw("failed to get settings", zap.Error(err))
+		return nil, err
+	}
+	current := parseSettingsOutput(output)
+
+	resp := &common.SetVariablesResponse{DryRun: req.GetDryRun()}
+	for _, key := range keys {
+		value, ok := current[key]
+		result := common.NewVariableResult(key, value, req.GetVariables()[key])
+		if !ok {
+			result.Error = "unknown setting"
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if req.GetDryRun() || resp.Failed() {
+		return resp, nil
+	}
+
+	// One ALTER USER statement applies every setting of the batch
+	if err := runClickHouseQuery(ctx, s.runner, conn, req.GetUsername(), password, query); err != nil {
+		s.logger.Errorw("failed to execute set variables", zap.Error(err))
+		return nil, err
+	}
+	resp.MarkApplied()
+	resp.Persisted = true
+
+	s.logger.Info("set variables clickhouse successfully")
+	return resp, nil
+}
+
+func (s *service) CreateUser(ctx context.Context, req *CreateUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "clickhouse create user", map[string]interface{}{
+		"username":   req.GetUsername(),
+		"user":       req.GetUser(),
+		"privileges": req.GetPrivileges(),
+		"revokes":    req.GetRevokes(),
+	})
+
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if err := validateIdentifier(req.GetUsername()); err != nil {
+		s.logger.Errorw("invalid clickhouse username", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	queries, err := buildCreateUserSQL(req.GetUser(), req.GetPassword(), req.GetPrivileges(), req.GetRevokes())
+	if err != nil {
+		s.logger.Errorw("failed to build create user query", zap.Error(err))
+		return nil, err
+	}
+
+	password, err := util.DecryptPlainTextPassword(req.GetUsername())
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	conn := readClickHouseConnection()
+	for _, query := range queries {
+		if err := runClickHouseQuery(ctx, s.runner, conn, req.GetUsername(), password, query); err != nil {
+			s.logger.Errorw("failed to execute create user", zap.Error(err), zap.String("user", req.GetUser()))
+			return nil, err
+		}
+	}
+
+	s.logger.Info("create user clickhouse successfully")
+	return nil, nil
+}
+
+func (s *service) DropUser(ctx context.Context, req *DropUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "clickhouse drop user", map[string]interface{}{
+		"username": req.GetUsername(),
+		"user":     req.GetUser(),
+	})
+
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if err := validateIdentifier(req.GetUsername()); err != nil {
+		s.logger.Errorw("invalid clickhouse username", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	if err := validateIdentifier(req.GetUser()); err != nil {
+		s.logger.Errorw("invalid clickhouse user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	password, err := util.DecryptPlainTextPassword(req.GetUsername())
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	if err := runClickHouseQuery(ctx, s.runner, readClickHouseConnection(), req.GetUsername(), password, "DROP USER IF EXISTS "+req.GetUser()); err != nil {
+		s.logger.Errorw("failed to execute drop user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	s.logger.Info("drop user clickhouse successfully")
+	return nil, nil
+}
+
+func (s *service) CreateDatabase(ctx context.Context, req *CreateDatabaseRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "clickhouse create database", map[string]interface{}{
+		"username": req.GetUsername(),
+		"database": req.GetDatabase(),
+		"engine":   req.GetEngine(),
+	})
+
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if err := validateIdentifier(req.GetUsername()); err != nil {
+		s.logger.Errorw("invalid clickhouse username", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	query, err := buildCreateDatabaseSQL(req.GetDatabase(), req.GetEngine())
+	if err != nil {
+		s.logger.Errorw("failed to build create database query", zap.Error(err))
+		return nil, err
+	}
+
+	password, err := util.DecryptPlainTextPassword(req.GetUsername())
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	if err := runClickHouseQuery(ctx, s.runner, readClickHouseConnection(), req.GetUsername(), password, query); err != nil {
+		s.logger.Errorw("failed to execute create database", zap.Error(err), zap.String("database", req.GetDatabase()))
+		return nil, err
+	}
+
+	s.logger.Info("create database clickhouse successfully")
 	return nil, nil
 }
 
@@ -205,26 +429,68 @@ func readClickHouseConnection() clickHouseConnection {
 	}
 }
 
-func buildBackupSQL(objectStorage *common.ObjectStorage, backupFile string) (string, error) {
-	s3URL, err := buildS3URL(objectStorage, backupFile)
+// buildBackupSQL builds the BACKUP query, an incremental backup refers to
+// its base backup in the same bucket.
+//
+// BACKUP TABLE db.t, DATABASE db2 TO S3('<url>', '<ak>', '<sk>')
+// SETTINGS base_backup = S3('<base url>', '<ak>', '<sk>') ASYNC
+func buildBackupSQL(req *LogicalBackupRequest) (string, error) {
+	destination, err := buildS3Destination(req.GetObjectStorage(), req.GetBackupFile())
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("BACKUP ALL TO S3(%s, %s, %s)",
-		quoteSQLString(s3URL),
-		quoteSQLString(objectStorage.GetAccessKey()),
-		quoteSQLString(objectStorage.GetSecretKey()),
-	), nil
+	targets, err := buildBackupTargets(req.GetTargets())
+	if err != nil {
+		return "", err
+	}
+
+	var settings []string
+	if req.GetBaseBackup() != "" {
+		if req.GetBaseBackup() == req.GetBackupFile() {
+			return "", fmt.Errorf("base_backup must differ from backup_file")
+		}
+		base, err := buildS3Destination(req.GetObjectStorage(), req.GetBaseBackup())
+		if err != nil {
+			return "", err
+		}
+		settings = append(settings, "base_backup = "+base)
+	}
+
+	return fmt.Sprintf("BACKUP %s TO %s%s", targets, destination, backupQuerySuffix(settings, req.GetAsync())), nil
 }
 
-func buildRestoreSQL(objectStorage *common.ObjectStorage, backupFile string) (string, error) {
+// buildRestoreSQL builds the RESTORE query, the base backups of an
+// incremental backup are found from its metadata.
+//
+// RESTORE TABLE db.t AS db.t_restored FROM S3('<url>', '<ak>', '<sk>')
+// SETTINGS allow_non_empty_tables = true ASYNC
+func buildRestoreSQL(req *RestoreRequest) (string, error) {
+	source, err := buildS3Destination(req.GetObjectStorage(), req.GetBackupFile())
+	if err != nil {
+		return "", err
+	}
+
+	targets, err := buildBackupTargets(req.GetTargets())
+	if err != nil {
+		return "", err
+	}
+
+	var settings []string
+	if req.GetAllowNonEmptyTables() {
+		settings = append(settings, "allow_non_empty_tables = true")
+	}
+
+	return fmt.Sprintf("RESTORE %s FROM %s%s", targets, source, backupQuerySuffix(settings, req.GetAsync())), nil
+}
+
+func buildS3Destination(objectStorage *common.ObjectStorage, backupFile string) (string, error) {
 	s3URL, err := buildS3URL(objectStorage, backupFile)
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("RESTORE ALL FROM S3(%s, %s, %s)",
+	return fmt.Sprintf("S3(%s, %s, %s)",
 		quoteSQLString(s3URL),
 		quoteSQLString(objectStorage.GetAccessKey()),
 		quoteSQLString(objectStorage.GetSecretKey()),
@@ -302,6 +568,148 @@ func buildSetVariableSQL(username, key, value string) (string, error) {
 	return fmt.Sprintf("ALTER USER %s SETTINGS %s = %s", username, key, quoteSQLString(value)), nil
 }
 
+func buildSetVariablesSQL(username string, keys []string, variables map[string]string) (string, error) {
+	if err := validateIdentifier(username); err != nil {
+		return "", err
+	}
+	if len(keys) == 0 {
+		return "", fmt.Errorf("variables is required")
+	}
+
+	assignments := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if err := validateIdentifier(key); err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(variables[key]) == "" {
+			return "", fmt.Errorf("value of %s is required", key)
+		}
+		assignments = append(assignments, fmt.Sprintf("%s = %s", key, quoteSQLString(variables[key])))
+	}
+
+	return fmt.Sprintf("ALTER USER %s SETTINGS %s", username, strings.Join(assignments, ", ")), nil
+}
+
+// buildGetSettingsSQL reads the current value of the given settings, the keys must be validated
+func buildGetSettingsSQL(keys []string) string {
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, quoteSQLString(key))
+	}
+
+	return fmt.Sprintf("SELECT name, value FROM system.settings WHERE name IN (%s) FORMAT TabSeparated", strings.Join(names, ", "))
+}
+
+// parseSettingsOutput parses the TabSeparated name and value rows of system.settings
+func parseSettingsOutput(output string) map[string]string {
+	unescape := strings.NewReplacer(`\t`, "\t", `\n`, "\n", `\\`, `\`)
+
+	settings := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		name, value, found := strings.Cut(line, "\t")
+		if !found {
+			continue
+		}
+		settings[name] = unescape.Replace(value)
+	}
+
+	return settings
+}
+
+// buildCreateUserSQL builds the statements which create the user, reset its
+// password, revoke the removed privileges and grant the privileges.
+// clickhouse-client runs one query per call.
+func buildCreateUserSQL(user, password string, privileges, revokes []*Privilege) ([]string, error) {
+	if err := validateIdentifier(user); err != nil {
+		return nil, err
+	}
+	if password == "" {
+		return nil, fmt.Errorf("password is required")
+	}
+
+	queries := []string{
+		fmt.Sprintf("CREATE USER IF NOT EXISTS %s IDENTIFIED WITH sha256_password BY %s", user, quoteSQLString(password)),
+		fmt.Sprintf("ALTER USER %s IDENTIFIED WITH sha256_password BY %s", user, quoteSQLString(password)),
+	}
+
+	// Privileges removed from the user are revoked before the grants, which may overlap them
+	for _, privilege := range revokes {
+		list, object, err := privilegeObject(privilege)
+		if err != nil {
+			return nil, err
+		}
+
+		queries = append(queries, fmt.Sprintf("REVOKE %s ON %s FROM %s", list, object, user))
+	}
+
+	for _, privilege := range privileges {
+		list, object, err := privilegeObject(privilege)
+		if err != nil {
+			return nil, err
+		}
+
+		queries = append(queries, fmt.Sprintf("GRANT %s ON %s TO %s", list, object, user))
+	}
+
+	return queries, nil
+}
+
+// privilegeObject validates the privilege and returns its privilege list and object.
+func privilegeObject(privilege *Privilege) (string, string, error) {
+	if len(privilege.GetPrivileges()) == 0 {
+		return "", "", fmt.Errorf("privileges of database %q are required", privilege.GetDatabase())
+	}
+
+	privileges := make([]string, 0, len(privilege.GetPrivileges()))
+	for _, p := range privilege.GetPrivileges() {
+		p = strings.ToUpper(strings.TrimSpace(p))
+		if !privilegeRE.MatchString(p) {
+			return "", "", fmt.Errorf("invalid privilege %q", p)
+		}
+		privileges = append(privileges, p)
+	}
+
+	database, err := grantObjectName(privilege.GetDatabase())
+	if err != nil {
+		return "", "", err
+	}
+	table, err := grantObjectName(privilege.GetTable())
+	if err != nil {
+		return "", "", err
+	}
+
+	return strings.Join(privileges, ", "), database + "." + table, nil
+}
+
+func buildCreateDatabaseSQL(database, engine string) (string, error) {
+	if err := validateIdentifier(database); err != nil {
+		return "", err
+	}
+
+	query := "CREATE DATABASE IF NOT EXISTS " + database
+	if engine != "" {
+		if err := validateIdentifier(engine); err != nil {
+			return "", err
+		}
+		query += " ENGINE = " + engine
+	}
+
+	return query, nil
+}
+
+// grantObjectName validates a database or table name, empty or "*" means all objects
+func grantObjectName(name string) (string, error) {
+	if name == "" || name == "*" {
+		return "*", nil
+	}
+
+	if err := validateIdentifier(name); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
 func validateIdentifier(value string) error {
 	if !identifierRE.MatchString(value) {
 		return fmt.Errorf("invalid identifier %q", value)
@@ -317,6 +725,23 @@ func quoteSQLString(value string) string {
 }
 
 func runClickHouseQuery(ctx context.Context, runner commandRunner, conn clickHouseConnection, username, password, query string) error {
+	return runner.ExecuteCommand(newClickHouseCommand(ctx, conn, username, password, query), "clickhouse")
+}
+
+// queryClickHouse runs a query and returns its output, only used for results
+// that carry no secrets.
+func queryClickHouse(ctx context.Context, runner commandRunner, conn clickHouseConnection, username, password, query string) (string, error) {
+	var out bytes.Buffer
+	cmd := newClickHouseCommand(ctx, conn, username, password, query)
+	cmd.Stdout = &out
+	if err := runner.ExecuteCommand(cmd, "clickhouse"); err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
+
+func newClickHouseCommand(ctx context.Context, conn clickHouseConnection, username, password, query string) *exec.Cmd {
 	args := []string{
 		"--host", conn.host,
 		"--port", conn.port,
@@ -329,7 +754,7 @@ func runClickHouseQuery(ctx context.Context, runner commandRunner, conn clickHou
 	cmd := exec.CommandContext(ctx, "clickhouse-client", args...)
 	cmd.Env = append(cmd.Environ(), "CLICKHOUSE_PASSWORD="+password)
 	cmd.Stdin = strings.NewReader(query)
-	return runner.ExecuteCommand(cmd, "clickhouse")
+	return cmd
 }
 
 type safeCommandRunner struct {
@@ -337,7 +762,9 @@ type safeCommandRunner struct {
 }
 
 func (r *safeCommandRunner) ExecuteCommand(cmd *exec.Cmd, _ string) error {
-	cmd.Stdout = io.Discard
+	if cmd.Stdout == nil {
+		cmd.Stdout = io.Discard
+	}
 	cmd.Stderr = io.Discard
 
 	if r.logger != nil {
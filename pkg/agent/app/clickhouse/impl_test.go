@@ -24,10 +24,14 @@ const (
 )
 
 type fakeCommandRunner struct {
-	err   error
-	args  []string
-	env   []string
-	stdin string
+	err    error
+	output string
+	// outputs are written by the successive commands, before falling back to output
+	outputs []string
+	args    []string
+	env     []string
+	stdin   string
+	queries []string
 }
 
 func (f *fakeCommandRunner) ExecuteCommand(cmd *exec.Cmd, _ string) error {
@@ -39,6 +43,16 @@ func (f *fakeCommandRunner) ExecuteCommand(cmd *exec.Cmd, _ string) error {
 			return err
 		}
 		f.stdin = string(stdin)
+		f.queries = append(f.queries, f.stdin)
+	}
+	output := f.output
+	if len(f.outputs) > 0 {
+		output, f.outputs = f.outputs[0], f.outputs[1:]
+	}
+	if cmd.Stdout != nil && output != "" {
+		if _, err := io.WriteString(cmd.Stdout, output); err != nil {
+			return err
+		}
 	}
 	return f.err
 }
@@ -205,11 +219,11 @@ func TestBuildBackupAndRestoreSQL(t *testing.T) {
 		SecretKey: "sk",
 	}
 
-	backupSQL, err := buildBackupSQL(objectStorage, "backup-001")
+	backupSQL, err := buildBackupSQL(&LogicalBackupRequest{ObjectStorage: objectStorage, BackupFile: "backup-001"})
 	require.NoError(t, err)
 	require.Equal(t, expectedBackupSQL, backupSQL)
 
-	restoreSQL, err := buildRestoreSQL(objectStorage, "backup-001")
+	restoreSQL, err := buildRestoreSQL(&RestoreRequest{ObjectStorage: objectStorage, BackupFile: "backup-001"})
 	require.NoError(t, err)
 	require.Equal(t, expectedRestoreSQL, restoreSQL)
 }
@@ -239,6 +253,28 @@ func TestBuildSetVariableSQLRequiresValue(t *testing.T) {
 	require.EqualError(t, err, "value is required")
 }
 
+func TestBuildSetVariablesSQL(t *testing.T) {
+	query, err := buildSetVariablesSQL("admin", []string{"max_memory_usage", "max_threads"}, map[string]string{
+		"max_threads":      "8",
+		"max_memory_usage": "10000000000",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "ALTER USER admin SETTINGS max_memory_usage = '10000000000', max_threads = '8'", query)
+
+	_, err = buildSetVariablesSQL("admin", []string{"max_threads;"}, map[string]string{"max_threads;": "8"})
+	require.Error(t, err)
+
+	_, err = buildSetVariablesSQL("admin", nil, nil)
+	require.Error(t, err)
+}
+
+func TestParseSettingsOutput(t *testing.T) {
+	settings := parseSettingsOutput("max_threads\t8\nformat_csv_delimiter\t\\t\n")
+
+	require.Equal(t, map[string]string{"max_threads": "8", "format_csv_delimiter": "\t"}, settings)
+}
+
 func TestRunClickHouseQueryPassesConnectionAndQuery(t *testing.T) {
 	runner := &fakeCommandRunner{}
 	conn := clickHouseConnection{host: "127.0.0.1", port: "9440", secure: true}
@@ -344,7 +380,7 @@ func TestSetVariableChecksSLMDecryptsPasswordAndRunsSafeCommand(t *testing.T) {
 	t.Setenv(clickHouseSecureEnvKey, "true")
 	writeEncryptedPassword(t, "admin", "secret")
 
-	runner := &fakeCommandRunner{}
+	runner := &fakeCommandRunner{outputs: []string{"max_threads\t4\n", "", "max_threads\t8\n"}}
 	lifecycle := &fakeSLM{}
 	s := &service{
 		logger: zap.NewNop().Sugar(),
@@ -352,7 +388,7 @@ func TestSetVariableChecksSLMDecryptsPasswordAndRunsSafeCommand(t *testing.T) {
 		runner: runner,
 	}
 
-	_, err := s.SetVariable(context.Background(), &SetVariableRequest{
+	resp, err := s.SetVariable(context.Background(), &SetVariableRequest{
 		Username: "admin",
 		Key:      "max_threads",
 		Value:    "8",
@@ -361,7 +397,92 @@ func TestSetVariableChecksSLMDecryptsPasswordAndRunsSafeCommand(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 1, lifecycle.checked)
 	requireSafeClickHouseCommand(t, runner, []string{"--secure"})
-	require.Equal(t, "ALTER USER admin SETTINGS max_threads = '8'", runner.stdin)
+	require.Len(t, runner.queries, 3)
+	require.Equal(t, "ALTER USER admin SETTINGS max_threads = '8'", runner.queries[1])
+	require.Equal(t, "4", resp.GetPreviousValue())
+	require.Equal(t, "8", resp.GetCurrentValue())
+	require.True(t, resp.GetChanged())
+	require.True(t, resp.GetPersisted())
+}
+
+func TestSetVariableRejectsUnknownSetting(t *testing.T) {
+	t.Setenv(clickHouseHostEnvKey, "")
+	t.Setenv(clickHousePortEnvKey, "9440")
+	t.Setenv(clickHouseSecureEnvKey, "true")
+	writeEncryptedPassword(t, "admin", "secret")
+
+	runner := &fakeCommandRunner{}
+	s := &service{
+		logger: zap.NewNop().Sugar(),
+		slm:    &fakeSLM{},
+		runner: runner,
+	}
+
+	_, err := s.SetVariable(context.Background(), &SetVariableRequest{
+		Username: "admin",
+		Key:      "max_threads",
+		Value:    "8",
+	})
+
+	require.EqualError(t, err, `unknown setting "max_threads"`)
+	require.Len(t, runner.queries, 1)
+}
+
+func TestSetVariablesDryRunReportsDiffWithoutApplying(t *testing.T) {
+	t.Setenv(clickHouseHostEnvKey, "")
+	t.Setenv(clickHousePortEnvKey, "9440")
+	t.Setenv(clickHouseSecureEnvKey, "true")
+	writeEncryptedPassword(t, "admin", "secret")
+
+	runner := &fakeCommandRunner{output: "max_threads\t4\n"}
+	s := &service{
+		logger: zap.NewNop().Sugar(),
+		slm:    &fakeSLM{},
+		runner: runner,
+	}
+
+	resp, err := s.SetVariables(context.Background(), &SetVariablesRequest{
+		Username:  "admin",
+		Variables: map[string]string{"max_threads": "8", "max_block_size": "65536"},
+		DryRun:    true,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, runner.queries, 1)
+	require.Equal(t, "SELECT name, value FROM system.settings WHERE name IN ('max_block_size', 'max_threads') FORMAT TabSeparated", runner.queries[0])
+	require.True(t, resp.GetDryRun())
+	require.Len(t, resp.GetResults(), 2)
+	require.Equal(t, "unknown setting", resp.GetResults()[0].GetError())
+	require.Equal(t, "4", resp.GetResults()[1].GetCurrentValue())
+	require.True(t, resp.GetResults()[1].GetChanged())
+	require.False(t, resp.GetResults()[1].GetApplied())
+}
+
+func TestSetVariablesAppliesBatchInOneStatement(t *testing.T) {
+	t.Setenv(clickHouseHostEnvKey, "")
+	t.Setenv(clickHousePortEnvKey, "9440")
+	t.Setenv(clickHouseSecureEnvKey, "true")
+	writeEncryptedPassword(t, "admin", "secret")
+
+	runner := &fakeCommandRunner{output: "max_threads\t4\nmax_block_size\t65536\n"}
+	s := &service{
+		logger: zap.NewNop().Sugar(),
+		slm:    &fakeSLM{},
+		runner: runner,
+	}
+
+	resp, err := s.SetVariables(context.Background(), &SetVariablesRequest{
+		Username:  "admin",
+		Variables: map[string]string{"max_threads": "8", "max_block_size": "65536"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, runner.queries, 2)
+	require.Equal(t, "ALTER USER admin SETTINGS max_block_size = '65536', max_threads = '8'", runner.queries[1])
+	require.False(t, resp.GetResults()[0].GetChanged())
+	require.True(t, resp.GetResults()[1].GetChanged())
+	require.True(t, resp.GetResults()[0].GetApplied())
+	require.True(t, resp.GetResults()[1].GetApplied())
 }
 
 func TestSetVariableSLMFailurePreventsCommandExecution(t *testing.T) {
@@ -438,3 +559,70 @@ func writeEncryptedPassword(t *testing.T, username, password string) {
 	require.NoError(t, err)
 	require.NoError(t, os.WriteFile(filepath.Join(secretDir, username), encryptedPassword, 0600))
 }
+
+func TestBuildCreateUserSQL(t *testing.T) {
+	queries, err := buildCreateUserSQL("app", "it's", []*Privilege{
+		{Database: "analytics", Privileges: []string{"select", "INSERT"}},
+		{Database: "logs", Table: "events", Privileges: []string{"SELECT"}},
+	}, []*Privilege{
+		{Database: "analytics", Privileges: []string{"ALTER"}},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"CREATE USER IF NOT EXISTS app IDENTIFIED WITH sha256_password BY 'it\\'s'",
+		"ALTER USER app IDENTIFIED WITH sha256_password BY 'it\\'s'",
+		"REVOKE ALTER ON analytics.* FROM app",
+		"GRANT SELECT, INSERT ON analytics.* TO app",
+		"GRANT SELECT ON logs.events TO app",
+	}, queries)
+}
+
+func TestBuildCreateUserSQLRejectsInvalidInput(t *testing.T) {
+	_, err := buildCreateUserSQL("app; DROP", "secret", nil, nil)
+	require.Error(t, err)
+
+	_, err = buildCreateUserSQL("app", "", nil, nil)
+	require.Error(t, err)
+
+	_, err = buildCreateUserSQL("app", "secret", []*Privilege{{Database: "analytics", Privileges: []string{"SELECT ON *.* TO admin"}}}, nil)
+	require.Error(t, err)
+
+	_, err = buildCreateUserSQL("app", "secret", nil, []*Privilege{{Database: "analytics"}})
+	require.Error(t, err)
+}
+
+func TestBuildCreateDatabaseSQL(t *testing.T) {
+	query, err := buildCreateDatabaseSQL("analytics", "Atomic")
+	require.NoError(t, err)
+	require.Equal(t, "CREATE DATABASE IF NOT EXISTS analytics ENGINE = Atomic", query)
+
+	_, err = buildCreateDatabaseSQL("analytics; DROP", "")
+	require.Error(t, err)
+}
+
+func TestCreateUserRunsEachQuery(t *testing.T) {
+	t.Setenv(clickHouseHostEnvKey, "")
+	t.Setenv(clickHousePortEnvKey, "9440")
+	t.Setenv(clickHouseSecureEnvKey, "true")
+	writeEncryptedPassword(t, "admin", "secret")
+
+	runner := &fakeCommandRunner{}
+	s := &service{
+		logger: zap.NewNop().Sugar(),
+		slm:    &fakeSLM{},
+		runner: runner,
+	}
+
+	_, err := s.CreateUser(context.Background(), &CreateUserRequest{
+		Username:   "admin",
+		User:       "app",
+		Password:   "apppass",
+		Privileges: []*Privilege{{Database: "analytics", Privileges: []string{"SELECT"}}},
+	})
+
+	require.NoError(t, err)
+	requireSafeClickHouseCommand(t, runner, []string{"--secure"})
+	require.Len(t, runner.queries, 3)
+	require.Equal(t, "GRANT SELECT ON analytics.* TO app", runner.queries[2])
+}
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -26,30 +27,55 @@ func NewCommandExecutor(logger *zap.SugaredLogger) *CommandExecutor {
 	}
 }
 
-// ExecutePipedCommands executes two commands with pipe connection
-func (e *CommandExecutor) ExecutePipedCommands(cmd1 *exec.Cmd, cmd2 *exec.Cmd, logPrefix string) error {
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// CountingReader counts the bytes read through it
+type CountingReader struct {
+	io.Reader
+	N int64
+}
+
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.N += int64(n)
+	return n, err
+}
+
+// ExecutePipedCommands executes two commands with pipe connection, returning the bytes piped between them
+func (e *CommandExecutor) ExecutePipedCommands(cmd1 *exec.Cmd, cmd2 *exec.Cmd, logPrefix string) (int64, error) {
 	if err := e.prepareCommand(cmd1); err != nil {
-		return err
+		return 0, err
 	}
 
 	logFile1, err := e.openLogFile(cmd1.Args[0], logPrefix)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer func() { _ = logFile1.Close() }()
 
 	if err := e.prepareCommand(cmd2); err != nil {
-		return err
+		return 0, err
 	}
 
 	logFile2, err := e.openLogFile(cmd2.Args[0], logPrefix)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer func() { _ = logFile2.Close() }()
 
 	pr, pw := io.Pipe()
-	cmd1.Stdout = pw
+	piped := &countingWriter{w: pw}
+	cmd1.Stdout = piped
 	cmd2.Stdin = pr
 
 	stderr1, _ := cmd1.StderrPipe()
@@ -57,12 +83,12 @@ func (e *CommandExecutor) ExecutePipedCommands(cmd1 *exec.Cmd, cmd2 *exec.Cmd, l
 
 	e.logger.Infof("starting command (pip command): %s", strings.Join(cmd1.Args, " "))
 	if err := cmd1.Start(); err != nil {
-		return err
+		return 0, err
 	}
 
 	e.logger.Infof("starting command (pip command):  %s", strings.Join(cmd2.Args, " "))
 	if err := cmd2.Start(); err != nil {
-		return err
+		return 0, err
 	}
 
 	go io.Copy(logFile1, stderr1)
@@ -84,13 +110,13 @@ func (e *CommandExecutor) ExecutePipedCommands(cmd1 *exec.Cmd, cmd2 *exec.Cmd, l
 	err2 := <-errCh
 
 	if err1 != nil {
-		return fmt.Errorf("command %s failed (see %s)", cmd1.Args[0], logFile1.Name())
+		return 0, fmt.Errorf("command %s failed (see %s)", cmd1.Args[0], logFile1.Name())
 	}
 	if err2 != nil {
-		return fmt.Errorf("command %s failed (see %s)", cmd2.Args[0], logFile2.Name())
+		return 0, fmt.Errorf("command %s failed (see %s)", cmd2.Args[0], logFile2.Name())
 	}
 
-	return nil
+	return piped.n, nil
 }
 
 // ExecuteCommand executes a single command with stderr logging
@@ -121,21 +147,47 @@ func (e *CommandExecutor) ExecuteCommand(cmd *exec.Cmd, logPrefix string) error
 	return nil
 }
 
-func (e *CommandExecutor) ExecuteCommandStreamFromS3(ctx context.Context, cmd *exec.Cmd, factory ObjectStorageFactory, bucket, object, logPrefix string) error {
+// ExecuteCommandOutput executes a single command and returns its stdout, which is logged as well
+func (e *CommandExecutor) ExecuteCommandOutput(cmd *exec.Cmd, logPrefix string) ([]byte, error) {
 	if err := e.prepareCommand(cmd); err != nil {
-		return err
+		return nil, err
 	}
 
 	logFile, err := e.openLogFile(cmd.Args[0], logPrefix)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer func() { _ = logFile.Close() }()
+
+	var stdout bytes.Buffer
+	cmd.Stderr = logFile
+	cmd.Stdout = io.MultiWriter(&stdout, logFile)
+
+	e.logger.Infof("starting command: %s", strings.Join(cmd.Args, " "))
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("command failed: %w (see %s)", err, logFile.Name())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// ExecuteCommandStreamFromS3 streams an object to the stdin of a command, returning the bytes read
+func (e *CommandExecutor) ExecuteCommandStreamFromS3(ctx context.Context, cmd *exec.Cmd, factory ObjectStorageFactory, bucket, object, logPrefix string) (int64, error) {
+	if err := e.prepareCommand(cmd); err != nil {
+		return 0, err
+	}
+
+	logFile, err := e.openLogFile(cmd.Args[0], logPrefix)
+	if err != nil {
+		return 0, err
 	}
 	defer func() { _ = logFile.Close() }()
 
 	// 获取 S3 对象（reader）
 	objReader, err := factory.GetObject(ctx, bucket, object)
 	if err != nil {
-		return fmt.Errorf("get object from s3 failed: %w", err)
+		return 0, fmt.Errorf("get object from s3 failed: %w", err)
 	}
 	defer func() { _ = objReader.Close() }()
 
@@ -149,7 +201,7 @@ func (e *CommandExecutor) ExecuteCommandStreamFromS3(ctx context.Context, cmd *e
 	if err := cmd.Start(); err != nil {
 		_ = pr.Close()
 		_ = pw.Close()
-		return err
+		return 0, err
 	}
 
 	cmdErrCh := make(chan error, 1)
@@ -163,10 +215,12 @@ func (e *CommandExecutor) ExecuteCommandStreamFromS3(ctx context.Context, cmd *e
 		cmdErrCh <- err
 	}()
 
+	var read int64
 	copyErrCh := make(chan error, 1)
 	go func() {
-		_, err := io.Copy(pw, objReader)
+		n, err := io.Copy(pw, objReader)
 		_ = pw.Close()
+		read = n
 		copyErrCh <- err
 	}()
 
@@ -174,24 +228,25 @@ func (e *CommandExecutor) ExecuteCommandStreamFromS3(ctx context.Context, cmd *e
 	cmdErr := <-cmdErrCh
 
 	if copyErr != nil {
-		return fmt.Errorf("streaming from s3 failed: %w", copyErr)
+		return 0, fmt.Errorf("streaming from s3 failed: %w", copyErr)
 	}
 
 	if cmdErr != nil {
-		return fmt.Errorf("command failed: %w (see %s)", cmdErr, logFile.Name())
+		return 0, fmt.Errorf("command failed: %w (see %s)", cmdErr, logFile.Name())
 	}
 
-	return nil
+	return read, nil
 }
 
-func (e *CommandExecutor) ExecuteCommandStreamToS3(ctx context.Context, cmd *exec.Cmd, factory ObjectStorageFactory, bucket, object, logPrefix string) error {
+// ExecuteCommandStreamToS3 streams the stdout of a command to an object, returning the bytes written
+func (e *CommandExecutor) ExecuteCommandStreamToS3(ctx context.Context, cmd *exec.Cmd, factory ObjectStorageFactory, bucket, object, logPrefix string) (int64, error) {
 	if err := e.prepareCommand(cmd); err != nil {
-		return err
+		return 0, err
 	}
 
 	logFile, err := e.openLogFile(cmd.Args[0], logPrefix)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer func() { _ = logFile.Close() }()
 
@@ -204,7 +259,7 @@ func (e *CommandExecutor) ExecuteCommandStreamToS3(ctx context.Context, cmd *exe
 	if err := cmd.Start(); err != nil {
 		_ = pw.Close()
 		_ = pr.Close()
-		return err
+		return 0, err
 	}
 
 	// command execution goroutine
@@ -220,20 +275,21 @@ func (e *CommandExecutor) ExecuteCommandStreamToS3(ctx context.Context, cmd *exe
 	}()
 
 	// upload blocks until EOF or error
-	uploadErr := factory.PutObject(ctx, bucket, object, pr)
+	uploaded := &CountingReader{Reader: pr}
+	uploadErr := factory.PutObject(ctx, bucket, object, uploaded)
 	_ = pr.Close()
 
 	cmdErr := <-cmdErrCh
 
 	if cmdErr != nil {
-		return fmt.Errorf("command failed: %w (see %s)", cmdErr, logFile.Name())
+		return 0, fmt.Errorf("command failed: %w (see %s)", cmdErr, logFile.Name())
 	}
 
 	if uploadErr != nil {
-		return fmt.Errorf("upload to s3 failed: %w", uploadErr)
+		return 0, fmt.Errorf("upload to s3 failed: %w", uploadErr)
 	}
 
-	return nil
+	return uploaded.N, nil
 }
 
 func (e *CommandExecutor) prepareCommand(cmd *exec.Cmd) error {
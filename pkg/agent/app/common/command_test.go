@@ -65,6 +65,18 @@ func TestExecuteCommand(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestExecuteCommandOutput(t *testing.T) {
+	executor := newCommandExecutorForTest(t)
+	cmd := exec.Command("sh", "-c", "printf 'ok'; printf 'log' >&2")
+
+	out, err := executor.ExecuteCommandOutput(cmd, "unit")
+	require.NoError(t, err)
+	require.Equal(t, "ok", string(out))
+
+	_, err = executor.ExecuteCommandOutput(exec.Command("sh", "-c", "exit 1"), "unit")
+	require.Error(t, err)
+}
+
 func TestExecuteCommandInvalidBinary(t *testing.T) {
 	executor := newCommandExecutorForTest(t)
 	cmd := exec.Command("does-not-exist")
@@ -98,8 +110,9 @@ func TestExecutePipedCommands(t *testing.T) {
 	target := filepath.Join(t.TempDir(), "out")
 	cmd2 := exec.Command("sh", "-c", fmt.Sprintf("cat > %s", target))
 
-	err := executor.ExecutePipedCommands(cmd1, cmd2, "pipe")
+	n, err := executor.ExecutePipedCommands(cmd1, cmd2, "pipe")
 	require.NoError(t, err)
+	require.Equal(t, int64(len("payload")), n)
 
 	data, err := os.ReadFile(target)
 	require.NoError(t, err)
@@ -112,8 +125,9 @@ func TestExecuteCommandStreamToS3(t *testing.T) {
 	cmd := exec.Command("sh", "-c", "printf 'stream-data'")
 	factory := &fakeStorageFactory{}
 
-	err := executor.ExecuteCommandStreamToS3(context.Background(), cmd, factory, "bucket", "object", "backup")
+	n, err := executor.ExecuteCommandStreamToS3(context.Background(), cmd, factory, "bucket", "object", "backup")
 	require.NoError(t, err)
+	require.Equal(t, int64(len("stream-data")), n)
 	require.Equal(t, "stream-data", factory.putBuffer.String())
 }
 
@@ -126,8 +140,9 @@ func TestExecuteCommandStreamFromS3(t *testing.T) {
 		getBuffer: []byte("from-s3"),
 	}
 
-	err := executor.ExecuteCommandStreamFromS3(context.Background(), cmd, factory, "bucket", "object", "restore")
+	n, err := executor.ExecuteCommandStreamFromS3(context.Background(), cmd, factory, "bucket", "object", "restore")
 	require.NoError(t, err)
+	require.Equal(t, int64(len("from-s3")), n)
 
 	data, err := os.ReadFile(target)
 	require.NoError(t, err)
@@ -140,7 +155,7 @@ func TestExecutePipedCommandsCmdFailure(t *testing.T) {
 	cmd1 := exec.Command("sh", "-c", "exit 1")
 	cmd2 := exec.Command("cat")
 
-	err := executor.ExecutePipedCommands(cmd1, cmd2, "pipe")
+	_, err := executor.ExecutePipedCommands(cmd1, cmd2, "pipe")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "command sh failed")
 }
@@ -153,7 +168,7 @@ func TestExecuteCommandStreamToS3UploadError(t *testing.T) {
 		putErr: errors.New("upload failed"),
 	}
 
-	err := executor.ExecuteCommandStreamToS3(context.Background(), cmd, factory, "bucket", "object", "backup")
+	_, err := executor.ExecuteCommandStreamToS3(context.Background(), cmd, factory, "bucket", "object", "backup")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "upload failed")
 }
@@ -166,7 +181,7 @@ func TestExecuteCommandStreamFromS3GetError(t *testing.T) {
 		getErr: errors.New("missing object"),
 	}
 
-	err := executor.ExecuteCommandStreamFromS3(context.Background(), cmd, factory, "bucket", "object", "restore")
+	_, err := executor.ExecuteCommandStreamFromS3(context.Background(), cmd, factory, "bucket", "object", "restore")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "missing object")
 }
@@ -177,7 +192,7 @@ func TestExecuteCommandStreamToS3CommandFailure(t *testing.T) {
 	cmd := exec.Command("sh", "-c", "exit 1")
 	factory := &fakeStorageFactory{}
 
-	err := executor.ExecuteCommandStreamToS3(context.Background(), cmd, factory, "bucket", "object", "backup")
+	_, err := executor.ExecuteCommandStreamToS3(context.Background(), cmd, factory, "bucket", "object", "backup")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "command failed")
 }
@@ -190,7 +205,7 @@ func TestExecuteCommandStreamFromS3CommandFailure(t *testing.T) {
 		getBuffer: []byte("payload"),
 	}
 
-	err := executor.ExecuteCommandStreamFromS3(context.Background(), cmd, factory, "bucket", "object", "restore")
+	_, err := executor.ExecuteCommandStreamFromS3(context.Background(), cmd, factory, "bucket", "object", "restore")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "command failed")
 }
@@ -103,6 +103,395 @@ func (*Empty) Descriptor() ([]byte, []int) {
 	return file_pkg_agent_app_common_pb_common_proto_rawDescGZIP(), []int{0}
 }
 
+// BackupResponse describes the backup written by a backup RPC
+type BackupResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Bucket string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	// object key, or key prefix of a backup of several objects
+	Object string `protobuf:"bytes,2,opt,name=object,proto3" json:"object,omitempty"`
+	// bytes written to the object storage
+	SizeBytes  int64 `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	DurationMs int64 `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	// position the backup is consistent to, e.g. a GTID set or a WAL LSN, empty when unknown
+	Position      string `protobuf:"bytes,5,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupResponse) Reset() {
+	*x = BackupResponse{}
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupResponse) ProtoMessage() {}
+
+func (x *BackupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupResponse.ProtoReflect.Descriptor instead.
+func (*BackupResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_common_pb_common_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BackupResponse) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *BackupResponse) GetObject() string {
+	if x != nil {
+		return x.Object
+	}
+	return ""
+}
+
+func (x *BackupResponse) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *BackupResponse) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *BackupResponse) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+// RestoreResponse describes the backup applied by a restore RPC
+type RestoreResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Bucket string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Object string                 `protobuf:"bytes,2,opt,name=object,proto3" json:"object,omitempty"`
+	// bytes read from the object storage
+	SizeBytes  int64 `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	DurationMs int64 `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	// position the restored data is consistent to, empty when unknown
+	Position      string `protobuf:"bytes,5,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreResponse) Reset() {
+	*x = RestoreResponse{}
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreResponse) ProtoMessage() {}
+
+func (x *RestoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreResponse.ProtoReflect.Descriptor instead.
+func (*RestoreResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_common_pb_common_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RestoreResponse) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *RestoreResponse) GetObject() string {
+	if x != nil {
+		return x.Object
+	}
+	return ""
+}
+
+func (x *RestoreResponse) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *RestoreResponse) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *RestoreResponse) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+type SetVariableResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RestartRequired bool                   `protobuf:"varint,1,opt,name=restart_required,json=restartRequired,proto3" json:"restart_required,omitempty"`
+	Persisted       bool                   `protobuf:"varint,2,opt,name=persisted,proto3" json:"persisted,omitempty"`
+	// value before the change
+	PreviousValue string `protobuf:"bytes,3,opt,name=previous_value,json=previousValue,proto3" json:"previous_value,omitempty"`
+	// value after the change, read back where it applies at once, the value written otherwise
+	CurrentValue  string `protobuf:"bytes,4,opt,name=current_value,json=currentValue,proto3" json:"current_value,omitempty"`
+	Changed       bool   `protobuf:"varint,5,opt,name=changed,proto3" json:"changed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVariableResponse) Reset() {
+	*x = SetVariableResponse{}
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariableResponse) ProtoMessage() {}
+
+func (x *SetVariableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariableResponse.ProtoReflect.Descriptor instead.
+func (*SetVariableResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_common_pb_common_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SetVariableResponse) GetRestartRequired() bool {
+	if x != nil {
+		return x.RestartRequired
+	}
+	return false
+}
+
+func (x *SetVariableResponse) GetPersisted() bool {
+	if x != nil {
+		return x.Persisted
+	}
+	return false
+}
+
+func (x *SetVariableResponse) GetPreviousValue() string {
+	if x != nil {
+		return x.PreviousValue
+	}
+	return ""
+}
+
+func (x *SetVariableResponse) GetCurrentValue() string {
+	if x != nil {
+		return x.CurrentValue
+	}
+	return ""
+}
+
+func (x *SetVariableResponse) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+type VariableResult struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Key             string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	CurrentValue    string                 `protobuf:"bytes,2,opt,name=current_value,json=currentValue,proto3" json:"current_value,omitempty"`
+	DesiredValue    string                 `protobuf:"bytes,3,opt,name=desired_value,json=desiredValue,proto3" json:"desired_value,omitempty"`
+	Changed         bool                   `protobuf:"varint,4,opt,name=changed,proto3" json:"changed,omitempty"`
+	Applied         bool                   `protobuf:"varint,5,opt,name=applied,proto3" json:"applied,omitempty"`
+	RestartRequired bool                   `protobuf:"varint,6,opt,name=restart_required,json=restartRequired,proto3" json:"restart_required,omitempty"`
+	Error           string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *VariableResult) Reset() {
+	*x = VariableResult{}
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VariableResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariableResult) ProtoMessage() {}
+
+func (x *VariableResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariableResult.ProtoReflect.Descriptor instead.
+func (*VariableResult) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_common_pb_common_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VariableResult) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *VariableResult) GetCurrentValue() string {
+	if x != nil {
+		return x.CurrentValue
+	}
+	return ""
+}
+
+func (x *VariableResult) GetDesiredValue() string {
+	if x != nil {
+		return x.DesiredValue
+	}
+	return ""
+}
+
+func (x *VariableResult) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+func (x *VariableResult) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *VariableResult) GetRestartRequired() bool {
+	if x != nil {
+		return x.RestartRequired
+	}
+	return false
+}
+
+func (x *VariableResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SetVariablesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*VariableResult      `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Persisted     bool                   `protobuf:"varint,3,opt,name=persisted,proto3" json:"persisted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVariablesResponse) Reset() {
+	*x = SetVariablesResponse{}
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariablesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariablesResponse) ProtoMessage() {}
+
+func (x *SetVariablesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariablesResponse.ProtoReflect.Descriptor instead.
+func (*SetVariablesResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_common_pb_common_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SetVariablesResponse) GetResults() []*VariableResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SetVariablesResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *SetVariablesResponse) GetPersisted() bool {
+	if x != nil {
+		return x.Persisted
+	}
+	return false
+}
+
 type ObjectStorage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Endpoint      string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
@@ -117,7 +506,7 @@ type ObjectStorage struct {
 
 func (x *ObjectStorage) Reset() {
 	*x = ObjectStorage{}
-	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[1]
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -129,7 +518,7 @@ func (x *ObjectStorage) String() string {
 func (*ObjectStorage) ProtoMessage() {}
 
 func (x *ObjectStorage) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[1]
+	mi := &file_pkg_agent_app_common_pb_common_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -142,7 +531,7 @@ func (x *ObjectStorage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ObjectStorage.ProtoReflect.Descriptor instead.
 func (*ObjectStorage) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_common_pb_common_proto_rawDescGZIP(), []int{1}
+	return file_pkg_agent_app_common_pb_common_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ObjectStorage) GetEndpoint() string {
@@ -192,7 +581,41 @@ var File_pkg_agent_app_common_pb_common_proto protoreflect.FileDescriptor
 const file_pkg_agent_app_common_pb_common_proto_rawDesc = "" +
 	"\n" +
 	"$pkg/agent/app/common/pb/common.proto\x12\x06common\"\a\n" +
-	"\x05Empty\"\xc2\x01\n" +
+	"\x05Empty\"\x9c\x01\n" +
+	"\x0eBackupResponse\x12\x16\n" +
+	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x16\n" +
+	"\x06object\x18\x02 \x01(\tR\x06object\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x03 \x01(\x03R\tsizeBytes\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x03R\n" +
+	"durationMs\x12\x1a\n" +
+	"\bposition\x18\x05 \x01(\tR\bposition\"\x9d\x01\n" +
+	"\x0fRestoreResponse\x12\x16\n" +
+	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x16\n" +
+	"\x06object\x18\x02 \x01(\tR\x06object\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x03 \x01(\x03R\tsizeBytes\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x03R\n" +
+	"durationMs\x12\x1a\n" +
+	"\bposition\x18\x05 \x01(\tR\bposition\"\xc4\x01\n" +
+	"\x13SetVariableResponse\x12)\n" +
+	"\x10restart_required\x18\x01 \x01(\bR\x0frestartRequired\x12\x1c\n" +
+	"\tpersisted\x18\x02 \x01(\bR\tpersisted\x12%\n" +
+	"\x0eprevious_value\x18\x03 \x01(\tR\rpreviousValue\x12#\n" +
+	"\rcurrent_value\x18\x04 \x01(\tR\fcurrentValue\x12\x18\n" +
+	"\achanged\x18\x05 \x01(\bR\achanged\"\xe1\x01\n" +
+	"\x0eVariableResult\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12#\n" +
+	"\rcurrent_value\x18\x02 \x01(\tR\fcurrentValue\x12#\n" +
+	"\rdesired_value\x18\x03 \x01(\tR\fdesiredValue\x12\x18\n" +
+	"\achanged\x18\x04 \x01(\bR\achanged\x12\x18\n" +
+	"\aapplied\x18\x05 \x01(\bR\aapplied\x12)\n" +
+	"\x10restart_required\x18\x06 \x01(\bR\x0frestartRequired\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\"\x7f\n" +
+	"\x14SetVariablesResponse\x120\n" +
+	"\aresults\x18\x01 \x03(\v2\x16.common.VariableResultR\aresults\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12\x1c\n" +
+	"\tpersisted\x18\x03 \x01(\bR\tpersisted\"\xc2\x01\n" +
 	"\rObjectStorage\x12\x1a\n" +
 	"\bendpoint\x18\x01 \x01(\tR\bendpoint\x12\x16\n" +
 	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x1d\n" +
@@ -219,19 +642,25 @@ func file_pkg_agent_app_common_pb_common_proto_rawDescGZIP() []byte {
 }
 
 var file_pkg_agent_app_common_pb_common_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pkg_agent_app_common_pb_common_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pkg_agent_app_common_pb_common_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_pkg_agent_app_common_pb_common_proto_goTypes = []any{
-	(ObjectStorageType)(0), // 0: common.ObjectStorageType
-	(*Empty)(nil),          // 1: common.Empty
-	(*ObjectStorage)(nil),  // 2: common.ObjectStorage
+	(ObjectStorageType)(0),       // 0: common.ObjectStorageType
+	(*Empty)(nil),                // 1: common.Empty
+	(*BackupResponse)(nil),       // 2: common.BackupResponse
+	(*RestoreResponse)(nil),      // 3: common.RestoreResponse
+	(*SetVariableResponse)(nil),  // 4: common.SetVariableResponse
+	(*VariableResult)(nil),       // 5: common.VariableResult
+	(*SetVariablesResponse)(nil), // 6: common.SetVariablesResponse
+	(*ObjectStorage)(nil),        // 7: common.ObjectStorage
 }
 var file_pkg_agent_app_common_pb_common_proto_depIdxs = []int32{
-	0, // 0: common.ObjectStorage.type:type_name -> common.ObjectStorageType
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	5, // 0: common.SetVariablesResponse.results:type_name -> common.VariableResult
+	0, // 1: common.ObjectStorage.type:type_name -> common.ObjectStorageType
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_pkg_agent_app_common_pb_common_proto_init() }
@@ -245,7 +674,7 @@ func file_pkg_agent_app_common_pb_common_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_common_pb_common_proto_rawDesc), len(file_pkg_agent_app_common_pb_common_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   2,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
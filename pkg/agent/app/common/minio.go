@@ -75,5 +75,19 @@ func (mc *minioClient) PutObject(ctx context.Context, bucket, objectName string,
 }
 
 func (mc *minioClient) GetObject(ctx context.Context, bucket, objectName string) (io.ReadCloser, error) {
-	return mc.client.GetObject(ctx, bucket, objectName, minio.GetObjectOptions{})
+	obj, err := mc.client.GetObject(ctx, bucket, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// the object is fetched lazily, a missing object only fails on the first read
+	if _, err := obj.Stat(); err != nil {
+		_ = obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, fmt.Errorf("%w: %s/%s", ErrObjectNotFound, bucket, objectName)
+		}
+		return nil, err
+	}
+
+	return obj, nil
 }
@@ -0,0 +1,20 @@
+package common
+
+import "strings"
+
+// ParseRedisInfo parses the output of the Redis INFO command into its fields.
+func ParseRedisInfo(info string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if key, value, found := strings.Cut(line, ":"); found {
+			result[key] = value
+		}
+	}
+
+	return result
+}
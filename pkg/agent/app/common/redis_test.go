@@ -0,0 +1,17 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRedisInfo(t *testing.T) {
+	info := "# Persistence\r\nrdb_bgsave_in_progress:0\r\nloading:0\r\n\r\n# Replication\r\nmaster_host:10.0.0.1\r\n"
+
+	result := ParseRedisInfo(info)
+	require.Equal(t, "0", result["rdb_bgsave_in_progress"])
+	require.Equal(t, "0", result["loading"])
+	require.Equal(t, "10.0.0.1", result["master_host"])
+	require.Len(t, result, 3)
+}
@@ -2,10 +2,14 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 )
 
+// ErrObjectNotFound is wrapped by GetObject when the object does not exist
+var ErrObjectNotFound = errors.New("object not found")
+
 type ObjectStorageFactory interface {
 	PutFile(ctx context.Context, bucket, object, path string) error
 	GetFile(ctx context.Context, bucket, object, path string) error
@@ -0,0 +1,71 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortedVariableKeys returns the keys of a variables map in a stable order.
+func SortedVariableKeys(variables map[string]string) []string {
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// NewVariableResult builds the result of a single key from its current and desired value.
+func NewVariableResult(key, current, desired string) *VariableResult {
+	return &VariableResult{
+		Key:          key,
+		CurrentValue: current,
+		DesiredValue: desired,
+		Changed:      strings.TrimSpace(current) != strings.TrimSpace(desired),
+	}
+}
+
+// NewSetVariableResponse builds the response of a single key from its value
+// before and after the change.
+func NewSetVariableResponse(previous, current string) *SetVariableResponse {
+	return &SetVariableResponse{
+		PreviousValue: previous,
+		CurrentValue:  current,
+		Changed:       strings.TrimSpace(previous) != strings.TrimSpace(current),
+	}
+}
+
+// Failed reports whether any key of the batch failed validation or apply.
+func (x *SetVariablesResponse) Failed() bool {
+	for _, result := range x.GetResults() {
+		if result.GetError() != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FailedError summarizes the failed keys of the batch into a single error.
+func (x *SetVariablesResponse) FailedError() error {
+	var failed []string
+	for _, result := range x.GetResults() {
+		if result.GetError() != "" {
+			failed = append(failed, fmt.Sprintf("%s: %s", result.GetKey(), result.GetError()))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("set variables failed: %s", strings.Join(failed, "; "))
+}
+
+// MarkApplied flags every key of the batch as applied.
+func (x *SetVariablesResponse) MarkApplied() {
+	for _, result := range x.GetResults() {
+		result.Applied = true
+	}
+}
@@ -0,0 +1,42 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortedVariableKeys(t *testing.T) {
+	require.Equal(t, []string{"a", "b", "c"}, SortedVariableKeys(map[string]string{"c": "3", "a": "1", "b": "2"}))
+	require.Empty(t, SortedVariableKeys(nil))
+}
+
+func TestNewVariableResult(t *testing.T) {
+	require.False(t, NewVariableResult("k", "100", " 100 ").GetChanged())
+	require.True(t, NewVariableResult("k", "100", "200").GetChanged())
+}
+
+func TestNewSetVariableResponse(t *testing.T) {
+	resp := NewSetVariableResponse("100", "200")
+	require.Equal(t, "100", resp.GetPreviousValue())
+	require.Equal(t, "200", resp.GetCurrentValue())
+	require.True(t, resp.GetChanged())
+	require.False(t, NewSetVariableResponse("100", "100 ").GetChanged())
+}
+
+func TestSetVariablesResponseFailed(t *testing.T) {
+	resp := &SetVariablesResponse{Results: []*VariableResult{
+		NewVariableResult("a", "1", "2"),
+		NewVariableResult("b", "1", "1"),
+	}}
+	require.False(t, resp.Failed())
+	require.NoError(t, resp.FailedError())
+
+	resp.MarkApplied()
+	require.True(t, resp.GetResults()[0].GetApplied())
+	require.True(t, resp.GetResults()[1].GetApplied())
+
+	resp.Results[1].Error = "unknown variable"
+	require.True(t, resp.Failed())
+	require.EqualError(t, resp.FailedError(), "set variables failed: b: unknown variable")
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/upmio/unit-operator/pkg/agent/vars"
 	"k8s.io/apimachinery/pkg/util/json"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -60,6 +61,10 @@ func (s *service) Registry(server *grpc.Server) {
 	RegisterSyncConfigServiceServer(server, svr)
 }
 
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterSyncConfigServiceHandler(ctx, mux, conn)
+}
+
 func (s *service) SyncConfig(ctx context.Context, req *SyncConfigRequest) (*common.Empty, error) {
 	util.LogRequestSafely(s.logger, "sync config", map[string]interface{}{
 		"key":              req.GetKey(),
@@ -1,8 +1,10 @@
 package app
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
 )
 
@@ -13,6 +15,11 @@ type GRPCApp interface {
 	Name() string
 }
 
+// GatewayApp GRPCService served by the REST/JSON gateway as well
+type GatewayApp interface {
+	RegistryGateway(context.Context, *runtime.ServeMux, *grpc.ClientConn) error
+}
+
 var (
 	grpcApps = map[string]GRPCApp{}
 )
@@ -57,3 +64,19 @@ func LoadGrpcApp(server *grpc.Server) error {
 	}
 	return nil
 }
+
+// LoadGatewayApp register the gateway handlers of all Grpc apps, which
+// forward the REST/JSON requests to the gRPC server through conn
+func LoadGatewayApp(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	for name, app := range grpcApps {
+		gateway, ok := app.(GatewayApp)
+		if !ok {
+			continue
+		}
+
+		if err := gateway.RegistryGateway(ctx, mux, conn); err != nil {
+			return fmt.Errorf("register gateway of grpc app %s error %s", name, err)
+		}
+	}
+	return nil
+}
@@ -11,11 +11,13 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/upmio/unit-operator/pkg/agent/app"
 	"github.com/upmio/unit-operator/pkg/agent/app/common"
 	"github.com/upmio/unit-operator/pkg/agent/app/slm"
@@ -139,10 +141,15 @@ func (s *service) Registry(server *grpc.Server) {
 	RegisterMilvusOperationServer(server, svr)
 }
 
-func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty, error) {
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterMilvusOperationHandler(ctx, mux, conn)
+}
+
+func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.BackupResponse, error) {
 	util.LogRequestSafely(s.logger, "milvus backup", map[string]interface{}{
 		"backup_root_path": req.GetBackupRootPath(),
 		"backup_file":      req.GetBackupFile(),
+		"collections":      req.GetCollections(),
 		"bucket":           req.GetObjectStorage().GetBucket(),
 		"endpoint":         req.GetObjectStorage().GetEndpoint(),
 		"access_key":       req.GetObjectStorage().GetAccessKey(),
@@ -157,18 +164,27 @@ func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty
 		return nil, err
 	}
 
+	if err := validateCollections(req.GetCollections()); err != nil {
+		s.logger.Errorw("invalid collections", zap.Error(err))
+		return nil, err
+	}
+
 	if err := s.generateConfig(req.GetObjectStorage(), req.GetBackupRootPath()); err != nil {
 		return nil, err
 	}
 
-	cmd := exec.CommandContext(ctx,
-		"milvus-backup",
+	start := time.Now()
+	args := []string{
 		"--config",
 		milvusBackupConfFile,
 		"create",
 		"-n",
 		req.GetBackupFile(),
-	)
+	}
+	if len(req.GetCollections()) > 0 {
+		args = append(args, "-c", strings.Join(req.GetCollections(), ","))
+	}
+	cmd := exec.CommandContext(ctx, "milvus-backup", args...)
 
 	// Use command executor for single command
 	executor := common.NewCommandExecutor(s.logger)
@@ -178,15 +194,29 @@ func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty
 		return nil, err
 	}
 
-	s.logger.Info("backup milvus successfully")
-	return nil, nil
+	resp := &common.BackupResponse{
+		Bucket:     req.GetObjectStorage().GetBucket(),
+		Object:     path.Join(req.GetBackupRootPath(), req.GetBackupFile()),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	// The backup is written, a failure to describe it only leaves the size unknown
+	if info, err := s.getBackup(ctx, executor, req.GetBackupFile()); err != nil {
+		s.logger.Warnw("failed to get backup", zap.Error(err), zap.String("backup_file", req.GetBackupFile()))
+	} else {
+		resp.SizeBytes = info.GetSize()
+	}
+
+	s.logger.Infow("backup milvus successfully", "size_bytes", resp.GetSizeBytes())
+	return resp, nil
 }
 
-func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Empty, error) {
+func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.RestoreResponse, error) {
 	util.LogRequestSafely(s.logger, "milvus restore", map[string]interface{}{
 		"suffix":           req.GetSuffix(),
 		"backup_root_path": req.GetBackupRootPath(),
 		"backup_file":      req.GetBackupFile(),
+		"collections":      req.GetCollections(),
 		"bucket":           req.GetObjectStorage().GetBucket(),
 		"endpoint":         req.GetObjectStorage().GetEndpoint(),
 		"access_key":       req.GetObjectStorage().GetAccessKey(),
@@ -201,47 +231,56 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 		return nil, err
 	}
 
-	if err := s.generateConfig(req.GetObjectStorage(), req.GetBackupRootPath()); err != nil {
+	if err := validateCollections(req.GetCollections()); err != nil {
+		s.logger.Errorw("invalid collections", zap.Error(err))
 		return nil, err
 	}
 
-	// Execute milvus-backup command
-	var cmd *exec.Cmd
-
-	if req.GetSuffix() == "" {
-		cmd = exec.CommandContext(ctx,
-			"milvus-backup",
-			"--config",
-			milvusBackupConfFile,
-			"restore",
-			"--restore_index",
-			"-n",
-			req.GetBackupFile(),
-		)
-	} else {
-		cmd = exec.CommandContext(ctx,
-			"milvus-backup",
-			"--config",
-			milvusBackupConfFile,
-			"restore",
-			"--restore_index",
-			"-n",
-			req.GetBackupFile(),
-			"-s",
-			req.GetSuffix(),
-		)
+	if err := s.generateConfig(req.GetObjectStorage(), req.GetBackupRootPath()); err != nil {
+		return nil, err
 	}
 
 	// Use command executor for single command
 	executor := common.NewCommandExecutor(s.logger)
 
+	// The size of the backup is reported as the bytes read, a backup which can
+	// not be described leaves it unknown and fails the restore below instead
+	start := time.Now()
+	resp := &common.RestoreResponse{
+		Bucket: req.GetObjectStorage().GetBucket(),
+		Object: path.Join(req.GetBackupRootPath(), req.GetBackupFile()),
+	}
+	if info, err := s.getBackup(ctx, executor, req.GetBackupFile()); err != nil {
+		s.logger.Warnw("failed to get backup", zap.Error(err), zap.String("backup_file", req.GetBackupFile()))
+	} else {
+		resp.SizeBytes = info.GetSize()
+	}
+
+	// Execute milvus-backup command
+	args := []string{
+		"--config",
+		milvusBackupConfFile,
+		"restore",
+		"--restore_index",
+		"-n",
+		req.GetBackupFile(),
+	}
+	if req.GetSuffix() != "" {
+		args = append(args, "-s", req.GetSuffix())
+	}
+	if len(req.GetCollections()) > 0 {
+		args = append(args, "-c", strings.Join(req.GetCollections(), ","))
+	}
+	cmd := exec.CommandContext(ctx, "milvus-backup", args...)
+
 	if err := executor.ExecuteCommand(cmd, "restore"); err != nil {
 		s.logger.Errorw("failed to execute restore", zap.Error(err))
 		return nil, err
 	}
+	resp.DurationMs = time.Since(start).Milliseconds()
 
-	s.logger.Info("restore milvus successfully")
-	return nil, nil
+	s.logger.Infow("restore milvus successfully", "size_bytes", resp.GetSizeBytes())
+	return resp, nil
 }
 
 func (s *service) generateConfig(storage *common.ObjectStorage, backupRootPath string) error {
@@ -293,7 +332,7 @@ func (s *service) generateConfig(storage *common.ObjectStorage, backupRootPath s
 	return nil
 }
 
-func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.Empty, error) {
+func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.SetVariableResponse, error) {
 	util.LogRequestSafely(s.logger, "milvus set variable", map[string]interface{}{
 		"key":   req.GetKey(),
 		"value": req.GetValue(),
@@ -305,6 +344,13 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 		return nil, err
 	}
 
+	etcdKey := s.configKeyPath(req.GetKey())
+	if etcdKey == "" {
+		err := fmt.Errorf("invalid config key %q", req.GetKey())
+		s.logger.Errorw("invalid set variable request", zap.Error(err))
+		return nil, err
+	}
+
 	// Create etcd connection
 	client, err := s.newEtcdClient(ctx)
 	if err != nil {
@@ -312,19 +358,109 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 	}
 	defer s.closeEtcdClient(client)
 
-	// Execute set variable
-	etcdPrefix := fmt.Sprintf("%s/config/%s", s.rootPath, s.normalizeConfigKeyToPath(req.GetKey()))
-	_, err = client.Put(ctx, etcdPrefix, req.GetValue())
+	// Execute set variable, the previous override is empty when the key still uses the rendered milvus.yaml
+	put, err := client.Put(ctx, etcdKey, req.GetValue(), clientv3.WithPrevKV())
 	if err != nil {
 		s.logger.Errorw("failed to set variable", zap.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("set variable successfully")
-	return nil, nil
+	previous := ""
+	if put.PrevKv != nil {
+		previous = string(put.PrevKv.Value)
+	}
+
+	// The override is stored in etcd, milvus only reloads the keys it declares
+	// refreshable, which the agent can not tell apart, so a restart is reported
+	resp := common.NewSetVariableResponse(previous, req.GetValue())
+	resp.RestartRequired = true
+	resp.Persisted = true
+
+	s.logger.Infow("set variable successfully", "changed", resp.GetChanged())
+	return resp, nil
+}
+
+func (s *service) SetVariables(ctx context.Context, req *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	util.LogRequestSafely(s.logger, "milvus set variables", map[string]interface{}{
+		"variables": req.GetVariables(),
+		"dry_run":   req.GetDryRun(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if len(req.GetVariables()) == 0 {
+		err := fmt.Errorf("variables is required")
+		s.logger.Errorw("invalid set variables request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create etcd connection
+	client, err := s.newEtcdClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeEtcdClient(client)
+
+	// Read the current overrides, keys without one still use the rendered milvus.yaml
+	resp := &common.SetVariablesResponse{DryRun: req.GetDryRun()}
+	keys := common.SortedVariableKeys(req.GetVariables())
+	ops := make([]clientv3.Op, 0, len(keys))
+	for _, key := range keys {
+		value := req.GetVariables()[key]
+		etcdKey := s.configKeyPath(key)
+		if etcdKey == "" {
+			resp.Results = append(resp.Results, &common.VariableResult{Key: key, DesiredValue: value, Error: "invalid config key"})
+			continue
+		}
+
+		current, err := client.Get(ctx, etcdKey)
+		if err != nil {
+			s.logger.Errorw("failed to get variable", zap.Error(err), zap.String("key", key))
+			return nil, err
+		}
+
+		currentValue := ""
+		if len(current.Kvs) > 0 {
+			currentValue = string(current.Kvs[0].Value)
+		}
+		result := common.NewVariableResult(key, currentValue, value)
+		// milvus only reloads the keys it declares refreshable, see SetVariable
+		result.RestartRequired = result.GetChanged()
+		resp.Results = append(resp.Results, result)
+		ops = append(ops, clientv3.OpPut(etcdKey, value))
+	}
+
+	if req.GetDryRun() || resp.Failed() {
+		return resp, nil
+	}
+
+	// Write every key of the batch in one etcd transaction
+	if _, err = client.Txn(ctx).Then(ops...).Commit(); err != nil {
+		s.logger.Errorw("failed to set variables", zap.Error(err))
+		return nil, err
+	}
+	resp.MarkApplied()
+	resp.Persisted = true
+
+	s.logger.Info("set variables successfully")
+	return resp, nil
 }
 
 // Accept "a.b.c" or "a/b/c" or "a.b/c" and normalize to "a/b/c"
+// configKeyPath returns the etcd key holding the override of a milvus config key.
+func (s *service) configKeyPath(key string) string {
+	path := s.normalizeConfigKeyToPath(key)
+	if path == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/config/%s", s.rootPath, path)
+}
+
 func (s *service) normalizeConfigKeyToPath(k string) string {
 	k = strings.TrimSpace(k)
 	k = strings.TrimPrefix(k, "/")
@@ -13,6 +13,16 @@ func TestServiceName(t *testing.T) {
 	}
 }
 
+func TestConfigKeyPath(t *testing.T) {
+	svc := &service{rootPath: "by-dev"}
+	if got := svc.configKeyPath("quotaAndLimits.dml.enabled"); got != "by-dev/config/quotaAndLimits/dml/enabled" {
+		t.Fatalf("unexpected config key path %s", got)
+	}
+	if got := svc.configKeyPath(" / "); got != "" {
+		t.Fatalf("expected empty config key path, got %s", got)
+	}
+}
+
 func TestGetEtcdMemberList(t *testing.T) {
 	etcdMemberList := []byte(`["jvcbhiiw-etcd-t62-0.jvcbhiiw-etcd-t62-headless-svc.test.svc.cluster.local","jvcbhiiw-etcd-t62-1.jvcbhiiw-etcd-t62-headless-svc.test.svc.cluster.local","jvcbhiiw-etcd-t62-2.jvcbhiiw-etcd-t62-headless-svc.test.svc.cluster.local"]`)
 
@@ -31,3 +41,84 @@ func TestGetEtcdMemberList(t *testing.T) {
 
 	t.Log(uri)
 }
+
+func TestParseBackupList(t *testing.T) {
+	out := []byte("[2024/05/01 10:00:00.000 +00:00] [INFO] [core/backup_context.go:120] [\"list backups\"]\n" +
+		">> Backups:\n" +
+		"backup_20240501\n" +
+		"[2024/05/01 10:00:00.001 +00:00] [INFO] [core/backup_context.go:130] [\"done\"]\n" +
+		"\n" +
+		"backup_20240502\n")
+
+	names := parseBackupList(out)
+	if len(names) != 2 || names[0] != "backup_20240501" || names[1] != "backup_20240502" {
+		t.Fatalf("unexpected backup names %v", names)
+	}
+
+	if names := parseBackupList([]byte(">> Backups:\n")); len(names) != 0 {
+		t.Fatalf("expected no backup names, got %v", names)
+	}
+}
+
+func TestParseBackupInfo(t *testing.T) {
+	out := []byte(`[2024/05/01 10:00:00.000 +00:00] [INFO] [core/backup_context.go:120] ["get backup"]
+{
+    "requestId": "d4a1",
+    "msg": "success",
+    "data": {
+        "id": "c1b2",
+        "state_code": 2,
+        "start_time": 1714557600000,
+        "end_time": 1714557660000,
+        "progress": 100,
+        "name": "backup_20240501",
+        "size": 4096,
+        "milvus_version": "v2.4.0",
+        "collection_backups": [
+            {"db_name": "default", "collection_name": "books", "size": 1024},
+            {"db_name": "shop", "collection_name": "items", "size": 3072}
+        ]
+    }
+}
+`)
+
+	info, err := parseBackupInfo(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.GetName() != "backup_20240501" || info.GetState() != "success" || info.GetSize() != 4096 ||
+		info.GetStartTime() != 1714557600000 || info.GetMilvusVersion() != "v2.4.0" {
+		t.Fatalf("unexpected backup info %v", info)
+	}
+	if len(info.GetCollections()) != 2 || info.GetCollections()[1].GetDatabase() != "shop" ||
+		info.GetCollections()[1].GetName() != "items" || info.GetCollections()[1].GetSize() != 3072 {
+		t.Fatalf("unexpected backup collections %v", info.GetCollections())
+	}
+
+	if _, err := parseBackupInfo([]byte(`{"code": 404, "msg": "backup not found"}`)); err == nil {
+		t.Fatal("expected an error for a missing backup")
+	}
+	if _, err := parseBackupInfo([]byte("no json here\n")); err == nil {
+		t.Fatal("expected an error for an unexpected output")
+	}
+}
+
+func TestParseDeleteOutput(t *testing.T) {
+	if err := parseDeleteOutput([]byte("Success \n success\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := parseDeleteOutput([]byte("Request_Object_Not_Found \n backup does not exist\n")); err == nil {
+		t.Fatal("expected an error for a failed delete")
+	}
+}
+
+func TestValidateCollections(t *testing.T) {
+	if err := validateCollections([]string{"books", "shop.items"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, collection := range []string{"", "a,b", "a b"} {
+		if err := validateCollections([]string{collection}); err == nil {
+			t.Fatalf("expected an error for collection %q", collection)
+		}
+	}
+}
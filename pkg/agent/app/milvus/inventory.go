@@ -0,0 +1,308 @@
+package milvus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+	"go.uber.org/zap"
+)
+
+// backupListHeader precedes the backup names printed by milvus-backup list
+const backupListHeader = ">> Backups:"
+
+// backupStates names the milvus-backup task state codes
+var backupStates = map[int]string{
+	0: "initial",
+	1: "executing",
+	2: "success",
+	3: "fail",
+	4: "timeout",
+}
+
+// backupInfoResponse is the JSON printed by milvus-backup get.
+type backupInfoResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data *struct {
+		Name              string `json:"name"`
+		StateCode         int    `json:"state_code"`
+		ErrorMessage      string `json:"errorMessage"`
+		StartTime         int64  `json:"start_time"`
+		EndTime           int64  `json:"end_time"`
+		Size              int64  `json:"size"`
+		MilvusVersion     string `json:"milvus_version"`
+		CollectionBackups []struct {
+			DBName         string `json:"db_name"`
+			CollectionName string `json:"collection_name"`
+			Size           int64  `json:"size"`
+		} `json:"collection_backups"`
+	} `json:"data"`
+}
+
+func (s *service) ListBackups(ctx context.Context, req *ListBackupsRequest) (*ListBackupsResponse, error) {
+	util.LogRequestSafely(s.logger, "milvus list backups", map[string]interface{}{
+		"backup_root_path": req.GetBackupRootPath(),
+		"collection":       req.GetCollection(),
+		"with_details":     req.GetWithDetails(),
+		"bucket":           req.GetObjectStorage().GetBucket(),
+		"endpoint":         req.GetObjectStorage().GetEndpoint(),
+		"access_key":       req.GetObjectStorage().GetAccessKey(),
+		"secret_key":       req.GetObjectStorage().GetSecretKey(),
+		"ssl":              req.GetObjectStorage().GetSsl(),
+		"type":             req.GetObjectStorage().GetType(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.generateConfig(req.GetObjectStorage(), req.GetBackupRootPath()); err != nil {
+		return nil, err
+	}
+
+	args := []string{"--config", milvusBackupConfFile, "list"}
+	if req.GetCollection() != "" {
+		args = append(args, "-c", req.GetCollection())
+	}
+
+	executor := common.NewCommandExecutor(s.logger)
+	out, err := executor.ExecuteCommandOutput(exec.CommandContext(ctx, "milvus-backup", args...), "list")
+	if err != nil {
+		s.logger.Errorw("failed to list backups", zap.Error(err))
+		return nil, err
+	}
+
+	resp := &ListBackupsResponse{}
+	for _, name := range parseBackupList(out) {
+		if !req.GetWithDetails() {
+			resp.Backups = append(resp.Backups, &BackupInfo{Name: name})
+			continue
+		}
+
+		info, err := s.getBackup(ctx, executor, name)
+		if err != nil {
+			s.logger.Errorw("failed to get backup", zap.Error(err), zap.String("backup_file", name))
+			return nil, err
+		}
+		resp.Backups = append(resp.Backups, info)
+	}
+
+	s.logger.Infow("list backups successfully", "backups", len(resp.Backups))
+	return resp, nil
+}
+
+func (s *service) GetBackup(ctx context.Context, req *GetBackupRequest) (*BackupInfo, error) {
+	util.LogRequestSafely(s.logger, "milvus get backup", map[string]interface{}{
+		"backup_root_path": req.GetBackupRootPath(),
+		"backup_file":      req.GetBackupFile(),
+		"bucket":           req.GetObjectStorage().GetBucket(),
+		"endpoint":         req.GetObjectStorage().GetEndpoint(),
+		"access_key":       req.GetObjectStorage().GetAccessKey(),
+		"secret_key":       req.GetObjectStorage().GetSecretKey(),
+		"ssl":              req.GetObjectStorage().GetSsl(),
+		"type":             req.GetObjectStorage().GetType(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetBackupFile() == "" {
+		return nil, errors.New("backup_file is required")
+	}
+
+	if err := s.generateConfig(req.GetObjectStorage(), req.GetBackupRootPath()); err != nil {
+		return nil, err
+	}
+
+	info, err := s.getBackup(ctx, common.NewCommandExecutor(s.logger), req.GetBackupFile())
+	if err != nil {
+		s.logger.Errorw("failed to get backup", zap.Error(err), zap.String("backup_file", req.GetBackupFile()))
+		return nil, err
+	}
+
+	s.logger.Info("get backup successfully")
+	return info, nil
+}
+
+func (s *service) DeleteBackup(ctx context.Context, req *DeleteBackupRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "milvus delete backup", map[string]interface{}{
+		"backup_root_path": req.GetBackupRootPath(),
+		"backup_file":      req.GetBackupFile(),
+		"bucket":           req.GetObjectStorage().GetBucket(),
+		"endpoint":         req.GetObjectStorage().GetEndpoint(),
+		"access_key":       req.GetObjectStorage().GetAccessKey(),
+		"secret_key":       req.GetObjectStorage().GetSecretKey(),
+		"ssl":              req.GetObjectStorage().GetSsl(),
+		"type":             req.GetObjectStorage().GetType(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetBackupFile() == "" {
+		return nil, errors.New("backup_file is required")
+	}
+
+	if err := s.generateConfig(req.GetObjectStorage(), req.GetBackupRootPath()); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx,
+		"milvus-backup",
+		"--config",
+		milvusBackupConfFile,
+		"delete",
+		"-n",
+		req.GetBackupFile(),
+	)
+
+	executor := common.NewCommandExecutor(s.logger)
+	out, err := executor.ExecuteCommandOutput(cmd, "delete")
+	if err == nil {
+		err = parseDeleteOutput(out)
+	}
+	if err != nil {
+		s.logger.Errorw("failed to delete backup", zap.Error(err), zap.String("backup_file", req.GetBackupFile()))
+		return nil, err
+	}
+
+	s.logger.Info("delete backup successfully")
+	return nil, nil
+}
+
+func (s *service) getBackup(ctx context.Context, executor *common.CommandExecutor, name string) (*BackupInfo, error) {
+	cmd := exec.CommandContext(ctx,
+		"milvus-backup",
+		"--config",
+		milvusBackupConfFile,
+		"get",
+		"-n",
+		name,
+	)
+
+	out, err := executor.ExecuteCommandOutput(cmd, "get")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBackupInfo(out)
+}
+
+// parseBackupList returns the backup names printed by milvus-backup list, the
+// log lines the tool writes to the console are skipped.
+func parseBackupList(out []byte) []string {
+	var names []string
+	listed := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == backupListHeader:
+			listed = true
+		case !listed, line == "", strings.HasPrefix(line, "["):
+		default:
+			names = append(names, line)
+		}
+	}
+
+	return names
+}
+
+// parseBackupInfo decodes the JSON document printed by milvus-backup get,
+// the log lines the tool writes to the console before it are skipped.
+func parseBackupInfo(out []byte) (*BackupInfo, error) {
+	start := bytes.Index(out, []byte("\n{"))
+	if bytes.HasPrefix(out, []byte("{")) {
+		start = 0
+	}
+	if start < 0 {
+		return nil, fmt.Errorf("unexpected milvus-backup get output: %s", strings.TrimSpace(string(out)))
+	}
+
+	resp := &backupInfoResponse{}
+	if err := json.NewDecoder(bytes.NewReader(out[start:])).Decode(resp); err != nil {
+		return nil, fmt.Errorf("failed to decode milvus-backup get output: %v", err)
+	}
+
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("milvus-backup get failed: code %d: %s", resp.Code, resp.Msg)
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("milvus-backup get returned no backup: %s", resp.Msg)
+	}
+
+	info := &BackupInfo{
+		Name:          resp.Data.Name,
+		State:         backupStates[resp.Data.StateCode],
+		ErrorMessage:  resp.Data.ErrorMessage,
+		Size:          resp.Data.Size,
+		StartTime:     resp.Data.StartTime,
+		EndTime:       resp.Data.EndTime,
+		MilvusVersion: resp.Data.MilvusVersion,
+	}
+	if info.State == "" {
+		info.State = fmt.Sprintf("unknown(%d)", resp.Data.StateCode)
+	}
+
+	for _, collection := range resp.Data.CollectionBackups {
+		info.Collections = append(info.Collections, &BackupCollection{
+			Database: collection.DBName,
+			Name:     collection.CollectionName,
+			Size:     collection.Size,
+		})
+	}
+
+	return info, nil
+}
+
+// parseDeleteOutput checks the response code printed by milvus-backup delete,
+// the tool exits successfully when the backup could not be deleted.
+func parseDeleteOutput(out []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		code, msg, _ := strings.Cut(line, " ")
+		if code == "Success" || code == "0" {
+			return nil
+		}
+
+		if msg == "" && scanner.Scan() {
+			msg = strings.TrimSpace(scanner.Text())
+		}
+		return fmt.Errorf("milvus-backup delete failed: %s: %s", code, strings.TrimSpace(msg))
+	}
+
+	return nil
+}
+
+// validateCollections rejects names milvus-backup would split or ignore.
+func validateCollections(collections []string) error {
+	for _, collection := range collections {
+		if collection == "" || strings.ContainsAny(collection, ", ") {
+			return fmt.Errorf("invalid collection name %q", collection)
+		}
+	}
+
+	return nil
+}
@@ -27,8 +27,10 @@ type BackupRequest struct {
 	BackupFile     string                 `protobuf:"bytes,1,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
 	BackupRootPath string                 `protobuf:"bytes,2,opt,name=backup_root_path,json=backupRootPath,proto3" json:"backup_root_path,omitempty"`
 	ObjectStorage  *common.ObjectStorage  `protobuf:"bytes,3,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// collections limits the backup to the listed collections, as -c
+	Collections   []string `protobuf:"bytes,4,rep,name=collections,proto3" json:"collections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BackupRequest) Reset() {
@@ -82,14 +84,23 @@ func (x *BackupRequest) GetObjectStorage() *common.ObjectStorage {
 	return nil
 }
 
+func (x *BackupRequest) GetCollections() []string {
+	if x != nil {
+		return x.Collections
+	}
+	return nil
+}
+
 type RestoreRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	BackupFile     string                 `protobuf:"bytes,1,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
 	BackupRootPath string                 `protobuf:"bytes,2,opt,name=backup_root_path,json=backupRootPath,proto3" json:"backup_root_path,omitempty"`
 	Suffix         string                 `protobuf:"bytes,3,opt,name=suffix,proto3" json:"suffix,omitempty"`
 	ObjectStorage  *common.ObjectStorage  `protobuf:"bytes,4,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// collections limits the restore to the listed collections of the backup, as -c
+	Collections   []string `protobuf:"bytes,5,rep,name=collections,proto3" json:"collections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RestoreRequest) Reset() {
@@ -150,6 +161,410 @@ func (x *RestoreRequest) GetObjectStorage() *common.ObjectStorage {
 	return nil
 }
 
+func (x *RestoreRequest) GetCollections() []string {
+	if x != nil {
+		return x.Collections
+	}
+	return nil
+}
+
+type ListBackupsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BackupRootPath string                 `protobuf:"bytes,1,opt,name=backup_root_path,json=backupRootPath,proto3" json:"backup_root_path,omitempty"`
+	ObjectStorage  *common.ObjectStorage  `protobuf:"bytes,2,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
+	// collection lists only the backups holding the collection
+	Collection string `protobuf:"bytes,3,opt,name=collection,proto3" json:"collection,omitempty"`
+	// with_details describes every listed backup, otherwise only the names are returned
+	WithDetails   bool `protobuf:"varint,4,opt,name=with_details,json=withDetails,proto3" json:"with_details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBackupsRequest) Reset() {
+	*x = ListBackupsRequest{}
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBackupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBackupsRequest) ProtoMessage() {}
+
+func (x *ListBackupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBackupsRequest.ProtoReflect.Descriptor instead.
+func (*ListBackupsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_milvus_pb_milvus_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListBackupsRequest) GetBackupRootPath() string {
+	if x != nil {
+		return x.BackupRootPath
+	}
+	return ""
+}
+
+func (x *ListBackupsRequest) GetObjectStorage() *common.ObjectStorage {
+	if x != nil {
+		return x.ObjectStorage
+	}
+	return nil
+}
+
+func (x *ListBackupsRequest) GetCollection() string {
+	if x != nil {
+		return x.Collection
+	}
+	return ""
+}
+
+func (x *ListBackupsRequest) GetWithDetails() bool {
+	if x != nil {
+		return x.WithDetails
+	}
+	return false
+}
+
+type ListBackupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backups       []*BackupInfo          `protobuf:"bytes,1,rep,name=backups,proto3" json:"backups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBackupsResponse) Reset() {
+	*x = ListBackupsResponse{}
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBackupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBackupsResponse) ProtoMessage() {}
+
+func (x *ListBackupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBackupsResponse.ProtoReflect.Descriptor instead.
+func (*ListBackupsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_milvus_pb_milvus_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListBackupsResponse) GetBackups() []*BackupInfo {
+	if x != nil {
+		return x.Backups
+	}
+	return nil
+}
+
+type GetBackupRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BackupFile     string                 `protobuf:"bytes,1,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
+	BackupRootPath string                 `protobuf:"bytes,2,opt,name=backup_root_path,json=backupRootPath,proto3" json:"backup_root_path,omitempty"`
+	ObjectStorage  *common.ObjectStorage  `protobuf:"bytes,3,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetBackupRequest) Reset() {
+	*x = GetBackupRequest{}
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBackupRequest) ProtoMessage() {}
+
+func (x *GetBackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBackupRequest.ProtoReflect.Descriptor instead.
+func (*GetBackupRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_milvus_pb_milvus_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetBackupRequest) GetBackupFile() string {
+	if x != nil {
+		return x.BackupFile
+	}
+	return ""
+}
+
+func (x *GetBackupRequest) GetBackupRootPath() string {
+	if x != nil {
+		return x.BackupRootPath
+	}
+	return ""
+}
+
+func (x *GetBackupRequest) GetObjectStorage() *common.ObjectStorage {
+	if x != nil {
+		return x.ObjectStorage
+	}
+	return nil
+}
+
+type DeleteBackupRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BackupFile     string                 `protobuf:"bytes,1,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
+	BackupRootPath string                 `protobuf:"bytes,2,opt,name=backup_root_path,json=backupRootPath,proto3" json:"backup_root_path,omitempty"`
+	ObjectStorage  *common.ObjectStorage  `protobuf:"bytes,3,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeleteBackupRequest) Reset() {
+	*x = DeleteBackupRequest{}
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBackupRequest) ProtoMessage() {}
+
+func (x *DeleteBackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBackupRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBackupRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_milvus_pb_milvus_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteBackupRequest) GetBackupFile() string {
+	if x != nil {
+		return x.BackupFile
+	}
+	return ""
+}
+
+func (x *DeleteBackupRequest) GetBackupRootPath() string {
+	if x != nil {
+		return x.BackupRootPath
+	}
+	return ""
+}
+
+func (x *DeleteBackupRequest) GetObjectStorage() *common.ObjectStorage {
+	if x != nil {
+		return x.ObjectStorage
+	}
+	return nil
+}
+
+type BackupInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// state is the milvus-backup task state, as "success" or "fail"
+	State        string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	ErrorMessage string `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// size is the size of the backed up data in bytes
+	Size int64 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	// start_time and end_time are unix timestamps in milliseconds
+	StartTime     int64               `protobuf:"varint,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       int64               `protobuf:"varint,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	MilvusVersion string              `protobuf:"bytes,7,opt,name=milvus_version,json=milvusVersion,proto3" json:"milvus_version,omitempty"`
+	Collections   []*BackupCollection `protobuf:"bytes,8,rep,name=collections,proto3" json:"collections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupInfo) Reset() {
+	*x = BackupInfo{}
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupInfo) ProtoMessage() {}
+
+func (x *BackupInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupInfo.ProtoReflect.Descriptor instead.
+func (*BackupInfo) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_milvus_pb_milvus_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BackupInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetMilvusVersion() string {
+	if x != nil {
+		return x.MilvusVersion
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetCollections() []*BackupCollection {
+	if x != nil {
+		return x.Collections
+	}
+	return nil
+}
+
+type BackupCollection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Database      string                 `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupCollection) Reset() {
+	*x = BackupCollection{}
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupCollection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupCollection) ProtoMessage() {}
+
+func (x *BackupCollection) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupCollection.ProtoReflect.Descriptor instead.
+func (*BackupCollection) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_milvus_pb_milvus_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BackupCollection) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *BackupCollection) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BackupCollection) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
 type SetVariableRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -160,7 +575,7 @@ type SetVariableRequest struct {
 
 func (x *SetVariableRequest) Reset() {
 	*x = SetVariableRequest{}
-	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[2]
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -172,7 +587,7 @@ func (x *SetVariableRequest) String() string {
 func (*SetVariableRequest) ProtoMessage() {}
 
 func (x *SetVariableRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[2]
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -185,7 +600,7 @@ func (x *SetVariableRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetVariableRequest.ProtoReflect.Descriptor instead.
 func (*SetVariableRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_milvus_pb_milvus_proto_rawDescGZIP(), []int{2}
+	return file_pkg_agent_app_milvus_pb_milvus_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *SetVariableRequest) GetKey() string {
@@ -202,29 +617,127 @@ func (x *SetVariableRequest) GetValue() string {
 	return ""
 }
 
+type SetVariablesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variables     map[string]string      `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVariablesRequest) Reset() {
+	*x = SetVariablesRequest{}
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariablesRequest) ProtoMessage() {}
+
+func (x *SetVariablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariablesRequest.ProtoReflect.Descriptor instead.
+func (*SetVariablesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_milvus_pb_milvus_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SetVariablesRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *SetVariablesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
 var File_pkg_agent_app_milvus_pb_milvus_proto protoreflect.FileDescriptor
 
 const file_pkg_agent_app_milvus_pb_milvus_proto_rawDesc = "" +
 	"\n" +
-	"$pkg/agent/app/milvus/pb/milvus.proto\x12\x06milvus\x1a$pkg/agent/app/common/pb/common.proto\"\x98\x01\n" +
+	"$pkg/agent/app/milvus/pb/milvus.proto\x12\x06milvus\x1a$pkg/agent/app/common/pb/common.proto\"\xba\x01\n" +
 	"\rBackupRequest\x12\x1f\n" +
 	"\vbackup_file\x18\x01 \x01(\tR\n" +
 	"backupFile\x12(\n" +
 	"\x10backup_root_path\x18\x02 \x01(\tR\x0ebackupRootPath\x12<\n" +
-	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\"\xb1\x01\n" +
+	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\x12 \n" +
+	"\vcollections\x18\x04 \x03(\tR\vcollections\"\xd3\x01\n" +
 	"\x0eRestoreRequest\x12\x1f\n" +
 	"\vbackup_file\x18\x01 \x01(\tR\n" +
 	"backupFile\x12(\n" +
 	"\x10backup_root_path\x18\x02 \x01(\tR\x0ebackupRootPath\x12\x16\n" +
 	"\x06suffix\x18\x03 \x01(\tR\x06suffix\x12<\n" +
-	"\x0eobject_storage\x18\x04 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\"<\n" +
+	"\x0eobject_storage\x18\x04 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\x12 \n" +
+	"\vcollections\x18\x05 \x03(\tR\vcollections\"\xbf\x01\n" +
+	"\x12ListBackupsRequest\x12(\n" +
+	"\x10backup_root_path\x18\x01 \x01(\tR\x0ebackupRootPath\x12<\n" +
+	"\x0eobject_storage\x18\x02 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\x12\x1e\n" +
+	"\n" +
+	"collection\x18\x03 \x01(\tR\n" +
+	"collection\x12!\n" +
+	"\fwith_details\x18\x04 \x01(\bR\vwithDetails\"C\n" +
+	"\x13ListBackupsResponse\x12,\n" +
+	"\abackups\x18\x01 \x03(\v2\x12.milvus.BackupInfoR\abackups\"\x9b\x01\n" +
+	"\x10GetBackupRequest\x12\x1f\n" +
+	"\vbackup_file\x18\x01 \x01(\tR\n" +
+	"backupFile\x12(\n" +
+	"\x10backup_root_path\x18\x02 \x01(\tR\x0ebackupRootPath\x12<\n" +
+	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\"\x9e\x01\n" +
+	"\x13DeleteBackupRequest\x12\x1f\n" +
+	"\vbackup_file\x18\x01 \x01(\tR\n" +
+	"backupFile\x12(\n" +
+	"\x10backup_root_path\x18\x02 \x01(\tR\x0ebackupRootPath\x12<\n" +
+	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\"\x8c\x02\n" +
+	"\n" +
+	"BackupInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x05 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x06 \x01(\x03R\aendTime\x12%\n" +
+	"\x0emilvus_version\x18\a \x01(\tR\rmilvusVersion\x12:\n" +
+	"\vcollections\x18\b \x03(\v2\x18.milvus.BackupCollectionR\vcollections\"V\n" +
+	"\x10BackupCollection\x12\x1a\n" +
+	"\bdatabase\x18\x01 \x01(\tR\bdatabase\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\"<\n" +
 	"\x12SetVariableRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value2\xad\x01\n" +
-	"\x0fMilvusOperation\x12.\n" +
-	"\x06Backup\x12\x15.milvus.BackupRequest\x1a\r.common.Empty\x120\n" +
-	"\aRestore\x12\x16.milvus.RestoreRequest\x1a\r.common.Empty\x128\n" +
-	"\vSetVariable\x12\x1a.milvus.SetVariableRequest\x1a\r.common.EmptyB5Z3github.com/upmio/unit-operator/pkg/agent/app/milvusb\x06proto3"
+	"\x05value\x18\x02 \x01(\tR\x05value\"\xb6\x01\n" +
+	"\x13SetVariablesRequest\x12H\n" +
+	"\tvariables\x18\x01 \x03(\v2*.milvus.SetVariablesRequest.VariablesEntryR\tvariables\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\xd8\x03\n" +
+	"\x0fMilvusOperation\x127\n" +
+	"\x06Backup\x12\x15.milvus.BackupRequest\x1a\x16.common.BackupResponse\x12:\n" +
+	"\aRestore\x12\x16.milvus.RestoreRequest\x1a\x17.common.RestoreResponse\x12F\n" +
+	"\vListBackups\x12\x1a.milvus.ListBackupsRequest\x1a\x1b.milvus.ListBackupsResponse\x129\n" +
+	"\tGetBackup\x12\x18.milvus.GetBackupRequest\x1a\x12.milvus.BackupInfo\x12:\n" +
+	"\fDeleteBackup\x12\x1b.milvus.DeleteBackupRequest\x1a\r.common.Empty\x12F\n" +
+	"\vSetVariable\x12\x1a.milvus.SetVariableRequest\x1a\x1b.common.SetVariableResponse\x12I\n" +
+	"\fSetVariables\x12\x1b.milvus.SetVariablesRequest\x1a\x1c.common.SetVariablesResponseB5Z3github.com/upmio/unit-operator/pkg/agent/app/milvusb\x06proto3"
 
 var (
 	file_pkg_agent_app_milvus_pb_milvus_proto_rawDescOnce sync.Once
@@ -238,28 +751,54 @@ func file_pkg_agent_app_milvus_pb_milvus_proto_rawDescGZIP() []byte {
 	return file_pkg_agent_app_milvus_pb_milvus_proto_rawDescData
 }
 
-var file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pkg_agent_app_milvus_pb_milvus_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_pkg_agent_app_milvus_pb_milvus_proto_goTypes = []any{
-	(*BackupRequest)(nil),        // 0: milvus.BackupRequest
-	(*RestoreRequest)(nil),       // 1: milvus.RestoreRequest
-	(*SetVariableRequest)(nil),   // 2: milvus.SetVariableRequest
-	(*common.ObjectStorage)(nil), // 3: common.ObjectStorage
-	(*common.Empty)(nil),         // 4: common.Empty
+	(*BackupRequest)(nil),               // 0: milvus.BackupRequest
+	(*RestoreRequest)(nil),              // 1: milvus.RestoreRequest
+	(*ListBackupsRequest)(nil),          // 2: milvus.ListBackupsRequest
+	(*ListBackupsResponse)(nil),         // 3: milvus.ListBackupsResponse
+	(*GetBackupRequest)(nil),            // 4: milvus.GetBackupRequest
+	(*DeleteBackupRequest)(nil),         // 5: milvus.DeleteBackupRequest
+	(*BackupInfo)(nil),                  // 6: milvus.BackupInfo
+	(*BackupCollection)(nil),            // 7: milvus.BackupCollection
+	(*SetVariableRequest)(nil),          // 8: milvus.SetVariableRequest
+	(*SetVariablesRequest)(nil),         // 9: milvus.SetVariablesRequest
+	nil,                                 // 10: milvus.SetVariablesRequest.VariablesEntry
+	(*common.ObjectStorage)(nil),        // 11: common.ObjectStorage
+	(*common.BackupResponse)(nil),       // 12: common.BackupResponse
+	(*common.RestoreResponse)(nil),      // 13: common.RestoreResponse
+	(*common.Empty)(nil),                // 14: common.Empty
+	(*common.SetVariableResponse)(nil),  // 15: common.SetVariableResponse
+	(*common.SetVariablesResponse)(nil), // 16: common.SetVariablesResponse
 }
 var file_pkg_agent_app_milvus_pb_milvus_proto_depIdxs = []int32{
-	3, // 0: milvus.BackupRequest.object_storage:type_name -> common.ObjectStorage
-	3, // 1: milvus.RestoreRequest.object_storage:type_name -> common.ObjectStorage
-	0, // 2: milvus.MilvusOperation.Backup:input_type -> milvus.BackupRequest
-	1, // 3: milvus.MilvusOperation.Restore:input_type -> milvus.RestoreRequest
-	2, // 4: milvus.MilvusOperation.SetVariable:input_type -> milvus.SetVariableRequest
-	4, // 5: milvus.MilvusOperation.Backup:output_type -> common.Empty
-	4, // 6: milvus.MilvusOperation.Restore:output_type -> common.Empty
-	4, // 7: milvus.MilvusOperation.SetVariable:output_type -> common.Empty
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	11, // 0: milvus.BackupRequest.object_storage:type_name -> common.ObjectStorage
+	11, // 1: milvus.RestoreRequest.object_storage:type_name -> common.ObjectStorage
+	11, // 2: milvus.ListBackupsRequest.object_storage:type_name -> common.ObjectStorage
+	6,  // 3: milvus.ListBackupsResponse.backups:type_name -> milvus.BackupInfo
+	11, // 4: milvus.GetBackupRequest.object_storage:type_name -> common.ObjectStorage
+	11, // 5: milvus.DeleteBackupRequest.object_storage:type_name -> common.ObjectStorage
+	7,  // 6: milvus.BackupInfo.collections:type_name -> milvus.BackupCollection
+	10, // 7: milvus.SetVariablesRequest.variables:type_name -> milvus.SetVariablesRequest.VariablesEntry
+	0,  // 8: milvus.MilvusOperation.Backup:input_type -> milvus.BackupRequest
+	1,  // 9: milvus.MilvusOperation.Restore:input_type -> milvus.RestoreRequest
+	2,  // 10: milvus.MilvusOperation.ListBackups:input_type -> milvus.ListBackupsRequest
+	4,  // 11: milvus.MilvusOperation.GetBackup:input_type -> milvus.GetBackupRequest
+	5,  // 12: milvus.MilvusOperation.DeleteBackup:input_type -> milvus.DeleteBackupRequest
+	8,  // 13: milvus.MilvusOperation.SetVariable:input_type -> milvus.SetVariableRequest
+	9,  // 14: milvus.MilvusOperation.SetVariables:input_type -> milvus.SetVariablesRequest
+	12, // 15: milvus.MilvusOperation.Backup:output_type -> common.BackupResponse
+	13, // 16: milvus.MilvusOperation.Restore:output_type -> common.RestoreResponse
+	3,  // 17: milvus.MilvusOperation.ListBackups:output_type -> milvus.ListBackupsResponse
+	6,  // 18: milvus.MilvusOperation.GetBackup:output_type -> milvus.BackupInfo
+	14, // 19: milvus.MilvusOperation.DeleteBackup:output_type -> common.Empty
+	15, // 20: milvus.MilvusOperation.SetVariable:output_type -> common.SetVariableResponse
+	16, // 21: milvus.MilvusOperation.SetVariables:output_type -> common.SetVariablesResponse
+	15, // [15:22] is the sub-list for method output_type
+	8,  // [8:15] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_pkg_agent_app_milvus_pb_milvus_proto_init() }
@@ -273,7 +812,7 @@ func file_pkg_agent_app_milvus_pb_milvus_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_milvus_pb_milvus_proto_rawDesc), len(file_pkg_agent_app_milvus_pb_milvus_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
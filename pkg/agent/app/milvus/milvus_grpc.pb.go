@@ -19,9 +19,13 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MilvusOperationClient interface {
-	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error)
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.RestoreResponse, error)
+	ListBackups(ctx context.Context, in *ListBackupsRequest, opts ...grpc.CallOption) (*ListBackupsResponse, error)
+	GetBackup(ctx context.Context, in *GetBackupRequest, opts ...grpc.CallOption) (*BackupInfo, error)
+	DeleteBackup(ctx context.Context, in *DeleteBackupRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error)
+	SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error)
 }
 
 type milvusOperationClient struct {
@@ -32,8 +36,8 @@ func NewMilvusOperationClient(cc grpc.ClientConnInterface) MilvusOperationClient
 	return &milvusOperationClient{cc}
 }
 
-func (c *milvusOperationClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *milvusOperationClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error) {
+	out := new(common.BackupResponse)
 	err := c.cc.Invoke(ctx, "/milvus.MilvusOperation/Backup", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -41,8 +45,8 @@ func (c *milvusOperationClient) Backup(ctx context.Context, in *BackupRequest, o
 	return out, nil
 }
 
-func (c *milvusOperationClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *milvusOperationClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.RestoreResponse, error) {
+	out := new(common.RestoreResponse)
 	err := c.cc.Invoke(ctx, "/milvus.MilvusOperation/Restore", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -50,8 +54,35 @@ func (c *milvusOperationClient) Restore(ctx context.Context, in *RestoreRequest,
 	return out, nil
 }
 
-func (c *milvusOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+func (c *milvusOperationClient) ListBackups(ctx context.Context, in *ListBackupsRequest, opts ...grpc.CallOption) (*ListBackupsResponse, error) {
+	out := new(ListBackupsResponse)
+	err := c.cc.Invoke(ctx, "/milvus.MilvusOperation/ListBackups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *milvusOperationClient) GetBackup(ctx context.Context, in *GetBackupRequest, opts ...grpc.CallOption) (*BackupInfo, error) {
+	out := new(BackupInfo)
+	err := c.cc.Invoke(ctx, "/milvus.MilvusOperation/GetBackup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *milvusOperationClient) DeleteBackup(ctx context.Context, in *DeleteBackupRequest, opts ...grpc.CallOption) (*common.Empty, error) {
 	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/milvus.MilvusOperation/DeleteBackup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *milvusOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error) {
+	out := new(common.SetVariableResponse)
 	err := c.cc.Invoke(ctx, "/milvus.MilvusOperation/SetVariable", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -59,13 +90,26 @@ func (c *milvusOperationClient) SetVariable(ctx context.Context, in *SetVariable
 	return out, nil
 }
 
+func (c *milvusOperationClient) SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error) {
+	out := new(common.SetVariablesResponse)
+	err := c.cc.Invoke(ctx, "/milvus.MilvusOperation/SetVariables", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MilvusOperationServer is the server API for MilvusOperation service.
 // All implementations must embed UnimplementedMilvusOperationServer
 // for forward compatibility
 type MilvusOperationServer interface {
-	Backup(context.Context, *BackupRequest) (*common.Empty, error)
-	Restore(context.Context, *RestoreRequest) (*common.Empty, error)
-	SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error)
+	Backup(context.Context, *BackupRequest) (*common.BackupResponse, error)
+	Restore(context.Context, *RestoreRequest) (*common.RestoreResponse, error)
+	ListBackups(context.Context, *ListBackupsRequest) (*ListBackupsResponse, error)
+	GetBackup(context.Context, *GetBackupRequest) (*BackupInfo, error)
+	DeleteBackup(context.Context, *DeleteBackupRequest) (*common.Empty, error)
+	SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error)
+	SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error)
 	mustEmbedUnimplementedMilvusOperationServer()
 }
 
@@ -73,15 +117,27 @@ type MilvusOperationServer interface {
 type UnimplementedMilvusOperationServer struct {
 }
 
-func (UnimplementedMilvusOperationServer) Backup(context.Context, *BackupRequest) (*common.Empty, error) {
+func (UnimplementedMilvusOperationServer) Backup(context.Context, *BackupRequest) (*common.BackupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Backup not implemented")
 }
-func (UnimplementedMilvusOperationServer) Restore(context.Context, *RestoreRequest) (*common.Empty, error) {
+func (UnimplementedMilvusOperationServer) Restore(context.Context, *RestoreRequest) (*common.RestoreResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
 }
-func (UnimplementedMilvusOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error) {
+func (UnimplementedMilvusOperationServer) ListBackups(context.Context, *ListBackupsRequest) (*ListBackupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBackups not implemented")
+}
+func (UnimplementedMilvusOperationServer) GetBackup(context.Context, *GetBackupRequest) (*BackupInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBackup not implemented")
+}
+func (UnimplementedMilvusOperationServer) DeleteBackup(context.Context, *DeleteBackupRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBackup not implemented")
+}
+func (UnimplementedMilvusOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetVariable not implemented")
 }
+func (UnimplementedMilvusOperationServer) SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVariables not implemented")
+}
 func (UnimplementedMilvusOperationServer) mustEmbedUnimplementedMilvusOperationServer() {}
 
 // UnsafeMilvusOperationServer may be embedded to opt out of forward compatibility for this service.
@@ -131,6 +187,60 @@ func _MilvusOperation_Restore_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MilvusOperation_ListBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBackupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MilvusOperationServer).ListBackups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/milvus.MilvusOperation/ListBackups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MilvusOperationServer).ListBackups(ctx, req.(*ListBackupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MilvusOperation_GetBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MilvusOperationServer).GetBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/milvus.MilvusOperation/GetBackup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MilvusOperationServer).GetBackup(ctx, req.(*GetBackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MilvusOperation_DeleteBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MilvusOperationServer).DeleteBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/milvus.MilvusOperation/DeleteBackup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MilvusOperationServer).DeleteBackup(ctx, req.(*DeleteBackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MilvusOperation_SetVariable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetVariableRequest)
 	if err := dec(in); err != nil {
@@ -149,6 +259,24 @@ func _MilvusOperation_SetVariable_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MilvusOperation_SetVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVariablesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MilvusOperationServer).SetVariables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/milvus.MilvusOperation/SetVariables",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MilvusOperationServer).SetVariables(ctx, req.(*SetVariablesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MilvusOperation_ServiceDesc is the grpc.ServiceDesc for MilvusOperation service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -164,10 +292,26 @@ var MilvusOperation_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Restore",
 			Handler:    _MilvusOperation_Restore_Handler,
 		},
+		{
+			MethodName: "ListBackups",
+			Handler:    _MilvusOperation_ListBackups_Handler,
+		},
+		{
+			MethodName: "GetBackup",
+			Handler:    _MilvusOperation_GetBackup_Handler,
+		},
+		{
+			MethodName: "DeleteBackup",
+			Handler:    _MilvusOperation_DeleteBackup_Handler,
+		},
 		{
 			MethodName: "SetVariable",
 			Handler:    _MilvusOperation_SetVariable_Handler,
 		},
+		{
+			MethodName: "SetVariables",
+			Handler:    _MilvusOperation_SetVariables_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/agent/app/milvus/pb/milvus.proto",
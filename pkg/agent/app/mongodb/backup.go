@@ -0,0 +1,605 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// oplogIndexObject lists the archived oplog chunks under the prefix
+const oplogIndexObject = "oplog.index.json"
+
+// oplogIndex is stored as JSON next to the chunks, the chunks are contiguous:
+// a chunk starts where the previous one ends.
+type oplogIndex struct {
+	Chunks []oplogChunk `json:"chunks"`
+}
+
+// oplogChunk holds the entries after Start, or from Start for the first
+// chunk, up to End.
+type oplogChunk struct {
+	Object string `json:"object"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+}
+
+// oplogRange is the archived oplog a restore replays.
+type oplogRange struct {
+	prefix string
+	start  primitive.Timestamp
+	limit  primitive.Timestamp
+}
+
+// errOplogOverwritten is returned once the oplog rolled over entries not archived yet, the archive
+// has a gap no further run can fill.
+var errOplogOverwritten = errors.New("oplog entries were overwritten before they were archived")
+
+// ArchiveOplog archives the oplog entries written since the last chunk of the prefix. With
+// interval_seconds it keeps archiving until it is cancelled, a failed run is retried at the next
+// interval unless the oplog rolled over entries not archived yet.
+func (s *service) ArchiveOplog(ctx context.Context, req *ArchiveOplogRequest) (*ArchiveOplogResponse, error) {
+	util.LogRequestSafely(s.logger, "mongodb archive oplog", map[string]interface{}{
+		"username":         req.GetUsername(),
+		"prefix":           req.GetPrefix(),
+		"interval_seconds": req.GetIntervalSeconds(),
+		"bucket":           req.GetObjectStorage().GetBucket(),
+		"endpoint":         req.GetObjectStorage().GetEndpoint(),
+		"access_key":       req.GetObjectStorage().GetAccessKey(),
+		"secret_key":       req.GetObjectStorage().GetSecretKey(),
+		"ssl":              req.GetObjectStorage().GetSsl(),
+		"type":             req.GetObjectStorage().GetType(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	prefix := strings.Trim(req.GetPrefix(), "/")
+	if prefix == "" {
+		return nil, errors.New("prefix is required")
+	}
+	if req.GetIntervalSeconds() < 0 {
+		return nil, errors.New("interval_seconds must not be negative")
+	}
+
+	factory, err := req.GetObjectStorage().GenerateFactory()
+	if err != nil {
+		s.logger.Errorw("failed to generate storage factory", zap.Error(err))
+		return nil, err
+	}
+
+	archive := func(ctx context.Context) (*ArchiveOplogResponse, error) {
+		return s.archiveOplogChunk(ctx, req.GetUsername(), factory, req.GetObjectStorage().GetBucket(), prefix)
+	}
+
+	if req.GetIntervalSeconds() == 0 {
+		return archive(ctx)
+	}
+
+	// A single archiver runs per prefix, the index is theirs
+	release, err := s.oplogArchivers.acquire(prefix)
+	if err != nil {
+		s.logger.Errorw("failed to start oplog archiver", zap.Error(err), zap.String("prefix", prefix))
+		return nil, err
+	}
+	defer release()
+
+	return runOplogArchiver(ctx, time.Duration(req.GetIntervalSeconds())*time.Second, archive, func(progress string, err error) {
+		if err != nil {
+			s.logger.Errorw("failed to archive oplog, retrying at the next interval", zap.Error(err), zap.String("prefix", prefix))
+		}
+		operation.ReportProgress(ctx, progress)
+	})
+}
+
+// runOplogArchiver runs archive right away and then every interval until ctx is done, report gets
+// the last archived timestamp after every run, along with the error of a failed run. It returns the
+// last chunk archived once cancelled, or the error which leaves a gap in the archive.
+func runOplogArchiver(
+	ctx context.Context,
+	interval time.Duration,
+	archive func(context.Context) (*ArchiveOplogResponse, error),
+	report func(string, error),
+) (*ArchiveOplogResponse, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := &ArchiveOplogResponse{}
+	for {
+		resp, err := archive(ctx)
+		switch {
+		case errors.Is(err, errOplogOverwritten):
+			return nil, err
+		case err != nil && ctx.Err() != nil:
+			return last, ctx.Err()
+		case err == nil:
+			last = resp
+		}
+
+		progress := fmt.Sprintf("archived up to %s in %d chunks", last.GetEnd(), last.GetChunks())
+		if last.GetEnd() == "" {
+			progress = "no oplog archived yet"
+		}
+		if err != nil {
+			progress = fmt.Sprintf("%s, last run failed: %v", progress, err)
+		}
+		report(progress, err)
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// oplogArchivers tracks the prefixes a long running archiver owns.
+type oplogArchivers struct {
+	mu       sync.Mutex
+	prefixes map[string]bool
+}
+
+func (a *oplogArchivers) acquire(prefix string) (func(), error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.prefixes[prefix] {
+		return nil, status.Errorf(codes.FailedPrecondition, "an oplog archiver already runs for prefix %s", prefix)
+	}
+	if a.prefixes == nil {
+		a.prefixes = make(map[string]bool)
+	}
+	a.prefixes[prefix] = true
+
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.prefixes, prefix)
+	}, nil
+}
+
+// archiveOplogChunk archives the entries written since the last chunk of the index as a new chunk,
+// the chunks of a prefix are archived one at a time.
+func (s *service) archiveOplogChunk(
+	ctx context.Context,
+	username string,
+	factory common.ObjectStorageFactory,
+	bucket, prefix string,
+) (*ArchiveOplogResponse, error) {
+	s.oplogMu.Lock()
+	defer s.oplogMu.Unlock()
+
+	index, err := getOplogIndex(ctx, factory, bucket, prefix)
+	if err != nil {
+		s.logger.Errorw("failed to read oplog index", zap.Error(err), zap.String("prefix", prefix))
+		return nil, err
+	}
+
+	client, err := s.newMongoClient(ctx, username)
+	if err != nil {
+		s.logger.Errorw("failed to connect to mongodb", zap.Error(err))
+		return nil, err
+	}
+	defer s.closeMongoClient(ctx, client)
+
+	first, last, err := getOplogWindow(ctx, client)
+	if err != nil {
+		s.logger.Errorw("failed to read oplog window", zap.Error(err))
+		return nil, err
+	}
+
+	resp := &ArchiveOplogResponse{Chunks: int64(len(index.Chunks))}
+
+	// The first chunk starts at the oldest entry, the next ones after the end
+	// of the previous chunk.
+	start, operator := first, "$gte"
+	if n := len(index.Chunks); n > 0 {
+		end, err := parseOplogTimestamp(index.Chunks[n-1].End)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oplog index: %v", err)
+		}
+
+		if first.After(end) {
+			err := fmt.Errorf("%w: oplog starts at %s, after the last archived entry %s",
+				errOplogOverwritten, formatOplogTimestamp(first), formatOplogTimestamp(end))
+			s.logger.Errorw("failed to archive oplog", zap.Error(err))
+			return nil, err
+		}
+
+		if !last.After(end) {
+			s.logger.Infow("no new oplog entries to archive", "end", formatOplogTimestamp(end))
+			resp.Start, resp.End = index.Chunks[n-1].Start, index.Chunks[n-1].End
+			return resp, nil
+		}
+
+		start, operator = end, "$gt"
+	}
+
+	query, err := bson.MarshalExtJSON(bson.D{{Key: "ts", Value: bson.D{
+		{Key: operator, Value: start},
+		{Key: "$lte", Value: last},
+	}}}, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := util.DecryptPlainTextPassword(username)
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", username))
+		return nil, err
+	}
+
+	hosts, err := s.getMongoDBURL(ctx)
+	if err != nil {
+		s.logger.Errorw("failed to get mongodb url", zap.Error(err))
+		return nil, err
+	}
+
+	// The oplog is read from the primary, where the window was taken from.
+	//
+	// mongodump --uri "mongodb://<user>:<password>@<hosts>/?replicaSet=demo&authSource=admin" \
+	//           --db local --collection oplog.rs \
+	//           --query '{"ts":{"$gt":{"$timestamp":{"t":1700000000,"i":1}},"$lte":{...}}}' \
+	//           --out -
+	cmd := exec.CommandContext(ctx,
+		"mongodump",
+		"--uri", fmt.Sprintf("mongodb://%s:%s@%s/?replicaSet=%s&authSource=admin", username, password, hosts, s.serviceGroupName),
+		"--db", "local",
+		"--collection", "oplog.rs",
+		"--query", string(query),
+		"--out", "-",
+	)
+
+	object := path.Join(prefix, fmt.Sprintf("oplog-%d-%d.bson", last.T, last.I))
+	executor := common.NewCommandExecutor(s.logger)
+	if _, err := executor.ExecuteCommandStreamToS3(ctx, cmd, factory, bucket, object, "archive-oplog"); err != nil {
+		s.logger.Errorw("failed to archive oplog", zap.Error(err))
+		return nil, err
+	}
+
+	chunk := oplogChunk{
+		Object: object,
+		Start:  formatOplogTimestamp(start),
+		End:    formatOplogTimestamp(last),
+	}
+	index.Chunks = append(index.Chunks, chunk)
+	if err := putOplogIndex(ctx, factory, bucket, prefix, index); err != nil {
+		s.logger.Errorw("failed to write oplog index", zap.Error(err), zap.String("prefix", prefix))
+		return nil, err
+	}
+
+	s.logger.Infow("archive oplog successfully", "object", object, "start", chunk.Start, "end", chunk.End)
+	return &ArchiveOplogResponse{
+		Object: object,
+		Start:  chunk.Start,
+		End:    chunk.End,
+		Chunks: int64(len(index.Chunks)),
+	}, nil
+}
+
+// replayOplog applies the archived oplog chunks covering the range on top of
+// a restored backup.
+func (s *service) replayOplog(
+	ctx context.Context,
+	executor *common.CommandExecutor,
+	factory common.ObjectStorageFactory,
+	bucket, uri string,
+	r *oplogRange,
+) error {
+	index, err := getOplogIndex(ctx, factory, bucket, r.prefix)
+	if err != nil {
+		return err
+	}
+
+	chunks, err := selectOplogChunks(index.Chunks, r.start, r.limit)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "mongodb-oplog-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// mongorestore replays <dir>/oplog.bson, the chunks are concatenated in order
+	if err := downloadOplogChunks(ctx, factory, bucket, chunks, filepath.Join(dir, "oplog.bson")); err != nil {
+		return err
+	}
+
+	// mongorestore --uri "mongodb://<user>:<password>@127.0.0.1:27017/?replicaSet=demo" \
+	//              --oplogReplay --oplogLimit 1700000000:1 \
+	//              --dir /tmp/mongodb-oplog-xxxxxxxx
+	cmd := exec.CommandContext(ctx,
+		"mongorestore",
+		"--uri", uri,
+		"--oplogReplay",
+		"--oplogLimit", formatOplogTimestamp(r.limit),
+		"--dir", dir,
+	)
+
+	if err := executor.ExecuteCommand(cmd, "restore"); err != nil {
+		return err
+	}
+
+	s.logger.Infow("replay oplog successfully", "chunks", len(chunks), "limit", formatOplogTimestamp(r.limit))
+	return nil
+}
+
+func downloadOplogChunks(ctx context.Context, factory common.ObjectStorageFactory, bucket string, chunks []oplogChunk, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	for _, chunk := range chunks {
+		reader, err := factory.GetObject(ctx, bucket, chunk.Object)
+		if err != nil {
+			return fmt.Errorf("failed to get oplog chunk %s: %w", chunk.Object, err)
+		}
+
+		_, err = io.Copy(f, reader)
+		_ = reader.Close()
+		if err != nil {
+			return fmt.Errorf("failed to download oplog chunk %s: %w", chunk.Object, err)
+		}
+	}
+
+	return f.Close()
+}
+
+// selectOplogChunks returns the chunks holding the entries from start, or
+// from the oldest archived entry when start is zero, up to limit.
+func selectOplogChunks(chunks []oplogChunk, start, limit primitive.Timestamp) ([]oplogChunk, error) {
+	var selected []oplogChunk
+	var end primitive.Timestamp
+	for _, chunk := range chunks {
+		chunkStart, err := parseOplogTimestamp(chunk.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oplog index: %v", err)
+		}
+		chunkEnd, err := parseOplogTimestamp(chunk.End)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oplog index: %v", err)
+		}
+
+		if !start.IsZero() && chunkEnd.Before(start) {
+			continue
+		}
+		if !chunkStart.Before(limit) {
+			break
+		}
+
+		if len(selected) == 0 {
+			if chunkStart.After(start) && !start.IsZero() {
+				return nil, fmt.Errorf("oplog is archived from %s only, after the start %s",
+					chunk.Start, formatOplogTimestamp(start))
+			}
+		} else if !chunkStart.Equal(end) {
+			return nil, fmt.Errorf("oplog archive has a gap between %s and %s", formatOplogTimestamp(end), chunk.Start)
+		}
+
+		selected = append(selected, chunk)
+		end = chunkEnd
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no archived oplog covers %s", formatOplogTimestamp(limit))
+	}
+	if end.Before(limit) {
+		return nil, fmt.Errorf("oplog is archived up to %s only, archive the oplog past %s first",
+			formatOplogTimestamp(end), formatOplogTimestamp(limit))
+	}
+
+	return selected, nil
+}
+
+func getOplogIndex(ctx context.Context, factory common.ObjectStorageFactory, bucket, prefix string) (*oplogIndex, error) {
+	reader, err := factory.GetObject(ctx, bucket, path.Join(prefix, oplogIndexObject))
+	if errors.Is(err, common.ErrObjectNotFound) {
+		return &oplogIndex{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &oplogIndex{}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("invalid oplog index: %v", err)
+	}
+
+	return index, nil
+}
+
+func putOplogIndex(ctx context.Context, factory common.ObjectStorageFactory, bucket, prefix string, index *oplogIndex) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	return factory.PutObject(ctx, bucket, path.Join(prefix, oplogIndexObject), bytes.NewReader(data))
+}
+
+// getOplogWindow returns the timestamps of the oldest and the newest entry of the oplog.
+func getOplogWindow(ctx context.Context, client *mongo.Client) (primitive.Timestamp, primitive.Timestamp, error) {
+	oplog := client.Database("local").Collection("oplog.rs")
+
+	entryAt := func(order int) (primitive.Timestamp, error) {
+		var entry struct {
+			TS primitive.Timestamp `bson:"ts"`
+		}
+
+		opts := options.FindOne().
+			SetSort(bson.D{{Key: "$natural", Value: order}}).
+			SetProjection(bson.D{{Key: "ts", Value: 1}})
+		if err := oplog.FindOne(ctx, bson.D{}, opts).Decode(&entry); err != nil {
+			return primitive.Timestamp{}, err
+		}
+
+		return entry.TS, nil
+	}
+
+	first, err := entryAt(1)
+	if err != nil {
+		return primitive.Timestamp{}, primitive.Timestamp{}, err
+	}
+	last, err := entryAt(-1)
+	if err != nil {
+		return primitive.Timestamp{}, primitive.Timestamp{}, err
+	}
+
+	return first, last, nil
+}
+
+// backupFilterArgs returns the mongodump arguments selecting what is backed
+// up, a full backup carries the oplog.
+func backupFilterArgs(req *BackupRequest) ([]string, error) {
+	if req.GetDatabase() == "" {
+		if req.GetCollection() != "" || len(req.GetExcludeCollections()) > 0 {
+			return nil, errors.New("collection and exclude_collections require database")
+		}
+
+		return []string{"--oplog"}, nil
+	}
+
+	if req.GetCollection() != "" && len(req.GetExcludeCollections()) > 0 {
+		return nil, errors.New("collection and exclude_collections are mutually exclusive")
+	}
+
+	args := []string{"--db", req.GetDatabase()}
+	if req.GetCollection() != "" {
+		args = append(args, "--collection", req.GetCollection())
+	}
+	for _, collection := range req.GetExcludeCollections() {
+		if collection == "" {
+			return nil, errors.New("exclude_collections must not contain empty names")
+		}
+		args = append(args, "--excludeCollection", collection)
+	}
+
+	return args, nil
+}
+
+// restoreFilterArgs returns the mongorestore arguments selecting what is
+// restored and how.
+func restoreFilterArgs(req *RestoreRequest) ([]string, error) {
+	var args []string
+	if !req.GetKeepExisting() {
+		args = append(args, "--drop")
+	}
+
+	for _, ns := range req.GetNsInclude() {
+		if ns == "" {
+			return nil, errors.New("ns_include must not contain empty namespaces")
+		}
+		args = append(args, "--nsInclude", ns)
+	}
+	for _, ns := range req.GetNsExclude() {
+		if ns == "" {
+			return nil, errors.New("ns_exclude must not contain empty namespaces")
+		}
+		args = append(args, "--nsExclude", ns)
+	}
+	for _, rename := range req.GetNsRenames() {
+		if rename.GetFrom() == "" || rename.GetTo() == "" {
+			return nil, errors.New("ns_renames require both from and to")
+		}
+		args = append(args, "--nsFrom", rename.GetFrom(), "--nsTo", rename.GetTo())
+	}
+
+	return args, nil
+}
+
+// parseOplogRange returns the archived oplog the restore replays, nil when
+// the restore stops at the backup.
+func parseOplogRange(req *RestoreRequest) (*oplogRange, error) {
+	prefix := strings.Trim(req.GetOplogPrefix(), "/")
+	if prefix == "" {
+		if req.GetOplogLimit() != "" || req.GetOplogStart() != "" {
+			return nil, errors.New("oplog_limit and oplog_start require oplog_prefix")
+		}
+
+		return nil, nil
+	}
+
+	if req.GetOplogLimit() == "" {
+		return nil, errors.New("oplog_prefix requires oplog_limit")
+	}
+
+	// The oplog holds every namespace, a partial restore replayed with it
+	// would bring back what the filters left out.
+	if len(req.GetNsInclude()) > 0 || len(req.GetNsExclude()) > 0 || len(req.GetNsRenames()) > 0 {
+		return nil, errors.New("oplog replay restores the whole deployment, ns filters and renames are not supported")
+	}
+
+	limit, err := parseOplogTimestamp(req.GetOplogLimit())
+	if err != nil {
+		return nil, fmt.Errorf("invalid oplog_limit: %v", err)
+	}
+
+	r := &oplogRange{prefix: prefix, limit: limit}
+	if req.GetOplogStart() != "" {
+		if r.start, err = parseOplogTimestamp(req.GetOplogStart()); err != nil {
+			return nil, fmt.Errorf("invalid oplog_start: %v", err)
+		}
+		if !r.start.Before(limit) {
+			return nil, errors.New("oplog_start must be before oplog_limit")
+		}
+	}
+
+	return r, nil
+}
+
+// parseOplogTimestamp parses "<seconds>[:<ordinal>]", the --oplogLimit format.
+func parseOplogTimestamp(s string) (primitive.Timestamp, error) {
+	seconds, ordinal, found := strings.Cut(s, ":")
+
+	t, err := strconv.ParseUint(seconds, 10, 32)
+	if err != nil {
+		return primitive.Timestamp{}, fmt.Errorf("timestamp %q is not <seconds>[:<ordinal>]", s)
+	}
+
+	var i uint64
+	if found {
+		if i, err = strconv.ParseUint(ordinal, 10, 32); err != nil {
+			return primitive.Timestamp{}, fmt.Errorf("timestamp %q is not <seconds>[:<ordinal>]", s)
+		}
+	}
+
+	return primitive.Timestamp{T: uint32(t), I: uint32(i)}, nil
+}
+
+func formatOplogTimestamp(ts primitive.Timestamp) string {
+	return fmt.Sprintf("%d:%d", ts.T, ts.I)
+}
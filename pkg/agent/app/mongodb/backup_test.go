@@ -0,0 +1,202 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBackupFilterArgs(t *testing.T) {
+	args, err := backupFilterArgs(&BackupRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"--oplog"}, args)
+
+	args, err = backupFilterArgs(&BackupRequest{Database: "app", Collection: "orders"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"--db", "app", "--collection", "orders"}, args)
+
+	args, err = backupFilterArgs(&BackupRequest{Database: "app", ExcludeCollections: []string{"logs", "tmp"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"--db", "app", "--excludeCollection", "logs", "--excludeCollection", "tmp"}, args)
+
+	for _, req := range []*BackupRequest{
+		{Collection: "orders"},
+		{ExcludeCollections: []string{"logs"}},
+		{Database: "app", Collection: "orders", ExcludeCollections: []string{"logs"}},
+		{Database: "app", ExcludeCollections: []string{""}},
+	} {
+		_, err := backupFilterArgs(req)
+		require.Error(t, err, "%v", req)
+	}
+}
+
+func TestRestoreFilterArgs(t *testing.T) {
+	args, err := restoreFilterArgs(&RestoreRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"--drop"}, args)
+
+	args, err = restoreFilterArgs(&RestoreRequest{
+		NsInclude:    []string{"app.*"},
+		NsExclude:    []string{"app.logs"},
+		NsRenames:    []*NamespaceRename{{From: "app.$coll$", To: "app_copy.$coll$"}},
+		KeepExisting: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"--nsInclude", "app.*",
+		"--nsExclude", "app.logs",
+		"--nsFrom", "app.$coll$", "--nsTo", "app_copy.$coll$",
+	}, args)
+
+	for _, req := range []*RestoreRequest{
+		{NsInclude: []string{""}},
+		{NsExclude: []string{""}},
+		{NsRenames: []*NamespaceRename{{From: "app.orders"}}},
+	} {
+		_, err := restoreFilterArgs(req)
+		require.Error(t, err, "%v", req)
+	}
+}
+
+func TestParseOplogRange(t *testing.T) {
+	r, err := parseOplogRange(&RestoreRequest{})
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	r, err = parseOplogRange(&RestoreRequest{OplogPrefix: "/oplog/demo/", OplogLimit: "1700000100:2", OplogStart: "1700000000"})
+	require.NoError(t, err)
+	require.Equal(t, &oplogRange{
+		prefix: "oplog/demo",
+		start:  primitive.Timestamp{T: 1700000000},
+		limit:  primitive.Timestamp{T: 1700000100, I: 2},
+	}, r)
+
+	for _, req := range []*RestoreRequest{
+		{OplogLimit: "1700000100"},
+		{OplogStart: "1700000000"},
+		{OplogPrefix: "oplog"},
+		{OplogPrefix: "oplog", OplogLimit: "yesterday"},
+		{OplogPrefix: "oplog", OplogLimit: "1700000100:x"},
+		{OplogPrefix: "oplog", OplogLimit: "1700000100", OplogStart: "1700000100"},
+		{OplogPrefix: "oplog", OplogLimit: "1700000100", NsInclude: []string{"app.*"}},
+	} {
+		_, err := parseOplogRange(req)
+		require.Error(t, err, "%v", req)
+	}
+}
+
+func TestSelectOplogChunks(t *testing.T) {
+	chunks := []oplogChunk{
+		{Object: "oplog/oplog-100-0.bson", Start: "50:1", End: "100:0"},
+		{Object: "oplog/oplog-200-3.bson", Start: "100:0", End: "200:3"},
+		{Object: "oplog/oplog-300-0.bson", Start: "200:3", End: "300:0"},
+	}
+	objects := func(chunks []oplogChunk) []string {
+		var out []string
+		for _, chunk := range chunks {
+			out = append(out, chunk.Object)
+		}
+		return out
+	}
+
+	selected, err := selectOplogChunks(chunks, primitive.Timestamp{}, primitive.Timestamp{T: 150})
+	require.NoError(t, err)
+	require.Equal(t, []string{"oplog/oplog-100-0.bson", "oplog/oplog-200-3.bson"}, objects(selected))
+
+	// the chunks ending before the backup are skipped
+	selected, err = selectOplogChunks(chunks, primitive.Timestamp{T: 120}, primitive.Timestamp{T: 250})
+	require.NoError(t, err)
+	require.Equal(t, []string{"oplog/oplog-200-3.bson", "oplog/oplog-300-0.bson"}, objects(selected))
+
+	// the archive ends before the limit
+	_, err = selectOplogChunks(chunks, primitive.Timestamp{}, primitive.Timestamp{T: 301})
+	require.Error(t, err)
+
+	// the archive starts after the backup
+	_, err = selectOplogChunks(chunks, primitive.Timestamp{T: 10}, primitive.Timestamp{T: 150})
+	require.Error(t, err)
+
+	// a chunk is missing
+	_, err = selectOplogChunks([]oplogChunk{chunks[0], chunks[2]}, primitive.Timestamp{}, primitive.Timestamp{T: 250})
+	require.Error(t, err)
+}
+
+func TestOplogTimestamp(t *testing.T) {
+	ts, err := parseOplogTimestamp("1700000000")
+	require.NoError(t, err)
+	require.Equal(t, primitive.Timestamp{T: 1700000000}, ts)
+
+	ts, err = parseOplogTimestamp("1700000000:12")
+	require.NoError(t, err)
+	require.Equal(t, "1700000000:12", formatOplogTimestamp(ts))
+
+	for _, s := range []string{"", ":1", "1:", "-1", "4294967296", "1:2:3"} {
+		_, err := parseOplogTimestamp(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestRunOplogArchiver(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	archive := func(context.Context) (*ArchiveOplogResponse, error) {
+		runs++
+		switch runs {
+		case 2:
+			return nil, errors.New("mongodump failed")
+		case 4:
+			cancel()
+			return nil, ctx.Err()
+		}
+		return &ArchiveOplogResponse{End: fmt.Sprintf("%d:1", runs), Chunks: int64(runs)}, nil
+	}
+
+	var progress []string
+	resp, err := runOplogArchiver(ctx, time.Millisecond, archive, func(p string, _ error) {
+		progress = append(progress, p)
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, "3:1", resp.GetEnd())
+	require.Equal(t, []string{
+		"archived up to 1:1 in 1 chunks",
+		"archived up to 1:1 in 1 chunks, last run failed: mongodump failed",
+		"archived up to 3:1 in 3 chunks",
+	}, progress)
+}
+
+func TestRunOplogArchiverStopsOnGap(t *testing.T) {
+	archive := func(context.Context) (*ArchiveOplogResponse, error) {
+		return nil, fmt.Errorf("%w: oplog starts at 200:0", errOplogOverwritten)
+	}
+
+	_, err := runOplogArchiver(context.Background(), time.Millisecond, archive, func(string, error) {
+		t.Fatal("a gap is not retried")
+	})
+	require.ErrorIs(t, err, errOplogOverwritten)
+}
+
+func TestOplogArchiversAcquire(t *testing.T) {
+	archivers := &oplogArchivers{}
+
+	release, err := archivers.acquire("oplog/demo")
+	require.NoError(t, err)
+
+	_, err = archivers.acquire("oplog/demo")
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = archivers.acquire("oplog/other")
+	require.NoError(t, err)
+
+	release()
+	_, err = archivers.acquire("oplog/demo")
+	require.NoError(t, err)
+}
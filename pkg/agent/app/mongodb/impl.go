@@ -2,13 +2,17 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"os/exec"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/upmio/unit-operator/pkg/agent/app"
 	"github.com/upmio/unit-operator/pkg/agent/app/common"
 	"github.com/upmio/unit-operator/pkg/agent/app/slm"
@@ -27,6 +31,8 @@ import (
 var (
 	// service instance
 	svr = &service{}
+
+	parameterNameRE = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.]*$`)
 )
 
 type service struct {
@@ -39,6 +45,10 @@ type service struct {
 	namespace        string
 	serviceName      string
 	serviceGroupName string
+
+	// oplogMu serializes the oplog chunks archived, oplogArchivers the long running archivers
+	oplogMu        sync.Mutex
+	oplogArchivers oplogArchivers
 }
 
 func (s *service) Config() error {
@@ -83,16 +93,23 @@ func (s *service) Registry(server *grpc.Server) {
 	RegisterMongoDBOperationServer(server, svr)
 }
 
-func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty, error) {
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterMongoDBOperationHandler(ctx, mux, conn)
+}
+
+func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.BackupResponse, error) {
 	util.LogRequestSafely(s.logger, "mongodb backup", map[string]interface{}{
-		"username":    req.GetUsername(),
-		"backup_file": req.GetBackupFile(),
-		"bucket":      req.GetObjectStorage().GetBucket(),
-		"endpoint":    req.GetObjectStorage().GetEndpoint(),
-		"access_key":  req.GetObjectStorage().GetAccessKey(),
-		"secret_key":  req.GetObjectStorage().GetSecretKey(),
-		"ssl":         req.GetObjectStorage().GetSsl(),
-		"type":        req.GetObjectStorage().GetType(),
+		"username":            req.GetUsername(),
+		"backup_file":         req.GetBackupFile(),
+		"bucket":              req.GetObjectStorage().GetBucket(),
+		"endpoint":            req.GetObjectStorage().GetEndpoint(),
+		"access_key":          req.GetObjectStorage().GetAccessKey(),
+		"secret_key":          req.GetObjectStorage().GetSecretKey(),
+		"ssl":                 req.GetObjectStorage().GetSsl(),
+		"type":                req.GetObjectStorage().GetType(),
+		"database":            req.GetDatabase(),
+		"collection":          req.GetCollection(),
+		"exclude_collections": req.GetExcludeCollections(),
 	})
 
 	// Check process is started
@@ -101,6 +118,14 @@ func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty
 		return nil, err
 	}
 
+	start := time.Now()
+
+	filters, err := backupFilterArgs(req)
+	if err != nil {
+		s.logger.Errorw("invalid backup request", zap.Error(err))
+		return nil, err
+	}
+
 	password, err := util.DecryptPlainTextPassword(req.GetUsername())
 	if err != nil {
 		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
@@ -120,15 +145,15 @@ func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty
 	//                  demo-mongodb-6rn-2.demo-mongodb-6rn-headless-svc.demo:27017/ \
 	//                  ?replicaSet=demo&readPreference=secondaryPreferred" \
 	//                  --oplog --gzip --archive=/backup/mongodb-backup-xxxxxxxx --numParallelCollections=4
+	//
+	// A selective backup replaces --oplog with --db, --collection and --excludeCollection.
 
-	cmd := exec.CommandContext(ctx,
-		"mongodump",
+	args := []string{
 		"--uri", fmt.Sprintf("mongodb://%s:%s@%s/?replicaSet=%s&readPreference=secondaryPreferred", req.GetUsername(), password, uri, s.serviceGroupName),
-		"--oplog",
-		"--gzip",
-		"--archive",
-		"--numParallelCollections=4",
-	)
+	}
+	args = append(args, filters...)
+	args = append(args, "--gzip", "--archive", "--numParallelCollections=4")
+	cmd := exec.CommandContext(ctx, "mongodump", args...)
 
 	executor := common.NewCommandExecutor(s.logger)
 	factory, err := req.GetObjectStorage().GenerateFactory()
@@ -137,25 +162,38 @@ func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty
 		return nil, err
 	}
 
-	if err := executor.ExecuteCommandStreamToS3(ctx, cmd, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "backup"); err != nil {
+	size, err := executor.ExecuteCommandStreamToS3(ctx, cmd, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "backup")
+	if err != nil {
 		s.logger.Errorw("failed to execute backup", zap.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("backup mongodb successfully")
-	return nil, nil
+	s.logger.Infow("backup mongodb successfully", "size_bytes", size)
+	return &common.BackupResponse{
+		Bucket:     req.GetObjectStorage().GetBucket(),
+		Object:     req.GetBackupFile(),
+		SizeBytes:  size,
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
 }
 
-func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Empty, error) {
+func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.RestoreResponse, error) {
 	util.LogRequestSafely(s.logger, "mongodb restore", map[string]interface{}{
-		"username":    req.GetUsername(),
-		"backup_file": req.GetBackupFile(),
-		"bucket":      req.GetObjectStorage().GetBucket(),
-		"endpoint":    req.GetObjectStorage().GetEndpoint(),
-		"access_key":  req.GetObjectStorage().GetAccessKey(),
-		"secret_key":  req.GetObjectStorage().GetSecretKey(),
-		"ssl":         req.GetObjectStorage().GetSsl(),
-		"type":        req.GetObjectStorage().GetType(),
+		"username":      req.GetUsername(),
+		"backup_file":   req.GetBackupFile(),
+		"bucket":        req.GetObjectStorage().GetBucket(),
+		"endpoint":      req.GetObjectStorage().GetEndpoint(),
+		"access_key":    req.GetObjectStorage().GetAccessKey(),
+		"secret_key":    req.GetObjectStorage().GetSecretKey(),
+		"ssl":           req.GetObjectStorage().GetSsl(),
+		"type":          req.GetObjectStorage().GetType(),
+		"ns_include":    req.GetNsInclude(),
+		"ns_exclude":    req.GetNsExclude(),
+		"ns_renames":    req.GetNsRenames(),
+		"keep_existing": req.GetKeepExisting(),
+		"oplog_prefix":  req.GetOplogPrefix(),
+		"oplog_limit":   req.GetOplogLimit(),
+		"oplog_start":   req.GetOplogStart(),
 	})
 
 	// Check process is started
@@ -164,6 +202,20 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 		return nil, err
 	}
 
+	start := time.Now()
+
+	filters, err := restoreFilterArgs(req)
+	if err != nil {
+		s.logger.Errorw("invalid restore request", zap.Error(err))
+		return nil, err
+	}
+
+	oplogRange, err := parseOplogRange(req)
+	if err != nil {
+		s.logger.Errorw("invalid restore request", zap.Error(err))
+		return nil, err
+	}
+
 	password, err := util.DecryptPlainTextPassword(req.GetUsername())
 	if err != nil {
 		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
@@ -175,13 +227,13 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 	// 					--drop \
 	// 					--gzip \
 	// 					--archive
-	cmd := exec.CommandContext(ctx,
-		"mongorestore",
-		"--uri", fmt.Sprintf("mongodb://%s:%s@127.0.0.1:27017/?replicaSet=%s", req.GetUsername(), password, s.serviceGroupName),
-		"--drop",
-		"--gzip",
-		"--archive",
-	)
+	//
+	// A selective restore adds --nsInclude, --nsExclude and --nsFrom/--nsTo, keep_existing leaves out --drop.
+	uri := fmt.Sprintf("mongodb://%s:%s@127.0.0.1:27017/?replicaSet=%s", req.GetUsername(), password, s.serviceGroupName)
+	args := []string{"--uri", uri}
+	args = append(args, filters...)
+	args = append(args, "--gzip", "--archive")
+	cmd := exec.CommandContext(ctx, "mongorestore", args...)
 
 	executor := common.NewCommandExecutor(s.logger)
 	factory, err := req.GetObjectStorage().GenerateFactory()
@@ -189,13 +241,30 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 		s.logger.Errorw("failed to generate storage factory", zap.Error(err))
 		return nil, err
 	}
-	if err := executor.ExecuteCommandStreamFromS3(ctx, cmd, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "restore"); err != nil {
+	size, err := executor.ExecuteCommandStreamFromS3(ctx, cmd, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "restore")
+	if err != nil {
 		s.logger.Errorw("failed to execute restore", zap.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("restore mongodb successfully")
-	return nil, nil
+	resp := &common.RestoreResponse{
+		Bucket:    req.GetObjectStorage().GetBucket(),
+		Object:    req.GetBackupFile(),
+		SizeBytes: size,
+	}
+
+	if oplogRange != nil {
+		if err := s.replayOplog(ctx, executor, factory, req.GetObjectStorage().GetBucket(), uri, oplogRange); err != nil {
+			s.logger.Errorw("failed to replay oplog", zap.Error(err), zap.String("oplog_prefix", req.GetOplogPrefix()))
+			return nil, err
+		}
+		resp.Position = formatOplogTimestamp(oplogRange.limit)
+	}
+
+	resp.DurationMs = time.Since(start).Milliseconds()
+
+	s.logger.Infow("restore mongodb successfully", "size_bytes", resp.GetSizeBytes(), "position", resp.GetPosition())
+	return resp, nil
 }
 
 func (s *service) getMongoDBURL(ctx context.Context) (string, error) {
@@ -210,14 +279,19 @@ func (s *service) getMongoDBURL(ctx context.Context) (string, error) {
 
 	uri := make([]string, 0)
 	for _, pod := range obj.Items {
-		endpoint := fmt.Sprintf("%s.%s-headless-svc.%s:27017", pod.GetName(), s.serviceName, pod.GetNamespace())
-		uri = append(uri, endpoint)
+		uri = append(uri, s.memberHost(pod.GetName()))
 	}
 
 	return strings.Join(uri, ","), nil
 }
 
-func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.Empty, error) {
+// memberHost returns the address of the pod behind the headless service, as
+// the replica set members know each other.
+func (s *service) memberHost(podName string) string {
+	return fmt.Sprintf("%s.%s-headless-svc.%s:27017", podName, s.serviceName, s.namespace)
+}
+
+func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.SetVariableResponse, error) {
 	util.LogRequestSafely(s.logger, "mongodb set variable", map[string]interface{}{
 		"key":      req.GetKey(),
 		"value":    req.GetValue(),
@@ -231,6 +305,12 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 		return nil, err
 	}
 
+	if !parameterNameRE.MatchString(req.GetKey()) {
+		err := fmt.Errorf("invalid parameter name %q", req.GetKey())
+		s.logger.Errorw("invalid set variable request", zap.Error(err))
+		return nil, err
+	}
+
 	// Create mongo connection
 	client, err := s.newMongoClient(ctx, req.GetUsername())
 	if err != nil {
@@ -238,12 +318,27 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 	}
 	defer s.closeMongoClient(ctx, client)
 
-	val, err := s.parseValueByType(req.GetType(), req.GetValue())
+	// Unknown parameters are rejected, the value type defaults to the type of the current value
+	var current bson.M
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "getParameter", Value: 1}, {Key: req.GetKey(), Value: 1}}).Decode(&current); err != nil {
+		s.logger.Errorw("unknown parameter", zap.Error(err), zap.String("key", req.GetKey()))
+		return nil, fmt.Errorf("unknown parameter %q: %v", req.GetKey(), err)
+	}
+
+	typ := req.GetType()
+	if typ == "" {
+		if typ, err = parameterType(current[req.GetKey()]); err != nil {
+			s.logger.Errorw("invalid set variable request", zap.Error(err), zap.String("key", req.GetKey()))
+			return nil, err
+		}
+	}
+
+	val, err := s.parseValueByType(typ, req.GetValue())
 	if err != nil {
 		s.logger.Errorw("invalid parameter value",
 			zap.Error(err),
 			zap.String("key", req.GetKey()),
-			zap.String("type", req.GetType()),
+			zap.String("type", typ),
 			zap.String("value", req.GetValue()),
 		)
 		return nil, err
@@ -267,10 +362,274 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 		return nil, err
 	}
 
-	s.logger.Info("set variable successfully")
+	// Read the value back, mongod converts it to the type of the parameter
+	var after bson.M
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "getParameter", Value: 1}, {Key: req.GetKey(), Value: 1}}).Decode(&after); err != nil {
+		s.logger.Errorw("failed to get parameter", zap.Error(err), zap.String("key", req.GetKey()))
+		return nil, err
+	}
+
+	// setParameter only accepts runtime parameters and does not write mongod.conf
+	result := common.NewSetVariableResponse(fmt.Sprint(current[req.GetKey()]), fmt.Sprint(after[req.GetKey()]))
+
+	s.logger.Infow("set variable successfully", "changed", result.GetChanged())
+	return result, nil
+}
+
+func (s *service) SetVariables(ctx context.Context, req *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	util.LogRequestSafely(s.logger, "mongodb set variables", map[string]interface{}{
+		"variables": req.GetVariables(),
+		"types":     req.GetTypes(),
+		"username":  req.GetUsername(),
+		"dry_run":   req.GetDryRun(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if len(req.GetVariables()) == 0 {
+		err := fmt.Errorf("variables is required")
+		s.logger.Errorw("invalid set variables request", zap.Error(err))
+		return nil, err
+	}
+	for key := range req.GetVariables() {
+		if !parameterNameRE.MatchString(key) {
+			err := fmt.Errorf("invalid parameter name %q", key)
+			s.logger.Errorw("invalid set variables request", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// Create mongo connection
+	client, err := s.newMongoClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeMongoClient(ctx, client)
+
+	// Read the current values, the value type defaults to the type of the current value
+	resp := &common.SetVariablesResponse{DryRun: req.GetDryRun()}
+	keys := common.SortedVariableKeys(req.GetVariables())
+	cmd := bson.D{{Key: "setParameter", Value: 1}}
+	for _, key := range keys {
+		value := req.GetVariables()[key]
+
+		var current bson.M
+		err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "getParameter", Value: 1}, {Key: key, Value: 1}}).Decode(&current)
+		result := common.NewVariableResult(key, fmt.Sprint(current[key]), value)
+		resp.Results = append(resp.Results, result)
+		if err != nil {
+			result.CurrentValue = ""
+			result.Error = fmt.Sprintf("unknown parameter: %v", err)
+			continue
+		}
+
+		typ := req.GetTypes()[key]
+		if typ == "" {
+			if typ, err = parameterType(current[key]); err != nil {
+				result.Error = err.Error()
+				continue
+			}
+		}
+
+		val, err := s.parseValueByType(typ, value)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+		cmd = append(cmd, bson.E{Key: key, Value: val})
+	}
+
+	if req.GetDryRun() || resp.Failed() {
+		return resp, nil
+	}
+
+	// setParameter accepts every parameter of the batch in a single command
+	var setResp bson.M
+	if err := client.Database("admin").RunCommand(ctx, cmd).Decode(&setResp); err != nil {
+		s.logger.Errorw("failed to set parameters", zap.Error(err))
+		return nil, fmt.Errorf("failed to set parameters, %v", err)
+	}
+	if ok, _ := setResp["ok"].(float64); ok != 1 {
+		err = fmt.Errorf("set parameters not ok: resp=%v", setResp)
+		s.logger.Errorw("failed to set parameters", zap.Error(err))
+		return nil, err
+	}
+	resp.MarkApplied()
+
+	s.logger.Info("set variables successfully")
+	return resp, nil
+}
+
+func (s *service) CreateUser(ctx context.Context, req *CreateUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mongodb create user", map[string]interface{}{
+		"username":      req.GetUsername(),
+		"user":          req.GetUser(),
+		"auth_database": req.GetAuthDatabase(),
+		"roles":         req.GetRoles(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" || req.GetPassword() == "" {
+		err := fmt.Errorf("user and password are required")
+		s.logger.Errorw("invalid create user request", zap.Error(err))
+		return nil, err
+	}
+
+	roles, err := buildUserRoles(req.GetRoles())
+	if err != nil {
+		s.logger.Errorw("invalid roles", zap.Error(err))
+		return nil, err
+	}
+
+	// Create mongo connection, commands are routed to the primary
+	client, err := s.newMongoClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeMongoClient(ctx, client)
+
+	db := client.Database(authDatabase(req.GetAuthDatabase()))
+
+	var info bson.M
+	if err := db.RunCommand(ctx, bson.D{{Key: "usersInfo", Value: req.GetUser()}}).Decode(&info); err != nil {
+		s.logger.Errorw("failed to query user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	verb := "createUser"
+	if users, ok := info["users"].(bson.A); ok && len(users) > 0 {
+		verb = "updateUser"
+	}
+
+	cmd := bson.D{
+		{Key: verb, Value: req.GetUser()},
+		{Key: "pwd", Value: req.GetPassword()},
+		{Key: "roles", Value: roles},
+	}
+	if err := db.RunCommand(ctx, cmd).Err(); err != nil {
+		s.logger.Errorw("failed to create user", zap.Error(err), zap.String("user", req.GetUser()), zap.String("command", verb))
+		return nil, err
+	}
+
+	s.logger.Info("create user successfully")
 	return nil, nil
 }
 
+func (s *service) DropUser(ctx context.Context, req *DropUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mongodb drop user", map[string]interface{}{
+		"username":      req.GetUsername(),
+		"user":          req.GetUser(),
+		"auth_database": req.GetAuthDatabase(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" {
+		err := fmt.Errorf("user is required")
+		s.logger.Errorw("invalid drop user request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create mongo connection
+	client, err := s.newMongoClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeMongoClient(ctx, client)
+
+	err = client.Database(authDatabase(req.GetAuthDatabase())).RunCommand(ctx, bson.D{{Key: "dropUser", Value: req.GetUser()}}).Err()
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Name == "UserNotFound" {
+		s.logger.Infow("user not found, nothing to drop", "user", req.GetUser())
+		return nil, nil
+	}
+	if err != nil {
+		s.logger.Errorw("failed to drop user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	s.logger.Info("drop user successfully")
+	return nil, nil
+}
+
+func (s *service) RotatePassword(ctx context.Context, req *RotatePasswordRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mongodb rotate password", map[string]interface{}{
+		"username":      req.GetUsername(),
+		"user":          req.GetUser(),
+		"auth_database": req.GetAuthDatabase(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" || req.GetPassword() == "" {
+		err := fmt.Errorf("user and password are required")
+		s.logger.Errorw("invalid rotate password request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create mongo connection, commands are routed to the primary
+	client, err := s.newMongoClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeMongoClient(ctx, client)
+
+	// MongoDB keeps a single password per user, the previous password stops working immediately
+	cmd := bson.D{
+		{Key: "updateUser", Value: req.GetUser()},
+		{Key: "pwd", Value: req.GetPassword()},
+	}
+	if err := client.Database(authDatabase(req.GetAuthDatabase())).RunCommand(ctx, cmd).Err(); err != nil {
+		s.logger.Errorw("failed to rotate password", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	util.SetRotatedPassword(req.GetUser(), req.GetPassword())
+
+	s.logger.Info("rotate password successfully")
+	return nil, nil
+}
+
+func authDatabase(database string) string {
+	if database == "" {
+		return "admin"
+	}
+
+	return database
+}
+
+func buildUserRoles(roles []*Role) (bson.A, error) {
+	out := bson.A{}
+	for _, role := range roles {
+		if role.GetRole() == "" || role.GetDatabase() == "" {
+			return nil, fmt.Errorf("role and database are required, got role %q on database %q", role.GetRole(), role.GetDatabase())
+		}
+		out = append(out, bson.D{
+			{Key: "role", Value: role.GetRole()},
+			{Key: "db", Value: role.GetDatabase()},
+		})
+	}
+
+	return out, nil
+}
+
 func (s *service) parseValueByType(typeStr, raw string) (any, error) {
 	t := strings.ToLower(strings.TrimSpace(typeStr))
 	v := strings.TrimSpace(raw)
@@ -309,8 +668,34 @@ func (s *service) parseValueByType(typeStr, raw string) (any, error) {
 	}
 }
 
+// parameterType returns the value type of a server parameter, as accepted by parseValueByType.
+func parameterType(current any) (string, error) {
+	switch current.(type) {
+	case bool:
+		return "bool", nil
+	case int32, int64:
+		return "int", nil
+	case float64:
+		return "float", nil
+	case string:
+		return "string", nil
+	default:
+		return "", fmt.Errorf("unsupported parameter type %T, set the value type explicitly", current)
+	}
+}
+
 // newMongoClient creates a client with sane defaults.
 func (s *service) newMongoClient(ctx context.Context, username string) (*mongo.Client, error) {
+	return s.connectMongo(ctx, username, false)
+}
+
+// newDirectMongoClient creates a client talking to the local member only,
+// which also works before the replica set is initiated.
+func (s *service) newDirectMongoClient(ctx context.Context, username string) (*mongo.Client, error) {
+	return s.connectMongo(ctx, username, true)
+}
+
+func (s *service) connectMongo(ctx context.Context, username string, direct bool) (*mongo.Client, error) {
 	password, err := util.DecryptPlainTextPassword(username)
 	if err != nil {
 		s.logger.Errorw("failed to decrypt password", zap.Error(err), "username", username)
@@ -327,7 +712,8 @@ func (s *service) newMongoClient(ctx context.Context, username string) (*mongo.C
 			AuthSource: "admin",
 		}).
 		SetServerSelectionTimeout(5 * time.Second).
-		SetConnectTimeout(5 * time.Second)
+		SetConnectTimeout(5 * time.Second).
+		SetDirect(direct)
 
 	c, err := mongo.Connect(ctx, opts)
 	if err != nil {
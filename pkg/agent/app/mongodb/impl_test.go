@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/upmio/unit-operator/pkg/agent/app/common"
 	"github.com/upmio/unit-operator/pkg/agent/app/slm"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.uber.org/zap"
 )
 
@@ -55,3 +56,72 @@ func TestRestoreFailsWhenProcessNotStarted(t *testing.T) {
 
 	require.Equal(t, startErr, err)
 }
+
+func TestCreateUserFailsWhenProcessNotStarted(t *testing.T) {
+	startErr := errors.New("not running")
+	svc := newMongoServiceWithSlm(startErr, nil)
+
+	_, err := svc.CreateUser(context.Background(), &CreateUserRequest{
+		Username: "user",
+		User:     "app",
+		Password: "secret",
+	})
+
+	require.Equal(t, startErr, err)
+}
+
+func TestSetVariableRejectsInvalidParameterName(t *testing.T) {
+	svc := newMongoServiceWithSlm(nil, nil)
+
+	_, err := svc.SetVariable(context.Background(), &SetVariableRequest{
+		Username: "user",
+		Key:      "cursorTimeoutMillis: 1, shutdown",
+		Value:    "1",
+	})
+
+	require.ErrorContains(t, err, "invalid parameter name")
+}
+
+func TestRotatePasswordFailsWhenProcessNotStarted(t *testing.T) {
+	startErr := errors.New("not running")
+	svc := newMongoServiceWithSlm(startErr, nil)
+
+	_, err := svc.RotatePassword(context.Background(), &RotatePasswordRequest{
+		Username: "user",
+		User:     "backup",
+		Password: "secret",
+	})
+
+	require.Equal(t, startErr, err)
+}
+
+func TestBuildUserRoles(t *testing.T) {
+	roles, err := buildUserRoles([]*Role{{Database: "app", Role: "readWrite"}})
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+
+	_, err = buildUserRoles([]*Role{{Role: "readWrite"}})
+	require.Error(t, err)
+}
+
+func TestAuthDatabase(t *testing.T) {
+	require.Equal(t, "admin", authDatabase(""))
+	require.Equal(t, "app", authDatabase("app"))
+}
+
+func TestParameterType(t *testing.T) {
+	for current, want := range map[any]string{
+		true:        "bool",
+		int32(1):    "int",
+		int64(1):    "int",
+		float64(.5): "float",
+		"majority":  "string",
+	} {
+		got, err := parameterType(current)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+
+	_, err := parameterType(bson.M{"a": 1})
+	require.Error(t, err)
+}
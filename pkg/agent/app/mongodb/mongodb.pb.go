@@ -27,8 +27,14 @@ type BackupRequest struct {
 	BackupFile    string                 `protobuf:"bytes,1,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
 	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
 	ObjectStorage *common.ObjectStorage  `protobuf:"bytes,3,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// database limits the backup to a single database, and collection to a
+	// single collection of it, a selective backup carries no oplog
+	Database   string `protobuf:"bytes,4,opt,name=database,proto3" json:"database,omitempty"`
+	Collection string `protobuf:"bytes,5,opt,name=collection,proto3" json:"collection,omitempty"`
+	// exclude_collections are skipped, they require database
+	ExcludeCollections []string `protobuf:"bytes,6,rep,name=exclude_collections,json=excludeCollections,proto3" json:"exclude_collections,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *BackupRequest) Reset() {
@@ -82,18 +88,109 @@ func (x *BackupRequest) GetObjectStorage() *common.ObjectStorage {
 	return nil
 }
 
+func (x *BackupRequest) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *BackupRequest) GetCollection() string {
+	if x != nil {
+		return x.Collection
+	}
+	return ""
+}
+
+func (x *BackupRequest) GetExcludeCollections() []string {
+	if x != nil {
+		return x.ExcludeCollections
+	}
+	return nil
+}
+
+type NamespaceRename struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// from and to are "database.collection" patterns, as --nsFrom and --nsTo
+	From          string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NamespaceRename) Reset() {
+	*x = NamespaceRename{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NamespaceRename) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NamespaceRename) ProtoMessage() {}
+
+func (x *NamespaceRename) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NamespaceRename.ProtoReflect.Descriptor instead.
+func (*NamespaceRename) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NamespaceRename) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *NamespaceRename) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
 type RestoreRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BackupFile    string                 `protobuf:"bytes,1,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
 	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
 	ObjectStorage *common.ObjectStorage  `protobuf:"bytes,3,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
+	// ns_include and ns_exclude select the restored namespaces, as --nsInclude
+	// and --nsExclude
+	NsInclude []string           `protobuf:"bytes,4,rep,name=ns_include,json=nsInclude,proto3" json:"ns_include,omitempty"`
+	NsExclude []string           `protobuf:"bytes,5,rep,name=ns_exclude,json=nsExclude,proto3" json:"ns_exclude,omitempty"`
+	NsRenames []*NamespaceRename `protobuf:"bytes,6,rep,name=ns_renames,json=nsRenames,proto3" json:"ns_renames,omitempty"`
+	// keep_existing restores without dropping the existing collections first,
+	// documents whose _id already exists are skipped
+	KeepExisting bool `protobuf:"varint,7,opt,name=keep_existing,json=keepExisting,proto3" json:"keep_existing,omitempty"`
+	// oplog_prefix is the prefix the oplog is archived under by ArchiveOplog,
+	// the archived oplog is replayed after the backup up to oplog_limit
+	OplogPrefix string `protobuf:"bytes,8,opt,name=oplog_prefix,json=oplogPrefix,proto3" json:"oplog_prefix,omitempty"`
+	// oplog_limit is the "<seconds>[:<ordinal>]" timestamp the replay stops
+	// before, as --oplogLimit
+	OplogLimit string `protobuf:"bytes,9,opt,name=oplog_limit,json=oplogLimit,proto3" json:"oplog_limit,omitempty"`
+	// oplog_start skips the chunks archived before the backup was taken,
+	// replaying older entries is harmless but slow
+	OplogStart    string `protobuf:"bytes,10,opt,name=oplog_start,json=oplogStart,proto3" json:"oplog_start,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RestoreRequest) Reset() {
 	*x = RestoreRequest{}
-	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[1]
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -105,7 +202,7 @@ func (x *RestoreRequest) String() string {
 func (*RestoreRequest) ProtoMessage() {}
 
 func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[1]
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -118,7 +215,7 @@ func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RestoreRequest.ProtoReflect.Descriptor instead.
 func (*RestoreRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{1}
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *RestoreRequest) GetBackupFile() string {
@@ -142,6 +239,196 @@ func (x *RestoreRequest) GetObjectStorage() *common.ObjectStorage {
 	return nil
 }
 
+func (x *RestoreRequest) GetNsInclude() []string {
+	if x != nil {
+		return x.NsInclude
+	}
+	return nil
+}
+
+func (x *RestoreRequest) GetNsExclude() []string {
+	if x != nil {
+		return x.NsExclude
+	}
+	return nil
+}
+
+func (x *RestoreRequest) GetNsRenames() []*NamespaceRename {
+	if x != nil {
+		return x.NsRenames
+	}
+	return nil
+}
+
+func (x *RestoreRequest) GetKeepExisting() bool {
+	if x != nil {
+		return x.KeepExisting
+	}
+	return false
+}
+
+func (x *RestoreRequest) GetOplogPrefix() string {
+	if x != nil {
+		return x.OplogPrefix
+	}
+	return ""
+}
+
+func (x *RestoreRequest) GetOplogLimit() string {
+	if x != nil {
+		return x.OplogLimit
+	}
+	return ""
+}
+
+func (x *RestoreRequest) GetOplogStart() string {
+	if x != nil {
+		return x.OplogStart
+	}
+	return ""
+}
+
+type ArchiveOplogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	ObjectStorage *common.ObjectStorage  `protobuf:"bytes,2,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
+	// prefix holds the oplog chunks and their index, one prefix per replica set
+	Prefix string `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// interval_seconds keeps the call archiving a chunk every interval until it
+	// is cancelled, run it as an operation and stop it with CancelOperation.
+	// The progress of the operation reports the last archived timestamp.
+	IntervalSeconds int64 `protobuf:"varint,4,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ArchiveOplogRequest) Reset() {
+	*x = ArchiveOplogRequest{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveOplogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveOplogRequest) ProtoMessage() {}
+
+func (x *ArchiveOplogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveOplogRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveOplogRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ArchiveOplogRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ArchiveOplogRequest) GetObjectStorage() *common.ObjectStorage {
+	if x != nil {
+		return x.ObjectStorage
+	}
+	return nil
+}
+
+func (x *ArchiveOplogRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ArchiveOplogRequest) GetIntervalSeconds() int64 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+type ArchiveOplogResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// object is empty when no entry was written since the last chunk
+	Object        string `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	Start         string `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           string `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Chunks        int64  `protobuf:"varint,4,opt,name=chunks,proto3" json:"chunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveOplogResponse) Reset() {
+	*x = ArchiveOplogResponse{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveOplogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveOplogResponse) ProtoMessage() {}
+
+func (x *ArchiveOplogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveOplogResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveOplogResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ArchiveOplogResponse) GetObject() string {
+	if x != nil {
+		return x.Object
+	}
+	return ""
+}
+
+func (x *ArchiveOplogResponse) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *ArchiveOplogResponse) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *ArchiveOplogResponse) GetChunks() int64 {
+	if x != nil {
+		return x.Chunks
+	}
+	return 0
+}
+
 type SetVariableRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -154,7 +441,7 @@ type SetVariableRequest struct {
 
 func (x *SetVariableRequest) Reset() {
 	*x = SetVariableRequest{}
-	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[2]
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -166,7 +453,7 @@ func (x *SetVariableRequest) String() string {
 func (*SetVariableRequest) ProtoMessage() {}
 
 func (x *SetVariableRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[2]
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -179,7 +466,7 @@ func (x *SetVariableRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetVariableRequest.ProtoReflect.Descriptor instead.
 func (*SetVariableRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{2}
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SetVariableRequest) GetKey() string {
@@ -210,30 +497,1089 @@ func (x *SetVariableRequest) GetType() string {
 	return ""
 }
 
-var File_pkg_agent_app_mongodb_pb_mongodb_proto protoreflect.FileDescriptor
+type SetVariablesRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Variables map[string]string      `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Username  string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	// optional per key type, "bool" | "int" | "string" | "float", defaults to the type of the current value
+	Types         map[string]string `protobuf:"bytes,3,rep,name=types,proto3" json:"types,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DryRun        bool              `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDesc = "" +
-	"\n" +
-	"&pkg/agent/app/mongodb/pb/mongodb.proto\x12\amongodb\x1a$pkg/agent/app/common/pb/common.proto\"\x8a\x01\n" +
-	"\rBackupRequest\x12\x1f\n" +
-	"\vbackup_file\x18\x01 \x01(\tR\n" +
-	"backupFile\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\x12<\n" +
-	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\"\x8b\x01\n" +
-	"\x0eRestoreRequest\x12\x1f\n" +
-	"\vbackup_file\x18\x01 \x01(\tR\n" +
-	"backupFile\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\x12<\n" +
-	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\"l\n" +
-	"\x12SetVariableRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1a\n" +
-	"\busername\x18\x03 \x01(\tR\busername\x12\x12\n" +
-	"\x04type\x18\x04 \x01(\tR\x04type2\xb1\x01\n" +
-	"\x10MongoDBOperation\x12/\n" +
-	"\x06Backup\x12\x16.mongodb.BackupRequest\x1a\r.common.Empty\x121\n" +
-	"\aRestore\x12\x17.mongodb.RestoreRequest\x1a\r.common.Empty\x129\n" +
-	"\vSetVariable\x12\x1b.mongodb.SetVariableRequest\x1a\r.common.EmptyB6Z4github.com/upmio/unit-operator/pkg/agent/app/mongodbb\x06proto3"
+func (x *SetVariablesRequest) Reset() {
+	*x = SetVariablesRequest{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariablesRequest) ProtoMessage() {}
+
+func (x *SetVariablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariablesRequest.ProtoReflect.Descriptor instead.
+func (*SetVariablesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetVariablesRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *SetVariablesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetVariablesRequest) GetTypes() map[string]string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *SetVariablesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type Role struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Database      string                 `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Role) Reset() {
+	*x = Role{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Role) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Role) ProtoMessage() {}
+
+func (x *Role) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Role.ProtoReflect.Descriptor instead.
+func (*Role) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Role) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *Role) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	AuthDatabase  string                 `protobuf:"bytes,4,opt,name=auth_database,json=authDatabase,proto3" json:"auth_database,omitempty"`
+	Roles         []*Role                `protobuf:"bytes,5,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetAuthDatabase() string {
+	if x != nil {
+		return x.AuthDatabase
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetRoles() []*Role {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type DropUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	AuthDatabase  string                 `protobuf:"bytes,3,opt,name=auth_database,json=authDatabase,proto3" json:"auth_database,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropUserRequest) Reset() {
+	*x = DropUserRequest{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropUserRequest) ProtoMessage() {}
+
+func (x *DropUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropUserRequest.ProtoReflect.Descriptor instead.
+func (*DropUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DropUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *DropUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *DropUserRequest) GetAuthDatabase() string {
+	if x != nil {
+		return x.AuthDatabase
+	}
+	return ""
+}
+
+type RotatePasswordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	AuthDatabase  string                 `protobuf:"bytes,4,opt,name=auth_database,json=authDatabase,proto3" json:"auth_database,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotatePasswordRequest) Reset() {
+	*x = RotatePasswordRequest{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotatePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotatePasswordRequest) ProtoMessage() {}
+
+func (x *RotatePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotatePasswordRequest.ProtoReflect.Descriptor instead.
+func (*RotatePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RotatePasswordRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetAuthDatabase() string {
+	if x != nil {
+		return x.AuthDatabase
+	}
+	return ""
+}
+
+type ReplicaSetMember struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// host is the "host:port" the member is reached at by the other members
+	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	// priority and votes keep their current value, or the server default for
+	// a new member, when unset
+	Priority      *float64 `protobuf:"fixed64,2,opt,name=priority,proto3,oneof" json:"priority,omitempty"`
+	Votes         *int32   `protobuf:"varint,3,opt,name=votes,proto3,oneof" json:"votes,omitempty"`
+	Hidden        bool     `protobuf:"varint,4,opt,name=hidden,proto3" json:"hidden,omitempty"`
+	ArbiterOnly   bool     `protobuf:"varint,5,opt,name=arbiter_only,json=arbiterOnly,proto3" json:"arbiter_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplicaSetMember) Reset() {
+	*x = ReplicaSetMember{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicaSetMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicaSetMember) ProtoMessage() {}
+
+func (x *ReplicaSetMember) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicaSetMember.ProtoReflect.Descriptor instead.
+func (*ReplicaSetMember) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReplicaSetMember) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *ReplicaSetMember) GetPriority() float64 {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return 0
+}
+
+func (x *ReplicaSetMember) GetVotes() int32 {
+	if x != nil && x.Votes != nil {
+		return *x.Votes
+	}
+	return 0
+}
+
+func (x *ReplicaSetMember) GetHidden() bool {
+	if x != nil {
+		return x.Hidden
+	}
+	return false
+}
+
+func (x *ReplicaSetMember) GetArbiterOnly() bool {
+	if x != nil {
+		return x.ArbiterOnly
+	}
+	return false
+}
+
+type InitiateReplicaSetRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// members defaults to this unit only
+	Members       []*ReplicaSetMember `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InitiateReplicaSetRequest) Reset() {
+	*x = InitiateReplicaSetRequest{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InitiateReplicaSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitiateReplicaSetRequest) ProtoMessage() {}
+
+func (x *InitiateReplicaSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitiateReplicaSetRequest.ProtoReflect.Descriptor instead.
+func (*InitiateReplicaSetRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *InitiateReplicaSetRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *InitiateReplicaSetRequest) GetMembers() []*ReplicaSetMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type AddMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Member        *ReplicaSetMember      `protobuf:"bytes,2,opt,name=member,proto3" json:"member,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddMemberRequest) Reset() {
+	*x = AddMemberRequest{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddMemberRequest) ProtoMessage() {}
+
+func (x *AddMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddMemberRequest.ProtoReflect.Descriptor instead.
+func (*AddMemberRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AddMemberRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *AddMemberRequest) GetMember() *ReplicaSetMember {
+	if x != nil {
+		return x.Member
+	}
+	return nil
+}
+
+type RemoveMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Host          string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveMemberRequest) Reset() {
+	*x = RemoveMemberRequest{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveMemberRequest) ProtoMessage() {}
+
+func (x *RemoveMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveMemberRequest.ProtoReflect.Descriptor instead.
+func (*RemoveMemberRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RemoveMemberRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RemoveMemberRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+type SetMemberConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Member        *ReplicaSetMember      `protobuf:"bytes,2,opt,name=member,proto3" json:"member,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMemberConfigRequest) Reset() {
+	*x = SetMemberConfigRequest{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMemberConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMemberConfigRequest) ProtoMessage() {}
+
+func (x *SetMemberConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMemberConfigRequest.ProtoReflect.Descriptor instead.
+func (*SetMemberConfigRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SetMemberConfigRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetMemberConfigRequest) GetMember() *ReplicaSetMember {
+	if x != nil {
+		return x.Member
+	}
+	return nil
+}
+
+type StepDownRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// step_down_seconds defaults to 60 and catch_up_seconds to 10
+	StepDownSeconds int64 `protobuf:"varint,2,opt,name=step_down_seconds,json=stepDownSeconds,proto3" json:"step_down_seconds,omitempty"`
+	CatchUpSeconds  int64 `protobuf:"varint,3,opt,name=catch_up_seconds,json=catchUpSeconds,proto3" json:"catch_up_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StepDownRequest) Reset() {
+	*x = StepDownRequest{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepDownRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepDownRequest) ProtoMessage() {}
+
+func (x *StepDownRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepDownRequest.ProtoReflect.Descriptor instead.
+func (*StepDownRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StepDownRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *StepDownRequest) GetStepDownSeconds() int64 {
+	if x != nil {
+		return x.StepDownSeconds
+	}
+	return 0
+}
+
+func (x *StepDownRequest) GetCatchUpSeconds() int64 {
+	if x != nil {
+		return x.CatchUpSeconds
+	}
+	return 0
+}
+
+type ReplicaSetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplicaSetStatusRequest) Reset() {
+	*x = ReplicaSetStatusRequest{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicaSetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicaSetStatusRequest) ProtoMessage() {}
+
+func (x *ReplicaSetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicaSetStatusRequest.ProtoReflect.Descriptor instead.
+func (*ReplicaSetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReplicaSetStatusRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type MemberStatus struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Host        string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	State       string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Healthy     bool                   `protobuf:"varint,4,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	Self        bool                   `protobuf:"varint,5,opt,name=self,proto3" json:"self,omitempty"`
+	Priority    float64                `protobuf:"fixed64,6,opt,name=priority,proto3" json:"priority,omitempty"`
+	Votes       int32                  `protobuf:"varint,7,opt,name=votes,proto3" json:"votes,omitempty"`
+	Hidden      bool                   `protobuf:"varint,8,opt,name=hidden,proto3" json:"hidden,omitempty"`
+	ArbiterOnly bool                   `protobuf:"varint,9,opt,name=arbiter_only,json=arbiterOnly,proto3" json:"arbiter_only,omitempty"`
+	// optime_seconds is the unix time of the last applied operation
+	OptimeSeconds int64 `protobuf:"varint,10,opt,name=optime_seconds,json=optimeSeconds,proto3" json:"optime_seconds,omitempty"`
+	// lag_seconds is how far the member is behind the primary, healthy
+	// secondaries only
+	LagSeconds    int64  `protobuf:"varint,11,opt,name=lag_seconds,json=lagSeconds,proto3" json:"lag_seconds,omitempty"`
+	SyncSource    string `protobuf:"bytes,12,opt,name=sync_source,json=syncSource,proto3" json:"sync_source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MemberStatus) Reset() {
+	*x = MemberStatus{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemberStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemberStatus) ProtoMessage() {}
+
+func (x *MemberStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemberStatus.ProtoReflect.Descriptor instead.
+func (*MemberStatus) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *MemberStatus) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *MemberStatus) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *MemberStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *MemberStatus) GetHealthy() bool {
+	if x != nil {
+		return x.Healthy
+	}
+	return false
+}
+
+func (x *MemberStatus) GetSelf() bool {
+	if x != nil {
+		return x.Self
+	}
+	return false
+}
+
+func (x *MemberStatus) GetPriority() float64 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *MemberStatus) GetVotes() int32 {
+	if x != nil {
+		return x.Votes
+	}
+	return 0
+}
+
+func (x *MemberStatus) GetHidden() bool {
+	if x != nil {
+		return x.Hidden
+	}
+	return false
+}
+
+func (x *MemberStatus) GetArbiterOnly() bool {
+	if x != nil {
+		return x.ArbiterOnly
+	}
+	return false
+}
+
+func (x *MemberStatus) GetOptimeSeconds() int64 {
+	if x != nil {
+		return x.OptimeSeconds
+	}
+	return 0
+}
+
+func (x *MemberStatus) GetLagSeconds() int64 {
+	if x != nil {
+		return x.LagSeconds
+	}
+	return 0
+}
+
+func (x *MemberStatus) GetSyncSource() string {
+	if x != nil {
+		return x.SyncSource
+	}
+	return ""
+}
+
+type ReplicaSetStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Initiated     bool                   `protobuf:"varint,1,opt,name=initiated,proto3" json:"initiated,omitempty"`
+	Set           string                 `protobuf:"bytes,2,opt,name=set,proto3" json:"set,omitempty"`
+	ConfigVersion int64                  `protobuf:"varint,3,opt,name=config_version,json=configVersion,proto3" json:"config_version,omitempty"`
+	Primary       string                 `protobuf:"bytes,4,opt,name=primary,proto3" json:"primary,omitempty"`
+	Members       []*MemberStatus        `protobuf:"bytes,5,rep,name=members,proto3" json:"members,omitempty"`
+	MaxLagSeconds int64                  `protobuf:"varint,6,opt,name=max_lag_seconds,json=maxLagSeconds,proto3" json:"max_lag_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplicaSetStatusResponse) Reset() {
+	*x = ReplicaSetStatusResponse{}
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicaSetStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicaSetStatusResponse) ProtoMessage() {}
+
+func (x *ReplicaSetStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicaSetStatusResponse.ProtoReflect.Descriptor instead.
+func (*ReplicaSetStatusResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReplicaSetStatusResponse) GetInitiated() bool {
+	if x != nil {
+		return x.Initiated
+	}
+	return false
+}
+
+func (x *ReplicaSetStatusResponse) GetSet() string {
+	if x != nil {
+		return x.Set
+	}
+	return ""
+}
+
+func (x *ReplicaSetStatusResponse) GetConfigVersion() int64 {
+	if x != nil {
+		return x.ConfigVersion
+	}
+	return 0
+}
+
+func (x *ReplicaSetStatusResponse) GetPrimary() string {
+	if x != nil {
+		return x.Primary
+	}
+	return ""
+}
+
+func (x *ReplicaSetStatusResponse) GetMembers() []*MemberStatus {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *ReplicaSetStatusResponse) GetMaxLagSeconds() int64 {
+	if x != nil {
+		return x.MaxLagSeconds
+	}
+	return 0
+}
+
+var File_pkg_agent_app_mongodb_pb_mongodb_proto protoreflect.FileDescriptor
+
+const file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDesc = "" +
+	"\n" +
+	"&pkg/agent/app/mongodb/pb/mongodb.proto\x12\amongodb\x1a$pkg/agent/app/common/pb/common.proto\"\xf7\x01\n" +
+	"\rBackupRequest\x12\x1f\n" +
+	"\vbackup_file\x18\x01 \x01(\tR\n" +
+	"backupFile\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12<\n" +
+	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\x12\x1a\n" +
+	"\bdatabase\x18\x04 \x01(\tR\bdatabase\x12\x1e\n" +
+	"\n" +
+	"collection\x18\x05 \x01(\tR\n" +
+	"collection\x12/\n" +
+	"\x13exclude_collections\x18\x06 \x03(\tR\x12excludeCollections\"5\n" +
+	"\x0fNamespaceRename\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\"\x8c\x03\n" +
+	"\x0eRestoreRequest\x12\x1f\n" +
+	"\vbackup_file\x18\x01 \x01(\tR\n" +
+	"backupFile\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12<\n" +
+	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\x12\x1d\n" +
+	"\n" +
+	"ns_include\x18\x04 \x03(\tR\tnsInclude\x12\x1d\n" +
+	"\n" +
+	"ns_exclude\x18\x05 \x03(\tR\tnsExclude\x127\n" +
+	"\n" +
+	"ns_renames\x18\x06 \x03(\v2\x18.mongodb.NamespaceRenameR\tnsRenames\x12#\n" +
+	"\rkeep_existing\x18\a \x01(\bR\fkeepExisting\x12!\n" +
+	"\foplog_prefix\x18\b \x01(\tR\voplogPrefix\x12\x1f\n" +
+	"\voplog_limit\x18\t \x01(\tR\n" +
+	"oplogLimit\x12\x1f\n" +
+	"\voplog_start\x18\n" +
+	" \x01(\tR\n" +
+	"oplogStart\"\xb2\x01\n" +
+	"\x13ArchiveOplogRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12<\n" +
+	"\x0eobject_storage\x18\x02 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\x12)\n" +
+	"\x10interval_seconds\x18\x04 \x01(\x03R\x0fintervalSeconds\"n\n" +
+	"\x14ArchiveOplogResponse\x12\x16\n" +
+	"\x06object\x18\x01 \x01(\tR\x06object\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\tR\x03end\x12\x16\n" +
+	"\x06chunks\x18\x04 \x01(\x03R\x06chunks\"l\n" +
+	"\x12SetVariableRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\"\xcc\x02\n" +
+	"\x13SetVariablesRequest\x12I\n" +
+	"\tvariables\x18\x01 \x03(\v2+.mongodb.SetVariablesRequest.VariablesEntryR\tvariables\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12=\n" +
+	"\x05types\x18\x03 \x03(\v2'.mongodb.SetVariablesRequest.TypesEntryR\x05types\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"TypesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"6\n" +
+	"\x04Role\x12\x1a\n" +
+	"\bdatabase\x18\x01 \x01(\tR\bdatabase\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"\xa9\x01\n" +
+	"\x11CreateUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12#\n" +
+	"\rauth_database\x18\x04 \x01(\tR\fauthDatabase\x12#\n" +
+	"\x05roles\x18\x05 \x03(\v2\r.mongodb.RoleR\x05roles\"f\n" +
+	"\x0fDropUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12#\n" +
+	"\rauth_database\x18\x03 \x01(\tR\fauthDatabase\"\x88\x01\n" +
+	"\x15RotatePasswordRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12#\n" +
+	"\rauth_database\x18\x04 \x01(\tR\fauthDatabase\"\xb4\x01\n" +
+	"\x10ReplicaSetMember\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x1f\n" +
+	"\bpriority\x18\x02 \x01(\x01H\x00R\bpriority\x88\x01\x01\x12\x19\n" +
+	"\x05votes\x18\x03 \x01(\x05H\x01R\x05votes\x88\x01\x01\x12\x16\n" +
+	"\x06hidden\x18\x04 \x01(\bR\x06hidden\x12!\n" +
+	"\farbiter_only\x18\x05 \x01(\bR\varbiterOnlyB\v\n" +
+	"\t_priorityB\b\n" +
+	"\x06_votes\"l\n" +
+	"\x19InitiateReplicaSetRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x123\n" +
+	"\amembers\x18\x02 \x03(\v2\x19.mongodb.ReplicaSetMemberR\amembers\"a\n" +
+	"\x10AddMemberRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x121\n" +
+	"\x06member\x18\x02 \x01(\v2\x19.mongodb.ReplicaSetMemberR\x06member\"E\n" +
+	"\x13RemoveMemberRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\"g\n" +
+	"\x16SetMemberConfigRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x121\n" +
+	"\x06member\x18\x02 \x01(\v2\x19.mongodb.ReplicaSetMemberR\x06member\"\x83\x01\n" +
+	"\x0fStepDownRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12*\n" +
+	"\x11step_down_seconds\x18\x02 \x01(\x03R\x0fstepDownSeconds\x12(\n" +
+	"\x10catch_up_seconds\x18\x03 \x01(\x03R\x0ecatchUpSeconds\"5\n" +
+	"\x17ReplicaSetStatusRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"\xcc\x02\n" +
+	"\fMemberStatus\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x18\n" +
+	"\ahealthy\x18\x04 \x01(\bR\ahealthy\x12\x12\n" +
+	"\x04self\x18\x05 \x01(\bR\x04self\x12\x1a\n" +
+	"\bpriority\x18\x06 \x01(\x01R\bpriority\x12\x14\n" +
+	"\x05votes\x18\a \x01(\x05R\x05votes\x12\x16\n" +
+	"\x06hidden\x18\b \x01(\bR\x06hidden\x12!\n" +
+	"\farbiter_only\x18\t \x01(\bR\varbiterOnly\x12%\n" +
+	"\x0eoptime_seconds\x18\n" +
+	" \x01(\x03R\roptimeSeconds\x12\x1f\n" +
+	"\vlag_seconds\x18\v \x01(\x03R\n" +
+	"lagSeconds\x12\x1f\n" +
+	"\vsync_source\x18\f \x01(\tR\n" +
+	"syncSource\"\xe4\x01\n" +
+	"\x18ReplicaSetStatusResponse\x12\x1c\n" +
+	"\tinitiated\x18\x01 \x01(\bR\tinitiated\x12\x10\n" +
+	"\x03set\x18\x02 \x01(\tR\x03set\x12%\n" +
+	"\x0econfig_version\x18\x03 \x01(\x03R\rconfigVersion\x12\x18\n" +
+	"\aprimary\x18\x04 \x01(\tR\aprimary\x12/\n" +
+	"\amembers\x18\x05 \x03(\v2\x15.mongodb.MemberStatusR\amembers\x12&\n" +
+	"\x0fmax_lag_seconds\x18\x06 \x01(\x03R\rmaxLagSeconds2\xa8\a\n" +
+	"\x10MongoDBOperation\x128\n" +
+	"\x06Backup\x12\x16.mongodb.BackupRequest\x1a\x16.common.BackupResponse\x12;\n" +
+	"\aRestore\x12\x17.mongodb.RestoreRequest\x1a\x17.common.RestoreResponse\x12G\n" +
+	"\vSetVariable\x12\x1b.mongodb.SetVariableRequest\x1a\x1b.common.SetVariableResponse\x12J\n" +
+	"\fSetVariables\x12\x1c.mongodb.SetVariablesRequest\x1a\x1c.common.SetVariablesResponse\x127\n" +
+	"\n" +
+	"CreateUser\x12\x1a.mongodb.CreateUserRequest\x1a\r.common.Empty\x123\n" +
+	"\bDropUser\x12\x18.mongodb.DropUserRequest\x1a\r.common.Empty\x12?\n" +
+	"\x0eRotatePassword\x12\x1e.mongodb.RotatePasswordRequest\x1a\r.common.Empty\x12G\n" +
+	"\x12InitiateReplicaSet\x12\".mongodb.InitiateReplicaSetRequest\x1a\r.common.Empty\x125\n" +
+	"\tAddMember\x12\x19.mongodb.AddMemberRequest\x1a\r.common.Empty\x12;\n" +
+	"\fRemoveMember\x12\x1c.mongodb.RemoveMemberRequest\x1a\r.common.Empty\x12A\n" +
+	"\x0fSetMemberConfig\x12\x1f.mongodb.SetMemberConfigRequest\x1a\r.common.Empty\x123\n" +
+	"\bStepDown\x12\x18.mongodb.StepDownRequest\x1a\r.common.Empty\x12W\n" +
+	"\x10ReplicaSetStatus\x12 .mongodb.ReplicaSetStatusRequest\x1a!.mongodb.ReplicaSetStatusResponse\x12K\n" +
+	"\fArchiveOplog\x12\x1c.mongodb.ArchiveOplogRequest\x1a\x1d.mongodb.ArchiveOplogResponseB6Z4github.com/upmio/unit-operator/pkg/agent/app/mongodbb\x06proto3"
 
 var (
 	file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescOnce sync.Once
@@ -247,28 +1593,82 @@ func file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescGZIP() []byte {
 	return file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDescData
 }
 
-var file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_pkg_agent_app_mongodb_pb_mongodb_proto_goTypes = []any{
-	(*BackupRequest)(nil),        // 0: mongodb.BackupRequest
-	(*RestoreRequest)(nil),       // 1: mongodb.RestoreRequest
-	(*SetVariableRequest)(nil),   // 2: mongodb.SetVariableRequest
-	(*common.ObjectStorage)(nil), // 3: common.ObjectStorage
-	(*common.Empty)(nil),         // 4: common.Empty
+	(*BackupRequest)(nil),               // 0: mongodb.BackupRequest
+	(*NamespaceRename)(nil),             // 1: mongodb.NamespaceRename
+	(*RestoreRequest)(nil),              // 2: mongodb.RestoreRequest
+	(*ArchiveOplogRequest)(nil),         // 3: mongodb.ArchiveOplogRequest
+	(*ArchiveOplogResponse)(nil),        // 4: mongodb.ArchiveOplogResponse
+	(*SetVariableRequest)(nil),          // 5: mongodb.SetVariableRequest
+	(*SetVariablesRequest)(nil),         // 6: mongodb.SetVariablesRequest
+	(*Role)(nil),                        // 7: mongodb.Role
+	(*CreateUserRequest)(nil),           // 8: mongodb.CreateUserRequest
+	(*DropUserRequest)(nil),             // 9: mongodb.DropUserRequest
+	(*RotatePasswordRequest)(nil),       // 10: mongodb.RotatePasswordRequest
+	(*ReplicaSetMember)(nil),            // 11: mongodb.ReplicaSetMember
+	(*InitiateReplicaSetRequest)(nil),   // 12: mongodb.InitiateReplicaSetRequest
+	(*AddMemberRequest)(nil),            // 13: mongodb.AddMemberRequest
+	(*RemoveMemberRequest)(nil),         // 14: mongodb.RemoveMemberRequest
+	(*SetMemberConfigRequest)(nil),      // 15: mongodb.SetMemberConfigRequest
+	(*StepDownRequest)(nil),             // 16: mongodb.StepDownRequest
+	(*ReplicaSetStatusRequest)(nil),     // 17: mongodb.ReplicaSetStatusRequest
+	(*MemberStatus)(nil),                // 18: mongodb.MemberStatus
+	(*ReplicaSetStatusResponse)(nil),    // 19: mongodb.ReplicaSetStatusResponse
+	nil,                                 // 20: mongodb.SetVariablesRequest.VariablesEntry
+	nil,                                 // 21: mongodb.SetVariablesRequest.TypesEntry
+	(*common.ObjectStorage)(nil),        // 22: common.ObjectStorage
+	(*common.BackupResponse)(nil),       // 23: common.BackupResponse
+	(*common.RestoreResponse)(nil),      // 24: common.RestoreResponse
+	(*common.SetVariableResponse)(nil),  // 25: common.SetVariableResponse
+	(*common.SetVariablesResponse)(nil), // 26: common.SetVariablesResponse
+	(*common.Empty)(nil),                // 27: common.Empty
 }
 var file_pkg_agent_app_mongodb_pb_mongodb_proto_depIdxs = []int32{
-	3, // 0: mongodb.BackupRequest.object_storage:type_name -> common.ObjectStorage
-	3, // 1: mongodb.RestoreRequest.object_storage:type_name -> common.ObjectStorage
-	0, // 2: mongodb.MongoDBOperation.Backup:input_type -> mongodb.BackupRequest
-	1, // 3: mongodb.MongoDBOperation.Restore:input_type -> mongodb.RestoreRequest
-	2, // 4: mongodb.MongoDBOperation.SetVariable:input_type -> mongodb.SetVariableRequest
-	4, // 5: mongodb.MongoDBOperation.Backup:output_type -> common.Empty
-	4, // 6: mongodb.MongoDBOperation.Restore:output_type -> common.Empty
-	4, // 7: mongodb.MongoDBOperation.SetVariable:output_type -> common.Empty
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	22, // 0: mongodb.BackupRequest.object_storage:type_name -> common.ObjectStorage
+	22, // 1: mongodb.RestoreRequest.object_storage:type_name -> common.ObjectStorage
+	1,  // 2: mongodb.RestoreRequest.ns_renames:type_name -> mongodb.NamespaceRename
+	22, // 3: mongodb.ArchiveOplogRequest.object_storage:type_name -> common.ObjectStorage
+	20, // 4: mongodb.SetVariablesRequest.variables:type_name -> mongodb.SetVariablesRequest.VariablesEntry
+	21, // 5: mongodb.SetVariablesRequest.types:type_name -> mongodb.SetVariablesRequest.TypesEntry
+	7,  // 6: mongodb.CreateUserRequest.roles:type_name -> mongodb.Role
+	11, // 7: mongodb.InitiateReplicaSetRequest.members:type_name -> mongodb.ReplicaSetMember
+	11, // 8: mongodb.AddMemberRequest.member:type_name -> mongodb.ReplicaSetMember
+	11, // 9: mongodb.SetMemberConfigRequest.member:type_name -> mongodb.ReplicaSetMember
+	18, // 10: mongodb.ReplicaSetStatusResponse.members:type_name -> mongodb.MemberStatus
+	0,  // 11: mongodb.MongoDBOperation.Backup:input_type -> mongodb.BackupRequest
+	2,  // 12: mongodb.MongoDBOperation.Restore:input_type -> mongodb.RestoreRequest
+	5,  // 13: mongodb.MongoDBOperation.SetVariable:input_type -> mongodb.SetVariableRequest
+	6,  // 14: mongodb.MongoDBOperation.SetVariables:input_type -> mongodb.SetVariablesRequest
+	8,  // 15: mongodb.MongoDBOperation.CreateUser:input_type -> mongodb.CreateUserRequest
+	9,  // 16: mongodb.MongoDBOperation.DropUser:input_type -> mongodb.DropUserRequest
+	10, // 17: mongodb.MongoDBOperation.RotatePassword:input_type -> mongodb.RotatePasswordRequest
+	12, // 18: mongodb.MongoDBOperation.InitiateReplicaSet:input_type -> mongodb.InitiateReplicaSetRequest
+	13, // 19: mongodb.MongoDBOperation.AddMember:input_type -> mongodb.AddMemberRequest
+	14, // 20: mongodb.MongoDBOperation.RemoveMember:input_type -> mongodb.RemoveMemberRequest
+	15, // 21: mongodb.MongoDBOperation.SetMemberConfig:input_type -> mongodb.SetMemberConfigRequest
+	16, // 22: mongodb.MongoDBOperation.StepDown:input_type -> mongodb.StepDownRequest
+	17, // 23: mongodb.MongoDBOperation.ReplicaSetStatus:input_type -> mongodb.ReplicaSetStatusRequest
+	3,  // 24: mongodb.MongoDBOperation.ArchiveOplog:input_type -> mongodb.ArchiveOplogRequest
+	23, // 25: mongodb.MongoDBOperation.Backup:output_type -> common.BackupResponse
+	24, // 26: mongodb.MongoDBOperation.Restore:output_type -> common.RestoreResponse
+	25, // 27: mongodb.MongoDBOperation.SetVariable:output_type -> common.SetVariableResponse
+	26, // 28: mongodb.MongoDBOperation.SetVariables:output_type -> common.SetVariablesResponse
+	27, // 29: mongodb.MongoDBOperation.CreateUser:output_type -> common.Empty
+	27, // 30: mongodb.MongoDBOperation.DropUser:output_type -> common.Empty
+	27, // 31: mongodb.MongoDBOperation.RotatePassword:output_type -> common.Empty
+	27, // 32: mongodb.MongoDBOperation.InitiateReplicaSet:output_type -> common.Empty
+	27, // 33: mongodb.MongoDBOperation.AddMember:output_type -> common.Empty
+	27, // 34: mongodb.MongoDBOperation.RemoveMember:output_type -> common.Empty
+	27, // 35: mongodb.MongoDBOperation.SetMemberConfig:output_type -> common.Empty
+	27, // 36: mongodb.MongoDBOperation.StepDown:output_type -> common.Empty
+	19, // 37: mongodb.MongoDBOperation.ReplicaSetStatus:output_type -> mongodb.ReplicaSetStatusResponse
+	4,  // 38: mongodb.MongoDBOperation.ArchiveOplog:output_type -> mongodb.ArchiveOplogResponse
+	25, // [25:39] is the sub-list for method output_type
+	11, // [11:25] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_pkg_agent_app_mongodb_pb_mongodb_proto_init() }
@@ -276,13 +1676,14 @@ func file_pkg_agent_app_mongodb_pb_mongodb_proto_init() {
 	if File_pkg_agent_app_mongodb_pb_mongodb_proto != nil {
 		return
 	}
+	file_pkg_agent_app_mongodb_pb_mongodb_proto_msgTypes[11].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDesc), len(file_pkg_agent_app_mongodb_pb_mongodb_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
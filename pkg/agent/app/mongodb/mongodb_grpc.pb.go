@@ -19,9 +19,20 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MongoDBOperationClient interface {
-	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error)
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.RestoreResponse, error)
+	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error)
+	SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	DropUser(ctx context.Context, in *DropUserRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	RotatePassword(ctx context.Context, in *RotatePasswordRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	InitiateReplicaSet(ctx context.Context, in *InitiateReplicaSetRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	AddMember(ctx context.Context, in *AddMemberRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	SetMemberConfig(ctx context.Context, in *SetMemberConfigRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	StepDown(ctx context.Context, in *StepDownRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	ReplicaSetStatus(ctx context.Context, in *ReplicaSetStatusRequest, opts ...grpc.CallOption) (*ReplicaSetStatusResponse, error)
+	ArchiveOplog(ctx context.Context, in *ArchiveOplogRequest, opts ...grpc.CallOption) (*ArchiveOplogResponse, error)
 }
 
 type mongoDBOperationClient struct {
@@ -32,8 +43,8 @@ func NewMongoDBOperationClient(cc grpc.ClientConnInterface) MongoDBOperationClie
 	return &mongoDBOperationClient{cc}
 }
 
-func (c *mongoDBOperationClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *mongoDBOperationClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error) {
+	out := new(common.BackupResponse)
 	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/Backup", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -41,8 +52,8 @@ func (c *mongoDBOperationClient) Backup(ctx context.Context, in *BackupRequest,
 	return out, nil
 }
 
-func (c *mongoDBOperationClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *mongoDBOperationClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.RestoreResponse, error) {
+	out := new(common.RestoreResponse)
 	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/Restore", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -50,8 +61,8 @@ func (c *mongoDBOperationClient) Restore(ctx context.Context, in *RestoreRequest
 	return out, nil
 }
 
-func (c *mongoDBOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *mongoDBOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error) {
+	out := new(common.SetVariableResponse)
 	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/SetVariable", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -59,13 +70,123 @@ func (c *mongoDBOperationClient) SetVariable(ctx context.Context, in *SetVariabl
 	return out, nil
 }
 
+func (c *mongoDBOperationClient) SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error) {
+	out := new(common.SetVariablesResponse)
+	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/SetVariables", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mongoDBOperationClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/CreateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mongoDBOperationClient) DropUser(ctx context.Context, in *DropUserRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/DropUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mongoDBOperationClient) RotatePassword(ctx context.Context, in *RotatePasswordRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/RotatePassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mongoDBOperationClient) InitiateReplicaSet(ctx context.Context, in *InitiateReplicaSetRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/InitiateReplicaSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mongoDBOperationClient) AddMember(ctx context.Context, in *AddMemberRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/AddMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mongoDBOperationClient) RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/RemoveMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mongoDBOperationClient) SetMemberConfig(ctx context.Context, in *SetMemberConfigRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/SetMemberConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mongoDBOperationClient) StepDown(ctx context.Context, in *StepDownRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/StepDown", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mongoDBOperationClient) ReplicaSetStatus(ctx context.Context, in *ReplicaSetStatusRequest, opts ...grpc.CallOption) (*ReplicaSetStatusResponse, error) {
+	out := new(ReplicaSetStatusResponse)
+	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/ReplicaSetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mongoDBOperationClient) ArchiveOplog(ctx context.Context, in *ArchiveOplogRequest, opts ...grpc.CallOption) (*ArchiveOplogResponse, error) {
+	out := new(ArchiveOplogResponse)
+	err := c.cc.Invoke(ctx, "/mongodb.MongoDBOperation/ArchiveOplog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MongoDBOperationServer is the server API for MongoDBOperation service.
 // All implementations must embed UnimplementedMongoDBOperationServer
 // for forward compatibility
 type MongoDBOperationServer interface {
-	Backup(context.Context, *BackupRequest) (*common.Empty, error)
-	Restore(context.Context, *RestoreRequest) (*common.Empty, error)
-	SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error)
+	Backup(context.Context, *BackupRequest) (*common.BackupResponse, error)
+	Restore(context.Context, *RestoreRequest) (*common.RestoreResponse, error)
+	SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error)
+	SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error)
+	CreateUser(context.Context, *CreateUserRequest) (*common.Empty, error)
+	DropUser(context.Context, *DropUserRequest) (*common.Empty, error)
+	RotatePassword(context.Context, *RotatePasswordRequest) (*common.Empty, error)
+	InitiateReplicaSet(context.Context, *InitiateReplicaSetRequest) (*common.Empty, error)
+	AddMember(context.Context, *AddMemberRequest) (*common.Empty, error)
+	RemoveMember(context.Context, *RemoveMemberRequest) (*common.Empty, error)
+	SetMemberConfig(context.Context, *SetMemberConfigRequest) (*common.Empty, error)
+	StepDown(context.Context, *StepDownRequest) (*common.Empty, error)
+	ReplicaSetStatus(context.Context, *ReplicaSetStatusRequest) (*ReplicaSetStatusResponse, error)
+	ArchiveOplog(context.Context, *ArchiveOplogRequest) (*ArchiveOplogResponse, error)
 	mustEmbedUnimplementedMongoDBOperationServer()
 }
 
@@ -73,15 +194,48 @@ type MongoDBOperationServer interface {
 type UnimplementedMongoDBOperationServer struct {
 }
 
-func (UnimplementedMongoDBOperationServer) Backup(context.Context, *BackupRequest) (*common.Empty, error) {
+func (UnimplementedMongoDBOperationServer) Backup(context.Context, *BackupRequest) (*common.BackupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Backup not implemented")
 }
-func (UnimplementedMongoDBOperationServer) Restore(context.Context, *RestoreRequest) (*common.Empty, error) {
+func (UnimplementedMongoDBOperationServer) Restore(context.Context, *RestoreRequest) (*common.RestoreResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
 }
-func (UnimplementedMongoDBOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error) {
+func (UnimplementedMongoDBOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetVariable not implemented")
 }
+func (UnimplementedMongoDBOperationServer) SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVariables not implemented")
+}
+func (UnimplementedMongoDBOperationServer) CreateUser(context.Context, *CreateUserRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedMongoDBOperationServer) DropUser(context.Context, *DropUserRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropUser not implemented")
+}
+func (UnimplementedMongoDBOperationServer) RotatePassword(context.Context, *RotatePasswordRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotatePassword not implemented")
+}
+func (UnimplementedMongoDBOperationServer) InitiateReplicaSet(context.Context, *InitiateReplicaSetRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InitiateReplicaSet not implemented")
+}
+func (UnimplementedMongoDBOperationServer) AddMember(context.Context, *AddMemberRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddMember not implemented")
+}
+func (UnimplementedMongoDBOperationServer) RemoveMember(context.Context, *RemoveMemberRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMember not implemented")
+}
+func (UnimplementedMongoDBOperationServer) SetMemberConfig(context.Context, *SetMemberConfigRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMemberConfig not implemented")
+}
+func (UnimplementedMongoDBOperationServer) StepDown(context.Context, *StepDownRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StepDown not implemented")
+}
+func (UnimplementedMongoDBOperationServer) ReplicaSetStatus(context.Context, *ReplicaSetStatusRequest) (*ReplicaSetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplicaSetStatus not implemented")
+}
+func (UnimplementedMongoDBOperationServer) ArchiveOplog(context.Context, *ArchiveOplogRequest) (*ArchiveOplogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveOplog not implemented")
+}
 func (UnimplementedMongoDBOperationServer) mustEmbedUnimplementedMongoDBOperationServer() {}
 
 // UnsafeMongoDBOperationServer may be embedded to opt out of forward compatibility for this service.
@@ -149,6 +303,204 @@ func _MongoDBOperation_SetVariable_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MongoDBOperation_SetVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVariablesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MongoDBOperationServer).SetVariables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mongodb.MongoDBOperation/SetVariables",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MongoDBOperationServer).SetVariables(ctx, req.(*SetVariablesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MongoDBOperation_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MongoDBOperationServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mongodb.MongoDBOperation/CreateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MongoDBOperationServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MongoDBOperation_DropUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MongoDBOperationServer).DropUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mongodb.MongoDBOperation/DropUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MongoDBOperationServer).DropUser(ctx, req.(*DropUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MongoDBOperation_RotatePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotatePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MongoDBOperationServer).RotatePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mongodb.MongoDBOperation/RotatePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MongoDBOperationServer).RotatePassword(ctx, req.(*RotatePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MongoDBOperation_InitiateReplicaSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitiateReplicaSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MongoDBOperationServer).InitiateReplicaSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mongodb.MongoDBOperation/InitiateReplicaSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MongoDBOperationServer).InitiateReplicaSet(ctx, req.(*InitiateReplicaSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MongoDBOperation_AddMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MongoDBOperationServer).AddMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mongodb.MongoDBOperation/AddMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MongoDBOperationServer).AddMember(ctx, req.(*AddMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MongoDBOperation_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MongoDBOperationServer).RemoveMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mongodb.MongoDBOperation/RemoveMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MongoDBOperationServer).RemoveMember(ctx, req.(*RemoveMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MongoDBOperation_SetMemberConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMemberConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MongoDBOperationServer).SetMemberConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mongodb.MongoDBOperation/SetMemberConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MongoDBOperationServer).SetMemberConfig(ctx, req.(*SetMemberConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MongoDBOperation_StepDown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StepDownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MongoDBOperationServer).StepDown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mongodb.MongoDBOperation/StepDown",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MongoDBOperationServer).StepDown(ctx, req.(*StepDownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MongoDBOperation_ReplicaSetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplicaSetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MongoDBOperationServer).ReplicaSetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mongodb.MongoDBOperation/ReplicaSetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MongoDBOperationServer).ReplicaSetStatus(ctx, req.(*ReplicaSetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MongoDBOperation_ArchiveOplog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveOplogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MongoDBOperationServer).ArchiveOplog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mongodb.MongoDBOperation/ArchiveOplog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MongoDBOperationServer).ArchiveOplog(ctx, req.(*ArchiveOplogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MongoDBOperation_ServiceDesc is the grpc.ServiceDesc for MongoDBOperation service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -168,6 +520,50 @@ var MongoDBOperation_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetVariable",
 			Handler:    _MongoDBOperation_SetVariable_Handler,
 		},
+		{
+			MethodName: "SetVariables",
+			Handler:    _MongoDBOperation_SetVariables_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _MongoDBOperation_CreateUser_Handler,
+		},
+		{
+			MethodName: "DropUser",
+			Handler:    _MongoDBOperation_DropUser_Handler,
+		},
+		{
+			MethodName: "RotatePassword",
+			Handler:    _MongoDBOperation_RotatePassword_Handler,
+		},
+		{
+			MethodName: "InitiateReplicaSet",
+			Handler:    _MongoDBOperation_InitiateReplicaSet_Handler,
+		},
+		{
+			MethodName: "AddMember",
+			Handler:    _MongoDBOperation_AddMember_Handler,
+		},
+		{
+			MethodName: "RemoveMember",
+			Handler:    _MongoDBOperation_RemoveMember_Handler,
+		},
+		{
+			MethodName: "SetMemberConfig",
+			Handler:    _MongoDBOperation_SetMemberConfig_Handler,
+		},
+		{
+			MethodName: "StepDown",
+			Handler:    _MongoDBOperation_StepDown_Handler,
+		},
+		{
+			MethodName: "ReplicaSetStatus",
+			Handler:    _MongoDBOperation_ReplicaSetStatus_Handler,
+		},
+		{
+			MethodName: "ArchiveOplog",
+			Handler:    _MongoDBOperation_ArchiveOplog_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/agent/app/mongodb/pb/mongodb.proto",
@@ -0,0 +1,594 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxVotingMembers is the number of voting members a replica set accepts
+	maxVotingMembers = 7
+
+	defaultStepDownSeconds = 60
+	defaultCatchUpSeconds  = 10
+
+	// primaryTimeout bounds the wait for a primary after an initiate or step down
+	primaryTimeout = 60 * time.Second
+
+	// reconfigTimeout bounds the wait for a majority to commit a new config
+	reconfigTimeout = 60 * time.Second
+
+	// notYetInitializedCode is returned by replSetGetStatus before replSetInitiate
+	notYetInitializedCode = 94
+)
+
+// replSetConfig is the replica set config, the fields the agent does not
+// manage are kept in Extra so a reconfig leaves them untouched.
+type replSetConfig struct {
+	ID      string          `bson:"_id"`
+	Version int64           `bson:"version"`
+	Members []replSetMember `bson:"members"`
+	Extra   bson.M          `bson:",inline"`
+}
+
+type replSetMember struct {
+	ID          int     `bson:"_id"`
+	Host        string  `bson:"host"`
+	Priority    float64 `bson:"priority"`
+	Votes       int32   `bson:"votes"`
+	Hidden      bool    `bson:"hidden"`
+	ArbiterOnly bool    `bson:"arbiterOnly"`
+	Extra       bson.M  `bson:",inline"`
+}
+
+type replSetStatus struct {
+	Set     string                `bson:"set"`
+	Members []replSetMemberStatus `bson:"members"`
+}
+
+type replSetMemberStatus struct {
+	ID             int       `bson:"_id"`
+	Name           string    `bson:"name"`
+	Health         float64   `bson:"health"`
+	StateStr       string    `bson:"stateStr"`
+	Self           bool      `bson:"self"`
+	OptimeDate     time.Time `bson:"optimeDate"`
+	SyncSourceHost string    `bson:"syncSourceHost"`
+}
+
+func (s *service) InitiateReplicaSet(ctx context.Context, req *InitiateReplicaSetRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mongodb initiate replica set", map[string]interface{}{
+		"username": req.GetUsername(),
+		"members":  req.GetMembers(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	members := req.GetMembers()
+	if len(members) == 0 {
+		hostname, err := os.Hostname()
+		if err != nil {
+			s.logger.Errorw("failed to get hostname", zap.Error(err))
+			return nil, err
+		}
+		members = []*ReplicaSetMember{{Host: s.memberHost(hostname)}}
+	}
+
+	config, err := newReplSetConfig(s.serviceGroupName, members)
+	if err != nil {
+		s.logger.Errorw("invalid initiate replica set request", zap.Error(err))
+		return nil, err
+	}
+
+	// The set is not initiated yet, only a direct connection reaches the member
+	client, err := s.newDirectMongoClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeMongoClient(ctx, client)
+
+	if _, err := getReplSetStatus(ctx, client); err == nil {
+		s.logger.Info("replica set is already initiated")
+		return nil, nil
+	} else if !isNotYetInitialized(err) {
+		s.logger.Errorw("failed to get replica set status", zap.Error(err))
+		return nil, err
+	}
+
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "replSetInitiate", Value: config}}).Err(); err != nil {
+		s.logger.Errorw("failed to initiate replica set", zap.Error(err), zap.String("set", config.ID))
+		return nil, err
+	}
+
+	if err := waitForPrimary(ctx, client); err != nil {
+		s.logger.Errorw("failed to wait for primary", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Infow("initiate replica set successfully", zap.String("set", config.ID), zap.Int("members", len(config.Members)))
+	return nil, nil
+}
+
+func (s *service) AddMember(ctx context.Context, req *AddMemberRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mongodb add member", map[string]interface{}{
+		"username": req.GetUsername(),
+		"member":   req.GetMember(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	err := s.reconfigure(ctx, req.GetUsername(), func(config *replSetConfig) (bool, error) {
+		return addReplSetMember(config, req.GetMember())
+	})
+	if err != nil {
+		s.logger.Errorw("failed to add member", zap.Error(err), zap.String("host", req.GetMember().GetHost()))
+		return nil, err
+	}
+
+	s.logger.Infow("add member successfully", zap.String("host", req.GetMember().GetHost()))
+	return nil, nil
+}
+
+func (s *service) RemoveMember(ctx context.Context, req *RemoveMemberRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mongodb remove member", map[string]interface{}{
+		"username": req.GetUsername(),
+		"host":     req.GetHost(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetHost() == "" {
+		err := fmt.Errorf("host is required")
+		s.logger.Errorw("invalid remove member request", zap.Error(err))
+		return nil, err
+	}
+
+	// Commands are routed to the primary
+	client, err := s.newMongoClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeMongoClient(ctx, client)
+
+	// The primary cannot remove itself, it steps down first
+	primary, err := getPrimary(ctx, client)
+	if err != nil {
+		s.logger.Errorw("failed to get primary", zap.Error(err))
+		return nil, err
+	}
+	if primary == req.GetHost() {
+		if err := stepDown(ctx, client, defaultStepDownSeconds, defaultCatchUpSeconds); err != nil {
+			s.logger.Errorw("failed to step down primary", zap.Error(err), zap.String("host", primary))
+			return nil, err
+		}
+		if err := waitForPrimary(ctx, client); err != nil {
+			s.logger.Errorw("failed to wait for primary", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if err := reconfigure(ctx, client, func(config *replSetConfig) (bool, error) {
+		return removeReplSetMember(config, req.GetHost()), nil
+	}); err != nil {
+		s.logger.Errorw("failed to remove member", zap.Error(err), zap.String("host", req.GetHost()))
+		return nil, err
+	}
+
+	s.logger.Infow("remove member successfully", zap.String("host", req.GetHost()))
+	return nil, nil
+}
+
+func (s *service) SetMemberConfig(ctx context.Context, req *SetMemberConfigRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mongodb set member config", map[string]interface{}{
+		"username": req.GetUsername(),
+		"member":   req.GetMember(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	err := s.reconfigure(ctx, req.GetUsername(), func(config *replSetConfig) (bool, error) {
+		return updateReplSetMember(config, req.GetMember())
+	})
+	if err != nil {
+		s.logger.Errorw("failed to set member config", zap.Error(err), zap.String("host", req.GetMember().GetHost()))
+		return nil, err
+	}
+
+	s.logger.Infow("set member config successfully", zap.String("host", req.GetMember().GetHost()))
+	return nil, nil
+}
+
+func (s *service) StepDown(ctx context.Context, req *StepDownRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mongodb step down", map[string]interface{}{
+		"username":          req.GetUsername(),
+		"step_down_seconds": req.GetStepDownSeconds(),
+		"catch_up_seconds":  req.GetCatchUpSeconds(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	stepDownSeconds, catchUpSeconds := req.GetStepDownSeconds(), req.GetCatchUpSeconds()
+	if stepDownSeconds < 0 || catchUpSeconds < 0 {
+		err := fmt.Errorf("step_down_seconds and catch_up_seconds must not be negative")
+		s.logger.Errorw("invalid step down request", zap.Error(err))
+		return nil, err
+	}
+	if stepDownSeconds == 0 {
+		stepDownSeconds = defaultStepDownSeconds
+	}
+	if catchUpSeconds == 0 {
+		catchUpSeconds = defaultCatchUpSeconds
+	}
+	if catchUpSeconds >= stepDownSeconds {
+		err := fmt.Errorf("catch_up_seconds must be less than step_down_seconds")
+		s.logger.Errorw("invalid step down request", zap.Error(err))
+		return nil, err
+	}
+
+	// Commands are routed to the primary
+	client, err := s.newMongoClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeMongoClient(ctx, client)
+
+	if err := stepDown(ctx, client, stepDownSeconds, catchUpSeconds); err != nil {
+		s.logger.Errorw("failed to step down primary", zap.Error(err))
+		return nil, err
+	}
+
+	if err := waitForPrimary(ctx, client); err != nil {
+		s.logger.Errorw("failed to wait for primary", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("step down primary successfully")
+	return nil, nil
+}
+
+func (s *service) ReplicaSetStatus(ctx context.Context, req *ReplicaSetStatusRequest) (*ReplicaSetStatusResponse, error) {
+	util.LogRequestSafely(s.logger, "mongodb replica set status", map[string]interface{}{
+		"username": req.GetUsername(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	// The status is the view of the local member, initiated or not
+	client, err := s.newDirectMongoClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeMongoClient(ctx, client)
+
+	status, err := getReplSetStatus(ctx, client)
+	if isNotYetInitialized(err) {
+		return &ReplicaSetStatusResponse{Set: s.serviceGroupName}, nil
+	}
+	if err != nil {
+		s.logger.Errorw("failed to get replica set status", zap.Error(err))
+		return nil, err
+	}
+
+	config, err := getReplSetConfig(ctx, client)
+	if err != nil {
+		s.logger.Errorw("failed to get replica set config", zap.Error(err))
+		return nil, err
+	}
+
+	return newReplicaSetStatusResponse(status, config), nil
+}
+
+// reconfigure applies change to the replica set config through the primary.
+func (s *service) reconfigure(ctx context.Context, username string, change func(*replSetConfig) (bool, error)) error {
+	// Commands are routed to the primary
+	client, err := s.newMongoClient(ctx, username)
+	if err != nil {
+		return err
+	}
+	defer s.closeMongoClient(ctx, client)
+
+	return reconfigure(ctx, client, change)
+}
+
+// reconfigure reads the config, applies change and submits the new version
+// unless change reports nothing changed.
+func reconfigure(ctx context.Context, client *mongo.Client, change func(*replSetConfig) (bool, error)) error {
+	config, err := getReplSetConfig(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	changed, err := change(config)
+	if err != nil || !changed {
+		return err
+	}
+
+	// The term is owned by the server
+	delete(config.Extra, "term")
+	config.Version++
+
+	return client.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "replSetReconfig", Value: config},
+		{Key: "maxTimeMS", Value: reconfigTimeout.Milliseconds()},
+	}).Err()
+}
+
+func getReplSetConfig(ctx context.Context, client *mongo.Client) (*replSetConfig, error) {
+	var resp struct {
+		Config replSetConfig `bson:"config"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "replSetGetConfig", Value: 1}}).Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Config, nil
+}
+
+func getReplSetStatus(ctx context.Context, client *mongo.Client) (*replSetStatus, error) {
+	status := &replSetStatus{}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "replSetGetStatus", Value: 1}}).Decode(status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func isNotYetInitialized(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Code == notYetInitializedCode
+}
+
+// getPrimary returns the host of the primary as seen by the member the client talks to.
+func getPrimary(ctx context.Context, client *mongo.Client) (string, error) {
+	var hello struct {
+		Primary string `bson:"primary"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return "", err
+	}
+
+	return hello.Primary, nil
+}
+
+func stepDown(ctx context.Context, client *mongo.Client, stepDownSeconds, catchUpSeconds int64) error {
+	return client.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "replSetStepDown", Value: stepDownSeconds},
+		{Key: "secondaryCatchUpPeriodSecs", Value: catchUpSeconds},
+	}).Err()
+}
+
+// waitForPrimary polls until a member reports a primary.
+func waitForPrimary(ctx context.Context, client *mongo.Client) error {
+	ctx, cancel := context.WithTimeout(ctx, primaryTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		if primary, err := getPrimary(ctx, client); err == nil && primary != "" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("no primary elected: %v", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// newReplSetConfig returns the initial config of the set with the members.
+func newReplSetConfig(set string, members []*ReplicaSetMember) (*replSetConfig, error) {
+	if set == "" {
+		return nil, fmt.Errorf("replica set name is required")
+	}
+
+	config := &replSetConfig{ID: set, Version: 1}
+	for _, member := range members {
+		if _, err := addReplSetMember(config, member); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// addReplSetMember appends the member to the config, a member beyond the
+// voting members limit does not vote unless asked to. An existing member is
+// left as is.
+func addReplSetMember(config *replSetConfig, member *ReplicaSetMember) (bool, error) {
+	if err := validateReplicaSetMember(member); err != nil {
+		return false, err
+	}
+	if findReplSetMember(config, member.GetHost()) != nil {
+		return false, nil
+	}
+
+	id, voting := 0, 0
+	for _, m := range config.Members {
+		if m.ID >= id {
+			id = m.ID + 1
+		}
+		if m.Votes > 0 {
+			voting++
+		}
+	}
+
+	m := replSetMember{ID: id, Host: member.GetHost(), Priority: 1, Votes: 1}
+	if member.Votes == nil && voting >= maxVotingMembers {
+		m.Priority, m.Votes = 0, 0
+	}
+	applyReplicaSetMember(&m, member)
+	config.Members = append(config.Members, m)
+
+	return true, nil
+}
+
+// updateReplSetMember applies the member settings to the existing member.
+func updateReplSetMember(config *replSetConfig, member *ReplicaSetMember) (bool, error) {
+	if err := validateReplicaSetMember(member); err != nil {
+		return false, err
+	}
+
+	m := findReplSetMember(config, member.GetHost())
+	if m == nil {
+		return false, fmt.Errorf("member %s not found in replica set %s", member.GetHost(), config.ID)
+	}
+	if m.ArbiterOnly != member.GetArbiterOnly() {
+		return false, fmt.Errorf("member %s cannot change between arbiter and data bearing, remove and add it instead", member.GetHost())
+	}
+
+	before := *m
+	applyReplicaSetMember(m, member)
+
+	return before.Priority != m.Priority || before.Votes != m.Votes || before.Hidden != m.Hidden, nil
+}
+
+// removeReplSetMember drops the member with the host from the config.
+func removeReplSetMember(config *replSetConfig, host string) bool {
+	for i := range config.Members {
+		if config.Members[i].Host == host {
+			config.Members = append(config.Members[:i], config.Members[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func findReplSetMember(config *replSetConfig, host string) *replSetMember {
+	for i := range config.Members {
+		if config.Members[i].Host == host {
+			return &config.Members[i]
+		}
+	}
+
+	return nil
+}
+
+// applyReplicaSetMember sets the requested settings, hidden members and
+// arbiters never become primary.
+func applyReplicaSetMember(m *replSetMember, member *ReplicaSetMember) {
+	if member.Priority != nil {
+		m.Priority = member.GetPriority()
+	}
+	if member.Votes != nil {
+		m.Votes = member.GetVotes()
+	}
+	m.Hidden = member.GetHidden()
+	m.ArbiterOnly = member.GetArbiterOnly()
+
+	if m.Hidden || m.ArbiterOnly || m.Votes == 0 {
+		m.Priority = 0
+	}
+	if m.ArbiterOnly {
+		m.Votes = 1
+	}
+}
+
+func validateReplicaSetMember(member *ReplicaSetMember) error {
+	host := member.GetHost()
+	if host == "" || !strings.Contains(host, ":") {
+		return fmt.Errorf("member host must be host:port, got %q", host)
+	}
+	if member.Votes != nil && member.GetVotes() != 0 && member.GetVotes() != 1 {
+		return fmt.Errorf("member votes must be 0 or 1, got %d", member.GetVotes())
+	}
+	if member.Priority != nil && (member.GetPriority() < 0 || member.GetPriority() > 1000) {
+		return fmt.Errorf("member priority must be between 0 and 1000, got %v", member.GetPriority())
+	}
+
+	// A priority asked for a member that cannot be elected is a mistake, not a default to override
+	neverPrimary := member.GetHidden() || member.GetArbiterOnly() || (member.Votes != nil && member.GetVotes() == 0)
+	if neverPrimary && member.GetPriority() > 0 {
+		return fmt.Errorf("hidden, arbiter and non voting member %s must have priority 0", host)
+	}
+	if member.GetArbiterOnly() && member.GetHidden() {
+		return fmt.Errorf("arbiter %s cannot be hidden", host)
+	}
+
+	return nil
+}
+
+// newReplicaSetStatusResponse merges the member states with their config.
+func newReplicaSetStatusResponse(status *replSetStatus, config *replSetConfig) *ReplicaSetStatusResponse {
+	resp := &ReplicaSetStatusResponse{
+		Initiated:     true,
+		Set:           status.Set,
+		ConfigVersion: config.Version,
+	}
+
+	var primaryOptime time.Time
+	for _, m := range status.Members {
+		if m.StateStr == "PRIMARY" {
+			resp.Primary = m.Name
+			primaryOptime = m.OptimeDate
+		}
+	}
+
+	for _, m := range status.Members {
+		member := &MemberStatus{
+			Id:         int32(m.ID),
+			Host:       m.Name,
+			State:      m.StateStr,
+			Healthy:    m.Health == 1,
+			Self:       m.Self,
+			SyncSource: m.SyncSourceHost,
+		}
+		if !m.OptimeDate.IsZero() {
+			member.OptimeSeconds = m.OptimeDate.Unix()
+		}
+		if c := findReplSetMember(config, m.Name); c != nil {
+			member.Priority = c.Priority
+			member.Votes = c.Votes
+			member.Hidden = c.Hidden
+			member.ArbiterOnly = c.ArbiterOnly
+		}
+
+		if member.Healthy && m.StateStr == "SECONDARY" && !primaryOptime.IsZero() && primaryOptime.After(m.OptimeDate) {
+			member.LagSeconds = int64(primaryOptime.Sub(m.OptimeDate).Seconds())
+			if member.LagSeconds > resp.MaxLagSeconds {
+				resp.MaxLagSeconds = member.LagSeconds
+			}
+		}
+
+		resp.Members = append(resp.Members, member)
+	}
+
+	return resp
+}
@@ -0,0 +1,159 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewReplSetConfig(t *testing.T) {
+	config, err := newReplSetConfig("demo", []*ReplicaSetMember{
+		{Host: "demo-0.demo-headless-svc.default:27017"},
+		{Host: "demo-1.demo-headless-svc.default:27017", Priority: proto.Float64(2)},
+		{Host: "demo-2.demo-headless-svc.default:27017", ArbiterOnly: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "demo", config.ID)
+	require.Equal(t, int64(1), config.Version)
+	require.Equal(t, []replSetMember{
+		{ID: 0, Host: "demo-0.demo-headless-svc.default:27017", Priority: 1, Votes: 1},
+		{ID: 1, Host: "demo-1.demo-headless-svc.default:27017", Priority: 2, Votes: 1},
+		{ID: 2, Host: "demo-2.demo-headless-svc.default:27017", Priority: 0, Votes: 1, ArbiterOnly: true},
+	}, config.Members)
+
+	_, err = newReplSetConfig("", []*ReplicaSetMember{{Host: "demo-0:27017"}})
+	require.Error(t, err)
+}
+
+func TestAddReplSetMember(t *testing.T) {
+	config := &replSetConfig{ID: "demo", Version: 3}
+	for i, host := range []string{"a:27017", "b:27017", "c:27017", "d:27017", "e:27017", "f:27017", "g:27017"} {
+		changed, err := addReplSetMember(config, &ReplicaSetMember{Host: host})
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Equal(t, i, config.Members[i].ID)
+	}
+
+	// an existing member is left as is
+	changed, err := addReplSetMember(config, &ReplicaSetMember{Host: "a:27017", Priority: proto.Float64(5)})
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Equal(t, float64(1), config.Members[0].Priority)
+
+	// the eighth member does not vote
+	changed, err = addReplSetMember(config, &ReplicaSetMember{Host: "h:27017"})
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, replSetMember{ID: 7, Host: "h:27017"}, config.Members[7])
+
+	// ids are never reused
+	require.True(t, removeReplSetMember(config, "c:27017"))
+	require.False(t, removeReplSetMember(config, "c:27017"))
+	_, err = addReplSetMember(config, &ReplicaSetMember{Host: "i:27017", Votes: proto.Int32(0)})
+	require.NoError(t, err)
+	require.Equal(t, 8, config.Members[7].ID)
+}
+
+func TestUpdateReplSetMember(t *testing.T) {
+	config, err := newReplSetConfig("demo", []*ReplicaSetMember{{Host: "a:27017"}, {Host: "b:27017"}, {Host: "c:27017", ArbiterOnly: true}})
+	require.NoError(t, err)
+
+	changed, err := updateReplSetMember(config, &ReplicaSetMember{Host: "b:27017", Hidden: true})
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, replSetMember{ID: 1, Host: "b:27017", Priority: 0, Votes: 1, Hidden: true}, config.Members[1])
+
+	changed, err = updateReplSetMember(config, &ReplicaSetMember{Host: "b:27017", Hidden: true})
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	changed, err = updateReplSetMember(config, &ReplicaSetMember{Host: "a:27017", Priority: proto.Float64(10)})
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, float64(10), config.Members[0].Priority)
+
+	_, err = updateReplSetMember(config, &ReplicaSetMember{Host: "c:27017"})
+	require.Error(t, err)
+
+	_, err = updateReplSetMember(config, &ReplicaSetMember{Host: "z:27017"})
+	require.Error(t, err)
+}
+
+func TestValidateReplicaSetMember(t *testing.T) {
+	require.NoError(t, validateReplicaSetMember(&ReplicaSetMember{Host: "a:27017", Votes: proto.Int32(0), Priority: proto.Float64(0)}))
+
+	for _, member := range []*ReplicaSetMember{
+		{},
+		{Host: "a"},
+		{Host: "a:27017", Votes: proto.Int32(2)},
+		{Host: "a:27017", Priority: proto.Float64(-1)},
+		{Host: "a:27017", Hidden: true, Priority: proto.Float64(1)},
+		{Host: "a:27017", Votes: proto.Int32(0), Priority: proto.Float64(1)},
+		{Host: "a:27017", ArbiterOnly: true, Hidden: true},
+	} {
+		require.Error(t, validateReplicaSetMember(member), "%v", member)
+	}
+}
+
+func TestReplSetConfigKeepsUnmanagedFields(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: "demo"},
+		{Key: "version", Value: int32(4)},
+		{Key: "term", Value: int64(2)},
+		{Key: "protocolVersion", Value: int64(1)},
+		{Key: "members", Value: bson.A{
+			bson.D{{Key: "_id", Value: int32(0)}, {Key: "host", Value: "a:27017"}, {Key: "priority", Value: float64(1)},
+				{Key: "votes", Value: int32(1)}, {Key: "tags", Value: bson.D{{Key: "zone", Value: "a"}}}},
+		}},
+		{Key: "settings", Value: bson.D{{Key: "chainingAllowed", Value: true}}},
+	})
+	require.NoError(t, err)
+
+	config := &replSetConfig{}
+	require.NoError(t, bson.Unmarshal(raw, config))
+	require.Equal(t, int64(4), config.Version)
+	require.Contains(t, config.Extra, "settings")
+	require.Contains(t, config.Members[0].Extra, "tags")
+
+	raw, err = bson.Marshal(config)
+	require.NoError(t, err)
+
+	out := bson.M{}
+	require.NoError(t, bson.Unmarshal(raw, &out))
+	require.Contains(t, out, "settings")
+	require.Contains(t, out, "protocolVersion")
+}
+
+func TestNewReplicaSetStatusResponse(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC()
+	status := &replSetStatus{Set: "demo"}
+	for _, m := range []struct {
+		name, state string
+		health      float64
+		optime      time.Time
+	}{
+		{"a:27017", "PRIMARY", 1, now},
+		{"b:27017", "SECONDARY", 1, now.Add(-5 * time.Second)},
+		{"c:27017", "(not reachable/healthy)", 0, time.Time{}},
+	} {
+		status.Members = append(status.Members, replSetMemberStatus{
+			ID: len(status.Members), Name: m.name, Health: m.health, StateStr: m.state, OptimeDate: m.optime,
+		})
+	}
+
+	config, err := newReplSetConfig("demo", []*ReplicaSetMember{{Host: "a:27017"}, {Host: "b:27017", Hidden: true}, {Host: "c:27017"}})
+	require.NoError(t, err)
+
+	resp := newReplicaSetStatusResponse(status, config)
+	require.True(t, resp.GetInitiated())
+	require.Equal(t, "a:27017", resp.GetPrimary())
+	require.Equal(t, int64(5), resp.GetMaxLagSeconds())
+	require.Len(t, resp.GetMembers(), 3)
+	require.Equal(t, int64(5), resp.GetMembers()[1].GetLagSeconds())
+	require.True(t, resp.GetMembers()[1].GetHidden())
+	require.False(t, resp.GetMembers()[2].GetHealthy())
+	require.Zero(t, resp.GetMembers()[2].GetOptimeSeconds())
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/upmio/unit-operator/pkg/agent/vars"
@@ -12,6 +13,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -21,21 +23,39 @@ import (
 	"github.com/upmio/unit-operator/pkg/agent/app/slm"
 	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
 	// this import  needs to be done otherwise the mysql driver don't work
-	_ "github.com/go-sql-driver/mysql"
+	mysqldriver "github.com/go-sql-driver/mysql"
 )
 
 const (
 	relayLogDirEnvKey = "RELAY_LOG_DIR"
 	binLogDirEnvKey   = "BIN_LOG_DIR"
+
+	// ER_INCORRECT_GLOBAL_LOCAL_VAR, returned when a read only variable is set at runtime
+	erReadOnlyVariable = 1238
+	// ER_NONEXISTING_GRANT and ER_NONEXISTING_TABLE_GRANT, returned when a privilege to revoke is not held
+	erNonexistingGrant      = 1141
+	erNonexistingTableGrant = 1147
 )
 
 var (
 	// service instance
 	svr = &service{}
+
+	privilegeRE = regexp.MustCompile(`^[A-Z]+( [A-Z]+)*$`)
+	charsetRE   = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+	// readOnlyVariableRE extracts the variable of ER_INCORRECT_GLOBAL_LOCAL_VAR
+	readOnlyVariableRE = regexp.MustCompile(`^Variable '([^']+)' is a read only variable`)
+
+	variableNameRE = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+	numericValueRE = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+	binlogPosRE = regexp.MustCompile(`filename '([^']*)', position '([^']*)'(?:, GTID of the last change '([^']*)')?`)
 )
 
 type service struct {
@@ -107,7 +127,11 @@ func (s *service) Registry(server *grpc.Server) {
 	RegisterMysqlOperationServer(server, svr)
 }
 
-func (s *service) Clone(ctx context.Context, req *CloneRequest) (*common.Empty, error) {
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterMysqlOperationHandler(ctx, mux, conn)
+}
+
+func (s *service) Clone(ctx context.Context, req *CloneRequest) (*CloneResponse, error) {
 	util.LogRequestSafely(s.logger, "mysql clone", map[string]interface{}{
 		"username":          req.GetUsername(),
 		"source_host":       req.GetSourceHost(),
@@ -140,6 +164,7 @@ func (s *service) Clone(ctx context.Context, req *CloneRequest) (*common.Empty,
 	}
 
 	// Set valid donor list
+	start := time.Now()
 	addr := net.JoinHostPort(req.GetSourceHost(), strconv.FormatInt(req.GetSourcePort(), 10))
 	if _, err = db.ExecContext(ctx, SetValidDonorListSql, addr); err != nil {
 		s.logger.Errorw("failed to set valid donor list", zap.Error(err))
@@ -161,16 +186,21 @@ func (s *service) Clone(ctx context.Context, req *CloneRequest) (*common.Empty,
 		)
 	}
 
-	if err := s.waitForCloneComplete(ctx, req.GetUsername(), time.Minute); err != nil {
+	resp, err := s.waitForCloneComplete(ctx, req.GetUsername(), time.Minute)
+	if err != nil {
 		s.logger.Errorw("failed to wait for clone complete", zap.Error(err))
 		return nil, err
 	}
+	resp.Source = addr
+	resp.DurationMs = time.Since(start).Milliseconds()
 
-	s.logger.Info("clone successfully")
-	return nil, nil
+	s.logger.Infow("clone successfully", "size_bytes", resp.GetSizeBytes(), "position", resp.GetPosition())
+	return resp, nil
 }
 
-func (s *service) waitForCloneComplete(ctx context.Context, username string, timeout time.Duration) error {
+// waitForCloneComplete waits for the clone to complete, returning the
+// position and the size of the cloned data.
+func (s *service) waitForCloneComplete(ctx context.Context, username string, timeout time.Duration) (*CloneResponse, error) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 
@@ -188,8 +218,14 @@ func (s *service) waitForCloneComplete(ctx context.Context, username string, tim
 				continue
 			}
 
-			var status, errMsg string
-			err = db.QueryRowContext(ctx, getCloneStatusSql).Scan(&status, &errMsg)
+			var status, errMsg, gtid, binlogFile string
+			var binlogPos int64
+			err = db.QueryRowContext(ctx, getCloneStatusSql).Scan(&status, &errMsg, &gtid, &binlogFile, &binlogPos)
+
+			resp := &CloneResponse{Position: clonePosition(gtid, binlogFile, binlogPos)}
+			if err == nil && status == "Completed" {
+				err = db.QueryRowContext(ctx, getCloneDataSql).Scan(&resp.SizeBytes)
+			}
 			s.closeDBConn(db)
 
 			if err != nil {
@@ -204,18 +240,18 @@ func (s *service) waitForCloneComplete(ctx context.Context, username string, tim
 
 			switch status {
 			case "Completed":
-				return nil
+				return resp, nil
 			case "Failed":
-				return fmt.Errorf("clone failed: %s", errMsg)
+				return nil, fmt.Errorf("clone failed: %s", errMsg)
 			}
 
 		case <-timeoutCh:
-			return fmt.Errorf("timeout waiting for clone to complete")
+			return nil, fmt.Errorf("timeout waiting for clone to complete")
 		}
 	}
 }
 
-func (s *service) PhysicalBackup(ctx context.Context, req *PhysicalBackupRequest) (*common.Empty, error) {
+func (s *service) PhysicalBackup(ctx context.Context, req *PhysicalBackupRequest) (*common.BackupResponse, error) {
 	util.LogRequestSafely(s.logger, "mysql physical backup", map[string]interface{}{
 		"username":    req.GetUsername(),
 		"backup_tool": req.GetTool().String(),
@@ -240,6 +276,8 @@ func (s *service) PhysicalBackup(ctx context.Context, req *PhysicalBackupRequest
 		return nil, err
 	}
 
+	start := time.Now()
+
 	var cmd1, cmd2 *exec.Cmd
 
 	switch req.GetTool() {
@@ -291,17 +329,26 @@ func (s *service) PhysicalBackup(ctx context.Context, req *PhysicalBackupRequest
 
 	// Use command executor for piped commands
 	executor := common.NewCommandExecutor(s.logger)
-	if err := executor.ExecutePipedCommands(cmd1, cmd2, "backup"); err != nil {
+	size, err := executor.ExecutePipedCommands(cmd1, cmd2, "backup")
+	if err != nil {
 		s.logger.Errorw("failed to physical backup mysql", zap.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("physical backup mysql successfully")
+	resp := &common.BackupResponse{
+		Bucket:     req.GetObjectStorage().GetBucket(),
+		Object:     req.GetBackupFile(),
+		SizeBytes:  size,
+		DurationMs: time.Since(start).Milliseconds(),
+		Position:   readXtrabackupInfoPosition(filepath.Join("/tmp/s3_tmp_dir", "xtrabackup_info")),
+	}
 
-	return nil, nil
+	s.logger.Infow("physical backup mysql successfully", "size_bytes", resp.GetSizeBytes(), "position", resp.GetPosition())
+
+	return resp, nil
 }
 
-func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest) (*common.Empty, error) {
+func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest) (*common.BackupResponse, error) {
 	util.LogRequestSafely(s.logger, "mysql logical backup", map[string]interface{}{
 		"username":            req.GetUsername(),
 		"backup_file":         req.GetBackupFile(),
@@ -328,6 +375,8 @@ func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest)
 		return nil, err
 	}
 
+	start := time.Now()
+
 	var cmd *exec.Cmd
 
 	switch req.GetLogicalBackupMode() {
@@ -378,13 +427,19 @@ func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest)
 		return nil, err
 	}
 
-	if err := executor.ExecuteCommandStreamToS3(ctx, cmd, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "backup"); err != nil {
+	size, err := executor.ExecuteCommandStreamToS3(ctx, cmd, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "backup")
+	if err != nil {
 		s.logger.Errorw("failed to execute backup", zap.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("logical backup mysql successfully")
-	return nil, nil
+	s.logger.Infow("logical backup mysql successfully", "size_bytes", size)
+	return &common.BackupResponse{
+		Bucket:     req.GetObjectStorage().GetBucket(),
+		Object:     req.GetBackupFile(),
+		SizeBytes:  size,
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
 }
 
 func (s *service) GtidPurge(ctx context.Context, req *GtidPurgeRequest) (*common.Empty, error) {
@@ -480,7 +535,7 @@ func (s *service) generateGtidPurgeSql() (string, error) {
 	return strings.Join(gtid, ","), nil
 }
 
-func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Empty, error) {
+func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.RestoreResponse, error) {
 	util.LogRequestSafely(s.logger, "mysql restore", map[string]interface{}{
 		"backup_file": req.GetBackupFile(),
 		"tool":        req.GetTool().String(),
@@ -498,6 +553,8 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 		return nil, err
 	}
 
+	start := time.Now()
+
 	// Clean directories
 	if err := s.removeContents(s.dataDir); err != nil {
 		s.logger.Errorw("failed to remove contents", zap.Error(err), zap.String("dir", s.dataDir))
@@ -561,7 +618,8 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 
 	// Use command executor for piped commands
 	executor := common.NewCommandExecutor(s.logger)
-	if err := executor.ExecutePipedCommands(cmd1, cmd2, "restore"); err != nil {
+	size, err := executor.ExecutePipedCommands(cmd1, cmd2, "restore")
+	if err != nil {
 		s.logger.Errorw("failed to restore mysql", zap.Error(err))
 		return nil, err
 	}
@@ -579,8 +637,48 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 		return nil, err
 	}
 
-	s.logger.Info("restore mysql successfully")
-	return nil, nil
+	resp := &common.RestoreResponse{
+		Bucket:     req.GetObjectStorage().GetBucket(),
+		Object:     req.GetBackupFile(),
+		SizeBytes:  size,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	// the GTID set of the backup, which gtid-purge applies afterwards
+	if gtid, err := s.generateGtidPurgeSql(); err == nil {
+		resp.Position = gtid
+	}
+
+	s.logger.Infow("restore mysql successfully", "size_bytes", resp.GetSizeBytes(), "position", resp.GetPosition())
+	return resp, nil
+}
+
+// readXtrabackupInfoPosition returns the GTID set, or else the binlog file and
+// position, of the binlog_pos line of an xtrabackup_info file, e.g.
+// binlog_pos = filename 'binlog.000003', position '157', GTID of the last change 'uuid:1-5'
+func readXtrabackupInfoPosition(file string) string {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "binlog_pos" {
+			continue
+		}
+
+		matches := binlogPosRE.FindStringSubmatch(value)
+		if matches == nil {
+			return strings.TrimSpace(value)
+		}
+		if matches[3] != "" {
+			return matches[3]
+		}
+		return fmt.Sprintf("%s:%s", matches[1], matches[2])
+	}
+
+	return ""
 }
 
 func (s *service) removeContents(dir string) error {
@@ -606,11 +704,12 @@ func (s *service) removeContents(dir string) error {
 	return nil
 }
 
-func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.Empty, error) {
+func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.SetVariableResponse, error) {
 	util.LogRequestSafely(s.logger, "mysql set variable", map[string]interface{}{
 		"username": req.GetUsername(),
 		"key":      req.GetKey(),
 		"value":    req.GetValue(),
+		"persist":  req.GetPersist(),
 	})
 
 	// Check process is started
@@ -619,6 +718,13 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 		return nil, err
 	}
 
+	key, err := normalizeVariableName(req.GetKey())
+	if err != nil {
+		s.logger.Errorw("invalid set variable request", zap.Error(err))
+		return nil, err
+	}
+	value := formatVariableValue(req.GetValue())
+
 	// Create mysql connection
 	db, err := s.newDBConn(ctx, req.GetUsername())
 	if err != nil {
@@ -626,18 +732,634 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 	}
 	defer s.closeDBConn(db)
 
-	// Execute set variable SQL
-	execSQL := fmt.Sprintf(setVariableSql, req.GetKey(), req.GetValue())
+	// Check the variable against the server catalog
+	var previous string
+	if err = db.QueryRowContext(ctx, getVariableSql, key).Scan(&previous); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("unknown system variable %q", key)
+		}
+		s.logger.Errorw("failed to check variable", zap.Error(err), zap.String("key", key))
+		return nil, err
+	}
+
+	// SET PERSIST also writes mysqld-auto.cnf, which is applied after the rendered config file on restart
+	execSQL := fmt.Sprintf(setVariableSql, key, value)
+	if req.GetPersist() {
+		execSQL = fmt.Sprintf(persistVariableSql, key, value)
+	}
+
+	restartRequired := false
 	if _, err = db.ExecContext(ctx, execSQL); err != nil {
-		s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("key", req.GetKey()), zap.String("value", req.GetValue()))
+		// Read only variables can only be persisted for the next start
+		if !req.GetPersist() || !isReadOnlyVariableError(err) {
+			s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("key", key), zap.String("value", req.GetValue()))
+			return nil, err
+		}
+
+		if _, err = db.ExecContext(ctx, fmt.Sprintf(persistOnlyVariableSql, key, value)); err != nil {
+			s.logger.Errorw("failed to persist variable", zap.Error(err), zap.String("key", key), zap.String("value", req.GetValue()))
+			return nil, err
+		}
+		restartRequired = true
+	}
+
+	// Read the value back, the server normalizes it, unless it only applies on restart
+	current := req.GetValue()
+	if !restartRequired {
+		if err = db.QueryRowContext(ctx, getVariableSql, key).Scan(&current); err != nil {
+			s.logger.Errorw("failed to get variable", zap.Error(err), zap.String("key", key))
+			return nil, err
+		}
+	}
+
+	resp := common.NewSetVariableResponse(previous, current)
+	resp.RestartRequired = restartRequired
+	resp.Persisted = req.GetPersist()
+
+	s.logger.Infow("set variable successfully", "changed", resp.GetChanged(), "restart_required", resp.GetRestartRequired())
+
+	return resp, nil
+}
+
+func (s *service) SetVariables(ctx context.Context, req *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	util.LogRequestSafely(s.logger, "mysql set variables", map[string]interface{}{
+		"username":  req.GetUsername(),
+		"variables": req.GetVariables(),
+		"persist":   req.GetPersist(),
+		"dry_run":   req.GetDryRun(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("set variable successfully")
+	variables, err := normalizeVariables(req.GetVariables())
+	if err != nil {
+		s.logger.Errorw("invalid set variables request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create mysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	// Read the current values, unknown variables fail the whole batch
+	resp := &common.SetVariablesResponse{DryRun: req.GetDryRun()}
+	keys := common.SortedVariableKeys(variables)
+	for _, key := range keys {
+		var current string
+		err = db.QueryRowContext(ctx, getVariableSql, key).Scan(&current)
+		result := common.NewVariableResult(key, current, variables[key])
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			result.Error = "unknown system variable"
+		case err != nil:
+			s.logger.Errorw("failed to get variable", zap.Error(err), zap.String("key", key))
+			return nil, err
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if req.GetDryRun() || resp.Failed() {
+		return resp, nil
+	}
+
+	// A single SET statement with several assignments either changes every variable or none
+	resp.Persisted = req.GetPersist()
+	scope := "GLOBAL"
+	if req.GetPersist() {
+		scope = "PERSIST"
+	}
+
+	// Read only variables can only be persisted for the next start, they are
+	// switched to PERSIST_ONLY one at a time as the server reports them and the
+	// whole statement is retried, so that the batch is never partially applied
+	persistOnly := make(map[string]bool)
+	for {
+		_, err = db.ExecContext(ctx, buildSetVariablesSql(scope, keys, variables, persistOnly))
+		if err == nil {
+			break
+		}
+
+		key := readOnlyVariableName(err)
+		if _, ok := variables[key]; !req.GetPersist() || !ok || persistOnly[key] {
+			s.logger.Errorw("failed to set variables", zap.Error(err))
+			return nil, err
+		}
+		persistOnly[key] = true
+	}
+
+	resp.MarkApplied()
+	for _, result := range resp.Results {
+		result.RestartRequired = persistOnly[result.GetKey()]
+	}
+
+	s.logger.Info("set variables successfully")
+
+	return resp, nil
+}
+
+func (s *service) CreateUser(ctx context.Context, req *CreateUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mysql create user", map[string]interface{}{
+		"username":   req.GetUsername(),
+		"user":       req.GetUser(),
+		"host":       req.GetHost(),
+		"privileges": req.GetPrivileges(),
+		"revokes":    req.GetRevokes(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" || req.GetPassword() == "" {
+		err := fmt.Errorf("user and password are required")
+		s.logger.Errorw("invalid create user request", zap.Error(err))
+		return nil, err
+	}
+
+	host := accountHost(req.GetHost())
+
+	grants := make([]string, 0, len(req.GetPrivileges()))
+	for _, privilege := range req.GetPrivileges() {
+		grant, err := buildGrantSql(privilege)
+		if err != nil {
+			s.logger.Errorw("invalid privilege", zap.Error(err))
+			return nil, err
+		}
+		grants = append(grants, grant)
+	}
+
+	revokes := make([]string, 0, len(req.GetRevokes()))
+	for _, privilege := range req.GetRevokes() {
+		revoke, err := buildRevokeSql(privilege)
+		if err != nil {
+			s.logger.Errorw("invalid privilege to revoke", zap.Error(err))
+			return nil, err
+		}
+		revokes = append(revokes, revoke)
+	}
+
+	// Create mysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	// Accounts are replicated from the source, read-only replicas must not be written directly
+	readOnly, err := s.isReadOnly(ctx, db)
+	if err != nil {
+		s.logger.Errorw("failed to check read only", zap.Error(err))
+		return nil, err
+	}
+	if readOnly {
+		s.logger.Infow("instance is read only, skip create user", "user", req.GetUser())
+		return nil, nil
+	}
+
+	if _, err = db.ExecContext(ctx, createUserSql, req.GetUser(), host, req.GetPassword()); err != nil {
+		s.logger.Errorw("failed to create user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	if _, err = db.ExecContext(ctx, alterUserPasswordSql, req.GetUser(), host, req.GetPassword()); err != nil {
+		s.logger.Errorw("failed to alter user password", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	// Privileges removed from the user are revoked before the grants, which may overlap them
+	for _, revoke := range revokes {
+		if _, err = db.ExecContext(ctx, revoke, req.GetUser(), host); err != nil && !isNonexistingGrantError(err) {
+			s.logger.Errorw("failed to revoke privileges", zap.Error(err), zap.String("user", req.GetUser()), zap.String("revoke", revoke))
+			return nil, err
+		}
+	}
+
+	for _, grant := range grants {
+		if _, err = db.ExecContext(ctx, grant, req.GetUser(), host); err != nil {
+			s.logger.Errorw("failed to grant privileges", zap.Error(err), zap.String("user", req.GetUser()), zap.String("grant", grant))
+			return nil, err
+		}
+	}
+
+	s.logger.Info("create user successfully")
+	return nil, nil
+}
+
+func (s *service) DropUser(ctx context.Context, req *DropUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mysql drop user", map[string]interface{}{
+		"username": req.GetUsername(),
+		"user":     req.GetUser(),
+		"host":     req.GetHost(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" {
+		err := fmt.Errorf("user is required")
+		s.logger.Errorw("invalid drop user request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create mysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	readOnly, err := s.isReadOnly(ctx, db)
+	if err != nil {
+		s.logger.Errorw("failed to check read only", zap.Error(err))
+		return nil, err
+	}
+	if readOnly {
+		s.logger.Infow("instance is read only, skip drop user", "user", req.GetUser())
+		return nil, nil
+	}
+
+	if _, err = db.ExecContext(ctx, dropUserSql, req.GetUser(), accountHost(req.GetHost())); err != nil {
+		s.logger.Errorw("failed to drop user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
 
+	s.logger.Info("drop user successfully")
 	return nil, nil
 }
 
+func (s *service) CreateDatabase(ctx context.Context, req *CreateDatabaseRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mysql create database", map[string]interface{}{
+		"username":      req.GetUsername(),
+		"database":      req.GetDatabase(),
+		"character_set": req.GetCharacterSet(),
+		"collation":     req.GetCollation(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	execSQL, err := buildCreateDatabaseSql(req.GetDatabase(), req.GetCharacterSet(), req.GetCollation())
+	if err != nil {
+		s.logger.Errorw("invalid create database request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create mysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	readOnly, err := s.isReadOnly(ctx, db)
+	if err != nil {
+		s.logger.Errorw("failed to check read only", zap.Error(err))
+		return nil, err
+	}
+	if readOnly {
+		s.logger.Infow("instance is read only, skip create database", "database", req.GetDatabase())
+		return nil, nil
+	}
+
+	if _, err = db.ExecContext(ctx, execSQL); err != nil {
+		s.logger.Errorw("failed to create database", zap.Error(err), zap.String("database", req.GetDatabase()))
+		return nil, err
+	}
+
+	s.logger.Info("create database successfully")
+	return nil, nil
+}
+
+func (s *service) RotatePassword(ctx context.Context, req *RotatePasswordRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "mysql rotate password", map[string]interface{}{
+		"username":                req.GetUsername(),
+		"user":                    req.GetUser(),
+		"host":                    req.GetHost(),
+		"retain_current_password": req.GetRetainCurrentPassword(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" || req.GetPassword() == "" {
+		err := fmt.Errorf("user and password are required")
+		s.logger.Errorw("invalid rotate password request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create mysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	// Password changes are replicated from the source, read-only replicas only switch to the new password
+	readOnly, err := s.isReadOnly(ctx, db)
+	if err != nil {
+		s.logger.Errorw("failed to check read only", zap.Error(err))
+		return nil, err
+	}
+
+	if readOnly {
+		s.logger.Infow("instance is read only, skip alter user password", "user", req.GetUser())
+	} else {
+		// RETAIN CURRENT PASSWORD keeps the previous password valid as secondary password
+		// until the next rotation, clients still holding it keep working meanwhile
+		execSQL := alterUserPasswordSql
+		if req.GetRetainCurrentPassword() {
+			execSQL = retainUserPasswordSql
+		}
+
+		if _, err = db.ExecContext(ctx, execSQL, req.GetUser(), accountHost(req.GetHost()), req.GetPassword()); err != nil {
+			s.logger.Errorw("failed to rotate password", zap.Error(err), zap.String("user", req.GetUser()))
+			return nil, err
+		}
+	}
+
+	// Replicas authenticate to their source with the credentials of their channels,
+	// keep them in line with the rotated user
+	if err = s.updateReplicationPassword(ctx, db, req.GetUser(), req.GetPassword()); err != nil {
+		s.logger.Errorw("failed to update replication channel password", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	util.SetRotatedPassword(req.GetUser(), req.GetPassword())
+
+	s.logger.Info("rotate password successfully")
+	return nil, nil
+}
+
+// updateReplicationPassword sets the password of the replication channels connecting as the user.
+// The receiver thread of a running channel is restarted, so that it reconnects with the new password.
+func (s *service) updateReplicationPassword(ctx context.Context, db *sql.DB, user, password string) error {
+	rows, err := db.QueryContext(ctx, replicationChannelsSql, user)
+	if err != nil {
+		return err
+	}
+
+	running := make(map[string]bool)
+	var channels []string
+	for rows.Next() {
+		var (
+			channel string
+			on      bool
+		)
+		if err := rows.Scan(&channel, &on); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		channels = append(channels, channel)
+		running[channel] = on
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		restart := running[channel] && channel != groupReplicationRecoveryChannel
+		name := quoteString(channel)
+
+		if restart {
+			if _, err := db.ExecContext(ctx, fmt.Sprintf(stopReplicaIOSql, name)); err != nil {
+				return fmt.Errorf("failed to stop receiver of channel %q: %v", channel, err)
+			}
+		}
+
+		if _, err := db.ExecContext(ctx, fmt.Sprintf(changeSourcePasswordSql, quoteString(password), name)); err != nil {
+			return fmt.Errorf("failed to change source password of channel %q: %v", channel, err)
+		}
+
+		if restart {
+			if _, err := db.ExecContext(ctx, fmt.Sprintf(startReplicaIOSql, name)); err != nil {
+				return fmt.Errorf("failed to start receiver of channel %q: %v", channel, err)
+			}
+		}
+
+		s.logger.Infow("update replication channel password successfully", "channel", channel, "user", user)
+	}
+
+	return nil
+}
+
+// isReadOnly reports whether read_only or super_read_only is enabled on the instance
+func (s *service) isReadOnly(ctx context.Context, db *sql.DB) (bool, error) {
+	var readOnly bool
+	if err := db.QueryRowContext(ctx, checkReadOnlySql).Scan(&readOnly); err != nil {
+		return false, err
+	}
+
+	return readOnly, nil
+}
+
+// normalizeVariableName validates the name of a system variable, component variables
+// such as validate_password.policy are allowed
+func normalizeVariableName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !variableNameRE.MatchString(name) {
+		return "", fmt.Errorf("invalid system variable name %q", name)
+	}
+
+	return name, nil
+}
+
+// normalizeVariables validates the names of a batch of system variables
+func normalizeVariables(variables map[string]string) (map[string]string, error) {
+	if len(variables) == 0 {
+		return nil, fmt.Errorf("variables is required")
+	}
+
+	normalized := make(map[string]string, len(variables))
+	for key, value := range variables {
+		name, err := normalizeVariableName(key)
+		if err != nil {
+			return nil, err
+		}
+		normalized[name] = value
+	}
+
+	return normalized, nil
+}
+
+// buildSetVariablesSql renders one SET statement assigning every variable of the batch in the given scope,
+// the persistOnly variables are assigned with PERSIST_ONLY
+func buildSetVariablesSql(scope string, keys []string, variables map[string]string, persistOnly map[string]bool) string {
+	assignments := make([]string, 0, len(keys))
+	for _, key := range keys {
+		keyScope := scope
+		if persistOnly[key] {
+			keyScope = "PERSIST_ONLY"
+		}
+		assignments = append(assignments, fmt.Sprintf("%s %s = %s", keyScope, key, formatVariableValue(variables[key])))
+	}
+
+	return "SET " + strings.Join(assignments, ", ") + ";"
+}
+
+// formatVariableValue renders the value of SET GLOBAL, numbers are kept as literals
+// so that numeric variables accept them, anything else is a quoted string
+func formatVariableValue(value string) string {
+	if numericValueRE.MatchString(value) {
+		return value
+	}
+
+	return quoteString(value)
+}
+
+// clonePosition returns the position of the cloned data, its GTID set or its
+// binlog coordinates on a server without GTIDs.
+func clonePosition(gtid, binlogFile string, binlogPos int64) string {
+	if gtid = strings.ReplaceAll(strings.TrimSpace(gtid), "\n", ""); gtid != "" {
+		return gtid
+	}
+	if binlogFile != "" {
+		return fmt.Sprintf("%s:%d", binlogFile, binlogPos)
+	}
+
+	return ""
+}
+
+// quoteString quotes a string literal, for the statements which do not accept placeholders
+func quoteString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `''`)
+	return "'" + value + "'"
+}
+
+// isReadOnlyVariableError reports whether the server refused to change a read only variable at runtime
+func isReadOnlyVariableError(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == erReadOnlyVariable
+}
+
+// isNonexistingGrantError reports whether the privilege to revoke was not held by the account
+func isNonexistingGrantError(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && (mysqlErr.Number == erNonexistingGrant || mysqlErr.Number == erNonexistingTableGrant)
+}
+
+// readOnlyVariableName returns the variable a read only variable error is about, or
+// an empty string for any other error
+func readOnlyVariableName(err error) string {
+	var mysqlErr *mysqldriver.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != erReadOnlyVariable {
+		return ""
+	}
+
+	if m := readOnlyVariableRE.FindStringSubmatch(mysqlErr.Message); m != nil {
+		return strings.ToLower(m[1])
+	}
+
+	return ""
+}
+
+// accountHost returns the host part of the account, which defaults to any host
+func accountHost(host string) string {
+	if host == "" {
+		return "%"
+	}
+
+	return host
+}
+
+// buildGrantSql builds a GRANT statement, the account is bound through placeholders
+func buildGrantSql(privilege *Privilege) (string, error) {
+	return buildPrivilegeSql(grantSql, privilege)
+}
+
+// buildRevokeSql builds a REVOKE statement, the account is bound through placeholders
+func buildRevokeSql(privilege *Privilege) (string, error) {
+	return buildPrivilegeSql(revokeSql, privilege)
+}
+
+func buildPrivilegeSql(format string, privilege *Privilege) (string, error) {
+	if len(privilege.GetPrivileges()) == 0 {
+		return "", fmt.Errorf("privileges of database %q are required", privilege.GetDatabase())
+	}
+
+	privileges := make([]string, 0, len(privilege.GetPrivileges()))
+	for _, p := range privilege.GetPrivileges() {
+		p = strings.ToUpper(strings.TrimSpace(p))
+		if !privilegeRE.MatchString(p) {
+			return "", fmt.Errorf("invalid privilege %q", p)
+		}
+		privileges = append(privileges, p)
+	}
+
+	database, err := quoteObjectName(privilege.GetDatabase())
+	if err != nil {
+		return "", err
+	}
+
+	table, err := quoteObjectName(privilege.GetTable())
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(format, strings.Join(privileges, ", "), database+"."+table), nil
+}
+
+func buildCreateDatabaseSql(database, characterSet, collation string) (string, error) {
+	if database == "" || database == "*" {
+		return "", fmt.Errorf("invalid database %q", database)
+	}
+
+	name, err := quoteObjectName(database)
+	if err != nil {
+		return "", err
+	}
+
+	options := ""
+	if characterSet != "" {
+		if !charsetRE.MatchString(characterSet) {
+			return "", fmt.Errorf("invalid character set %q", characterSet)
+		}
+		options += " CHARACTER SET " + characterSet
+	}
+	if collation != "" {
+		if !charsetRE.MatchString(collation) {
+			return "", fmt.Errorf("invalid collation %q", collation)
+		}
+		options += " COLLATE " + collation
+	}
+
+	return fmt.Sprintf(createDatabaseSql, name, options), nil
+}
+
+// quoteObjectName quotes a database or table name with backticks, empty or "*" means all objects
+func quoteObjectName(name string) (string, error) {
+	if name == "" || name == "*" {
+		return "*", nil
+	}
+
+	if strings.ContainsAny(name, "`\x00") {
+		return "", fmt.Errorf("invalid object name %q", name)
+	}
+
+	return "`" + name + "`", nil
+}
+
 // newDBConn creates a MySQL database connection
 func (s *service) newDBConn(ctx context.Context, username string) (*sql.DB, error) {
 	password, err := util.DecryptPlainTextPassword(username)
@@ -1,10 +1,13 @@
 package mysql
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -52,3 +55,137 @@ func TestRemoveContents(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, entries, 0)
 }
+
+func TestBuildGrantSql(t *testing.T) {
+	grant, err := buildGrantSql(&Privilege{Database: "app", Privileges: []string{"select", " insert "}})
+	require.NoError(t, err)
+	require.Equal(t, "GRANT SELECT, INSERT ON `app`.* TO ?@?;", grant)
+
+	grant, err = buildGrantSql(&Privilege{Database: "app", Table: "orders", Privileges: []string{"ALL PRIVILEGES"}})
+	require.NoError(t, err)
+	require.Equal(t, "GRANT ALL PRIVILEGES ON `app`.`orders` TO ?@?;", grant)
+
+	grant, err = buildGrantSql(&Privilege{Privileges: []string{"PROCESS"}})
+	require.NoError(t, err)
+	require.Equal(t, "GRANT PROCESS ON *.* TO ?@?;", grant)
+}
+
+func TestBuildGrantSqlRejectsInvalidInput(t *testing.T) {
+	_, err := buildGrantSql(&Privilege{Database: "app"})
+	require.Error(t, err)
+
+	_, err = buildGrantSql(&Privilege{Database: "app", Privileges: []string{"SELECT; DROP DATABASE app"}})
+	require.Error(t, err)
+
+	_, err = buildGrantSql(&Privilege{Database: "app`", Privileges: []string{"SELECT"}})
+	require.Error(t, err)
+}
+
+func TestBuildRevokeSql(t *testing.T) {
+	revoke, err := buildRevokeSql(&Privilege{Database: "app", Table: "orders", Privileges: []string{"insert"}})
+	require.NoError(t, err)
+	require.Equal(t, "REVOKE INSERT ON `app`.`orders` FROM ?@?;", revoke)
+
+	require.True(t, isNonexistingGrantError(&mysqldriver.MySQLError{Number: erNonexistingGrant}))
+	require.False(t, isNonexistingGrantError(&mysqldriver.MySQLError{Number: erReadOnlyVariable}))
+}
+
+func TestBuildCreateDatabaseSql(t *testing.T) {
+	execSQL, err := buildCreateDatabaseSql("app", "utf8mb4", "utf8mb4_bin")
+	require.NoError(t, err)
+	require.Equal(t, "CREATE DATABASE IF NOT EXISTS `app` CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;", execSQL)
+
+	execSQL, err = buildCreateDatabaseSql("app", "", "")
+	require.NoError(t, err)
+	require.Equal(t, "CREATE DATABASE IF NOT EXISTS `app`;", execSQL)
+
+	_, err = buildCreateDatabaseSql("", "", "")
+	require.Error(t, err)
+
+	_, err = buildCreateDatabaseSql("app", "utf8mb4; DROP", "")
+	require.Error(t, err)
+}
+
+func TestAccountHost(t *testing.T) {
+	require.Equal(t, "%", accountHost(""))
+	require.Equal(t, "10.0.0.%", accountHost("10.0.0.%"))
+}
+
+func TestNormalizeVariableName(t *testing.T) {
+	name, err := normalizeVariableName(" Max_Connections ")
+	require.NoError(t, err)
+	require.Equal(t, "max_connections", name)
+
+	name, err = normalizeVariableName("validate_password.policy")
+	require.NoError(t, err)
+	require.Equal(t, "validate_password.policy", name)
+
+	for _, invalid := range []string{"", "max_connections = 1; DROP", "a.b.c", "1abc", "`x`"} {
+		_, err = normalizeVariableName(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestFormatVariableValue(t *testing.T) {
+	require.Equal(t, "500", formatVariableValue("500"))
+	require.Equal(t, "-1", formatVariableValue("-1"))
+	require.Equal(t, "0.5", formatVariableValue("0.5"))
+	require.Equal(t, "'ON'", formatVariableValue("ON"))
+	require.Equal(t, "'1G'", formatVariableValue("1G"))
+	require.Equal(t, `'a''b\\c'`, formatVariableValue(`a'b\c`))
+	require.Equal(t, "'1234'", quoteString("1234"))
+}
+
+func TestNormalizeVariables(t *testing.T) {
+	variables, err := normalizeVariables(map[string]string{"Max_Connections": "500", " sql_mode ": "ANSI"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"max_connections": "500", "sql_mode": "ANSI"}, variables)
+
+	_, err = normalizeVariables(nil)
+	require.Error(t, err)
+
+	_, err = normalizeVariables(map[string]string{"a=1,b": "1"})
+	require.Error(t, err)
+}
+
+func TestBuildSetVariablesSql(t *testing.T) {
+	variables := map[string]string{"max_connections": "500", "sql_mode": "it's"}
+	keys := []string{"max_connections", "sql_mode"}
+
+	require.Equal(t, "SET GLOBAL max_connections = 500, GLOBAL sql_mode = 'it''s';", buildSetVariablesSql("GLOBAL", keys, variables, nil))
+	require.Equal(t, "SET PERSIST max_connections = 500, PERSIST sql_mode = 'it''s';", buildSetVariablesSql("PERSIST", keys, variables, nil))
+	require.Equal(t, "SET PERSIST_ONLY max_connections = 500, PERSIST sql_mode = 'it''s';",
+		buildSetVariablesSql("PERSIST", keys, variables, map[string]bool{"max_connections": true}))
+}
+
+func TestIsReadOnlyVariableError(t *testing.T) {
+	require.True(t, isReadOnlyVariableError(fmt.Errorf("wrapped: %w", &mysqldriver.MySQLError{Number: erReadOnlyVariable})))
+	require.False(t, isReadOnlyVariableError(&mysqldriver.MySQLError{Number: 1193}))
+	require.False(t, isReadOnlyVariableError(errors.New("other")))
+
+	require.Equal(t, "innodb_log_file_size", readOnlyVariableName(&mysqldriver.MySQLError{
+		Number:  erReadOnlyVariable,
+		Message: "Variable 'innodb_log_file_size' is a read only variable",
+	}))
+	require.Empty(t, readOnlyVariableName(&mysqldriver.MySQLError{Number: 1193, Message: "Unknown system variable 'foo'"}))
+}
+
+func TestReadXtrabackupInfoPosition(t *testing.T) {
+	dir := t.TempDir()
+
+	gtidFile := filepath.Join(dir, "gtid")
+	require.NoError(t, os.WriteFile(gtidFile, []byte("tool_name = xtrabackup\nbinlog_pos = filename 'binlog.000003', position '157', GTID of the last change 'uuid:1-5'\n"), 0o644))
+	require.Equal(t, "uuid:1-5", readXtrabackupInfoPosition(gtidFile))
+
+	fileOnly := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(fileOnly, []byte("binlog_pos = filename 'binlog.000003', position '157'\n"), 0o644))
+	require.Equal(t, "binlog.000003:157", readXtrabackupInfoPosition(fileOnly))
+
+	require.Empty(t, readXtrabackupInfoPosition(filepath.Join(dir, "missing")))
+}
+
+func TestClonePosition(t *testing.T) {
+	require.Equal(t, "uuid-a:1-5,uuid-b:1-3", clonePosition("uuid-a:1-5,\nuuid-b:1-3\n", "binlog.000003", 157))
+	require.Equal(t, "binlog.000003:157", clonePosition("", "binlog.000003", 157))
+	require.Empty(t, clonePosition("", "", 0))
+}
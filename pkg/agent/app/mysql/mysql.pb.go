@@ -185,6 +185,78 @@ func (x *CloneRequest) GetUsername() string {
 	return ""
 }
 
+// CloneResponse describes the data cloned from the donor
+type CloneResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// donor address, as host:port
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// bytes transferred from the donor
+	SizeBytes  int64 `protobuf:"varint,2,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	DurationMs int64 `protobuf:"varint,3,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	// GTID set of the cloned data, or binlog file:position without GTIDs
+	Position      string `protobuf:"bytes,4,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloneResponse) Reset() {
+	*x = CloneResponse{}
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloneResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneResponse) ProtoMessage() {}
+
+func (x *CloneResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneResponse.ProtoReflect.Descriptor instead.
+func (*CloneResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CloneResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CloneResponse) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *CloneResponse) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *CloneResponse) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
 type LogicalBackupRequest struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	BackupFile        string                 `protobuf:"bytes,1,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
@@ -199,7 +271,7 @@ type LogicalBackupRequest struct {
 
 func (x *LogicalBackupRequest) Reset() {
 	*x = LogicalBackupRequest{}
-	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[1]
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -211,7 +283,7 @@ func (x *LogicalBackupRequest) String() string {
 func (*LogicalBackupRequest) ProtoMessage() {}
 
 func (x *LogicalBackupRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[1]
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -224,7 +296,7 @@ func (x *LogicalBackupRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogicalBackupRequest.ProtoReflect.Descriptor instead.
 func (*LogicalBackupRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{1}
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *LogicalBackupRequest) GetBackupFile() string {
@@ -281,7 +353,7 @@ type PhysicalBackupRequest struct {
 
 func (x *PhysicalBackupRequest) Reset() {
 	*x = PhysicalBackupRequest{}
-	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[2]
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -293,7 +365,7 @@ func (x *PhysicalBackupRequest) String() string {
 func (*PhysicalBackupRequest) ProtoMessage() {}
 
 func (x *PhysicalBackupRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[2]
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -306,7 +378,7 @@ func (x *PhysicalBackupRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PhysicalBackupRequest.ProtoReflect.Descriptor instead.
 func (*PhysicalBackupRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{2}
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *PhysicalBackupRequest) GetBackupFile() string {
@@ -348,7 +420,7 @@ type RestoreRequest struct {
 
 func (x *RestoreRequest) Reset() {
 	*x = RestoreRequest{}
-	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[3]
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -360,7 +432,7 @@ func (x *RestoreRequest) String() string {
 func (*RestoreRequest) ProtoMessage() {}
 
 func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[3]
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -373,7 +445,7 @@ func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RestoreRequest.ProtoReflect.Descriptor instead.
 func (*RestoreRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{3}
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *RestoreRequest) GetBackupFile() string {
@@ -406,7 +478,7 @@ type GtidPurgeRequest struct {
 
 func (x *GtidPurgeRequest) Reset() {
 	*x = GtidPurgeRequest{}
-	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[4]
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -418,7 +490,7 @@ func (x *GtidPurgeRequest) String() string {
 func (*GtidPurgeRequest) ProtoMessage() {}
 
 func (x *GtidPurgeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[4]
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -431,7 +503,7 @@ func (x *GtidPurgeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GtidPurgeRequest.ProtoReflect.Descriptor instead.
 func (*GtidPurgeRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{4}
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GtidPurgeRequest) GetUsername() string {
@@ -446,13 +518,14 @@ type SetVariableRequest struct {
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Persist       bool                   `protobuf:"varint,4,opt,name=persist,proto3" json:"persist,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SetVariableRequest) Reset() {
 	*x = SetVariableRequest{}
-	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[5]
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -464,7 +537,7 @@ func (x *SetVariableRequest) String() string {
 func (*SetVariableRequest) ProtoMessage() {}
 
 func (x *SetVariableRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[5]
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -477,7 +550,7 @@ func (x *SetVariableRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetVariableRequest.ProtoReflect.Descriptor instead.
 func (*SetVariableRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{5}
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *SetVariableRequest) GetKey() string {
@@ -501,6 +574,430 @@ func (x *SetVariableRequest) GetUsername() string {
 	return ""
 }
 
+func (x *SetVariableRequest) GetPersist() bool {
+	if x != nil {
+		return x.Persist
+	}
+	return false
+}
+
+type SetVariablesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variables     map[string]string      `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Persist       bool                   `protobuf:"varint,3,opt,name=persist,proto3" json:"persist,omitempty"`
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVariablesRequest) Reset() {
+	*x = SetVariablesRequest{}
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariablesRequest) ProtoMessage() {}
+
+func (x *SetVariablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariablesRequest.ProtoReflect.Descriptor instead.
+func (*SetVariablesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SetVariablesRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *SetVariablesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetVariablesRequest) GetPersist() bool {
+	if x != nil {
+		return x.Persist
+	}
+	return false
+}
+
+func (x *SetVariablesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type Privilege struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Database      string                 `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Table         string                 `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Privileges    []string               `protobuf:"bytes,3,rep,name=privileges,proto3" json:"privileges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Privilege) Reset() {
+	*x = Privilege{}
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Privilege) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Privilege) ProtoMessage() {}
+
+func (x *Privilege) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Privilege.ProtoReflect.Descriptor instead.
+func (*Privilege) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Privilege) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *Privilege) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *Privilege) GetPrivileges() []string {
+	if x != nil {
+		return x.Privileges
+	}
+	return nil
+}
+
+type CreateUserRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Username   string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User       string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Host       string                 `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	Password   string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	Privileges []*Privilege           `protobuf:"bytes,5,rep,name=privileges,proto3" json:"privileges,omitempty"`
+	// revokes are the privileges removed from the user since the last call
+	Revokes       []*Privilege `protobuf:"bytes,6,rep,name=revokes,proto3" json:"revokes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPrivileges() []*Privilege {
+	if x != nil {
+		return x.Privileges
+	}
+	return nil
+}
+
+func (x *CreateUserRequest) GetRevokes() []*Privilege {
+	if x != nil {
+		return x.Revokes
+	}
+	return nil
+}
+
+type DropUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Host          string                 `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropUserRequest) Reset() {
+	*x = DropUserRequest{}
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropUserRequest) ProtoMessage() {}
+
+func (x *DropUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropUserRequest.ProtoReflect.Descriptor instead.
+func (*DropUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DropUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *DropUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *DropUserRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+type CreateDatabaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Database      string                 `protobuf:"bytes,2,opt,name=database,proto3" json:"database,omitempty"`
+	CharacterSet  string                 `protobuf:"bytes,3,opt,name=character_set,json=characterSet,proto3" json:"character_set,omitempty"`
+	Collation     string                 `protobuf:"bytes,4,opt,name=collation,proto3" json:"collation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDatabaseRequest) Reset() {
+	*x = CreateDatabaseRequest{}
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDatabaseRequest) ProtoMessage() {}
+
+func (x *CreateDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*CreateDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateDatabaseRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateDatabaseRequest) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *CreateDatabaseRequest) GetCharacterSet() string {
+	if x != nil {
+		return x.CharacterSet
+	}
+	return ""
+}
+
+func (x *CreateDatabaseRequest) GetCollation() string {
+	if x != nil {
+		return x.Collation
+	}
+	return ""
+}
+
+type RotatePasswordRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Username              string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User                  string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Host                  string                 `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	Password              string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	RetainCurrentPassword bool                   `protobuf:"varint,5,opt,name=retain_current_password,json=retainCurrentPassword,proto3" json:"retain_current_password,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *RotatePasswordRequest) Reset() {
+	*x = RotatePasswordRequest{}
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotatePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotatePasswordRequest) ProtoMessage() {}
+
+func (x *RotatePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotatePasswordRequest.ProtoReflect.Descriptor instead.
+func (*RotatePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RotatePasswordRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetRetainCurrentPassword() bool {
+	if x != nil {
+		return x.RetainCurrentPassword
+	}
+	return false
+}
+
 var File_pkg_agent_app_mysql_pb_mysql_proto protoreflect.FileDescriptor
 
 const file_pkg_agent_app_mysql_pb_mysql_proto_rawDesc = "" +
@@ -512,7 +1009,14 @@ const file_pkg_agent_app_mysql_pb_mysql_proto_rawDesc = "" +
 	"sourceHost\x12\x1f\n" +
 	"\vsource_port\x18\x03 \x01(\x03R\n" +
 	"sourcePort\x12\x1a\n" +
-	"\busername\x18\x04 \x01(\tR\busername\"\x8d\x02\n" +
+	"\busername\x18\x04 \x01(\tR\busername\"\x83\x01\n" +
+	"\rCloneResponse\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x02 \x01(\x03R\tsizeBytes\x12\x1f\n" +
+	"\vduration_ms\x18\x03 \x01(\x03R\n" +
+	"durationMs\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\tR\bposition\"\x8d\x02\n" +
 	"\x14LogicalBackupRequest\x12\x1f\n" +
 	"\vbackup_file\x18\x01 \x01(\tR\n" +
 	"backupFile\x12\x1a\n" +
@@ -533,11 +1037,50 @@ const file_pkg_agent_app_mysql_pb_mysql_proto_rawDesc = "" +
 	"\x04tool\x18\x02 \x01(\x0e2\v.mysql.ToolR\x04tool\x12<\n" +
 	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\".\n" +
 	"\x10GtidPurgeRequest\x12\x1a\n" +
-	"\busername\x18\x01 \x01(\tR\busername\"X\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"r\n" +
 	"\x12SetVariableRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1a\n" +
-	"\busername\x18\x03 \x01(\tR\busername*\x1f\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x18\n" +
+	"\apersist\x18\x04 \x01(\bR\apersist\"\xeb\x01\n" +
+	"\x13SetVariablesRequest\x12G\n" +
+	"\tvariables\x18\x01 \x03(\v2).mysql.SetVariablesRequest.VariablesEntryR\tvariables\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x18\n" +
+	"\apersist\x18\x03 \x01(\bR\apersist\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"]\n" +
+	"\tPrivilege\x12\x1a\n" +
+	"\bdatabase\x18\x01 \x01(\tR\bdatabase\x12\x14\n" +
+	"\x05table\x18\x02 \x01(\tR\x05table\x12\x1e\n" +
+	"\n" +
+	"privileges\x18\x03 \x03(\tR\n" +
+	"privileges\"\xd1\x01\n" +
+	"\x11CreateUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x12\n" +
+	"\x04host\x18\x03 \x01(\tR\x04host\x12\x1a\n" +
+	"\bpassword\x18\x04 \x01(\tR\bpassword\x120\n" +
+	"\n" +
+	"privileges\x18\x05 \x03(\v2\x10.mysql.PrivilegeR\n" +
+	"privileges\x12*\n" +
+	"\arevokes\x18\x06 \x03(\v2\x10.mysql.PrivilegeR\arevokes\"U\n" +
+	"\x0fDropUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x12\n" +
+	"\x04host\x18\x03 \x01(\tR\x04host\"\x92\x01\n" +
+	"\x15CreateDatabaseRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
+	"\bdatabase\x18\x02 \x01(\tR\bdatabase\x12#\n" +
+	"\rcharacter_set\x18\x03 \x01(\tR\fcharacterSet\x12\x1c\n" +
+	"\tcollation\x18\x04 \x01(\tR\tcollation\"\xaf\x01\n" +
+	"\x15RotatePasswordRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x12\n" +
+	"\x04host\x18\x03 \x01(\tR\x04host\x12\x1a\n" +
+	"\bpassword\x18\x04 \x01(\tR\bpassword\x126\n" +
+	"\x17retain_current_password\x18\x05 \x01(\bR\x15retainCurrentPassword*\x1f\n" +
 	"\x04Tool\x12\x0e\n" +
 	"\n" +
 	"Xtrabackup\x10\x00\x12\a\n" +
@@ -545,14 +1088,20 @@ const file_pkg_agent_app_mysql_pb_mysql_proto_rawDesc = "" +
 	"\x11LogicalBackupMode\x12\b\n" +
 	"\x04Full\x10\x00\x12\f\n" +
 	"\bDatabase\x10\x01\x12\t\n" +
-	"\x05Table\x10\x022\xd8\x02\n" +
-	"\x0eMysqlOperation\x12+\n" +
-	"\x05Clone\x12\x13.mysql.CloneRequest\x1a\r.common.Empty\x12=\n" +
-	"\x0ePhysicalBackup\x12\x1c.mysql.PhysicalBackupRequest\x1a\r.common.Empty\x12;\n" +
-	"\rLogicalBackup\x12\x1b.mysql.LogicalBackupRequest\x1a\r.common.Empty\x12/\n" +
-	"\aRestore\x12\x15.mysql.RestoreRequest\x1a\r.common.Empty\x123\n" +
-	"\tGtidPurge\x12\x17.mysql.GtidPurgeRequest\x1a\r.common.Empty\x127\n" +
-	"\vSetVariable\x12\x19.mysql.SetVariableRequest\x1a\r.common.EmptyB4Z2github.com/upmio/unit-operator/pkg/agent/app/mysqlb\x06proto3"
+	"\x05Table\x10\x022\xbb\x05\n" +
+	"\x0eMysqlOperation\x122\n" +
+	"\x05Clone\x12\x13.mysql.CloneRequest\x1a\x14.mysql.CloneResponse\x12F\n" +
+	"\x0ePhysicalBackup\x12\x1c.mysql.PhysicalBackupRequest\x1a\x16.common.BackupResponse\x12D\n" +
+	"\rLogicalBackup\x12\x1b.mysql.LogicalBackupRequest\x1a\x16.common.BackupResponse\x129\n" +
+	"\aRestore\x12\x15.mysql.RestoreRequest\x1a\x17.common.RestoreResponse\x123\n" +
+	"\tGtidPurge\x12\x17.mysql.GtidPurgeRequest\x1a\r.common.Empty\x12E\n" +
+	"\vSetVariable\x12\x19.mysql.SetVariableRequest\x1a\x1b.common.SetVariableResponse\x12H\n" +
+	"\fSetVariables\x12\x1a.mysql.SetVariablesRequest\x1a\x1c.common.SetVariablesResponse\x125\n" +
+	"\n" +
+	"CreateUser\x12\x18.mysql.CreateUserRequest\x1a\r.common.Empty\x121\n" +
+	"\bDropUser\x12\x16.mysql.DropUserRequest\x1a\r.common.Empty\x12=\n" +
+	"\x0eCreateDatabase\x12\x1c.mysql.CreateDatabaseRequest\x1a\r.common.Empty\x12=\n" +
+	"\x0eRotatePassword\x12\x1c.mysql.RotatePasswordRequest\x1a\r.common.EmptyB4Z2github.com/upmio/unit-operator/pkg/agent/app/mysqlb\x06proto3"
 
 var (
 	file_pkg_agent_app_mysql_pb_mysql_proto_rawDescOnce sync.Once
@@ -567,43 +1116,68 @@ func file_pkg_agent_app_mysql_pb_mysql_proto_rawDescGZIP() []byte {
 }
 
 var file_pkg_agent_app_mysql_pb_mysql_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_pkg_agent_app_mysql_pb_mysql_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_pkg_agent_app_mysql_pb_mysql_proto_goTypes = []any{
-	(Tool)(0),                     // 0: mysql.Tool
-	(LogicalBackupMode)(0),        // 1: mysql.LogicalBackupMode
-	(*CloneRequest)(nil),          // 2: mysql.CloneRequest
-	(*LogicalBackupRequest)(nil),  // 3: mysql.LogicalBackupRequest
-	(*PhysicalBackupRequest)(nil), // 4: mysql.PhysicalBackupRequest
-	(*RestoreRequest)(nil),        // 5: mysql.RestoreRequest
-	(*GtidPurgeRequest)(nil),      // 6: mysql.GtidPurgeRequest
-	(*SetVariableRequest)(nil),    // 7: mysql.SetVariableRequest
-	(*common.ObjectStorage)(nil),  // 8: common.ObjectStorage
-	(*common.Empty)(nil),          // 9: common.Empty
+	(Tool)(0),                           // 0: mysql.Tool
+	(LogicalBackupMode)(0),              // 1: mysql.LogicalBackupMode
+	(*CloneRequest)(nil),                // 2: mysql.CloneRequest
+	(*CloneResponse)(nil),               // 3: mysql.CloneResponse
+	(*LogicalBackupRequest)(nil),        // 4: mysql.LogicalBackupRequest
+	(*PhysicalBackupRequest)(nil),       // 5: mysql.PhysicalBackupRequest
+	(*RestoreRequest)(nil),              // 6: mysql.RestoreRequest
+	(*GtidPurgeRequest)(nil),            // 7: mysql.GtidPurgeRequest
+	(*SetVariableRequest)(nil),          // 8: mysql.SetVariableRequest
+	(*SetVariablesRequest)(nil),         // 9: mysql.SetVariablesRequest
+	(*Privilege)(nil),                   // 10: mysql.Privilege
+	(*CreateUserRequest)(nil),           // 11: mysql.CreateUserRequest
+	(*DropUserRequest)(nil),             // 12: mysql.DropUserRequest
+	(*CreateDatabaseRequest)(nil),       // 13: mysql.CreateDatabaseRequest
+	(*RotatePasswordRequest)(nil),       // 14: mysql.RotatePasswordRequest
+	nil,                                 // 15: mysql.SetVariablesRequest.VariablesEntry
+	(*common.ObjectStorage)(nil),        // 16: common.ObjectStorage
+	(*common.BackupResponse)(nil),       // 17: common.BackupResponse
+	(*common.RestoreResponse)(nil),      // 18: common.RestoreResponse
+	(*common.Empty)(nil),                // 19: common.Empty
+	(*common.SetVariableResponse)(nil),  // 20: common.SetVariableResponse
+	(*common.SetVariablesResponse)(nil), // 21: common.SetVariablesResponse
 }
 var file_pkg_agent_app_mysql_pb_mysql_proto_depIdxs = []int32{
 	1,  // 0: mysql.LogicalBackupRequest.logical_backup_mode:type_name -> mysql.LogicalBackupMode
-	8,  // 1: mysql.LogicalBackupRequest.object_storage:type_name -> common.ObjectStorage
+	16, // 1: mysql.LogicalBackupRequest.object_storage:type_name -> common.ObjectStorage
 	0,  // 2: mysql.PhysicalBackupRequest.tool:type_name -> mysql.Tool
-	8,  // 3: mysql.PhysicalBackupRequest.object_storage:type_name -> common.ObjectStorage
+	16, // 3: mysql.PhysicalBackupRequest.object_storage:type_name -> common.ObjectStorage
 	0,  // 4: mysql.RestoreRequest.tool:type_name -> mysql.Tool
-	8,  // 5: mysql.RestoreRequest.object_storage:type_name -> common.ObjectStorage
-	2,  // 6: mysql.MysqlOperation.Clone:input_type -> mysql.CloneRequest
-	4,  // 7: mysql.MysqlOperation.PhysicalBackup:input_type -> mysql.PhysicalBackupRequest
-	3,  // 8: mysql.MysqlOperation.LogicalBackup:input_type -> mysql.LogicalBackupRequest
-	5,  // 9: mysql.MysqlOperation.Restore:input_type -> mysql.RestoreRequest
-	6,  // 10: mysql.MysqlOperation.GtidPurge:input_type -> mysql.GtidPurgeRequest
-	7,  // 11: mysql.MysqlOperation.SetVariable:input_type -> mysql.SetVariableRequest
-	9,  // 12: mysql.MysqlOperation.Clone:output_type -> common.Empty
-	9,  // 13: mysql.MysqlOperation.PhysicalBackup:output_type -> common.Empty
-	9,  // 14: mysql.MysqlOperation.LogicalBackup:output_type -> common.Empty
-	9,  // 15: mysql.MysqlOperation.Restore:output_type -> common.Empty
-	9,  // 16: mysql.MysqlOperation.GtidPurge:output_type -> common.Empty
-	9,  // 17: mysql.MysqlOperation.SetVariable:output_type -> common.Empty
-	12, // [12:18] is the sub-list for method output_type
-	6,  // [6:12] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	16, // 5: mysql.RestoreRequest.object_storage:type_name -> common.ObjectStorage
+	15, // 6: mysql.SetVariablesRequest.variables:type_name -> mysql.SetVariablesRequest.VariablesEntry
+	10, // 7: mysql.CreateUserRequest.privileges:type_name -> mysql.Privilege
+	10, // 8: mysql.CreateUserRequest.revokes:type_name -> mysql.Privilege
+	2,  // 9: mysql.MysqlOperation.Clone:input_type -> mysql.CloneRequest
+	5,  // 10: mysql.MysqlOperation.PhysicalBackup:input_type -> mysql.PhysicalBackupRequest
+	4,  // 11: mysql.MysqlOperation.LogicalBackup:input_type -> mysql.LogicalBackupRequest
+	6,  // 12: mysql.MysqlOperation.Restore:input_type -> mysql.RestoreRequest
+	7,  // 13: mysql.MysqlOperation.GtidPurge:input_type -> mysql.GtidPurgeRequest
+	8,  // 14: mysql.MysqlOperation.SetVariable:input_type -> mysql.SetVariableRequest
+	9,  // 15: mysql.MysqlOperation.SetVariables:input_type -> mysql.SetVariablesRequest
+	11, // 16: mysql.MysqlOperation.CreateUser:input_type -> mysql.CreateUserRequest
+	12, // 17: mysql.MysqlOperation.DropUser:input_type -> mysql.DropUserRequest
+	13, // 18: mysql.MysqlOperation.CreateDatabase:input_type -> mysql.CreateDatabaseRequest
+	14, // 19: mysql.MysqlOperation.RotatePassword:input_type -> mysql.RotatePasswordRequest
+	3,  // 20: mysql.MysqlOperation.Clone:output_type -> mysql.CloneResponse
+	17, // 21: mysql.MysqlOperation.PhysicalBackup:output_type -> common.BackupResponse
+	17, // 22: mysql.MysqlOperation.LogicalBackup:output_type -> common.BackupResponse
+	18, // 23: mysql.MysqlOperation.Restore:output_type -> common.RestoreResponse
+	19, // 24: mysql.MysqlOperation.GtidPurge:output_type -> common.Empty
+	20, // 25: mysql.MysqlOperation.SetVariable:output_type -> common.SetVariableResponse
+	21, // 26: mysql.MysqlOperation.SetVariables:output_type -> common.SetVariablesResponse
+	19, // 27: mysql.MysqlOperation.CreateUser:output_type -> common.Empty
+	19, // 28: mysql.MysqlOperation.DropUser:output_type -> common.Empty
+	19, // 29: mysql.MysqlOperation.CreateDatabase:output_type -> common.Empty
+	19, // 30: mysql.MysqlOperation.RotatePassword:output_type -> common.Empty
+	20, // [20:31] is the sub-list for method output_type
+	9,  // [9:20] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_pkg_agent_app_mysql_pb_mysql_proto_init() }
@@ -617,7 +1191,7 @@ func file_pkg_agent_app_mysql_pb_mysql_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_mysql_pb_mysql_proto_rawDesc), len(file_pkg_agent_app_mysql_pb_mysql_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   6,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
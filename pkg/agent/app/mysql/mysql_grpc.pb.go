@@ -19,12 +19,17 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MysqlOperationClient interface {
-	Clone(ctx context.Context, in *CloneRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	PhysicalBackup(ctx context.Context, in *PhysicalBackupRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	Clone(ctx context.Context, in *CloneRequest, opts ...grpc.CallOption) (*CloneResponse, error)
+	PhysicalBackup(ctx context.Context, in *PhysicalBackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error)
+	LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error)
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.RestoreResponse, error)
 	GtidPurge(ctx context.Context, in *GtidPurgeRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error)
+	SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	DropUser(ctx context.Context, in *DropUserRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	CreateDatabase(ctx context.Context, in *CreateDatabaseRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	RotatePassword(ctx context.Context, in *RotatePasswordRequest, opts ...grpc.CallOption) (*common.Empty, error)
 }
 
 type mysqlOperationClient struct {
@@ -35,8 +40,8 @@ func NewMysqlOperationClient(cc grpc.ClientConnInterface) MysqlOperationClient {
 	return &mysqlOperationClient{cc}
 }
 
-func (c *mysqlOperationClient) Clone(ctx context.Context, in *CloneRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *mysqlOperationClient) Clone(ctx context.Context, in *CloneRequest, opts ...grpc.CallOption) (*CloneResponse, error) {
+	out := new(CloneResponse)
 	err := c.cc.Invoke(ctx, "/mysql.MysqlOperation/Clone", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -44,8 +49,8 @@ func (c *mysqlOperationClient) Clone(ctx context.Context, in *CloneRequest, opts
 	return out, nil
 }
 
-func (c *mysqlOperationClient) PhysicalBackup(ctx context.Context, in *PhysicalBackupRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *mysqlOperationClient) PhysicalBackup(ctx context.Context, in *PhysicalBackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error) {
+	out := new(common.BackupResponse)
 	err := c.cc.Invoke(ctx, "/mysql.MysqlOperation/PhysicalBackup", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -53,8 +58,8 @@ func (c *mysqlOperationClient) PhysicalBackup(ctx context.Context, in *PhysicalB
 	return out, nil
 }
 
-func (c *mysqlOperationClient) LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *mysqlOperationClient) LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error) {
+	out := new(common.BackupResponse)
 	err := c.cc.Invoke(ctx, "/mysql.MysqlOperation/LogicalBackup", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -62,8 +67,8 @@ func (c *mysqlOperationClient) LogicalBackup(ctx context.Context, in *LogicalBac
 	return out, nil
 }
 
-func (c *mysqlOperationClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *mysqlOperationClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.RestoreResponse, error) {
+	out := new(common.RestoreResponse)
 	err := c.cc.Invoke(ctx, "/mysql.MysqlOperation/Restore", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -80,8 +85,8 @@ func (c *mysqlOperationClient) GtidPurge(ctx context.Context, in *GtidPurgeReque
 	return out, nil
 }
 
-func (c *mysqlOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *mysqlOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error) {
+	out := new(common.SetVariableResponse)
 	err := c.cc.Invoke(ctx, "/mysql.MysqlOperation/SetVariable", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -89,16 +94,66 @@ func (c *mysqlOperationClient) SetVariable(ctx context.Context, in *SetVariableR
 	return out, nil
 }
 
+func (c *mysqlOperationClient) SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error) {
+	out := new(common.SetVariablesResponse)
+	err := c.cc.Invoke(ctx, "/mysql.MysqlOperation/SetVariables", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mysqlOperationClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mysql.MysqlOperation/CreateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mysqlOperationClient) DropUser(ctx context.Context, in *DropUserRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mysql.MysqlOperation/DropUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mysqlOperationClient) CreateDatabase(ctx context.Context, in *CreateDatabaseRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mysql.MysqlOperation/CreateDatabase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mysqlOperationClient) RotatePassword(ctx context.Context, in *RotatePasswordRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/mysql.MysqlOperation/RotatePassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MysqlOperationServer is the server API for MysqlOperation service.
 // All implementations must embed UnimplementedMysqlOperationServer
 // for forward compatibility
 type MysqlOperationServer interface {
-	Clone(context.Context, *CloneRequest) (*common.Empty, error)
-	PhysicalBackup(context.Context, *PhysicalBackupRequest) (*common.Empty, error)
-	LogicalBackup(context.Context, *LogicalBackupRequest) (*common.Empty, error)
-	Restore(context.Context, *RestoreRequest) (*common.Empty, error)
+	Clone(context.Context, *CloneRequest) (*CloneResponse, error)
+	PhysicalBackup(context.Context, *PhysicalBackupRequest) (*common.BackupResponse, error)
+	LogicalBackup(context.Context, *LogicalBackupRequest) (*common.BackupResponse, error)
+	Restore(context.Context, *RestoreRequest) (*common.RestoreResponse, error)
 	GtidPurge(context.Context, *GtidPurgeRequest) (*common.Empty, error)
-	SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error)
+	SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error)
+	SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error)
+	CreateUser(context.Context, *CreateUserRequest) (*common.Empty, error)
+	DropUser(context.Context, *DropUserRequest) (*common.Empty, error)
+	CreateDatabase(context.Context, *CreateDatabaseRequest) (*common.Empty, error)
+	RotatePassword(context.Context, *RotatePasswordRequest) (*common.Empty, error)
 	mustEmbedUnimplementedMysqlOperationServer()
 }
 
@@ -106,24 +161,39 @@ type MysqlOperationServer interface {
 type UnimplementedMysqlOperationServer struct {
 }
 
-func (UnimplementedMysqlOperationServer) Clone(context.Context, *CloneRequest) (*common.Empty, error) {
+func (UnimplementedMysqlOperationServer) Clone(context.Context, *CloneRequest) (*CloneResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Clone not implemented")
 }
-func (UnimplementedMysqlOperationServer) PhysicalBackup(context.Context, *PhysicalBackupRequest) (*common.Empty, error) {
+func (UnimplementedMysqlOperationServer) PhysicalBackup(context.Context, *PhysicalBackupRequest) (*common.BackupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PhysicalBackup not implemented")
 }
-func (UnimplementedMysqlOperationServer) LogicalBackup(context.Context, *LogicalBackupRequest) (*common.Empty, error) {
+func (UnimplementedMysqlOperationServer) LogicalBackup(context.Context, *LogicalBackupRequest) (*common.BackupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LogicalBackup not implemented")
 }
-func (UnimplementedMysqlOperationServer) Restore(context.Context, *RestoreRequest) (*common.Empty, error) {
+func (UnimplementedMysqlOperationServer) Restore(context.Context, *RestoreRequest) (*common.RestoreResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
 }
 func (UnimplementedMysqlOperationServer) GtidPurge(context.Context, *GtidPurgeRequest) (*common.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GtidPurge not implemented")
 }
-func (UnimplementedMysqlOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error) {
+func (UnimplementedMysqlOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetVariable not implemented")
 }
+func (UnimplementedMysqlOperationServer) SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVariables not implemented")
+}
+func (UnimplementedMysqlOperationServer) CreateUser(context.Context, *CreateUserRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedMysqlOperationServer) DropUser(context.Context, *DropUserRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropUser not implemented")
+}
+func (UnimplementedMysqlOperationServer) CreateDatabase(context.Context, *CreateDatabaseRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDatabase not implemented")
+}
+func (UnimplementedMysqlOperationServer) RotatePassword(context.Context, *RotatePasswordRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotatePassword not implemented")
+}
 func (UnimplementedMysqlOperationServer) mustEmbedUnimplementedMysqlOperationServer() {}
 
 // UnsafeMysqlOperationServer may be embedded to opt out of forward compatibility for this service.
@@ -245,6 +315,96 @@ func _MysqlOperation_SetVariable_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MysqlOperation_SetVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVariablesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MysqlOperationServer).SetVariables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mysql.MysqlOperation/SetVariables",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MysqlOperationServer).SetVariables(ctx, req.(*SetVariablesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MysqlOperation_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MysqlOperationServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mysql.MysqlOperation/CreateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MysqlOperationServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MysqlOperation_DropUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MysqlOperationServer).DropUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mysql.MysqlOperation/DropUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MysqlOperationServer).DropUser(ctx, req.(*DropUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MysqlOperation_CreateDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDatabaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MysqlOperationServer).CreateDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mysql.MysqlOperation/CreateDatabase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MysqlOperationServer).CreateDatabase(ctx, req.(*CreateDatabaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MysqlOperation_RotatePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotatePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MysqlOperationServer).RotatePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mysql.MysqlOperation/RotatePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MysqlOperationServer).RotatePassword(ctx, req.(*RotatePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MysqlOperation_ServiceDesc is the grpc.ServiceDesc for MysqlOperation service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -276,6 +436,26 @@ var MysqlOperation_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetVariable",
 			Handler:    _MysqlOperation_SetVariable_Handler,
 		},
+		{
+			MethodName: "SetVariables",
+			Handler:    _MysqlOperation_SetVariables_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _MysqlOperation_CreateUser_Handler,
+		},
+		{
+			MethodName: "DropUser",
+			Handler:    _MysqlOperation_DropUser_Handler,
+		},
+		{
+			MethodName: "CreateDatabase",
+			Handler:    _MysqlOperation_CreateDatabase_Handler,
+		},
+		{
+			MethodName: "RotatePassword",
+			Handler:    _MysqlOperation_RotatePassword_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/agent/app/mysql/pb/mysql.proto",
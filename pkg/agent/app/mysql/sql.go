@@ -4,6 +4,28 @@ const (
 	checkCloneAvaliableSql = `SELECT PLUGIN_STATUS FROM INFORMATION_SCHEMA.PLUGINS  WHERE PLUGIN_NAME = 'clone';`
 	SetValidDonorListSql   = `SET GLOBAL clone_valid_donor_list = ?;`
 	ExecCloneSql           = `CLONE INSTANCE FROM %s@'%s':%d IDENTIFIED BY '%s';`
-	getCloneStatusSql      = `SELECT STATE, ERROR_MESSAGE FROM performance_schema.clone_status;`
+	getCloneStatusSql      = `SELECT STATE, ERROR_MESSAGE, COALESCE(GTID_EXECUTED, ''), COALESCE(BINLOG_FILE, ''), COALESCE(BINLOG_POSITION, 0) FROM performance_schema.clone_status;`
+	getCloneDataSql        = `SELECT COALESCE(SUM(DATA), 0) FROM performance_schema.clone_progress;`
 	setVariableSql         = `SET GLOBAL %s = %s;`
+	persistVariableSql     = `SET PERSIST %s = %s;`
+	persistOnlyVariableSql = `SET PERSIST_ONLY %s = %s;`
+	getVariableSql         = `SELECT VARIABLE_VALUE FROM performance_schema.global_variables WHERE VARIABLE_NAME = ?;`
+	checkReadOnlySql       = `SELECT @@GLOBAL.read_only OR @@GLOBAL.super_read_only;`
+	createUserSql          = `CREATE USER IF NOT EXISTS ?@? IDENTIFIED BY ?;`
+	alterUserPasswordSql   = `ALTER USER ?@? IDENTIFIED BY ?;`
+	retainUserPasswordSql  = `ALTER USER ?@? IDENTIFIED BY ? RETAIN CURRENT PASSWORD;`
+	dropUserSql            = `DROP USER IF EXISTS ?@?;`
+	grantSql               = `GRANT %s ON %s TO ?@?;`
+	revokeSql              = `REVOKE %s ON %s FROM ?@?;`
+	createDatabaseSql      = "CREATE DATABASE IF NOT EXISTS %s%s;"
+
+	// replicationChannelsSql lists the replication channels connecting to their source as the user
+	replicationChannelsSql  = `SELECT c.CHANNEL_NAME, s.SERVICE_STATE = 'ON' FROM performance_schema.replication_connection_configuration c JOIN performance_schema.replication_connection_status s USING (CHANNEL_NAME) WHERE c.USER = ?;`
+	stopReplicaIOSql        = `STOP REPLICA IO_THREAD FOR CHANNEL %s;`
+	startReplicaIOSql       = `START REPLICA IO_THREAD FOR CHANNEL %s;`
+	changeSourcePasswordSql = `CHANGE REPLICATION SOURCE TO SOURCE_PASSWORD = %s FOR CHANNEL %s;`
+
+	// groupReplicationRecoveryChannel is the channel group replication members use to catch up,
+	// its credentials are only read when a member joins
+	groupReplicationRecoveryChannel = "group_replication_recovery"
 )
@@ -0,0 +1,18 @@
+package operation
+
+import "time"
+
+const (
+	appName = "operation"
+
+	// AsyncMetadataKey asks the unit-agent to run an RPC as an operation
+	AsyncMetadataKey = "x-unit-agent-async"
+	// IDMetadataKey carries the operation id, requested by the client or
+	// returned by the unit-agent in the response header
+	IDMetadataKey = "x-unit-agent-operation-id"
+
+	// operationDirName is created under LOG_MOUNT, restores wipe DATA_DIR
+	operationDirName = "operations"
+	// operationRetention is how long finished operations are kept
+	operationRetention = 7 * 24 * time.Hour
+)
@@ -0,0 +1,140 @@
+package operation
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/upmio/unit-operator/pkg/agent/app"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+	"github.com/upmio/unit-operator/pkg/agent/vars"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// service instance
+	svr = &service{}
+)
+
+type service struct {
+	operation OperationsServer
+	UnimplementedOperationsServer
+	logger  *zap.SugaredLogger
+	manager *manager
+}
+
+func (s *service) Config() error {
+	s.operation = app.GetGrpcApp(appName).(OperationsServer)
+	s.logger = zap.L().Named(appName).Sugar()
+
+	if s.manager != nil {
+		return nil
+	}
+
+	// operations are only kept in memory when the unit has no log volume
+	var dir string
+	if logMount, err := util.IsEnvVarSet(vars.LogMountEnvKey); err == nil {
+		dir = filepath.Join(logMount, operationDirName)
+	} else {
+		s.logger.Warnw("operations are not persisted", zap.Error(err))
+	}
+
+	m, err := newManager(dir, s.logger)
+	if err != nil {
+		return err
+	}
+	s.manager = m
+
+	return nil
+}
+
+func (s *service) Name() string {
+	return appName
+}
+
+func (s *service) Registry(server *grpc.Server) {
+	RegisterOperationsServer(server, svr)
+}
+
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterOperationsHandler(ctx, mux, conn)
+}
+
+func (s *service) GetOperation(_ context.Context, req *GetOperationRequest) (*Operation, error) {
+	util.LogRequestSafely(s.logger, "get operation", map[string]interface{}{
+		"id": req.GetId(),
+	})
+
+	op, ok := s.manager.get(req.GetId())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "operation %s not found", req.GetId())
+	}
+
+	return op, nil
+}
+
+func (s *service) ListOperations(_ context.Context, req *ListOperationsRequest) (*ListOperationsResponse, error) {
+	util.LogRequestSafely(s.logger, "list operations", map[string]interface{}{
+		"method":      req.GetMethod(),
+		"active_only": req.GetActiveOnly(),
+	})
+
+	return &ListOperationsResponse{Operations: s.manager.list(req.GetMethod(), req.GetActiveOnly())}, nil
+}
+
+func (s *service) CancelOperation(_ context.Context, req *CancelOperationRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "cancel operation", map[string]interface{}{
+		"id": req.GetId(),
+	})
+
+	if err := s.manager.cancel(req.GetId()); err != nil {
+		s.logger.Errorw("failed to cancel operation", zap.Error(err), zap.String("id", req.GetId()))
+		return nil, err
+	}
+
+	s.logger.Infow("cancel operation successfully", "id", req.GetId())
+	return nil, nil
+}
+
+// WatchOperation streams the operation on every change until it finishes.
+func (s *service) WatchOperation(req *WatchOperationRequest, stream Operations_WatchOperationServer) error {
+	util.LogRequestSafely(s.logger, "watch operation", map[string]interface{}{
+		"id": req.GetId(),
+	})
+
+	var last *Operation
+	for {
+		changed := s.manager.watch()
+
+		op, ok := s.manager.get(req.GetId())
+		if !ok {
+			return status.Errorf(codes.NotFound, "operation %s not found", req.GetId())
+		}
+
+		if !proto.Equal(op, last) {
+			if err := stream.Send(op); err != nil {
+				return err
+			}
+			last = op
+		}
+
+		if isFinished(op.GetState()) {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+func init() {
+	app.RegistryGrpcApp(svr)
+}
@@ -0,0 +1,87 @@
+package operation
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryServerInterceptor runs the RPCs carrying the x-unit-agent-async metadata
+// as operations. The RPC returns as soon as the operation is started, with an
+// empty response and the operation id in the x-unit-agent-operation-id header.
+// Other RPCs, and all RPCs before the app is configured, run as usual.
+// Both hold the operation lock of the unit while they run, RPCs which conflict
+// with the RPCs in progress fail with FailedPrecondition.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		m := svr.manager
+		if m == nil || strings.HasPrefix(info.FullMethod, "/operation.Operations/") {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		if len(md.Get(AsyncMetadataKey)) == 0 {
+			release, err := m.lock.acquire(info.FullMethod, "")
+			if err != nil {
+				svr.logger.Errorw("failed to acquire operation lock", zap.Error(err), zap.String("method", info.FullMethod))
+				return nil, err
+			}
+			defer release()
+
+			return handler(ctx, req)
+		}
+
+		var id string
+		if ids := md.Get(IDMetadataKey); len(ids) > 0 {
+			id = ids[0]
+		}
+
+		op, err := m.start(id, info.FullMethod, func(ctx context.Context) (interface{}, error) {
+			return handler(ctx, req)
+		})
+		if err != nil {
+			svr.logger.Errorw("failed to start operation", zap.Error(err), zap.String("method", info.FullMethod))
+			return nil, err
+		}
+
+		if err := grpc.SetHeader(ctx, metadata.Pairs(IDMetadataKey, op.GetId())); err != nil {
+			svr.logger.Errorw("failed to set operation header", zap.Error(err), zap.String("id", op.GetId()))
+			return nil, err
+		}
+
+		svr.logger.Infow("start operation successfully", "id", op.GetId(), "method", info.FullMethod)
+		return nil, nil
+	}
+}
+
+// AsyncContext asks the unit-agent to run the RPCs of ctx as the operation id,
+// an empty id lets the unit-agent generate one.
+func AsyncContext(ctx context.Context, id string) context.Context {
+	ctx = metadata.AppendToOutgoingContext(ctx, AsyncMetadataKey, "true")
+	if id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, IDMetadataKey, id)
+	}
+
+	return ctx
+}
+
+// IDFromHeader returns the operation id of a response header, which is empty
+// when the unit-agent ran the RPC synchronously.
+func IDFromHeader(header metadata.MD) string {
+	if ids := header.Get(IDMetadataKey); len(ids) > 0 {
+		return ids[0]
+	}
+
+	return ""
+}
+
+// ReportProgress records the progress of the operation running ctx, it does
+// nothing for RPCs which run synchronously.
+func ReportProgress(ctx context.Context, progress string) {
+	if svr.manager != nil {
+		svr.manager.reportProgress(ctx, progress)
+	}
+}
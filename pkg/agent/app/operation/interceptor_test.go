@@ -0,0 +1,112 @@
+package operation
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startOperationServer serves the operations and the health service, as a
+// stand-in for the engine services, through the interceptor.
+func startOperationServer(t *testing.T) *grpc.ClientConn {
+	previous := svr.manager
+	svr.manager = newTestManager(t, "")
+	svr.logger = zap.NewNop().Sugar()
+	t.Cleanup(func() { svr.manager = previous })
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnaryInterceptor(UnaryServerInterceptor()))
+	RegisterOperationsServer(server, svr)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestInterceptorRunsAsyncRPCAsOperation(t *testing.T) {
+	conn := startOperationServer(t)
+	ctx := context.Background()
+
+	var header metadata.MD
+	resp, err := healthpb.NewHealthClient(conn).Check(AsyncContext(ctx, "check-1"), &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_UNKNOWN, resp.GetStatus())
+	require.Equal(t, "check-1", IDFromHeader(header))
+
+	stream, err := NewOperationsClient(conn).WatchOperation(ctx, &WatchOperationRequest{Id: "check-1"})
+	require.NoError(t, err)
+	var op *Operation
+	for {
+		next, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		op = next
+	}
+	require.Equal(t, State_SUCCEEDED, op.GetState())
+	require.Equal(t, "/grpc.health.v1.Health/Check", op.GetMethod())
+
+	result, err := op.GetResponse().UnmarshalNew()
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, result.(*healthpb.HealthCheckResponse).GetStatus())
+
+	list, err := NewOperationsClient(conn).ListOperations(ctx, &ListOperationsRequest{Method: "/grpc.health.v1.Health/Check"})
+	require.NoError(t, err)
+	require.Len(t, list.GetOperations(), 1)
+}
+
+func TestInterceptorRunsOtherRPCsSynchronously(t *testing.T) {
+	conn := startOperationServer(t)
+	ctx := context.Background()
+
+	var header metadata.MD
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	require.Empty(t, IDFromHeader(header))
+
+	_, err = NewOperationsClient(conn).GetOperation(AsyncContext(ctx, ""), &GetOperationRequest{Id: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestInterceptorRejectsConflictingRPC(t *testing.T) {
+	startOperationServer(t)
+	release, err := svr.manager.lock.acquire("/mysql.MysqlOperation/Restore", "restore-1")
+	require.NoError(t, err)
+
+	var called bool
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/service.ServiceLifecycle/StartProcess"}
+
+	_, err = UnaryServerInterceptor()(context.Background(), nil, info, handler)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.False(t, called)
+
+	release()
+	_, err = UnaryServerInterceptor()(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	require.True(t, called)
+}
@@ -0,0 +1,176 @@
+package operation
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// lockMode is how an RPC holds the operation lock of the unit served by the
+// unit-agent while it runs.
+type lockMode int
+
+const (
+	// unlocked RPCs only read, they run alongside any other RPC
+	unlocked lockMode = iota
+	// shared RPCs change the unit online, e.g. SetVariable, they run
+	// alongside each other and alongside a backup
+	shared
+	// backup RPCs run alongside shared RPCs, but not alongside another backup
+	backup
+	// exclusive RPCs stop the process or replace its data, e.g. Restore,
+	// they run alone
+	exclusive
+)
+
+func (m lockMode) String() string {
+	switch m {
+	case unlocked:
+		return "unlocked"
+	case shared:
+		return "shared"
+	case backup:
+		return "backup"
+	case exclusive:
+		return "exclusive"
+	default:
+		return fmt.Sprintf("lockMode(%d)", int(m))
+	}
+}
+
+// compatible reports whether RPCs holding the lock in modes a and b may run
+// at the same time.
+func compatible(a, b lockMode) bool {
+	switch {
+	case a == unlocked || b == unlocked:
+		return true
+	case a == exclusive || b == exclusive:
+		return false
+	default:
+		return a != backup || b != backup
+	}
+}
+
+// unlockedServices are the services whose RPCs never take the lock
+var unlockedServices = []string{
+	"/operation.Operations/",
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+// lockModes declares the lock mode of the RPCs by method name, the same
+// for the services of every unit type. The other RPCs are shared.
+var lockModes = map[string]lockMode{
+	"CheckProcessStarted": unlocked,
+	"CheckProcessStopped": unlocked,
+	"ProcessStatus":       unlocked,
+	"ListBackups":         unlocked,
+	"GetBackup":           unlocked,
+	"BackupStatus":        unlocked,
+	"Health":              unlocked,
+	"ClusterHealth":       unlocked,
+	"ClusterNodes":        unlocked,
+	"ReplicaSetStatus":    unlocked,
+	"ListMasters":         unlocked,
+	"ListReplicas":        unlocked,
+	"ListUsers":           unlocked,
+	"ListServers":         unlocked,
+	"ListQueryRules":      unlocked,
+
+	"Backup":         backup,
+	"PhysicalBackup": backup,
+	"LogicalBackup":  backup,
+	"SnapshotShard":  backup,
+	"UploadShard":    backup,
+
+	// ArchiveOplog may keep running until cancelled, the mongodb app
+	// serializes the chunks itself so that backups can run meanwhile
+	"ArchiveOplog": shared,
+
+	"Restore":        exclusive,
+	"RestoreShard":   exclusive,
+	"Clone":          exclusive,
+	"GtidPurge":      exclusive,
+	"StartProcess":   exclusive,
+	"StopProcess":    exclusive,
+	"RestartProcess": exclusive,
+}
+
+// lockModeOf returns the lock mode of the full gRPC method.
+func lockModeOf(fullMethod string) lockMode {
+	for _, prefix := range unlockedServices {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return unlocked
+		}
+	}
+
+	if mode, ok := lockModes[path.Base(fullMethod)]; ok {
+		return mode
+	}
+
+	return shared
+}
+
+// lockHolder is an RPC holding the lock, operation is empty for the RPCs
+// which run synchronously.
+type lockHolder struct {
+	method    string
+	mode      lockMode
+	operation string
+}
+
+func (h lockHolder) String() string {
+	if h.operation != "" {
+		return fmt.Sprintf("operation %s %s (%s)", h.operation, h.method, h.mode)
+	}
+
+	return fmt.Sprintf("%s (%s)", h.method, h.mode)
+}
+
+// unitLock is the operation lock of the unit, held by the RPCs in progress.
+type unitLock struct {
+	mu      sync.Mutex
+	next    int
+	holders map[int]lockHolder
+}
+
+func newUnitLock() *unitLock {
+	return &unitLock{holders: make(map[int]lockHolder)}
+}
+
+// acquire takes the lock for the full gRPC method run as operation, or
+// fails with FailedPrecondition when an RPC in progress conflicts with it.
+// release gives the lock back, it may be called more than once.
+func (l *unitLock) acquire(method, operation string) (release func(), err error) {
+	mode := lockModeOf(method)
+	if mode == unlocked {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, holder := range l.holders {
+		if !compatible(mode, holder.mode) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s (%s) conflicts with %s in progress",
+				method, mode, holder)
+		}
+	}
+
+	key := l.next
+	l.next++
+	l.holders[key] = lockHolder{method: method, mode: mode, operation: operation}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			delete(l.holders, key)
+		})
+	}, nil
+}
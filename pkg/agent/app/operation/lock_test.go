@@ -0,0 +1,91 @@
+package operation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestLockModeOf(t *testing.T) {
+	for method, want := range map[string]lockMode{
+		"/mysql.MysqlOperation/PhysicalBackup":            backup,
+		"/mongodb.MongoDBOperation/ArchiveOplog":          shared,
+		"/mysql.MysqlOperation/Restore":                   exclusive,
+		"/service.ServiceLifecycle/StartProcess":          exclusive,
+		"/mysql.MysqlOperation/SetVariable":               shared,
+		"/config.SyncConfigService/SyncConfig":            shared,
+		"/service.ServiceLifecycle/ProcessStatus":         unlocked,
+		"/operation.Operations/CancelOperation":           unlocked,
+		"/grpc.reflection.v1.ServerReflection/ServerInfo": unlocked,
+	} {
+		require.Equal(t, want, lockModeOf(method), method)
+	}
+}
+
+func TestUnitLockCompatibility(t *testing.T) {
+	for _, tc := range []struct {
+		held, next string
+		allowed    bool
+	}{
+		{"/mysql.MysqlOperation/PhysicalBackup", "/mysql.MysqlOperation/SetVariable", true},
+		{"/mysql.MysqlOperation/SetVariable", "/mysql.MysqlOperation/SetVariables", true},
+		{"/mysql.MysqlOperation/PhysicalBackup", "/mysql.MysqlOperation/LogicalBackup", false},
+		{"/mysql.MysqlOperation/PhysicalBackup", "/mysql.MysqlOperation/Restore", false},
+		{"/mysql.MysqlOperation/Restore", "/mysql.MysqlOperation/Restore", false},
+		{"/mysql.MysqlOperation/Restore", "/service.ServiceLifecycle/StartProcess", false},
+		{"/mysql.MysqlOperation/Restore", "/mysql.MysqlOperation/SetVariable", false},
+		{"/mysql.MysqlOperation/Restore", "/service.ServiceLifecycle/ProcessStatus", true},
+	} {
+		l := newUnitLock()
+		release, err := l.acquire(tc.held, "held")
+		require.NoError(t, err)
+
+		_, err = l.acquire(tc.next, "")
+		if tc.allowed {
+			require.NoError(t, err, "%s during %s", tc.next, tc.held)
+		} else {
+			require.Equal(t, codes.FailedPrecondition, status.Code(err), "%s during %s", tc.next, tc.held)
+		}
+
+		release()
+		release()
+		_, err = l.acquire(tc.next, "")
+		require.NoError(t, err, "%s after %s", tc.next, tc.held)
+	}
+}
+
+func TestManagerRejectsConflictingOperation(t *testing.T) {
+	m := newTestManager(t, "")
+	done := make(chan struct{})
+
+	_, err := m.start("restore-1", "/mysql.MysqlOperation/Restore", func(ctx context.Context) (interface{}, error) {
+		<-done
+		return wrapperspb.String("restored"), nil
+	})
+	require.NoError(t, err)
+
+	_, err = m.start("backup-1", "/mysql.MysqlOperation/PhysicalBackup", func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.Contains(t, err.Error(), "operation restore-1 /mysql.MysqlOperation/Restore (exclusive)")
+	_, ok := m.get("backup-1")
+	require.False(t, ok)
+
+	// a retried request of the operation in progress does not conflict with it
+	_, err = m.start("restore-1", "/mysql.MysqlOperation/Restore", nil)
+	require.NoError(t, err)
+
+	close(done)
+	waitFinished(t, m, "restore-1")
+
+	_, err = m.start("backup-1", "/mysql.MysqlOperation/PhysicalBackup", func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, State_SUCCEEDED, waitFinished(t, m, "backup-1").GetState())
+}
@@ -0,0 +1,382 @@
+package operation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// operationIDRE matches the operation ids, which are also the file names of the store
+var operationIDRE = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,127}$`)
+
+type operationKey struct{}
+
+// manager runs the operations and keeps them, one JSON file per operation,
+// in dir so they survive a unit-agent restart. An empty dir keeps them in memory.
+type manager struct {
+	mu         sync.Mutex
+	dir        string
+	logger     *zap.SugaredLogger
+	operations map[string]*Operation
+	cancels    map[string]context.CancelFunc
+	// lock is the operation lock of the unit, held by the operations and
+	// the synchronous RPCs in progress
+	lock *unitLock
+	// changed is closed and replaced whenever an operation changes
+	changed chan struct{}
+	now     func() time.Time
+}
+
+func newManager(dir string, logger *zap.SugaredLogger) (*manager, error) {
+	m := &manager{
+		dir:        dir,
+		logger:     logger,
+		operations: make(map[string]*Operation),
+		cancels:    make(map[string]context.CancelFunc),
+		lock:       newUnitLock(),
+		changed:    make(chan struct{}),
+		now:        time.Now,
+	}
+
+	if dir == "" {
+		return m, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create operation dir %s failed: %v", dir, err)
+	}
+
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// load reads the stored operations. Operations which were still pending or
+// running were lost with the previous process and are marked failed.
+func (m *manager) load() error {
+	files, err := filepath.Glob(filepath.Join(m.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	now := m.now().Unix()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("read operation %s failed: %v", file, err)
+		}
+
+		op := &Operation{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, op); err != nil {
+			m.logger.Warnw("failed to parse operation, skip it", zap.Error(err), zap.String("file", file))
+			continue
+		}
+
+		if !isFinished(op.GetState()) {
+			op.State = State_FAILED
+			op.Error = "unit-agent restarted while the operation was running"
+			op.Code = int32(codes.Aborted)
+			op.UpdateTime = now
+			op.EndTime = now
+			if err := m.persist(op); err != nil {
+				return err
+			}
+		}
+
+		m.operations[op.GetId()] = op
+	}
+
+	m.prune()
+	return nil
+}
+
+// start runs fn as the operation id, generating the id when it is empty.
+// An operation which already exists is returned as is, so that a retried
+// request does not run twice. The operation holds the lock of the unit for
+// method until fn returns, it is not started when the lock conflicts.
+func (m *manager) start(id, method string, fn func(ctx context.Context) (interface{}, error)) (*Operation, error) {
+	if id == "" {
+		id = newOperationID()
+	} else if !operationIDRE.MatchString(id) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid operation id %q", id)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if op, ok := m.operations[id]; ok {
+		return proto.Clone(op).(*Operation), nil
+	}
+
+	release, err := m.lock.acquire(method, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.now().Unix()
+	op := &Operation{
+		Id:         id,
+		Method:     method,
+		State:      State_PENDING,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := m.persist(op); err != nil {
+		release()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), operationKey{}, id))
+	m.operations[id] = op
+	m.cancels[id] = cancel
+	m.notify()
+
+	go m.run(ctx, id, release, fn)
+
+	return proto.Clone(op).(*Operation), nil
+}
+
+func (m *manager) run(ctx context.Context, id string, release func(), fn func(ctx context.Context) (interface{}, error)) {
+	m.update(id, func(op *Operation) {
+		op.State = State_RUNNING
+	})
+
+	resp, err := func() (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("operation panicked: %v", r)
+			}
+		}()
+
+		return fn(ctx)
+	}()
+	// the lock is given back before the operation turns finished, so that
+	// a client waiting for it does not conflict with it on its next RPC
+	release()
+
+	var response *anypb.Any
+	if msg, ok := resp.(proto.Message); ok && err == nil && msg.ProtoReflect().IsValid() {
+		if response, err = anypb.New(msg); err != nil {
+			err = fmt.Errorf("marshal response failed: %v", err)
+		}
+	}
+
+	m.update(id, func(op *Operation) {
+		switch {
+		case err != nil && ctx.Err() == context.Canceled:
+			op.State = State_CANCELLED
+			op.Error = err.Error()
+			op.Code = int32(codes.Canceled)
+		case err != nil:
+			op.State = State_FAILED
+			op.Error = err.Error()
+			op.Code = int32(status.Code(err))
+		default:
+			op.State = State_SUCCEEDED
+			op.Response = response
+		}
+	})
+
+	m.mu.Lock()
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+		delete(m.cancels, id)
+	}
+	m.prune()
+	m.mu.Unlock()
+
+	if err != nil {
+		m.logger.Errorw("operation failed", zap.Error(err), zap.String("id", id))
+		return
+	}
+	m.logger.Infow("operation succeeded", "id", id)
+}
+
+// update changes and stores the operation, logging rather than failing when
+// it can not be stored.
+func (m *manager) update(id string, fn func(op *Operation)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	op, ok := m.operations[id]
+	if !ok {
+		return
+	}
+
+	fn(op)
+	op.UpdateTime = m.now().Unix()
+	if isFinished(op.GetState()) && op.GetEndTime() == 0 {
+		op.EndTime = op.GetUpdateTime()
+	}
+
+	if err := m.persist(op); err != nil {
+		m.logger.Errorw("failed to store operation", zap.Error(err), zap.String("id", id))
+	}
+	m.notify()
+}
+
+func (m *manager) reportProgress(ctx context.Context, progress string) {
+	if id, ok := ctx.Value(operationKey{}).(string); ok {
+		m.update(id, func(op *Operation) {
+			op.Progress = progress
+		})
+	}
+}
+
+func (m *manager) get(id string) (*Operation, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	op, ok := m.operations[id]
+	if !ok {
+		return nil, false
+	}
+
+	return proto.Clone(op).(*Operation), true
+}
+
+func (m *manager) list(method string, activeOnly bool) []*Operation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	operations := make([]*Operation, 0, len(m.operations))
+	for _, op := range m.operations {
+		if method != "" && op.GetMethod() != method {
+			continue
+		}
+		if activeOnly && isFinished(op.GetState()) {
+			continue
+		}
+		operations = append(operations, proto.Clone(op).(*Operation))
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].GetCreateTime() != operations[j].GetCreateTime() {
+			return operations[i].GetCreateTime() < operations[j].GetCreateTime()
+		}
+		return operations[i].GetId() < operations[j].GetId()
+	})
+
+	return operations
+}
+
+// cancel cancels the context of a pending or running operation, which turns
+// cancelled once its handler returns. Finished operations are left as they are.
+func (m *manager) cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.operations[id]; !ok {
+		return status.Errorf(codes.NotFound, "operation %s not found", id)
+	}
+
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+	}
+
+	return nil
+}
+
+// watch returns a channel which is closed on the next change of any operation.
+func (m *manager) watch() <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.changed
+}
+
+func (m *manager) notify() {
+	close(m.changed)
+	m.changed = make(chan struct{})
+}
+
+// prune drops the operations which finished longer than operationRetention ago.
+func (m *manager) prune() {
+	deadline := m.now().Add(-operationRetention).Unix()
+	for id, op := range m.operations {
+		if !isFinished(op.GetState()) || op.GetEndTime() >= deadline {
+			continue
+		}
+
+		if m.dir != "" {
+			if err := os.Remove(m.path(id)); err != nil && !os.IsNotExist(err) {
+				m.logger.Warnw("failed to remove operation", zap.Error(err), zap.String("id", id))
+				continue
+			}
+		}
+		delete(m.operations, id)
+	}
+}
+
+// persist writes the operation to a temporary file renamed over the
+// previous one, so a crash never leaves a truncated operation behind.
+func (m *manager) persist(op *Operation) error {
+	if m.dir == "" {
+		return nil
+	}
+
+	data, err := protojson.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("marshal operation %s failed: %v", op.GetId(), err)
+	}
+
+	tmp, err := os.CreateTemp(m.dir, "."+op.GetId()+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("store operation %s failed: %v", op.GetId(), err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("store operation %s failed: %v", op.GetId(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("store operation %s failed: %v", op.GetId(), err)
+	}
+
+	if err := os.Rename(tmp.Name(), m.path(op.GetId())); err != nil {
+		return fmt.Errorf("store operation %s failed: %v", op.GetId(), err)
+	}
+
+	return nil
+}
+
+func (m *manager) path(id string) string {
+	return filepath.Join(m.dir, id+".json")
+}
+
+func isFinished(state State) bool {
+	switch state {
+	case State_SUCCEEDED, State_FAILED, State_CANCELLED:
+		return true
+	default:
+		return false
+	}
+}
+
+func newOperationID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+
+	return hex.EncodeToString(b)
+}
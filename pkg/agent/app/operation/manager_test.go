@@ -0,0 +1,150 @@
+package operation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newTestManager(t *testing.T, dir string) *manager {
+	m, err := newManager(dir, zap.NewNop().Sugar())
+	require.NoError(t, err)
+	return m
+}
+
+// waitFinished waits until the operation is finished and returns it.
+func waitFinished(t *testing.T, m *manager, id string) *Operation {
+	var op *Operation
+	require.Eventually(t, func() bool {
+		var ok bool
+		op, ok = m.get(id)
+		return ok && isFinished(op.GetState())
+	}, 5*time.Second, 10*time.Millisecond)
+	return op
+}
+
+func TestManagerRunsAndPersistsOperation(t *testing.T) {
+	dir := t.TempDir()
+	m := newTestManager(t, dir)
+
+	op, err := m.start("backup-1", "/mysql.MysqlOperation/PhysicalBackup", func(ctx context.Context) (interface{}, error) {
+		m.reportProgress(ctx, "copying")
+		return wrapperspb.String("done"), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, State_PENDING, op.GetState())
+
+	op = waitFinished(t, m, "backup-1")
+	require.Equal(t, State_SUCCEEDED, op.GetState())
+	require.Equal(t, "copying", op.GetProgress())
+	require.NotZero(t, op.GetEndTime())
+
+	resp, err := op.GetResponse().UnmarshalNew()
+	require.NoError(t, err)
+	require.Equal(t, "done", resp.(*wrapperspb.StringValue).GetValue())
+
+	reloaded := newTestManager(t, dir)
+	stored, ok := reloaded.get("backup-1")
+	require.True(t, ok)
+	require.Equal(t, State_SUCCEEDED, stored.GetState())
+	require.Equal(t, "/mysql.MysqlOperation/PhysicalBackup", stored.GetMethod())
+}
+
+func TestManagerStartIsIdempotent(t *testing.T) {
+	m := newTestManager(t, "")
+
+	release := make(chan struct{})
+	runs := 0
+	fn := func(ctx context.Context) (interface{}, error) {
+		runs++
+		<-release
+		return nil, nil
+	}
+
+	_, err := m.start("restore-1", "/redis.RedisOperation/Restore", fn)
+	require.NoError(t, err)
+	_, err = m.start("restore-1", "/redis.RedisOperation/Restore", fn)
+	require.NoError(t, err)
+
+	close(release)
+	require.Equal(t, State_SUCCEEDED, waitFinished(t, m, "restore-1").GetState())
+	require.Equal(t, 1, runs)
+	require.Len(t, m.list("", false), 1)
+
+	_, err = m.start("../restore", "/redis.RedisOperation/Restore", fn)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestManagerCancelsOperation(t *testing.T) {
+	m := newTestManager(t, "")
+
+	_, err := m.start("clone-1", "/mysql.MysqlOperation/Clone", func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	require.NoError(t, err)
+	require.Len(t, m.list("/mysql.MysqlOperation/Clone", true), 1)
+
+	require.NoError(t, m.cancel("clone-1"))
+	op := waitFinished(t, m, "clone-1")
+	require.Equal(t, State_CANCELLED, op.GetState())
+	require.Equal(t, int32(codes.Canceled), op.GetCode())
+	require.Empty(t, m.list("", true))
+
+	// cancelling a finished operation changes nothing
+	require.NoError(t, m.cancel("clone-1"))
+	require.Equal(t, codes.NotFound, status.Code(m.cancel("missing")))
+}
+
+func TestManagerFailsOperations(t *testing.T) {
+	m := newTestManager(t, "")
+
+	_, err := m.start("fail", "/m", func(ctx context.Context) (interface{}, error) {
+		return nil, status.Error(codes.Unavailable, "xtrabackup exited 1")
+	})
+	require.NoError(t, err)
+	_, err = m.start("panic", "/m", func(ctx context.Context) (interface{}, error) {
+		panic("boom")
+	})
+	require.NoError(t, err)
+
+	op := waitFinished(t, m, "fail")
+	require.Equal(t, State_FAILED, op.GetState())
+	require.Equal(t, "rpc error: code = Unavailable desc = xtrabackup exited 1", op.GetError())
+	require.Equal(t, int32(codes.Unavailable), op.GetCode())
+
+	op = waitFinished(t, m, "panic")
+	require.Equal(t, State_FAILED, op.GetState())
+	require.Equal(t, "operation panicked: boom", op.GetError())
+	require.Equal(t, int32(codes.Unknown), op.GetCode())
+}
+
+func TestManagerLoadFailsInterruptedAndPrunesExpired(t *testing.T) {
+	dir := t.TempDir()
+	m := newTestManager(t, dir)
+
+	now := time.Now()
+	require.NoError(t, m.persist(&Operation{Id: "running", State: State_RUNNING, CreateTime: now.Unix()}))
+	require.NoError(t, m.persist(&Operation{Id: "old", State: State_SUCCEEDED, EndTime: now.Add(-8 * 24 * time.Hour).Unix()}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644))
+
+	reloaded := newTestManager(t, dir)
+
+	op, ok := reloaded.get("running")
+	require.True(t, ok)
+	require.Equal(t, State_FAILED, op.GetState())
+	require.Contains(t, op.GetError(), "restarted")
+
+	_, ok = reloaded.get("old")
+	require.False(t, ok)
+	require.NoFileExists(t, filepath.Join(dir, "old.json"))
+	require.FileExists(t, filepath.Join(dir, "broken.json"))
+}
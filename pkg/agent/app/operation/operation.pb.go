@@ -0,0 +1,551 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.20.0
+// source: pkg/agent/app/operation/pb/operation.proto
+
+package operation
+
+import (
+	common "github.com/upmio/unit-operator/pkg/agent/app/common"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type State int32
+
+const (
+	State_STATE_UNSPECIFIED State = 0
+	State_PENDING           State = 1
+	State_RUNNING           State = 2
+	State_SUCCEEDED         State = 3
+	State_FAILED            State = 4
+	State_CANCELLED         State = 5
+)
+
+// Enum value maps for State.
+var (
+	State_name = map[int32]string{
+		0: "STATE_UNSPECIFIED",
+		1: "PENDING",
+		2: "RUNNING",
+		3: "SUCCEEDED",
+		4: "FAILED",
+		5: "CANCELLED",
+	}
+	State_value = map[string]int32{
+		"STATE_UNSPECIFIED": 0,
+		"PENDING":           1,
+		"RUNNING":           2,
+		"SUCCEEDED":         3,
+		"FAILED":            4,
+		"CANCELLED":         5,
+	}
+)
+
+func (x State) Enum() *State {
+	p := new(State)
+	*p = x
+	return p
+}
+
+func (x State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (State) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_agent_app_operation_pb_operation_proto_enumTypes[0].Descriptor()
+}
+
+func (State) Type() protoreflect.EnumType {
+	return &file_pkg_agent_app_operation_pb_operation_proto_enumTypes[0]
+}
+
+func (x State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use State.Descriptor instead.
+func (State) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_agent_app_operation_pb_operation_proto_rawDescGZIP(), []int{0}
+}
+
+// Operation is an RPC started asynchronously, see the x-unit-agent-async metadata
+type Operation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// full gRPC method, e.g. /mysql.MysqlOperation/PhysicalBackup
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	State  State  `protobuf:"varint,3,opt,name=state,proto3,enum=operation.State" json:"state,omitempty"`
+	Error  string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// last progress reported by the handler
+	Progress string `protobuf:"bytes,5,opt,name=progress,proto3" json:"progress,omitempty"`
+	// unix seconds
+	CreateTime int64 `protobuf:"varint,6,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	UpdateTime int64 `protobuf:"varint,7,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	EndTime    int64 `protobuf:"varint,8,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// response of a succeeded operation
+	Response *anypb.Any `protobuf:"bytes,9,opt,name=response,proto3" json:"response,omitempty"`
+	// gRPC code of the error of a failed or cancelled operation
+	Code          int32 `protobuf:"varint,10,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_operation_pb_operation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Operation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Operation) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Operation) GetState() State {
+	if x != nil {
+		return x.State
+	}
+	return State_STATE_UNSPECIFIED
+}
+
+func (x *Operation) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Operation) GetProgress() string {
+	if x != nil {
+		return x.Progress
+	}
+	return ""
+}
+
+func (x *Operation) GetCreateTime() int64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *Operation) GetUpdateTime() int64 {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return 0
+}
+
+func (x *Operation) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *Operation) GetResponse() *anypb.Any {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *Operation) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+type GetOperationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOperationRequest) Reset() {
+	*x = GetOperationRequest{}
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOperationRequest) ProtoMessage() {}
+
+func (x *GetOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOperationRequest.ProtoReflect.Descriptor instead.
+func (*GetOperationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_operation_pb_operation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetOperationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListOperationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// only operations of this full gRPC method when set
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	// only pending and running operations
+	ActiveOnly    bool `protobuf:"varint,2,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOperationsRequest) Reset() {
+	*x = ListOperationsRequest{}
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOperationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOperationsRequest) ProtoMessage() {}
+
+func (x *ListOperationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOperationsRequest.ProtoReflect.Descriptor instead.
+func (*ListOperationsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_operation_pb_operation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListOperationsRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *ListOperationsRequest) GetActiveOnly() bool {
+	if x != nil {
+		return x.ActiveOnly
+	}
+	return false
+}
+
+type ListOperationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Operations    []*Operation           `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOperationsResponse) Reset() {
+	*x = ListOperationsResponse{}
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOperationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOperationsResponse) ProtoMessage() {}
+
+func (x *ListOperationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOperationsResponse.ProtoReflect.Descriptor instead.
+func (*ListOperationsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_operation_pb_operation_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListOperationsResponse) GetOperations() []*Operation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type CancelOperationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOperationRequest) Reset() {
+	*x = CancelOperationRequest{}
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOperationRequest) ProtoMessage() {}
+
+func (x *CancelOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOperationRequest.ProtoReflect.Descriptor instead.
+func (*CancelOperationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_operation_pb_operation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CancelOperationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type WatchOperationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchOperationRequest) Reset() {
+	*x = WatchOperationRequest{}
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchOperationRequest) ProtoMessage() {}
+
+func (x *WatchOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_operation_pb_operation_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchOperationRequest.ProtoReflect.Descriptor instead.
+func (*WatchOperationRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_operation_pb_operation_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WatchOperationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_pkg_agent_app_operation_pb_operation_proto protoreflect.FileDescriptor
+
+const file_pkg_agent_app_operation_pb_operation_proto_rawDesc = "" +
+	"\n" +
+	"*pkg/agent/app/operation/pb/operation.proto\x12\toperation\x1a$pkg/agent/app/common/pb/common.proto\x1a\x19google/protobuf/any.proto\"\xb0\x02\n" +
+	"\tOperation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12&\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x10.operation.StateR\x05state\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1a\n" +
+	"\bprogress\x18\x05 \x01(\tR\bprogress\x12\x1f\n" +
+	"\vcreate_time\x18\x06 \x01(\x03R\n" +
+	"createTime\x12\x1f\n" +
+	"\vupdate_time\x18\a \x01(\x03R\n" +
+	"updateTime\x12\x19\n" +
+	"\bend_time\x18\b \x01(\x03R\aendTime\x120\n" +
+	"\bresponse\x18\t \x01(\v2\x14.google.protobuf.AnyR\bresponse\x12\x12\n" +
+	"\x04code\x18\n" +
+	" \x01(\x05R\x04code\"%\n" +
+	"\x13GetOperationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"P\n" +
+	"\x15ListOperationsRequest\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x1f\n" +
+	"\vactive_only\x18\x02 \x01(\bR\n" +
+	"activeOnly\"N\n" +
+	"\x16ListOperationsResponse\x124\n" +
+	"\n" +
+	"operations\x18\x01 \x03(\v2\x14.operation.OperationR\n" +
+	"operations\"(\n" +
+	"\x16CancelOperationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"'\n" +
+	"\x15WatchOperationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id*b\n" +
+	"\x05State\x12\x15\n" +
+	"\x11STATE_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aPENDING\x10\x01\x12\v\n" +
+	"\aRUNNING\x10\x02\x12\r\n" +
+	"\tSUCCEEDED\x10\x03\x12\n" +
+	"\n" +
+	"\x06FAILED\x10\x04\x12\r\n" +
+	"\tCANCELLED\x10\x052\xba\x02\n" +
+	"\n" +
+	"Operations\x12D\n" +
+	"\fGetOperation\x12\x1e.operation.GetOperationRequest\x1a\x14.operation.Operation\x12U\n" +
+	"\x0eListOperations\x12 .operation.ListOperationsRequest\x1a!.operation.ListOperationsResponse\x12C\n" +
+	"\x0fCancelOperation\x12!.operation.CancelOperationRequest\x1a\r.common.Empty\x12J\n" +
+	"\x0eWatchOperation\x12 .operation.WatchOperationRequest\x1a\x14.operation.Operation0\x01B8Z6github.com/upmio/unit-operator/pkg/agent/app/operationb\x06proto3"
+
+var (
+	file_pkg_agent_app_operation_pb_operation_proto_rawDescOnce sync.Once
+	file_pkg_agent_app_operation_pb_operation_proto_rawDescData []byte
+)
+
+func file_pkg_agent_app_operation_pb_operation_proto_rawDescGZIP() []byte {
+	file_pkg_agent_app_operation_pb_operation_proto_rawDescOnce.Do(func() {
+		file_pkg_agent_app_operation_pb_operation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_agent_app_operation_pb_operation_proto_rawDesc), len(file_pkg_agent_app_operation_pb_operation_proto_rawDesc)))
+	})
+	return file_pkg_agent_app_operation_pb_operation_proto_rawDescData
+}
+
+var file_pkg_agent_app_operation_pb_operation_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_agent_app_operation_pb_operation_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_pkg_agent_app_operation_pb_operation_proto_goTypes = []any{
+	(State)(0),                     // 0: operation.State
+	(*Operation)(nil),              // 1: operation.Operation
+	(*GetOperationRequest)(nil),    // 2: operation.GetOperationRequest
+	(*ListOperationsRequest)(nil),  // 3: operation.ListOperationsRequest
+	(*ListOperationsResponse)(nil), // 4: operation.ListOperationsResponse
+	(*CancelOperationRequest)(nil), // 5: operation.CancelOperationRequest
+	(*WatchOperationRequest)(nil),  // 6: operation.WatchOperationRequest
+	(*anypb.Any)(nil),              // 7: google.protobuf.Any
+	(*common.Empty)(nil),           // 8: common.Empty
+}
+var file_pkg_agent_app_operation_pb_operation_proto_depIdxs = []int32{
+	0, // 0: operation.Operation.state:type_name -> operation.State
+	7, // 1: operation.Operation.response:type_name -> google.protobuf.Any
+	1, // 2: operation.ListOperationsResponse.operations:type_name -> operation.Operation
+	2, // 3: operation.Operations.GetOperation:input_type -> operation.GetOperationRequest
+	3, // 4: operation.Operations.ListOperations:input_type -> operation.ListOperationsRequest
+	5, // 5: operation.Operations.CancelOperation:input_type -> operation.CancelOperationRequest
+	6, // 6: operation.Operations.WatchOperation:input_type -> operation.WatchOperationRequest
+	1, // 7: operation.Operations.GetOperation:output_type -> operation.Operation
+	4, // 8: operation.Operations.ListOperations:output_type -> operation.ListOperationsResponse
+	8, // 9: operation.Operations.CancelOperation:output_type -> common.Empty
+	1, // 10: operation.Operations.WatchOperation:output_type -> operation.Operation
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_pkg_agent_app_operation_pb_operation_proto_init() }
+func file_pkg_agent_app_operation_pb_operation_proto_init() {
+	if File_pkg_agent_app_operation_pb_operation_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_operation_pb_operation_proto_rawDesc), len(file_pkg_agent_app_operation_pb_operation_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_agent_app_operation_pb_operation_proto_goTypes,
+		DependencyIndexes: file_pkg_agent_app_operation_pb_operation_proto_depIdxs,
+		EnumInfos:         file_pkg_agent_app_operation_pb_operation_proto_enumTypes,
+		MessageInfos:      file_pkg_agent_app_operation_pb_operation_proto_msgTypes,
+	}.Build()
+	File_pkg_agent_app_operation_pb_operation_proto = out.File
+	file_pkg_agent_app_operation_pb_operation_proto_goTypes = nil
+	file_pkg_agent_app_operation_pb_operation_proto_depIdxs = nil
+}
@@ -3,6 +3,8 @@ package postgresql
 import (
 	"archive/tar"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
@@ -10,11 +12,15 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"io"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/upmio/unit-operator/pkg/agent/app"
 	"github.com/upmio/unit-operator/pkg/agent/app/common"
 	"github.com/upmio/unit-operator/pkg/agent/app/slm"
@@ -27,6 +33,11 @@ import (
 var (
 	// service instance
 	svr = &service{}
+
+	privilegeRE = regexp.MustCompile(`^[A-Z]+( [A-Z]+)*$`)
+	encodingRE  = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+	settingNameRE = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
 )
 
 type service struct {
@@ -63,7 +74,11 @@ func (s *service) Registry(server *grpc.Server) {
 	RegisterPostgresqlOperationServer(server, svr)
 }
 
-func (s *service) PhysicalBackup(ctx context.Context, req *PhysicalBackupRequest) (*common.Empty, error) {
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterPostgresqlOperationHandler(ctx, mux, conn)
+}
+
+func (s *service) PhysicalBackup(ctx context.Context, req *PhysicalBackupRequest) (*common.BackupResponse, error) {
 	util.LogRequestSafely(s.logger, "postgresql physical backup", map[string]interface{}{
 		"username":    req.GetUsername(),
 		"backup_file": req.GetBackupFile(),
@@ -87,6 +102,8 @@ func (s *service) PhysicalBackup(ctx context.Context, req *PhysicalBackupRequest
 		return nil, err
 	}
 
+	start := time.Now()
+
 	dir, err := os.MkdirTemp("/tmp", "pgbackup-*")
 	if err != nil {
 		s.logger.Errorw("failed to create temporary directory", zap.Error(err))
@@ -123,10 +140,12 @@ func (s *service) PhysicalBackup(ctx context.Context, req *PhysicalBackupRequest
 
 	errGrp := new(errgroup.Group)
 
+	var size int64
 	if err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if info.IsDir() {
 			return nil
 		}
+		size += info.Size()
 
 		key := filepath.Join(req.GetBackupFile(), strings.TrimPrefix(path, dir))
 
@@ -150,11 +169,19 @@ func (s *service) PhysicalBackup(ctx context.Context, req *PhysicalBackupRequest
 		return nil, err
 	}
 
-	s.logger.Info("physical backup postgresql successfully")
-	return nil, nil
+	resp := &common.BackupResponse{
+		Bucket:     req.GetObjectStorage().GetBucket(),
+		Object:     req.GetBackupFile(),
+		SizeBytes:  size,
+		DurationMs: time.Since(start).Milliseconds(),
+		Position:   readBackupManifestEndLSN(filepath.Join(dir, "backup_manifest")),
+	}
+
+	s.logger.Infow("physical backup postgresql successfully", "size_bytes", resp.GetSizeBytes(), "position", resp.GetPosition())
+	return resp, nil
 }
 
-func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest) (*common.Empty, error) {
+func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest) (*common.BackupResponse, error) {
 	util.LogRequestSafely(s.logger, "postgresql logical backup", map[string]interface{}{
 		"username":            req.GetUsername(),
 		"backup_file":         req.GetBackupFile(),
@@ -175,6 +202,8 @@ func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest)
 		return nil, err
 	}
 
+	start := time.Now()
+
 	password, err := util.DecryptPlainTextPassword(req.GetUsername())
 	if err != nil {
 		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
@@ -204,16 +233,22 @@ func (s *service) LogicalBackup(ctx context.Context, req *LogicalBackupRequest)
 		return nil, err
 	}
 
-	if err := executor.ExecuteCommandStreamToS3(ctx, cmd, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "backup"); err != nil {
+	size, err := executor.ExecuteCommandStreamToS3(ctx, cmd, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "backup")
+	if err != nil {
 		s.logger.Errorw("failed to execute backup", zap.Error(err))
 		return nil, err
 	}
 
-	s.logger.Info("logical backup postgresql successfully")
-	return nil, nil
+	s.logger.Infow("logical backup postgresql successfully", "size_bytes", size)
+	return &common.BackupResponse{
+		Bucket:     req.GetObjectStorage().GetBucket(),
+		Object:     req.GetBackupFile(),
+		SizeBytes:  size,
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
 }
 
-func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.Empty, error) {
+func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.SetVariableResponse, error) {
 	util.LogRequestSafely(s.logger, "postgresql set variable", map[string]interface{}{
 		"username": req.GetUsername(),
 		"key":      req.GetKey(),
@@ -226,25 +261,37 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 		return nil, err
 	}
 
-	password, err := util.DecryptPlainTextPassword(req.GetUsername())
+	key, err := normalizeSettingName(req.GetKey())
 	if err != nil {
-		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
+		s.logger.Errorw("invalid set variable request", zap.Error(err))
 		return nil, err
 	}
 
-	url := fmt.Sprintf("postgres://%s:%s@localhost:5432/postgres?connect_timeout=5", req.GetUsername(), password)
-
-	conn, err := pgx.Connect(ctx, url)
+	conn, err := s.newPgConn(ctx, req.GetUsername(), "postgres")
 	if err != nil {
-		s.logger.Errorw("failed to connect to postgresql", zap.Error(err))
 		return nil, err
 	}
 	defer func() { _ = conn.Close(ctx) }()
 
-	execSQL := fmt.Sprintf("ALTER SYSTEM SET %s = %s", req.GetKey(), req.GetValue())
+	// Check the setting against the server catalog, its context tells when a change takes effect
+	var previous, settingContext string
+	if err := conn.QueryRow(ctx, "SELECT current_setting(name), context FROM pg_settings WHERE name = $1", key).Scan(&previous, &settingContext); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = fmt.Errorf("unknown setting %q", key)
+		}
+		s.logger.Errorw("failed to check setting", zap.Error(err), zap.String("key", key))
+		return nil, err
+	}
+	if settingContext == "internal" {
+		err := fmt.Errorf("setting %q can not be changed", key)
+		s.logger.Errorw("invalid set variable request", zap.Error(err))
+		return nil, err
+	}
 
+	// ALTER SYSTEM writes postgresql.auto.conf, which is applied after the rendered config file
+	execSQL := fmt.Sprintf("ALTER SYSTEM SET %s = %s", key, quoteLiteral(req.GetValue()))
 	if _, err := conn.Exec(ctx, execSQL); err != nil {
-		s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("key", req.GetKey()), zap.String("value", req.GetValue()))
+		s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("key", key), zap.String("value", req.GetValue()))
 		return nil, err
 	}
 
@@ -253,12 +300,89 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 		return nil, err
 	}
 
-	s.logger.Info("set variable successfully")
+	// The reload is applied by every backend asynchronously, the value written is reported
+	resp := common.NewSetVariableResponse(previous, req.GetValue())
+	resp.RestartRequired = settingContext == "postmaster"
+	resp.Persisted = true
 
-	return nil, nil
+	s.logger.Infow("set variable successfully", "changed", resp.GetChanged(), "restart_required", resp.GetRestartRequired())
+
+	return resp, nil
 }
 
-func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Empty, error) {
+func (s *service) SetVariables(ctx context.Context, req *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	util.LogRequestSafely(s.logger, "postgresql set variables", map[string]interface{}{
+		"username":  req.GetUsername(),
+		"variables": req.GetVariables(),
+		"dry_run":   req.GetDryRun(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	variables, err := normalizeSettings(req.GetVariables())
+	if err != nil {
+		s.logger.Errorw("invalid set variables request", zap.Error(err))
+		return nil, err
+	}
+
+	conn, err := s.newPgConn(ctx, req.GetUsername(), "postgres")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	// Read the current values, unknown or internal settings fail the whole batch
+	resp := &common.SetVariablesResponse{DryRun: req.GetDryRun()}
+	keys := common.SortedVariableKeys(variables)
+	for _, key := range keys {
+		var current, settingContext string
+		err := conn.QueryRow(ctx, "SELECT current_setting(name), context FROM pg_settings WHERE name = $1", key).Scan(&current, &settingContext)
+		result := common.NewVariableResult(key, current, variables[key])
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			result.Error = "unknown setting"
+		case err != nil:
+			s.logger.Errorw("failed to check setting", zap.Error(err), zap.String("key", key))
+			return nil, err
+		case settingContext == "internal":
+			result.Error = "setting can not be changed"
+		}
+		result.RestartRequired = settingContext == "postmaster" && result.GetChanged()
+		resp.Results = append(resp.Results, result)
+	}
+
+	if req.GetDryRun() || resp.Failed() {
+		return resp, nil
+	}
+
+	// ALTER SYSTEM can not run inside a transaction, the configuration is only
+	// reloaded once every setting of the batch has been written
+	resp.Persisted = true
+	for _, result := range resp.Results {
+		execSQL := fmt.Sprintf("ALTER SYSTEM SET %s = %s", result.GetKey(), quoteLiteral(variables[result.GetKey()]))
+		if _, err := conn.Exec(ctx, execSQL); err != nil {
+			s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("key", result.GetKey()))
+			result.Error = err.Error()
+			return resp, nil
+		}
+		result.Applied = true
+	}
+
+	if _, err := conn.Exec(ctx, "SELECT pg_reload_conf()"); err != nil {
+		s.logger.Errorw("failed to reload configuration", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("set variables successfully")
+
+	return resp, nil
+}
+
+func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.RestoreResponse, error) {
 	util.LogRequestSafely(s.logger, "postgresql restore", map[string]interface{}{
 		"backup_file": req.GetBackupFile(),
 		"bucket":      req.GetObjectStorage().GetBucket(),
@@ -281,6 +405,8 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 		return nil, err
 	}
 
+	start := time.Now()
+
 	// Clear data directory
 	if err := s.removeContents(s.dataDir); err != nil {
 		s.logger.Errorw("failed to remove contents", zap.Error(err), zap.String("dir", s.dataDir))
@@ -288,12 +414,14 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 	}
 
 	// Extract backup files from S3
-	if err = s.extractFileFromS3(ctx, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "base.tar", s.dataDir); err != nil {
+	baseSize, err := s.extractFileFromS3(ctx, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "base.tar", s.dataDir)
+	if err != nil {
 		s.logger.Errorw("failed to extract base.tar", zap.Error(err))
 		return nil, err
 	}
 
-	if err = s.extractFileFromS3(ctx, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "pg_wal.tar", filepath.Join(s.dataDir, "pg_wal")); err != nil {
+	walSize, err := s.extractFileFromS3(ctx, factory, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), "pg_wal.tar", filepath.Join(s.dataDir, "pg_wal"))
+	if err != nil {
 		s.logger.Errorw("failed to extract pg_wal.tar", zap.Error(err))
 		return nil, err
 	}
@@ -315,9 +443,413 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 	}
 
 	s.logger.Info("restore postgresql successfully")
+	return &common.RestoreResponse{
+		Bucket:     req.GetObjectStorage().GetBucket(),
+		Object:     req.GetBackupFile(),
+		SizeBytes:  baseSize + walSize,
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
+}
+
+func (s *service) CreateUser(ctx context.Context, req *CreateUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "postgresql create user", map[string]interface{}{
+		"username":   req.GetUsername(),
+		"user":       req.GetUser(),
+		"privileges": req.GetPrivileges(),
+		"revokes":    req.GetRevokes(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" || req.GetPassword() == "" {
+		err := fmt.Errorf("user and password are required")
+		s.logger.Errorw("invalid create user request", zap.Error(err))
+		return nil, err
+	}
+
+	conn, err := s.newPgConn(ctx, req.GetUsername(), "postgres")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	// Roles are replicated from the primary, a standby can not be written
+	inRecovery, err := isInRecovery(ctx, conn)
+	if err != nil {
+		s.logger.Errorw("failed to check recovery status", zap.Error(err))
+		return nil, err
+	}
+	if inRecovery {
+		s.logger.Infow("instance is in recovery, skip create user", "user", req.GetUser())
+		return nil, nil
+	}
+
+	var exists bool
+	if err := conn.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = $1)", req.GetUser()).Scan(&exists); err != nil {
+		s.logger.Errorw("failed to query role", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	verb := "CREATE"
+	if exists {
+		verb = "ALTER"
+	}
+	execSQL := fmt.Sprintf("%s ROLE %s WITH LOGIN PASSWORD %s", verb, pgx.Identifier{req.GetUser()}.Sanitize(), quoteLiteral(req.GetPassword()))
+	if _, err := conn.Exec(ctx, execSQL); err != nil {
+		s.logger.Errorw("failed to create user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	// Privileges removed from the user are revoked before the grants, which may overlap them
+	for _, privilege := range req.GetRevokes() {
+		if err := s.revokePrivilege(ctx, conn, req.GetUsername(), req.GetUser(), privilege); err != nil {
+			s.logger.Errorw("failed to revoke privileges", zap.Error(err), zap.String("user", req.GetUser()), zap.String("database", privilege.GetDatabase()))
+			return nil, err
+		}
+	}
+
+	for _, privilege := range req.GetPrivileges() {
+		if err := s.grantPrivilege(ctx, conn, req.GetUsername(), req.GetUser(), privilege); err != nil {
+			s.logger.Errorw("failed to grant privileges", zap.Error(err), zap.String("user", req.GetUser()), zap.String("database", privilege.GetDatabase()))
+			return nil, err
+		}
+	}
+
+	s.logger.Info("create user successfully")
+	return nil, nil
+}
+
+func (s *service) DropUser(ctx context.Context, req *DropUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "postgresql drop user", map[string]interface{}{
+		"username": req.GetUsername(),
+		"user":     req.GetUser(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" {
+		err := fmt.Errorf("user is required")
+		s.logger.Errorw("invalid drop user request", zap.Error(err))
+		return nil, err
+	}
+
+	conn, err := s.newPgConn(ctx, req.GetUsername(), "postgres")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	inRecovery, err := isInRecovery(ctx, conn)
+	if err != nil {
+		s.logger.Errorw("failed to check recovery status", zap.Error(err))
+		return nil, err
+	}
+	if inRecovery {
+		s.logger.Infow("instance is in recovery, skip drop user", "user", req.GetUser())
+		return nil, nil
+	}
+
+	if _, err := conn.Exec(ctx, "DROP ROLE IF EXISTS "+pgx.Identifier{req.GetUser()}.Sanitize()); err != nil {
+		s.logger.Errorw("failed to drop user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	s.logger.Info("drop user successfully")
+	return nil, nil
+}
+
+func (s *service) CreateDatabase(ctx context.Context, req *CreateDatabaseRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "postgresql create database", map[string]interface{}{
+		"username": req.GetUsername(),
+		"database": req.GetDatabase(),
+		"owner":    req.GetOwner(),
+		"encoding": req.GetEncoding(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	execSQL, err := buildCreateDatabaseSQL(req.GetDatabase(), req.GetOwner(), req.GetEncoding())
+	if err != nil {
+		s.logger.Errorw("invalid create database request", zap.Error(err))
+		return nil, err
+	}
+
+	conn, err := s.newPgConn(ctx, req.GetUsername(), "postgres")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	inRecovery, err := isInRecovery(ctx, conn)
+	if err != nil {
+		s.logger.Errorw("failed to check recovery status", zap.Error(err))
+		return nil, err
+	}
+	if inRecovery {
+		s.logger.Infow("instance is in recovery, skip create database", "database", req.GetDatabase())
+		return nil, nil
+	}
+
+	// CREATE DATABASE has no IF NOT EXISTS clause
+	var exists bool
+	if err := conn.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1)", req.GetDatabase()).Scan(&exists); err != nil {
+		s.logger.Errorw("failed to query database", zap.Error(err), zap.String("database", req.GetDatabase()))
+		return nil, err
+	}
+	if exists {
+		s.logger.Infow("database already exists", "database", req.GetDatabase())
+		return nil, nil
+	}
+
+	if _, err := conn.Exec(ctx, execSQL); err != nil {
+		s.logger.Errorw("failed to create database", zap.Error(err), zap.String("database", req.GetDatabase()))
+		return nil, err
+	}
+
+	s.logger.Info("create database successfully")
 	return nil, nil
 }
 
+// RotatePassword changes the password of the role on the primary, a standby
+// receives it through replication and only records the new password.
+func (s *service) RotatePassword(ctx context.Context, req *RotatePasswordRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "postgresql rotate password", map[string]interface{}{
+		"username": req.GetUsername(),
+		"user":     req.GetUser(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" || req.GetPassword() == "" {
+		err := fmt.Errorf("user and password are required")
+		s.logger.Errorw("invalid rotate password request", zap.Error(err))
+		return nil, err
+	}
+
+	conn, err := s.newPgConn(ctx, req.GetUsername(), "postgres")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	// Password changes are replicated from the primary, a standby only switches to the new password
+	inRecovery, err := isInRecovery(ctx, conn)
+	if err != nil {
+		s.logger.Errorw("failed to check recovery status", zap.Error(err))
+		return nil, err
+	}
+
+	if inRecovery {
+		s.logger.Infow("instance is in recovery, skip alter role password", "user", req.GetUser())
+	} else {
+		// PostgreSQL has a single password per role, the previous password stops working immediately
+		execSQL := fmt.Sprintf("ALTER ROLE %s WITH PASSWORD %s", pgx.Identifier{req.GetUser()}.Sanitize(), quoteLiteral(req.GetPassword()))
+		if _, err := conn.Exec(ctx, execSQL); err != nil {
+			s.logger.Errorw("failed to rotate password", zap.Error(err), zap.String("user", req.GetUser()))
+			return nil, err
+		}
+	}
+
+	util.SetRotatedPassword(req.GetUser(), req.GetPassword())
+
+	s.logger.Info("rotate password successfully")
+	return nil, nil
+}
+
+// grantPrivilege grants database level privileges through the given connection,
+// table level privileges are granted through a connection to the target database.
+func (s *service) grantPrivilege(ctx context.Context, conn *pgx.Conn, username, user string, privilege *Privilege) error {
+	execSQL, err := buildGrantSQL(user, privilege)
+	if err != nil {
+		return err
+	}
+
+	return s.execPrivilegeSQL(ctx, conn, username, privilege, execSQL)
+}
+
+// revokePrivilege revokes the privilege from the user, revoking a privilege
+// the user does not hold is a no-op.
+func (s *service) revokePrivilege(ctx context.Context, conn *pgx.Conn, username, user string, privilege *Privilege) error {
+	execSQL, err := buildRevokeSQL(user, privilege)
+	if err != nil {
+		return err
+	}
+
+	return s.execPrivilegeSQL(ctx, conn, username, privilege, execSQL)
+}
+
+// execPrivilegeSQL runs a GRANT or REVOKE statement on conn for database
+// privileges, or in the database of the privilege for table privileges.
+func (s *service) execPrivilegeSQL(ctx context.Context, conn *pgx.Conn, username string, privilege *Privilege, execSQL string) error {
+	if privilege.GetTable() == "" {
+		_, err := conn.Exec(ctx, execSQL)
+		return err
+	}
+
+	dbConn, err := s.newPgConn(ctx, username, privilege.GetDatabase())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbConn.Close(ctx) }()
+
+	_, err = dbConn.Exec(ctx, execSQL)
+	return err
+}
+
+func (s *service) newPgConn(ctx context.Context, username, database string) (*pgx.Conn, error) {
+	password, err := util.DecryptPlainTextPassword(username)
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", username))
+		return nil, err
+	}
+
+	connURL := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(username, password),
+		Host:     "localhost:5432",
+		Path:     database,
+		RawQuery: "connect_timeout=5",
+	}
+
+	conn, err := pgx.Connect(ctx, connURL.String())
+	if err != nil {
+		s.logger.Errorw("failed to connect to postgresql", zap.Error(err))
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func isInRecovery(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	var inRecovery bool
+	if err := conn.QueryRow(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery); err != nil {
+		return false, err
+	}
+
+	return inRecovery, nil
+}
+
+// buildGrantSQL builds a GRANT statement. An empty table grants on the database,
+// "*" grants on all tables of the public schema and "schema.table" on a single table.
+func buildGrantSQL(user string, privilege *Privilege) (string, error) {
+	privileges, object, err := privilegeObject(privilege)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("GRANT %s ON %s TO %s", privileges, object, pgx.Identifier{user}.Sanitize()), nil
+}
+
+// buildRevokeSQL builds the REVOKE statement of the privileges granted by buildGrantSQL.
+func buildRevokeSQL(user string, privilege *Privilege) (string, error) {
+	privileges, object, err := privilegeObject(privilege)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s", privileges, object, pgx.Identifier{user}.Sanitize()), nil
+}
+
+// privilegeObject validates the privilege and returns its privilege list and object.
+func privilegeObject(privilege *Privilege) (string, string, error) {
+	if privilege.GetDatabase() == "" {
+		return "", "", fmt.Errorf("database is required")
+	}
+	if len(privilege.GetPrivileges()) == 0 {
+		return "", "", fmt.Errorf("privileges of database %q are required", privilege.GetDatabase())
+	}
+
+	privileges := make([]string, 0, len(privilege.GetPrivileges()))
+	for _, p := range privilege.GetPrivileges() {
+		p = strings.ToUpper(strings.TrimSpace(p))
+		if !privilegeRE.MatchString(p) {
+			return "", "", fmt.Errorf("invalid privilege %q", p)
+		}
+		privileges = append(privileges, p)
+	}
+
+	var object string
+	switch table := privilege.GetTable(); table {
+	case "":
+		object = "DATABASE " + pgx.Identifier{privilege.GetDatabase()}.Sanitize()
+	case "*":
+		object = "ALL TABLES IN SCHEMA public"
+	default:
+		object = "TABLE " + pgx.Identifier(strings.Split(table, ".")).Sanitize()
+	}
+
+	return strings.Join(privileges, ", "), object, nil
+}
+
+func buildCreateDatabaseSQL(database, owner, encoding string) (string, error) {
+	if database == "" {
+		return "", fmt.Errorf("database is required")
+	}
+
+	execSQL := "CREATE DATABASE " + pgx.Identifier{database}.Sanitize()
+	if owner != "" {
+		execSQL += " OWNER " + pgx.Identifier{owner}.Sanitize()
+	}
+	if encoding != "" {
+		if !encodingRE.MatchString(encoding) {
+			return "", fmt.Errorf("invalid encoding %q", encoding)
+		}
+		execSQL += " ENCODING " + quoteLiteral(encoding)
+	}
+
+	return execSQL, nil
+}
+
+// normalizeSettingName validates the name of a setting, extension settings such as
+// pg_stat_statements.max are allowed
+func normalizeSettingName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !settingNameRE.MatchString(name) {
+		return "", fmt.Errorf("invalid setting name %q", name)
+	}
+
+	return name, nil
+}
+
+// normalizeSettings validates the names of a batch of settings
+func normalizeSettings(variables map[string]string) (map[string]string, error) {
+	if len(variables) == 0 {
+		return nil, fmt.Errorf("variables is required")
+	}
+
+	normalized := make(map[string]string, len(variables))
+	for key, value := range variables {
+		name, err := normalizeSettingName(key)
+		if err != nil {
+			return nil, err
+		}
+		normalized[name] = value
+	}
+
+	return normalized, nil
+}
+
+// quoteLiteral quotes a string literal, standard_conforming_strings is assumed to be on
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
 func (s *service) removeContents(dir string) error {
 	d, err := os.Open(dir)
 	if err != nil {
@@ -355,34 +887,57 @@ func (s *service) recursiveChown(rootPath string, uid, gid int) error {
 	})
 }
 
-func (s *service) extractFileFromS3(ctx context.Context, storageFactory common.ObjectStorageFactory, bucket, key, filename, targetDir string) error {
+// extractFileFromS3 extracts a tar object into targetDir, returning the size of the extracted files
+func (s *service) extractFileFromS3(ctx context.Context, storageFactory common.ObjectStorageFactory, bucket, key, filename, targetDir string) (int64, error) {
 	fileKey := filepath.Join(key, filename)
 
 	obj, err := storageFactory.GetObject(ctx, bucket, fileKey)
 
 	if err != nil {
-		return fmt.Errorf("download %s failed: %v", fileKey, err)
+		return 0, fmt.Errorf("download %s failed: %v", fileKey, err)
 	}
 
 	defer obj.Close()
 
 	tr := tar.NewReader(obj)
 
+	var size int64
 	for {
 		hdr, err := tr.Next()
 		if err == io.EOF {
-			return nil
+			return size, nil
 		}
 		if err != nil {
-			return err
+			return 0, err
 		}
 
 		if err := s.extractTarEntry(tr, hdr, targetDir); err != nil {
-			return err
+			return 0, err
 		}
+		size += hdr.Size
 	}
 }
 
+// readBackupManifestEndLSN returns the end LSN of the last WAL range of a
+// pg_basebackup backup_manifest, which the backup is consistent to
+func readBackupManifestEndLSN(file string) string {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+
+	var manifest struct {
+		WALRanges []struct {
+			EndLSN string `json:"End-LSN"`
+		} `json:"WAL-Ranges"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil || len(manifest.WALRanges) == 0 {
+		return ""
+	}
+
+	return manifest.WALRanges[len(manifest.WALRanges)-1].EndLSN
+}
+
 func (s *service) safeTarPath(targetDir, tarName string) (string, error) {
 	cleanName := filepath.Clean(tarName)
 
@@ -114,8 +114,9 @@ func TestExtractFileFromS3(t *testing.T) {
 
 	fakeFactory := &fakeStorageFactory{data: tarData}
 
-	err := svc.extractFileFromS3(context.Background(), fakeFactory, "bucket", "backup", "base.tar", dir)
+	size, err := svc.extractFileFromS3(context.Background(), fakeFactory, "bucket", "backup", "base.tar", dir)
 	require.NoError(t, err)
+	require.Equal(t, int64(len("content")), size)
 
 	content, err := os.ReadFile(filepath.Join(dir, "base/file.txt"))
 	require.NoError(t, err)
@@ -135,3 +136,91 @@ func TestRemoveContents(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, entries, 0)
 }
+
+func TestBuildGrantSQL(t *testing.T) {
+	execSQL, err := buildGrantSQL("app", &Privilege{Database: "orders", Privileges: []string{"connect", "create"}})
+	require.NoError(t, err)
+	require.Equal(t, `GRANT CONNECT, CREATE ON DATABASE "orders" TO "app"`, execSQL)
+
+	execSQL, err = buildGrantSQL("app", &Privilege{Database: "orders", Table: "*", Privileges: []string{"SELECT"}})
+	require.NoError(t, err)
+	require.Equal(t, `GRANT SELECT ON ALL TABLES IN SCHEMA public TO "app"`, execSQL)
+
+	execSQL, err = buildGrantSQL("app", &Privilege{Database: "orders", Table: "sales.items", Privileges: []string{"SELECT", "UPDATE"}})
+	require.NoError(t, err)
+	require.Equal(t, `GRANT SELECT, UPDATE ON TABLE "sales"."items" TO "app"`, execSQL)
+}
+
+func TestBuildRevokeSQL(t *testing.T) {
+	execSQL, err := buildRevokeSQL("app", &Privilege{Database: "orders", Table: "*", Privileges: []string{"update"}})
+	require.NoError(t, err)
+	require.Equal(t, `REVOKE UPDATE ON ALL TABLES IN SCHEMA public FROM "app"`, execSQL)
+
+	_, err = buildRevokeSQL("app", &Privilege{Database: "orders"})
+	require.Error(t, err)
+}
+
+func TestBuildGrantSQLRejectsInvalidInput(t *testing.T) {
+	_, err := buildGrantSQL("app", &Privilege{Privileges: []string{"SELECT"}})
+	require.Error(t, err)
+
+	_, err = buildGrantSQL("app", &Privilege{Database: "orders"})
+	require.Error(t, err)
+
+	_, err = buildGrantSQL("app", &Privilege{Database: "orders", Privileges: []string{"ALL; DROP ROLE app"}})
+	require.Error(t, err)
+}
+
+func TestBuildCreateDatabaseSQL(t *testing.T) {
+	execSQL, err := buildCreateDatabaseSQL("orders", "app", "UTF8")
+	require.NoError(t, err)
+	require.Equal(t, `CREATE DATABASE "orders" OWNER "app" ENCODING 'UTF8'`, execSQL)
+
+	_, err = buildCreateDatabaseSQL("", "", "")
+	require.Error(t, err)
+
+	_, err = buildCreateDatabaseSQL("orders", "", "UTF8'; --")
+	require.Error(t, err)
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	require.Equal(t, `'it''s'`, quoteLiteral("it's"))
+}
+
+func TestNormalizeSettingName(t *testing.T) {
+	name, err := normalizeSettingName("Work_Mem")
+	require.NoError(t, err)
+	require.Equal(t, "work_mem", name)
+
+	name, err = normalizeSettingName("pg_stat_statements.max")
+	require.NoError(t, err)
+	require.Equal(t, "pg_stat_statements.max", name)
+
+	for _, invalid := range []string{"", "work_mem = 1; DROP TABLE t", "a.b.c", `"work_mem"`} {
+		_, err = normalizeSettingName(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestNormalizeSettings(t *testing.T) {
+	variables, err := normalizeSettings(map[string]string{"Work_Mem": "64MB", "pg_stat_statements.max": "10000"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"work_mem": "64MB", "pg_stat_statements.max": "10000"}, variables)
+
+	_, err = normalizeSettings(map[string]string{})
+	require.Error(t, err)
+
+	_, err = normalizeSettings(map[string]string{"work_mem = 1; --": "1"})
+	require.Error(t, err)
+}
+
+func TestReadBackupManifestEndLSN(t *testing.T) {
+	dir := t.TempDir()
+
+	manifest := filepath.Join(dir, "backup_manifest")
+	require.NoError(t, os.WriteFile(manifest, []byte(`{"PostgreSQL-Backup-Manifest-Version": 1, "Files": [],
+"WAL-Ranges": [{"Timeline": 1, "Start-LSN": "0/2000028", "End-LSN": "0/2000100"}]}`), 0o644))
+	require.Equal(t, "0/2000100", readBackupManifestEndLSN(manifest))
+
+	require.Empty(t, readBackupManifestEndLSN(filepath.Join(dir, "missing")))
+}
@@ -327,6 +327,383 @@ func (x *SetVariableRequest) GetUsername() string {
 	return ""
 }
 
+type SetVariablesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variables     map[string]string      `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	DryRun        bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVariablesRequest) Reset() {
+	*x = SetVariablesRequest{}
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariablesRequest) ProtoMessage() {}
+
+func (x *SetVariablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariablesRequest.ProtoReflect.Descriptor instead.
+func (*SetVariablesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SetVariablesRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *SetVariablesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetVariablesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type Privilege struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Database      string                 `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Table         string                 `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Privileges    []string               `protobuf:"bytes,3,rep,name=privileges,proto3" json:"privileges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Privilege) Reset() {
+	*x = Privilege{}
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Privilege) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Privilege) ProtoMessage() {}
+
+func (x *Privilege) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Privilege.ProtoReflect.Descriptor instead.
+func (*Privilege) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Privilege) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *Privilege) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *Privilege) GetPrivileges() []string {
+	if x != nil {
+		return x.Privileges
+	}
+	return nil
+}
+
+type CreateUserRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Username   string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User       string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Password   string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Privileges []*Privilege           `protobuf:"bytes,4,rep,name=privileges,proto3" json:"privileges,omitempty"`
+	// revokes are the privileges removed from the user since the last call
+	Revokes       []*Privilege `protobuf:"bytes,5,rep,name=revokes,proto3" json:"revokes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPrivileges() []*Privilege {
+	if x != nil {
+		return x.Privileges
+	}
+	return nil
+}
+
+func (x *CreateUserRequest) GetRevokes() []*Privilege {
+	if x != nil {
+		return x.Revokes
+	}
+	return nil
+}
+
+type DropUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropUserRequest) Reset() {
+	*x = DropUserRequest{}
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropUserRequest) ProtoMessage() {}
+
+func (x *DropUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropUserRequest.ProtoReflect.Descriptor instead.
+func (*DropUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DropUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *DropUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type CreateDatabaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Database      string                 `protobuf:"bytes,2,opt,name=database,proto3" json:"database,omitempty"`
+	Owner         string                 `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	Encoding      string                 `protobuf:"bytes,4,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDatabaseRequest) Reset() {
+	*x = CreateDatabaseRequest{}
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDatabaseRequest) ProtoMessage() {}
+
+func (x *CreateDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*CreateDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateDatabaseRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateDatabaseRequest) GetDatabase() string {
+	if x != nil {
+		return x.Database
+	}
+	return ""
+}
+
+func (x *CreateDatabaseRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *CreateDatabaseRequest) GetEncoding() string {
+	if x != nil {
+		return x.Encoding
+	}
+	return ""
+}
+
+type RotatePasswordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotatePasswordRequest) Reset() {
+	*x = RotatePasswordRequest{}
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotatePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotatePasswordRequest) ProtoMessage() {}
+
+func (x *RotatePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotatePasswordRequest.ProtoReflect.Descriptor instead.
+func (*RotatePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RotatePasswordRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
 var File_pkg_agent_app_postgresql_pb_postgresql_proto protoreflect.FileDescriptor
 
 const file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDesc = "" +
@@ -353,16 +730,55 @@ const file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDesc = "" +
 	"\x12SetVariableRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1a\n" +
-	"\busername\x18\x03 \x01(\tR\busername*6\n" +
+	"\busername\x18\x03 \x01(\tR\busername\"\xd6\x01\n" +
+	"\x13SetVariablesRequest\x12L\n" +
+	"\tvariables\x18\x01 \x03(\v2..postgresql.SetVariablesRequest.VariablesEntryR\tvariables\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"]\n" +
+	"\tPrivilege\x12\x1a\n" +
+	"\bdatabase\x18\x01 \x01(\tR\bdatabase\x12\x14\n" +
+	"\x05table\x18\x02 \x01(\tR\x05table\x12\x1e\n" +
+	"\n" +
+	"privileges\x18\x03 \x03(\tR\n" +
+	"privileges\"\xc7\x01\n" +
+	"\x11CreateUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x125\n" +
+	"\n" +
+	"privileges\x18\x04 \x03(\v2\x15.postgresql.PrivilegeR\n" +
+	"privileges\x12/\n" +
+	"\arevokes\x18\x05 \x03(\v2\x15.postgresql.PrivilegeR\arevokes\"A\n" +
+	"\x0fDropUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\"\x81\x01\n" +
+	"\x15CreateDatabaseRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
+	"\bdatabase\x18\x02 \x01(\tR\bdatabase\x12\x14\n" +
+	"\x05owner\x18\x03 \x01(\tR\x05owner\x12\x1a\n" +
+	"\bencoding\x18\x04 \x01(\tR\bencoding\"c\n" +
+	"\x15RotatePasswordRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword*6\n" +
 	"\x11LogicalBackupMode\x12\b\n" +
 	"\x04Full\x10\x00\x12\f\n" +
 	"\bDatabase\x10\x01\x12\t\n" +
-	"\x05Table\x10\x022\x8f\x02\n" +
-	"\x13PostgresqlOperation\x12B\n" +
-	"\x0ePhysicalBackup\x12!.postgresql.PhysicalBackupRequest\x1a\r.common.Empty\x12@\n" +
-	"\rLogicalBackup\x12 .postgresql.LogicalBackupRequest\x1a\r.common.Empty\x124\n" +
-	"\aRestore\x12\x1a.postgresql.RestoreRequest\x1a\r.common.Empty\x12<\n" +
-	"\vSetVariable\x12\x1e.postgresql.SetVariableRequest\x1a\r.common.EmptyB9Z7github.com/upmio/unit-operator/pkg/agent/app/postgresqlb\x06proto3"
+	"\x05Table\x10\x022\x84\x05\n" +
+	"\x13PostgresqlOperation\x12K\n" +
+	"\x0ePhysicalBackup\x12!.postgresql.PhysicalBackupRequest\x1a\x16.common.BackupResponse\x12I\n" +
+	"\rLogicalBackup\x12 .postgresql.LogicalBackupRequest\x1a\x16.common.BackupResponse\x12>\n" +
+	"\aRestore\x12\x1a.postgresql.RestoreRequest\x1a\x17.common.RestoreResponse\x12J\n" +
+	"\vSetVariable\x12\x1e.postgresql.SetVariableRequest\x1a\x1b.common.SetVariableResponse\x12M\n" +
+	"\fSetVariables\x12\x1f.postgresql.SetVariablesRequest\x1a\x1c.common.SetVariablesResponse\x12:\n" +
+	"\n" +
+	"CreateUser\x12\x1d.postgresql.CreateUserRequest\x1a\r.common.Empty\x126\n" +
+	"\bDropUser\x12\x1b.postgresql.DropUserRequest\x1a\r.common.Empty\x12B\n" +
+	"\x0eCreateDatabase\x12!.postgresql.CreateDatabaseRequest\x1a\r.common.Empty\x12B\n" +
+	"\x0eRotatePassword\x12!.postgresql.RotatePasswordRequest\x1a\r.common.EmptyB9Z7github.com/upmio/unit-operator/pkg/agent/app/postgresqlb\x06proto3"
 
 var (
 	file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDescOnce sync.Once
@@ -377,34 +793,58 @@ func file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDescGZIP() []byte {
 }
 
 var file_pkg_agent_app_postgresql_pb_postgresql_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_pkg_agent_app_postgresql_pb_postgresql_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_pkg_agent_app_postgresql_pb_postgresql_proto_goTypes = []any{
-	(LogicalBackupMode)(0),        // 0: postgresql.LogicalBackupMode
-	(*LogicalBackupRequest)(nil),  // 1: postgresql.LogicalBackupRequest
-	(*PhysicalBackupRequest)(nil), // 2: postgresql.PhysicalBackupRequest
-	(*RestoreRequest)(nil),        // 3: postgresql.RestoreRequest
-	(*SetVariableRequest)(nil),    // 4: postgresql.SetVariableRequest
-	(*common.ObjectStorage)(nil),  // 5: common.ObjectStorage
-	(*common.Empty)(nil),          // 6: common.Empty
+	(LogicalBackupMode)(0),              // 0: postgresql.LogicalBackupMode
+	(*LogicalBackupRequest)(nil),        // 1: postgresql.LogicalBackupRequest
+	(*PhysicalBackupRequest)(nil),       // 2: postgresql.PhysicalBackupRequest
+	(*RestoreRequest)(nil),              // 3: postgresql.RestoreRequest
+	(*SetVariableRequest)(nil),          // 4: postgresql.SetVariableRequest
+	(*SetVariablesRequest)(nil),         // 5: postgresql.SetVariablesRequest
+	(*Privilege)(nil),                   // 6: postgresql.Privilege
+	(*CreateUserRequest)(nil),           // 7: postgresql.CreateUserRequest
+	(*DropUserRequest)(nil),             // 8: postgresql.DropUserRequest
+	(*CreateDatabaseRequest)(nil),       // 9: postgresql.CreateDatabaseRequest
+	(*RotatePasswordRequest)(nil),       // 10: postgresql.RotatePasswordRequest
+	nil,                                 // 11: postgresql.SetVariablesRequest.VariablesEntry
+	(*common.ObjectStorage)(nil),        // 12: common.ObjectStorage
+	(*common.BackupResponse)(nil),       // 13: common.BackupResponse
+	(*common.RestoreResponse)(nil),      // 14: common.RestoreResponse
+	(*common.SetVariableResponse)(nil),  // 15: common.SetVariableResponse
+	(*common.SetVariablesResponse)(nil), // 16: common.SetVariablesResponse
+	(*common.Empty)(nil),                // 17: common.Empty
 }
 var file_pkg_agent_app_postgresql_pb_postgresql_proto_depIdxs = []int32{
-	0, // 0: postgresql.LogicalBackupRequest.logical_backup_mode:type_name -> postgresql.LogicalBackupMode
-	5, // 1: postgresql.LogicalBackupRequest.object_storage:type_name -> common.ObjectStorage
-	5, // 2: postgresql.PhysicalBackupRequest.object_storage:type_name -> common.ObjectStorage
-	5, // 3: postgresql.RestoreRequest.object_storage:type_name -> common.ObjectStorage
-	2, // 4: postgresql.PostgresqlOperation.PhysicalBackup:input_type -> postgresql.PhysicalBackupRequest
-	1, // 5: postgresql.PostgresqlOperation.LogicalBackup:input_type -> postgresql.LogicalBackupRequest
-	3, // 6: postgresql.PostgresqlOperation.Restore:input_type -> postgresql.RestoreRequest
-	4, // 7: postgresql.PostgresqlOperation.SetVariable:input_type -> postgresql.SetVariableRequest
-	6, // 8: postgresql.PostgresqlOperation.PhysicalBackup:output_type -> common.Empty
-	6, // 9: postgresql.PostgresqlOperation.LogicalBackup:output_type -> common.Empty
-	6, // 10: postgresql.PostgresqlOperation.Restore:output_type -> common.Empty
-	6, // 11: postgresql.PostgresqlOperation.SetVariable:output_type -> common.Empty
-	8, // [8:12] is the sub-list for method output_type
-	4, // [4:8] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	0,  // 0: postgresql.LogicalBackupRequest.logical_backup_mode:type_name -> postgresql.LogicalBackupMode
+	12, // 1: postgresql.LogicalBackupRequest.object_storage:type_name -> common.ObjectStorage
+	12, // 2: postgresql.PhysicalBackupRequest.object_storage:type_name -> common.ObjectStorage
+	12, // 3: postgresql.RestoreRequest.object_storage:type_name -> common.ObjectStorage
+	11, // 4: postgresql.SetVariablesRequest.variables:type_name -> postgresql.SetVariablesRequest.VariablesEntry
+	6,  // 5: postgresql.CreateUserRequest.privileges:type_name -> postgresql.Privilege
+	6,  // 6: postgresql.CreateUserRequest.revokes:type_name -> postgresql.Privilege
+	2,  // 7: postgresql.PostgresqlOperation.PhysicalBackup:input_type -> postgresql.PhysicalBackupRequest
+	1,  // 8: postgresql.PostgresqlOperation.LogicalBackup:input_type -> postgresql.LogicalBackupRequest
+	3,  // 9: postgresql.PostgresqlOperation.Restore:input_type -> postgresql.RestoreRequest
+	4,  // 10: postgresql.PostgresqlOperation.SetVariable:input_type -> postgresql.SetVariableRequest
+	5,  // 11: postgresql.PostgresqlOperation.SetVariables:input_type -> postgresql.SetVariablesRequest
+	7,  // 12: postgresql.PostgresqlOperation.CreateUser:input_type -> postgresql.CreateUserRequest
+	8,  // 13: postgresql.PostgresqlOperation.DropUser:input_type -> postgresql.DropUserRequest
+	9,  // 14: postgresql.PostgresqlOperation.CreateDatabase:input_type -> postgresql.CreateDatabaseRequest
+	10, // 15: postgresql.PostgresqlOperation.RotatePassword:input_type -> postgresql.RotatePasswordRequest
+	13, // 16: postgresql.PostgresqlOperation.PhysicalBackup:output_type -> common.BackupResponse
+	13, // 17: postgresql.PostgresqlOperation.LogicalBackup:output_type -> common.BackupResponse
+	14, // 18: postgresql.PostgresqlOperation.Restore:output_type -> common.RestoreResponse
+	15, // 19: postgresql.PostgresqlOperation.SetVariable:output_type -> common.SetVariableResponse
+	16, // 20: postgresql.PostgresqlOperation.SetVariables:output_type -> common.SetVariablesResponse
+	17, // 21: postgresql.PostgresqlOperation.CreateUser:output_type -> common.Empty
+	17, // 22: postgresql.PostgresqlOperation.DropUser:output_type -> common.Empty
+	17, // 23: postgresql.PostgresqlOperation.CreateDatabase:output_type -> common.Empty
+	17, // 24: postgresql.PostgresqlOperation.RotatePassword:output_type -> common.Empty
+	16, // [16:25] is the sub-list for method output_type
+	7,  // [7:16] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_pkg_agent_app_postgresql_pb_postgresql_proto_init() }
@@ -418,7 +858,7 @@ func file_pkg_agent_app_postgresql_pb_postgresql_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDesc), len(file_pkg_agent_app_postgresql_pb_postgresql_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   4,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,10 +19,15 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PostgresqlOperationClient interface {
-	PhysicalBackup(ctx context.Context, in *PhysicalBackupRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	PhysicalBackup(ctx context.Context, in *PhysicalBackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error)
+	LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error)
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.RestoreResponse, error)
+	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error)
+	SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	DropUser(ctx context.Context, in *DropUserRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	CreateDatabase(ctx context.Context, in *CreateDatabaseRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	RotatePassword(ctx context.Context, in *RotatePasswordRequest, opts ...grpc.CallOption) (*common.Empty, error)
 }
 
 type postgresqlOperationClient struct {
@@ -33,8 +38,8 @@ func NewPostgresqlOperationClient(cc grpc.ClientConnInterface) PostgresqlOperati
 	return &postgresqlOperationClient{cc}
 }
 
-func (c *postgresqlOperationClient) PhysicalBackup(ctx context.Context, in *PhysicalBackupRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *postgresqlOperationClient) PhysicalBackup(ctx context.Context, in *PhysicalBackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error) {
+	out := new(common.BackupResponse)
 	err := c.cc.Invoke(ctx, "/postgresql.PostgresqlOperation/PhysicalBackup", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -42,8 +47,8 @@ func (c *postgresqlOperationClient) PhysicalBackup(ctx context.Context, in *Phys
 	return out, nil
 }
 
-func (c *postgresqlOperationClient) LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *postgresqlOperationClient) LogicalBackup(ctx context.Context, in *LogicalBackupRequest, opts ...grpc.CallOption) (*common.BackupResponse, error) {
+	out := new(common.BackupResponse)
 	err := c.cc.Invoke(ctx, "/postgresql.PostgresqlOperation/LogicalBackup", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -51,8 +56,8 @@ func (c *postgresqlOperationClient) LogicalBackup(ctx context.Context, in *Logic
 	return out, nil
 }
 
-func (c *postgresqlOperationClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *postgresqlOperationClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*common.RestoreResponse, error) {
+	out := new(common.RestoreResponse)
 	err := c.cc.Invoke(ctx, "/postgresql.PostgresqlOperation/Restore", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -60,8 +65,8 @@ func (c *postgresqlOperationClient) Restore(ctx context.Context, in *RestoreRequ
 	return out, nil
 }
 
-func (c *postgresqlOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *postgresqlOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error) {
+	out := new(common.SetVariableResponse)
 	err := c.cc.Invoke(ctx, "/postgresql.PostgresqlOperation/SetVariable", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -69,14 +74,64 @@ func (c *postgresqlOperationClient) SetVariable(ctx context.Context, in *SetVari
 	return out, nil
 }
 
+func (c *postgresqlOperationClient) SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error) {
+	out := new(common.SetVariablesResponse)
+	err := c.cc.Invoke(ctx, "/postgresql.PostgresqlOperation/SetVariables", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postgresqlOperationClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/postgresql.PostgresqlOperation/CreateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postgresqlOperationClient) DropUser(ctx context.Context, in *DropUserRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/postgresql.PostgresqlOperation/DropUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postgresqlOperationClient) CreateDatabase(ctx context.Context, in *CreateDatabaseRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/postgresql.PostgresqlOperation/CreateDatabase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postgresqlOperationClient) RotatePassword(ctx context.Context, in *RotatePasswordRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/postgresql.PostgresqlOperation/RotatePassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PostgresqlOperationServer is the server API for PostgresqlOperation service.
 // All implementations must embed UnimplementedPostgresqlOperationServer
 // for forward compatibility
 type PostgresqlOperationServer interface {
-	PhysicalBackup(context.Context, *PhysicalBackupRequest) (*common.Empty, error)
-	LogicalBackup(context.Context, *LogicalBackupRequest) (*common.Empty, error)
-	Restore(context.Context, *RestoreRequest) (*common.Empty, error)
-	SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error)
+	PhysicalBackup(context.Context, *PhysicalBackupRequest) (*common.BackupResponse, error)
+	LogicalBackup(context.Context, *LogicalBackupRequest) (*common.BackupResponse, error)
+	Restore(context.Context, *RestoreRequest) (*common.RestoreResponse, error)
+	SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error)
+	SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error)
+	CreateUser(context.Context, *CreateUserRequest) (*common.Empty, error)
+	DropUser(context.Context, *DropUserRequest) (*common.Empty, error)
+	CreateDatabase(context.Context, *CreateDatabaseRequest) (*common.Empty, error)
+	RotatePassword(context.Context, *RotatePasswordRequest) (*common.Empty, error)
 	mustEmbedUnimplementedPostgresqlOperationServer()
 }
 
@@ -84,18 +139,33 @@ type PostgresqlOperationServer interface {
 type UnimplementedPostgresqlOperationServer struct {
 }
 
-func (UnimplementedPostgresqlOperationServer) PhysicalBackup(context.Context, *PhysicalBackupRequest) (*common.Empty, error) {
+func (UnimplementedPostgresqlOperationServer) PhysicalBackup(context.Context, *PhysicalBackupRequest) (*common.BackupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PhysicalBackup not implemented")
 }
-func (UnimplementedPostgresqlOperationServer) LogicalBackup(context.Context, *LogicalBackupRequest) (*common.Empty, error) {
+func (UnimplementedPostgresqlOperationServer) LogicalBackup(context.Context, *LogicalBackupRequest) (*common.BackupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LogicalBackup not implemented")
 }
-func (UnimplementedPostgresqlOperationServer) Restore(context.Context, *RestoreRequest) (*common.Empty, error) {
+func (UnimplementedPostgresqlOperationServer) Restore(context.Context, *RestoreRequest) (*common.RestoreResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
 }
-func (UnimplementedPostgresqlOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error) {
+func (UnimplementedPostgresqlOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetVariable not implemented")
 }
+func (UnimplementedPostgresqlOperationServer) SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVariables not implemented")
+}
+func (UnimplementedPostgresqlOperationServer) CreateUser(context.Context, *CreateUserRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedPostgresqlOperationServer) DropUser(context.Context, *DropUserRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropUser not implemented")
+}
+func (UnimplementedPostgresqlOperationServer) CreateDatabase(context.Context, *CreateDatabaseRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDatabase not implemented")
+}
+func (UnimplementedPostgresqlOperationServer) RotatePassword(context.Context, *RotatePasswordRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotatePassword not implemented")
+}
 func (UnimplementedPostgresqlOperationServer) mustEmbedUnimplementedPostgresqlOperationServer() {}
 
 // UnsafePostgresqlOperationServer may be embedded to opt out of forward compatibility for this service.
@@ -181,6 +251,96 @@ func _PostgresqlOperation_SetVariable_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PostgresqlOperation_SetVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVariablesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostgresqlOperationServer).SetVariables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/postgresql.PostgresqlOperation/SetVariables",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostgresqlOperationServer).SetVariables(ctx, req.(*SetVariablesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PostgresqlOperation_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostgresqlOperationServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/postgresql.PostgresqlOperation/CreateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostgresqlOperationServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PostgresqlOperation_DropUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostgresqlOperationServer).DropUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/postgresql.PostgresqlOperation/DropUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostgresqlOperationServer).DropUser(ctx, req.(*DropUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PostgresqlOperation_CreateDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDatabaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostgresqlOperationServer).CreateDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/postgresql.PostgresqlOperation/CreateDatabase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostgresqlOperationServer).CreateDatabase(ctx, req.(*CreateDatabaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PostgresqlOperation_RotatePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotatePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostgresqlOperationServer).RotatePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/postgresql.PostgresqlOperation/RotatePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostgresqlOperationServer).RotatePassword(ctx, req.(*RotatePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PostgresqlOperation_ServiceDesc is the grpc.ServiceDesc for PostgresqlOperation service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -204,6 +364,26 @@ var PostgresqlOperation_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetVariable",
 			Handler:    _PostgresqlOperation_SetVariable_Handler,
 		},
+		{
+			MethodName: "SetVariables",
+			Handler:    _PostgresqlOperation_SetVariables_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _PostgresqlOperation_CreateUser_Handler,
+		},
+		{
+			MethodName: "DropUser",
+			Handler:    _PostgresqlOperation_DropUser_Handler,
+		},
+		{
+			MethodName: "CreateDatabase",
+			Handler:    _PostgresqlOperation_CreateDatabase_Handler,
+		},
+		{
+			MethodName: "RotatePassword",
+			Handler:    _PostgresqlOperation_RotatePassword_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/agent/app/postgresql/pb/postgresql.proto",
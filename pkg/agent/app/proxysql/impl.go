@@ -3,22 +3,60 @@ package proxysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net"
+	"regexp"
+	"sort"
+	"strings"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/upmio/unit-operator/pkg/agent/app"
 	"github.com/upmio/unit-operator/pkg/agent/app/common"
 	"github.com/upmio/unit-operator/pkg/agent/app/slm"
 	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 	// this import  needs to be done otherwise the mysql driver don't work
 	_ "github.com/go-sql-driver/mysql"
 )
 
+const (
+	defaultMaxConnections = 10000
+
+	deleteMysqlUserSql = `DELETE FROM mysql_users WHERE username = ?`
+	insertMysqlUserSql = `INSERT INTO mysql_users (username, password, active, default_hostgroup, default_schema, max_connections) VALUES (?, ?, 1, ?, ?, ?)`
+
+	getVariableSql        = `SELECT variable_value FROM global_variables WHERE variable_name = ?`
+	getRuntimeVariableSql = `SELECT variable_value FROM runtime_global_variables WHERE variable_name = ?`
+	updateVariableSql     = `UPDATE global_variables SET variable_value = ? WHERE variable_name = ?`
+	loadVariablesSql      = `LOAD %s VARIABLES TO RUNTIME`
+	saveVariablesSql      = `SAVE %s VARIABLES TO DISK`
+
+	listMysqlUsersSql = `SELECT username, active, default_hostgroup, default_schema, max_connections FROM mysql_users ORDER BY username`
+
+	defaultCheckType            = "read_only"
+	defaultServerWeight         = 1
+	defaultServerMaxConnections = 1000
+
+	listServersSql                 = `SELECT hostgroup_id, hostname, port, status, weight, max_connections, max_replication_lag, comment FROM %s`
+	serversInHostgroupsSql         = ` WHERE hostgroup_id IN (%s)`
+	deleteMysqlServerSql           = `DELETE FROM mysql_servers WHERE hostgroup_id = ? AND hostname = ? AND port = ?`
+	replaceMysqlServerSql          = `REPLACE INTO mysql_servers (hostgroup_id, hostname, port, status, weight, max_connections, max_replication_lag, comment) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	listReplicationHostgroupsSql   = `SELECT writer_hostgroup, reader_hostgroup, check_type, comment FROM runtime_mysql_replication_hostgroups`
+	replaceReplicationHostgroupSql = `REPLACE INTO mysql_replication_hostgroups (writer_hostgroup, reader_hostgroup, check_type, comment) VALUES (?, ?, ?, ?)`
+
+	deleteQueryRulesSql = `DELETE FROM mysql_query_rules`
+	insertQueryRuleSql  = `INSERT INTO mysql_query_rules (rule_id, active, username, schemaname, match_digest, match_pattern, destination_hostgroup, apply, comment) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	listQueryRulesSql   = `SELECT rule_id, active, username, schemaname, match_digest, match_pattern, destination_hostgroup, apply, comment FROM runtime_mysql_query_rules ORDER BY rule_id`
+)
+
 var (
 	// service instance
 	svr = &service{}
+
+	variableKeyRE = regexp.MustCompile(`^[a-z0-9_]+$`)
 )
 
 type service struct {
@@ -46,12 +84,17 @@ func (s *service) Registry(server *grpc.Server) {
 	RegisterProxysqlOperationServer(server, svr)
 }
 
-func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.Empty, error) {
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterProxysqlOperationHandler(ctx, mux, conn)
+}
+
+func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.SetVariableResponse, error) {
 	util.LogRequestSafely(s.logger, "proxysql set variable", map[string]interface{}{
 		"key":      req.GetKey(),
 		"value":    req.GetValue(),
 		"section":  req.GetSection(),
 		"username": req.GetUsername(),
+		"persist":  req.GetPersist(),
 	})
 
 	// Check process is started
@@ -60,6 +103,12 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 		return nil, err
 	}
 
+	section, name, err := normalizeVariableName(req.GetSection(), req.GetKey())
+	if err != nil {
+		s.logger.Errorw("invalid variable name", zap.Error(err))
+		return nil, err
+	}
+
 	// Create proxysql connection
 	db, err := s.newDBConn(ctx, req.GetUsername())
 	if err != nil {
@@ -67,31 +116,748 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 	}
 	defer s.closeDBConn(db)
 
+	var previous string
+	if err = db.QueryRowContext(ctx, getRuntimeVariableSql, name).Scan(&previous); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("unknown variable %s", name)
+		}
+		s.logger.Errorw("failed to check variable", zap.Error(err), zap.String("key", name))
+		return nil, err
+	}
+
 	// Execute set variable
-	execSQL := fmt.Sprintf("SET %s-%s = %s", req.GetSection(), req.GetKey(), req.GetValue())
-	if _, err = db.ExecContext(ctx, execSQL); err != nil {
-		s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("key", req.GetKey()), zap.String("value", req.GetValue()))
+	if _, err = db.ExecContext(ctx, updateVariableSql, req.GetValue(), name); err != nil {
+		s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("key", name), zap.String("value", req.GetValue()))
 		return nil, err
 	}
 
 	// Load variables to runtime based on section
-	switch req.GetSection() {
-	case "admin":
-		if _, err = db.ExecContext(ctx, `LOAD ADMIN VARIABLES TO RUNTIME`); err != nil {
-			s.logger.Errorw("failed to load admin section variable to runtime", zap.Error(err))
+	upper := strings.ToUpper(section)
+	if _, err = db.ExecContext(ctx, fmt.Sprintf(loadVariablesSql, upper)); err != nil {
+		s.logger.Errorw(fmt.Sprintf("failed to load %s section variable to runtime", section), zap.Error(err))
+		return nil, err
+	}
+
+	var current string
+	if err = db.QueryRowContext(ctx, getRuntimeVariableSql, name).Scan(&current); err != nil {
+		s.logger.Errorw("failed to get variable", zap.Error(err), zap.String("key", name))
+		return nil, err
+	}
+	resp := common.NewSetVariableResponse(previous, current)
+
+	if req.GetPersist() {
+		if _, err = db.ExecContext(ctx, fmt.Sprintf(saveVariablesSql, upper)); err != nil {
+			s.logger.Errorw(fmt.Sprintf("failed to save %s section variable to disk", section), zap.Error(err))
+			return nil, err
+		}
+		resp.Persisted = true
+	}
+
+	s.logger.Infow("set variable successfully", "changed", resp.GetChanged())
+	return resp, nil
+}
+
+func (s *service) SetVariables(ctx context.Context, req *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	util.LogRequestSafely(s.logger, "proxysql set variables", map[string]interface{}{
+		"variables": req.GetVariables(),
+		"username":  req.GetUsername(),
+		"persist":   req.GetPersist(),
+		"dry_run":   req.GetDryRun(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	variables, sections, err := normalizeVariables(req.GetVariables())
+	if err != nil {
+		s.logger.Errorw("invalid variable name", zap.Error(err))
+		return nil, err
+	}
+
+	// Create proxysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	// Read the current values, unknown variables fail the whole batch
+	resp := &common.SetVariablesResponse{DryRun: req.GetDryRun()}
+	keys := common.SortedVariableKeys(variables)
+	for _, key := range keys {
+		var current string
+		err = db.QueryRowContext(ctx, getVariableSql, key).Scan(&current)
+		result := common.NewVariableResult(key, current, variables[key])
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			result.Error = "unknown variable"
+		case err != nil:
+			s.logger.Errorw("failed to get variable", zap.Error(err), zap.String("key", key))
 			return nil, err
 		}
-	case "mysql":
-		if _, err = db.ExecContext(ctx, `LOAD MYSQL VARIABLES TO RUNTIME`); err != nil {
-			s.logger.Errorw("failed to load mysql section variable to runtime variable", zap.Error(err))
+		resp.Results = append(resp.Results, result)
+	}
+
+	if req.GetDryRun() || resp.Failed() {
+		return resp, nil
+	}
+
+	// Variables only take effect once loaded to runtime, so the batch is applied by a single load per section
+	for _, key := range keys {
+		if _, err = db.ExecContext(ctx, updateVariableSql, variables[key], key); err != nil {
+			s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("key", key))
 			return nil, err
 		}
 	}
 
-	s.logger.Info("set variable successfully")
+	for _, section := range sections {
+		upper := strings.ToUpper(section)
+		if _, err = db.ExecContext(ctx, fmt.Sprintf(loadVariablesSql, upper)); err != nil {
+			s.logger.Errorw(fmt.Sprintf("failed to load %s section variable to runtime", section), zap.Error(err))
+			return nil, err
+		}
+
+		if req.GetPersist() {
+			if _, err = db.ExecContext(ctx, fmt.Sprintf(saveVariablesSql, upper)); err != nil {
+				s.logger.Errorw(fmt.Sprintf("failed to save %s section variable to disk", section), zap.Error(err))
+				return nil, err
+			}
+		}
+	}
+	resp.MarkApplied()
+	resp.Persisted = req.GetPersist()
+
+	s.logger.Info("set variables successfully")
+	return resp, nil
+}
+
+// normalizeVariableName validates the section and key of a proxysql variable
+// and returns the lowercased section together with the full variable name.
+func normalizeVariableName(section, key string) (string, string, error) {
+	section = strings.ToLower(strings.TrimSpace(section))
+	key = strings.ToLower(strings.TrimSpace(key))
+
+	switch section {
+	case "admin", "mysql":
+	default:
+		return "", "", fmt.Errorf("unsupported variable section %q", section)
+	}
+
+	if !variableKeyRE.MatchString(key) {
+		return "", "", fmt.Errorf("invalid variable key %q", key)
+	}
+
+	return section, section + "-" + key, nil
+}
+
+// normalizeVariables validates a batch of full variable names, such as
+// mysql-max_connections, and returns the sections it touches.
+func normalizeVariables(variables map[string]string) (map[string]string, []string, error) {
+	if len(variables) == 0 {
+		return nil, nil, fmt.Errorf("variables is required")
+	}
+
+	normalized := make(map[string]string, len(variables))
+	seen := make(map[string]bool)
+	var sections []string
+	for key, value := range variables {
+		section, name, found := strings.Cut(key, "-")
+		if !found {
+			return nil, nil, fmt.Errorf("invalid variable name %q", key)
+		}
+
+		section, name, err := normalizeVariableName(section, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		normalized[name] = value
+
+		if !seen[section] {
+			seen[section] = true
+			sections = append(sections, section)
+		}
+	}
+	sort.Strings(sections)
+
+	return normalized, sections, nil
+}
+
+func (s *service) CreateUser(ctx context.Context, req *CreateUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "proxysql create user", map[string]interface{}{
+		"username":          req.GetUsername(),
+		"user":              req.GetUser(),
+		"default_hostgroup": req.GetDefaultHostgroup(),
+		"default_schema":    req.GetDefaultSchema(),
+		"max_connections":   req.GetMaxConnections(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" || req.GetPassword() == "" {
+		err := fmt.Errorf("user and password are required")
+		s.logger.Errorw("invalid create user request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create proxysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	var defaultSchema interface{}
+	if req.GetDefaultSchema() != "" {
+		defaultSchema = req.GetDefaultSchema()
+	}
+
+	maxConnections := req.GetMaxConnections()
+	if maxConnections <= 0 {
+		maxConnections = defaultMaxConnections
+	}
+
+	if _, err = db.ExecContext(ctx, deleteMysqlUserSql, req.GetUser()); err != nil {
+		s.logger.Errorw("failed to delete mysql user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	if _, err = db.ExecContext(ctx, insertMysqlUserSql, req.GetUser(), req.GetPassword(), req.GetDefaultHostgroup(), defaultSchema, maxConnections); err != nil {
+		s.logger.Errorw("failed to insert mysql user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	if err = s.loadAndSaveMysqlUsers(ctx, db); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("create user successfully")
 	return nil, nil
 }
 
+func (s *service) DropUser(ctx context.Context, req *DropUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "proxysql drop user", map[string]interface{}{
+		"username": req.GetUsername(),
+		"user":     req.GetUser(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" {
+		err := fmt.Errorf("user is required")
+		s.logger.Errorw("invalid drop user request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create proxysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	if _, err = db.ExecContext(ctx, deleteMysqlUserSql, req.GetUser()); err != nil {
+		s.logger.Errorw("failed to delete mysql user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	if err = s.loadAndSaveMysqlUsers(ctx, db); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("drop user successfully")
+	return nil, nil
+}
+
+func (s *service) ListUsers(ctx context.Context, req *ListUsersRequest) (*ListUsersResponse, error) {
+	util.LogRequestSafely(s.logger, "proxysql list users", map[string]interface{}{
+		"username": req.GetUsername(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	// Create proxysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	rows, err := db.QueryContext(ctx, listMysqlUsersSql)
+	if err != nil {
+		s.logger.Errorw("failed to list mysql users", zap.Error(err))
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	resp := &ListUsersResponse{}
+	for rows.Next() {
+		user := &User{}
+		var defaultSchema sql.NullString
+		if err = rows.Scan(&user.User, &user.Active, &user.DefaultHostgroup, &defaultSchema, &user.MaxConnections); err != nil {
+			s.logger.Errorw("failed to scan mysql user", zap.Error(err))
+			return nil, err
+		}
+		user.DefaultSchema = defaultSchema.String
+		resp.Users = append(resp.Users, user)
+	}
+	if err = rows.Err(); err != nil {
+		s.logger.Errorw("failed to list mysql users", zap.Error(err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *service) SyncServers(ctx context.Context, req *SyncServersRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "proxysql sync servers", map[string]interface{}{
+		"username":              req.GetUsername(),
+		"hostgroups":            req.GetHostgroups(),
+		"servers":               req.GetServers(),
+		"replication_hostgroup": req.GetReplicationHostgroup(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	hostgroups, err := validateSyncServersRequest(req)
+	if err != nil {
+		s.logger.Errorw("invalid sync servers request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create proxysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	// The monitor only moves servers between the writer and reader hostgroups
+	// at runtime, copy its placement back before computing the changes
+	replication := req.GetReplicationHostgroup()
+	if replication != nil {
+		if _, err = db.ExecContext(ctx, `SAVE MYSQL SERVERS FROM RUNTIME`); err != nil {
+			s.logger.Errorw("failed to save mysql servers from runtime", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	existing, err := s.queryServers(ctx, db, fmt.Sprintf(listServersSql, "mysql_servers")+fmt.Sprintf(serversInHostgroupsSql, placeholders(len(hostgroups))), int64Args(hostgroups)...)
+	if err != nil {
+		return nil, err
+	}
+
+	deletes, upserts := planServerChanges(existing, req.GetServers(), replication)
+	for _, server := range deletes {
+		if _, err = db.ExecContext(ctx, deleteMysqlServerSql, server.GetHostgroupId(), server.GetHostname(), server.GetPort()); err != nil {
+			s.logger.Errorw("failed to delete mysql server", zap.Error(err), zap.String("hostname", server.GetHostname()))
+			return nil, err
+		}
+	}
+
+	for _, server := range upserts {
+		if _, err = db.ExecContext(ctx, replaceMysqlServerSql, server.GetHostgroupId(), server.GetHostname(), server.GetPort(),
+			server.GetStatus(), server.GetWeight(), server.GetMaxConnections(), server.GetMaxReplicationLag(), server.GetComment()); err != nil {
+			s.logger.Errorw("failed to replace mysql server", zap.Error(err), zap.String("hostname", server.GetHostname()))
+			return nil, err
+		}
+	}
+
+	if replication != nil {
+		checkType := replication.GetCheckType()
+		if checkType == "" {
+			checkType = defaultCheckType
+		}
+		if _, err = db.ExecContext(ctx, replaceReplicationHostgroupSql, replication.GetWriterHostgroup(), replication.GetReaderHostgroup(), checkType, replication.GetComment()); err != nil {
+			s.logger.Errorw("failed to replace mysql replication hostgroup", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if err = s.loadAndSave(ctx, db, "MYSQL SERVERS"); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infow("sync servers successfully", "deleted", len(deletes), "replaced", len(upserts))
+	return nil, nil
+}
+
+func (s *service) ListServers(ctx context.Context, req *ListServersRequest) (*ListServersResponse, error) {
+	util.LogRequestSafely(s.logger, "proxysql list servers", map[string]interface{}{
+		"username": req.GetUsername(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	// Create proxysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	servers, err := s.queryServers(ctx, db, fmt.Sprintf(listServersSql, "runtime_mysql_servers"))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, listReplicationHostgroupsSql)
+	if err != nil {
+		s.logger.Errorw("failed to list mysql replication hostgroups", zap.Error(err))
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	resp := &ListServersResponse{Servers: servers}
+	for rows.Next() {
+		replication := &ReplicationHostgroup{}
+		var comment sql.NullString
+		if err = rows.Scan(&replication.WriterHostgroup, &replication.ReaderHostgroup, &replication.CheckType, &comment); err != nil {
+			s.logger.Errorw("failed to scan mysql replication hostgroup", zap.Error(err))
+			return nil, err
+		}
+		replication.Comment = comment.String
+		resp.ReplicationHostgroups = append(resp.ReplicationHostgroups, replication)
+	}
+	if err = rows.Err(); err != nil {
+		s.logger.Errorw("failed to list mysql replication hostgroups", zap.Error(err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *service) SyncQueryRules(ctx context.Context, req *SyncQueryRulesRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "proxysql sync query rules", map[string]interface{}{
+		"username": req.GetUsername(),
+		"rules":    req.GetRules(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if err := validateQueryRules(req.GetRules()); err != nil {
+		s.logger.Errorw("invalid sync query rules request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create proxysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	// The rules are replaced as a whole in one transaction, so that a failed
+	// insert leaves the previous rules in place, and only loaded to runtime
+	// once committed
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Errorw("failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err = tx.ExecContext(ctx, deleteQueryRulesSql); err != nil {
+		s.logger.Errorw("failed to delete query rules", zap.Error(err))
+		return nil, err
+	}
+
+	for _, rule := range req.GetRules() {
+		if _, err = tx.ExecContext(ctx, insertQueryRuleSql, rule.GetRuleId(), rule.GetActive(), nullIfEmpty(rule.GetUser()), nullIfEmpty(rule.GetSchemaname()),
+			nullIfEmpty(rule.GetMatchDigest()), nullIfEmpty(rule.GetMatchPattern()), rule.GetDestinationHostgroup(), rule.GetApply(), nullIfEmpty(rule.GetComment())); err != nil {
+			s.logger.Errorw("failed to insert query rule", zap.Error(err), zap.Int64("rule_id", rule.GetRuleId()))
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.logger.Errorw("failed to commit query rules", zap.Error(err))
+		return nil, err
+	}
+
+	if err = s.loadAndSave(ctx, db, "MYSQL QUERY RULES"); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("sync query rules successfully")
+	return nil, nil
+}
+
+func (s *service) ListQueryRules(ctx context.Context, req *ListQueryRulesRequest) (*ListQueryRulesResponse, error) {
+	util.LogRequestSafely(s.logger, "proxysql list query rules", map[string]interface{}{
+		"username": req.GetUsername(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	// Create proxysql connection
+	db, err := s.newDBConn(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeDBConn(db)
+
+	rows, err := db.QueryContext(ctx, listQueryRulesSql)
+	if err != nil {
+		s.logger.Errorw("failed to list query rules", zap.Error(err))
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	resp := &ListQueryRulesResponse{}
+	for rows.Next() {
+		rule := &QueryRule{}
+		var user, schemaname, matchDigest, matchPattern, comment sql.NullString
+		var destination sql.NullInt64
+		if err = rows.Scan(&rule.RuleId, &rule.Active, &user, &schemaname, &matchDigest, &matchPattern, &destination, &rule.Apply, &comment); err != nil {
+			s.logger.Errorw("failed to scan query rule", zap.Error(err))
+			return nil, err
+		}
+		rule.User = user.String
+		rule.Schemaname = schemaname.String
+		rule.MatchDigest = matchDigest.String
+		rule.MatchPattern = matchPattern.String
+		rule.DestinationHostgroup = destination.Int64
+		rule.Comment = comment.String
+		resp.Rules = append(resp.Rules, rule)
+	}
+	if err = rows.Err(); err != nil {
+		s.logger.Errorw("failed to list query rules", zap.Error(err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *service) queryServers(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]*Server, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		s.logger.Errorw("failed to list mysql servers", zap.Error(err))
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var servers []*Server
+	for rows.Next() {
+		server := &Server{}
+		var comment sql.NullString
+		if err = rows.Scan(&server.HostgroupId, &server.Hostname, &server.Port, &server.Status, &server.Weight,
+			&server.MaxConnections, &server.MaxReplicationLag, &comment); err != nil {
+			s.logger.Errorw("failed to scan mysql server", zap.Error(err))
+			return nil, err
+		}
+		server.Comment = comment.String
+		servers = append(servers, server)
+	}
+	if err = rows.Err(); err != nil {
+		s.logger.Errorw("failed to list mysql servers", zap.Error(err))
+		return nil, err
+	}
+
+	return servers, nil
+}
+
+// loadAndSave loads the given module, such as MYSQL SERVERS, to runtime and saves it to disk
+func (s *service) loadAndSave(ctx context.Context, db *sql.DB, module string) error {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(`LOAD %s TO RUNTIME`, module)); err != nil {
+		s.logger.Errorw(fmt.Sprintf("failed to load %s to runtime", strings.ToLower(module)), zap.Error(err))
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(`SAVE %s TO DISK`, module)); err != nil {
+		s.logger.Errorw(fmt.Sprintf("failed to save %s to disk", strings.ToLower(module)), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// validateSyncServersRequest checks the servers of the request, fills in their
+// defaults and returns the hostgroups owned by the request.
+func validateSyncServersRequest(req *SyncServersRequest) ([]int64, error) {
+	owned := make(map[int64]bool)
+	for _, hostgroup := range req.GetHostgroups() {
+		owned[hostgroup] = true
+	}
+
+	if replication := req.GetReplicationHostgroup(); replication != nil {
+		if replication.GetWriterHostgroup() == replication.GetReaderHostgroup() {
+			return nil, fmt.Errorf("writer and reader hostgroup must differ")
+		}
+		switch replication.GetCheckType() {
+		case "", "read_only", "innodb_read_only", "super_read_only":
+		default:
+			return nil, fmt.Errorf("unsupported check type %q", replication.GetCheckType())
+		}
+		owned[replication.GetWriterHostgroup()] = true
+		owned[replication.GetReaderHostgroup()] = true
+	}
+
+	if len(owned) == 0 {
+		return nil, fmt.Errorf("hostgroups or replication hostgroup is required")
+	}
+
+	for _, server := range req.GetServers() {
+		if server.GetHostname() == "" {
+			return nil, fmt.Errorf("hostname is required")
+		}
+		if server.GetPort() <= 0 || server.GetPort() > 65535 {
+			return nil, fmt.Errorf("invalid port %d of server %s", server.GetPort(), server.GetHostname())
+		}
+		if !owned[server.GetHostgroupId()] {
+			return nil, fmt.Errorf("hostgroup %d of server %s is not owned by the request", server.GetHostgroupId(), server.GetHostname())
+		}
+
+		switch server.GetStatus() {
+		case "":
+			server.Status = "ONLINE"
+		case "ONLINE", "OFFLINE_SOFT", "OFFLINE_HARD":
+		default:
+			return nil, fmt.Errorf("unsupported status %q of server %s", server.GetStatus(), server.GetHostname())
+		}
+		if server.GetWeight() <= 0 {
+			server.Weight = defaultServerWeight
+		}
+		if server.GetMaxConnections() <= 0 {
+			server.MaxConnections = defaultServerMaxConnections
+		}
+	}
+
+	hostgroups := make([]int64, 0, len(owned))
+	for hostgroup := range owned {
+		hostgroups = append(hostgroups, hostgroup)
+	}
+	sort.Slice(hostgroups, func(i, j int) bool { return hostgroups[i] < hostgroups[j] })
+
+	return hostgroups, nil
+}
+
+// planServerChanges compares the servers of the owned hostgroups with the desired ones.
+// With a replication hostgroup the writer and reader hostgroups are treated as one,
+// so an existing server keeps the hostgroup the monitor placed it in.
+func planServerChanges(existing, desired []*Server, replication *ReplicationHostgroup) ([]*Server, []*Server) {
+	type serverKey struct {
+		hostgroup int64
+		hostname  string
+		port      int64
+	}
+
+	keyOf := func(server *Server) serverKey {
+		hostgroup := server.GetHostgroupId()
+		if replication != nil && hostgroup == replication.GetReaderHostgroup() {
+			hostgroup = replication.GetWriterHostgroup()
+		}
+		return serverKey{hostgroup: hostgroup, hostname: server.GetHostname(), port: server.GetPort()}
+	}
+
+	wanted := make(map[serverKey]*Server, len(desired))
+	for _, server := range desired {
+		wanted[keyOf(server)] = server
+	}
+
+	var deletes, upserts []*Server
+	placed := make(map[serverKey]bool)
+	for _, server := range existing {
+		key := keyOf(server)
+		want, ok := wanted[key]
+		if !ok {
+			deletes = append(deletes, server)
+			continue
+		}
+
+		upsert := proto.Clone(want).(*Server)
+		upsert.HostgroupId = server.GetHostgroupId()
+		upserts = append(upserts, upsert)
+		placed[key] = true
+	}
+
+	for _, server := range desired {
+		if !placed[keyOf(server)] {
+			upserts = append(upserts, server)
+		}
+	}
+
+	return deletes, upserts
+}
+
+// validateQueryRules checks that every rule has a unique positive id
+func validateQueryRules(rules []*QueryRule) error {
+	ids := make(map[int64]bool, len(rules))
+	for _, rule := range rules {
+		if rule.GetRuleId() <= 0 {
+			return fmt.Errorf("rule_id must be positive")
+		}
+		if ids[rule.GetRuleId()] {
+			return fmt.Errorf("duplicate rule_id %d", rule.GetRuleId())
+		}
+		ids[rule.GetRuleId()] = true
+
+		if rule.GetDestinationHostgroup() < 0 {
+			return fmt.Errorf("invalid destination_hostgroup of rule %d", rule.GetRuleId())
+		}
+	}
+
+	return nil
+}
+
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+
+	return value
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func int64Args(values []int64) []interface{} {
+	args := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		args = append(args, value)
+	}
+
+	return args
+}
+
+// loadAndSaveMysqlUsers activates the mysql_users table and persists it
+func (s *service) loadAndSaveMysqlUsers(ctx context.Context, db *sql.DB) error {
+	return s.loadAndSave(ctx, db, "MYSQL USERS")
+}
+
 // newDBConn creates a ProxySQL database connection
 func (s *service) newDBConn(ctx context.Context, username string) (*sql.DB, error) {
 	password, err := util.DecryptPlainTextPassword(username)
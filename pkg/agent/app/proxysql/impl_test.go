@@ -37,7 +37,156 @@ func TestSetVariableFailsWhenProcessDown(t *testing.T) {
 	require.Equal(t, startErr, err)
 }
 
+func TestNormalizeVariableName(t *testing.T) {
+	section, name, err := normalizeVariableName(" MySQL ", "Max_Connections")
+	require.NoError(t, err)
+	require.Equal(t, "mysql", section)
+	require.Equal(t, "mysql-max_connections", name)
+
+	_, _, err = normalizeVariableName("ldap", "auth")
+	require.Error(t, err)
+
+	_, _, err = normalizeVariableName("admin", "x = 1; DROP")
+	require.Error(t, err)
+}
+
+func TestNormalizeVariables(t *testing.T) {
+	variables, sections, err := normalizeVariables(map[string]string{
+		"mysql-max_connections":  "2048",
+		"MYSQL-monitor_enabled":  "false",
+		"admin-refresh_interval": "2000",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"mysql-max_connections":  "2048",
+		"mysql-monitor_enabled":  "false",
+		"admin-refresh_interval": "2000",
+	}, variables)
+	require.Equal(t, []string{"admin", "mysql"}, sections)
+
+	_, _, err = normalizeVariables(nil)
+	require.Error(t, err)
+
+	_, _, err = normalizeVariables(map[string]string{"max_connections": "1"})
+	require.Error(t, err)
+}
+
 func TestCloseDBConnHandlesNil(t *testing.T) {
 	svc := &service{logger: zap.NewNop().Sugar()}
 	require.NotPanics(t, func() { svc.closeDBConn(nil) })
 }
+
+func TestCreateUserRequiresPassword(t *testing.T) {
+	svc := &service{
+		logger: zap.NewNop().Sugar(),
+		slm:    &fakeSlm{},
+	}
+
+	_, err := svc.CreateUser(context.Background(), &CreateUserRequest{
+		Username: "admin",
+		User:     "app",
+	})
+
+	require.Error(t, err)
+}
+
+func TestDropUserFailsWhenProcessDown(t *testing.T) {
+	startErr := errors.New("down")
+	svc := &service{
+		logger: zap.NewNop().Sugar(),
+		slm:    &fakeSlm{startedErr: startErr},
+	}
+
+	_, err := svc.DropUser(context.Background(), &DropUserRequest{
+		Username: "admin",
+		User:     "app",
+	})
+
+	require.Equal(t, startErr, err)
+}
+
+func TestValidateSyncServersRequest(t *testing.T) {
+	req := &SyncServersRequest{
+		Servers: []*Server{{HostgroupId: 20, Hostname: "mysql-0", Port: 3306}},
+		ReplicationHostgroup: &ReplicationHostgroup{
+			WriterHostgroup: 10,
+			ReaderHostgroup: 20,
+		},
+	}
+
+	hostgroups, err := validateSyncServersRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, []int64{10, 20}, hostgroups)
+	require.Equal(t, "ONLINE", req.GetServers()[0].GetStatus())
+	require.Equal(t, int64(defaultServerWeight), req.GetServers()[0].GetWeight())
+	require.Equal(t, int64(defaultServerMaxConnections), req.GetServers()[0].GetMaxConnections())
+
+	_, err = validateSyncServersRequest(&SyncServersRequest{})
+	require.Error(t, err)
+
+	_, err = validateSyncServersRequest(&SyncServersRequest{
+		Hostgroups: []int64{10},
+		Servers:    []*Server{{HostgroupId: 30, Hostname: "mysql-0", Port: 3306}},
+	})
+	require.Error(t, err)
+
+	_, err = validateSyncServersRequest(&SyncServersRequest{
+		Hostgroups: []int64{10},
+		Servers:    []*Server{{HostgroupId: 10, Hostname: "mysql-0", Port: 3306, Status: "SHUNNED"}},
+	})
+	require.Error(t, err)
+}
+
+func TestPlanServerChangesKeepsMonitorPlacement(t *testing.T) {
+	replication := &ReplicationHostgroup{WriterHostgroup: 10, ReaderHostgroup: 20}
+	existing := []*Server{
+		{HostgroupId: 10, Hostname: "mysql-1", Port: 3306, Weight: 1},
+		{HostgroupId: 20, Hostname: "mysql-1", Port: 3306, Weight: 1},
+		{HostgroupId: 20, Hostname: "mysql-2", Port: 3306, Weight: 1},
+	}
+	desired := []*Server{
+		{HostgroupId: 20, Hostname: "mysql-0", Port: 3306, Weight: 5},
+		{HostgroupId: 20, Hostname: "mysql-1", Port: 3306, Weight: 5},
+	}
+
+	deletes, upserts := planServerChanges(existing, desired, replication)
+
+	require.Len(t, deletes, 1)
+	require.Equal(t, "mysql-2", deletes[0].GetHostname())
+
+	require.Len(t, upserts, 3)
+	require.Equal(t, int64(10), upserts[0].GetHostgroupId())
+	require.Equal(t, "mysql-1", upserts[0].GetHostname())
+	require.Equal(t, int64(5), upserts[0].GetWeight())
+	require.Equal(t, int64(20), upserts[1].GetHostgroupId())
+	require.Equal(t, "mysql-1", upserts[1].GetHostname())
+	require.Equal(t, "mysql-0", upserts[2].GetHostname())
+	require.Equal(t, int64(20), upserts[2].GetHostgroupId())
+}
+
+func TestPlanServerChangesWithoutReplication(t *testing.T) {
+	existing := []*Server{{HostgroupId: 10, Hostname: "mysql-0", Port: 3306}}
+	desired := []*Server{{HostgroupId: 20, Hostname: "mysql-0", Port: 3306}}
+
+	deletes, upserts := planServerChanges(existing, desired, nil)
+
+	require.Len(t, deletes, 1)
+	require.Equal(t, int64(10), deletes[0].GetHostgroupId())
+	require.Len(t, upserts, 1)
+	require.Equal(t, int64(20), upserts[0].GetHostgroupId())
+}
+
+func TestValidateQueryRules(t *testing.T) {
+	require.NoError(t, validateQueryRules([]*QueryRule{
+		{RuleId: 1, MatchDigest: "^SELECT .* FOR UPDATE$", DestinationHostgroup: 10},
+		{RuleId: 2, MatchDigest: "^SELECT", DestinationHostgroup: 20},
+	}))
+	require.NoError(t, validateQueryRules(nil))
+	require.Error(t, validateQueryRules([]*QueryRule{{RuleId: 0}}))
+	require.Error(t, validateQueryRules([]*QueryRule{{RuleId: 1}, {RuleId: 1}}))
+}
+
+func TestPlaceholders(t *testing.T) {
+	require.Equal(t, "?, ?, ?", placeholders(3))
+	require.Equal(t, []interface{}{int64(10), int64(20)}, int64Args([]int64{10, 20}))
+}
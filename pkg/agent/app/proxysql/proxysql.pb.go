@@ -28,6 +28,7 @@ type SetVariableRequest struct {
 	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	Section       string                 `protobuf:"bytes,3,opt,name=section,proto3" json:"section,omitempty"`
 	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Persist       bool                   `protobuf:"varint,5,opt,name=persist,proto3" json:"persist,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -90,18 +91,1063 @@ func (x *SetVariableRequest) GetUsername() string {
 	return ""
 }
 
+func (x *SetVariableRequest) GetPersist() bool {
+	if x != nil {
+		return x.Persist
+	}
+	return false
+}
+
+type SetVariablesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// keys are full variable names, e.g. mysql-max_connections
+	Variables     map[string]string `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Username      string            `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Persist       bool              `protobuf:"varint,3,opt,name=persist,proto3" json:"persist,omitempty"`
+	DryRun        bool              `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVariablesRequest) Reset() {
+	*x = SetVariablesRequest{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariablesRequest) ProtoMessage() {}
+
+func (x *SetVariablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariablesRequest.ProtoReflect.Descriptor instead.
+func (*SetVariablesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetVariablesRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *SetVariablesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetVariablesRequest) GetPersist() bool {
+	if x != nil {
+		return x.Persist
+	}
+	return false
+}
+
+func (x *SetVariablesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type CreateUserRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Username         string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User             string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Password         string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	DefaultHostgroup int64                  `protobuf:"varint,4,opt,name=default_hostgroup,json=defaultHostgroup,proto3" json:"default_hostgroup,omitempty"`
+	DefaultSchema    string                 `protobuf:"bytes,5,opt,name=default_schema,json=defaultSchema,proto3" json:"default_schema,omitempty"`
+	MaxConnections   int64                  `protobuf:"varint,6,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetDefaultHostgroup() int64 {
+	if x != nil {
+		return x.DefaultHostgroup
+	}
+	return 0
+}
+
+func (x *CreateUserRequest) GetDefaultSchema() string {
+	if x != nil {
+		return x.DefaultSchema
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetMaxConnections() int64 {
+	if x != nil {
+		return x.MaxConnections
+	}
+	return 0
+}
+
+type DropUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropUserRequest) Reset() {
+	*x = DropUserRequest{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropUserRequest) ProtoMessage() {}
+
+func (x *DropUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropUserRequest.ProtoReflect.Descriptor instead.
+func (*DropUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DropUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *DropUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type User struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	User             string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Active           bool                   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	DefaultHostgroup int64                  `protobuf:"varint,3,opt,name=default_hostgroup,json=defaultHostgroup,proto3" json:"default_hostgroup,omitempty"`
+	DefaultSchema    string                 `protobuf:"bytes,4,opt,name=default_schema,json=defaultSchema,proto3" json:"default_schema,omitempty"`
+	MaxConnections   int64                  `protobuf:"varint,5,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *User) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *User) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *User) GetDefaultHostgroup() int64 {
+	if x != nil {
+		return x.DefaultHostgroup
+	}
+	return 0
+}
+
+func (x *User) GetDefaultSchema() string {
+	if x != nil {
+		return x.DefaultSchema
+	}
+	return ""
+}
+
+func (x *User) GetMaxConnections() int64 {
+	if x != nil {
+		return x.MaxConnections
+	}
+	return 0
+}
+
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListUsersRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type Server struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	HostgroupId int64                  `protobuf:"varint,1,opt,name=hostgroup_id,json=hostgroupId,proto3" json:"hostgroup_id,omitempty"`
+	Hostname    string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Port        int64                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	// ONLINE | OFFLINE_SOFT | OFFLINE_HARD, defaults to ONLINE
+	Status            string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Weight            int64  `protobuf:"varint,5,opt,name=weight,proto3" json:"weight,omitempty"`
+	MaxConnections    int64  `protobuf:"varint,6,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	MaxReplicationLag int64  `protobuf:"varint,7,opt,name=max_replication_lag,json=maxReplicationLag,proto3" json:"max_replication_lag,omitempty"`
+	Comment           string `protobuf:"bytes,8,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Server) Reset() {
+	*x = Server{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server) ProtoMessage() {}
+
+func (x *Server) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server.ProtoReflect.Descriptor instead.
+func (*Server) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Server) GetHostgroupId() int64 {
+	if x != nil {
+		return x.HostgroupId
+	}
+	return 0
+}
+
+func (x *Server) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *Server) GetPort() int64 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *Server) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Server) GetWeight() int64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Server) GetMaxConnections() int64 {
+	if x != nil {
+		return x.MaxConnections
+	}
+	return 0
+}
+
+func (x *Server) GetMaxReplicationLag() int64 {
+	if x != nil {
+		return x.MaxReplicationLag
+	}
+	return 0
+}
+
+func (x *Server) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type ReplicationHostgroup struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	WriterHostgroup int64                  `protobuf:"varint,1,opt,name=writer_hostgroup,json=writerHostgroup,proto3" json:"writer_hostgroup,omitempty"`
+	ReaderHostgroup int64                  `protobuf:"varint,2,opt,name=reader_hostgroup,json=readerHostgroup,proto3" json:"reader_hostgroup,omitempty"`
+	// read_only | innodb_read_only | super_read_only, defaults to read_only
+	CheckType     string `protobuf:"bytes,3,opt,name=check_type,json=checkType,proto3" json:"check_type,omitempty"`
+	Comment       string `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplicationHostgroup) Reset() {
+	*x = ReplicationHostgroup{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicationHostgroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicationHostgroup) ProtoMessage() {}
+
+func (x *ReplicationHostgroup) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicationHostgroup.ProtoReflect.Descriptor instead.
+func (*ReplicationHostgroup) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReplicationHostgroup) GetWriterHostgroup() int64 {
+	if x != nil {
+		return x.WriterHostgroup
+	}
+	return 0
+}
+
+func (x *ReplicationHostgroup) GetReaderHostgroup() int64 {
+	if x != nil {
+		return x.ReaderHostgroup
+	}
+	return 0
+}
+
+func (x *ReplicationHostgroup) GetCheckType() string {
+	if x != nil {
+		return x.CheckType
+	}
+	return ""
+}
+
+func (x *ReplicationHostgroup) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type SyncServersRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// hostgroups owned by the request, servers of other hostgroups are left untouched
+	Hostgroups []int64   `protobuf:"varint,2,rep,packed,name=hostgroups,proto3" json:"hostgroups,omitempty"`
+	Servers    []*Server `protobuf:"bytes,3,rep,name=servers,proto3" json:"servers,omitempty"`
+	// optional, lets the proxysql monitor move servers between the writer and reader hostgroups
+	ReplicationHostgroup *ReplicationHostgroup `protobuf:"bytes,4,opt,name=replication_hostgroup,json=replicationHostgroup,proto3" json:"replication_hostgroup,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *SyncServersRequest) Reset() {
+	*x = SyncServersRequest{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncServersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncServersRequest) ProtoMessage() {}
+
+func (x *SyncServersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncServersRequest.ProtoReflect.Descriptor instead.
+func (*SyncServersRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SyncServersRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SyncServersRequest) GetHostgroups() []int64 {
+	if x != nil {
+		return x.Hostgroups
+	}
+	return nil
+}
+
+func (x *SyncServersRequest) GetServers() []*Server {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+func (x *SyncServersRequest) GetReplicationHostgroup() *ReplicationHostgroup {
+	if x != nil {
+		return x.ReplicationHostgroup
+	}
+	return nil
+}
+
+type ListServersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServersRequest) Reset() {
+	*x = ListServersRequest{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServersRequest) ProtoMessage() {}
+
+func (x *ListServersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServersRequest.ProtoReflect.Descriptor instead.
+func (*ListServersRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListServersRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type ListServersResponse struct {
+	state                 protoimpl.MessageState  `protogen:"open.v1"`
+	Servers               []*Server               `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty"`
+	ReplicationHostgroups []*ReplicationHostgroup `protobuf:"bytes,2,rep,name=replication_hostgroups,json=replicationHostgroups,proto3" json:"replication_hostgroups,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ListServersResponse) Reset() {
+	*x = ListServersResponse{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServersResponse) ProtoMessage() {}
+
+func (x *ListServersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServersResponse.ProtoReflect.Descriptor instead.
+func (*ListServersResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListServersResponse) GetServers() []*Server {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+func (x *ListServersResponse) GetReplicationHostgroups() []*ReplicationHostgroup {
+	if x != nil {
+		return x.ReplicationHostgroups
+	}
+	return nil
+}
+
+type QueryRule struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	RuleId               int64                  `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	Active               bool                   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	User                 string                 `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Schemaname           string                 `protobuf:"bytes,4,opt,name=schemaname,proto3" json:"schemaname,omitempty"`
+	MatchDigest          string                 `protobuf:"bytes,5,opt,name=match_digest,json=matchDigest,proto3" json:"match_digest,omitempty"`
+	MatchPattern         string                 `protobuf:"bytes,6,opt,name=match_pattern,json=matchPattern,proto3" json:"match_pattern,omitempty"`
+	DestinationHostgroup int64                  `protobuf:"varint,7,opt,name=destination_hostgroup,json=destinationHostgroup,proto3" json:"destination_hostgroup,omitempty"`
+	Apply                bool                   `protobuf:"varint,8,opt,name=apply,proto3" json:"apply,omitempty"`
+	Comment              string                 `protobuf:"bytes,9,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *QueryRule) Reset() {
+	*x = QueryRule{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRule) ProtoMessage() {}
+
+func (x *QueryRule) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRule.ProtoReflect.Descriptor instead.
+func (*QueryRule) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *QueryRule) GetRuleId() int64 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+func (x *QueryRule) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *QueryRule) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *QueryRule) GetSchemaname() string {
+	if x != nil {
+		return x.Schemaname
+	}
+	return ""
+}
+
+func (x *QueryRule) GetMatchDigest() string {
+	if x != nil {
+		return x.MatchDigest
+	}
+	return ""
+}
+
+func (x *QueryRule) GetMatchPattern() string {
+	if x != nil {
+		return x.MatchPattern
+	}
+	return ""
+}
+
+func (x *QueryRule) GetDestinationHostgroup() int64 {
+	if x != nil {
+		return x.DestinationHostgroup
+	}
+	return 0
+}
+
+func (x *QueryRule) GetApply() bool {
+	if x != nil {
+		return x.Apply
+	}
+	return false
+}
+
+func (x *QueryRule) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type SyncQueryRulesRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// replaces every query rule
+	Rules         []*QueryRule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncQueryRulesRequest) Reset() {
+	*x = SyncQueryRulesRequest{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncQueryRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncQueryRulesRequest) ProtoMessage() {}
+
+func (x *SyncQueryRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncQueryRulesRequest.ProtoReflect.Descriptor instead.
+func (*SyncQueryRulesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SyncQueryRulesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SyncQueryRulesRequest) GetRules() []*QueryRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type ListQueryRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQueryRulesRequest) Reset() {
+	*x = ListQueryRulesRequest{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQueryRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQueryRulesRequest) ProtoMessage() {}
+
+func (x *ListQueryRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQueryRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListQueryRulesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListQueryRulesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type ListQueryRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*QueryRule           `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQueryRulesResponse) Reset() {
+	*x = ListQueryRulesResponse{}
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQueryRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQueryRulesResponse) ProtoMessage() {}
+
+func (x *ListQueryRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQueryRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListQueryRulesResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListQueryRulesResponse) GetRules() []*QueryRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
 var File_pkg_agent_app_proxysql_pb_proxysql_proto protoreflect.FileDescriptor
 
 const file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDesc = "" +
 	"\n" +
-	"(pkg/agent/app/proxysql/pb/proxysql.proto\x12\bproxysql\x1a$pkg/agent/app/common/pb/common.proto\"r\n" +
+	"(pkg/agent/app/proxysql/pb/proxysql.proto\x12\bproxysql\x1a$pkg/agent/app/common/pb/common.proto\"\x8c\x01\n" +
 	"\x12SetVariableRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value\x12\x18\n" +
 	"\asection\x18\x03 \x01(\tR\asection\x12\x1a\n" +
-	"\busername\x18\x04 \x01(\tR\busername2O\n" +
-	"\x11ProxysqlOperation\x12:\n" +
-	"\vSetVariable\x12\x1c.proxysql.SetVariableRequest\x1a\r.common.EmptyB7Z5github.com/upmio/unit-operator/pkg/agent/app/proxysqlb\x06proto3"
+	"\busername\x18\x04 \x01(\tR\busername\x12\x18\n" +
+	"\apersist\x18\x05 \x01(\bR\apersist\"\xee\x01\n" +
+	"\x13SetVariablesRequest\x12J\n" +
+	"\tvariables\x18\x01 \x03(\v2,.proxysql.SetVariablesRequest.VariablesEntryR\tvariables\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x18\n" +
+	"\apersist\x18\x03 \x01(\bR\apersist\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xdc\x01\n" +
+	"\x11CreateUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12+\n" +
+	"\x11default_hostgroup\x18\x04 \x01(\x03R\x10defaultHostgroup\x12%\n" +
+	"\x0edefault_schema\x18\x05 \x01(\tR\rdefaultSchema\x12'\n" +
+	"\x0fmax_connections\x18\x06 \x01(\x03R\x0emaxConnections\"A\n" +
+	"\x0fDropUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\"\xaf\x01\n" +
+	"\x04User\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x16\n" +
+	"\x06active\x18\x02 \x01(\bR\x06active\x12+\n" +
+	"\x11default_hostgroup\x18\x03 \x01(\x03R\x10defaultHostgroup\x12%\n" +
+	"\x0edefault_schema\x18\x04 \x01(\tR\rdefaultSchema\x12'\n" +
+	"\x0fmax_connections\x18\x05 \x01(\x03R\x0emaxConnections\".\n" +
+	"\x10ListUsersRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"9\n" +
+	"\x11ListUsersResponse\x12$\n" +
+	"\x05users\x18\x01 \x03(\v2\x0e.proxysql.UserR\x05users\"\xfe\x01\n" +
+	"\x06Server\x12!\n" +
+	"\fhostgroup_id\x18\x01 \x01(\x03R\vhostgroupId\x12\x1a\n" +
+	"\bhostname\x18\x02 \x01(\tR\bhostname\x12\x12\n" +
+	"\x04port\x18\x03 \x01(\x03R\x04port\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x16\n" +
+	"\x06weight\x18\x05 \x01(\x03R\x06weight\x12'\n" +
+	"\x0fmax_connections\x18\x06 \x01(\x03R\x0emaxConnections\x12.\n" +
+	"\x13max_replication_lag\x18\a \x01(\x03R\x11maxReplicationLag\x12\x18\n" +
+	"\acomment\x18\b \x01(\tR\acomment\"\xa5\x01\n" +
+	"\x14ReplicationHostgroup\x12)\n" +
+	"\x10writer_hostgroup\x18\x01 \x01(\x03R\x0fwriterHostgroup\x12)\n" +
+	"\x10reader_hostgroup\x18\x02 \x01(\x03R\x0freaderHostgroup\x12\x1d\n" +
+	"\n" +
+	"check_type\x18\x03 \x01(\tR\tcheckType\x12\x18\n" +
+	"\acomment\x18\x04 \x01(\tR\acomment\"\xd1\x01\n" +
+	"\x12SyncServersRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1e\n" +
+	"\n" +
+	"hostgroups\x18\x02 \x03(\x03R\n" +
+	"hostgroups\x12*\n" +
+	"\aservers\x18\x03 \x03(\v2\x10.proxysql.ServerR\aservers\x12S\n" +
+	"\x15replication_hostgroup\x18\x04 \x01(\v2\x1e.proxysql.ReplicationHostgroupR\x14replicationHostgroup\"0\n" +
+	"\x12ListServersRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"\x98\x01\n" +
+	"\x13ListServersResponse\x12*\n" +
+	"\aservers\x18\x01 \x03(\v2\x10.proxysql.ServerR\aservers\x12U\n" +
+	"\x16replication_hostgroups\x18\x02 \x03(\v2\x1e.proxysql.ReplicationHostgroupR\x15replicationHostgroups\"\x9d\x02\n" +
+	"\tQueryRule\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\x03R\x06ruleId\x12\x16\n" +
+	"\x06active\x18\x02 \x01(\bR\x06active\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\x12\x1e\n" +
+	"\n" +
+	"schemaname\x18\x04 \x01(\tR\n" +
+	"schemaname\x12!\n" +
+	"\fmatch_digest\x18\x05 \x01(\tR\vmatchDigest\x12#\n" +
+	"\rmatch_pattern\x18\x06 \x01(\tR\fmatchPattern\x123\n" +
+	"\x15destination_hostgroup\x18\a \x01(\x03R\x14destinationHostgroup\x12\x14\n" +
+	"\x05apply\x18\b \x01(\bR\x05apply\x12\x18\n" +
+	"\acomment\x18\t \x01(\tR\acomment\"^\n" +
+	"\x15SyncQueryRulesRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12)\n" +
+	"\x05rules\x18\x02 \x03(\v2\x13.proxysql.QueryRuleR\x05rules\"3\n" +
+	"\x15ListQueryRulesRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"C\n" +
+	"\x16ListQueryRulesResponse\x12)\n" +
+	"\x05rules\x18\x01 \x03(\v2\x13.proxysql.QueryRuleR\x05rules2\xff\x04\n" +
+	"\x11ProxysqlOperation\x12H\n" +
+	"\vSetVariable\x12\x1c.proxysql.SetVariableRequest\x1a\x1b.common.SetVariableResponse\x12K\n" +
+	"\fSetVariables\x12\x1d.proxysql.SetVariablesRequest\x1a\x1c.common.SetVariablesResponse\x128\n" +
+	"\n" +
+	"CreateUser\x12\x1b.proxysql.CreateUserRequest\x1a\r.common.Empty\x124\n" +
+	"\bDropUser\x12\x19.proxysql.DropUserRequest\x1a\r.common.Empty\x12D\n" +
+	"\tListUsers\x12\x1a.proxysql.ListUsersRequest\x1a\x1b.proxysql.ListUsersResponse\x12:\n" +
+	"\vSyncServers\x12\x1c.proxysql.SyncServersRequest\x1a\r.common.Empty\x12J\n" +
+	"\vListServers\x12\x1c.proxysql.ListServersRequest\x1a\x1d.proxysql.ListServersResponse\x12@\n" +
+	"\x0eSyncQueryRules\x12\x1f.proxysql.SyncQueryRulesRequest\x1a\r.common.Empty\x12S\n" +
+	"\x0eListQueryRules\x12\x1f.proxysql.ListQueryRulesRequest\x1a .proxysql.ListQueryRulesResponseB7Z5github.com/upmio/unit-operator/pkg/agent/app/proxysqlb\x06proto3"
 
 var (
 	file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescOnce sync.Once
@@ -115,19 +1161,61 @@ func file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescGZIP() []byte {
 	return file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDescData
 }
 
-var file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_pkg_agent_app_proxysql_pb_proxysql_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_pkg_agent_app_proxysql_pb_proxysql_proto_goTypes = []any{
-	(*SetVariableRequest)(nil), // 0: proxysql.SetVariableRequest
-	(*common.Empty)(nil),       // 1: common.Empty
+	(*SetVariableRequest)(nil),          // 0: proxysql.SetVariableRequest
+	(*SetVariablesRequest)(nil),         // 1: proxysql.SetVariablesRequest
+	(*CreateUserRequest)(nil),           // 2: proxysql.CreateUserRequest
+	(*DropUserRequest)(nil),             // 3: proxysql.DropUserRequest
+	(*User)(nil),                        // 4: proxysql.User
+	(*ListUsersRequest)(nil),            // 5: proxysql.ListUsersRequest
+	(*ListUsersResponse)(nil),           // 6: proxysql.ListUsersResponse
+	(*Server)(nil),                      // 7: proxysql.Server
+	(*ReplicationHostgroup)(nil),        // 8: proxysql.ReplicationHostgroup
+	(*SyncServersRequest)(nil),          // 9: proxysql.SyncServersRequest
+	(*ListServersRequest)(nil),          // 10: proxysql.ListServersRequest
+	(*ListServersResponse)(nil),         // 11: proxysql.ListServersResponse
+	(*QueryRule)(nil),                   // 12: proxysql.QueryRule
+	(*SyncQueryRulesRequest)(nil),       // 13: proxysql.SyncQueryRulesRequest
+	(*ListQueryRulesRequest)(nil),       // 14: proxysql.ListQueryRulesRequest
+	(*ListQueryRulesResponse)(nil),      // 15: proxysql.ListQueryRulesResponse
+	nil,                                 // 16: proxysql.SetVariablesRequest.VariablesEntry
+	(*common.SetVariableResponse)(nil),  // 17: common.SetVariableResponse
+	(*common.SetVariablesResponse)(nil), // 18: common.SetVariablesResponse
+	(*common.Empty)(nil),                // 19: common.Empty
 }
 var file_pkg_agent_app_proxysql_pb_proxysql_proto_depIdxs = []int32{
-	0, // 0: proxysql.ProxysqlOperation.SetVariable:input_type -> proxysql.SetVariableRequest
-	1, // 1: proxysql.ProxysqlOperation.SetVariable:output_type -> common.Empty
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	16, // 0: proxysql.SetVariablesRequest.variables:type_name -> proxysql.SetVariablesRequest.VariablesEntry
+	4,  // 1: proxysql.ListUsersResponse.users:type_name -> proxysql.User
+	7,  // 2: proxysql.SyncServersRequest.servers:type_name -> proxysql.Server
+	8,  // 3: proxysql.SyncServersRequest.replication_hostgroup:type_name -> proxysql.ReplicationHostgroup
+	7,  // 4: proxysql.ListServersResponse.servers:type_name -> proxysql.Server
+	8,  // 5: proxysql.ListServersResponse.replication_hostgroups:type_name -> proxysql.ReplicationHostgroup
+	12, // 6: proxysql.SyncQueryRulesRequest.rules:type_name -> proxysql.QueryRule
+	12, // 7: proxysql.ListQueryRulesResponse.rules:type_name -> proxysql.QueryRule
+	0,  // 8: proxysql.ProxysqlOperation.SetVariable:input_type -> proxysql.SetVariableRequest
+	1,  // 9: proxysql.ProxysqlOperation.SetVariables:input_type -> proxysql.SetVariablesRequest
+	2,  // 10: proxysql.ProxysqlOperation.CreateUser:input_type -> proxysql.CreateUserRequest
+	3,  // 11: proxysql.ProxysqlOperation.DropUser:input_type -> proxysql.DropUserRequest
+	5,  // 12: proxysql.ProxysqlOperation.ListUsers:input_type -> proxysql.ListUsersRequest
+	9,  // 13: proxysql.ProxysqlOperation.SyncServers:input_type -> proxysql.SyncServersRequest
+	10, // 14: proxysql.ProxysqlOperation.ListServers:input_type -> proxysql.ListServersRequest
+	13, // 15: proxysql.ProxysqlOperation.SyncQueryRules:input_type -> proxysql.SyncQueryRulesRequest
+	14, // 16: proxysql.ProxysqlOperation.ListQueryRules:input_type -> proxysql.ListQueryRulesRequest
+	17, // 17: proxysql.ProxysqlOperation.SetVariable:output_type -> common.SetVariableResponse
+	18, // 18: proxysql.ProxysqlOperation.SetVariables:output_type -> common.SetVariablesResponse
+	19, // 19: proxysql.ProxysqlOperation.CreateUser:output_type -> common.Empty
+	19, // 20: proxysql.ProxysqlOperation.DropUser:output_type -> common.Empty
+	6,  // 21: proxysql.ProxysqlOperation.ListUsers:output_type -> proxysql.ListUsersResponse
+	19, // 22: proxysql.ProxysqlOperation.SyncServers:output_type -> common.Empty
+	11, // 23: proxysql.ProxysqlOperation.ListServers:output_type -> proxysql.ListServersResponse
+	19, // 24: proxysql.ProxysqlOperation.SyncQueryRules:output_type -> common.Empty
+	15, // 25: proxysql.ProxysqlOperation.ListQueryRules:output_type -> proxysql.ListQueryRulesResponse
+	17, // [17:26] is the sub-list for method output_type
+	8,  // [8:17] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_pkg_agent_app_proxysql_pb_proxysql_proto_init() }
@@ -141,7 +1229,7 @@ func file_pkg_agent_app_proxysql_pb_proxysql_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDesc), len(file_pkg_agent_app_proxysql_pb_proxysql_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
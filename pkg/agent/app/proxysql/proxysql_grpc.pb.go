@@ -19,7 +19,15 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ProxysqlOperationClient interface {
-	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error)
+	SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	DropUser(ctx context.Context, in *DropUserRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	SyncServers(ctx context.Context, in *SyncServersRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	ListServers(ctx context.Context, in *ListServersRequest, opts ...grpc.CallOption) (*ListServersResponse, error)
+	SyncQueryRules(ctx context.Context, in *SyncQueryRulesRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	ListQueryRules(ctx context.Context, in *ListQueryRulesRequest, opts ...grpc.CallOption) (*ListQueryRulesResponse, error)
 }
 
 type proxysqlOperationClient struct {
@@ -30,8 +38,8 @@ func NewProxysqlOperationClient(cc grpc.ClientConnInterface) ProxysqlOperationCl
 	return &proxysqlOperationClient{cc}
 }
 
-func (c *proxysqlOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *proxysqlOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error) {
+	out := new(common.SetVariableResponse)
 	err := c.cc.Invoke(ctx, "/proxysql.ProxysqlOperation/SetVariable", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -39,11 +47,91 @@ func (c *proxysqlOperationClient) SetVariable(ctx context.Context, in *SetVariab
 	return out, nil
 }
 
+func (c *proxysqlOperationClient) SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error) {
+	out := new(common.SetVariablesResponse)
+	err := c.cc.Invoke(ctx, "/proxysql.ProxysqlOperation/SetVariables", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proxysqlOperationClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/proxysql.ProxysqlOperation/CreateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proxysqlOperationClient) DropUser(ctx context.Context, in *DropUserRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/proxysql.ProxysqlOperation/DropUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proxysqlOperationClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, "/proxysql.ProxysqlOperation/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proxysqlOperationClient) SyncServers(ctx context.Context, in *SyncServersRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/proxysql.ProxysqlOperation/SyncServers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proxysqlOperationClient) ListServers(ctx context.Context, in *ListServersRequest, opts ...grpc.CallOption) (*ListServersResponse, error) {
+	out := new(ListServersResponse)
+	err := c.cc.Invoke(ctx, "/proxysql.ProxysqlOperation/ListServers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proxysqlOperationClient) SyncQueryRules(ctx context.Context, in *SyncQueryRulesRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/proxysql.ProxysqlOperation/SyncQueryRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proxysqlOperationClient) ListQueryRules(ctx context.Context, in *ListQueryRulesRequest, opts ...grpc.CallOption) (*ListQueryRulesResponse, error) {
+	out := new(ListQueryRulesResponse)
+	err := c.cc.Invoke(ctx, "/proxysql.ProxysqlOperation/ListQueryRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProxysqlOperationServer is the server API for ProxysqlOperation service.
 // All implementations must embed UnimplementedProxysqlOperationServer
 // for forward compatibility
 type ProxysqlOperationServer interface {
-	SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error)
+	SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error)
+	SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error)
+	CreateUser(context.Context, *CreateUserRequest) (*common.Empty, error)
+	DropUser(context.Context, *DropUserRequest) (*common.Empty, error)
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	SyncServers(context.Context, *SyncServersRequest) (*common.Empty, error)
+	ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error)
+	SyncQueryRules(context.Context, *SyncQueryRulesRequest) (*common.Empty, error)
+	ListQueryRules(context.Context, *ListQueryRulesRequest) (*ListQueryRulesResponse, error)
 	mustEmbedUnimplementedProxysqlOperationServer()
 }
 
@@ -51,9 +139,33 @@ type ProxysqlOperationServer interface {
 type UnimplementedProxysqlOperationServer struct {
 }
 
-func (UnimplementedProxysqlOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error) {
+func (UnimplementedProxysqlOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetVariable not implemented")
 }
+func (UnimplementedProxysqlOperationServer) SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVariables not implemented")
+}
+func (UnimplementedProxysqlOperationServer) CreateUser(context.Context, *CreateUserRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedProxysqlOperationServer) DropUser(context.Context, *DropUserRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropUser not implemented")
+}
+func (UnimplementedProxysqlOperationServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedProxysqlOperationServer) SyncServers(context.Context, *SyncServersRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncServers not implemented")
+}
+func (UnimplementedProxysqlOperationServer) ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServers not implemented")
+}
+func (UnimplementedProxysqlOperationServer) SyncQueryRules(context.Context, *SyncQueryRulesRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncQueryRules not implemented")
+}
+func (UnimplementedProxysqlOperationServer) ListQueryRules(context.Context, *ListQueryRulesRequest) (*ListQueryRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQueryRules not implemented")
+}
 func (UnimplementedProxysqlOperationServer) mustEmbedUnimplementedProxysqlOperationServer() {}
 
 // UnsafeProxysqlOperationServer may be embedded to opt out of forward compatibility for this service.
@@ -85,6 +197,150 @@ func _ProxysqlOperation_SetVariable_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProxysqlOperation_SetVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVariablesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProxysqlOperationServer).SetVariables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proxysql.ProxysqlOperation/SetVariables",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProxysqlOperationServer).SetVariables(ctx, req.(*SetVariablesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProxysqlOperation_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProxysqlOperationServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proxysql.ProxysqlOperation/CreateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProxysqlOperationServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProxysqlOperation_DropUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProxysqlOperationServer).DropUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proxysql.ProxysqlOperation/DropUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProxysqlOperationServer).DropUser(ctx, req.(*DropUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProxysqlOperation_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProxysqlOperationServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proxysql.ProxysqlOperation/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProxysqlOperationServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProxysqlOperation_SyncServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncServersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProxysqlOperationServer).SyncServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proxysql.ProxysqlOperation/SyncServers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProxysqlOperationServer).SyncServers(ctx, req.(*SyncServersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProxysqlOperation_ListServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProxysqlOperationServer).ListServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proxysql.ProxysqlOperation/ListServers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProxysqlOperationServer).ListServers(ctx, req.(*ListServersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProxysqlOperation_SyncQueryRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncQueryRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProxysqlOperationServer).SyncQueryRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proxysql.ProxysqlOperation/SyncQueryRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProxysqlOperationServer).SyncQueryRules(ctx, req.(*SyncQueryRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProxysqlOperation_ListQueryRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQueryRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProxysqlOperationServer).ListQueryRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proxysql.ProxysqlOperation/ListQueryRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProxysqlOperationServer).ListQueryRules(ctx, req.(*ListQueryRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ProxysqlOperation_ServiceDesc is the grpc.ServiceDesc for ProxysqlOperation service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -96,6 +352,38 @@ var ProxysqlOperation_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetVariable",
 			Handler:    _ProxysqlOperation_SetVariable_Handler,
 		},
+		{
+			MethodName: "SetVariables",
+			Handler:    _ProxysqlOperation_SetVariables_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _ProxysqlOperation_CreateUser_Handler,
+		},
+		{
+			MethodName: "DropUser",
+			Handler:    _ProxysqlOperation_DropUser_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _ProxysqlOperation_ListUsers_Handler,
+		},
+		{
+			MethodName: "SyncServers",
+			Handler:    _ProxysqlOperation_SyncServers_Handler,
+		},
+		{
+			MethodName: "ListServers",
+			Handler:    _ProxysqlOperation_ListServers_Handler,
+		},
+		{
+			MethodName: "SyncQueryRules",
+			Handler:    _ProxysqlOperation_SyncQueryRules_Handler,
+		},
+		{
+			MethodName: "ListQueryRules",
+			Handler:    _ProxysqlOperation_ListQueryRules_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/agent/app/proxysql/pb/proxysql.proto",
@@ -0,0 +1,477 @@
+package redis
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+)
+
+const (
+	// rdbMagic is the header every RDB file starts with.
+	rdbMagic = "REDIS"
+
+	// aofRestoreDir is the staging directory an AOF archive is extracted into before it is swapped in.
+	aofRestoreDir = ".aof-restore"
+
+	// redisConfName is the name of the redis config file in CONF_DIR.
+	redisConfName = "redis.conf"
+
+	// defaultAOFFileName and defaultAOFDirName are used by redis when redis.conf leaves them unset.
+	defaultAOFFileName = "appendonly.aof"
+	defaultAOFDirName  = "appendonlydir"
+)
+
+// aofLayout describes where an instance keeps its append only files.
+type aofLayout struct {
+	// dirName is the Redis 7 multi-part AOF directory, empty for a single AOF file.
+	dirName string
+	// fileName is the appendfilename, the manifest is named after it.
+	fileName string
+}
+
+// manifestName returns the name of the multi-part AOF manifest.
+func (l *aofLayout) manifestName() string {
+	return l.fileName + ".manifest"
+}
+
+// detectAOFLayout returns the AOF layout of the instance, or nil when appendonly is disabled.
+func detectAOFLayout(ctx context.Context, client *redis.Client) (*aofLayout, error) {
+	appendOnly, err := configGet(ctx, client, "appendonly")
+	if err != nil {
+		return nil, err
+	}
+
+	if appendOnly != "yes" {
+		return nil, nil
+	}
+
+	fileName, err := configGet(ctx, client, "appendfilename")
+	if err != nil {
+		return nil, err
+	}
+	if fileName == "" {
+		return nil, fmt.Errorf("redis appendfilename is empty")
+	}
+
+	// appenddirname only exists since Redis 7, older versions return nothing.
+	dirName, err := configGet(ctx, client, "appenddirname")
+	if err != nil {
+		return nil, err
+	}
+
+	return &aofLayout{dirName: dirName, fileName: fileName}, nil
+}
+
+// readAOFLayout returns the AOF layout configured in the redis config file, or nil when appendonly is disabled.
+// It is used while redis is stopped, when the layout cannot be queried with CONFIG GET.
+// dirName stays empty unless appenddirname is set explicitly.
+func readAOFLayout(confFile string) (*aofLayout, error) {
+	content, err := os.ReadFile(confFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redis config file: %w", err)
+	}
+
+	values := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		// A later directive overrides an earlier one, as it does in redis.
+		values[strings.ToLower(fields[0])] = strings.Trim(fields[1], `"'`)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(values["appendonly"]) != "yes" {
+		return nil, nil
+	}
+
+	fileName := values["appendfilename"]
+	if fileName == "" {
+		fileName = defaultAOFFileName
+	}
+
+	return &aofLayout{dirName: values["appenddirname"], fileName: fileName}, nil
+}
+
+// checkAOFArchive verifies that the extracted archive entries match the AOF layout of the target.
+func checkAOFArchive(stagingDir string, entries []string, layout *aofLayout) error {
+	if layout == nil {
+		return fmt.Errorf("target has appendonly disabled, an aof archive can not be restored")
+	}
+
+	if len(entries) != 1 {
+		return fmt.Errorf("aof archive must contain a single aof file or directory, got %v", entries)
+	}
+
+	entry := entries[0]
+	info, err := os.Stat(filepath.Join(stagingDir, entry))
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		if layout.dirName != "" {
+			return fmt.Errorf("target uses the multi-part aof directory %s, archive contains the single aof file %s", layout.dirName, entry)
+		}
+		if entry != layout.fileName {
+			return fmt.Errorf("target appendfilename is %s, archive contains the aof file %s", layout.fileName, entry)
+		}
+
+		return nil
+	}
+
+	dirName := layout.dirName
+	if dirName == "" {
+		dirName = defaultAOFDirName
+	}
+	if entry != dirName {
+		return fmt.Errorf("target appenddirname is %s, archive contains the aof directory %s", dirName, entry)
+	}
+
+	if _, err := os.Stat(filepath.Join(stagingDir, entry, layout.manifestName())); err != nil {
+		return fmt.Errorf("aof archive does not contain the manifest %s of the target: %w", layout.manifestName(), err)
+	}
+
+	return nil
+}
+
+func configGet(ctx context.Context, client *redis.Client, key string) (string, error) {
+	values, err := client.ConfigGet(ctx, key).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to CONFIG GET %s: %w", key, err)
+	}
+
+	return values[key], nil
+}
+
+// ensureAOFRewrite triggers BGREWRITEAOF and waits until the rewrite, and any rewrite already running, finishes.
+func ensureAOFRewrite(ctx context.Context, client *redis.Client, timeout time.Duration) error {
+	if err := waitForAOFRewrite(ctx, client, timeout); err != nil {
+		return err
+	}
+
+	if err := client.BgRewriteAOF(ctx).Err(); err != nil {
+		if !strings.Contains(err.Error(), "already in progress") {
+			return fmt.Errorf("failed to trigger redis BGREWRITEAOF: %w", err)
+		}
+	}
+
+	if err := waitForAOFRewrite(ctx, client, timeout); err != nil {
+		return err
+	}
+
+	info, err := client.Info(ctx, "persistence").Result()
+	if err != nil {
+		return fmt.Errorf("failed to query redis persistence info: %w", err)
+	}
+
+	if status := common.ParseRedisInfo(info)["aof_last_bgrewrite_status"]; status != "ok" {
+		return fmt.Errorf("redis aof rewrite failed with status %q", status)
+	}
+
+	return nil
+}
+
+// waitForAOFRewrite waits until no AOF rewrite is running or scheduled.
+func waitForAOFRewrite(ctx context.Context, client *redis.Client, timeout time.Duration) error {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		info, err := client.Info(ctx, "persistence").Result()
+		if err == nil {
+			values := common.ParseRedisInfo(info)
+			if values["aof_rewrite_in_progress"] == "0" && values["aof_rewrite_scheduled"] == "0" {
+				return nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for redis aof rewrite to finish")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseAOFManifest returns the files referenced by a multi-part AOF manifest.
+func parseAOFManifest(manifest []byte) ([]string, error) {
+	var files []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(manifest))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i+1 < len(fields); i += 2 {
+			if fields[i] != "file" {
+				continue
+			}
+
+			name := strings.Trim(fields[i+1], `"`)
+			if name == "" || name != filepath.Base(name) {
+				return nil, fmt.Errorf("invalid aof manifest file name %q", fields[i+1])
+			}
+
+			files = append(files, name)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("aof manifest references no files")
+	}
+
+	return files, nil
+}
+
+// writeAOFArchive writes the AOF files of the layout as a tar stream, paths are relative to dataDir.
+// The manifest is read once up front, so that the archive always matches the files it references.
+func writeAOFArchive(w io.Writer, dataDir string, layout *aofLayout) error {
+	tw := tar.NewWriter(w)
+
+	if layout.dirName == "" {
+		if err := addTarFile(tw, dataDir, layout.fileName); err != nil {
+			return err
+		}
+
+		return tw.Close()
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(dataDir, layout.dirName, layout.manifestName()))
+	if err != nil {
+		return fmt.Errorf("failed to read aof manifest: %w", err)
+	}
+
+	files, err := parseAOFManifest(manifest)
+	if err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     layout.dirName + "/",
+		Mode:     0755,
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := addTarFile(tw, dataDir, path.Join(layout.dirName, file)); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     path.Join(layout.dirName, layout.manifestName()),
+		Mode:     0644,
+		Size:     int64(len(manifest)),
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	if _, err := tw.Write(manifest); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// addTarFile adds the file as it was when opened, an incr file still being appended to is cut at that size.
+func addTarFile(tw *tar.Writer, dataDir, name string) error {
+	f, err := os.Open(filepath.Join(dataDir, filepath.FromSlash(name)))
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	if _, err := io.CopyN(tw, f, hdr.Size); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// isRDBStream reports whether the backup object is a plain RDB file rather than an AOF archive.
+func isRDBStream(r *bufio.Reader) (bool, error) {
+	magic, err := r.Peek(len(rdbMagic))
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	return string(magic) == rdbMagic, nil
+}
+
+// restoreAOFArchive extracts an AOF archive into dataDir and returns the restored top level paths.
+// The archive is staged first, existing files are only renamed to .bak once it extracted completely
+// and matches the AOF layout of the target.
+func restoreAOFArchive(r io.Reader, dataDir string, layout *aofLayout) ([]string, error) {
+	stagingDir := filepath.Join(dataDir, aofRestoreDir)
+	if err := os.RemoveAll(stagingDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(stagingDir) }()
+
+	var entries []string
+	seen := make(map[string]bool)
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read aof archive: %w", err)
+		}
+
+		targetPath, err := safeTarPath(stagingDir, hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		top := strings.SplitN(filepath.ToSlash(filepath.Clean(hdr.Name)), "/", 2)[0]
+		if !seen[top] {
+			seen[top] = true
+			entries = append(entries, top)
+		}
+
+		if err := extractTarEntry(tr, hdr, targetPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("aof archive is empty")
+	}
+
+	if err := checkAOFArchive(stagingDir, entries, layout); err != nil {
+		return nil, err
+	}
+
+	restored := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		target := filepath.Join(dataDir, entry)
+		if err := util.MoveAsideWithBak(target); err != nil {
+			return nil, err
+		}
+
+		if err := os.Rename(filepath.Join(stagingDir, entry), target); err != nil {
+			return nil, err
+		}
+
+		restored = append(restored, target)
+	}
+
+	return restored, nil
+}
+
+func safeTarPath(targetDir, tarName string) (string, error) {
+	cleanName := filepath.Clean(tarName)
+
+	if cleanName == "." || filepath.IsAbs(cleanName) || strings.HasPrefix(cleanName, "..") {
+		return "", fmt.Errorf("illegal tar entry path: %s", tarName)
+	}
+
+	targetPath := filepath.Join(targetDir, cleanName)
+
+	base := filepath.Clean(targetDir) + string(os.PathSeparator)
+	if !strings.HasPrefix(targetPath, base) {
+		return "", fmt.Errorf("tar path escapes target dir: %s", tarName)
+	}
+
+	return targetPath, nil
+}
+
+func extractTarEntry(tr *tar.Reader, hdr *tar.Header, targetPath string) error {
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(targetPath, 0755)
+
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(f, tr); err != nil {
+			_ = f.Close()
+			return err
+		}
+
+		return f.Close()
+
+	default:
+		return fmt.Errorf("unsupported aof archive entry %s", hdr.Name)
+	}
+}
+
+// chownRecursive changes the ownership of root and everything below it.
+func chownRecursive(root string, uid, gid int) error {
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to access path %s, %v", path, err)
+		}
+
+		if err := os.Chown(path, uid, gid); err != nil {
+			return fmt.Errorf("failed to chown %s to %d:%d, %v", path, uid, gid, err)
+		}
+
+		return nil
+	})
+}
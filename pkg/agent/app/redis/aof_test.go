@@ -0,0 +1,184 @@
+package redis
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `file appendonly.aof.2.base.rdb seq 2 type b
+file appendonly.aof.3.incr.aof seq 3 type i
+file appendonly.aof.4.incr.aof seq 4 type i
+`
+
+func TestParseAOFManifest(t *testing.T) {
+	files, err := parseAOFManifest([]byte(testManifest))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"appendonly.aof.2.base.rdb",
+		"appendonly.aof.3.incr.aof",
+		"appendonly.aof.4.incr.aof",
+	}, files)
+}
+
+func TestParseAOFManifestRejectsInvalidInput(t *testing.T) {
+	_, err := parseAOFManifest([]byte("file ../dump.rdb seq 1 type b\n"))
+	require.Error(t, err)
+
+	_, err = parseAOFManifest([]byte("\n"))
+	require.Error(t, err)
+}
+
+func writeTestAOFDir(t *testing.T, dataDir string) {
+	t.Helper()
+
+	aofDir := filepath.Join(dataDir, "appendonlydir")
+	require.NoError(t, os.MkdirAll(aofDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(aofDir, "appendonly.aof.manifest"), []byte(testManifest), 0o644))
+	for _, name := range []string{"appendonly.aof.2.base.rdb", "appendonly.aof.3.incr.aof", "appendonly.aof.4.incr.aof"} {
+		require.NoError(t, os.WriteFile(filepath.Join(aofDir, name), []byte(name), 0o644))
+	}
+	// history files not referenced by the manifest are left out
+	require.NoError(t, os.WriteFile(filepath.Join(aofDir, "appendonly.aof.1.base.rdb"), []byte("old"), 0o644))
+}
+
+func TestAOFArchiveRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	writeTestAOFDir(t, src)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeAOFArchive(&buf, src, &aofLayout{dirName: "appendonlydir", fileName: "appendonly.aof"}))
+
+	dst := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dst, "appendonlydir"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dst, "appendonlydir", "stale.aof"), []byte("stale"), 0o644))
+
+	restored, err := restoreAOFArchive(&buf, dst, &aofLayout{fileName: "appendonly.aof"})
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dst, "appendonlydir")}, restored)
+
+	manifest, err := os.ReadFile(filepath.Join(dst, "appendonlydir", "appendonly.aof.manifest"))
+	require.NoError(t, err)
+	require.Equal(t, testManifest, string(manifest))
+
+	data, err := os.ReadFile(filepath.Join(dst, "appendonlydir", "appendonly.aof.4.incr.aof"))
+	require.NoError(t, err)
+	require.Equal(t, "appendonly.aof.4.incr.aof", string(data))
+
+	require.NoFileExists(t, filepath.Join(dst, "appendonlydir", "appendonly.aof.1.base.rdb"))
+	require.NoFileExists(t, filepath.Join(dst, "appendonlydir", "stale.aof"))
+	require.FileExists(t, filepath.Join(dst, "appendonlydir.bak", "stale.aof"))
+	require.NoDirExists(t, filepath.Join(dst, aofRestoreDir))
+}
+
+func TestAOFArchiveSingleFile(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "appendonly.aof"), []byte("*1\r\n$4\r\nPING\r\n"), 0o644))
+
+	var buf bytes.Buffer
+	require.NoError(t, writeAOFArchive(&buf, src, &aofLayout{fileName: "appendonly.aof"}))
+
+	dst := t.TempDir()
+	restored, err := restoreAOFArchive(&buf, dst, &aofLayout{fileName: "appendonly.aof"})
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dst, "appendonly.aof")}, restored)
+	require.FileExists(t, filepath.Join(dst, "appendonly.aof"))
+}
+
+func TestAOFArchiveLayoutMismatch(t *testing.T) {
+	src := t.TempDir()
+	writeTestAOFDir(t, src)
+
+	var multiPart bytes.Buffer
+	require.NoError(t, writeAOFArchive(&multiPart, src, &aofLayout{dirName: "appendonlydir", fileName: "appendonly.aof"}))
+
+	require.NoError(t, os.WriteFile(filepath.Join(src, "appendonly.aof"), []byte("*1\r\n$4\r\nPING\r\n"), 0o644))
+	var single bytes.Buffer
+	require.NoError(t, writeAOFArchive(&single, src, &aofLayout{fileName: "appendonly.aof"}))
+
+	tests := []struct {
+		name    string
+		archive []byte
+		layout  *aofLayout
+	}{
+		{name: "appendonly disabled", archive: single.Bytes(), layout: nil},
+		{name: "single file into multi-part", archive: single.Bytes(), layout: &aofLayout{dirName: "appendonlydir", fileName: "appendonly.aof"}},
+		{name: "file name mismatch", archive: single.Bytes(), layout: &aofLayout{fileName: "cache.aof"}},
+		{name: "directory name mismatch", archive: multiPart.Bytes(), layout: &aofLayout{dirName: "aofdir", fileName: "appendonly.aof"}},
+		{name: "manifest name mismatch", archive: multiPart.Bytes(), layout: &aofLayout{dirName: "appendonlydir", fileName: "cache.aof"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dst, "appendonly.aof"), []byte("current"), 0o644))
+
+			_, err := restoreAOFArchive(bytes.NewReader(tt.archive), dst, tt.layout)
+			require.Error(t, err)
+
+			data, err := os.ReadFile(filepath.Join(dst, "appendonly.aof"))
+			require.NoError(t, err)
+			require.Equal(t, "current", string(data))
+			require.NoFileExists(t, filepath.Join(dst, "appendonly.aof.bak"))
+		})
+	}
+}
+
+func TestReadAOFLayout(t *testing.T) {
+	dir := t.TempDir()
+	confFile := filepath.Join(dir, redisConfName)
+
+	require.NoError(t, os.WriteFile(confFile, []byte("port 6379\n# appendonly yes\nappendonly no\n"), 0o644))
+	layout, err := readAOFLayout(confFile)
+	require.NoError(t, err)
+	require.Nil(t, layout)
+
+	require.NoError(t, os.WriteFile(confFile, []byte("appendonly no\nappendonly yes\n"), 0o644))
+	layout, err = readAOFLayout(confFile)
+	require.NoError(t, err)
+	require.Equal(t, &aofLayout{fileName: defaultAOFFileName}, layout)
+
+	require.NoError(t, os.WriteFile(confFile, []byte("appendonly yes\nappendfilename \"cache.aof\"\nappenddirname \"aofdir\"\n"), 0o644))
+	layout, err = readAOFLayout(confFile)
+	require.NoError(t, err)
+	require.Equal(t, &aofLayout{dirName: "aofdir", fileName: "cache.aof"}, layout)
+
+	_, err = readAOFLayout(filepath.Join(dir, "missing.conf"))
+	require.Error(t, err)
+}
+
+func TestIsRDBStream(t *testing.T) {
+	isRDB, err := isRDBStream(bufio.NewReader(strings.NewReader("REDIS0011")))
+	require.NoError(t, err)
+	require.True(t, isRDB)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeAOFArchive(&buf, func() string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "appendonly.aof"), []byte("data"), 0o644))
+		return dir
+	}(), &aofLayout{fileName: "appendonly.aof"}))
+
+	isRDB, err = isRDBStream(bufio.NewReader(&buf))
+	require.NoError(t, err)
+	require.False(t, isRDB)
+}
+
+func TestSafeTarPath(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := safeTarPath(dir, "../escape")
+	require.Error(t, err)
+
+	_, err = safeTarPath(dir, "/etc/passwd")
+	require.Error(t, err)
+
+	path, err := safeTarPath(dir, "appendonlydir/appendonly.aof.manifest")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "appendonlydir", "appendonly.aof.manifest"), path)
+}
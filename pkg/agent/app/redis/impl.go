@@ -1,14 +1,18 @@
 package redis
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/redis/go-redis/v9"
 	"github.com/upmio/unit-operator/pkg/agent/app"
 	"github.com/upmio/unit-operator/pkg/agent/app/common"
@@ -22,6 +26,8 @@ import (
 var (
 	// service instance
 	svr = &service{}
+
+	configNameRE = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
 )
 
 type service struct {
@@ -31,7 +37,8 @@ type service struct {
 
 	slm slm.ServiceLifecycleServer
 
-	dataDir string
+	dataDir  string
+	confFile string
 }
 
 func (s *service) Config() error {
@@ -45,7 +52,13 @@ func (s *service) Config() error {
 		return err
 	}
 
+	confDir, err := util.IsEnvVarSet(vars.ConfigDirEnvKey)
+	if err != nil {
+		return err
+	}
+
 	s.dataDir = dataDir
+	s.confFile = filepath.Join(confDir, redisConfName)
 
 	return nil
 }
@@ -58,11 +71,16 @@ func (s *service) Registry(server *grpc.Server) {
 	RegisterRedisOperationServer(server, svr)
 }
 
-func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.Empty, error) {
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterRedisOperationHandler(ctx, mux, conn)
+}
+
+func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.SetVariableResponse, error) {
 	util.LogRequestSafely(s.logger, "redis set variable", map[string]interface{}{
 		"key":      req.GetKey(),
 		"value":    req.GetValue(),
 		"username": req.GetUsername(),
+		"persist":  req.GetPersist(),
 	})
 
 	// Check process is started
@@ -71,6 +89,12 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 		return nil, err
 	}
 
+	key, err := normalizeConfigName(req.GetKey())
+	if err != nil {
+		s.logger.Errorw("invalid set variable request", zap.Error(err))
+		return nil, err
+	}
+
 	// Create connection
 	rdb, err := s.newRedisClient(ctx, req.GetUsername())
 	if err != nil {
@@ -78,18 +102,117 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 	}
 	defer s.closeRedisClient(rdb)
 
+	// Check the parameter against the server configuration
+	current, err := rdb.ConfigGet(ctx, key).Result()
+	if err != nil {
+		s.logger.Errorw("failed to get config", zap.Error(err), zap.String("key", key))
+		return nil, err
+	}
+	previous, ok := current[key]
+	if !ok {
+		err = fmt.Errorf("unknown config parameter %q", key)
+		s.logger.Errorw("invalid set variable request", zap.Error(err))
+		return nil, err
+	}
+
 	// Execute config set
-	err = rdb.ConfigSet(ctx, req.GetKey(), req.GetValue()).Err()
+	err = rdb.ConfigSet(ctx, key, req.GetValue()).Err()
 	if err != nil {
-		s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("key", req.GetKey()), zap.String("value", req.GetValue()))
+		s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("key", key), zap.String("value", req.GetValue()))
 		return nil, err
 	}
 
-	s.logger.Info("set variable successfully")
-	return nil, nil
+	// Read the value back, redis normalizes units and enums
+	if current, err = rdb.ConfigGet(ctx, key).Result(); err != nil {
+		s.logger.Errorw("failed to get config", zap.Error(err), zap.String("key", key))
+		return nil, err
+	}
+	resp := common.NewSetVariableResponse(previous, current[key])
+
+	if req.GetPersist() {
+		if err = rdb.ConfigRewrite(ctx).Err(); err != nil {
+			s.logger.Errorw("failed to rewrite config", zap.Error(err))
+			return nil, err
+		}
+		resp.Persisted = true
+	}
+
+	s.logger.Infow("set variable successfully", "changed", resp.GetChanged())
+	return resp, nil
+}
+
+func (s *service) SetVariables(ctx context.Context, req *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	util.LogRequestSafely(s.logger, "redis set variables", map[string]interface{}{
+		"variables": req.GetVariables(),
+		"username":  req.GetUsername(),
+		"persist":   req.GetPersist(),
+		"dry_run":   req.GetDryRun(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	variables, err := normalizeConfigs(req.GetVariables())
+	if err != nil {
+		s.logger.Errorw("invalid set variables request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create connection
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	// Read the current values, unknown parameters fail the whole batch
+	resp := &common.SetVariablesResponse{DryRun: req.GetDryRun()}
+	keys := common.SortedVariableKeys(variables)
+	for _, key := range keys {
+		current, err := rdb.ConfigGet(ctx, key).Result()
+		if err != nil {
+			s.logger.Errorw("failed to get config", zap.Error(err), zap.String("key", key))
+			return nil, err
+		}
+		value, ok := current[key]
+		result := common.NewVariableResult(key, value, variables[key])
+		if !ok {
+			result.Error = "unknown config parameter"
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if req.GetDryRun() || resp.Failed() {
+		return resp, nil
+	}
+
+	// CONFIG SET with several parameters is atomic, either all of them are applied or none
+	args := []interface{}{"CONFIG", "SET"}
+	for _, key := range keys {
+		args = append(args, key, variables[key])
+	}
+	if err = rdb.Do(ctx, args...).Err(); err != nil {
+		s.logger.Errorw("failed to set variables", zap.Error(err))
+		return nil, err
+	}
+	resp.MarkApplied()
+
+	if req.GetPersist() {
+		if err = rdb.ConfigRewrite(ctx).Err(); err != nil {
+			s.logger.Errorw("failed to rewrite config", zap.Error(err))
+			return nil, err
+		}
+		resp.Persisted = true
+	}
+
+	s.logger.Info("set variables successfully")
+	return resp, nil
 }
 
-func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty, error) {
+func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.BackupResponse, error) {
 	util.LogRequestSafely(s.logger, "redis backup", map[string]interface{}{
 		"backup_file": req.GetBackupFile(),
 		"username":    req.GetUsername(),
@@ -107,6 +230,8 @@ func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty
 		return nil, err
 	}
 
+	start := time.Now()
+
 	// Create connection
 	rdb, err := s.newRedisClient(ctx, req.GetUsername())
 	if err != nil {
@@ -114,6 +239,47 @@ func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty
 	}
 	defer s.closeRedisClient(rdb)
 
+	storageFactory, err := req.GetObjectStorage().GenerateFactory()
+	if err != nil {
+		s.logger.Errorw("failed to generate storage factory", zap.Error(err))
+		return nil, err
+	}
+
+	layout, err := detectAOFLayout(ctx, rdb)
+	if err != nil {
+		s.logger.Errorw("failed to detect persistence mode", zap.Error(err))
+		return nil, err
+	}
+
+	// AOF instances are archived as the manifest plus its base and incr files
+	if layout != nil {
+		if err := ensureAOFRewrite(ctx, rdb, 2*time.Minute); err != nil {
+			s.logger.Errorw("failed to rewrite aof", zap.Error(err))
+			return nil, err
+		}
+
+		pr, pw := io.Pipe()
+		go func() {
+			_ = pw.CloseWithError(writeAOFArchive(pw, s.dataDir, layout))
+		}()
+
+		archive := &common.CountingReader{Reader: pr}
+		if err := storageFactory.PutObject(ctx, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), archive); err != nil {
+			_ = pr.CloseWithError(err)
+			s.logger.Errorw("failed to put aof backup archive", zap.Error(err))
+			return nil, err
+		}
+
+		s.logger.Infow("backup redis aof files successfully", "size_bytes", archive.N)
+
+		return &common.BackupResponse{
+			Bucket:     req.GetObjectStorage().GetBucket(),
+			Object:     req.GetBackupFile(),
+			SizeBytes:  archive.N,
+			DurationMs: time.Since(start).Milliseconds(),
+		}, nil
+	}
+
 	if err := ensureFreshRDBSnapshot(ctx, rdb, 2*time.Minute); err != nil {
 		s.logger.Errorw("failed to ensure fresh rdb snapshot", zap.Error(err))
 		return nil, err
@@ -126,9 +292,9 @@ func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty
 		return nil, err
 	}
 
-	storageFactory, err := req.GetObjectStorage().GenerateFactory()
+	info, err := os.Stat(rdbPath)
 	if err != nil {
-		s.logger.Errorw("failed to generate storage factory", zap.Error(err))
+		s.logger.Errorw("failed to stat rdb file", zap.Error(err))
 		return nil, err
 	}
 
@@ -137,12 +303,17 @@ func (s *service) Backup(ctx context.Context, req *BackupRequest) (*common.Empty
 		return nil, err
 	}
 
-	s.logger.Info("backup redis rdb file successfully")
+	s.logger.Infow("backup redis rdb file successfully", "size_bytes", info.Size())
 
-	return nil, nil
+	return &common.BackupResponse{
+		Bucket:     req.GetObjectStorage().GetBucket(),
+		Object:     req.GetBackupFile(),
+		SizeBytes:  info.Size(),
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
 }
 
-func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Empty, error) {
+func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.RestoreResponse, error) {
 	util.LogRequestSafely(s.logger, "redis restore", map[string]interface{}{
 		"backup_file": req.GetBackupFile(),
 		"bucket":      req.GetObjectStorage().GetBucket(),
@@ -159,16 +330,71 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 		return nil, err
 	}
 
-	// Discover RDB file path
-	rdbPath, err := discoverRDBPath(s.dataDir)
+	start := time.Now()
+
+	storageFactory, err := req.GetObjectStorage().GenerateFactory()
 	if err != nil {
-		s.logger.Errorw("failed to discover rdb path", zap.Error(err))
+		s.logger.Errorw("failed to generate storage factory", zap.Error(err))
 		return nil, err
 	}
 
-	storageFactory, err := req.GetObjectStorage().GenerateFactory()
+	obj, err := storageFactory.GetObject(ctx, req.GetObjectStorage().GetBucket(), req.GetBackupFile())
 	if err != nil {
-		s.logger.Errorw("failed to generate storage factory", zap.Error(err))
+		s.logger.Errorw("failed to get backup file", zap.Error(err))
+		return nil, err
+	}
+	defer func() { _ = obj.Close() }()
+
+	counter := &common.CountingReader{Reader: obj}
+	reader := bufio.NewReader(counter)
+	isRDB, err := isRDBStream(reader)
+	if err != nil {
+		s.logger.Errorw("failed to read backup file", zap.Error(err))
+		return nil, err
+	}
+
+	// The backup type has to match the persistence of the target, redis ignores dump.rdb when appendonly is enabled
+	layout, err := readAOFLayout(s.confFile)
+	if err != nil {
+		s.logger.Errorw("failed to read aof layout", zap.Error(err))
+		return nil, err
+	}
+
+	if isRDB && layout != nil {
+		err := fmt.Errorf("target has appendonly enabled, an rdb backup can not be restored")
+		s.logger.Errorw("backup does not match the target persistence", zap.Error(err))
+		return nil, err
+	}
+
+	// Anything but an RDB file is an AOF archive
+	if !isRDB {
+		restored, err := restoreAOFArchive(reader, s.dataDir, layout)
+		if err != nil {
+			s.logger.Errorw("failed to restore aof backup archive", zap.Error(err))
+			return nil, err
+		}
+
+		for _, path := range restored {
+			if err := chownRecursive(path, 1001, 1001); err != nil {
+				s.logger.Errorw("failed to chown aof files", zap.Error(err))
+				return nil, err
+			}
+		}
+
+		s.logger.Info("restore redis aof files successfully")
+
+		return &common.RestoreResponse{
+			Bucket:     req.GetObjectStorage().GetBucket(),
+			Object:     req.GetBackupFile(),
+			SizeBytes:  counter.N,
+			DurationMs: time.Since(start).Milliseconds(),
+		}, nil
+	}
+
+	// Discover RDB file path
+	rdbPath, err := discoverRDBPath(s.dataDir)
+	if err != nil {
+		s.logger.Errorw("failed to discover rdb path", zap.Error(err))
 		return nil, err
 	}
 
@@ -178,8 +404,8 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 		return nil, err
 	}
 
-	if err := storageFactory.GetFile(ctx, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), rdbPath); err != nil {
-		s.logger.Errorw("failed to get backup file", zap.Error(err))
+	if err := writeFile(rdbPath, reader); err != nil {
+		s.logger.Errorw("failed to write rdb file", zap.Error(err))
 		return nil, err
 	}
 
@@ -195,9 +421,267 @@ func (s *service) Restore(ctx context.Context, req *RestoreRequest) (*common.Emp
 
 	s.logger.Info("restore redis rdb file successfully")
 
+	return &common.RestoreResponse{
+		Bucket:     req.GetObjectStorage().GetBucket(),
+		Object:     req.GetBackupFile(),
+		SizeBytes:  counter.N,
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
+}
+
+func (s *service) CreateUser(ctx context.Context, req *CreateUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis create user", map[string]interface{}{
+		"username": req.GetUsername(),
+		"user":     req.GetUser(),
+		"rules":    req.GetRules(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	args, err := buildACLSetUserArgs(req.GetUser(), req.GetPassword(), req.GetRules())
+	if err != nil {
+		s.logger.Errorw("invalid create user request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create connection
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	if err = rdb.Do(ctx, args...).Err(); err != nil {
+		s.logger.Errorw("failed to set acl user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	if err = persistACL(ctx, rdb); err != nil {
+		s.logger.Errorw("failed to persist acl", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("create user successfully")
+	return nil, nil
+}
+
+func (s *service) DropUser(ctx context.Context, req *DropUserRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis drop user", map[string]interface{}{
+		"username": req.GetUsername(),
+		"user":     req.GetUser(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if req.GetUser() == "" || req.GetUser() == "default" {
+		err := fmt.Errorf("invalid user %q", req.GetUser())
+		s.logger.Errorw("invalid drop user request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create connection
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	if err = rdb.Do(ctx, "ACL", "DELUSER", req.GetUser()).Err(); err != nil {
+		s.logger.Errorw("failed to delete acl user", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	if err = persistACL(ctx, rdb); err != nil {
+		s.logger.Errorw("failed to persist acl", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("drop user successfully")
+	return nil, nil
+}
+
+func (s *service) RotatePassword(ctx context.Context, req *RotatePasswordRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis rotate password", map[string]interface{}{
+		"username":                req.GetUsername(),
+		"user":                    req.GetUser(),
+		"retain_current_password": req.GetRetainCurrentPassword(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	// The current password stays valid as second password until the next rotation
+	currentPassword := ""
+	if req.GetRetainCurrentPassword() {
+		password, err := util.DecryptPlainTextPassword(req.GetUser())
+		if err != nil {
+			s.logger.Warnw("failed to decrypt current password, it will not be retained", zap.Error(err), zap.String("user", req.GetUser()))
+		} else {
+			currentPassword = password
+		}
+	}
+
+	args, err := buildACLRotatePasswordArgs(req.GetUser(), currentPassword, req.GetPassword())
+	if err != nil {
+		s.logger.Errorw("invalid rotate password request", zap.Error(err))
+		return nil, err
+	}
+
+	// Create connection
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	if err = rdb.Do(ctx, args...).Err(); err != nil {
+		s.logger.Errorw("failed to rotate password", zap.Error(err), zap.String("user", req.GetUser()))
+		return nil, err
+	}
+
+	// Replicas authenticate to their master with masterauth, keep it in line with the rotated user
+	masterUser, err := rdb.ConfigGet(ctx, "masteruser").Result()
+	if err != nil {
+		s.logger.Errorw("failed to get masteruser", zap.Error(err))
+		return nil, err
+	}
+	if isMasterUser(req.GetUser(), masterUser["masteruser"]) {
+		if err = rdb.ConfigSet(ctx, "masterauth", req.GetPassword()).Err(); err != nil {
+			s.logger.Errorw("failed to set masterauth", zap.Error(err))
+			return nil, err
+		}
+
+		if err = rdb.ConfigRewrite(ctx).Err(); err != nil {
+			s.logger.Errorw("failed to rewrite config", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if err = persistACL(ctx, rdb); err != nil {
+		s.logger.Errorw("failed to persist acl", zap.Error(err))
+		return nil, err
+	}
+
+	util.SetRotatedPassword(req.GetUser(), req.GetPassword())
+
+	s.logger.Info("rotate password successfully")
 	return nil, nil
 }
 
+// buildACLRotatePasswordArgs replaces the passwords of the user with the new password,
+// and the current password when it has to stay valid
+func buildACLRotatePasswordArgs(user, currentPassword, password string) ([]interface{}, error) {
+	if user == "" || strings.ContainsAny(user, " \t\r\n") {
+		return nil, fmt.Errorf("invalid user %q", user)
+	}
+	if password == "" {
+		return nil, fmt.Errorf("password is required")
+	}
+
+	args := []interface{}{"ACL", "SETUSER", user, "resetpass"}
+	if currentPassword != "" && currentPassword != password {
+		args = append(args, ">"+currentPassword)
+	}
+
+	return append(args, ">"+password), nil
+}
+
+// isMasterUser reports whether the user is the one replicas authenticate with,
+// an empty masteruser means the default user
+func isMasterUser(user, masterUser string) bool {
+	if masterUser == "" {
+		masterUser = "default"
+	}
+
+	return user == masterUser
+}
+
+// buildACLSetUserArgs builds an ACL SETUSER command which resets the user before
+// applying the rules, so that repeated calls converge to the same definition.
+func buildACLSetUserArgs(user, password string, rules []string) ([]interface{}, error) {
+	if user == "" || user == "default" || strings.ContainsAny(user, " \t\r\n") {
+		return nil, fmt.Errorf("invalid user %q", user)
+	}
+	if password == "" {
+		return nil, fmt.Errorf("password is required")
+	}
+
+	args := []interface{}{"ACL", "SETUSER", user, "reset", "on", ">" + password}
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		if strings.ContainsAny(rule, " \t\r\n") {
+			return nil, fmt.Errorf("invalid acl rule %q", rule)
+		}
+		// passwords are managed through the request password only
+		switch {
+		case strings.HasPrefix(rule, ">"), strings.HasPrefix(rule, "<"),
+			strings.HasPrefix(rule, "#"), strings.HasPrefix(rule, "!"),
+			rule == "nopass", rule == "resetpass", rule == "off":
+			return nil, fmt.Errorf("acl rule %q is not allowed", rule)
+		}
+		args = append(args, rule)
+	}
+
+	return args, nil
+}
+
+// normalizeConfigName validates the name of a config parameter
+func normalizeConfigName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !configNameRE.MatchString(name) {
+		return "", fmt.Errorf("invalid config parameter %q", name)
+	}
+
+	return name, nil
+}
+
+// normalizeConfigs validates the names of a batch of config parameters
+func normalizeConfigs(variables map[string]string) (map[string]string, error) {
+	if len(variables) == 0 {
+		return nil, fmt.Errorf("variables is required")
+	}
+
+	normalized := make(map[string]string, len(variables))
+	for key, value := range variables {
+		name, err := normalizeConfigName(key)
+		if err != nil {
+			return nil, err
+		}
+		normalized[name] = value
+	}
+
+	return normalized, nil
+}
+
+// persistACL saves the ACL to the acl file, or rewrites the config file when
+// users are defined in redis.conf.
+func persistACL(ctx context.Context, client *redis.Client) error {
+	err := client.Do(ctx, "ACL", "SAVE").Err()
+	if err == nil {
+		return nil
+	}
+
+	if !strings.Contains(err.Error(), "ACL file") {
+		return err
+	}
+
+	return client.ConfigRewrite(ctx).Err()
+}
+
 // newRedisClient creates a Redis connection
 func (s *service) newRedisClient(ctx context.Context, username string) (*redis.Client, error) {
 	password, err := util.DecryptPlainTextPassword(username)
@@ -281,7 +765,7 @@ func waitForExistingBGSave(ctx context.Context, client *redis.Client, baseline t
 		return fmt.Errorf("failed to query redis persistence info: %w", err)
 	}
 
-	if parseRedisInfo(info)["rdb_bgsave_in_progress"] != "1" {
+	if common.ParseRedisInfo(info)["rdb_bgsave_in_progress"] != "1" {
 		return nil
 	}
 
@@ -306,7 +790,7 @@ func waitForBGSave(ctx context.Context, client *redis.Client, baseline time.Time
 			if err != nil {
 				continue
 			}
-			if parseRedisInfo(info)["rdb_bgsave_in_progress"] == "0" {
+			if common.ParseRedisInfo(info)["rdb_bgsave_in_progress"] == "0" {
 				lastSave, err := redisLastSaveTime(ctx, client)
 				if err != nil {
 					return fmt.Errorf("failed to query redis LASTSAVE: %w", err)
@@ -323,22 +807,6 @@ func waitForBGSave(ctx context.Context, client *redis.Client, baseline time.Time
 	}
 }
 
-func parseRedisInfo(info string) map[string]string {
-	result := make(map[string]string)
-	lines := strings.Split(info, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		parts := strings.SplitN(line, ":", 2)
-		if len(parts) == 2 {
-			result[parts[0]] = parts[1]
-		}
-	}
-	return result
-}
-
 //func getConfigValue(ctx context.Context, client *redis.Client, key string) (string, error) {
 //	resp, err := client.Do(ctx, "CONFIG", "GET", key).Result()
 //	if err != nil {
@@ -412,6 +880,21 @@ func renameWithBak(src string) error {
 	return nil
 }
 
+// writeFile writes the reader to path, replacing its content.
+func writeFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
 func RegistryGrpcApp() {
 	app.RegistryGrpcApp(svr)
 }
@@ -8,17 +8,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestParseRedisInfo(t *testing.T) {
-	info := `
-# Persistence
-rdb_bgsave_in_progress:0
-loading:0
-`
-	result := parseRedisInfo(info)
-	require.Equal(t, "0", result["rdb_bgsave_in_progress"])
-	require.Equal(t, "0", result["loading"])
-}
-
 func TestDiscoverRDBPath(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "dump.rdb")
@@ -50,3 +39,75 @@ func TestRenameWithBakMissing(t *testing.T) {
 	err := renameWithBak(filepath.Join(dir, "not-exist.rdb"))
 	require.NoError(t, err)
 }
+
+func TestBuildACLSetUserArgs(t *testing.T) {
+	args, err := buildACLSetUserArgs("app", "secret", []string{"~app:*", " +@read ", ""})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"ACL", "SETUSER", "app", "reset", "on", ">secret", "~app:*", "+@read"}, args)
+}
+
+func TestBuildACLSetUserArgsRejectsInvalidInput(t *testing.T) {
+	_, err := buildACLSetUserArgs("default", "secret", nil)
+	require.Error(t, err)
+
+	_, err = buildACLSetUserArgs("app", "", nil)
+	require.Error(t, err)
+
+	_, err = buildACLSetUserArgs("app", "secret", []string{"+@all ~*"})
+	require.Error(t, err)
+
+	_, err = buildACLSetUserArgs("app", "secret", []string{"nopass"})
+	require.Error(t, err)
+
+	_, err = buildACLSetUserArgs("app", "secret", []string{">other"})
+	require.Error(t, err)
+}
+
+func TestBuildACLRotatePasswordArgs(t *testing.T) {
+	args, err := buildACLRotatePasswordArgs("default", "old", "new")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"ACL", "SETUSER", "default", "resetpass", ">old", ">new"}, args)
+
+	args, err = buildACLRotatePasswordArgs("monitor", "", "new")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"ACL", "SETUSER", "monitor", "resetpass", ">new"}, args)
+
+	args, err = buildACLRotatePasswordArgs("monitor", "new", "new")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"ACL", "SETUSER", "monitor", "resetpass", ">new"}, args)
+
+	_, err = buildACLRotatePasswordArgs("monitor", "old", "")
+	require.Error(t, err)
+
+	_, err = buildACLRotatePasswordArgs("bad user", "old", "new")
+	require.Error(t, err)
+}
+
+func TestIsMasterUser(t *testing.T) {
+	require.True(t, isMasterUser("default", ""))
+	require.True(t, isMasterUser("replica", "replica"))
+	require.False(t, isMasterUser("monitor", ""))
+}
+
+func TestNormalizeConfigName(t *testing.T) {
+	name, err := normalizeConfigName("MaxMemory-Policy")
+	require.NoError(t, err)
+	require.Equal(t, "maxmemory-policy", name)
+
+	for _, invalid := range []string{"", "maxmemory 1", "*", "dir;x"} {
+		_, err = normalizeConfigName(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestNormalizeConfigs(t *testing.T) {
+	variables, err := normalizeConfigs(map[string]string{"MaxMemory": "1gb", "maxmemory-policy": "allkeys-lru"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"maxmemory": "1gb", "maxmemory-policy": "allkeys-lru"}, variables)
+
+	_, err = normalizeConfigs(nil)
+	require.Error(t, err)
+
+	_, err = normalizeConfigs(map[string]string{"dir;x": "/tmp"})
+	require.Error(t, err)
+}
@@ -27,6 +27,7 @@ type SetVariableRequest struct {
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Persist       bool                   `protobuf:"varint,4,opt,name=persist,proto3" json:"persist,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,6 +83,81 @@ func (x *SetVariableRequest) GetUsername() string {
 	return ""
 }
 
+func (x *SetVariableRequest) GetPersist() bool {
+	if x != nil {
+		return x.Persist
+	}
+	return false
+}
+
+type SetVariablesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variables     map[string]string      `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Persist       bool                   `protobuf:"varint,3,opt,name=persist,proto3" json:"persist,omitempty"`
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVariablesRequest) Reset() {
+	*x = SetVariablesRequest{}
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariablesRequest) ProtoMessage() {}
+
+func (x *SetVariablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariablesRequest.ProtoReflect.Descriptor instead.
+func (*SetVariablesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_redis_pb_redis_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetVariablesRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *SetVariablesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetVariablesRequest) GetPersist() bool {
+	if x != nil {
+		return x.Persist
+	}
+	return false
+}
+
+func (x *SetVariablesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
 type BackupRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BackupFile    string                 `protobuf:"bytes,1,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
@@ -93,7 +169,7 @@ type BackupRequest struct {
 
 func (x *BackupRequest) Reset() {
 	*x = BackupRequest{}
-	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[1]
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -105,7 +181,7 @@ func (x *BackupRequest) String() string {
 func (*BackupRequest) ProtoMessage() {}
 
 func (x *BackupRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[1]
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -118,7 +194,7 @@ func (x *BackupRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BackupRequest.ProtoReflect.Descriptor instead.
 func (*BackupRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_redis_pb_redis_proto_rawDescGZIP(), []int{1}
+	return file_pkg_agent_app_redis_pb_redis_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *BackupRequest) GetBackupFile() string {
@@ -152,7 +228,7 @@ type RestoreRequest struct {
 
 func (x *RestoreRequest) Reset() {
 	*x = RestoreRequest{}
-	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[2]
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -164,7 +240,7 @@ func (x *RestoreRequest) String() string {
 func (*RestoreRequest) ProtoMessage() {}
 
 func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[2]
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -177,7 +253,7 @@ func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RestoreRequest.ProtoReflect.Descriptor instead.
 func (*RestoreRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_agent_app_redis_pb_redis_proto_rawDescGZIP(), []int{2}
+	return file_pkg_agent_app_redis_pb_redis_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *RestoreRequest) GetBackupFile() string {
@@ -194,15 +270,212 @@ func (x *RestoreRequest) GetObjectStorage() *common.ObjectStorage {
 	return nil
 }
 
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Rules         []string               `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_redis_pb_redis_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetRules() []string {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type DropUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropUserRequest) Reset() {
+	*x = DropUserRequest{}
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropUserRequest) ProtoMessage() {}
+
+func (x *DropUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropUserRequest.ProtoReflect.Descriptor instead.
+func (*DropUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_redis_pb_redis_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DropUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *DropUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type RotatePasswordRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Username              string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	User                  string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Password              string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	RetainCurrentPassword bool                   `protobuf:"varint,4,opt,name=retain_current_password,json=retainCurrentPassword,proto3" json:"retain_current_password,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *RotatePasswordRequest) Reset() {
+	*x = RotatePasswordRequest{}
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotatePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotatePasswordRequest) ProtoMessage() {}
+
+func (x *RotatePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_redis_pb_redis_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotatePasswordRequest.ProtoReflect.Descriptor instead.
+func (*RotatePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_redis_pb_redis_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RotatePasswordRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *RotatePasswordRequest) GetRetainCurrentPassword() bool {
+	if x != nil {
+		return x.RetainCurrentPassword
+	}
+	return false
+}
+
 var File_pkg_agent_app_redis_pb_redis_proto protoreflect.FileDescriptor
 
 const file_pkg_agent_app_redis_pb_redis_proto_rawDesc = "" +
 	"\n" +
-	"\"pkg/agent/app/redis/pb/redis.proto\x12\x05redis\x1a$pkg/agent/app/common/pb/common.proto\"X\n" +
+	"\"pkg/agent/app/redis/pb/redis.proto\x12\x05redis\x1a$pkg/agent/app/common/pb/common.proto\"r\n" +
 	"\x12SetVariableRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1a\n" +
-	"\busername\x18\x03 \x01(\tR\busername\"\x8a\x01\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x18\n" +
+	"\apersist\x18\x04 \x01(\bR\apersist\"\xeb\x01\n" +
+	"\x13SetVariablesRequest\x12G\n" +
+	"\tvariables\x18\x01 \x03(\v2).redis.SetVariablesRequest.VariablesEntryR\tvariables\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x18\n" +
+	"\apersist\x18\x03 \x01(\bR\apersist\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8a\x01\n" +
 	"\rBackupRequest\x12\x1f\n" +
 	"\vbackup_file\x18\x01 \x01(\tR\n" +
 	"backupFile\x12\x1a\n" +
@@ -211,11 +484,29 @@ const file_pkg_agent_app_redis_pb_redis_proto_rawDesc = "" +
 	"\x0eRestoreRequest\x12\x1f\n" +
 	"\vbackup_file\x18\x01 \x01(\tR\n" +
 	"backupFile\x12<\n" +
-	"\x0eobject_storage\x18\x02 \x01(\v2\x15.common.ObjectStorageR\robjectStorage2\xa9\x01\n" +
-	"\x0eRedisOperation\x127\n" +
-	"\vSetVariable\x12\x19.redis.SetVariableRequest\x1a\r.common.Empty\x12-\n" +
-	"\x06Backup\x12\x14.redis.BackupRequest\x1a\r.common.Empty\x12/\n" +
-	"\aRestore\x12\x15.redis.RestoreRequest\x1a\r.common.EmptyB4Z2github.com/upmio/unit-operator/pkg/agent/app/redisb\x06proto3"
+	"\x0eobject_storage\x18\x02 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\"u\n" +
+	"\x11CreateUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x14\n" +
+	"\x05rules\x18\x04 \x03(\tR\x05rules\"A\n" +
+	"\x0fDropUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\"\x9b\x01\n" +
+	"\x15RotatePasswordRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x126\n" +
+	"\x17retain_current_password\x18\x04 \x01(\bR\x15retainCurrentPassword2\xbd\x03\n" +
+	"\x0eRedisOperation\x12E\n" +
+	"\vSetVariable\x12\x19.redis.SetVariableRequest\x1a\x1b.common.SetVariableResponse\x12H\n" +
+	"\fSetVariables\x12\x1a.redis.SetVariablesRequest\x1a\x1c.common.SetVariablesResponse\x126\n" +
+	"\x06Backup\x12\x14.redis.BackupRequest\x1a\x16.common.BackupResponse\x129\n" +
+	"\aRestore\x12\x15.redis.RestoreRequest\x1a\x17.common.RestoreResponse\x125\n" +
+	"\n" +
+	"CreateUser\x12\x18.redis.CreateUserRequest\x1a\r.common.Empty\x121\n" +
+	"\bDropUser\x12\x16.redis.DropUserRequest\x1a\r.common.Empty\x12=\n" +
+	"\x0eRotatePassword\x12\x1c.redis.RotatePasswordRequest\x1a\r.common.EmptyB4Z2github.com/upmio/unit-operator/pkg/agent/app/redisb\x06proto3"
 
 var (
 	file_pkg_agent_app_redis_pb_redis_proto_rawDescOnce sync.Once
@@ -229,28 +520,46 @@ func file_pkg_agent_app_redis_pb_redis_proto_rawDescGZIP() []byte {
 	return file_pkg_agent_app_redis_pb_redis_proto_rawDescData
 }
 
-var file_pkg_agent_app_redis_pb_redis_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pkg_agent_app_redis_pb_redis_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_pkg_agent_app_redis_pb_redis_proto_goTypes = []any{
-	(*SetVariableRequest)(nil),   // 0: redis.SetVariableRequest
-	(*BackupRequest)(nil),        // 1: redis.BackupRequest
-	(*RestoreRequest)(nil),       // 2: redis.RestoreRequest
-	(*common.ObjectStorage)(nil), // 3: common.ObjectStorage
-	(*common.Empty)(nil),         // 4: common.Empty
+	(*SetVariableRequest)(nil),          // 0: redis.SetVariableRequest
+	(*SetVariablesRequest)(nil),         // 1: redis.SetVariablesRequest
+	(*BackupRequest)(nil),               // 2: redis.BackupRequest
+	(*RestoreRequest)(nil),              // 3: redis.RestoreRequest
+	(*CreateUserRequest)(nil),           // 4: redis.CreateUserRequest
+	(*DropUserRequest)(nil),             // 5: redis.DropUserRequest
+	(*RotatePasswordRequest)(nil),       // 6: redis.RotatePasswordRequest
+	nil,                                 // 7: redis.SetVariablesRequest.VariablesEntry
+	(*common.ObjectStorage)(nil),        // 8: common.ObjectStorage
+	(*common.SetVariableResponse)(nil),  // 9: common.SetVariableResponse
+	(*common.SetVariablesResponse)(nil), // 10: common.SetVariablesResponse
+	(*common.BackupResponse)(nil),       // 11: common.BackupResponse
+	(*common.RestoreResponse)(nil),      // 12: common.RestoreResponse
+	(*common.Empty)(nil),                // 13: common.Empty
 }
 var file_pkg_agent_app_redis_pb_redis_proto_depIdxs = []int32{
-	3, // 0: redis.BackupRequest.object_storage:type_name -> common.ObjectStorage
-	3, // 1: redis.RestoreRequest.object_storage:type_name -> common.ObjectStorage
-	0, // 2: redis.RedisOperation.SetVariable:input_type -> redis.SetVariableRequest
-	1, // 3: redis.RedisOperation.Backup:input_type -> redis.BackupRequest
-	2, // 4: redis.RedisOperation.Restore:input_type -> redis.RestoreRequest
-	4, // 5: redis.RedisOperation.SetVariable:output_type -> common.Empty
-	4, // 6: redis.RedisOperation.Backup:output_type -> common.Empty
-	4, // 7: redis.RedisOperation.Restore:output_type -> common.Empty
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	7,  // 0: redis.SetVariablesRequest.variables:type_name -> redis.SetVariablesRequest.VariablesEntry
+	8,  // 1: redis.BackupRequest.object_storage:type_name -> common.ObjectStorage
+	8,  // 2: redis.RestoreRequest.object_storage:type_name -> common.ObjectStorage
+	0,  // 3: redis.RedisOperation.SetVariable:input_type -> redis.SetVariableRequest
+	1,  // 4: redis.RedisOperation.SetVariables:input_type -> redis.SetVariablesRequest
+	2,  // 5: redis.RedisOperation.Backup:input_type -> redis.BackupRequest
+	3,  // 6: redis.RedisOperation.Restore:input_type -> redis.RestoreRequest
+	4,  // 7: redis.RedisOperation.CreateUser:input_type -> redis.CreateUserRequest
+	5,  // 8: redis.RedisOperation.DropUser:input_type -> redis.DropUserRequest
+	6,  // 9: redis.RedisOperation.RotatePassword:input_type -> redis.RotatePasswordRequest
+	9,  // 10: redis.RedisOperation.SetVariable:output_type -> common.SetVariableResponse
+	10, // 11: redis.RedisOperation.SetVariables:output_type -> common.SetVariablesResponse
+	11, // 12: redis.RedisOperation.Backup:output_type -> common.BackupResponse
+	12, // 13: redis.RedisOperation.Restore:output_type -> common.RestoreResponse
+	13, // 14: redis.RedisOperation.CreateUser:output_type -> common.Empty
+	13, // 15: redis.RedisOperation.DropUser:output_type -> common.Empty
+	13, // 16: redis.RedisOperation.RotatePassword:output_type -> common.Empty
+	10, // [10:17] is the sub-list for method output_type
+	3,  // [3:10] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_pkg_agent_app_redis_pb_redis_proto_init() }
@@ -264,7 +573,7 @@ func file_pkg_agent_app_redis_pb_redis_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_redis_pb_redis_proto_rawDesc), len(file_pkg_agent_app_redis_pb_redis_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,1535 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.20.0
+// source: pkg/agent/app/rediscluster/pb/rediscluster.proto
+
+package rediscluster
+
+import (
+	common "github.com/upmio/unit-operator/pkg/agent/app/common"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SlotRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int64                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           int64                  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlotRange) Reset() {
+	*x = SlotRange{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlotRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlotRange) ProtoMessage() {}
+
+func (x *SlotRange) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlotRange.ProtoReflect.Descriptor instead.
+func (*SlotRange) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SlotRange) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *SlotRange) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type MigratingSlot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slot          int64                  `protobuf:"varint,1,opt,name=slot,proto3" json:"slot,omitempty"`
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigratingSlot) Reset() {
+	*x = MigratingSlot{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigratingSlot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigratingSlot) ProtoMessage() {}
+
+func (x *MigratingSlot) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigratingSlot.ProtoReflect.Descriptor instead.
+func (*MigratingSlot) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MigratingSlot) GetSlot() int64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *MigratingSlot) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+type ClusterNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Flags         []string               `protobuf:"bytes,3,rep,name=flags,proto3" json:"flags,omitempty"`
+	MasterId      string                 `protobuf:"bytes,4,opt,name=master_id,json=masterId,proto3" json:"master_id,omitempty"`
+	LinkState     string                 `protobuf:"bytes,5,opt,name=link_state,json=linkState,proto3" json:"link_state,omitempty"`
+	Slots         []*SlotRange           `protobuf:"bytes,6,rep,name=slots,proto3" json:"slots,omitempty"`
+	Migrating     []*MigratingSlot       `protobuf:"bytes,7,rep,name=migrating,proto3" json:"migrating,omitempty"`
+	Importing     []*MigratingSlot       `protobuf:"bytes,8,rep,name=importing,proto3" json:"importing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterNode) Reset() {
+	*x = ClusterNode{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterNode) ProtoMessage() {}
+
+func (x *ClusterNode) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterNode.ProtoReflect.Descriptor instead.
+func (*ClusterNode) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ClusterNode) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ClusterNode) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *ClusterNode) GetFlags() []string {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *ClusterNode) GetMasterId() string {
+	if x != nil {
+		return x.MasterId
+	}
+	return ""
+}
+
+func (x *ClusterNode) GetLinkState() string {
+	if x != nil {
+		return x.LinkState
+	}
+	return ""
+}
+
+func (x *ClusterNode) GetSlots() []*SlotRange {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+func (x *ClusterNode) GetMigrating() []*MigratingSlot {
+	if x != nil {
+		return x.Migrating
+	}
+	return nil
+}
+
+func (x *ClusterNode) GetImporting() []*MigratingSlot {
+	if x != nil {
+		return x.Importing
+	}
+	return nil
+}
+
+type ClusterNodesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterNodesRequest) Reset() {
+	*x = ClusterNodesRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterNodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterNodesRequest) ProtoMessage() {}
+
+func (x *ClusterNodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterNodesRequest.ProtoReflect.Descriptor instead.
+func (*ClusterNodesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ClusterNodesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type ClusterNodesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MyId          string                 `protobuf:"bytes,1,opt,name=my_id,json=myId,proto3" json:"my_id,omitempty"`
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Nodes         []*ClusterNode         `protobuf:"bytes,3,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterNodesResponse) Reset() {
+	*x = ClusterNodesResponse{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterNodesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterNodesResponse) ProtoMessage() {}
+
+func (x *ClusterNodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterNodesResponse.ProtoReflect.Descriptor instead.
+func (*ClusterNodesResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ClusterNodesResponse) GetMyId() string {
+	if x != nil {
+		return x.MyId
+	}
+	return ""
+}
+
+func (x *ClusterNodesResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ClusterNodesResponse) GetNodes() []*ClusterNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type PauseWritesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	TimeoutMs     int64                  `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseWritesRequest) Reset() {
+	*x = PauseWritesRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseWritesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseWritesRequest) ProtoMessage() {}
+
+func (x *PauseWritesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseWritesRequest.ProtoReflect.Descriptor instead.
+func (*PauseWritesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PauseWritesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *PauseWritesRequest) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+type UnpauseWritesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnpauseWritesRequest) Reset() {
+	*x = UnpauseWritesRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnpauseWritesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnpauseWritesRequest) ProtoMessage() {}
+
+func (x *UnpauseWritesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnpauseWritesRequest.ProtoReflect.Descriptor instead.
+func (*UnpauseWritesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UnpauseWritesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type SnapshotShardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotShardRequest) Reset() {
+	*x = SnapshotShardRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotShardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotShardRequest) ProtoMessage() {}
+
+func (x *SnapshotShardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotShardRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotShardRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SnapshotShardRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type SnapshotShardResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Slots         []*SlotRange           `protobuf:"bytes,2,rep,name=slots,proto3" json:"slots,omitempty"`
+	Keys          int64                  `protobuf:"varint,3,opt,name=keys,proto3" json:"keys,omitempty"`
+	LastSave      int64                  `protobuf:"varint,4,opt,name=last_save,json=lastSave,proto3" json:"last_save,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotShardResponse) Reset() {
+	*x = SnapshotShardResponse{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotShardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotShardResponse) ProtoMessage() {}
+
+func (x *SnapshotShardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotShardResponse.ProtoReflect.Descriptor instead.
+func (*SnapshotShardResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SnapshotShardResponse) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *SnapshotShardResponse) GetSlots() []*SlotRange {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+func (x *SnapshotShardResponse) GetKeys() int64 {
+	if x != nil {
+		return x.Keys
+	}
+	return 0
+}
+
+func (x *SnapshotShardResponse) GetLastSave() int64 {
+	if x != nil {
+		return x.LastSave
+	}
+	return 0
+}
+
+type UploadShardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	BackupFile    string                 `protobuf:"bytes,2,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
+	ObjectStorage *common.ObjectStorage  `protobuf:"bytes,3,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
+	LastSave      int64                  `protobuf:"varint,4,opt,name=last_save,json=lastSave,proto3" json:"last_save,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadShardRequest) Reset() {
+	*x = UploadShardRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadShardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadShardRequest) ProtoMessage() {}
+
+func (x *UploadShardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadShardRequest.ProtoReflect.Descriptor instead.
+func (*UploadShardRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UploadShardRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UploadShardRequest) GetBackupFile() string {
+	if x != nil {
+		return x.BackupFile
+	}
+	return ""
+}
+
+func (x *UploadShardRequest) GetObjectStorage() *common.ObjectStorage {
+	if x != nil {
+		return x.ObjectStorage
+	}
+	return nil
+}
+
+func (x *UploadShardRequest) GetLastSave() int64 {
+	if x != nil {
+		return x.LastSave
+	}
+	return 0
+}
+
+type RestoreShardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BackupFile    string                 `protobuf:"bytes,1,opt,name=backup_file,json=backupFile,proto3" json:"backup_file,omitempty"`
+	ObjectStorage *common.ObjectStorage  `protobuf:"bytes,2,opt,name=object_storage,json=objectStorage,proto3" json:"object_storage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreShardRequest) Reset() {
+	*x = RestoreShardRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreShardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreShardRequest) ProtoMessage() {}
+
+func (x *RestoreShardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreShardRequest.ProtoReflect.Descriptor instead.
+func (*RestoreShardRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RestoreShardRequest) GetBackupFile() string {
+	if x != nil {
+		return x.BackupFile
+	}
+	return ""
+}
+
+func (x *RestoreShardRequest) GetObjectStorage() *common.ObjectStorage {
+	if x != nil {
+		return x.ObjectStorage
+	}
+	return nil
+}
+
+type MeetNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Host          string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Port          int64                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MeetNodeRequest) Reset() {
+	*x = MeetNodeRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MeetNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MeetNodeRequest) ProtoMessage() {}
+
+func (x *MeetNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MeetNodeRequest.ProtoReflect.Descriptor instead.
+func (*MeetNodeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MeetNodeRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *MeetNodeRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *MeetNodeRequest) GetPort() int64 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+type AssignSlotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Slots         []*SlotRange           `protobuf:"bytes,2,rep,name=slots,proto3" json:"slots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignSlotsRequest) Reset() {
+	*x = AssignSlotsRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignSlotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignSlotsRequest) ProtoMessage() {}
+
+func (x *AssignSlotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignSlotsRequest.ProtoReflect.Descriptor instead.
+func (*AssignSlotsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AssignSlotsRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *AssignSlotsRequest) GetSlots() []*SlotRange {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+type ReplicateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	MasterId      string                 `protobuf:"bytes,2,opt,name=master_id,json=masterId,proto3" json:"master_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplicateRequest) Reset() {
+	*x = ReplicateRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicateRequest) ProtoMessage() {}
+
+func (x *ReplicateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicateRequest.ProtoReflect.Descriptor instead.
+func (*ReplicateRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReplicateRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ReplicateRequest) GetMasterId() string {
+	if x != nil {
+		return x.MasterId
+	}
+	return ""
+}
+
+type AddNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Host          string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Port          int64                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	MasterId      string                 `protobuf:"bytes,4,opt,name=master_id,json=masterId,proto3" json:"master_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddNodeRequest) Reset() {
+	*x = AddNodeRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddNodeRequest) ProtoMessage() {}
+
+func (x *AddNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddNodeRequest.ProtoReflect.Descriptor instead.
+func (*AddNodeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AddNodeRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *AddNodeRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *AddNodeRequest) GetPort() int64 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *AddNodeRequest) GetMasterId() string {
+	if x != nil {
+		return x.MasterId
+	}
+	return ""
+}
+
+type RebalanceRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Username  string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Threshold int64                  `protobuf:"varint,2,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	MaxSlots  int64                  `protobuf:"varint,3,opt,name=max_slots,json=maxSlots,proto3" json:"max_slots,omitempty"`
+	Pipeline  int64                  `protobuf:"varint,4,opt,name=pipeline,proto3" json:"pipeline,omitempty"`
+	// masters whose slots all move to the other masters, before their node leaves the cluster
+	DrainIds      []string `protobuf:"bytes,5,rep,name=drain_ids,json=drainIds,proto3" json:"drain_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RebalanceRequest) Reset() {
+	*x = RebalanceRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebalanceRequest) ProtoMessage() {}
+
+func (x *RebalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebalanceRequest.ProtoReflect.Descriptor instead.
+func (*RebalanceRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RebalanceRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RebalanceRequest) GetThreshold() int64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *RebalanceRequest) GetMaxSlots() int64 {
+	if x != nil {
+		return x.MaxSlots
+	}
+	return 0
+}
+
+func (x *RebalanceRequest) GetPipeline() int64 {
+	if x != nil {
+		return x.Pipeline
+	}
+	return 0
+}
+
+func (x *RebalanceRequest) GetDrainIds() []string {
+	if x != nil {
+		return x.DrainIds
+	}
+	return nil
+}
+
+type RebalanceResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PlannedSlots   int64                  `protobuf:"varint,1,opt,name=planned_slots,json=plannedSlots,proto3" json:"planned_slots,omitempty"`
+	MigratedSlots  int64                  `protobuf:"varint,2,opt,name=migrated_slots,json=migratedSlots,proto3" json:"migrated_slots,omitempty"`
+	MigratedKeys   int64                  `protobuf:"varint,3,opt,name=migrated_keys,json=migratedKeys,proto3" json:"migrated_keys,omitempty"`
+	RemainingSlots int64                  `protobuf:"varint,4,opt,name=remaining_slots,json=remainingSlots,proto3" json:"remaining_slots,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RebalanceResponse) Reset() {
+	*x = RebalanceResponse{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebalanceResponse) ProtoMessage() {}
+
+func (x *RebalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebalanceResponse.ProtoReflect.Descriptor instead.
+func (*RebalanceResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RebalanceResponse) GetPlannedSlots() int64 {
+	if x != nil {
+		return x.PlannedSlots
+	}
+	return 0
+}
+
+func (x *RebalanceResponse) GetMigratedSlots() int64 {
+	if x != nil {
+		return x.MigratedSlots
+	}
+	return 0
+}
+
+func (x *RebalanceResponse) GetMigratedKeys() int64 {
+	if x != nil {
+		return x.MigratedKeys
+	}
+	return 0
+}
+
+func (x *RebalanceResponse) GetRemainingSlots() int64 {
+	if x != nil {
+		return x.RemainingSlots
+	}
+	return 0
+}
+
+type ForgetNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForgetNodeRequest) Reset() {
+	*x = ForgetNodeRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForgetNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForgetNodeRequest) ProtoMessage() {}
+
+func (x *ForgetNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForgetNodeRequest.ProtoReflect.Descriptor instead.
+func (*ForgetNodeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ForgetNodeRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ForgetNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+type FailoverRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Username       string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Mode           string                 `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	TimeoutSeconds int64                  `protobuf:"varint,3,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FailoverRequest) Reset() {
+	*x = FailoverRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailoverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailoverRequest) ProtoMessage() {}
+
+func (x *FailoverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailoverRequest.ProtoReflect.Descriptor instead.
+func (*FailoverRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FailoverRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *FailoverRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *FailoverRequest) GetTimeoutSeconds() int64 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+type ClusterHealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterHealthRequest) Reset() {
+	*x = ClusterHealthRequest{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterHealthRequest) ProtoMessage() {}
+
+func (x *ClusterHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterHealthRequest.ProtoReflect.Descriptor instead.
+func (*ClusterHealthRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ClusterHealthRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type ClusterHealthResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	State          string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	SlotsAssigned  int64                  `protobuf:"varint,2,opt,name=slots_assigned,json=slotsAssigned,proto3" json:"slots_assigned,omitempty"`
+	SlotsOk        int64                  `protobuf:"varint,3,opt,name=slots_ok,json=slotsOk,proto3" json:"slots_ok,omitempty"`
+	SlotsPfail     int64                  `protobuf:"varint,4,opt,name=slots_pfail,json=slotsPfail,proto3" json:"slots_pfail,omitempty"`
+	SlotsFail      int64                  `protobuf:"varint,5,opt,name=slots_fail,json=slotsFail,proto3" json:"slots_fail,omitempty"`
+	KnownNodes     int64                  `protobuf:"varint,6,opt,name=known_nodes,json=knownNodes,proto3" json:"known_nodes,omitempty"`
+	Size           int64                  `protobuf:"varint,7,opt,name=size,proto3" json:"size,omitempty"`
+	UncoveredSlots []*SlotRange           `protobuf:"bytes,8,rep,name=uncovered_slots,json=uncoveredSlots,proto3" json:"uncovered_slots,omitempty"`
+	FailingNodes   []*ClusterNode         `protobuf:"bytes,9,rep,name=failing_nodes,json=failingNodes,proto3" json:"failing_nodes,omitempty"`
+	OpenSlots      []*MigratingSlot       `protobuf:"bytes,10,rep,name=open_slots,json=openSlots,proto3" json:"open_slots,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ClusterHealthResponse) Reset() {
+	*x = ClusterHealthResponse{}
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterHealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterHealthResponse) ProtoMessage() {}
+
+func (x *ClusterHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterHealthResponse.ProtoReflect.Descriptor instead.
+func (*ClusterHealthResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ClusterHealthResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ClusterHealthResponse) GetSlotsAssigned() int64 {
+	if x != nil {
+		return x.SlotsAssigned
+	}
+	return 0
+}
+
+func (x *ClusterHealthResponse) GetSlotsOk() int64 {
+	if x != nil {
+		return x.SlotsOk
+	}
+	return 0
+}
+
+func (x *ClusterHealthResponse) GetSlotsPfail() int64 {
+	if x != nil {
+		return x.SlotsPfail
+	}
+	return 0
+}
+
+func (x *ClusterHealthResponse) GetSlotsFail() int64 {
+	if x != nil {
+		return x.SlotsFail
+	}
+	return 0
+}
+
+func (x *ClusterHealthResponse) GetKnownNodes() int64 {
+	if x != nil {
+		return x.KnownNodes
+	}
+	return 0
+}
+
+func (x *ClusterHealthResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ClusterHealthResponse) GetUncoveredSlots() []*SlotRange {
+	if x != nil {
+		return x.UncoveredSlots
+	}
+	return nil
+}
+
+func (x *ClusterHealthResponse) GetFailingNodes() []*ClusterNode {
+	if x != nil {
+		return x.FailingNodes
+	}
+	return nil
+}
+
+func (x *ClusterHealthResponse) GetOpenSlots() []*MigratingSlot {
+	if x != nil {
+		return x.OpenSlots
+	}
+	return nil
+}
+
+var File_pkg_agent_app_rediscluster_pb_rediscluster_proto protoreflect.FileDescriptor
+
+const file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDesc = "" +
+	"\n" +
+	"0pkg/agent/app/rediscluster/pb/rediscluster.proto\x12\frediscluster\x1a$pkg/agent/app/common/pb/common.proto\"3\n" +
+	"\tSlotRange\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\x03R\x03end\"<\n" +
+	"\rMigratingSlot\x12\x12\n" +
+	"\x04slot\x18\x01 \x01(\x03R\x04slot\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\"\xae\x02\n" +
+	"\vClusterNode\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x14\n" +
+	"\x05flags\x18\x03 \x03(\tR\x05flags\x12\x1b\n" +
+	"\tmaster_id\x18\x04 \x01(\tR\bmasterId\x12\x1d\n" +
+	"\n" +
+	"link_state\x18\x05 \x01(\tR\tlinkState\x12-\n" +
+	"\x05slots\x18\x06 \x03(\v2\x17.rediscluster.SlotRangeR\x05slots\x129\n" +
+	"\tmigrating\x18\a \x03(\v2\x1b.rediscluster.MigratingSlotR\tmigrating\x129\n" +
+	"\timporting\x18\b \x03(\v2\x1b.rediscluster.MigratingSlotR\timporting\"1\n" +
+	"\x13ClusterNodesRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"r\n" +
+	"\x14ClusterNodesResponse\x12\x13\n" +
+	"\x05my_id\x18\x01 \x01(\tR\x04myId\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12/\n" +
+	"\x05nodes\x18\x03 \x03(\v2\x19.rediscluster.ClusterNodeR\x05nodes\"O\n" +
+	"\x12PauseWritesRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x02 \x01(\x03R\ttimeoutMs\"2\n" +
+	"\x14UnpauseWritesRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"2\n" +
+	"\x14SnapshotShardRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"\x90\x01\n" +
+	"\x15SnapshotShardResponse\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12-\n" +
+	"\x05slots\x18\x02 \x03(\v2\x17.rediscluster.SlotRangeR\x05slots\x12\x12\n" +
+	"\x04keys\x18\x03 \x01(\x03R\x04keys\x12\x1b\n" +
+	"\tlast_save\x18\x04 \x01(\x03R\blastSave\"\xac\x01\n" +
+	"\x12UploadShardRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1f\n" +
+	"\vbackup_file\x18\x02 \x01(\tR\n" +
+	"backupFile\x12<\n" +
+	"\x0eobject_storage\x18\x03 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\x12\x1b\n" +
+	"\tlast_save\x18\x04 \x01(\x03R\blastSave\"t\n" +
+	"\x13RestoreShardRequest\x12\x1f\n" +
+	"\vbackup_file\x18\x01 \x01(\tR\n" +
+	"backupFile\x12<\n" +
+	"\x0eobject_storage\x18\x02 \x01(\v2\x15.common.ObjectStorageR\robjectStorage\"U\n" +
+	"\x0fMeetNodeRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x03 \x01(\x03R\x04port\"_\n" +
+	"\x12AssignSlotsRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12-\n" +
+	"\x05slots\x18\x02 \x03(\v2\x17.rediscluster.SlotRangeR\x05slots\"K\n" +
+	"\x10ReplicateRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1b\n" +
+	"\tmaster_id\x18\x02 \x01(\tR\bmasterId\"q\n" +
+	"\x0eAddNodeRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x03 \x01(\x03R\x04port\x12\x1b\n" +
+	"\tmaster_id\x18\x04 \x01(\tR\bmasterId\"\xa2\x01\n" +
+	"\x10RebalanceRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1c\n" +
+	"\tthreshold\x18\x02 \x01(\x03R\tthreshold\x12\x1b\n" +
+	"\tmax_slots\x18\x03 \x01(\x03R\bmaxSlots\x12\x1a\n" +
+	"\bpipeline\x18\x04 \x01(\x03R\bpipeline\x12\x1b\n" +
+	"\tdrain_ids\x18\x05 \x03(\tR\bdrainIds\"\xad\x01\n" +
+	"\x11RebalanceResponse\x12#\n" +
+	"\rplanned_slots\x18\x01 \x01(\x03R\fplannedSlots\x12%\n" +
+	"\x0emigrated_slots\x18\x02 \x01(\x03R\rmigratedSlots\x12#\n" +
+	"\rmigrated_keys\x18\x03 \x01(\x03R\fmigratedKeys\x12'\n" +
+	"\x0fremaining_slots\x18\x04 \x01(\x03R\x0eremainingSlots\"H\n" +
+	"\x11ForgetNodeRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\"j\n" +
+	"\x0fFailoverRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\tR\x04mode\x12'\n" +
+	"\x0ftimeout_seconds\x18\x03 \x01(\x03R\x0etimeoutSeconds\"2\n" +
+	"\x14ClusterHealthRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"\xa2\x03\n" +
+	"\x15ClusterHealthResponse\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12%\n" +
+	"\x0eslots_assigned\x18\x02 \x01(\x03R\rslotsAssigned\x12\x19\n" +
+	"\bslots_ok\x18\x03 \x01(\x03R\aslotsOk\x12\x1f\n" +
+	"\vslots_pfail\x18\x04 \x01(\x03R\n" +
+	"slotsPfail\x12\x1d\n" +
+	"\n" +
+	"slots_fail\x18\x05 \x01(\x03R\tslotsFail\x12\x1f\n" +
+	"\vknown_nodes\x18\x06 \x01(\x03R\n" +
+	"knownNodes\x12\x12\n" +
+	"\x04size\x18\a \x01(\x03R\x04size\x12@\n" +
+	"\x0funcovered_slots\x18\b \x03(\v2\x17.rediscluster.SlotRangeR\x0euncoveredSlots\x12>\n" +
+	"\rfailing_nodes\x18\t \x03(\v2\x19.rediscluster.ClusterNodeR\ffailingNodes\x12:\n" +
+	"\n" +
+	"open_slots\x18\n" +
+	" \x03(\v2\x1b.rediscluster.MigratingSlotR\topenSlots2\xdc\a\n" +
+	"\x15RedisClusterOperation\x12U\n" +
+	"\fClusterNodes\x12!.rediscluster.ClusterNodesRequest\x1a\".rediscluster.ClusterNodesResponse\x12>\n" +
+	"\vPauseWrites\x12 .rediscluster.PauseWritesRequest\x1a\r.common.Empty\x12B\n" +
+	"\rUnpauseWrites\x12\".rediscluster.UnpauseWritesRequest\x1a\r.common.Empty\x12X\n" +
+	"\rSnapshotShard\x12\".rediscluster.SnapshotShardRequest\x1a#.rediscluster.SnapshotShardResponse\x12>\n" +
+	"\vUploadShard\x12 .rediscluster.UploadShardRequest\x1a\r.common.Empty\x12@\n" +
+	"\fRestoreShard\x12!.rediscluster.RestoreShardRequest\x1a\r.common.Empty\x128\n" +
+	"\bMeetNode\x12\x1d.rediscluster.MeetNodeRequest\x1a\r.common.Empty\x12>\n" +
+	"\vAssignSlots\x12 .rediscluster.AssignSlotsRequest\x1a\r.common.Empty\x12:\n" +
+	"\tReplicate\x12\x1e.rediscluster.ReplicateRequest\x1a\r.common.Empty\x126\n" +
+	"\aAddNode\x12\x1c.rediscluster.AddNodeRequest\x1a\r.common.Empty\x12L\n" +
+	"\tRebalance\x12\x1e.rediscluster.RebalanceRequest\x1a\x1f.rediscluster.RebalanceResponse\x12<\n" +
+	"\n" +
+	"ForgetNode\x12\x1f.rediscluster.ForgetNodeRequest\x1a\r.common.Empty\x128\n" +
+	"\bFailover\x12\x1d.rediscluster.FailoverRequest\x1a\r.common.Empty\x12X\n" +
+	"\rClusterHealth\x12\".rediscluster.ClusterHealthRequest\x1a#.rediscluster.ClusterHealthResponseB;Z9github.com/upmio/unit-operator/pkg/agent/app/redisclusterb\x06proto3"
+
+var (
+	file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescOnce sync.Once
+	file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescData []byte
+)
+
+func file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescGZIP() []byte {
+	file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescOnce.Do(func() {
+		file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDesc), len(file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDesc)))
+	})
+	return file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDescData
+}
+
+var file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_pkg_agent_app_rediscluster_pb_rediscluster_proto_goTypes = []any{
+	(*SlotRange)(nil),             // 0: rediscluster.SlotRange
+	(*MigratingSlot)(nil),         // 1: rediscluster.MigratingSlot
+	(*ClusterNode)(nil),           // 2: rediscluster.ClusterNode
+	(*ClusterNodesRequest)(nil),   // 3: rediscluster.ClusterNodesRequest
+	(*ClusterNodesResponse)(nil),  // 4: rediscluster.ClusterNodesResponse
+	(*PauseWritesRequest)(nil),    // 5: rediscluster.PauseWritesRequest
+	(*UnpauseWritesRequest)(nil),  // 6: rediscluster.UnpauseWritesRequest
+	(*SnapshotShardRequest)(nil),  // 7: rediscluster.SnapshotShardRequest
+	(*SnapshotShardResponse)(nil), // 8: rediscluster.SnapshotShardResponse
+	(*UploadShardRequest)(nil),    // 9: rediscluster.UploadShardRequest
+	(*RestoreShardRequest)(nil),   // 10: rediscluster.RestoreShardRequest
+	(*MeetNodeRequest)(nil),       // 11: rediscluster.MeetNodeRequest
+	(*AssignSlotsRequest)(nil),    // 12: rediscluster.AssignSlotsRequest
+	(*ReplicateRequest)(nil),      // 13: rediscluster.ReplicateRequest
+	(*AddNodeRequest)(nil),        // 14: rediscluster.AddNodeRequest
+	(*RebalanceRequest)(nil),      // 15: rediscluster.RebalanceRequest
+	(*RebalanceResponse)(nil),     // 16: rediscluster.RebalanceResponse
+	(*ForgetNodeRequest)(nil),     // 17: rediscluster.ForgetNodeRequest
+	(*FailoverRequest)(nil),       // 18: rediscluster.FailoverRequest
+	(*ClusterHealthRequest)(nil),  // 19: rediscluster.ClusterHealthRequest
+	(*ClusterHealthResponse)(nil), // 20: rediscluster.ClusterHealthResponse
+	(*common.ObjectStorage)(nil),  // 21: common.ObjectStorage
+	(*common.Empty)(nil),          // 22: common.Empty
+}
+var file_pkg_agent_app_rediscluster_pb_rediscluster_proto_depIdxs = []int32{
+	0,  // 0: rediscluster.ClusterNode.slots:type_name -> rediscluster.SlotRange
+	1,  // 1: rediscluster.ClusterNode.migrating:type_name -> rediscluster.MigratingSlot
+	1,  // 2: rediscluster.ClusterNode.importing:type_name -> rediscluster.MigratingSlot
+	2,  // 3: rediscluster.ClusterNodesResponse.nodes:type_name -> rediscluster.ClusterNode
+	0,  // 4: rediscluster.SnapshotShardResponse.slots:type_name -> rediscluster.SlotRange
+	21, // 5: rediscluster.UploadShardRequest.object_storage:type_name -> common.ObjectStorage
+	21, // 6: rediscluster.RestoreShardRequest.object_storage:type_name -> common.ObjectStorage
+	0,  // 7: rediscluster.AssignSlotsRequest.slots:type_name -> rediscluster.SlotRange
+	0,  // 8: rediscluster.ClusterHealthResponse.uncovered_slots:type_name -> rediscluster.SlotRange
+	2,  // 9: rediscluster.ClusterHealthResponse.failing_nodes:type_name -> rediscluster.ClusterNode
+	1,  // 10: rediscluster.ClusterHealthResponse.open_slots:type_name -> rediscluster.MigratingSlot
+	3,  // 11: rediscluster.RedisClusterOperation.ClusterNodes:input_type -> rediscluster.ClusterNodesRequest
+	5,  // 12: rediscluster.RedisClusterOperation.PauseWrites:input_type -> rediscluster.PauseWritesRequest
+	6,  // 13: rediscluster.RedisClusterOperation.UnpauseWrites:input_type -> rediscluster.UnpauseWritesRequest
+	7,  // 14: rediscluster.RedisClusterOperation.SnapshotShard:input_type -> rediscluster.SnapshotShardRequest
+	9,  // 15: rediscluster.RedisClusterOperation.UploadShard:input_type -> rediscluster.UploadShardRequest
+	10, // 16: rediscluster.RedisClusterOperation.RestoreShard:input_type -> rediscluster.RestoreShardRequest
+	11, // 17: rediscluster.RedisClusterOperation.MeetNode:input_type -> rediscluster.MeetNodeRequest
+	12, // 18: rediscluster.RedisClusterOperation.AssignSlots:input_type -> rediscluster.AssignSlotsRequest
+	13, // 19: rediscluster.RedisClusterOperation.Replicate:input_type -> rediscluster.ReplicateRequest
+	14, // 20: rediscluster.RedisClusterOperation.AddNode:input_type -> rediscluster.AddNodeRequest
+	15, // 21: rediscluster.RedisClusterOperation.Rebalance:input_type -> rediscluster.RebalanceRequest
+	17, // 22: rediscluster.RedisClusterOperation.ForgetNode:input_type -> rediscluster.ForgetNodeRequest
+	18, // 23: rediscluster.RedisClusterOperation.Failover:input_type -> rediscluster.FailoverRequest
+	19, // 24: rediscluster.RedisClusterOperation.ClusterHealth:input_type -> rediscluster.ClusterHealthRequest
+	4,  // 25: rediscluster.RedisClusterOperation.ClusterNodes:output_type -> rediscluster.ClusterNodesResponse
+	22, // 26: rediscluster.RedisClusterOperation.PauseWrites:output_type -> common.Empty
+	22, // 27: rediscluster.RedisClusterOperation.UnpauseWrites:output_type -> common.Empty
+	8,  // 28: rediscluster.RedisClusterOperation.SnapshotShard:output_type -> rediscluster.SnapshotShardResponse
+	22, // 29: rediscluster.RedisClusterOperation.UploadShard:output_type -> common.Empty
+	22, // 30: rediscluster.RedisClusterOperation.RestoreShard:output_type -> common.Empty
+	22, // 31: rediscluster.RedisClusterOperation.MeetNode:output_type -> common.Empty
+	22, // 32: rediscluster.RedisClusterOperation.AssignSlots:output_type -> common.Empty
+	22, // 33: rediscluster.RedisClusterOperation.Replicate:output_type -> common.Empty
+	22, // 34: rediscluster.RedisClusterOperation.AddNode:output_type -> common.Empty
+	16, // 35: rediscluster.RedisClusterOperation.Rebalance:output_type -> rediscluster.RebalanceResponse
+	22, // 36: rediscluster.RedisClusterOperation.ForgetNode:output_type -> common.Empty
+	22, // 37: rediscluster.RedisClusterOperation.Failover:output_type -> common.Empty
+	20, // 38: rediscluster.RedisClusterOperation.ClusterHealth:output_type -> rediscluster.ClusterHealthResponse
+	25, // [25:39] is the sub-list for method output_type
+	11, // [11:25] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_pkg_agent_app_rediscluster_pb_rediscluster_proto_init() }
+func file_pkg_agent_app_rediscluster_pb_rediscluster_proto_init() {
+	if File_pkg_agent_app_rediscluster_pb_rediscluster_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDesc), len(file_pkg_agent_app_rediscluster_pb_rediscluster_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   21,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_agent_app_rediscluster_pb_rediscluster_proto_goTypes,
+		DependencyIndexes: file_pkg_agent_app_rediscluster_pb_rediscluster_proto_depIdxs,
+		MessageInfos:      file_pkg_agent_app_rediscluster_pb_rediscluster_proto_msgTypes,
+	}.Build()
+	File_pkg_agent_app_rediscluster_pb_rediscluster_proto = out.File
+	file_pkg_agent_app_rediscluster_pb_rediscluster_proto_goTypes = nil
+	file_pkg_agent_app_rediscluster_pb_rediscluster_proto_depIdxs = nil
+}
@@ -0,0 +1,570 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package rediscluster
+
+import (
+	context "context"
+	common "github.com/upmio/unit-operator/pkg/agent/app/common"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RedisClusterOperationClient is the client API for RedisClusterOperation service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RedisClusterOperationClient interface {
+	ClusterNodes(ctx context.Context, in *ClusterNodesRequest, opts ...grpc.CallOption) (*ClusterNodesResponse, error)
+	PauseWrites(ctx context.Context, in *PauseWritesRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	UnpauseWrites(ctx context.Context, in *UnpauseWritesRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	SnapshotShard(ctx context.Context, in *SnapshotShardRequest, opts ...grpc.CallOption) (*SnapshotShardResponse, error)
+	UploadShard(ctx context.Context, in *UploadShardRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	RestoreShard(ctx context.Context, in *RestoreShardRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	MeetNode(ctx context.Context, in *MeetNodeRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	AssignSlots(ctx context.Context, in *AssignSlotsRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	Replicate(ctx context.Context, in *ReplicateRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	AddNode(ctx context.Context, in *AddNodeRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	Rebalance(ctx context.Context, in *RebalanceRequest, opts ...grpc.CallOption) (*RebalanceResponse, error)
+	ForgetNode(ctx context.Context, in *ForgetNodeRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	Failover(ctx context.Context, in *FailoverRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	ClusterHealth(ctx context.Context, in *ClusterHealthRequest, opts ...grpc.CallOption) (*ClusterHealthResponse, error)
+}
+
+type redisClusterOperationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRedisClusterOperationClient(cc grpc.ClientConnInterface) RedisClusterOperationClient {
+	return &redisClusterOperationClient{cc}
+}
+
+func (c *redisClusterOperationClient) ClusterNodes(ctx context.Context, in *ClusterNodesRequest, opts ...grpc.CallOption) (*ClusterNodesResponse, error) {
+	out := new(ClusterNodesResponse)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/ClusterNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) PauseWrites(ctx context.Context, in *PauseWritesRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/PauseWrites", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) UnpauseWrites(ctx context.Context, in *UnpauseWritesRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/UnpauseWrites", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) SnapshotShard(ctx context.Context, in *SnapshotShardRequest, opts ...grpc.CallOption) (*SnapshotShardResponse, error) {
+	out := new(SnapshotShardResponse)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/SnapshotShard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) UploadShard(ctx context.Context, in *UploadShardRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/UploadShard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) RestoreShard(ctx context.Context, in *RestoreShardRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/RestoreShard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) MeetNode(ctx context.Context, in *MeetNodeRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/MeetNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) AssignSlots(ctx context.Context, in *AssignSlotsRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/AssignSlots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) Replicate(ctx context.Context, in *ReplicateRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/Replicate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) AddNode(ctx context.Context, in *AddNodeRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/AddNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) Rebalance(ctx context.Context, in *RebalanceRequest, opts ...grpc.CallOption) (*RebalanceResponse, error) {
+	out := new(RebalanceResponse)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/Rebalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) ForgetNode(ctx context.Context, in *ForgetNodeRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/ForgetNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) Failover(ctx context.Context, in *FailoverRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/Failover", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redisClusterOperationClient) ClusterHealth(ctx context.Context, in *ClusterHealthRequest, opts ...grpc.CallOption) (*ClusterHealthResponse, error) {
+	out := new(ClusterHealthResponse)
+	err := c.cc.Invoke(ctx, "/rediscluster.RedisClusterOperation/ClusterHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RedisClusterOperationServer is the server API for RedisClusterOperation service.
+// All implementations must embed UnimplementedRedisClusterOperationServer
+// for forward compatibility
+type RedisClusterOperationServer interface {
+	ClusterNodes(context.Context, *ClusterNodesRequest) (*ClusterNodesResponse, error)
+	PauseWrites(context.Context, *PauseWritesRequest) (*common.Empty, error)
+	UnpauseWrites(context.Context, *UnpauseWritesRequest) (*common.Empty, error)
+	SnapshotShard(context.Context, *SnapshotShardRequest) (*SnapshotShardResponse, error)
+	UploadShard(context.Context, *UploadShardRequest) (*common.Empty, error)
+	RestoreShard(context.Context, *RestoreShardRequest) (*common.Empty, error)
+	MeetNode(context.Context, *MeetNodeRequest) (*common.Empty, error)
+	AssignSlots(context.Context, *AssignSlotsRequest) (*common.Empty, error)
+	Replicate(context.Context, *ReplicateRequest) (*common.Empty, error)
+	AddNode(context.Context, *AddNodeRequest) (*common.Empty, error)
+	Rebalance(context.Context, *RebalanceRequest) (*RebalanceResponse, error)
+	ForgetNode(context.Context, *ForgetNodeRequest) (*common.Empty, error)
+	Failover(context.Context, *FailoverRequest) (*common.Empty, error)
+	ClusterHealth(context.Context, *ClusterHealthRequest) (*ClusterHealthResponse, error)
+	mustEmbedUnimplementedRedisClusterOperationServer()
+}
+
+// UnimplementedRedisClusterOperationServer must be embedded to have forward compatible implementations.
+type UnimplementedRedisClusterOperationServer struct {
+}
+
+func (UnimplementedRedisClusterOperationServer) ClusterNodes(context.Context, *ClusterNodesRequest) (*ClusterNodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClusterNodes not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) PauseWrites(context.Context, *PauseWritesRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseWrites not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) UnpauseWrites(context.Context, *UnpauseWritesRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnpauseWrites not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) SnapshotShard(context.Context, *SnapshotShardRequest) (*SnapshotShardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotShard not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) UploadShard(context.Context, *UploadShardRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadShard not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) RestoreShard(context.Context, *RestoreShardRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreShard not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) MeetNode(context.Context, *MeetNodeRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MeetNode not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) AssignSlots(context.Context, *AssignSlotsRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignSlots not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) Replicate(context.Context, *ReplicateRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Replicate not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) AddNode(context.Context, *AddNodeRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddNode not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) Rebalance(context.Context, *RebalanceRequest) (*RebalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rebalance not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) ForgetNode(context.Context, *ForgetNodeRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForgetNode not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) Failover(context.Context, *FailoverRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Failover not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) ClusterHealth(context.Context, *ClusterHealthRequest) (*ClusterHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClusterHealth not implemented")
+}
+func (UnimplementedRedisClusterOperationServer) mustEmbedUnimplementedRedisClusterOperationServer() {}
+
+// UnsafeRedisClusterOperationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RedisClusterOperationServer will
+// result in compilation errors.
+type UnsafeRedisClusterOperationServer interface {
+	mustEmbedUnimplementedRedisClusterOperationServer()
+}
+
+func RegisterRedisClusterOperationServer(s grpc.ServiceRegistrar, srv RedisClusterOperationServer) {
+	s.RegisterService(&RedisClusterOperation_ServiceDesc, srv)
+}
+
+func _RedisClusterOperation_ClusterNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).ClusterNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/ClusterNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).ClusterNodes(ctx, req.(*ClusterNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_PauseWrites_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseWritesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).PauseWrites(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/PauseWrites",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).PauseWrites(ctx, req.(*PauseWritesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_UnpauseWrites_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnpauseWritesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).UnpauseWrites(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/UnpauseWrites",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).UnpauseWrites(ctx, req.(*UnpauseWritesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_SnapshotShard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotShardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).SnapshotShard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/SnapshotShard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).SnapshotShard(ctx, req.(*SnapshotShardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_UploadShard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadShardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).UploadShard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/UploadShard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).UploadShard(ctx, req.(*UploadShardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_RestoreShard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreShardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).RestoreShard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/RestoreShard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).RestoreShard(ctx, req.(*RestoreShardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_MeetNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MeetNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).MeetNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/MeetNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).MeetNode(ctx, req.(*MeetNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_AssignSlots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignSlotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).AssignSlots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/AssignSlots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).AssignSlots(ctx, req.(*AssignSlotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_Replicate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplicateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).Replicate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/Replicate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).Replicate(ctx, req.(*ReplicateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_AddNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).AddNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/AddNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).AddNode(ctx, req.(*AddNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_Rebalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).Rebalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/Rebalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).Rebalance(ctx, req.(*RebalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_ForgetNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForgetNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).ForgetNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/ForgetNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).ForgetNode(ctx, req.(*ForgetNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_Failover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FailoverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).Failover(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/Failover",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).Failover(ctx, req.(*FailoverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedisClusterOperation_ClusterHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedisClusterOperationServer).ClusterHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rediscluster.RedisClusterOperation/ClusterHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedisClusterOperationServer).ClusterHealth(ctx, req.(*ClusterHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RedisClusterOperation_ServiceDesc is the grpc.ServiceDesc for RedisClusterOperation service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RedisClusterOperation_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rediscluster.RedisClusterOperation",
+	HandlerType: (*RedisClusterOperationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ClusterNodes",
+			Handler:    _RedisClusterOperation_ClusterNodes_Handler,
+		},
+		{
+			MethodName: "PauseWrites",
+			Handler:    _RedisClusterOperation_PauseWrites_Handler,
+		},
+		{
+			MethodName: "UnpauseWrites",
+			Handler:    _RedisClusterOperation_UnpauseWrites_Handler,
+		},
+		{
+			MethodName: "SnapshotShard",
+			Handler:    _RedisClusterOperation_SnapshotShard_Handler,
+		},
+		{
+			MethodName: "UploadShard",
+			Handler:    _RedisClusterOperation_UploadShard_Handler,
+		},
+		{
+			MethodName: "RestoreShard",
+			Handler:    _RedisClusterOperation_RestoreShard_Handler,
+		},
+		{
+			MethodName: "MeetNode",
+			Handler:    _RedisClusterOperation_MeetNode_Handler,
+		},
+		{
+			MethodName: "AssignSlots",
+			Handler:    _RedisClusterOperation_AssignSlots_Handler,
+		},
+		{
+			MethodName: "Replicate",
+			Handler:    _RedisClusterOperation_Replicate_Handler,
+		},
+		{
+			MethodName: "AddNode",
+			Handler:    _RedisClusterOperation_AddNode_Handler,
+		},
+		{
+			MethodName: "Rebalance",
+			Handler:    _RedisClusterOperation_Rebalance_Handler,
+		},
+		{
+			MethodName: "ForgetNode",
+			Handler:    _RedisClusterOperation_ForgetNode_Handler,
+		},
+		{
+			MethodName: "Failover",
+			Handler:    _RedisClusterOperation_Failover_Handler,
+		},
+		{
+			MethodName: "ClusterHealth",
+			Handler:    _RedisClusterOperation_ClusterHealth_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/agent/app/rediscluster/pb/rediscluster.proto",
+}
@@ -0,0 +1,783 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/redis/go-redis/v9"
+	"github.com/upmio/unit-operator/pkg/agent/app"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/slm"
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+	"github.com/upmio/unit-operator/pkg/agent/vars"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+const (
+	// clusterSlots is the number of hash slots of a Redis Cluster
+	clusterSlots = 16384
+
+	// maxPauseTimeout bounds how long the writes of a shard may be paused
+	maxPauseTimeout = 60 * time.Second
+
+	nodesConfName = "nodes.conf"
+	rdbName       = "dump.rdb"
+
+	// shardBackupName is the hard link the uploaded snapshot is read from, so that a
+	// later save replacing dump.rdb cannot change it during the upload
+	shardBackupName = "dump.rdb.cluster-backup"
+)
+
+var (
+	// service instance
+	svr = &service{}
+
+	// resetPaths are the files, relative to the data directory, a restored shard must not load
+	resetPaths = []string{rdbName, "appendonlydir", "appendonly.aof"}
+)
+
+type service struct {
+	UnimplementedRedisClusterOperationServer
+	logger *zap.SugaredLogger
+
+	slm slm.ServiceLifecycleServer
+
+	dataDir string
+	confDir string
+}
+
+func (s *service) Config() error {
+	s.logger = zap.L().Named(appName).Sugar()
+
+	s.slm = app.GetGrpcApp("slm").(slm.ServiceLifecycleServer)
+
+	dataDir, err := util.IsEnvVarSet(vars.DataDirEnvKey)
+	if err != nil {
+		return err
+	}
+
+	confDir, err := util.IsEnvVarSet(vars.ConfigDirEnvKey)
+	if err != nil {
+		return err
+	}
+
+	s.dataDir = dataDir
+	s.confDir = confDir
+
+	return nil
+}
+
+func (s *service) Name() string {
+	return appName
+}
+
+func (s *service) Registry(server *grpc.Server) {
+	RegisterRedisClusterOperationServer(server, svr)
+}
+
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterRedisClusterOperationHandler(ctx, mux, conn)
+}
+
+func (s *service) ClusterNodes(ctx context.Context, req *ClusterNodesRequest) (*ClusterNodesResponse, error) {
+	util.LogRequestSafely(s.logger, "redis cluster nodes", map[string]interface{}{
+		"username": req.GetUsername(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	resp, err := clusterNodes(ctx, rdb)
+	if err != nil {
+		s.logger.Errorw("failed to query cluster nodes", zap.Error(err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *service) PauseWrites(ctx context.Context, req *PauseWritesRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis cluster pause writes", map[string]interface{}{
+		"username":   req.GetUsername(),
+		"timeout_ms": req.GetTimeoutMs(),
+	})
+
+	timeout := time.Duration(req.GetTimeoutMs()) * time.Millisecond
+	if timeout <= 0 || timeout > maxPauseTimeout {
+		return nil, fmt.Errorf("pause timeout must be between 1ms and %v", maxPauseTimeout)
+	}
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	if err := rdb.Do(ctx, "CLIENT", "PAUSE", req.GetTimeoutMs(), "WRITE").Err(); err != nil {
+		s.logger.Errorw("failed to pause writes", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("pause writes successfully")
+	return nil, nil
+}
+
+func (s *service) UnpauseWrites(ctx context.Context, req *UnpauseWritesRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis cluster unpause writes", map[string]interface{}{
+		"username": req.GetUsername(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	if err := rdb.Do(ctx, "CLIENT", "UNPAUSE").Err(); err != nil {
+		s.logger.Errorw("failed to unpause writes", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("unpause writes successfully")
+	return nil, nil
+}
+
+// SnapshotShard forks a background save of the paused shard and resumes its writes once the
+// fork captured the dataset, the save keeps running and is uploaded by UploadShard.
+func (s *service) SnapshotShard(ctx context.Context, req *SnapshotShardRequest) (*SnapshotShardResponse, error) {
+	util.LogRequestSafely(s.logger, "redis cluster snapshot shard", map[string]interface{}{
+		"username": req.GetUsername(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	defer func() {
+		if err := rdb.Do(context.Background(), "CLIENT", "UNPAUSE").Err(); err != nil {
+			s.logger.Errorw("failed to unpause writes", zap.Error(err))
+		}
+	}()
+
+	nodes, err := clusterNodes(ctx, rdb)
+	if err != nil {
+		s.logger.Errorw("failed to query cluster nodes", zap.Error(err))
+		return nil, err
+	}
+
+	myself := nodes.Myself()
+	if myself == nil || !myself.IsMaster() {
+		return nil, fmt.Errorf("node %s is not a master", nodes.GetMyId())
+	}
+
+	info, err := rdb.Info(ctx, "persistence").Result()
+	if err != nil {
+		s.logger.Errorw("failed to query persistence info", zap.Error(err))
+		return nil, err
+	}
+
+	if common.ParseRedisInfo(info)["rdb_bgsave_in_progress"] == "1" {
+		return nil, fmt.Errorf("a background save is already in progress")
+	}
+
+	keys, err := rdb.DBSize(ctx).Result()
+	if err != nil {
+		s.logger.Errorw("failed to query dbsize", zap.Error(err))
+		return nil, err
+	}
+
+	lastSave, err := rdb.LastSave(ctx).Result()
+	if err != nil {
+		s.logger.Errorw("failed to query lastsave", zap.Error(err))
+		return nil, err
+	}
+
+	// BGSAVE returns once the child is forked, the snapshot is fixed from then on
+	if err := rdb.BgSave(ctx).Err(); err != nil {
+		s.logger.Errorw("failed to trigger bgsave", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("snapshot shard successfully")
+
+	return &SnapshotShardResponse{
+		NodeId:   myself.GetId(),
+		Slots:    myself.GetSlots(),
+		Keys:     keys,
+		LastSave: lastSave,
+	}, nil
+}
+
+func (s *service) UploadShard(ctx context.Context, req *UploadShardRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis cluster upload shard", map[string]interface{}{
+		"backup_file": req.GetBackupFile(),
+		"username":    req.GetUsername(),
+		"last_save":   req.GetLastSave(),
+		"bucket":      req.GetObjectStorage().GetBucket(),
+		"endpoint":    req.GetObjectStorage().GetEndpoint(),
+		"access_key":  req.GetObjectStorage().GetAccessKey(),
+		"secret_key":  req.GetObjectStorage().GetSecretKey(),
+		"ssl":         req.GetObjectStorage().GetSsl(),
+		"type":        req.GetObjectStorage().GetType(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	savedAt, err := waitForSnapshot(ctx, rdb, req.GetLastSave())
+	if err != nil {
+		s.logger.Errorw("failed to wait for snapshot", zap.Error(err))
+		return nil, err
+	}
+
+	backupPath := filepath.Join(s.dataDir, shardBackupName)
+	if err := os.Remove(backupPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if err := os.Link(filepath.Join(s.dataDir, rdbName), backupPath); err != nil {
+		s.logger.Errorw("failed to link snapshot", zap.Error(err))
+		return nil, err
+	}
+	defer func() { _ = os.Remove(backupPath) }()
+
+	// A save finishing after the snapshot may have replaced dump.rdb before it was linked
+	lastSave, err := rdb.LastSave(ctx).Result()
+	if err != nil {
+		s.logger.Errorw("failed to query lastsave", zap.Error(err))
+		return nil, err
+	}
+	if lastSave != savedAt {
+		return nil, fmt.Errorf("the snapshot was replaced by a later save")
+	}
+
+	storageFactory, err := req.GetObjectStorage().GenerateFactory()
+	if err != nil {
+		s.logger.Errorw("failed to generate storage factory", zap.Error(err))
+		return nil, err
+	}
+
+	if err := storageFactory.PutFile(ctx, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), backupPath); err != nil {
+		s.logger.Errorw("failed to put backup file", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("upload shard successfully")
+	return nil, nil
+}
+
+// RestoreShard resets the stopped node, so that it starts with a new cluster identity, and
+// loads the shard backup. An empty backup file leaves the node empty to join as a replica.
+// It is safe to retry, the data of the node before the first attempt stays in the .bak files.
+func (s *service) RestoreShard(ctx context.Context, req *RestoreShardRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis cluster restore shard", map[string]interface{}{
+		"backup_file": req.GetBackupFile(),
+		"bucket":      req.GetObjectStorage().GetBucket(),
+		"endpoint":    req.GetObjectStorage().GetEndpoint(),
+		"access_key":  req.GetObjectStorage().GetAccessKey(),
+		"secret_key":  req.GetObjectStorage().GetSecretKey(),
+		"ssl":         req.GetObjectStorage().GetSsl(),
+		"type":        req.GetObjectStorage().GetType(),
+	})
+
+	// Check process is stopped
+	if _, err := s.slm.CheckProcessStopped(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process stopped", zap.Error(err))
+		return nil, err
+	}
+
+	paths := []string{filepath.Join(s.confDir, nodesConfName)}
+	for _, name := range resetPaths {
+		paths = append(paths, filepath.Join(s.dataDir, name))
+	}
+
+	for _, path := range paths {
+		if err := resetPath(path); err != nil {
+			s.logger.Errorw("failed to move aside", zap.Error(err), zap.String("path", path))
+			return nil, err
+		}
+	}
+
+	if req.GetBackupFile() == "" {
+		s.logger.Info("reset node successfully")
+		return nil, nil
+	}
+
+	storageFactory, err := req.GetObjectStorage().GenerateFactory()
+	if err != nil {
+		s.logger.Errorw("failed to generate storage factory", zap.Error(err))
+		return nil, err
+	}
+
+	rdbPath := filepath.Join(s.dataDir, rdbName)
+	if err := storageFactory.GetFile(ctx, req.GetObjectStorage().GetBucket(), req.GetBackupFile(), rdbPath); err != nil {
+		s.logger.Errorw("failed to get backup file", zap.Error(err))
+		return nil, err
+	}
+
+	if err := os.Chmod(rdbPath, 0644); err != nil {
+		s.logger.Errorw("failed to chmod rdb file", zap.Error(err))
+		return nil, err
+	}
+
+	if err := os.Chown(rdbPath, 1001, 1001); err != nil {
+		s.logger.Errorw("failed to chown rdb file", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("restore shard successfully")
+	return nil, nil
+}
+
+func (s *service) MeetNode(ctx context.Context, req *MeetNodeRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis cluster meet node", map[string]interface{}{
+		"username": req.GetUsername(),
+		"host":     req.GetHost(),
+		"port":     req.GetPort(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	if err := meetNode(ctx, rdb, req.GetHost(), req.GetPort()); err != nil {
+		s.logger.Errorw("failed to meet node", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("meet node successfully")
+	return nil, nil
+}
+
+func (s *service) AssignSlots(ctx context.Context, req *AssignSlotsRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis cluster assign slots", map[string]interface{}{
+		"username": req.GetUsername(),
+		"slots":    formatSlotRanges(req.GetSlots()),
+	})
+
+	if err := validateSlotRanges(req.GetSlots()); err != nil {
+		return nil, err
+	}
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	nodes, err := clusterNodes(ctx, rdb)
+	if err != nil {
+		s.logger.Errorw("failed to query cluster nodes", zap.Error(err))
+		return nil, err
+	}
+
+	// Slots already owned, such as the slots a loaded shard claimed for its keys, are skipped
+	missing := subtractSlotRanges(req.GetSlots(), nodes.Myself().GetSlots())
+	if len(missing) == 0 {
+		s.logger.Info("slots already assigned")
+		return nil, nil
+	}
+
+	args := []interface{}{"CLUSTER", "ADDSLOTSRANGE"}
+	for _, r := range missing {
+		args = append(args, r.GetStart(), r.GetEnd())
+	}
+
+	if err := rdb.Do(ctx, args...).Err(); err != nil {
+		s.logger.Errorw("failed to assign slots", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("assign slots successfully")
+	return nil, nil
+}
+
+func (s *service) Replicate(ctx context.Context, req *ReplicateRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis cluster replicate", map[string]interface{}{
+		"username":  req.GetUsername(),
+		"master_id": req.GetMasterId(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	nodes, err := clusterNodes(ctx, rdb)
+	if err != nil {
+		s.logger.Errorw("failed to query cluster nodes", zap.Error(err))
+		return nil, err
+	}
+
+	if nodes.Myself().GetMasterId() == req.GetMasterId() {
+		s.logger.Info("already replicating master")
+		return nil, nil
+	}
+
+	if err := rdb.ClusterReplicate(ctx, req.GetMasterId()).Err(); err != nil {
+		s.logger.Errorw("failed to replicate master", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("replicate master successfully")
+	return nil, nil
+}
+
+// Myself returns the node answering the request.
+func (x *ClusterNodesResponse) Myself() *ClusterNode {
+	for _, node := range x.GetNodes() {
+		if node.GetId() == x.GetMyId() {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// IsMaster reports whether the node is a master.
+func (x *ClusterNode) IsMaster() bool {
+	return x.hasFlag("master")
+}
+
+func (x *ClusterNode) hasFlag(flag string) bool {
+	for _, f := range x.GetFlags() {
+		if f == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// meetNode resolves the host, CLUSTER MEET only accepts an ip address, and meets the node.
+func meetNode(ctx context.Context, client *redis.Client, host string, port int64) error {
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("failed to resolve host %s: %v", host, err)
+	}
+
+	return client.ClusterMeet(ctx, addrs[0], strconv.FormatInt(port, 10)).Err()
+}
+
+func clusterNodes(ctx context.Context, client *redis.Client) (*ClusterNodesResponse, error) {
+	text, err := client.ClusterNodes(ctx).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to CLUSTER NODES: %w", err)
+	}
+
+	info, err := client.ClusterInfo(ctx).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to CLUSTER INFO: %w", err)
+	}
+
+	resp, err := parseClusterNodes(text)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.State = common.ParseRedisInfo(info)["cluster_state"]
+	return resp, nil
+}
+
+// parseClusterNodes parses the CLUSTER NODES output, slots being migrated ("[slot->-id]")
+// or imported ("[slot-<-id]") are reported apart from the owned slots.
+func parseClusterNodes(text string) (*ClusterNodesResponse, error) {
+	resp := &ClusterNodesResponse{}
+
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 8 {
+			return nil, fmt.Errorf("unexpected CLUSTER NODES line %q", line)
+		}
+
+		node := &ClusterNode{
+			Id:        fields[0],
+			Address:   strings.SplitN(strings.SplitN(fields[1], ",", 2)[0], "@", 2)[0],
+			Flags:     strings.Split(fields[2], ","),
+			LinkState: fields[7],
+		}
+		if fields[3] != "-" {
+			node.MasterId = fields[3]
+		}
+
+		for _, slot := range fields[8:] {
+			if strings.HasPrefix(slot, "[") {
+				if err := node.addOpenSlot(slot); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			r, err := parseSlotRange(slot)
+			if err != nil {
+				return nil, err
+			}
+			node.Slots = append(node.Slots, r)
+		}
+
+		if node.hasFlag("myself") {
+			resp.MyId = node.Id
+		}
+
+		resp.Nodes = append(resp.Nodes, node)
+	}
+
+	return resp, nil
+}
+
+// addOpenSlot records a slot being migrated or imported by the node.
+func (x *ClusterNode) addOpenSlot(value string) error {
+	entry := strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	if slot, nodeID, found := strings.Cut(entry, "->-"); found {
+		n, err := strconv.ParseInt(slot, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid migrating slot %q", value)
+		}
+		x.Migrating = append(x.Migrating, &MigratingSlot{Slot: n, NodeId: nodeID})
+		return nil
+	}
+
+	if slot, nodeID, found := strings.Cut(entry, "-<-"); found {
+		n, err := strconv.ParseInt(slot, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid importing slot %q", value)
+		}
+		x.Importing = append(x.Importing, &MigratingSlot{Slot: n, NodeId: nodeID})
+		return nil
+	}
+
+	return fmt.Errorf("invalid open slot %q", value)
+}
+
+func parseSlotRange(value string) (*SlotRange, error) {
+	start, end, found := strings.Cut(value, "-")
+	if !found {
+		end = start
+	}
+
+	s, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slot range %q", value)
+	}
+
+	e, err := strconv.ParseInt(end, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slot range %q", value)
+	}
+
+	return &SlotRange{Start: s, End: e}, nil
+}
+
+func validateSlotRanges(ranges []*SlotRange) error {
+	if len(ranges) == 0 {
+		return fmt.Errorf("slots is required")
+	}
+
+	for _, r := range ranges {
+		if r.GetStart() < 0 || r.GetEnd() >= clusterSlots || r.GetStart() > r.GetEnd() {
+			return fmt.Errorf("invalid slot range %d-%d", r.GetStart(), r.GetEnd())
+		}
+	}
+
+	return nil
+}
+
+// subtractSlotRanges returns the slots of ranges not in owned, merged into ascending ranges.
+func subtractSlotRanges(ranges, owned []*SlotRange) []*SlotRange {
+	var slots [clusterSlots]bool
+	for _, r := range ranges {
+		for i := r.GetStart(); i <= r.GetEnd(); i++ {
+			slots[i] = true
+		}
+	}
+	for _, r := range owned {
+		for i := r.GetStart(); i <= r.GetEnd() && i < clusterSlots; i++ {
+			slots[i] = false
+		}
+	}
+
+	var result []*SlotRange
+	for i := int64(0); i < clusterSlots; i++ {
+		if !slots[i] {
+			continue
+		}
+
+		if n := len(result); n > 0 && result[n-1].End == i-1 {
+			result[n-1].End = i
+			continue
+		}
+		result = append(result, &SlotRange{Start: i, End: i})
+	}
+
+	return result
+}
+
+func formatSlotRanges(ranges []*SlotRange) string {
+	values := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		values = append(values, fmt.Sprintf("%d-%d", r.GetStart(), r.GetEnd()))
+	}
+
+	return strings.Join(values, ",")
+}
+
+// waitForSnapshot waits until the background save started after lastSave finished
+// and returns its LASTSAVE.
+func waitForSnapshot(ctx context.Context, client *redis.Client, lastSave int64) (int64, error) {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		info, err := client.Info(ctx, "persistence").Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to query redis persistence info: %w", err)
+		}
+
+		values := common.ParseRedisInfo(info)
+		if values["rdb_bgsave_in_progress"] == "0" {
+			if values["rdb_last_bgsave_status"] != "ok" {
+				return 0, fmt.Errorf("redis background save failed")
+			}
+
+			savedAt, err := client.LastSave(ctx).Result()
+			if err != nil {
+				return 0, fmt.Errorf("failed to query redis LASTSAVE: %w", err)
+			}
+			if savedAt > lastSave {
+				return savedAt, nil
+			}
+
+			return 0, fmt.Errorf("no background save finished since %d", lastSave)
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// resetPath moves path aside to <path>.bak. A .bak left by an earlier attempt of the restore
+// holds the original data and is kept, path is a leftover of that attempt and removed.
+func resetPath(path string) error {
+	if util.IsFileExist(path + ".bak") {
+		return os.RemoveAll(path)
+	}
+
+	return util.MoveAsideWithBak(path)
+}
+
+// newRedisClient creates a Redis connection
+func (s *service) newRedisClient(ctx context.Context, username string) (*redis.Client, error) {
+	password, err := util.DecryptPlainTextPassword(username)
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", username))
+		return nil, err
+	}
+
+	return s.connect(ctx, "localhost:6379", password)
+}
+
+// connect creates a Redis connection to the node at addr
+func (s *service) connect(ctx context.Context, addr, password string) (*redis.Client, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       0,
+	})
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		s.logger.Errorw("failed to ping redis", zap.Error(err), zap.String("addr", addr))
+
+		s.closeRedisClient(rdb)
+		return nil, err
+	}
+
+	return rdb, nil
+}
+
+func (s *service) closeRedisClient(client *redis.Client) {
+	if client == nil {
+		return
+	}
+	if err := client.Close(); err != nil {
+		s.logger.Errorw("failed to close redis connection", zap.Error(err))
+	}
+}
+
+func RegistryGrpcApp() {
+	app.RegistryGrpcApp(svr)
+}
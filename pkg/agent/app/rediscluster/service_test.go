@@ -0,0 +1,82 @@
+package rediscluster
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testClusterNodes = `07c37dfeb235213a872192d90877d0cd55635b91 10.0.0.2:6379@16379,redis-1 slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 4 connected
+67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 10.0.0.3:6379@16379 master - 0 1426238316232 2 connected 5461-10922
+e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 10.0.0.1:6379@16379 myself,master - 0 0 1 connected 0-5460 [5461->-67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1]
+292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f 10.0.0.4:6379@16379 master,fail - 1426238316232 1426238315228 3 disconnected 10923 10924-16383
+`
+
+func TestParseClusterNodes(t *testing.T) {
+	resp, err := parseClusterNodes(testClusterNodes)
+	require.NoError(t, err)
+	require.Len(t, resp.GetNodes(), 4)
+	require.Equal(t, "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca", resp.GetMyId())
+
+	myself := resp.Myself()
+	require.NotNil(t, myself)
+	require.True(t, myself.IsMaster())
+	require.Equal(t, "10.0.0.1:6379", myself.GetAddress())
+	require.Equal(t, []*SlotRange{{Start: 0, End: 5460}}, myself.GetSlots())
+
+	replica := resp.GetNodes()[0]
+	require.False(t, replica.IsMaster())
+	require.Equal(t, "10.0.0.2:6379", replica.GetAddress())
+	require.Equal(t, "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca", replica.GetMasterId())
+
+	failed := resp.GetNodes()[3]
+	require.Equal(t, "disconnected", failed.GetLinkState())
+	require.Equal(t, []*SlotRange{{Start: 10923, End: 10923}, {Start: 10924, End: 16383}}, failed.GetSlots())
+}
+
+func TestParseClusterNodesRejectsInvalidInput(t *testing.T) {
+	_, err := parseClusterNodes("07c37dfeb235213a872192d90877d0cd55635b91 10.0.0.2:6379@16379 master")
+	require.Error(t, err)
+
+	_, err = parseClusterNodes("07c37 10.0.0.2:6379@16379 master - 0 0 1 connected 1-x")
+	require.Error(t, err)
+}
+
+func TestSubtractSlotRanges(t *testing.T) {
+	result := subtractSlotRanges(
+		[]*SlotRange{{Start: 0, End: 5460}, {Start: 6000, End: 6001}},
+		[]*SlotRange{{Start: 100, End: 200}, {Start: 6001, End: 6001}},
+	)
+	require.Equal(t, []*SlotRange{{Start: 0, End: 99}, {Start: 201, End: 5460}, {Start: 6000, End: 6000}}, result)
+
+	require.Empty(t, subtractSlotRanges([]*SlotRange{{Start: 0, End: 10}}, []*SlotRange{{Start: 0, End: 16383}}))
+}
+
+func TestValidateSlotRanges(t *testing.T) {
+	require.NoError(t, validateSlotRanges([]*SlotRange{{Start: 0, End: 16383}}))
+	require.Error(t, validateSlotRanges(nil))
+	require.Error(t, validateSlotRanges([]*SlotRange{{Start: 10, End: 5}}))
+	require.Error(t, validateSlotRanges([]*SlotRange{{Start: 0, End: 16384}}))
+}
+
+func TestResetPathKeepsFirstBak(t *testing.T) {
+	dir := t.TempDir()
+	rdbPath := filepath.Join(dir, "dump.rdb")
+
+	require.NoError(t, os.WriteFile(rdbPath, []byte("original"), 0o644))
+	require.NoError(t, resetPath(rdbPath))
+	require.NoFileExists(t, rdbPath)
+
+	// a retry after the download of the snapshot failed half way
+	require.NoError(t, os.WriteFile(rdbPath, []byte("partial"), 0o644))
+	require.NoError(t, resetPath(rdbPath))
+	require.NoFileExists(t, rdbPath)
+
+	data, err := os.ReadFile(rdbPath + ".bak")
+	require.NoError(t, err)
+	require.Equal(t, "original", string(data))
+
+	require.NoError(t, resetPath(filepath.Join(dir, "nodes.conf")))
+}
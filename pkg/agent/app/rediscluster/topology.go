@@ -0,0 +1,830 @@
+package rediscluster
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultRebalanceThreshold is the deviation, in percent of its share, a master may have before it is rebalanced
+	defaultRebalanceThreshold = 2
+
+	// defaultMigratePipeline is the number of keys moved by a single MIGRATE
+	defaultMigratePipeline = 100
+
+	// migrateTimeout bounds a single MIGRATE
+	migrateTimeout = 60 * time.Second
+
+	// defaultFailoverTimeout bounds how long the replica may take to be promoted
+	defaultFailoverTimeout = 30 * time.Second
+
+	// joinTimeout bounds how long a new node waits to learn the master it replicates
+	joinTimeout = 30 * time.Second
+)
+
+var failoverModes = map[string]bool{"": true, "force": true, "takeover": true}
+
+// AddNode joins this empty node to the cluster the host belongs to. With a master id the node becomes
+// a replica of that master once it learned it, otherwise it joins as a master without slots.
+func (s *service) AddNode(ctx context.Context, req *AddNodeRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis cluster add node", map[string]interface{}{
+		"username":  req.GetUsername(),
+		"host":      req.GetHost(),
+		"port":      req.GetPort(),
+		"master_id": req.GetMasterId(),
+	})
+
+	if req.GetHost() == "" || req.GetPort() <= 0 {
+		return nil, fmt.Errorf("host and port are required")
+	}
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	nodes, err := clusterNodes(ctx, rdb)
+	if err != nil {
+		s.logger.Errorw("failed to query cluster nodes", zap.Error(err))
+		return nil, err
+	}
+
+	// A node knowing no other node is not part of a cluster yet
+	if len(nodes.GetNodes()) <= 1 {
+		if len(nodes.Myself().GetSlots()) > 0 {
+			return nil, fmt.Errorf("node %s owns slots, only an empty node can be added", nodes.GetMyId())
+		}
+
+		keys, err := rdb.DBSize(ctx).Result()
+		if err != nil {
+			s.logger.Errorw("failed to query dbsize", zap.Error(err))
+			return nil, err
+		}
+		if keys > 0 {
+			return nil, fmt.Errorf("node %s holds %d keys, only an empty node can be added", nodes.GetMyId(), keys)
+		}
+
+		if err := meetNode(ctx, rdb, req.GetHost(), req.GetPort()); err != nil {
+			s.logger.Errorw("failed to meet node", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if req.GetMasterId() == "" || nodes.Myself().GetMasterId() == req.GetMasterId() {
+		s.logger.Info("add node successfully")
+		return nil, nil
+	}
+
+	if err := waitForNode(ctx, rdb, req.GetMasterId(), joinTimeout); err != nil {
+		s.logger.Errorw("failed to wait for master", zap.Error(err))
+		return nil, err
+	}
+
+	if err := rdb.ClusterReplicate(ctx, req.GetMasterId()).Err(); err != nil {
+		s.logger.Errorw("failed to replicate master", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("add node successfully")
+	return nil, nil
+}
+
+// Rebalance moves slots between the masters until every master, including a master without slots,
+// holds its share of the slots. Migrations left open by an interrupted rebalance are finished first.
+// max_slots bounds the slots moved by a single call, so that the caller can report the progress and
+// call again until no slot remains.
+// The masters of drain_ids get no share, all their slots move to the other masters so that their
+// node can leave the cluster.
+func (s *service) Rebalance(ctx context.Context, req *RebalanceRequest) (*RebalanceResponse, error) {
+	util.LogRequestSafely(s.logger, "redis cluster rebalance", map[string]interface{}{
+		"username":  req.GetUsername(),
+		"threshold": req.GetThreshold(),
+		"max_slots": req.GetMaxSlots(),
+		"pipeline":  req.GetPipeline(),
+		"drain_ids": req.GetDrainIds(),
+	})
+
+	if req.GetThreshold() < 0 || req.GetMaxSlots() < 0 || req.GetPipeline() < 0 {
+		return nil, fmt.Errorf("threshold, max_slots and pipeline must not be negative")
+	}
+
+	threshold := req.GetThreshold()
+	if threshold == 0 {
+		threshold = defaultRebalanceThreshold
+	}
+
+	pipeline := req.GetPipeline()
+	if pipeline == 0 {
+		pipeline = defaultMigratePipeline
+	}
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	password, err := util.DecryptPlainTextPassword(req.GetUsername())
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	rdb, err := s.connect(ctx, "localhost:6379", password)
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	nodes, err := clusterNodes(ctx, rdb)
+	if err != nil {
+		s.logger.Errorw("failed to query cluster nodes", zap.Error(err))
+		return nil, err
+	}
+
+	m, err := s.newSlotMigrator(ctx, nodes, password, pipeline)
+	if err != nil {
+		s.logger.Errorw("failed to prepare rebalance", zap.Error(err))
+		return nil, err
+	}
+	defer m.close()
+
+	resp := &RebalanceResponse{}
+
+	slots, keys, err := m.finishOpenSlots(ctx)
+	resp.MigratedSlots, resp.MigratedKeys = slots, keys
+	if err != nil {
+		s.logger.Errorw("failed to finish open slots", zap.Error(err))
+		return nil, err
+	}
+
+	drain := make(map[string]bool, len(req.GetDrainIds()))
+	for _, id := range req.GetDrainIds() {
+		drain[id] = true
+	}
+
+	kept := 0
+	for _, id := range m.masterIDs() {
+		if !drain[id] {
+			kept++
+		}
+	}
+	if kept == 0 {
+		return nil, fmt.Errorf("no master would be left to take the slots of the drained masters")
+	}
+
+	moves := planRebalance(m.masterIDs(), m.owned, threshold, drain)
+	resp.PlannedSlots = int64(len(moves))
+
+	migrated := int64(0)
+	for _, move := range moves {
+		if req.GetMaxSlots() > 0 && resp.GetMigratedSlots() >= req.GetMaxSlots() {
+			break
+		}
+
+		keys, err := m.migrateSlot(ctx, move)
+		if err != nil {
+			s.logger.Errorw("failed to migrate slot", zap.Error(err), zap.Int64("slot", move.slot))
+			return nil, err
+		}
+
+		migrated++
+		resp.MigratedSlots++
+		resp.MigratedKeys += keys
+	}
+
+	resp.RemainingSlots = resp.GetPlannedSlots() - migrated
+
+	s.logger.Infow("rebalance successfully",
+		"migrated_slots", resp.GetMigratedSlots(),
+		"migrated_keys", resp.GetMigratedKeys(),
+		"remaining_slots", resp.GetRemainingSlots())
+	return resp, nil
+}
+
+// ForgetNode removes the node from the cluster once its unit is gone. Every node the cluster still
+// reaches forgets it, so that no node gossips it back to the others. A node owning slots is refused,
+// its slots have to be drained by Rebalance first.
+func (s *service) ForgetNode(ctx context.Context, req *ForgetNodeRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis cluster forget node", map[string]interface{}{
+		"username": req.GetUsername(),
+		"node_id":  req.GetNodeId(),
+	})
+
+	if req.GetNodeId() == "" {
+		return nil, fmt.Errorf("node_id is required")
+	}
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	password, err := util.DecryptPlainTextPassword(req.GetUsername())
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	rdb, err := s.connect(ctx, "localhost:6379", password)
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	nodes, err := clusterNodes(ctx, rdb)
+	if err != nil {
+		s.logger.Errorw("failed to query cluster nodes", zap.Error(err))
+		return nil, err
+	}
+
+	if nodes.GetMyId() == req.GetNodeId() {
+		return nil, fmt.Errorf("node %s cannot forget itself", req.GetNodeId())
+	}
+
+	for _, node := range nodes.GetNodes() {
+		if node.GetId() == req.GetNodeId() && len(node.GetSlots()) > 0 {
+			return nil, fmt.Errorf("node %s owns slots %s, drain it first", node.GetId(), formatSlotRanges(node.GetSlots()))
+		}
+	}
+
+	for _, node := range nodes.GetNodes() {
+		if node.GetId() == req.GetNodeId() || node.hasFlag("fail") || node.hasFlag("noaddr") {
+			continue
+		}
+
+		if err := s.forgetNode(ctx, node, nodes.GetMyId(), rdb, password, req.GetNodeId()); err != nil {
+			s.logger.Errorw("failed to forget node", zap.Error(err), zap.String("node", node.GetId()))
+			return nil, err
+		}
+	}
+
+	s.logger.Info("forget node successfully")
+	return nil, nil
+}
+
+// forgetNode sends CLUSTER FORGET to the node, a node which does not know the forgotten node any
+// more has nothing left to do.
+func (s *service) forgetNode(ctx context.Context, node *ClusterNode, myID string, local *redis.Client, password, id string) error {
+	client := local
+	if node.GetId() != myID {
+		var err error
+		if client, err = s.connect(ctx, nodeAddr(node), password); err != nil {
+			return fmt.Errorf("failed to connect to node %s: %v", node.GetId(), err)
+		}
+		defer s.closeRedisClient(client)
+	}
+
+	if err := client.ClusterForget(ctx, id).Err(); err != nil && !strings.Contains(err.Error(), "Unknown node") {
+		return fmt.Errorf("node %s failed to forget node %s: %v", node.GetId(), id, err)
+	}
+
+	return nil
+}
+
+// Failover promotes this replica with CLUSTER FAILOVER and waits until it became a master.
+func (s *service) Failover(ctx context.Context, req *FailoverRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis cluster failover", map[string]interface{}{
+		"username":        req.GetUsername(),
+		"mode":            req.GetMode(),
+		"timeout_seconds": req.GetTimeoutSeconds(),
+	})
+
+	mode := strings.ToLower(req.GetMode())
+	if !failoverModes[mode] {
+		return nil, fmt.Errorf("invalid failover mode %q, must be one of force, takeover or empty", req.GetMode())
+	}
+
+	timeout := time.Duration(req.GetTimeoutSeconds()) * time.Second
+	if timeout <= 0 {
+		timeout = defaultFailoverTimeout
+	}
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	nodes, err := clusterNodes(ctx, rdb)
+	if err != nil {
+		s.logger.Errorw("failed to query cluster nodes", zap.Error(err))
+		return nil, err
+	}
+
+	if nodes.Myself().IsMaster() {
+		s.logger.Info("node is already a master")
+		return nil, nil
+	}
+
+	args := []interface{}{"CLUSTER", "FAILOVER"}
+	if mode != "" {
+		args = append(args, strings.ToUpper(mode))
+	}
+
+	if err := rdb.Do(ctx, args...).Err(); err != nil {
+		s.logger.Errorw("failed to failover", zap.Error(err))
+		return nil, err
+	}
+
+	if err := waitForPromotion(ctx, rdb, timeout); err != nil {
+		s.logger.Errorw("failed to wait for promotion", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("failover successfully")
+	return nil, nil
+}
+
+// ClusterHealth reports the slot coverage, the failing nodes and the slots being migrated.
+func (s *service) ClusterHealth(ctx context.Context, req *ClusterHealthRequest) (*ClusterHealthResponse, error) {
+	util.LogRequestSafely(s.logger, "redis cluster health", map[string]interface{}{
+		"username": req.GetUsername(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	password, err := util.DecryptPlainTextPassword(req.GetUsername())
+	if err != nil {
+		s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", req.GetUsername()))
+		return nil, err
+	}
+
+	rdb, err := s.connect(ctx, "localhost:6379", password)
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	nodes, err := clusterNodes(ctx, rdb)
+	if err != nil {
+		s.logger.Errorw("failed to query cluster nodes", zap.Error(err))
+		return nil, err
+	}
+
+	info, err := rdb.ClusterInfo(ctx).Result()
+	if err != nil {
+		s.logger.Errorw("failed to query cluster info", zap.Error(err))
+		return nil, err
+	}
+
+	resp := clusterHealth(nodes, common.ParseRedisInfo(info))
+
+	// A node only reports its own migrating slots
+	for _, node := range nodes.GetNodes() {
+		if !node.IsMaster() || node.isFailing() {
+			continue
+		}
+
+		view := nodes
+		if node.GetId() != nodes.GetMyId() {
+			client, err := s.connect(ctx, nodeAddr(node), password)
+			if err != nil {
+				continue
+			}
+
+			view, err = clusterNodes(ctx, client)
+			s.closeRedisClient(client)
+			if err != nil {
+				s.logger.Warnw("failed to query cluster nodes", zap.Error(err), zap.String("node", node.GetId()))
+				continue
+			}
+		}
+
+		resp.OpenSlots = append(resp.OpenSlots, view.Myself().GetMigrating()...)
+	}
+
+	return resp, nil
+}
+
+// isFailing reports whether the cluster flagged the node as failing or unreachable.
+func (x *ClusterNode) isFailing() bool {
+	return x.hasFlag("fail") || x.hasFlag("fail?") || x.hasFlag("noaddr")
+}
+
+// clusterHealth summarizes the CLUSTER INFO and CLUSTER NODES of a node.
+func clusterHealth(nodes *ClusterNodesResponse, info map[string]string) *ClusterHealthResponse {
+	resp := &ClusterHealthResponse{
+		State:         info["cluster_state"],
+		SlotsAssigned: parseInt(info["cluster_slots_assigned"]),
+		SlotsOk:       parseInt(info["cluster_slots_ok"]),
+		SlotsPfail:    parseInt(info["cluster_slots_pfail"]),
+		SlotsFail:     parseInt(info["cluster_slots_fail"]),
+		KnownNodes:    parseInt(info["cluster_known_nodes"]),
+		Size:          parseInt(info["cluster_size"]),
+	}
+
+	var assigned []*SlotRange
+	for _, node := range nodes.GetNodes() {
+		if node.IsMaster() {
+			assigned = append(assigned, node.GetSlots()...)
+		}
+
+		if node.isFailing() {
+			resp.FailingNodes = append(resp.FailingNodes, node)
+		}
+	}
+
+	resp.UncoveredSlots = subtractSlotRanges([]*SlotRange{{Start: 0, End: clusterSlots - 1}}, assigned)
+	return resp
+}
+
+func parseInt(value string) int64 {
+	n, _ := strconv.ParseInt(value, 10, 64)
+	return n
+}
+
+// nodeAddr returns the address to reach the node at, a node never met announces no ip.
+func nodeAddr(node *ClusterNode) string {
+	host, port, err := net.SplitHostPort(node.GetAddress())
+	if err != nil || host == "" {
+		return net.JoinHostPort("localhost", port)
+	}
+
+	return node.GetAddress()
+}
+
+// waitForNode waits until the node learned the node with the id past the handshake.
+func waitForNode(ctx context.Context, client *redis.Client, id string, timeout time.Duration) error {
+	return poll(ctx, timeout, func() (bool, error) {
+		nodes, err := clusterNodes(ctx, client)
+		if err != nil {
+			return false, err
+		}
+
+		for _, node := range nodes.GetNodes() {
+			if node.GetId() == id && !node.hasFlag("handshake") && !node.hasFlag("noaddr") {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}, fmt.Sprintf("node %s to be known", id))
+}
+
+// waitForPromotion waits until the node became a master.
+func waitForPromotion(ctx context.Context, client *redis.Client, timeout time.Duration) error {
+	return poll(ctx, timeout, func() (bool, error) {
+		nodes, err := clusterNodes(ctx, client)
+		if err != nil {
+			return false, err
+		}
+
+		return nodes.Myself().IsMaster(), nil
+	}, "the node to be promoted")
+}
+
+func poll(ctx context.Context, timeout time.Duration, done func() (bool, error), what string) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := done()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s", what)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// slotMove moves a slot from the source to the target master.
+type slotMove struct {
+	slot   int64
+	source string
+	target string
+}
+
+// planRebalance returns the slot moves giving every master its share of the slots. The masters
+// holding the most slots keep the remainder of the division, the surplus is taken from the highest
+// slots. A cluster whose masters all deviate at most threshold percent from their share is left as is,
+// unless a master holds no slot. The drained masters get no share and give away all their slots.
+func planRebalance(masters []string, owned map[string][]int64, threshold int64, drain map[string]bool) []slotMove {
+	var ids, drained []string
+	for _, id := range masters {
+		if drain[id] {
+			drained = append(drained, id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		a, b := len(owned[ids[i]]), len(owned[ids[j]])
+		if a != b {
+			return a > b
+		}
+		return ids[i] < ids[j]
+	})
+
+	base, extra := clusterSlots/len(ids), clusterSlots%len(ids)
+
+	shares := make(map[string]int, len(ids))
+	balanced := true
+	for i, id := range ids {
+		share := base
+		if i < extra {
+			share++
+		}
+		shares[id] = share
+
+		count := len(owned[id])
+		deviation := count - share
+		if deviation < 0 {
+			deviation = -deviation
+		}
+
+		if (count == 0 && share > 0) || int64(deviation)*100 > int64(share)*threshold {
+			balanced = false
+		}
+	}
+
+	sort.Strings(drained)
+	for _, id := range drained {
+		shares[id] = 0
+		if len(owned[id]) > 0 {
+			balanced = false
+		}
+	}
+
+	if balanced {
+		return nil
+	}
+
+	var surplus []slotMove
+	for _, id := range append(drained, ids...) {
+		slots := owned[id]
+		if n := len(slots) - shares[id]; n > 0 {
+			for _, slot := range slots[len(slots)-n:] {
+				surplus = append(surplus, slotMove{slot: slot, source: id})
+			}
+		}
+	}
+
+	var moves []slotMove
+	for _, id := range ids {
+		for need := shares[id] - len(owned[id]); need > 0 && len(surplus) > 0; need-- {
+			move := surplus[0]
+			surplus = surplus[1:]
+
+			move.target = id
+			moves = append(moves, move)
+		}
+	}
+
+	return moves
+}
+
+// slotMigrator migrates slots between the masters of the cluster, the way redis-cli does.
+type slotMigrator struct {
+	service  *service
+	password string
+	pipeline int64
+
+	masters map[string]*ClusterNode
+	clients map[string]*redis.Client
+	// views is the CLUSTER NODES of every master, a master only reports its own open slots
+	views map[string]*ClusterNodesResponse
+	// owned is the ascending slots of every master
+	owned map[string][]int64
+}
+
+// newSlotMigrator connects to every master, the cluster must cover every slot and no master may be failing.
+func (s *service) newSlotMigrator(ctx context.Context, nodes *ClusterNodesResponse, password string, pipeline int64) (*slotMigrator, error) {
+	m := &slotMigrator{
+		service:  s,
+		password: password,
+		pipeline: pipeline,
+		masters:  make(map[string]*ClusterNode),
+		clients:  make(map[string]*redis.Client),
+		views:    make(map[string]*ClusterNodesResponse),
+		owned:    make(map[string][]int64),
+	}
+
+	var assigned []*SlotRange
+	for _, node := range nodes.GetNodes() {
+		if !node.IsMaster() {
+			continue
+		}
+
+		if node.isFailing() || node.hasFlag("handshake") {
+			return nil, fmt.Errorf("master %s is failing or still joining", node.GetId())
+		}
+
+		m.masters[node.GetId()] = node
+		assigned = append(assigned, node.GetSlots()...)
+
+		for _, r := range node.GetSlots() {
+			for slot := r.GetStart(); slot <= r.GetEnd(); slot++ {
+				m.owned[node.GetId()] = append(m.owned[node.GetId()], slot)
+			}
+		}
+	}
+
+	if uncovered := subtractSlotRanges([]*SlotRange{{Start: 0, End: clusterSlots - 1}}, assigned); len(uncovered) > 0 {
+		return nil, fmt.Errorf("slots %s are not covered", formatSlotRanges(uncovered))
+	}
+
+	for _, id := range m.masterIDs() {
+		client, err := s.connect(ctx, nodeAddr(m.masters[id]), password)
+		if err != nil {
+			m.close()
+			return nil, fmt.Errorf("failed to connect to master %s: %v", id, err)
+		}
+		m.clients[id] = client
+
+		view, err := clusterNodes(ctx, client)
+		if err != nil {
+			m.close()
+			return nil, fmt.Errorf("failed to query cluster nodes of master %s: %v", id, err)
+		}
+		m.views[id] = view
+	}
+
+	return m, nil
+}
+
+func (m *slotMigrator) masterIDs() []string {
+	ids := make([]string, 0, len(m.masters))
+	for id := range m.masters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func (m *slotMigrator) close() {
+	for _, client := range m.clients {
+		m.service.closeRedisClient(client)
+	}
+}
+
+// finishOpenSlots completes the migrations an interrupted rebalance left open. A slot only imported
+// was left before its source started migrating and holds no key yet, it is made stable again.
+func (m *slotMigrator) finishOpenSlots(ctx context.Context) (int64, int64, error) {
+	var slots, keys int64
+
+	migrating := make(map[string]bool)
+	for _, id := range m.masterIDs() {
+		for _, open := range m.views[id].Myself().GetMigrating() {
+			if _, ok := m.masters[open.GetNodeId()]; !ok {
+				return slots, keys, fmt.Errorf("slot %d is migrating to unknown master %s", open.GetSlot(), open.GetNodeId())
+			}
+
+			moved, err := m.migrateSlot(ctx, slotMove{slot: open.GetSlot(), source: id, target: open.GetNodeId()})
+			if err != nil {
+				return slots, keys, err
+			}
+
+			migrating[fmt.Sprintf("%d/%s", open.GetSlot(), open.GetNodeId())] = true
+			slots++
+			keys += moved
+		}
+	}
+
+	for _, id := range m.masterIDs() {
+		for _, open := range m.views[id].Myself().GetImporting() {
+			if migrating[fmt.Sprintf("%d/%s", open.GetSlot(), id)] {
+				continue
+			}
+
+			if err := m.clients[id].Do(ctx, "CLUSTER", "SETSLOT", open.GetSlot(), "STABLE").Err(); err != nil {
+				return slots, keys, fmt.Errorf("failed to make slot %d stable on %s: %v", open.GetSlot(), id, err)
+			}
+		}
+	}
+
+	return slots, keys, nil
+}
+
+// migrateSlot moves every key of the slot to the target and assigns it the slot, it returns the keys moved.
+func (m *slotMigrator) migrateSlot(ctx context.Context, move slotMove) (int64, error) {
+	source, target := m.clients[move.source], m.clients[move.target]
+	if source == nil || target == nil {
+		return 0, fmt.Errorf("slot %d moves between unknown masters %s and %s", move.slot, move.source, move.target)
+	}
+
+	if err := target.Do(ctx, "CLUSTER", "SETSLOT", move.slot, "IMPORTING", move.source).Err(); err != nil {
+		return 0, fmt.Errorf("failed to set slot %d importing on %s: %v", move.slot, move.target, err)
+	}
+
+	if err := source.Do(ctx, "CLUSTER", "SETSLOT", move.slot, "MIGRATING", move.target).Err(); err != nil {
+		return 0, fmt.Errorf("failed to set slot %d migrating on %s: %v", move.slot, move.source, err)
+	}
+
+	host, port, err := net.SplitHostPort(nodeAddr(m.masters[move.target]))
+	if err != nil {
+		return 0, err
+	}
+
+	var moved int64
+	for {
+		keys, err := source.ClusterGetKeysInSlot(ctx, int(move.slot), int(m.pipeline)).Result()
+		if err != nil {
+			return moved, fmt.Errorf("failed to get keys in slot %d: %v", move.slot, err)
+		}
+		if len(keys) == 0 {
+			break
+		}
+
+		args := []interface{}{"MIGRATE", host, port, "", 0, migrateTimeout.Milliseconds()}
+		if m.password != "" {
+			args = append(args, "AUTH", m.password)
+		}
+		args = append(args, "KEYS")
+		for _, key := range keys {
+			args = append(args, key)
+		}
+
+		if err := source.Do(ctx, args...).Err(); err != nil {
+			return moved, fmt.Errorf("failed to migrate keys of slot %d: %v", move.slot, err)
+		}
+		moved += int64(len(keys))
+	}
+
+	// The target first, so that the slot is never without an owner accepting its keys
+	for _, id := range []string{move.target, move.source} {
+		if err := m.clients[id].Do(ctx, "CLUSTER", "SETSLOT", move.slot, "NODE", move.target).Err(); err != nil {
+			return moved, fmt.Errorf("failed to assign slot %d to %s on %s: %v", move.slot, move.target, id, err)
+		}
+	}
+
+	// The other masters learn the new owner from the gossip anyway, telling them saves the redirections
+	for _, id := range m.masterIDs() {
+		if id != move.target && id != move.source {
+			_ = m.clients[id].Do(ctx, "CLUSTER", "SETSLOT", move.slot, "NODE", move.target).Err()
+		}
+	}
+
+	m.owned[move.source] = removeSlot(m.owned[move.source], move.slot)
+	m.owned[move.target] = insertSlot(m.owned[move.target], move.slot)
+
+	return moved, nil
+}
+
+func removeSlot(slots []int64, slot int64) []int64 {
+	for i, s := range slots {
+		if s == slot {
+			return append(slots[:i:i], slots[i+1:]...)
+		}
+	}
+
+	return slots
+}
+
+func insertSlot(slots []int64, slot int64) []int64 {
+	i := sort.Search(len(slots), func(i int) bool { return slots[i] >= slot })
+	if i < len(slots) && slots[i] == slot {
+		return slots
+	}
+
+	slots = append(slots, 0)
+	copy(slots[i+1:], slots[i:])
+	slots[i] = slot
+	return slots
+}
@@ -0,0 +1,134 @@
+package rediscluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+)
+
+func slotSpan(start, end int64) []int64 {
+	slots := make([]int64, 0, end-start+1)
+	for slot := start; slot <= end; slot++ {
+		slots = append(slots, slot)
+	}
+	return slots
+}
+
+func applyMoves(owned map[string][]int64, moves []slotMove) map[string]int {
+	counts := make(map[string]int, len(owned))
+	for id, slots := range owned {
+		counts[id] = len(slots)
+	}
+	for _, move := range moves {
+		counts[move.source]--
+		counts[move.target]++
+	}
+	return counts
+}
+
+func TestPlanRebalanceAddsEmptyMaster(t *testing.T) {
+	owned := map[string][]int64{
+		"a": slotSpan(0, 8191),
+		"b": slotSpan(8192, 16383),
+		"c": nil,
+	}
+
+	moves := planRebalance([]string{"a", "b", "c"}, owned, defaultRebalanceThreshold, nil)
+	require.Len(t, moves, 5461)
+	require.Equal(t, map[string]int{"a": 5462, "b": 5461, "c": 5461}, applyMoves(owned, moves))
+
+	for _, move := range moves {
+		require.Equal(t, "c", move.target)
+	}
+	// the surplus is taken from the highest slots of each source
+	require.Equal(t, slotMove{slot: 5462, source: "a", target: "c"}, moves[0])
+	require.Equal(t, int64(16383), moves[len(moves)-1].slot)
+}
+
+func TestPlanRebalanceWithinThreshold(t *testing.T) {
+	owned := map[string][]int64{
+		"a": slotSpan(0, 8250),
+		"b": slotSpan(8251, 16383),
+	}
+
+	require.Empty(t, planRebalance([]string{"a", "b"}, owned, defaultRebalanceThreshold, nil))
+	require.Len(t, planRebalance([]string{"a", "b"}, owned, 0, nil), 59)
+	require.Empty(t, planRebalance(nil, owned, defaultRebalanceThreshold, nil))
+}
+
+func TestPlanRebalanceDrainsMaster(t *testing.T) {
+	owned := map[string][]int64{
+		"a": slotSpan(0, 5461),
+		"b": slotSpan(5462, 10922),
+		"c": slotSpan(10923, 16383),
+	}
+
+	moves := planRebalance([]string{"a", "b", "c"}, owned, defaultRebalanceThreshold, map[string]bool{"c": true})
+	require.Len(t, moves, 5461)
+	require.Equal(t, map[string]int{"a": 8192, "b": 8192, "c": 0}, applyMoves(owned, moves))
+
+	for _, move := range moves {
+		require.Equal(t, "c", move.source)
+	}
+
+	// a drained master without slots leaves a balanced cluster as is
+	owned = map[string][]int64{
+		"a": slotSpan(0, 8191),
+		"b": slotSpan(8192, 16383),
+		"c": nil,
+	}
+	require.Empty(t, planRebalance([]string{"a", "b", "c"}, owned, defaultRebalanceThreshold, map[string]bool{"c": true}))
+	require.Empty(t, planRebalance([]string{"a"}, owned, defaultRebalanceThreshold, map[string]bool{"a": true}))
+}
+
+func TestParseClusterNodesOpenSlots(t *testing.T) {
+	resp, err := parseClusterNodes(testClusterNodes)
+	require.NoError(t, err)
+
+	myself := resp.Myself()
+	require.Equal(t, []*MigratingSlot{{Slot: 5461, NodeId: "67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1"}}, myself.GetMigrating())
+	require.Empty(t, myself.GetImporting())
+
+	resp, err = parseClusterNodes("e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 10.0.0.1:6379@16379 myself,master - 0 0 1 connected 0-5460 [5461-<-67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1]\n")
+	require.NoError(t, err)
+	require.Equal(t, []*MigratingSlot{{Slot: 5461, NodeId: "67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1"}}, resp.Myself().GetImporting())
+
+	_, err = parseClusterNodes("e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 10.0.0.1:6379@16379 myself,master - 0 0 1 connected [5461]\n")
+	require.Error(t, err)
+}
+
+func TestClusterHealth(t *testing.T) {
+	nodes, err := parseClusterNodes(testClusterNodes)
+	require.NoError(t, err)
+
+	resp := clusterHealth(nodes, common.ParseRedisInfo("cluster_state:fail\r\ncluster_slots_assigned:16384\r\ncluster_slots_ok:10923\r\ncluster_slots_fail:5461\r\ncluster_known_nodes:4\r\ncluster_size:3\r\n"))
+	require.Equal(t, "fail", resp.GetState())
+	require.Equal(t, int64(10923), resp.GetSlotsOk())
+	require.Equal(t, int64(5461), resp.GetSlotsFail())
+	require.Equal(t, int64(4), resp.GetKnownNodes())
+	require.Empty(t, resp.GetUncoveredSlots())
+	require.Len(t, resp.GetFailingNodes(), 1)
+	require.Equal(t, "292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f", resp.GetFailingNodes()[0].GetId())
+
+	// the failed master drops out of the coverage once it is forgotten
+	nodes.Nodes = nodes.Nodes[:3]
+	resp = clusterHealth(nodes, map[string]string{})
+	require.Equal(t, []*SlotRange{{Start: 10923, End: 16383}}, resp.GetUncoveredSlots())
+	require.Empty(t, resp.GetFailingNodes())
+}
+
+func TestSlotListHelpers(t *testing.T) {
+	slots := []int64{1, 3, 5}
+	slots = insertSlot(slots, 4)
+	require.Equal(t, []int64{1, 3, 4, 5}, slots)
+	require.Equal(t, []int64{1, 3, 4, 5}, insertSlot(slots, 4))
+	require.Equal(t, []int64{0, 1, 3, 4, 5}, insertSlot(slots, 0))
+	require.Equal(t, []int64{1, 4, 5}, removeSlot([]int64{1, 3, 4, 5}, 3))
+	require.Equal(t, []int64{1, 5}, removeSlot([]int64{1, 5}, 3))
+}
+
+func TestNodeAddr(t *testing.T) {
+	require.Equal(t, "10.0.0.1:6379", nodeAddr(&ClusterNode{Address: "10.0.0.1:6379"}))
+	require.Equal(t, "localhost:6379", nodeAddr(&ClusterNode{Address: ":6379"}))
+}
@@ -3,7 +3,10 @@ package sentinel
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	composev1alpha1 "github.com/upmio/compose-operator/api/v1alpha1"
 	"github.com/upmio/unit-operator/pkg/agent/app"
 	"github.com/upmio/unit-operator/pkg/agent/app/common"
@@ -20,9 +23,30 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	defaultMasterName = "mymaster"
+
+	// masterNameEnvKey overrides the master name used when a request carries none
+	masterNameEnvKey = "SENTINEL_MASTER_NAME"
+)
+
 var (
 	// service instance
 	svr = &service{}
+
+	// sentinelOptions are the options accepted by SENTINEL SET, the write only
+	// ones are not reported by SENTINEL MASTER
+	sentinelOptions = map[string]bool{
+		"down-after-milliseconds":         true,
+		"failover-timeout":                true,
+		"parallel-syncs":                  true,
+		"quorum":                          true,
+		"master-reboot-down-after-period": true,
+		"notification-script":             true,
+		"client-reconfig-script":          true,
+		"auth-pass":                       false,
+		"auth-user":                       false,
+	}
 )
 
 type service struct {
@@ -33,6 +57,8 @@ type service struct {
 	composeClient client.Client
 	recorder      *common.EventRecorder
 	slm           slm.ServiceLifecycleServer
+
+	masterName string
 }
 
 func (s *service) Config() error {
@@ -40,6 +66,7 @@ func (s *service) Config() error {
 	s.logger = zap.L().Named(appName).Sugar()
 
 	s.slm = app.GetGrpcApp("slm").(slm.ServiceLifecycleServer)
+	s.masterName = os.Getenv(masterNameEnvKey)
 
 	c, err := conf.GetConf().GetComposeClient()
 	if err != nil {
@@ -62,6 +89,10 @@ func (s *service) Registry(server *grpc.Server) {
 	RegisterSentinelOperationServer(server, svr)
 }
 
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterSentinelOperationHandler(ctx, mux, conn)
+}
+
 func (s *service) UpdateRedisReplication(ctx context.Context, req *UpdateRedisReplicationRequest) (*common.Empty, error) {
 	util.LogRequestSafely(s.logger, "update redis replication", map[string]interface{}{
 		"namespace":              req.GetNamespace(),
@@ -128,11 +159,12 @@ func (s *service) ensureRedisReplicationInstance(ctx context.Context, instance *
 	return fmt.Errorf("cannot find node %s:%d in replica list", sourceHost, sourcePort)
 }
 
-func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.Empty, error) {
+func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*common.SetVariableResponse, error) {
 	util.LogRequestSafely(s.logger, "redis-sentinel set variable", map[string]interface{}{
-		"key":      req.GetKey(),
-		"value":    req.GetValue(),
-		"username": req.GetUsername(),
+		"key":         req.GetKey(),
+		"value":       req.GetValue(),
+		"username":    req.GetUsername(),
+		"master_name": req.GetMasterName(),
 	})
 
 	// Check process is started
@@ -141,6 +173,19 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 		return nil, err
 	}
 
+	key := strings.ToLower(strings.TrimSpace(req.GetKey()))
+	if _, ok := sentinelOptions[key]; !ok {
+		err := fmt.Errorf("unknown sentinel option %q", req.GetKey())
+		s.logger.Errorw("invalid set variable request", zap.Error(err))
+		return nil, err
+	}
+
+	masterName, err := s.resolveMasterName(req.GetMasterName())
+	if err != nil {
+		s.logger.Errorw("invalid master name", zap.Error(err))
+		return nil, err
+	}
+
 	// Create connection
 	rdb, err := s.newRedisClient(ctx, req.GetUsername())
 	if err != nil {
@@ -148,15 +193,151 @@ func (s *service) SetVariable(ctx context.Context, req *SetVariableRequest) (*co
 	}
 	defer s.closeRedisClient(rdb)
 
-	// Execute set variable
-	masterName := "mymaster"
-	if err := rdb.Do(ctx, "SENTINEL", "SET", masterName, req.GetKey(), req.GetValue()).Err(); err != nil {
-		s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("key", req.GetKey()), zap.String("value", req.GetValue()))
+	previous, err := s.masterOption(ctx, rdb, masterName, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute set variable, SENTINEL SET applies the option at once and rewrites sentinel.conf
+	if err := rdb.Do(ctx, "SENTINEL", "SET", masterName, key, req.GetValue()).Err(); err != nil {
+		s.logger.Errorw("failed to set variable", zap.Error(err), zap.String("master_name", masterName), zap.String("key", key), zap.String("value", req.GetValue()))
 		return nil, err
 	}
 
-	s.logger.Info("set variable successfully")
-	return nil, nil
+	current, err := s.masterOption(ctx, rdb, masterName, key)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := common.NewSetVariableResponse(previous, current)
+	resp.Persisted = true
+
+	s.logger.Infow("set variable successfully", "changed", resp.GetChanged())
+	return resp, nil
+}
+
+// masterOption reads an option of the monitored master, empty when SENTINEL
+// MASTER does not report it.
+func (s *service) masterOption(ctx context.Context, rdb *redis.Client, masterName, key string) (string, error) {
+	reply, err := rdb.Do(ctx, "SENTINEL", "MASTER", masterName).Result()
+	if err != nil {
+		s.logger.Errorw("failed to get master", zap.Error(err), zap.String("master_name", masterName))
+		return "", err
+	}
+
+	return parseSentinelReply(reply)[key], nil
+}
+
+func (s *service) SetVariables(ctx context.Context, req *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	util.LogRequestSafely(s.logger, "redis-sentinel set variables", map[string]interface{}{
+		"variables":   req.GetVariables(),
+		"username":    req.GetUsername(),
+		"dry_run":     req.GetDryRun(),
+		"master_name": req.GetMasterName(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	if len(req.GetVariables()) == 0 {
+		err := fmt.Errorf("variables is required")
+		s.logger.Errorw("invalid set variables request", zap.Error(err))
+		return nil, err
+	}
+
+	masterName, err := s.resolveMasterName(req.GetMasterName())
+	if err != nil {
+		s.logger.Errorw("invalid master name", zap.Error(err))
+		return nil, err
+	}
+
+	// Create connection
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	reply, err := rdb.Do(ctx, "SENTINEL", "MASTER", masterName).Result()
+	if err != nil {
+		s.logger.Errorw("failed to get master", zap.Error(err), zap.String("master_name", masterName))
+		return nil, err
+	}
+	current := parseSentinelReply(reply)
+
+	// Unknown options fail the whole batch
+	variables := make(map[string]string, len(req.GetVariables()))
+	for key, value := range req.GetVariables() {
+		variables[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+
+	resp := &common.SetVariablesResponse{DryRun: req.GetDryRun()}
+	keys := common.SortedVariableKeys(variables)
+	for _, key := range keys {
+		result := common.NewVariableResult(key, current[key], variables[key])
+		if _, ok := sentinelOptions[key]; !ok {
+			result.Error = "unknown sentinel option"
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if req.GetDryRun() || resp.Failed() {
+		return resp, nil
+	}
+
+	// SENTINEL SET applies every option of the batch and rewrites sentinel.conf
+	args := []interface{}{"SENTINEL", "SET", masterName}
+	for _, key := range keys {
+		args = append(args, key, variables[key])
+	}
+	if err = rdb.Do(ctx, args...).Err(); err != nil {
+		s.logger.Errorw("failed to set variables", zap.Error(err))
+		return nil, err
+	}
+	resp.MarkApplied()
+	resp.Persisted = true
+
+	s.logger.Info("set variables successfully")
+	return resp, nil
+}
+
+// resolveMasterName returns the requested master name, falling back to the
+// configured one, and rejects names sentinel cannot store in sentinel.conf.
+func (s *service) resolveMasterName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = s.masterName
+	}
+	if name == "" {
+		name = defaultMasterName
+	}
+
+	if strings.ContainsAny(name, " \t\r\n\"'") {
+		return "", fmt.Errorf("invalid master name %q", name)
+	}
+
+	return name, nil
+}
+
+// parseSentinelReply converts a SENTINEL MASTER reply, a flat field list on
+// RESP2 or a map on RESP3, into a map of field values.
+func parseSentinelReply(reply interface{}) map[string]string {
+	fields := make(map[string]string)
+	switch v := reply.(type) {
+	case []interface{}:
+		for i := 0; i+1 < len(v); i += 2 {
+			fields[fmt.Sprint(v[i])] = fmt.Sprint(v[i+1])
+		}
+	case map[interface{}]interface{}:
+		for key, value := range v {
+			fields[fmt.Sprint(key)] = fmt.Sprint(value)
+		}
+	}
+
+	return fields
 }
 
 // newRedisClient creates a Redis connection
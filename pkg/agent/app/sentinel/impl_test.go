@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	composev1alpha1 "github.com/upmio/compose-operator/api/v1alpha1"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/slm"
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -82,3 +84,26 @@ func TestEnsureRedisReplicationInstanceNotFound(t *testing.T) {
 	require.Error(t, err)
 	require.False(t, stub.updated)
 }
+
+func TestParseSentinelReply(t *testing.T) {
+	require.Equal(t, map[string]string{"name": "mymaster", "quorum": "2"},
+		parseSentinelReply([]interface{}{"name", "mymaster", "quorum", "2"}))
+	require.Equal(t, map[string]string{"quorum": "2"},
+		parseSentinelReply(map[interface{}]interface{}{"quorum": int64(2)}))
+	require.Empty(t, parseSentinelReply(nil))
+}
+
+type startedSlm struct {
+	slm.UnimplementedServiceLifecycleServer
+}
+
+func (startedSlm) CheckProcessStarted(context.Context, *common.Empty) (*common.Empty, error) {
+	return nil, nil
+}
+
+func TestSetVariableRejectsUnknownOption(t *testing.T) {
+	s := &service{logger: zap.NewNop().Sugar(), slm: startedSlm{}}
+
+	_, err := s.SetVariable(context.Background(), &SetVariableRequest{Key: "monitor", Value: "x"})
+	require.ErrorContains(t, err, "unknown sentinel option")
+}
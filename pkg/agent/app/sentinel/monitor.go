@@ -0,0 +1,430 @@
+package sentinel
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultAuthUser is the redis user that needs no auth-user option
+	defaultAuthUser = "default"
+)
+
+func (s *service) Monitor(ctx context.Context, req *MonitorRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis-sentinel monitor", map[string]interface{}{
+		"username":    req.GetUsername(),
+		"master_name": req.GetMasterName(),
+		"host":        req.GetHost(),
+		"port":        req.GetPort(),
+		"quorum":      req.GetQuorum(),
+		"auth_user":   req.GetAuthUser(),
+		"options":     req.GetOptions(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	masterName, err := s.resolveMasterName(req.GetMasterName())
+	if err != nil {
+		s.logger.Errorw("invalid master name", zap.Error(err))
+		return nil, err
+	}
+
+	options, err := monitorOptions(req)
+	if err != nil {
+		s.logger.Errorw("invalid monitor request", zap.Error(err))
+		return nil, err
+	}
+
+	// The auth password is decrypted before anything is monitored, a half
+	// configured master would fail over without being able to authenticate
+	var authArgs []interface{}
+	if authUser := req.GetAuthUser(); authUser != "" {
+		authPass, err := util.DecryptPlainTextPassword(authUser)
+		if err != nil {
+			s.logger.Errorw("failed to decrypt password", zap.Error(err), zap.String("username", authUser))
+			return nil, err
+		}
+
+		authArgs = append(authArgs, "auth-pass", authPass)
+		if authUser != defaultAuthUser {
+			authArgs = append(authArgs, "auth-user", authUser)
+		}
+	}
+
+	// Create connection
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	// A master already monitored at the same address, or failed over since,
+	// is only reconfigured, so the request can be replayed on every sentinel unit
+	current, err := getMaster(ctx, rdb, masterName)
+	if err != nil {
+		s.logger.Errorw("failed to get master", zap.Error(err), zap.String("master_name", masterName))
+		return nil, err
+	}
+
+	if current == nil {
+		if err := rdb.Do(ctx, "SENTINEL", "MONITOR", masterName, req.GetHost(), req.GetPort(), req.GetQuorum()).Err(); err != nil {
+			s.logger.Errorw("failed to monitor master", zap.Error(err), zap.String("master_name", masterName))
+			return nil, err
+		}
+	} else {
+		monitored, err := monitoredAt(ctx, rdb, masterName, current, req.GetHost(), req.GetPort())
+		if err != nil {
+			s.logger.Errorw("failed to list replicas", zap.Error(err), zap.String("master_name", masterName))
+			return nil, err
+		}
+		if !monitored {
+			err := fmt.Errorf("master %s is already monitored at %s:%d", masterName, current.GetHost(), current.GetPort())
+			s.logger.Errorw("failed to monitor master", zap.Error(err))
+			return nil, err
+		}
+
+		options["quorum"] = strconv.FormatInt(req.GetQuorum(), 10)
+	}
+
+	args := []interface{}{"SENTINEL", "SET", masterName}
+	args = append(args, authArgs...)
+	for _, key := range common.SortedVariableKeys(options) {
+		args = append(args, key, options[key])
+	}
+	if len(args) > 3 {
+		if err := rdb.Do(ctx, args...).Err(); err != nil {
+			s.logger.Errorw("failed to set master options", zap.Error(err), zap.String("master_name", masterName))
+			return nil, err
+		}
+	}
+
+	s.logger.Infow("monitor master successfully", zap.String("master_name", masterName), zap.String("host", req.GetHost()), zap.Int64("port", req.GetPort()))
+	return nil, nil
+}
+
+func (s *service) Remove(ctx context.Context, req *MasterRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis-sentinel remove", map[string]interface{}{
+		"username":    req.GetUsername(),
+		"master_name": req.GetMasterName(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	masterName, err := s.resolveMasterName(req.GetMasterName())
+	if err != nil {
+		s.logger.Errorw("invalid master name", zap.Error(err))
+		return nil, err
+	}
+
+	// Create connection
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	current, err := getMaster(ctx, rdb, masterName)
+	if err != nil {
+		s.logger.Errorw("failed to get master", zap.Error(err), zap.String("master_name", masterName))
+		return nil, err
+	}
+	if current == nil {
+		s.logger.Infow("master is not monitored", zap.String("master_name", masterName))
+		return nil, nil
+	}
+
+	if err := rdb.Do(ctx, "SENTINEL", "REMOVE", masterName).Err(); err != nil {
+		s.logger.Errorw("failed to remove master", zap.Error(err), zap.String("master_name", masterName))
+		return nil, err
+	}
+
+	s.logger.Infow("remove master successfully", zap.String("master_name", masterName))
+	return nil, nil
+}
+
+func (s *service) Reset(ctx context.Context, req *ResetRequest) (*ResetResponse, error) {
+	util.LogRequestSafely(s.logger, "redis-sentinel reset", map[string]interface{}{
+		"username": req.GetUsername(),
+		"pattern":  req.GetPattern(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	// Resetting every master takes an explicit "*" pattern
+	pattern, err := s.resolveMasterName(req.GetPattern())
+	if err != nil {
+		s.logger.Errorw("invalid reset pattern", zap.Error(err))
+		return nil, err
+	}
+
+	// Create connection
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	reset, err := rdb.Do(ctx, "SENTINEL", "RESET", pattern).Int64()
+	if err != nil {
+		s.logger.Errorw("failed to reset masters", zap.Error(err), zap.String("pattern", pattern))
+		return nil, err
+	}
+
+	s.logger.Infow("reset masters successfully", zap.String("pattern", pattern), zap.Int64("reset", reset))
+	return &ResetResponse{ResetMasters: reset}, nil
+}
+
+func (s *service) ListMasters(ctx context.Context, req *ListMastersRequest) (*ListMastersResponse, error) {
+	util.LogRequestSafely(s.logger, "redis-sentinel list masters", map[string]interface{}{
+		"username": req.GetUsername(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	// Create connection
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	reply, err := rdb.Do(ctx, "SENTINEL", "MASTERS").Result()
+	if err != nil {
+		s.logger.Errorw("failed to list masters", zap.Error(err))
+		return nil, err
+	}
+
+	return &ListMastersResponse{Masters: parseSentinelInstances(reply)}, nil
+}
+
+func (s *service) ListReplicas(ctx context.Context, req *MasterRequest) (*ListReplicasResponse, error) {
+	util.LogRequestSafely(s.logger, "redis-sentinel list replicas", map[string]interface{}{
+		"username":    req.GetUsername(),
+		"master_name": req.GetMasterName(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	masterName, err := s.resolveMasterName(req.GetMasterName())
+	if err != nil {
+		s.logger.Errorw("invalid master name", zap.Error(err))
+		return nil, err
+	}
+
+	// Create connection
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	reply, err := rdb.Do(ctx, "SENTINEL", "REPLICAS", masterName).Result()
+	if err != nil {
+		s.logger.Errorw("failed to list replicas", zap.Error(err), zap.String("master_name", masterName))
+		return nil, err
+	}
+
+	return &ListReplicasResponse{Replicas: parseSentinelInstances(reply)}, nil
+}
+
+func (s *service) Failover(ctx context.Context, req *MasterRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "redis-sentinel failover", map[string]interface{}{
+		"username":    req.GetUsername(),
+		"master_name": req.GetMasterName(),
+	})
+
+	// Check process is started
+	if _, err := s.slm.CheckProcessStarted(ctx, nil); err != nil {
+		s.logger.Errorw("failed to check process started", zap.Error(err))
+		return nil, err
+	}
+
+	masterName, err := s.resolveMasterName(req.GetMasterName())
+	if err != nil {
+		s.logger.Errorw("invalid master name", zap.Error(err))
+		return nil, err
+	}
+
+	// Create connection
+	rdb, err := s.newRedisClient(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	defer s.closeRedisClient(rdb)
+
+	// The failover runs asynchronously, the new master is reported to
+	// UpdateRedisReplication by the client reconfig script
+	if err := rdb.Do(ctx, "SENTINEL", "FAILOVER", masterName).Err(); err != nil {
+		s.logger.Errorw("failed to failover master", zap.Error(err), zap.String("master_name", masterName))
+		return nil, err
+	}
+
+	s.logger.Infow("failover master successfully", zap.String("master_name", masterName))
+	return nil, nil
+}
+
+// monitorOptions validates a monitor request and returns its lower cased
+// options. The auth options are derived from auth_user only.
+func monitorOptions(req *MonitorRequest) (map[string]string, error) {
+	if req.GetHost() == "" {
+		return nil, fmt.Errorf("host is required")
+	}
+	if req.GetPort() <= 0 || req.GetPort() > 65535 {
+		return nil, fmt.Errorf("invalid port %d", req.GetPort())
+	}
+	if req.GetQuorum() <= 0 {
+		return nil, fmt.Errorf("quorum must be positive")
+	}
+
+	options := make(map[string]string, len(req.GetOptions()))
+	for key, value := range req.GetOptions() {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if readable, ok := sentinelOptions[key]; !ok || !readable || key == "quorum" {
+			return nil, fmt.Errorf("unsupported monitor option %q", key)
+		}
+		options[key] = value
+	}
+
+	return options, nil
+}
+
+// monitoredAt reports whether the master sentinel monitors is the one requested at host:port. A
+// master sentinel failed over since is known as a replica of the master it promoted.
+func monitoredAt(ctx context.Context, rdb *redis.Client, masterName string, current *SentinelInstance, host string, port int64) (bool, error) {
+	if sameAddress(ctx, current.GetHost(), current.GetPort(), host, port) {
+		return true, nil
+	}
+
+	reply, err := rdb.Do(ctx, "SENTINEL", "REPLICAS", masterName).Result()
+	if err != nil {
+		return false, err
+	}
+
+	for _, replica := range parseSentinelInstances(reply) {
+		if sameAddress(ctx, replica.GetHost(), replica.GetPort(), host, port) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// lookupHost resolves a host name, replaced by the tests.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// sameAddress reports whether both addresses resolve to a common ip, sentinel
+// keeps the ip a host name resolved to unless resolve-hostnames is enabled.
+func sameAddress(ctx context.Context, hostA string, portA int64, hostB string, portB int64) bool {
+	if portA != portB {
+		return false
+	}
+	if strings.EqualFold(hostA, hostB) {
+		return true
+	}
+
+	ips := make(map[string]bool)
+	for _, ip := range resolveHost(ctx, hostA) {
+		ips[ip] = true
+	}
+	for _, ip := range resolveHost(ctx, hostB) {
+		if ips[ip] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveHost returns the ips of the host, an ip or a host name which does
+// not resolve is returned as is.
+func resolveHost(ctx context.Context, host string) []string {
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{ip.String()}
+	}
+
+	addrs, err := lookupHost(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return []string{host}
+	}
+
+	ips := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		if ip := net.ParseIP(addr); ip != nil {
+			ips = append(ips, ip.String())
+		}
+	}
+
+	return ips
+}
+
+// getMaster returns the monitored master with the given name, or nil when
+// sentinel does not monitor it.
+func getMaster(ctx context.Context, rdb *redis.Client, masterName string) (*SentinelInstance, error) {
+	reply, err := rdb.Do(ctx, "SENTINEL", "MASTER", masterName).Result()
+	if err != nil {
+		if strings.Contains(err.Error(), "No such master") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return newSentinelInstance(parseSentinelReply(reply)), nil
+}
+
+// parseSentinelInstances converts a SENTINEL MASTERS or REPLICAS reply, a
+// list of instance replies, into sentinel instances.
+func parseSentinelInstances(reply interface{}) []*SentinelInstance {
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	instances := make([]*SentinelInstance, 0, len(items))
+	for _, item := range items {
+		instances = append(instances, newSentinelInstance(parseSentinelReply(item)))
+	}
+
+	return instances
+}
+
+func newSentinelInstance(fields map[string]string) *SentinelInstance {
+	instance := &SentinelInstance{
+		Name:   fields["name"],
+		Host:   fields["ip"],
+		Fields: fields,
+	}
+	instance.Port, _ = strconv.ParseInt(fields["port"], 10, 64)
+	if flags := fields["flags"]; flags != "" {
+		instance.Flags = strings.Split(flags, ",")
+	}
+
+	return instance
+}
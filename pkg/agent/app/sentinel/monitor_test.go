@@ -0,0 +1,93 @@
+package sentinel
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveMasterName(t *testing.T) {
+	svc := &service{}
+
+	name, err := svc.resolveMasterName("")
+	require.NoError(t, err)
+	require.Equal(t, defaultMasterName, name)
+
+	svc.masterName = "cache"
+	name, err = svc.resolveMasterName(" ")
+	require.NoError(t, err)
+	require.Equal(t, "cache", name)
+
+	name, err = svc.resolveMasterName("session")
+	require.NoError(t, err)
+	require.Equal(t, "session", name)
+
+	_, err = svc.resolveMasterName("my master")
+	require.Error(t, err)
+}
+
+func TestMonitorOptions(t *testing.T) {
+	options, err := monitorOptions(&MonitorRequest{
+		Host:    "redis-0.redis-headless-svc.default.svc",
+		Port:    6379,
+		Quorum:  2,
+		Options: map[string]string{" Down-After-Milliseconds ": "5000", "failover-timeout": "60000"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"down-after-milliseconds": "5000", "failover-timeout": "60000"}, options)
+
+	for _, req := range []*MonitorRequest{
+		{Port: 6379, Quorum: 2},
+		{Host: "redis", Port: 0, Quorum: 2},
+		{Host: "redis", Port: 6379},
+		{Host: "redis", Port: 6379, Quorum: 2, Options: map[string]string{"auth-pass": "secret"}},
+		{Host: "redis", Port: 6379, Quorum: 2, Options: map[string]string{"quorum": "3"}},
+		{Host: "redis", Port: 6379, Quorum: 2, Options: map[string]string{"unknown": "1"}},
+	} {
+		_, err := monitorOptions(req)
+		require.Error(t, err)
+	}
+}
+
+func TestParseSentinelInstances(t *testing.T) {
+	instances := parseSentinelInstances([]interface{}{
+		[]interface{}{"name", "cache", "ip", "10.0.0.1", "port", "6379", "flags", "master", "quorum", "2"},
+		map[interface{}]interface{}{"name": "10.0.0.2:6379", "ip": "10.0.0.2", "port": "6379", "flags": "slave,s_down"},
+	})
+	require.Len(t, instances, 2)
+
+	require.Equal(t, "cache", instances[0].GetName())
+	require.Equal(t, "10.0.0.1", instances[0].GetHost())
+	require.Equal(t, int64(6379), instances[0].GetPort())
+	require.Equal(t, []string{"master"}, instances[0].GetFlags())
+	require.Equal(t, "2", instances[0].GetFields()["quorum"])
+
+	require.Equal(t, []string{"slave", "s_down"}, instances[1].GetFlags())
+
+	require.Empty(t, parseSentinelInstances(nil))
+}
+
+func TestSameAddress(t *testing.T) {
+	hosts := map[string][]string{
+		"redis-0.redis-headless-svc.default.svc": {"10.0.0.1"},
+		"redis-1.redis-headless-svc.default.svc": {"10.0.0.2"},
+	}
+	lookup := lookupHost
+	lookupHost = func(_ context.Context, host string) ([]string, error) {
+		if addrs, ok := hosts[host]; ok {
+			return addrs, nil
+		}
+		return nil, fmt.Errorf("no such host %s", host)
+	}
+	t.Cleanup(func() { lookupHost = lookup })
+
+	ctx := context.Background()
+	require.True(t, sameAddress(ctx, "10.0.0.1", 6379, "redis-0.redis-headless-svc.default.svc", 6379))
+	require.True(t, sameAddress(ctx, "redis-0.redis-headless-svc.default.svc", 6379, "redis-0.redis-headless-svc.default.svc", 6379))
+	require.True(t, sameAddress(ctx, "unknown", 6379, "UNKNOWN", 6379))
+	require.False(t, sameAddress(ctx, "10.0.0.1", 6380, "redis-0.redis-headless-svc.default.svc", 6379))
+	require.False(t, sameAddress(ctx, "10.0.0.1", 6379, "redis-1.redis-headless-svc.default.svc", 6379))
+	require.False(t, sameAddress(ctx, "10.0.0.1", 6379, "unknown", 6379))
+}
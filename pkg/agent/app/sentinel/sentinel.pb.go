@@ -99,10 +99,12 @@ func (x *UpdateRedisReplicationRequest) GetSelfUnitName() string {
 }
 
 type SetVariableRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Key      string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value    string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Username string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	// master_name defaults to the configured master name
+	MasterName    string `protobuf:"bytes,4,opt,name=master_name,json=masterName,proto3" json:"master_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -158,6 +160,537 @@ func (x *SetVariableRequest) GetUsername() string {
 	return ""
 }
 
+func (x *SetVariableRequest) GetMasterName() string {
+	if x != nil {
+		return x.MasterName
+	}
+	return ""
+}
+
+type SetVariablesRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Variables map[string]string      `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Username  string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	DryRun    bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// master_name defaults to the configured master name
+	MasterName    string `protobuf:"bytes,4,opt,name=master_name,json=masterName,proto3" json:"master_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVariablesRequest) Reset() {
+	*x = SetVariablesRequest{}
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariablesRequest) ProtoMessage() {}
+
+func (x *SetVariablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariablesRequest.ProtoReflect.Descriptor instead.
+func (*SetVariablesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SetVariablesRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *SetVariablesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetVariablesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *SetVariablesRequest) GetMasterName() string {
+	if x != nil {
+		return x.MasterName
+	}
+	return ""
+}
+
+type MonitorRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Username   string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	MasterName string                 `protobuf:"bytes,2,opt,name=master_name,json=masterName,proto3" json:"master_name,omitempty"`
+	Host       string                 `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	Port       int64                  `protobuf:"varint,4,opt,name=port,proto3" json:"port,omitempty"`
+	Quorum     int64                  `protobuf:"varint,5,opt,name=quorum,proto3" json:"quorum,omitempty"`
+	// auth_user is the redis user sentinel authenticates to the master with,
+	// its password is read from the secret mount
+	AuthUser string `protobuf:"bytes,6,opt,name=auth_user,json=authUser,proto3" json:"auth_user,omitempty"`
+	// options are applied with SENTINEL SET once the master is monitored
+	Options       map[string]string `protobuf:"bytes,7,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MonitorRequest) Reset() {
+	*x = MonitorRequest{}
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MonitorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MonitorRequest) ProtoMessage() {}
+
+func (x *MonitorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MonitorRequest.ProtoReflect.Descriptor instead.
+func (*MonitorRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MonitorRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *MonitorRequest) GetMasterName() string {
+	if x != nil {
+		return x.MasterName
+	}
+	return ""
+}
+
+func (x *MonitorRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *MonitorRequest) GetPort() int64 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *MonitorRequest) GetQuorum() int64 {
+	if x != nil {
+		return x.Quorum
+	}
+	return 0
+}
+
+func (x *MonitorRequest) GetAuthUser() string {
+	if x != nil {
+		return x.AuthUser
+	}
+	return ""
+}
+
+func (x *MonitorRequest) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type MasterRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// master_name defaults to the configured master name
+	MasterName    string `protobuf:"bytes,2,opt,name=master_name,json=masterName,proto3" json:"master_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MasterRequest) Reset() {
+	*x = MasterRequest{}
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MasterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MasterRequest) ProtoMessage() {}
+
+func (x *MasterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MasterRequest.ProtoReflect.Descriptor instead.
+func (*MasterRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MasterRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *MasterRequest) GetMasterName() string {
+	if x != nil {
+		return x.MasterName
+	}
+	return ""
+}
+
+type ResetRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// pattern is a glob matched against the master names, it defaults to the
+	// configured master name
+	Pattern       string `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetRequest) Reset() {
+	*x = ResetRequest{}
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetRequest) ProtoMessage() {}
+
+func (x *ResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetRequest.ProtoReflect.Descriptor instead.
+func (*ResetRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResetRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ResetRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+type ResetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ResetMasters  int64                  `protobuf:"varint,1,opt,name=reset_masters,json=resetMasters,proto3" json:"reset_masters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetResponse) Reset() {
+	*x = ResetResponse{}
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetResponse) ProtoMessage() {}
+
+func (x *ResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetResponse.ProtoReflect.Descriptor instead.
+func (*ResetResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ResetResponse) GetResetMasters() int64 {
+	if x != nil {
+		return x.ResetMasters
+	}
+	return 0
+}
+
+type ListMastersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMastersRequest) Reset() {
+	*x = ListMastersRequest{}
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMastersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMastersRequest) ProtoMessage() {}
+
+func (x *ListMastersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMastersRequest.ProtoReflect.Descriptor instead.
+func (*ListMastersRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListMastersRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type SentinelInstance struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Host  string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Port  int64                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Flags []string               `protobuf:"bytes,4,rep,name=flags,proto3" json:"flags,omitempty"`
+	// fields holds every field reported by sentinel for the instance
+	Fields        map[string]string `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SentinelInstance) Reset() {
+	*x = SentinelInstance{}
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SentinelInstance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SentinelInstance) ProtoMessage() {}
+
+func (x *SentinelInstance) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SentinelInstance.ProtoReflect.Descriptor instead.
+func (*SentinelInstance) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SentinelInstance) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SentinelInstance) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *SentinelInstance) GetPort() int64 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *SentinelInstance) GetFlags() []string {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *SentinelInstance) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ListMastersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Masters       []*SentinelInstance    `protobuf:"bytes,1,rep,name=masters,proto3" json:"masters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMastersResponse) Reset() {
+	*x = ListMastersResponse{}
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMastersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMastersResponse) ProtoMessage() {}
+
+func (x *ListMastersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMastersResponse.ProtoReflect.Descriptor instead.
+func (*ListMastersResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListMastersResponse) GetMasters() []*SentinelInstance {
+	if x != nil {
+		return x.Masters
+	}
+	return nil
+}
+
+type ListReplicasResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Replicas      []*SentinelInstance    `protobuf:"bytes,1,rep,name=replicas,proto3" json:"replicas,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReplicasResponse) Reset() {
+	*x = ListReplicasResponse{}
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReplicasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReplicasResponse) ProtoMessage() {}
+
+func (x *ListReplicasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReplicasResponse.ProtoReflect.Descriptor instead.
+func (*ListReplicasResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListReplicasResponse) GetReplicas() []*SentinelInstance {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
 var File_pkg_agent_app_sentinel_pb_sentinel_proto protoreflect.FileDescriptor
 
 const file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDesc = "" +
@@ -170,14 +703,68 @@ const file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDesc = "" +
 	"sourceHost\x12\x1f\n" +
 	"\vsource_port\x18\x04 \x01(\x03R\n" +
 	"sourcePort\x12$\n" +
-	"\x0eself_unit_name\x18\x05 \x01(\tR\fselfUnitName\"X\n" +
+	"\x0eself_unit_name\x18\x05 \x01(\tR\fselfUnitName\"y\n" +
 	"\x12SetVariableRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1a\n" +
-	"\busername\x18\x03 \x01(\tR\busername2\xa1\x01\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x1f\n" +
+	"\vmaster_name\x18\x04 \x01(\tR\n" +
+	"masterName\"\xf5\x01\n" +
+	"\x13SetVariablesRequest\x12J\n" +
+	"\tvariables\x18\x01 \x03(\v2,.sentinel.SetVariablesRequest.VariablesEntryR\tvariables\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12\x1f\n" +
+	"\vmaster_name\x18\x04 \x01(\tR\n" +
+	"masterName\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa7\x02\n" +
+	"\x0eMonitorRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1f\n" +
+	"\vmaster_name\x18\x02 \x01(\tR\n" +
+	"masterName\x12\x12\n" +
+	"\x04host\x18\x03 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x04 \x01(\x03R\x04port\x12\x16\n" +
+	"\x06quorum\x18\x05 \x01(\x03R\x06quorum\x12\x1b\n" +
+	"\tauth_user\x18\x06 \x01(\tR\bauthUser\x12?\n" +
+	"\aoptions\x18\a \x03(\v2%.sentinel.MonitorRequest.OptionsEntryR\aoptions\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"L\n" +
+	"\rMasterRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1f\n" +
+	"\vmaster_name\x18\x02 \x01(\tR\n" +
+	"masterName\"D\n" +
+	"\fResetRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x18\n" +
+	"\apattern\x18\x02 \x01(\tR\apattern\"4\n" +
+	"\rResetResponse\x12#\n" +
+	"\rreset_masters\x18\x01 \x01(\x03R\fresetMasters\"0\n" +
+	"\x12ListMastersRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"\xdf\x01\n" +
+	"\x10SentinelInstance\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\x12\x12\n" +
+	"\x04port\x18\x03 \x01(\x03R\x04port\x12\x14\n" +
+	"\x05flags\x18\x04 \x03(\tR\x05flags\x12>\n" +
+	"\x06fields\x18\x05 \x03(\v2&.sentinel.SentinelInstance.FieldsEntryR\x06fields\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"K\n" +
+	"\x13ListMastersResponse\x124\n" +
+	"\amasters\x18\x01 \x03(\v2\x1a.sentinel.SentinelInstanceR\amasters\"N\n" +
+	"\x14ListReplicasResponse\x126\n" +
+	"\breplicas\x18\x01 \x03(\v2\x1a.sentinel.SentinelInstanceR\breplicas2\xe5\x04\n" +
 	"\x11SentinelOperation\x12P\n" +
-	"\x16UpdateRedisReplication\x12'.sentinel.UpdateRedisReplicationRequest\x1a\r.common.Empty\x12:\n" +
-	"\vSetVariable\x12\x1c.sentinel.SetVariableRequest\x1a\r.common.EmptyB7Z5github.com/upmio/unit-operator/pkg/agent/app/sentinelb\x06proto3"
+	"\x16UpdateRedisReplication\x12'.sentinel.UpdateRedisReplicationRequest\x1a\r.common.Empty\x12H\n" +
+	"\vSetVariable\x12\x1c.sentinel.SetVariableRequest\x1a\x1b.common.SetVariableResponse\x12K\n" +
+	"\fSetVariables\x12\x1d.sentinel.SetVariablesRequest\x1a\x1c.common.SetVariablesResponse\x122\n" +
+	"\aMonitor\x12\x18.sentinel.MonitorRequest\x1a\r.common.Empty\x120\n" +
+	"\x06Remove\x12\x17.sentinel.MasterRequest\x1a\r.common.Empty\x128\n" +
+	"\x05Reset\x12\x16.sentinel.ResetRequest\x1a\x17.sentinel.ResetResponse\x12J\n" +
+	"\vListMasters\x12\x1c.sentinel.ListMastersRequest\x1a\x1d.sentinel.ListMastersResponse\x12G\n" +
+	"\fListReplicas\x12\x17.sentinel.MasterRequest\x1a\x1e.sentinel.ListReplicasResponse\x122\n" +
+	"\bFailover\x12\x17.sentinel.MasterRequest\x1a\r.common.EmptyB7Z5github.com/upmio/unit-operator/pkg/agent/app/sentinelb\x06proto3"
 
 var (
 	file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescOnce sync.Once
@@ -191,22 +778,55 @@ func file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescGZIP() []byte {
 	return file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDescData
 }
 
-var file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pkg_agent_app_sentinel_pb_sentinel_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_pkg_agent_app_sentinel_pb_sentinel_proto_goTypes = []any{
 	(*UpdateRedisReplicationRequest)(nil), // 0: sentinel.UpdateRedisReplicationRequest
 	(*SetVariableRequest)(nil),            // 1: sentinel.SetVariableRequest
-	(*common.Empty)(nil),                  // 2: common.Empty
+	(*SetVariablesRequest)(nil),           // 2: sentinel.SetVariablesRequest
+	(*MonitorRequest)(nil),                // 3: sentinel.MonitorRequest
+	(*MasterRequest)(nil),                 // 4: sentinel.MasterRequest
+	(*ResetRequest)(nil),                  // 5: sentinel.ResetRequest
+	(*ResetResponse)(nil),                 // 6: sentinel.ResetResponse
+	(*ListMastersRequest)(nil),            // 7: sentinel.ListMastersRequest
+	(*SentinelInstance)(nil),              // 8: sentinel.SentinelInstance
+	(*ListMastersResponse)(nil),           // 9: sentinel.ListMastersResponse
+	(*ListReplicasResponse)(nil),          // 10: sentinel.ListReplicasResponse
+	nil,                                   // 11: sentinel.SetVariablesRequest.VariablesEntry
+	nil,                                   // 12: sentinel.MonitorRequest.OptionsEntry
+	nil,                                   // 13: sentinel.SentinelInstance.FieldsEntry
+	(*common.Empty)(nil),                  // 14: common.Empty
+	(*common.SetVariableResponse)(nil),    // 15: common.SetVariableResponse
+	(*common.SetVariablesResponse)(nil),   // 16: common.SetVariablesResponse
 }
 var file_pkg_agent_app_sentinel_pb_sentinel_proto_depIdxs = []int32{
-	0, // 0: sentinel.SentinelOperation.UpdateRedisReplication:input_type -> sentinel.UpdateRedisReplicationRequest
-	1, // 1: sentinel.SentinelOperation.SetVariable:input_type -> sentinel.SetVariableRequest
-	2, // 2: sentinel.SentinelOperation.UpdateRedisReplication:output_type -> common.Empty
-	2, // 3: sentinel.SentinelOperation.SetVariable:output_type -> common.Empty
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	11, // 0: sentinel.SetVariablesRequest.variables:type_name -> sentinel.SetVariablesRequest.VariablesEntry
+	12, // 1: sentinel.MonitorRequest.options:type_name -> sentinel.MonitorRequest.OptionsEntry
+	13, // 2: sentinel.SentinelInstance.fields:type_name -> sentinel.SentinelInstance.FieldsEntry
+	8,  // 3: sentinel.ListMastersResponse.masters:type_name -> sentinel.SentinelInstance
+	8,  // 4: sentinel.ListReplicasResponse.replicas:type_name -> sentinel.SentinelInstance
+	0,  // 5: sentinel.SentinelOperation.UpdateRedisReplication:input_type -> sentinel.UpdateRedisReplicationRequest
+	1,  // 6: sentinel.SentinelOperation.SetVariable:input_type -> sentinel.SetVariableRequest
+	2,  // 7: sentinel.SentinelOperation.SetVariables:input_type -> sentinel.SetVariablesRequest
+	3,  // 8: sentinel.SentinelOperation.Monitor:input_type -> sentinel.MonitorRequest
+	4,  // 9: sentinel.SentinelOperation.Remove:input_type -> sentinel.MasterRequest
+	5,  // 10: sentinel.SentinelOperation.Reset:input_type -> sentinel.ResetRequest
+	7,  // 11: sentinel.SentinelOperation.ListMasters:input_type -> sentinel.ListMastersRequest
+	4,  // 12: sentinel.SentinelOperation.ListReplicas:input_type -> sentinel.MasterRequest
+	4,  // 13: sentinel.SentinelOperation.Failover:input_type -> sentinel.MasterRequest
+	14, // 14: sentinel.SentinelOperation.UpdateRedisReplication:output_type -> common.Empty
+	15, // 15: sentinel.SentinelOperation.SetVariable:output_type -> common.SetVariableResponse
+	16, // 16: sentinel.SentinelOperation.SetVariables:output_type -> common.SetVariablesResponse
+	14, // 17: sentinel.SentinelOperation.Monitor:output_type -> common.Empty
+	14, // 18: sentinel.SentinelOperation.Remove:output_type -> common.Empty
+	6,  // 19: sentinel.SentinelOperation.Reset:output_type -> sentinel.ResetResponse
+	9,  // 20: sentinel.SentinelOperation.ListMasters:output_type -> sentinel.ListMastersResponse
+	10, // 21: sentinel.SentinelOperation.ListReplicas:output_type -> sentinel.ListReplicasResponse
+	14, // 22: sentinel.SentinelOperation.Failover:output_type -> common.Empty
+	14, // [14:23] is the sub-list for method output_type
+	5,  // [5:14] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_pkg_agent_app_sentinel_pb_sentinel_proto_init() }
@@ -220,7 +840,7 @@ func file_pkg_agent_app_sentinel_pb_sentinel_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDesc), len(file_pkg_agent_app_sentinel_pb_sentinel_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
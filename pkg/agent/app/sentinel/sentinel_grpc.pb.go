@@ -20,7 +20,14 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SentinelOperationClient interface {
 	UpdateRedisReplication(ctx context.Context, in *UpdateRedisReplicationRequest, opts ...grpc.CallOption) (*common.Empty, error)
-	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error)
+	SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error)
+	Monitor(ctx context.Context, in *MonitorRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	Remove(ctx context.Context, in *MasterRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error)
+	ListMasters(ctx context.Context, in *ListMastersRequest, opts ...grpc.CallOption) (*ListMastersResponse, error)
+	ListReplicas(ctx context.Context, in *MasterRequest, opts ...grpc.CallOption) (*ListReplicasResponse, error)
+	Failover(ctx context.Context, in *MasterRequest, opts ...grpc.CallOption) (*common.Empty, error)
 }
 
 type sentinelOperationClient struct {
@@ -40,8 +47,8 @@ func (c *sentinelOperationClient) UpdateRedisReplication(ctx context.Context, in
 	return out, nil
 }
 
-func (c *sentinelOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.Empty, error) {
-	out := new(common.Empty)
+func (c *sentinelOperationClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*common.SetVariableResponse, error) {
+	out := new(common.SetVariableResponse)
 	err := c.cc.Invoke(ctx, "/sentinel.SentinelOperation/SetVariable", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -49,12 +56,82 @@ func (c *sentinelOperationClient) SetVariable(ctx context.Context, in *SetVariab
 	return out, nil
 }
 
+func (c *sentinelOperationClient) SetVariables(ctx context.Context, in *SetVariablesRequest, opts ...grpc.CallOption) (*common.SetVariablesResponse, error) {
+	out := new(common.SetVariablesResponse)
+	err := c.cc.Invoke(ctx, "/sentinel.SentinelOperation/SetVariables", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sentinelOperationClient) Monitor(ctx context.Context, in *MonitorRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/sentinel.SentinelOperation/Monitor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sentinelOperationClient) Remove(ctx context.Context, in *MasterRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/sentinel.SentinelOperation/Remove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sentinelOperationClient) Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error) {
+	out := new(ResetResponse)
+	err := c.cc.Invoke(ctx, "/sentinel.SentinelOperation/Reset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sentinelOperationClient) ListMasters(ctx context.Context, in *ListMastersRequest, opts ...grpc.CallOption) (*ListMastersResponse, error) {
+	out := new(ListMastersResponse)
+	err := c.cc.Invoke(ctx, "/sentinel.SentinelOperation/ListMasters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sentinelOperationClient) ListReplicas(ctx context.Context, in *MasterRequest, opts ...grpc.CallOption) (*ListReplicasResponse, error) {
+	out := new(ListReplicasResponse)
+	err := c.cc.Invoke(ctx, "/sentinel.SentinelOperation/ListReplicas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sentinelOperationClient) Failover(ctx context.Context, in *MasterRequest, opts ...grpc.CallOption) (*common.Empty, error) {
+	out := new(common.Empty)
+	err := c.cc.Invoke(ctx, "/sentinel.SentinelOperation/Failover", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SentinelOperationServer is the server API for SentinelOperation service.
 // All implementations must embed UnimplementedSentinelOperationServer
 // for forward compatibility
 type SentinelOperationServer interface {
 	UpdateRedisReplication(context.Context, *UpdateRedisReplicationRequest) (*common.Empty, error)
-	SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error)
+	SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error)
+	SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error)
+	Monitor(context.Context, *MonitorRequest) (*common.Empty, error)
+	Remove(context.Context, *MasterRequest) (*common.Empty, error)
+	Reset(context.Context, *ResetRequest) (*ResetResponse, error)
+	ListMasters(context.Context, *ListMastersRequest) (*ListMastersResponse, error)
+	ListReplicas(context.Context, *MasterRequest) (*ListReplicasResponse, error)
+	Failover(context.Context, *MasterRequest) (*common.Empty, error)
 	mustEmbedUnimplementedSentinelOperationServer()
 }
 
@@ -65,9 +142,30 @@ type UnimplementedSentinelOperationServer struct {
 func (UnimplementedSentinelOperationServer) UpdateRedisReplication(context.Context, *UpdateRedisReplicationRequest) (*common.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateRedisReplication not implemented")
 }
-func (UnimplementedSentinelOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.Empty, error) {
+func (UnimplementedSentinelOperationServer) SetVariable(context.Context, *SetVariableRequest) (*common.SetVariableResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetVariable not implemented")
 }
+func (UnimplementedSentinelOperationServer) SetVariables(context.Context, *SetVariablesRequest) (*common.SetVariablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVariables not implemented")
+}
+func (UnimplementedSentinelOperationServer) Monitor(context.Context, *MonitorRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Monitor not implemented")
+}
+func (UnimplementedSentinelOperationServer) Remove(context.Context, *MasterRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Remove not implemented")
+}
+func (UnimplementedSentinelOperationServer) Reset(context.Context, *ResetRequest) (*ResetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reset not implemented")
+}
+func (UnimplementedSentinelOperationServer) ListMasters(context.Context, *ListMastersRequest) (*ListMastersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMasters not implemented")
+}
+func (UnimplementedSentinelOperationServer) ListReplicas(context.Context, *MasterRequest) (*ListReplicasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReplicas not implemented")
+}
+func (UnimplementedSentinelOperationServer) Failover(context.Context, *MasterRequest) (*common.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Failover not implemented")
+}
 func (UnimplementedSentinelOperationServer) mustEmbedUnimplementedSentinelOperationServer() {}
 
 // UnsafeSentinelOperationServer may be embedded to opt out of forward compatibility for this service.
@@ -117,6 +215,132 @@ func _SentinelOperation_SetVariable_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SentinelOperation_SetVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVariablesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentinelOperationServer).SetVariables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sentinel.SentinelOperation/SetVariables",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentinelOperationServer).SetVariables(ctx, req.(*SetVariablesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SentinelOperation_Monitor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MonitorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentinelOperationServer).Monitor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sentinel.SentinelOperation/Monitor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentinelOperationServer).Monitor(ctx, req.(*MonitorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SentinelOperation_Remove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MasterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentinelOperationServer).Remove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sentinel.SentinelOperation/Remove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentinelOperationServer).Remove(ctx, req.(*MasterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SentinelOperation_Reset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentinelOperationServer).Reset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sentinel.SentinelOperation/Reset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentinelOperationServer).Reset(ctx, req.(*ResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SentinelOperation_ListMasters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMastersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentinelOperationServer).ListMasters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sentinel.SentinelOperation/ListMasters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentinelOperationServer).ListMasters(ctx, req.(*ListMastersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SentinelOperation_ListReplicas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MasterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentinelOperationServer).ListReplicas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sentinel.SentinelOperation/ListReplicas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentinelOperationServer).ListReplicas(ctx, req.(*MasterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SentinelOperation_Failover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MasterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentinelOperationServer).Failover(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sentinel.SentinelOperation/Failover",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentinelOperationServer).Failover(ctx, req.(*MasterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SentinelOperation_ServiceDesc is the grpc.ServiceDesc for SentinelOperation service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -132,6 +356,34 @@ var SentinelOperation_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetVariable",
 			Handler:    _SentinelOperation_SetVariable_Handler,
 		},
+		{
+			MethodName: "SetVariables",
+			Handler:    _SentinelOperation_SetVariables_Handler,
+		},
+		{
+			MethodName: "Monitor",
+			Handler:    _SentinelOperation_Monitor_Handler,
+		},
+		{
+			MethodName: "Remove",
+			Handler:    _SentinelOperation_Remove_Handler,
+		},
+		{
+			MethodName: "Reset",
+			Handler:    _SentinelOperation_Reset_Handler,
+		},
+		{
+			MethodName: "ListMasters",
+			Handler:    _SentinelOperation_ListMasters_Handler,
+		},
+		{
+			MethodName: "ListReplicas",
+			Handler:    _SentinelOperation_ListReplicas_Handler,
+		},
+		{
+			MethodName: "Failover",
+			Handler:    _SentinelOperation_Failover_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/agent/app/sentinel/pb/sentinel.proto",
@@ -3,14 +3,19 @@ package slm
 import (
 	"context"
 	"fmt"
+	"syscall"
+	"time"
 
 	"github.com/abrander/go-supervisord"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/upmio/unit-operator/pkg/agent/app"
 	"github.com/upmio/unit-operator/pkg/agent/app/common"
 	"github.com/upmio/unit-operator/pkg/agent/conf"
 	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -22,6 +27,7 @@ type supervisorClient interface {
 	GetProcessInfo(string) (*supervisord.ProcessInfo, error)
 	StartProcess(string, bool) error
 	StopProcess(string, bool) error
+	SignalProcess(string, syscall.Signal) error
 }
 
 type service struct {
@@ -35,6 +41,12 @@ const (
 	processName = "unit_app"
 )
 
+// stopPollInterval is how often a graceful stop checks whether the process stopped.
+var stopPollInterval = time.Second
+
+// stopKillTimeout is how long a process killed after its graceful timeout is given to exit.
+var stopKillTimeout = 10 * time.Second
+
 func (s *service) Config() error {
 	s.service = app.GetGrpcApp(appName).(ServiceLifecycleServer)
 	s.logger = zap.L().Named(appName).Sugar()
@@ -56,6 +68,10 @@ func (s *service) Registry(server *grpc.Server) {
 	RegisterServiceLifecycleServer(server, svr)
 }
 
+func (s *service) RegistryGateway(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterServiceLifecycleHandler(ctx, mux, conn)
+}
+
 func (s *service) StartProcess(_ context.Context, _ *common.Empty) (*common.Empty, error) {
 	util.LogRequestSafely(s.logger, "start process", map[string]interface{}{})
 
@@ -83,24 +99,12 @@ func (s *service) StartProcess(_ context.Context, _ *common.Empty) (*common.Empt
 	return nil, nil
 }
 
-func (s *service) StopProcess(_ context.Context, _ *common.Empty) (*common.Empty, error) {
-	util.LogRequestSafely(s.logger, "stop process", map[string]interface{}{})
+func (s *service) StopProcess(ctx context.Context, req *StopProcessRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "stop process", map[string]interface{}{
+		"graceful_timeout_seconds": req.GetGracefulTimeoutSeconds(),
+	})
 
-	// Get process information
-	pi, err := s.getProcessInfo()
-	if err != nil {
-		s.logger.Error(err)
-		return nil, err
-	}
-
-	// Check if process is already stopped
-	if pi.State == supervisord.StateStopped {
-		s.logger.Info("process is already stopped, no stop needed")
-		return nil, nil
-	}
-
-	// Stop process
-	if err = s.client.StopProcess(processName, true); err != nil {
+	if err := s.stopProcess(ctx, time.Duration(req.GetGracefulTimeoutSeconds())*time.Second); err != nil {
 		s.logger.Errorw("failed to stop process", zap.Error(err))
 		return nil, err
 	}
@@ -110,16 +114,18 @@ func (s *service) StopProcess(_ context.Context, _ *common.Empty) (*common.Empty
 	return nil, nil
 }
 
-func (s *service) RestartProcess(ctx context.Context, empty *common.Empty) (*common.Empty, error) {
-	util.LogRequestSafely(s.logger, "restart process", map[string]interface{}{})
+func (s *service) RestartProcess(ctx context.Context, req *RestartProcessRequest) (*common.Empty, error) {
+	util.LogRequestSafely(s.logger, "restart process", map[string]interface{}{
+		"graceful_timeout_seconds": req.GetGracefulTimeoutSeconds(),
+	})
 
 	// Stop the process
-	if _, err := s.StopProcess(ctx, empty); err != nil {
+	if _, err := s.StopProcess(ctx, &StopProcessRequest{GracefulTimeoutSeconds: req.GetGracefulTimeoutSeconds()}); err != nil {
 		return nil, err
 	}
 
 	// Start the service
-	if _, err := s.StartProcess(ctx, empty); err != nil {
+	if _, err := s.StartProcess(ctx, &common.Empty{}); err != nil {
 		return nil, err
 	}
 
@@ -154,6 +160,104 @@ func (s *service) CheckProcessStopped(_ context.Context, _ *common.Empty) (*comm
 	return nil, nil
 }
 
+func (s *service) ProcessStatus(_ context.Context, _ *common.Empty) (*ProcessStatusResponse, error) {
+	pi, err := s.getProcessInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ProcessStatusResponse{
+		State:      pi.StateName,
+		Pid:        int64(pi.Pid),
+		ExitStatus: int64(pi.ExitStatus),
+		SpawnError: pi.SpawnErr,
+	}
+	if pi.State == supervisord.StateRunning && pi.Start > 0 {
+		resp.UptimeSeconds = int64(pi.Now - pi.Start)
+	}
+
+	return resp, nil
+}
+
+// stopProcess stops unit_app, giving it timeout to stop gracefully when timeout is set
+// and killing it once the timeout elapsed.
+func (s *service) stopProcess(ctx context.Context, timeout time.Duration) error {
+	pi, err := s.getProcessInfo()
+	if err != nil {
+		return err
+	}
+
+	// Check if process is already stopped
+	if isProcessStopped(pi.State) {
+		s.logger.Info("process is already stopped, no stop needed")
+		return nil
+	}
+
+	if timeout <= 0 {
+		return s.client.StopProcess(processName, true)
+	}
+
+	if err := s.client.StopProcess(processName, false); err != nil {
+		return err
+	}
+
+	pi, err = s.waitProcessStopped(ctx, timeout)
+	if err != nil || isProcessStopped(pi.State) {
+		return err
+	}
+
+	s.logger.Warnw("process did not stop gracefully, killing it", "timeout", timeout, "state", pi.StateName)
+	if err := s.client.SignalProcess(processName, syscall.SIGKILL); err != nil {
+		// the process may have exited between the last poll and the signal
+		if pi, perr := s.getProcessInfo(); perr == nil && isProcessStopped(pi.State) {
+			return nil
+		}
+		return fmt.Errorf("failed to kill process: %v", err)
+	}
+
+	pi, err = s.waitProcessStopped(ctx, stopKillTimeout)
+	if err != nil {
+		return err
+	}
+	if !isProcessStopped(pi.State) {
+		return status.Errorf(codes.DeadlineExceeded, "process did not stop within %s after being killed, current status is %s", stopKillTimeout, pi.StateName)
+	}
+	return nil
+}
+
+// waitProcessStopped polls unit_app until it stopped or timeout elapsed, returning its last process information.
+func (s *service) waitProcessStopped(ctx context.Context, timeout time.Duration) (*supervisord.ProcessInfo, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(stopPollInterval)
+	defer ticker.Stop()
+
+	for {
+		pi, err := s.getProcessInfo()
+		if err != nil {
+			return nil, err
+		}
+		if isProcessStopped(pi.State) {
+			return pi, nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return pi, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// isProcessStopped reports whether the process is not running and not being started or stopped.
+func isProcessStopped(state supervisord.ProcessState) bool {
+	return state == supervisord.StateStopped || state == supervisord.StateExited || state == supervisord.StateFatal
+}
+
 // getProcessInfo gets the process information for unit_app
 func (s *service) getProcessInfo() (*supervisord.ProcessInfo, error) {
 	pi, err := s.client.GetProcessInfo(processName)
@@ -3,11 +3,15 @@ package slm
 import (
 	"context"
 	"errors"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/abrander/go-supervisord"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type fakeClient struct {
@@ -18,6 +22,10 @@ type fakeClient struct {
 	stopErr    error
 	startCalls int
 	stopCalls  int
+	stopWait   []bool
+	signals    []syscall.Signal
+	// afterKill replaces infos once the process is signalled
+	afterKill []*supervisord.ProcessInfo
 }
 
 func (f *fakeClient) nextInfo() *supervisord.ProcessInfo {
@@ -39,9 +47,18 @@ func (f *fakeClient) StartProcess(name string, wait bool) error {
 
 func (f *fakeClient) StopProcess(name string, wait bool) error {
 	f.stopCalls++
+	f.stopWait = append(f.stopWait, wait)
 	return f.stopErr
 }
 
+func (f *fakeClient) SignalProcess(name string, signal syscall.Signal) error {
+	f.signals = append(f.signals, signal)
+	if f.afterKill != nil {
+		f.infos, f.infoIdx = f.afterKill, 0
+	}
+	return nil
+}
+
 func (f *fakeClient) GetProcessInfo(name string) (*supervisord.ProcessInfo, error) {
 	if f.processErr != nil {
 		return nil, f.processErr
@@ -128,6 +145,78 @@ func TestStopProcessWhenRunning(t *testing.T) {
 	require.Equal(t, 1, fc.stopCalls)
 }
 
+func TestStopProcessGracefully(t *testing.T) {
+	defer func(interval time.Duration) { stopPollInterval = interval }(stopPollInterval)
+	stopPollInterval = time.Millisecond
+
+	fc := &fakeClient{
+		infos: []*supervisord.ProcessInfo{
+			{State: supervisord.StateRunning},
+			{State: supervisord.StateStopping},
+			{State: supervisord.StateStopped},
+		},
+	}
+	svc := newServiceWithClient(fc)
+	_, err := svc.StopProcess(context.Background(), &StopProcessRequest{GracefulTimeoutSeconds: 5})
+	require.NoError(t, err)
+	require.Equal(t, []bool{false}, fc.stopWait)
+}
+
+func TestStopProcessGracefulTimeoutKills(t *testing.T) {
+	defer func(interval time.Duration) { stopPollInterval = interval }(stopPollInterval)
+	stopPollInterval = 100 * time.Millisecond
+
+	fc := &fakeClient{
+		infos:     []*supervisord.ProcessInfo{{State: supervisord.StateStopping, StateName: "STOPPING"}},
+		afterKill: []*supervisord.ProcessInfo{{State: supervisord.StateStopped, StateName: "STOPPED"}},
+	}
+	svc := newServiceWithClient(fc)
+	_, err := svc.StopProcess(context.Background(), &StopProcessRequest{GracefulTimeoutSeconds: 1})
+	require.NoError(t, err)
+	require.Equal(t, []bool{false}, fc.stopWait)
+	require.Equal(t, []syscall.Signal{syscall.SIGKILL}, fc.signals)
+}
+
+func TestStopProcessKillTimeout(t *testing.T) {
+	defer func(interval, kill time.Duration) {
+		stopPollInterval, stopKillTimeout = interval, kill
+	}(stopPollInterval, stopKillTimeout)
+	stopPollInterval = 100 * time.Millisecond
+	stopKillTimeout = 300 * time.Millisecond
+
+	fc := &fakeClient{
+		infos: []*supervisord.ProcessInfo{{State: supervisord.StateStopping, StateName: "STOPPING"}},
+	}
+	svc := newServiceWithClient(fc)
+	_, err := svc.StopProcess(context.Background(), &StopProcessRequest{GracefulTimeoutSeconds: 1})
+	require.Error(t, err)
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	require.Contains(t, err.Error(), "current status is STOPPING")
+	require.Equal(t, []syscall.Signal{syscall.SIGKILL}, fc.signals)
+}
+
+func TestRestartProcess(t *testing.T) {
+	fc := &fakeClient{
+		infos: []*supervisord.ProcessInfo{{State: supervisord.StateRunning}, {State: supervisord.StateStopped}},
+	}
+	svc := newServiceWithClient(fc)
+	_, err := svc.RestartProcess(context.Background(), &RestartProcessRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 1, fc.stopCalls)
+	require.Equal(t, 1, fc.startCalls)
+}
+
+func TestProcessStatus(t *testing.T) {
+	svc := newServiceWithClient(&fakeClient{
+		infos: []*supervisord.ProcessInfo{{State: supervisord.StateRunning, StateName: "RUNNING", Pid: 42, Start: 1000, Now: 1060}},
+	})
+	resp, err := svc.ProcessStatus(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, "RUNNING", resp.GetState())
+	require.Equal(t, int64(42), resp.GetPid())
+	require.Equal(t, int64(60), resp.GetUptimeSeconds())
+}
+
 func TestGetProcessInfoError(t *testing.T) {
 	svc := newServiceWithClient(&fakeClient{processErr: errors.New("fail")})
 	_, err := svc.getProcessInfo()
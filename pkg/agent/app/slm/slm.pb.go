@@ -11,6 +11,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
+	sync "sync"
 	unsafe "unsafe"
 )
 
@@ -21,34 +22,236 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type StopProcessRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// seconds the process is given to stop gracefully before it is killed with
+	// SIGKILL. 0 waits for supervisord to stop it, killing it after its
+	// stopwaitsecs.
+	GracefulTimeoutSeconds int32 `protobuf:"varint,1,opt,name=graceful_timeout_seconds,json=gracefulTimeoutSeconds,proto3" json:"graceful_timeout_seconds,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *StopProcessRequest) Reset() {
+	*x = StopProcessRequest{}
+	mi := &file_pkg_agent_app_slm_pb_slm_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopProcessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopProcessRequest) ProtoMessage() {}
+
+func (x *StopProcessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_slm_pb_slm_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopProcessRequest.ProtoReflect.Descriptor instead.
+func (*StopProcessRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_slm_pb_slm_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StopProcessRequest) GetGracefulTimeoutSeconds() int32 {
+	if x != nil {
+		return x.GracefulTimeoutSeconds
+	}
+	return 0
+}
+
+type RestartProcessRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// seconds the process is given to stop gracefully before it is killed with
+	// SIGKILL. 0 waits for supervisord to stop it, killing it after its
+	// stopwaitsecs.
+	GracefulTimeoutSeconds int32 `protobuf:"varint,1,opt,name=graceful_timeout_seconds,json=gracefulTimeoutSeconds,proto3" json:"graceful_timeout_seconds,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *RestartProcessRequest) Reset() {
+	*x = RestartProcessRequest{}
+	mi := &file_pkg_agent_app_slm_pb_slm_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartProcessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartProcessRequest) ProtoMessage() {}
+
+func (x *RestartProcessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_slm_pb_slm_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartProcessRequest.ProtoReflect.Descriptor instead.
+func (*RestartProcessRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_slm_pb_slm_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RestartProcessRequest) GetGracefulTimeoutSeconds() int32 {
+	if x != nil {
+		return x.GracefulTimeoutSeconds
+	}
+	return 0
+}
+
+type ProcessStatusResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// supervisord state name, e.g. RUNNING, STOPPED, EXITED or FATAL
+	State         string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Pid           int64  `protobuf:"varint,2,opt,name=pid,proto3" json:"pid,omitempty"`
+	UptimeSeconds int64  `protobuf:"varint,3,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	ExitStatus    int64  `protobuf:"varint,4,opt,name=exit_status,json=exitStatus,proto3" json:"exit_status,omitempty"`
+	SpawnError    string `protobuf:"bytes,5,opt,name=spawn_error,json=spawnError,proto3" json:"spawn_error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessStatusResponse) Reset() {
+	*x = ProcessStatusResponse{}
+	mi := &file_pkg_agent_app_slm_pb_slm_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessStatusResponse) ProtoMessage() {}
+
+func (x *ProcessStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agent_app_slm_pb_slm_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessStatusResponse.ProtoReflect.Descriptor instead.
+func (*ProcessStatusResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_agent_app_slm_pb_slm_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProcessStatusResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ProcessStatusResponse) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *ProcessStatusResponse) GetUptimeSeconds() int64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+func (x *ProcessStatusResponse) GetExitStatus() int64 {
+	if x != nil {
+		return x.ExitStatus
+	}
+	return 0
+}
+
+func (x *ProcessStatusResponse) GetSpawnError() string {
+	if x != nil {
+		return x.SpawnError
+	}
+	return ""
+}
+
 var File_pkg_agent_app_slm_pb_slm_proto protoreflect.FileDescriptor
 
 const file_pkg_agent_app_slm_pb_slm_proto_rawDesc = "" +
 	"\n" +
-	"\x1epkg/agent/app/slm/pb/slm.proto\x12\aservice\x1a$pkg/agent/app/common/pb/common.proto2\x87\x02\n" +
+	"\x1epkg/agent/app/slm/pb/slm.proto\x12\aservice\x1a$pkg/agent/app/common/pb/common.proto\"N\n" +
+	"\x12StopProcessRequest\x128\n" +
+	"\x18graceful_timeout_seconds\x18\x01 \x01(\x05R\x16gracefulTimeoutSeconds\"Q\n" +
+	"\x15RestartProcessRequest\x128\n" +
+	"\x18graceful_timeout_seconds\x18\x01 \x01(\x05R\x16gracefulTimeoutSeconds\"\xa8\x01\n" +
+	"\x15ProcessStatusResponse\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12\x10\n" +
+	"\x03pid\x18\x02 \x01(\x03R\x03pid\x12%\n" +
+	"\x0euptime_seconds\x18\x03 \x01(\x03R\ruptimeSeconds\x12\x1f\n" +
+	"\vexit_status\x18\x04 \x01(\x03R\n" +
+	"exitStatus\x12\x1f\n" +
+	"\vspawn_error\x18\x05 \x01(\tR\n" +
+	"spawnError2\xe6\x02\n" +
 	"\x10ServiceLifecycle\x12,\n" +
-	"\fStartProcess\x12\r.common.Empty\x1a\r.common.Empty\x12+\n" +
-	"\vStopProcess\x12\r.common.Empty\x1a\r.common.Empty\x12.\n" +
-	"\x0eRestartProcess\x12\r.common.Empty\x1a\r.common.Empty\x123\n" +
+	"\fStartProcess\x12\r.common.Empty\x1a\r.common.Empty\x129\n" +
+	"\vStopProcess\x12\x1b.service.StopProcessRequest\x1a\r.common.Empty\x12?\n" +
+	"\x0eRestartProcess\x12\x1e.service.RestartProcessRequest\x1a\r.common.Empty\x123\n" +
 	"\x13CheckProcessStarted\x12\r.common.Empty\x1a\r.common.Empty\x123\n" +
-	"\x13CheckProcessStopped\x12\r.common.Empty\x1a\r.common.EmptyB2Z0github.com/upmio/unit-operator/pkg/agent/app/slmb\x06proto3"
+	"\x13CheckProcessStopped\x12\r.common.Empty\x1a\r.common.Empty\x12>\n" +
+	"\rProcessStatus\x12\r.common.Empty\x1a\x1e.service.ProcessStatusResponseB2Z0github.com/upmio/unit-operator/pkg/agent/app/slmb\x06proto3"
+
+var (
+	file_pkg_agent_app_slm_pb_slm_proto_rawDescOnce sync.Once
+	file_pkg_agent_app_slm_pb_slm_proto_rawDescData []byte
+)
+
+func file_pkg_agent_app_slm_pb_slm_proto_rawDescGZIP() []byte {
+	file_pkg_agent_app_slm_pb_slm_proto_rawDescOnce.Do(func() {
+		file_pkg_agent_app_slm_pb_slm_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_agent_app_slm_pb_slm_proto_rawDesc), len(file_pkg_agent_app_slm_pb_slm_proto_rawDesc)))
+	})
+	return file_pkg_agent_app_slm_pb_slm_proto_rawDescData
+}
 
+var file_pkg_agent_app_slm_pb_slm_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_pkg_agent_app_slm_pb_slm_proto_goTypes = []any{
-	(*common.Empty)(nil), // 0: common.Empty
+	(*StopProcessRequest)(nil),    // 0: service.StopProcessRequest
+	(*RestartProcessRequest)(nil), // 1: service.RestartProcessRequest
+	(*ProcessStatusResponse)(nil), // 2: service.ProcessStatusResponse
+	(*common.Empty)(nil),          // 3: common.Empty
 }
 var file_pkg_agent_app_slm_pb_slm_proto_depIdxs = []int32{
-	0, // 0: service.ServiceLifecycle.StartProcess:input_type -> common.Empty
-	0, // 1: service.ServiceLifecycle.StopProcess:input_type -> common.Empty
-	0, // 2: service.ServiceLifecycle.RestartProcess:input_type -> common.Empty
-	0, // 3: service.ServiceLifecycle.CheckProcessStarted:input_type -> common.Empty
-	0, // 4: service.ServiceLifecycle.CheckProcessStopped:input_type -> common.Empty
-	0, // 5: service.ServiceLifecycle.StartProcess:output_type -> common.Empty
-	0, // 6: service.ServiceLifecycle.StopProcess:output_type -> common.Empty
-	0, // 7: service.ServiceLifecycle.RestartProcess:output_type -> common.Empty
-	0, // 8: service.ServiceLifecycle.CheckProcessStarted:output_type -> common.Empty
-	0, // 9: service.ServiceLifecycle.CheckProcessStopped:output_type -> common.Empty
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
+	3, // 0: service.ServiceLifecycle.StartProcess:input_type -> common.Empty
+	0, // 1: service.ServiceLifecycle.StopProcess:input_type -> service.StopProcessRequest
+	1, // 2: service.ServiceLifecycle.RestartProcess:input_type -> service.RestartProcessRequest
+	3, // 3: service.ServiceLifecycle.CheckProcessStarted:input_type -> common.Empty
+	3, // 4: service.ServiceLifecycle.CheckProcessStopped:input_type -> common.Empty
+	3, // 5: service.ServiceLifecycle.ProcessStatus:input_type -> common.Empty
+	3, // 6: service.ServiceLifecycle.StartProcess:output_type -> common.Empty
+	3, // 7: service.ServiceLifecycle.StopProcess:output_type -> common.Empty
+	3, // 8: service.ServiceLifecycle.RestartProcess:output_type -> common.Empty
+	3, // 9: service.ServiceLifecycle.CheckProcessStarted:output_type -> common.Empty
+	3, // 10: service.ServiceLifecycle.CheckProcessStopped:output_type -> common.Empty
+	2, // 11: service.ServiceLifecycle.ProcessStatus:output_type -> service.ProcessStatusResponse
+	6, // [6:12] is the sub-list for method output_type
+	0, // [0:6] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -65,12 +268,13 @@ func file_pkg_agent_app_slm_pb_slm_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_agent_app_slm_pb_slm_proto_rawDesc), len(file_pkg_agent_app_slm_pb_slm_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   0,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_pkg_agent_app_slm_pb_slm_proto_goTypes,
 		DependencyIndexes: file_pkg_agent_app_slm_pb_slm_proto_depIdxs,
+		MessageInfos:      file_pkg_agent_app_slm_pb_slm_proto_msgTypes,
 	}.Build()
 	File_pkg_agent_app_slm_pb_slm_proto = out.File
 	file_pkg_agent_app_slm_pb_slm_proto_goTypes = nil
@@ -20,10 +20,11 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ServiceLifecycleClient interface {
 	StartProcess(ctx context.Context, in *common.Empty, opts ...grpc.CallOption) (*common.Empty, error)
-	StopProcess(ctx context.Context, in *common.Empty, opts ...grpc.CallOption) (*common.Empty, error)
-	RestartProcess(ctx context.Context, in *common.Empty, opts ...grpc.CallOption) (*common.Empty, error)
+	StopProcess(ctx context.Context, in *StopProcessRequest, opts ...grpc.CallOption) (*common.Empty, error)
+	RestartProcess(ctx context.Context, in *RestartProcessRequest, opts ...grpc.CallOption) (*common.Empty, error)
 	CheckProcessStarted(ctx context.Context, in *common.Empty, opts ...grpc.CallOption) (*common.Empty, error)
 	CheckProcessStopped(ctx context.Context, in *common.Empty, opts ...grpc.CallOption) (*common.Empty, error)
+	ProcessStatus(ctx context.Context, in *common.Empty, opts ...grpc.CallOption) (*ProcessStatusResponse, error)
 }
 
 type serviceLifecycleClient struct {
@@ -43,7 +44,7 @@ func (c *serviceLifecycleClient) StartProcess(ctx context.Context, in *common.Em
 	return out, nil
 }
 
-func (c *serviceLifecycleClient) StopProcess(ctx context.Context, in *common.Empty, opts ...grpc.CallOption) (*common.Empty, error) {
+func (c *serviceLifecycleClient) StopProcess(ctx context.Context, in *StopProcessRequest, opts ...grpc.CallOption) (*common.Empty, error) {
 	out := new(common.Empty)
 	err := c.cc.Invoke(ctx, "/service.ServiceLifecycle/StopProcess", in, out, opts...)
 	if err != nil {
@@ -52,7 +53,7 @@ func (c *serviceLifecycleClient) StopProcess(ctx context.Context, in *common.Emp
 	return out, nil
 }
 
-func (c *serviceLifecycleClient) RestartProcess(ctx context.Context, in *common.Empty, opts ...grpc.CallOption) (*common.Empty, error) {
+func (c *serviceLifecycleClient) RestartProcess(ctx context.Context, in *RestartProcessRequest, opts ...grpc.CallOption) (*common.Empty, error) {
 	out := new(common.Empty)
 	err := c.cc.Invoke(ctx, "/service.ServiceLifecycle/RestartProcess", in, out, opts...)
 	if err != nil {
@@ -79,15 +80,25 @@ func (c *serviceLifecycleClient) CheckProcessStopped(ctx context.Context, in *co
 	return out, nil
 }
 
+func (c *serviceLifecycleClient) ProcessStatus(ctx context.Context, in *common.Empty, opts ...grpc.CallOption) (*ProcessStatusResponse, error) {
+	out := new(ProcessStatusResponse)
+	err := c.cc.Invoke(ctx, "/service.ServiceLifecycle/ProcessStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ServiceLifecycleServer is the server API for ServiceLifecycle service.
 // All implementations must embed UnimplementedServiceLifecycleServer
 // for forward compatibility
 type ServiceLifecycleServer interface {
 	StartProcess(context.Context, *common.Empty) (*common.Empty, error)
-	StopProcess(context.Context, *common.Empty) (*common.Empty, error)
-	RestartProcess(context.Context, *common.Empty) (*common.Empty, error)
+	StopProcess(context.Context, *StopProcessRequest) (*common.Empty, error)
+	RestartProcess(context.Context, *RestartProcessRequest) (*common.Empty, error)
 	CheckProcessStarted(context.Context, *common.Empty) (*common.Empty, error)
 	CheckProcessStopped(context.Context, *common.Empty) (*common.Empty, error)
+	ProcessStatus(context.Context, *common.Empty) (*ProcessStatusResponse, error)
 	mustEmbedUnimplementedServiceLifecycleServer()
 }
 
@@ -98,10 +109,10 @@ type UnimplementedServiceLifecycleServer struct {
 func (UnimplementedServiceLifecycleServer) StartProcess(context.Context, *common.Empty) (*common.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StartProcess not implemented")
 }
-func (UnimplementedServiceLifecycleServer) StopProcess(context.Context, *common.Empty) (*common.Empty, error) {
+func (UnimplementedServiceLifecycleServer) StopProcess(context.Context, *StopProcessRequest) (*common.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopProcess not implemented")
 }
-func (UnimplementedServiceLifecycleServer) RestartProcess(context.Context, *common.Empty) (*common.Empty, error) {
+func (UnimplementedServiceLifecycleServer) RestartProcess(context.Context, *RestartProcessRequest) (*common.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RestartProcess not implemented")
 }
 func (UnimplementedServiceLifecycleServer) CheckProcessStarted(context.Context, *common.Empty) (*common.Empty, error) {
@@ -110,6 +121,9 @@ func (UnimplementedServiceLifecycleServer) CheckProcessStarted(context.Context,
 func (UnimplementedServiceLifecycleServer) CheckProcessStopped(context.Context, *common.Empty) (*common.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckProcessStopped not implemented")
 }
+func (UnimplementedServiceLifecycleServer) ProcessStatus(context.Context, *common.Empty) (*ProcessStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProcessStatus not implemented")
+}
 func (UnimplementedServiceLifecycleServer) mustEmbedUnimplementedServiceLifecycleServer() {}
 
 // UnsafeServiceLifecycleServer may be embedded to opt out of forward compatibility for this service.
@@ -142,7 +156,7 @@ func _ServiceLifecycle_StartProcess_Handler(srv interface{}, ctx context.Context
 }
 
 func _ServiceLifecycle_StopProcess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(common.Empty)
+	in := new(StopProcessRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -154,13 +168,13 @@ func _ServiceLifecycle_StopProcess_Handler(srv interface{}, ctx context.Context,
 		FullMethod: "/service.ServiceLifecycle/StopProcess",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ServiceLifecycleServer).StopProcess(ctx, req.(*common.Empty))
+		return srv.(ServiceLifecycleServer).StopProcess(ctx, req.(*StopProcessRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _ServiceLifecycle_RestartProcess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(common.Empty)
+	in := new(RestartProcessRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -172,7 +186,7 @@ func _ServiceLifecycle_RestartProcess_Handler(srv interface{}, ctx context.Conte
 		FullMethod: "/service.ServiceLifecycle/RestartProcess",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ServiceLifecycleServer).RestartProcess(ctx, req.(*common.Empty))
+		return srv.(ServiceLifecycleServer).RestartProcess(ctx, req.(*RestartProcessRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -213,6 +227,24 @@ func _ServiceLifecycle_CheckProcessStopped_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ServiceLifecycle_ProcessStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(common.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceLifecycleServer).ProcessStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.ServiceLifecycle/ProcessStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceLifecycleServer).ProcessStatus(ctx, req.(*common.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ServiceLifecycle_ServiceDesc is the grpc.ServiceDesc for ServiceLifecycle service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -240,6 +272,10 @@ var ServiceLifecycle_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckProcessStopped",
 			Handler:    _ServiceLifecycle_CheckProcessStopped_Handler,
 		},
+		{
+			MethodName: "ProcessStatus",
+			Handler:    _ServiceLifecycle_ProcessStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/agent/app/slm/pb/slm.proto",
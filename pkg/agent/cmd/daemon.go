@@ -33,6 +33,7 @@ import (
 
 	// Adding a new service requires import
 	_ "github.com/upmio/unit-operator/pkg/agent/app/config"
+	_ "github.com/upmio/unit-operator/pkg/agent/app/operation"
 	_ "github.com/upmio/unit-operator/pkg/agent/app/slm"
 )
 
@@ -121,6 +122,7 @@ var daemonCmd = &cobra.Command{
 
 			if arch == "cluster" {
 				rediscluster.RegistryDaemonApp()
+				rediscluster.RegistryGrpcApp()
 			}
 		case "redis-sentinel":
 			sentinel.RegistryGrpcApp()
@@ -158,11 +160,12 @@ var daemonCmd = &cobra.Command{
 
 func init() {
 	daemonCmd.PersistentFlags().StringVarP(&configPath, "file", "f", "/etc/unit-agent/config.toml", "Specify the config file path")
-	daemonCmd.PersistentFlags().BoolVarP(&httpEnable, "http-enable", "", false, "Specify whether http service need to start")
+	daemonCmd.PersistentFlags().BoolVarP(&httpEnable, "http-enable", "", false, "Specify whether the REST/JSON gateway of the grpc services need to start")
 }
 
 type service struct {
 	grpc   *protocol.GrpcService
+	http   *protocol.HttpService
 	logger *zap.SugaredLogger
 }
 
@@ -172,6 +175,10 @@ func newService() (*service, error) {
 		logger: zap.L().Named("init").Sugar(),
 	}
 
+	if httpEnable {
+		svr.http = protocol.NewHttpService()
+	}
+
 	return svr, nil
 }
 
@@ -179,6 +186,10 @@ func (s *service) start() error {
 	s.logger.Info(fmt.Sprintf("loaded grpc apps %s", app.LoadedGrpcApp()))
 	go s.grpc.Start()
 
+	if s.http != nil {
+		go s.http.Start()
+	}
+
 	return nil
 }
 
@@ -189,6 +200,9 @@ func (s *service) waitSign(ctx context.Context, wg *sync.WaitGroup) {
 		case <-ctx.Done():
 			zap.L().Named("grpc service").Sugar().Infof("start graceful shutdown")
 
+			if s.http != nil {
+				s.http.Stop()
+			}
 			s.grpc.Stop()
 			wg.Done()
 			return
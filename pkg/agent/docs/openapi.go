@@ -0,0 +1,9 @@
+package docs
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI spec of the REST/JSON gateway, generated from the
+// protos of the unit-agent services by pb-gen.
+//
+//go:embed unit-agent.swagger.json
+var OpenAPI []byte
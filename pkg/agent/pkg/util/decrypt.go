@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/upmio/unit-operator/pkg/agent/vars"
 )
@@ -21,6 +22,9 @@ const (
 var (
 	// Global AES key that should be set during application startup
 	aesKey string
+
+	// Passwords rotated on the engine whose secret file has not been refreshed yet
+	rotatedPasswords sync.Map
 )
 
 // ValidateAndSetAESKey validates the AES key from environment variable and sets it for use
@@ -55,6 +59,15 @@ func AES_CTR_Encrypt(plainText []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	return AES_CTR_EncryptWithKey(keyStr, plainText)
+}
+
+// AES_CTR_EncryptWithKey encrypts plaintext with the given key, the result can be read by AES_CTR_Decrypt
+func AES_CTR_EncryptWithKey(keyStr string, plainText []byte) ([]byte, error) {
+	if len(keyStr) != 32 {
+		return nil, fmt.Errorf("invalid AES key length: expected 32 characters, got %d", len(keyStr))
+	}
+
 	// Convert key to OpenSSL compatible format
 	opensslKey := hex.EncodeToString([]byte(keyStr))
 	key, err := hex.DecodeString(opensslKey)
@@ -94,6 +107,15 @@ func AES_CTR_Decrypt(encryptedData []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	return AES_CTR_DecryptWithKey(keyStr, encryptedData)
+}
+
+// AES_CTR_DecryptWithKey decrypts data produced by AES_CTR_Encrypt with the given key
+func AES_CTR_DecryptWithKey(keyStr string, encryptedData []byte) ([]byte, error) {
+	if len(keyStr) != 32 {
+		return nil, fmt.Errorf("invalid AES key length: expected 32 characters, got %d", len(keyStr))
+	}
+
 	// Convert key to OpenSSL compatible format
 	opensslKey := hex.EncodeToString([]byte(keyStr))
 	key, err := hex.DecodeString(opensslKey)
@@ -129,6 +151,27 @@ func AES_CTR_Decrypt(encryptedData []byte) ([]byte, error) {
 }
 
 func DecryptPlainTextPassword(username string) (string, error) {
+	plaintext, err := decryptPasswordFile(username)
+
+	// A rotated password wins until the mounted secret file carries the same value
+	if rotated, ok := rotatedPasswords.Load(username); ok {
+		if err == nil && plaintext == rotated.(string) {
+			rotatedPasswords.CompareAndDelete(username, rotated)
+		}
+
+		return rotated.(string), nil
+	}
+
+	return plaintext, err
+}
+
+// SetRotatedPassword records the new password of a user rotated on the engine, so that it is used
+// before the kubelet refreshes the mounted secret file
+func SetRotatedPassword(username, password string) {
+	rotatedPasswords.Store(username, password)
+}
+
+func decryptPasswordFile(username string) (string, error) {
 	secretPath, err := IsEnvVarSet(vars.SecretMountEnvKey)
 	if err != nil {
 		return "", err
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/upmio/unit-operator/pkg/agent/vars"
+)
+
+func writePasswordFile(t *testing.T, dir, username, password string) {
+	t.Helper()
+
+	content, err := AES_CTR_Encrypt([]byte(password))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, username), content, 0o600))
+}
+
+func TestDecryptPlainTextPasswordRotated(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(vars.SecretMountEnvKey, dir)
+	t.Setenv(vars.AESEnvKey, "0123456789abcdef0123456789abcdef")
+	require.NoError(t, ValidateAndSetAESKey())
+
+	writePasswordFile(t, dir, "backup", "old")
+
+	password, err := DecryptPlainTextPassword("backup")
+	require.NoError(t, err)
+	require.Equal(t, "old", password)
+
+	// The rotated password is used while the file is stale
+	SetRotatedPassword("backup", "new")
+	password, err = DecryptPlainTextPassword("backup")
+	require.NoError(t, err)
+	require.Equal(t, "new", password)
+
+	// Once the file is refreshed the override is dropped
+	writePasswordFile(t, dir, "backup", "new")
+	password, err = DecryptPlainTextPassword("backup")
+	require.NoError(t, err)
+	require.Equal(t, "new", password)
+	_, ok := rotatedPasswords.Load("backup")
+	require.False(t, ok)
+}
+
+func TestAESCTREncryptWithKey(t *testing.T) {
+	t.Setenv(vars.AESEnvKey, "0123456789abcdef0123456789abcdef")
+	require.NoError(t, ValidateAndSetAESKey())
+
+	content, err := AES_CTR_EncryptWithKey("0123456789abcdef0123456789abcdef", []byte("secret"))
+	require.NoError(t, err)
+
+	plaintext, err := AES_CTR_Decrypt(content)
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(plaintext))
+
+	_, err = AES_CTR_EncryptWithKey("short", []byte("secret"))
+	require.Error(t, err)
+}
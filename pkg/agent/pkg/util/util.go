@@ -25,6 +25,24 @@ func IsFileExist(fpath string) bool {
 	return true
 }
 
+// MoveAsideWithBak renames a file or directory to <name>.bak, replacing a previous .bak.
+// A missing src is left as it is.
+func MoveAsideWithBak(src string) error {
+	if _, err := os.Stat(src); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	baseBak := src + ".bak"
+	if err := os.RemoveAll(baseBak); err != nil {
+		return err
+	}
+
+	return os.Rename(src, baseBak)
+}
+
 // FileInfo describes a configuration file and is returned by fileStat.
 type FileInfo struct {
 	Uid  uint32
@@ -27,6 +27,22 @@ func TestIsFileExist(t *testing.T) {
 	require.False(t, IsFileExist(filepath.Join(t.TempDir(), "missing")))
 }
 
+func TestMoveAsideWithBak(t *testing.T) {
+	dir := t.TempDir()
+
+	aofDir := filepath.Join(dir, "appendonlydir")
+	require.NoError(t, os.MkdirAll(aofDir, 0o755))
+	require.NoError(t, os.MkdirAll(aofDir+".bak", 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(aofDir+".bak", "old"), []byte("old"), 0o644))
+
+	require.NoError(t, MoveAsideWithBak(aofDir))
+	require.NoDirExists(t, aofDir)
+	require.DirExists(t, aofDir+".bak")
+	require.NoFileExists(t, filepath.Join(aofDir+".bak", "old"))
+
+	require.NoError(t, MoveAsideWithBak(filepath.Join(dir, "nodes.conf")))
+}
+
 func TestIsConfigChanged(t *testing.T) {
 	dir := t.TempDir()
 	src := filepath.Join(dir, "src")
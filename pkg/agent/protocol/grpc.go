@@ -4,6 +4,7 @@ import (
 	"net"
 
 	"github.com/upmio/unit-operator/pkg/agent/app"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
 	"github.com/upmio/unit-operator/pkg/agent/conf"
 
 	"go.uber.org/zap"
@@ -17,7 +18,7 @@ type GrpcService struct {
 }
 
 func NewGrpcService() *GrpcService {
-	server := grpc.NewServer()
+	server := grpc.NewServer(grpc.UnaryInterceptor(operation.UnaryServerInterceptor()))
 	reflection.Register(server)
 
 	return &GrpcService{
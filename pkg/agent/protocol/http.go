@@ -0,0 +1,141 @@
+package protocol
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/upmio/unit-operator/pkg/agent/app"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
+	"github.com/upmio/unit-operator/pkg/agent/conf"
+	"github.com/upmio/unit-operator/pkg/agent/docs"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// gatewayHeaders are passed as is between the HTTP headers and the gRPC
+// metadata, so that the RPCs see the same credentials and async operation
+// metadata as when called over gRPC. The other headers follow the
+// grpc-gateway defaults, e.g. Grpc-Metadata- prefixed ones.
+var gatewayHeaders = []string{"authorization", operation.AsyncMetadataKey, operation.IDMetadataKey}
+
+// HttpService is the REST/JSON gateway of the grpc apps. It calls the grpc
+// service of the unit-agent, so that the RPCs go through its interceptors.
+type HttpService struct {
+	l *zap.SugaredLogger
+	s *http.Server
+}
+
+func NewHttpService() *HttpService {
+	return &HttpService{
+		s: &http.Server{ReadHeaderTimeout: 10 * time.Second},
+		l: zap.L().Named("http service").Sugar(),
+	}
+}
+
+func (h *HttpService) Start() {
+	conn, err := grpc.NewClient(gatewayEndpoint(conf.GetConf().GrpcAddr()),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		h.l.Errorw("dial grpc service failed", zap.Error(err))
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	handler, err := newGatewayHandler(context.Background(), conn)
+	if err != nil {
+		h.l.Errorw("load gateway app failed", zap.Error(err))
+		return
+	}
+	h.s.Handler = handler
+
+	addr := conf.GetConf().App.Addr()
+	lsr, err := net.Listen("tcp", addr)
+	if err != nil {
+		h.l.Errorf("listen http tcp conn error, %s", err)
+		return
+	}
+
+	h.l.Infof("start http service successfully, listen address: [%s]", addr)
+
+	if err := h.s.Serve(lsr); err != nil {
+		if err == http.ErrServerClosed {
+			h.l.Info("service is stopped")
+			return
+		}
+
+		h.l.Errorw("start http service error", zap.Error(err))
+	}
+}
+
+func (h *HttpService) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.s.Shutdown(ctx); err != nil {
+		h.l.Errorw("stop http service error", zap.Error(err))
+		return
+	}
+	h.l.Info("service is stopped")
+}
+
+// newGatewayHandler serves the RPCs of the grpc apps as POST
+// /<package>.<Service>/<Method> through conn, and their OpenAPI spec as
+// GET /openapi.json.
+func newGatewayHandler(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	gateway := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+	)
+	if err := app.LoadGatewayApp(ctx, gateway, conn); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(docs.OpenAPI)
+	})
+	mux.Handle("/", gateway)
+
+	return mux, nil
+}
+
+func incomingHeaderMatcher(key string) (string, bool) {
+	if lower := strings.ToLower(key); slices.Contains(gatewayHeaders, lower) {
+		return lower, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if slices.Contains(gatewayHeaders, key) {
+		return key, true
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// gatewayEndpoint returns the address the gateway dials the grpc service
+// listening on addr at, the loopback address when it listens on all addresses.
+func gatewayEndpoint(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+
+	return net.JoinHostPort(host, port)
+}
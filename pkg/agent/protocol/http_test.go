@@ -0,0 +1,110 @@
+package protocol
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/upmio/unit-operator/pkg/agent/app"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startGateway serves the operation app, which is the only grpc app
+// registered in this package, over gRPC and through the gateway.
+func startGateway(t *testing.T) *httptest.Server {
+	grpcApp := app.GetGrpcApp("operation")
+	require.NoError(t, grpcApp.Config())
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnaryInterceptor(operation.UnaryServerInterceptor()))
+	grpcApp.Registry(server)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	handler, err := newGatewayHandler(context.Background(), conn)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func post(t *testing.T, url, body string) (int, string) {
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data)
+}
+
+func TestGatewayServesGrpcApps(t *testing.T) {
+	ts := startGateway(t)
+
+	code, body := post(t, ts.URL+"/operation.Operations/ListOperations", `{"activeOnly": true}`)
+	require.Equal(t, http.StatusOK, code)
+	require.JSONEq(t, `{"operations": []}`, body)
+
+	code, body = post(t, ts.URL+"/operation.Operations/GetOperation", `{"id": "missing"}`)
+	require.Equal(t, http.StatusNotFound, code)
+	require.Contains(t, body, "operation missing not found")
+
+	code, _ = post(t, ts.URL+"/mysql.MysqlOperation/Restore", `{}`)
+	require.Equal(t, http.StatusNotFound, code)
+}
+
+func TestGatewayServesOpenAPI(t *testing.T) {
+	ts := startGateway(t)
+
+	resp, err := http.Get(ts.URL + "/openapi.json")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"/mysql.MysqlOperation/Restore"`)
+	require.Contains(t, string(data), `"/service.ServiceLifecycle/StartProcess"`)
+}
+
+func TestGatewayHeaderMatchers(t *testing.T) {
+	for key, want := range map[string]string{
+		"Authorization":             "authorization",
+		"X-Unit-Agent-Async":        operation.AsyncMetadataKey,
+		"X-Unit-Agent-Operation-Id": operation.IDMetadataKey,
+		"Grpc-Metadata-Foo":         "Foo",
+	} {
+		got, ok := incomingHeaderMatcher(key)
+		require.True(t, ok, key)
+		require.Equal(t, want, got, key)
+	}
+
+	_, ok := incomingHeaderMatcher("X-Other")
+	require.False(t, ok)
+
+	got, _ := outgoingHeaderMatcher(operation.IDMetadataKey)
+	require.Equal(t, operation.IDMetadataKey, got)
+	got, _ = outgoingHeaderMatcher("foo")
+	require.Equal(t, "Grpc-Metadata-foo", got)
+}
+
+func TestGatewayEndpoint(t *testing.T) {
+	require.Equal(t, "127.0.0.1:2214", gatewayEndpoint("0.0.0.0:2214"))
+	require.Equal(t, "127.0.0.1:2214", gatewayEndpoint(":2214"))
+	require.Equal(t, "10.0.0.1:2214", gatewayEndpoint("10.0.0.1:2214"))
+}
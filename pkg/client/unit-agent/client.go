@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"time"
 
+	"github.com/upmio/unit-operator/pkg/agent/app/clickhouse"
 	"github.com/upmio/unit-operator/pkg/agent/app/config"
 	"github.com/upmio/unit-operator/pkg/agent/app/slm"
 	"google.golang.org/grpc"
@@ -94,6 +96,23 @@ func GetServiceProcessState(agentHostType, unitsetHeadlessSvc, host, namespace,
 	return parserProcessState(1), nil
 }
 
+// ClickHouseHealth returns the replica, keeper and disk health reported by the unit-agent of a ClickHouse unit.
+func ClickHouseHealth(agentHostType, unitsetHeadlessSvc, host, namespace, port, username string) (*clickhouse.HealthResponse, error) {
+
+	addr := fmtUnitAgentDomainAddr(agentHostType, unitsetHeadlessSvc, host, namespace, port)
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return clickhouse.NewClickHouseOperationClient(conn).Health(ctx, &clickhouse.HealthRequest{Username: username})
+}
+
 func fmtUnitAgentDomainAddr(agentHostType, unitsetHeadlessSvc, host, namespace, port string) string {
 	switch agentHostType {
 	case "domain":
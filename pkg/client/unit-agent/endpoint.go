@@ -0,0 +1,99 @@
+package unit_agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/vars"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DialFunc connects to the unit-agent of a unit
+type DialFunc func(*upmv1alpha2.Unit) (*grpc.ClientConn, error)
+
+// UnitHost returns the DNS name of the unit behind the headless service of its UnitSet.
+func UnitHost(unit *upmv1alpha2.Unit) string {
+	return fmt.Sprintf("%s.%s.%s.svc", unit.Name, upmv1alpha2.UnitsetHeadlessSvcName(unit), unit.Namespace)
+}
+
+// Endpoint returns the host and port of the unit-agent container of the unit.
+func Endpoint(unit *upmv1alpha2.Unit) (string, string, error) {
+	// 1. Find the container named "unit-agent"
+	var agent *corev1.Container
+	for i := range unit.Spec.Template.Spec.Containers {
+		c := &unit.Spec.Template.Spec.Containers[i]
+		if c.Name == vars.UnitAgentName {
+			agent = c
+			break
+		}
+	}
+	if agent == nil {
+		return "", "", fmt.Errorf("container %q not found in unit [%s]", vars.UnitAgentName, unit.Name)
+	}
+
+	// 2. Locate the port named "unit-agent" within the container
+	var agentPort *corev1.ContainerPort
+	for i := range agent.Ports {
+		p := &agent.Ports[i]
+		if p.Name == vars.UnitAgentName {
+			agentPort = p
+			break
+		}
+	}
+	if agentPort == nil {
+		return "", "", fmt.Errorf("port %s not found in container of unit [%s]", vars.UnitAgentName, unit.Name)
+	}
+
+	// 3. Return the host and port as strings
+	return UnitHost(unit), strconv.Itoa(int(agentPort.ContainerPort)), nil
+}
+
+// NewConn builds a grpc connection to the unit-agent listening on host and port.
+func NewConn(host, port string) (*grpc.ClientConn, error) {
+	return grpc.NewClient(
+		net.JoinHostPort(host, port),
+		//grpc.WithTransportCredentials(creds),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			MinConnectTimeout: 5 * time.Second,
+		}),
+	)
+}
+
+// Dial connects to the unit-agent of the unit.
+func Dial(unit *upmv1alpha2.Unit) (*grpc.ClientConn, error) {
+	host, port, err := Endpoint(unit)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConn(host, port)
+}
+
+// Call calls fn with a connection to the unit-agent of the unit made by dial, bounded by timeout.
+func Call(
+	ctx context.Context,
+	unit *upmv1alpha2.Unit,
+	dial DialFunc,
+	timeout time.Duration,
+	fn func(context.Context, grpc.ClientConnInterface) error,
+) error {
+	conn, err := dial(unit)
+	if err != nil {
+		return fmt.Errorf("failed to initialize grpc client: %v", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return fn(callCtx, conn)
+}
@@ -0,0 +1,54 @@
+package unit_agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/vars"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestUnit() *upmv1alpha2.Unit {
+	return &upmv1alpha2.Unit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mysql-0",
+			Namespace: "default",
+			Labels:    map[string]string{upmv1alpha2.UnitsetName: "mysql"},
+		},
+		Spec: upmv1alpha2.UnitSpec{
+			Template: upmv1alpha2.UnitPodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  vars.UnitAgentName,
+							Ports: []corev1.ContainerPort{{Name: vars.UnitAgentName, ContainerPort: 2214}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	host, port, err := Endpoint(newTestUnit())
+
+	require.NoError(t, err)
+	assert.Equal(t, "mysql-0.mysql-headless-svc.default.svc", host)
+	assert.Equal(t, "2214", port)
+}
+
+func TestEndpointMissingPort(t *testing.T) {
+	unit := newTestUnit()
+	unit.Spec.Template.Spec.Containers[0].Ports = nil
+
+	_, _, err := Endpoint(unit)
+	assert.Error(t, err)
+
+	unit.Spec.Template.Spec.Containers = nil
+	_, _, err = Endpoint(unit)
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+package unit_agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrOperationFailed is wrapped by the errors of an operation which failed, was cancelled or was
+// lost by the unit-agent, it has to be started again under a new id.
+var ErrOperationFailed = errors.New("unit agent operation failed")
+
+// StartOperation runs call on the unit-agent of the unit as the operation id. The unit-agent joins
+// an operation already started under id, so a start retried after an operator restart runs call
+// once. It returns the id of the operation, which is empty when the unit-agent ran call
+// synchronously and it already succeeded.
+func StartOperation(
+	ctx context.Context,
+	unit *upmv1alpha2.Unit,
+	dial DialFunc,
+	timeout time.Duration,
+	id string,
+	call func(context.Context, grpc.ClientConnInterface, ...grpc.CallOption) error,
+) (string, error) {
+	var header metadata.MD
+	if err := Call(operation.AsyncContext(ctx, id), unit, dial, timeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+		return call(ctx, conn, grpc.Header(&header))
+	}); err != nil {
+		return "", err
+	}
+
+	return operation.IDFromHeader(header), nil
+}
+
+// PollOperation gets the operation id from the unit-agent of the unit and reports whether it
+// succeeded. An operation which has not finished yet returns its progress.
+func PollOperation(
+	ctx context.Context,
+	unit *upmv1alpha2.Unit,
+	dial DialFunc,
+	timeout time.Duration,
+	id string,
+) (bool, string, error) {
+	return PollOperationResult(ctx, unit, dial, timeout, id, nil)
+}
+
+// PollOperationResult is PollOperation which unmarshals the response of a succeeded operation into
+// result, result is left alone when nil.
+func PollOperationResult(
+	ctx context.Context,
+	unit *upmv1alpha2.Unit,
+	dial DialFunc,
+	timeout time.Duration,
+	id string,
+	result proto.Message,
+) (bool, string, error) {
+	var op *operation.Operation
+	if err := Call(ctx, unit, dial, timeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+		resp, err := operation.NewOperationsClient(conn).GetOperation(ctx, &operation.GetOperationRequest{Id: id})
+		op = resp
+		return err
+	}); err != nil {
+		// lost with a unit-agent which does not persist its operations
+		if status.Code(err) == codes.NotFound {
+			return false, "", fmt.Errorf("%w: operation %s not found on unit [%s]", ErrOperationFailed, id, unit.Name)
+		}
+		return false, "", fmt.Errorf("failed to get operation %s of unit [%s]: %v", id, unit.Name, err)
+	}
+
+	switch op.GetState() {
+	case operation.State_SUCCEEDED:
+		if result != nil && op.GetResponse() != nil {
+			if err := op.GetResponse().UnmarshalTo(result); err != nil {
+				return false, "", fmt.Errorf("failed to unmarshal the response of operation %s of unit [%s]: %v", id, unit.Name, err)
+			}
+		}
+		return true, "", nil
+	case operation.State_FAILED, operation.State_CANCELLED:
+		return false, "", fmt.Errorf("%w: operation %s of unit [%s] %s: %s",
+			ErrOperationFailed, id, unit.Name, strings.ToLower(op.GetState().String()), op.GetError())
+	default:
+		return false, op.GetProgress(), nil
+	}
+}
@@ -0,0 +1,125 @@
+package unit_agent
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
+	"github.com/upmio/unit-operator/pkg/agent/app/slm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type fakeOperations struct {
+	operation.UnimplementedOperationsServer
+	slm.UnimplementedServiceLifecycleServer
+
+	operations map[string]*operation.Operation
+	started    []string
+}
+
+func (f *fakeOperations) GetOperation(_ context.Context, req *operation.GetOperationRequest) (*operation.Operation, error) {
+	op, ok := f.operations[req.GetId()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "operation %s not found", req.GetId())
+	}
+	return op, nil
+}
+
+// StopProcess runs as an operation when asked to, like the unit-agent interceptor does.
+func (f *fakeOperations) StopProcess(ctx context.Context, _ *slm.StopProcessRequest) (*common.Empty, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get(operation.AsyncMetadataKey)) == 0 {
+		return &common.Empty{}, nil
+	}
+
+	id := md.Get(operation.IDMetadataKey)[0]
+	f.started = append(f.started, id)
+	return &common.Empty{}, grpc.SetHeader(ctx, metadata.Pairs(operation.IDMetadataKey, id))
+}
+
+func startFakeOperations(t *testing.T, f *fakeOperations) DialFunc {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	operation.RegisterOperationsServer(server, f)
+	slm.RegisterServiceLifecycleServer(server, f)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	return func(*upmv1alpha2.Unit) (*grpc.ClientConn, error) {
+		return grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+}
+
+func TestStartOperation(t *testing.T) {
+	f := &fakeOperations{}
+	dial := startFakeOperations(t, f)
+
+	id, err := StartOperation(context.Background(), newTestUnit(), dial, time.Second, "restore-1",
+		func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) error {
+			_, err := slm.NewServiceLifecycleClient(conn).StopProcess(ctx, &slm.StopProcessRequest{}, opts...)
+			return err
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "restore-1", id)
+	assert.Equal(t, []string{"restore-1"}, f.started)
+}
+
+func TestPollOperation(t *testing.T) {
+	f := &fakeOperations{operations: map[string]*operation.Operation{
+		"running":   {Id: "running", State: operation.State_RUNNING, Progress: "50%"},
+		"succeeded": {Id: "succeeded", State: operation.State_SUCCEEDED},
+		"failed":    {Id: "failed", State: operation.State_FAILED, Error: "disk full"},
+	}}
+	dial := startFakeOperations(t, f)
+	ctx := context.Background()
+
+	done, progress, err := PollOperation(ctx, newTestUnit(), dial, time.Second, "running")
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, "50%", progress)
+
+	done, _, err = PollOperation(ctx, newTestUnit(), dial, time.Second, "succeeded")
+	require.NoError(t, err)
+	assert.True(t, done)
+
+	_, _, err = PollOperation(ctx, newTestUnit(), dial, time.Second, "failed")
+	require.True(t, errors.Is(err, ErrOperationFailed))
+	assert.Contains(t, err.Error(), "disk full")
+
+	_, _, err = PollOperation(ctx, newTestUnit(), dial, time.Second, "lost")
+	require.True(t, errors.Is(err, ErrOperationFailed))
+}
+
+func TestPollOperationResult(t *testing.T) {
+	response, err := anypb.New(&slm.ProcessStatusResponse{State: "RUNNING"})
+	require.NoError(t, err)
+
+	f := &fakeOperations{operations: map[string]*operation.Operation{
+		"succeeded": {Id: "succeeded", State: operation.State_SUCCEEDED, Response: response},
+	}}
+	dial := startFakeOperations(t, f)
+
+	result := &slm.ProcessStatusResponse{}
+	done, _, err := PollOperationResult(context.Background(), newTestUnit(), dial, time.Second, "succeeded", result)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, "RUNNING", result.GetState())
+}
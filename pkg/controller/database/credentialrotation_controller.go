@@ -0,0 +1,356 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"time"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+	unitAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	credentialRotationAppName = "credential-rotation"
+
+	// pendingPasswordSuffix marks the Secret key holding a generated password not yet applied
+	// on every unit, so that an interrupted rotation resumes with the same password
+	pendingPasswordSuffix = ".pending"
+
+	defaultRotationInterval = 2160 * time.Hour
+	defaultPasswordLength   = 32
+
+	defaultAESSecretName = "aes-secret-key"
+	aesSecretKey         = "AES_SECRET_KEY"
+
+	passwordAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// ReconcileCredentialRotation reconciles CredentialRotation resources.
+type ReconcileCredentialRotation struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	dial     unitAgent.DialFunc
+}
+
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=credentialrotations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=credentialrotations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=units,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update
+
+func (r *ReconcileCredentialRotation) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.Infof("start reconciling credential rotation instance [%s]", req.String())
+	startTime := time.Now()
+
+	defer func() {
+		klog.Infof("finished reconciliation credential rotation instance [%s], duration [%v]", req.String(), time.Since(startTime))
+	}()
+
+	instance := &upmv1alpha1.CredentialRotation{}
+	if err := r.client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			klog.Infof("credential rotation instance [%s] not found, probably deleted.", req.String())
+			return reconcile.Result{}, nil
+		}
+
+		klog.Errorf("failed to fetch credential rotation instance [%s]: [%v]", req.String(), err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if !instance.DeletionTimestamp.IsZero() || instance.Spec.Suspend {
+		return reconcile.Result{}, nil
+	}
+
+	oldStatus := instance.Status.DeepCopy()
+	now := time.Now()
+
+	err := r.rotateDueUsers(ctx, instance, now)
+
+	result := reconcile.Result{}
+	instance.Status.NextRotationTime = nextRotationTime(instance)
+	if err != nil {
+		instance.Status.Result = upmv1alpha1.FailedResult
+		instance.Status.Message = err.Error()
+		r.recorder.Event(instance, corev1.EventTypeWarning, "RotationFailed", err.Error())
+		result.RequeueAfter = retryInterval
+	} else {
+		instance.Status.Result = upmv1alpha1.SuccessResult
+		instance.Status.Message = ""
+		if next := instance.Status.NextRotationTime; next != nil {
+			result.RequeueAfter = max(next.Sub(now), time.Second)
+		}
+	}
+
+	if !reflect.DeepEqual(oldStatus, &instance.Status) {
+		if err := r.client.Status().Update(ctx, instance); err != nil {
+			klog.Errorf("failed to update credential rotation [%s] status: %v", req.String(), err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// rotateDueUsers rotates the password of every user whose rotation is due or was interrupted.
+func (r *ReconcileCredentialRotation) rotateDueUsers(ctx context.Context, instance *upmv1alpha1.CredentialRotation, now time.Time) error {
+	pruneUserStatus(instance)
+
+	if err := validateRotation(instance); err != nil {
+		return err
+	}
+
+	aesKey, err := readAESKey(ctx, r.client, instance.Namespace)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: instance.Spec.SecretName}, secret); err != nil {
+		return fmt.Errorf("failed to fetch secret [%s/%s]: %v", instance.Namespace, instance.Spec.SecretName, err)
+	}
+
+	units, err := listUnitSetUnits(ctx, r.client, instance.Namespace, instance.Spec.UnitSet)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, user := range instance.Spec.Users {
+		if !isRotationDue(instance, secret, user.Name, now) {
+			continue
+		}
+
+		if err := r.rotateUser(ctx, instance, secret, units, user, aesKey); err != nil {
+			errs = append(errs, fmt.Errorf("user [%s]: %v", user.Name, err))
+			continue
+		}
+
+		setLastRotationTime(instance, user.Name, metav1.NewTime(now))
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, "RotationSucceeded",
+			"password of user [%s] rotated on units %v", user.Name, unitNames(units))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// rotateUser stores a new password as pending in the Secret, applies it on every unit, then
+// promotes it to the key of the user. The unit-agent uses the new password from the moment
+// the engine accepted it until the kubelet refreshes the mounted Secret.
+func (r *ReconcileCredentialRotation) rotateUser(
+	ctx context.Context,
+	instance *upmv1alpha1.CredentialRotation,
+	secret *corev1.Secret,
+	units []upmv1alpha2.Unit,
+	user upmv1alpha1.CredentialRotationUser,
+	aesKey string,
+) error {
+	pendingKey := user.Name + pendingPasswordSuffix
+
+	encrypted, ok := secret.Data[pendingKey]
+	if !ok {
+		password, err := generatePassword(passwordLength(instance))
+		if err != nil {
+			return fmt.Errorf("failed to generate password: %v", err)
+		}
+
+		encrypted, err = util.AES_CTR_EncryptWithKey(aesKey, []byte(password))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt password: %v", err)
+		}
+
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[pendingKey] = encrypted
+		if err := r.client.Update(ctx, secret); err != nil {
+			return fmt.Errorf("failed to store pending password in secret [%s/%s]: %v", secret.Namespace, secret.Name, err)
+		}
+	}
+
+	password, err := util.AES_CTR_DecryptWithKey(aesKey, encrypted)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt pending password: %v", err)
+	}
+
+	if err := forEachUnit(ctx, units, r.dial, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+		return rotatePassword(ctx, conn, instance, user, string(password))
+	}); err != nil {
+		return fmt.Errorf("failed to rotate password: %v", err)
+	}
+
+	secret.Data[user.Name] = encrypted
+	delete(secret.Data, pendingKey)
+	if err := r.client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to store password in secret [%s/%s]: %v", secret.Namespace, secret.Name, err)
+	}
+
+	return nil
+}
+
+// validateRotation rejects rotating the admin user unless the engine keeps its current password
+// valid: the agents of the other units log in with the password in their mounted Secret, which
+// only holds the new password once every unit applied it.
+func validateRotation(instance *upmv1alpha1.CredentialRotation) error {
+	for _, user := range instance.Spec.Users {
+		if user.Name == instance.Spec.AdminUsername && !retainsCurrentPassword(instance) {
+			return fmt.Errorf("admin user [%s] can only be rotated on mysql or redis with retainCurrentPassword", user.Name)
+		}
+	}
+
+	return nil
+}
+
+// retainsCurrentPassword reports whether the engine keeps the previous password of a rotated user valid.
+func retainsCurrentPassword(instance *upmv1alpha1.CredentialRotation) bool {
+	switch instance.Spec.Type {
+	case upmv1alpha1.MysqlType, upmv1alpha1.RedisType:
+		return instance.Spec.RetainCurrentPassword == nil || *instance.Spec.RetainCurrentPassword
+	default:
+		return false
+	}
+}
+
+// readAESKey returns the AES key the unit-agents of the namespace decrypt the password files with.
+func readAESKey(ctx context.Context, c client.Client, namespace string) (string, error) {
+	name := defaultAESSecretName
+	if env := os.Getenv("AES_SECRET_KEY"); env != "" {
+		name = env
+	}
+
+	value, _, err := readSecretKey(ctx, c, namespace, corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: name},
+		Key:                  aesSecretKey,
+	})
+
+	return value, err
+}
+
+// isRotationDue reports whether the user was never rotated, its interval elapsed,
+// or a previous rotation left a pending password behind.
+func isRotationDue(instance *upmv1alpha1.CredentialRotation, secret *corev1.Secret, name string, now time.Time) bool {
+	if _, ok := secret.Data[name+pendingPasswordSuffix]; ok {
+		return true
+	}
+
+	status := instance.UserStatus(name)
+	if status == nil || status.LastRotationTime == nil {
+		return true
+	}
+
+	return !now.Before(status.LastRotationTime.Add(rotationInterval(instance)))
+}
+
+// nextRotationTime returns the earliest time a user is due, nil when a user was never rotated.
+func nextRotationTime(instance *upmv1alpha1.CredentialRotation) *metav1.Time {
+	var next *metav1.Time
+	for _, user := range instance.Spec.Users {
+		status := instance.UserStatus(user.Name)
+		if status == nil || status.LastRotationTime == nil {
+			return nil
+		}
+
+		due := metav1.NewTime(status.LastRotationTime.Add(rotationInterval(instance)))
+		if next == nil || due.Before(next) {
+			next = &due
+		}
+	}
+
+	return next
+}
+
+// pruneUserStatus keeps the status of the users listed in the spec, in the spec order.
+func pruneUserStatus(instance *upmv1alpha1.CredentialRotation) {
+	users := make([]upmv1alpha1.CredentialRotationUserStatus, 0, len(instance.Spec.Users))
+	for _, user := range instance.Spec.Users {
+		if status := instance.UserStatus(user.Name); status != nil {
+			users = append(users, *status)
+		}
+	}
+
+	instance.Status.Users = users
+}
+
+func setLastRotationTime(instance *upmv1alpha1.CredentialRotation, name string, at metav1.Time) {
+	if status := instance.UserStatus(name); status != nil {
+		status.LastRotationTime = &at
+		return
+	}
+
+	instance.Status.Users = append(instance.Status.Users, upmv1alpha1.CredentialRotationUserStatus{Name: name, LastRotationTime: &at})
+}
+
+func rotationInterval(instance *upmv1alpha1.CredentialRotation) time.Duration {
+	if instance.Spec.Interval.Duration <= 0 {
+		return defaultRotationInterval
+	}
+
+	return instance.Spec.Interval.Duration
+}
+
+func passwordLength(instance *upmv1alpha1.CredentialRotation) int {
+	if instance.Spec.PasswordLength <= 0 {
+		return defaultPasswordLength
+	}
+
+	return instance.Spec.PasswordLength
+}
+
+// generatePassword returns a random alphanumeric password, which needs no quoting in
+// connection strings or statements.
+func generatePassword(length int) (string, error) {
+	limit := big.NewInt(int64(len(passwordAlphabet)))
+
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		password[i] = passwordAlphabet[n.Int64()]
+	}
+
+	return string(password), nil
+}
+
+func setupCredentialRotation(mgr ctrl.Manager) error {
+	r := &ReconcileCredentialRotation{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor(credentialRotationAppName),
+		dial:     unitAgent.Dial,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&upmv1alpha1.CredentialRotation{}).
+		Complete(r)
+}
@@ -0,0 +1,279 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/mysql"
+	"github.com/upmio/unit-operator/pkg/agent/pkg/util"
+)
+
+const testAESKey = "0123456789abcdef0123456789abcdef"
+
+func newTestRotationReconciler(t *testing.T, agent mysql.MysqlOperationServer, objs ...client.Object) *ReconcileCredentialRotation {
+	t.Helper()
+
+	s := newTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithStatusSubresource(&upmv1alpha1.CredentialRotation{}).
+		WithObjects(objs...).
+		Build()
+
+	return &ReconcileCredentialRotation{
+		client:   c,
+		scheme:   s,
+		recorder: record.NewFakeRecorder(10),
+		dial: startTestAgent(t, func(s *grpc.Server) {
+			mysql.RegisterMysqlOperationServer(s, agent)
+		}),
+	}
+}
+
+func newTestRotationObjects(t *testing.T) []client.Object {
+	t.Helper()
+
+	current, err := util.AES_CTR_EncryptWithKey(testAESKey, []byte("old"))
+	require.NoError(t, err)
+
+	return []client.Object{
+		&upmv1alpha1.CredentialRotation{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "default"},
+			Spec: upmv1alpha1.CredentialRotationSpec{
+				UnitSet:       "mysql",
+				Type:          upmv1alpha1.MysqlType,
+				AdminUsername: "root",
+				SecretName:    "mysql-secret",
+				Users:         []upmv1alpha1.CredentialRotationUser{{Name: "backup"}},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultAESSecretName, Namespace: "default"},
+			Data:       map[string][]byte{aesSecretKey: []byte(testAESKey)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysql-secret", Namespace: "default"},
+			Data:       map[string][]byte{"backup": current},
+		},
+		newTestUnit("mysql-0", "mysql"),
+		newTestUnit("mysql-1", "mysql"),
+	}
+}
+
+func TestReconcileCredentialRotation(t *testing.T) {
+	agent := &fakeMysqlAgent{}
+	r := newTestRotationReconciler(t, agent, newTestRotationObjects(t)...)
+
+	key := types.NamespacedName{Namespace: "default", Name: "mysql"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.InDelta(t, defaultRotationInterval, result.RequeueAfter, float64(time.Minute))
+
+	require.Len(t, agent.rotations, 2)
+	rotated := agent.rotations[0].GetPassword()
+	assert.Len(t, rotated, defaultPasswordLength)
+	assert.Equal(t, "backup", agent.rotations[0].GetUser())
+	assert.True(t, agent.rotations[0].GetRetainCurrentPassword())
+	assert.Equal(t, rotated, agent.rotations[1].GetPassword())
+
+	secret := &corev1.Secret{}
+	require.NoError(t, r.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "mysql-secret"}, secret))
+	assert.NotContains(t, secret.Data, "backup"+pendingPasswordSuffix)
+	stored, err := util.AES_CTR_DecryptWithKey(testAESKey, secret.Data["backup"])
+	require.NoError(t, err)
+	assert.Equal(t, rotated, string(stored))
+
+	instance := &upmv1alpha1.CredentialRotation{}
+	require.NoError(t, r.client.Get(context.Background(), key, instance))
+	assert.Equal(t, upmv1alpha1.SuccessResult, instance.Status.Result)
+	require.NotNil(t, instance.UserStatus("backup"))
+	assert.NotNil(t, instance.Status.NextRotationTime)
+
+	// Nothing is due until the interval elapsed
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Len(t, agent.rotations, 2)
+}
+
+// lockoutMysqlAgent is the engine shared by the units: once the password of the admin user
+// changed without retaining the current one, the agents still using the old password of
+// their mounted Secret fail to log in.
+type lockoutMysqlAgent struct {
+	fakeMysqlAgent
+
+	lockedOut bool
+}
+
+func (f *lockoutMysqlAgent) RotatePassword(ctx context.Context, req *mysql.RotatePasswordRequest) (*common.Empty, error) {
+	f.mu.Lock()
+	lockedOut := f.lockedOut
+	if req.GetUser() == req.GetUsername() && !req.GetRetainCurrentPassword() {
+		f.lockedOut = true
+	}
+	f.mu.Unlock()
+
+	if lockedOut {
+		return nil, status.Errorf(codes.Unauthenticated, "access denied for user [%s]", req.GetUsername())
+	}
+
+	return f.fakeMysqlAgent.RotatePassword(ctx, req)
+}
+
+func newTestAdminRotationObjects(t *testing.T, retain bool) []client.Object {
+	t.Helper()
+
+	current, err := util.AES_CTR_EncryptWithKey(testAESKey, []byte("old"))
+	require.NoError(t, err)
+
+	objs := newTestRotationObjects(t)
+	spec := &objs[0].(*upmv1alpha1.CredentialRotation).Spec
+	spec.Users = []upmv1alpha1.CredentialRotationUser{{Name: "root"}}
+	spec.RetainCurrentPassword = &retain
+	objs[2].(*corev1.Secret).Data["root"] = current
+
+	return objs
+}
+
+func TestReconcileCredentialRotationAdminRetainsCurrentPassword(t *testing.T) {
+	agent := &lockoutMysqlAgent{}
+	r := newTestRotationReconciler(t, agent, newTestAdminRotationObjects(t, true)...)
+
+	key := types.NamespacedName{Namespace: "default", Name: "mysql"}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Len(t, agent.rotations, 2)
+
+	secret := &corev1.Secret{}
+	require.NoError(t, r.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "mysql-secret"}, secret))
+	assert.NotContains(t, secret.Data, "root"+pendingPasswordSuffix)
+	stored, err := util.AES_CTR_DecryptWithKey(testAESKey, secret.Data["root"])
+	require.NoError(t, err)
+	assert.Equal(t, agent.rotations[0].GetPassword(), string(stored))
+}
+
+func TestReconcileCredentialRotationRejectsAdminWithoutRetainedPassword(t *testing.T) {
+	agent := &lockoutMysqlAgent{}
+	r := newTestRotationReconciler(t, agent, newTestAdminRotationObjects(t, false)...)
+
+	key := types.NamespacedName{Namespace: "default", Name: "mysql"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, retryInterval, result.RequeueAfter)
+
+	// mysql-1 would be locked out once mysql-0 changed the password
+	assert.Empty(t, agent.rotations)
+	assert.False(t, agent.lockedOut)
+
+	instance := &upmv1alpha1.CredentialRotation{}
+	require.NoError(t, r.client.Get(context.Background(), key, instance))
+	assert.Equal(t, upmv1alpha1.FailedResult, instance.Status.Result)
+	assert.Contains(t, instance.Status.Message, "admin user [root]")
+
+	secret := &corev1.Secret{}
+	require.NoError(t, r.client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "mysql-secret"}, secret))
+	assert.NotContains(t, secret.Data, "root"+pendingPasswordSuffix)
+}
+
+func TestValidateRotation(t *testing.T) {
+	retain, discard := true, false
+	for _, tc := range []struct {
+		unitType upmv1alpha1.UnitType
+		retain   *bool
+		user     string
+		wantErr  bool
+	}{
+		{unitType: upmv1alpha1.MysqlType, user: "root"},
+		{unitType: upmv1alpha1.RedisType, retain: &retain, user: "root"},
+		{unitType: upmv1alpha1.MysqlType, retain: &discard, user: "root", wantErr: true},
+		{unitType: upmv1alpha1.MongoDBType, user: "root", wantErr: true},
+		{unitType: upmv1alpha1.PostgresqlType, user: "root", wantErr: true},
+		{unitType: upmv1alpha1.PostgresqlType, user: "replication"},
+	} {
+		instance := &upmv1alpha1.CredentialRotation{Spec: upmv1alpha1.CredentialRotationSpec{
+			Type:                  tc.unitType,
+			AdminUsername:         "root",
+			Users:                 []upmv1alpha1.CredentialRotationUser{{Name: tc.user}},
+			RetainCurrentPassword: tc.retain,
+		}}
+
+		err := validateRotation(instance)
+		assert.Equal(t, tc.wantErr, err != nil, "%s %s", tc.unitType, tc.user)
+	}
+}
+
+func TestReconcileCredentialRotationResumesPendingPassword(t *testing.T) {
+	objs := newTestRotationObjects(t)
+
+	pending, err := util.AES_CTR_EncryptWithKey(testAESKey, []byte("pending"))
+	require.NoError(t, err)
+	objs[2].(*corev1.Secret).Data["backup"+pendingPasswordSuffix] = pending
+
+	lastRotation := metav1.NewTime(time.Now())
+	objs[0].(*upmv1alpha1.CredentialRotation).Status.Users = []upmv1alpha1.CredentialRotationUserStatus{
+		{Name: "backup", LastRotationTime: &lastRotation},
+	}
+
+	agent := &fakeMysqlAgent{}
+	r := newTestRotationReconciler(t, agent, objs...)
+
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "mysql"}})
+	require.NoError(t, err)
+
+	require.Len(t, agent.rotations, 2)
+	assert.Equal(t, "pending", agent.rotations[0].GetPassword())
+}
+
+func TestReconcileCredentialRotationMissingAESKey(t *testing.T) {
+	objs := newTestRotationObjects(t)
+	objs = append(objs[:1], objs[2:]...)
+
+	agent := &fakeMysqlAgent{}
+	r := newTestRotationReconciler(t, agent, objs...)
+
+	key := types.NamespacedName{Namespace: "default", Name: "mysql"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, retryInterval, result.RequeueAfter)
+	assert.Empty(t, agent.rotations)
+
+	instance := &upmv1alpha1.CredentialRotation{}
+	require.NoError(t, r.client.Get(context.Background(), key, instance))
+	assert.Equal(t, upmv1alpha1.FailedResult, instance.Status.Result)
+}
+
+func TestGeneratePassword(t *testing.T) {
+	password, err := generatePassword(48)
+	require.NoError(t, err)
+	assert.Len(t, password, 48)
+	for _, c := range password {
+		assert.Contains(t, passwordAlphabet, string(c))
+	}
+}
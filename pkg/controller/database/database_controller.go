@@ -0,0 +1,161 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	unitAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const databaseAppName = "database"
+
+// ReconcileDatabase reconciles Database resources.
+type ReconcileDatabase struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	dial     unitAgent.DialFunc
+}
+
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=databases,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=databases/status,verbs=get;update;patch
+
+func (r *ReconcileDatabase) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.Infof("start reconciling database instance [%s]", req.String())
+	startTime := time.Now()
+
+	defer func() {
+		klog.Infof("finished reconciliation database instance [%s], duration [%v]", req.String(), time.Since(startTime))
+	}()
+
+	instance := &upmv1alpha1.Database{}
+	if err := r.client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			klog.Infof("database instance [%s] not found, probably deleted.", req.String())
+			return reconcile.Result{}, nil
+		}
+
+		klog.Errorf("failed to fetch database instance [%s]: [%v]", req.String(), err.Error())
+		return reconcile.Result{}, err
+	}
+
+	// Deleting a Database never drops the database
+	if !instance.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	oldStatus := instance.Status.DeepCopy()
+
+	err := func() error {
+		units, err := listUnitSetUnits(ctx, r.client, instance.Namespace, instance.Spec.UnitSet)
+		if err != nil {
+			return err
+		}
+
+		if instance.Status.Result == upmv1alpha1.SuccessResult &&
+			instance.Status.ObservedGeneration == instance.Generation &&
+			reflect.DeepEqual(instance.Status.Units, unitNames(units)) {
+			return nil
+		}
+
+		if err := forEachUnit(ctx, units, r.dial, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			return createDatabase(ctx, conn, instance)
+		}); err != nil {
+			return fmt.Errorf("failed to create database: %v", err)
+		}
+
+		now := metav1.Now()
+		instance.Status.ObservedGeneration = instance.Generation
+		instance.Status.Units = unitNames(units)
+		instance.Status.LastSyncTime = &now
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, "SyncSucceeded",
+			"database [%s] synchronized to units %v", instance.DatabaseName(), unitNames(units))
+
+		return nil
+	}()
+
+	result := reconcile.Result{}
+	if err != nil {
+		instance.Status.Result = upmv1alpha1.FailedResult
+		instance.Status.Message = err.Error()
+		r.recorder.Event(instance, corev1.EventTypeWarning, "SyncFailed", err.Error())
+		result.RequeueAfter = retryInterval
+	} else {
+		instance.Status.Result = upmv1alpha1.SuccessResult
+		instance.Status.Message = ""
+	}
+
+	if !reflect.DeepEqual(oldStatus, &instance.Status) {
+		if err := r.client.Status().Update(ctx, instance); err != nil {
+			klog.Errorf("failed to update database [%s] status: %v", req.String(), err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+func setupDatabase(mgr ctrl.Manager) error {
+	r := &ReconcileDatabase{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor(databaseAppName),
+		dial:     unitAgent.Dial,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&upmv1alpha1.Database{}).
+		Complete(r)
+}
+
+// Setup creates the DatabaseUser, Database, CredentialRotation, ProxysqlBackend, RedisClusterBackup and
+// RedisClusterRestore controllers and adds them to the manager.
+func Setup(mgr ctrl.Manager) error {
+	if err := setupDatabaseUser(mgr); err != nil {
+		return err
+	}
+
+	if err := setupDatabase(mgr); err != nil {
+		return err
+	}
+
+	if err := setupCredentialRotation(mgr); err != nil {
+		return err
+	}
+
+	if err := setupProxysqlBackend(mgr); err != nil {
+		return err
+	}
+
+	if err := setupRedisClusterBackup(mgr); err != nil {
+		return err
+	}
+
+	return setupRedisClusterRestore(mgr)
+}
@@ -0,0 +1,285 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	unitAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	databaseUserAppName = "database-user"
+
+	// FinalizerDatabaseUser guards the removal of the account when a DatabaseUser is deleted
+	FinalizerDatabaseUser = "unit-operator/database-user"
+
+	// retryInterval is the delay before a failed synchronization is retried
+	retryInterval = 30 * time.Second
+)
+
+// ReconcileDatabaseUser reconciles DatabaseUser resources.
+type ReconcileDatabaseUser struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	dial     unitAgent.DialFunc
+}
+
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=databaseusers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=databaseusers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=databaseusers/finalizers,verbs=update
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=units,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+func (r *ReconcileDatabaseUser) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.Infof("start reconciling database user instance [%s]", req.String())
+	startTime := time.Now()
+
+	defer func() {
+		klog.Infof("finished reconciliation database user instance [%s], duration [%v]", req.String(), time.Since(startTime))
+	}()
+
+	instance := &upmv1alpha1.DatabaseUser{}
+	if err := r.client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			klog.Infof("database user instance [%s] not found, probably deleted.", req.String())
+			return reconcile.Result{}, nil
+		}
+
+		klog.Errorf("failed to fetch database user instance [%s]: [%v]", req.String(), err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if !instance.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, r.reconcileDelete(ctx, instance)
+	}
+
+	if controllerutil.AddFinalizer(instance, FinalizerDatabaseUser) {
+		if err := r.client.Update(ctx, instance); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to add finalizer to database user [%s]: %v", req.String(), err)
+		}
+	}
+
+	oldStatus := instance.Status.DeepCopy()
+
+	err := func() error {
+		password, secretVersion, err := readSecretKey(ctx, r.client, instance.Namespace, instance.Spec.PasswordSecretRef)
+		if err != nil {
+			return err
+		}
+
+		units, err := listUnitSetUnits(ctx, r.client, instance.Namespace, instance.Spec.UnitSet)
+		if err != nil {
+			return err
+		}
+
+		if isDatabaseUserSynced(instance, secretVersion, unitNames(units)) {
+			return nil
+		}
+
+		revokes := revokedPrivileges(instance.Status.Privileges, instance.Spec.Privileges)
+		if err := forEachUnit(ctx, units, r.dial, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			return createUser(ctx, conn, instance, password, revokes)
+		}); err != nil {
+			return fmt.Errorf("failed to create database user: %v", err)
+		}
+
+		now := metav1.Now()
+		instance.Status.ObservedGeneration = instance.Generation
+		instance.Status.PasswordSecretVersion = secretVersion
+		instance.Status.Units = unitNames(units)
+		instance.Status.Privileges = append([]upmv1alpha1.DatabasePrivilege(nil), instance.Spec.Privileges...)
+		instance.Status.LastSyncTime = &now
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, "SyncSucceeded",
+			"database user [%s] synchronized to units %v", instance.AccountName(), unitNames(units))
+
+		return nil
+	}()
+
+	result := reconcile.Result{}
+	if err != nil {
+		instance.Status.Result = upmv1alpha1.FailedResult
+		instance.Status.Message = err.Error()
+		r.recorder.Event(instance, corev1.EventTypeWarning, "SyncFailed", err.Error())
+		result.RequeueAfter = retryInterval
+	} else {
+		instance.Status.Result = upmv1alpha1.SuccessResult
+		instance.Status.Message = ""
+	}
+
+	if !reflect.DeepEqual(oldStatus, &instance.Status) {
+		if err := r.client.Status().Update(ctx, instance); err != nil {
+			klog.Errorf("failed to update database user [%s] status: %v", req.String(), err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// reconcileDelete drops the account unless the deletion policy retains it, then releases the finalizer.
+func (r *ReconcileDatabaseUser) reconcileDelete(ctx context.Context, instance *upmv1alpha1.DatabaseUser) error {
+	if !controllerutil.ContainsFinalizer(instance, FinalizerDatabaseUser) {
+		return nil
+	}
+
+	if instance.Spec.DeletionPolicy != upmv1alpha1.DeletionPolicyRetain {
+		// A UnitSet without units, or already deleted, has no account left to drop
+		units, err := listUnits(ctx, r.client, instance.Namespace, instance.Spec.UnitSet)
+		if err != nil {
+			return err
+		}
+
+		if err := forEachUnit(ctx, units, r.dial, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			return dropUser(ctx, conn, instance)
+		}); err != nil {
+			r.recorder.Event(instance, corev1.EventTypeWarning, "DropFailed", err.Error())
+			return fmt.Errorf("failed to drop database user: %v", err)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(instance, FinalizerDatabaseUser)
+	return r.client.Update(ctx, instance)
+}
+
+// isDatabaseUserSynced reports whether the last successful synchronization applied
+// the current spec and password to the current units.
+func isDatabaseUserSynced(instance *upmv1alpha1.DatabaseUser, secretVersion string, units []string) bool {
+	return instance.Status.Result == upmv1alpha1.SuccessResult &&
+		instance.Status.ObservedGeneration == instance.Generation &&
+		instance.Status.PasswordSecretVersion == secretVersion &&
+		reflect.DeepEqual(instance.Status.Units, units)
+}
+
+// revokedPrivileges returns the privileges of applied which are no longer in desired,
+// grouped by database and table in the order of applied.
+func revokedPrivileges(applied, desired []upmv1alpha1.DatabasePrivilege) []upmv1alpha1.DatabasePrivilege {
+	type object struct{ database, table string }
+
+	granted := make(map[object]map[string]bool, len(desired))
+	for _, p := range desired {
+		key := object{p.Database, p.Table}
+		if granted[key] == nil {
+			granted[key] = make(map[string]bool, len(p.Privileges))
+		}
+		for _, privilege := range p.Privileges {
+			granted[key][normalizePrivilege(privilege)] = true
+		}
+	}
+
+	var revokes []upmv1alpha1.DatabasePrivilege
+	index := make(map[object]int)
+	for _, p := range applied {
+		key := object{p.Database, p.Table}
+		for _, privilege := range p.Privileges {
+			privilege = normalizePrivilege(privilege)
+			if granted[key][privilege] {
+				continue
+			}
+
+			i, ok := index[key]
+			if !ok {
+				i = len(revokes)
+				index[key] = i
+				revokes = append(revokes, upmv1alpha1.DatabasePrivilege{Database: p.Database, Table: p.Table})
+			}
+			if !slices.Contains(revokes[i].Privileges, privilege) {
+				revokes[i].Privileges = append(revokes[i].Privileges, privilege)
+			}
+		}
+	}
+
+	return revokes
+}
+
+func normalizePrivilege(privilege string) string {
+	return strings.ToUpper(strings.TrimSpace(privilege))
+}
+
+// secretToDatabaseUsers maps a Secret to the DatabaseUsers reading their password from it.
+func (r *ReconcileDatabaseUser) secretToDatabaseUsers(ctx context.Context, obj client.Object) []reconcile.Request {
+	users := &upmv1alpha1.DatabaseUserList{}
+	if err := r.client.List(ctx, users, client.InNamespace(obj.GetNamespace())); err != nil {
+		klog.Errorf("failed to list database users in namespace [%s]: %v", obj.GetNamespace(), err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, user := range users.Items {
+		if user.Spec.PasswordSecretRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: user.Namespace, Name: user.Name}})
+		}
+	}
+
+	return requests
+}
+
+// unitToDatabaseUsers maps a Unit to the DatabaseUsers of its UnitSet, so that new units receive the users.
+func (r *ReconcileDatabaseUser) unitToDatabaseUsers(ctx context.Context, obj client.Object) []reconcile.Request {
+	unitSet := obj.GetLabels()[upmv1alpha2.UnitsetName]
+	if unitSet == "" {
+		return nil
+	}
+
+	users := &upmv1alpha1.DatabaseUserList{}
+	if err := r.client.List(ctx, users, client.InNamespace(obj.GetNamespace())); err != nil {
+		klog.Errorf("failed to list database users in namespace [%s]: %v", obj.GetNamespace(), err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, user := range users.Items {
+		if user.Spec.UnitSet == unitSet {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: user.Namespace, Name: user.Name}})
+		}
+	}
+
+	return requests
+}
+
+func setupDatabaseUser(mgr ctrl.Manager) error {
+	r := &ReconcileDatabaseUser{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor(databaseUserAppName),
+		dial:     unitAgent.Dial,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&upmv1alpha1.DatabaseUser{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.secretToDatabaseUsers)).
+		Watches(&upmv1alpha2.Unit{}, handler.EnqueueRequestsFromMapFunc(r.unitToDatabaseUsers)).
+		Complete(r)
+}
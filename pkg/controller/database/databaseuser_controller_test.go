@@ -0,0 +1,185 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/mysql"
+)
+
+func newTestReconciler(t *testing.T, agent *fakeMysqlAgent, objs ...client.Object) *ReconcileDatabaseUser {
+	t.Helper()
+
+	s := newTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithStatusSubresource(&upmv1alpha1.DatabaseUser{}).
+		WithObjects(objs...).
+		Build()
+
+	return &ReconcileDatabaseUser{
+		client:   c,
+		scheme:   s,
+		recorder: record.NewFakeRecorder(10),
+		dial: startTestAgent(t, func(s *grpc.Server) {
+			mysql.RegisterMysqlOperationServer(s, agent)
+		}),
+	}
+}
+
+func newTestPasswordSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-password", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+}
+
+func newReconcileTestUser() *upmv1alpha1.DatabaseUser {
+	instance := newTestDatabaseUser(upmv1alpha1.MysqlType)
+	instance.Spec.PasswordSecretRef = corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "app-password"},
+		Key:                  "password",
+	}
+	return instance
+}
+
+func TestReconcileDatabaseUserSync(t *testing.T) {
+	agent := &fakeMysqlAgent{}
+	r := newTestReconciler(t, agent, newReconcileTestUser(), newTestPasswordSecret(),
+		newTestUnit("mysql-0", "mysql"), newTestUnit("mysql-1", "mysql"))
+
+	key := types.NamespacedName{Namespace: "default", Name: "app"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Len(t, agent.creates, 2)
+
+	instance := &upmv1alpha1.DatabaseUser{}
+	require.NoError(t, r.client.Get(context.Background(), key, instance))
+	assert.True(t, controllerutil.ContainsFinalizer(instance, FinalizerDatabaseUser))
+	assert.Equal(t, upmv1alpha1.SuccessResult, instance.Status.Result)
+	assert.Equal(t, []string{"mysql-0", "mysql-1"}, instance.Status.Units)
+	assert.NotEmpty(t, instance.Status.PasswordSecretVersion)
+
+	assert.Equal(t, instance.Spec.Privileges, instance.Status.Privileges)
+
+	// A second pass with nothing changed must not call the agents again.
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Len(t, agent.creates, 2)
+
+	// Privileges removed from the spec are revoked.
+	instance.Spec.Privileges = []upmv1alpha1.DatabasePrivilege{{Database: "shop", Privileges: []string{"SELECT"}}}
+	instance.Generation++
+	require.NoError(t, r.client.Update(context.Background(), instance))
+
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	require.Len(t, agent.creates, 4)
+	require.Len(t, agent.creates[3].GetRevokes(), 1)
+	assert.Equal(t, "shop", agent.creates[3].GetRevokes()[0].GetDatabase())
+	assert.Equal(t, []string{"INSERT"}, agent.creates[3].GetRevokes()[0].GetPrivileges())
+}
+
+func TestReconcileDatabaseUserMissingSecret(t *testing.T) {
+	agent := &fakeMysqlAgent{}
+	r := newTestReconciler(t, agent, newReconcileTestUser(), newTestUnit("mysql-0", "mysql"))
+
+	key := types.NamespacedName{Namespace: "default", Name: "app"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, retryInterval, result.RequeueAfter)
+	assert.Empty(t, agent.creates)
+
+	instance := &upmv1alpha1.DatabaseUser{}
+	require.NoError(t, r.client.Get(context.Background(), key, instance))
+	assert.Equal(t, upmv1alpha1.FailedResult, instance.Status.Result)
+	assert.NotEmpty(t, instance.Status.Message)
+}
+
+func TestReconcileDatabaseUserDelete(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    upmv1alpha1.DeletionPolicy
+		units     int
+		wantDrops int
+	}{
+		{name: "delete", policy: upmv1alpha1.DeletionPolicyDelete, units: 1, wantDrops: 1},
+		{name: "retain", policy: upmv1alpha1.DeletionPolicyRetain, units: 1, wantDrops: 0},
+		{name: "no units", policy: upmv1alpha1.DeletionPolicyDelete, units: 0, wantDrops: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := newReconcileTestUser()
+			instance.Spec.DeletionPolicy = tt.policy
+			instance.Finalizers = []string{FinalizerDatabaseUser}
+			now := metav1.NewTime(time.Now())
+			instance.DeletionTimestamp = &now
+
+			agent := &fakeMysqlAgent{}
+			objs := []client.Object{instance, &upmv1alpha2.UnitSet{ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "default"}}}
+			if tt.units > 0 {
+				objs = append(objs, newTestUnit("mysql-0", "mysql"))
+			}
+			r := newTestReconciler(t, agent, objs...)
+
+			key := types.NamespacedName{Namespace: "default", Name: "app"}
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+			require.NoError(t, err)
+			assert.Len(t, agent.drops, tt.wantDrops)
+
+			// The object is gone once its last finalizer is removed.
+			err = r.client.Get(context.Background(), key, &upmv1alpha1.DatabaseUser{})
+			assert.True(t, client.IgnoreNotFound(err) == nil && err != nil)
+		})
+	}
+}
+
+func TestRevokedPrivileges(t *testing.T) {
+	applied := []upmv1alpha1.DatabasePrivilege{
+		{Database: "shop", Privileges: []string{"SELECT", "INSERT", "DELETE"}},
+		{Database: "shop", Table: "orders", Privileges: []string{"UPDATE"}},
+		{Database: "logs", Privileges: []string{"select"}},
+	}
+	desired := []upmv1alpha1.DatabasePrivilege{
+		{Database: "shop", Privileges: []string{"select", " insert "}},
+		{Database: "logs", Table: "events", Privileges: []string{"SELECT"}},
+	}
+
+	assert.Equal(t, []upmv1alpha1.DatabasePrivilege{
+		{Database: "shop", Privileges: []string{"DELETE"}},
+		{Database: "shop", Table: "orders", Privileges: []string{"UPDATE"}},
+		{Database: "logs", Privileges: []string{"SELECT"}},
+	}, revokedPrivileges(applied, desired))
+
+	assert.Empty(t, revokedPrivileges(nil, desired))
+	assert.Empty(t, revokedPrivileges(desired, desired))
+}
@@ -0,0 +1,279 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	"github.com/upmio/unit-operator/pkg/agent/app/clickhouse"
+	"github.com/upmio/unit-operator/pkg/agent/app/mongodb"
+	"github.com/upmio/unit-operator/pkg/agent/app/mysql"
+	"github.com/upmio/unit-operator/pkg/agent/app/postgresql"
+	"github.com/upmio/unit-operator/pkg/agent/app/proxysql"
+	"github.com/upmio/unit-operator/pkg/agent/app/redis"
+	"google.golang.org/grpc"
+)
+
+// createUser creates or updates the account described by the DatabaseUser on one unit
+// and revokes the privileges removed from it. mongodb replaces the roles of the user.
+func createUser(ctx context.Context, conn grpc.ClientConnInterface, instance *upmv1alpha1.DatabaseUser, password string, revokes []upmv1alpha1.DatabasePrivilege) error {
+	spec := instance.Spec
+	user := instance.AccountName()
+
+	var err error
+	switch spec.Type {
+	case upmv1alpha1.MysqlType:
+		req := &mysql.CreateUserRequest{
+			Username: spec.AdminUsername,
+			User:     user,
+			Password: password,
+		}
+		if spec.Mysql != nil {
+			req.Host = spec.Mysql.Host
+		}
+		for _, p := range spec.Privileges {
+			req.Privileges = append(req.Privileges, &mysql.Privilege{Database: p.Database, Table: p.Table, Privileges: p.Privileges})
+		}
+		for _, p := range revokes {
+			req.Revokes = append(req.Revokes, &mysql.Privilege{Database: p.Database, Table: p.Table, Privileges: p.Privileges})
+		}
+		_, err = mysql.NewMysqlOperationClient(conn).CreateUser(ctx, req)
+	case upmv1alpha1.PostgresqlType:
+		req := &postgresql.CreateUserRequest{
+			Username: spec.AdminUsername,
+			User:     user,
+			Password: password,
+		}
+		for _, p := range spec.Privileges {
+			req.Privileges = append(req.Privileges, &postgresql.Privilege{Database: p.Database, Table: p.Table, Privileges: p.Privileges})
+		}
+		for _, p := range revokes {
+			req.Revokes = append(req.Revokes, &postgresql.Privilege{Database: p.Database, Table: p.Table, Privileges: p.Privileges})
+		}
+		_, err = postgresql.NewPostgresqlOperationClient(conn).CreateUser(ctx, req)
+	case upmv1alpha1.ProxysqlType:
+		req := &proxysql.CreateUserRequest{
+			Username: spec.AdminUsername,
+			User:     user,
+			Password: password,
+		}
+		if spec.Proxysql != nil {
+			req.DefaultHostgroup = spec.Proxysql.DefaultHostgroup
+			req.DefaultSchema = spec.Proxysql.DefaultSchema
+			req.MaxConnections = spec.Proxysql.MaxConnections
+		}
+		_, err = proxysql.NewProxysqlOperationClient(conn).CreateUser(ctx, req)
+	case upmv1alpha1.RedisType:
+		req := &redis.CreateUserRequest{
+			Username: spec.AdminUsername,
+			User:     user,
+			Password: password,
+		}
+		if spec.Redis != nil {
+			req.Rules = spec.Redis.ACLRules
+		}
+		_, err = redis.NewRedisOperationClient(conn).CreateUser(ctx, req)
+	case upmv1alpha1.MongoDBType:
+		req := &mongodb.CreateUserRequest{
+			Username: spec.AdminUsername,
+			User:     user,
+			Password: password,
+		}
+		if spec.MongoDB != nil {
+			req.AuthDatabase = spec.MongoDB.AuthDatabase
+		}
+		for _, p := range spec.Privileges {
+			for _, role := range p.Privileges {
+				req.Roles = append(req.Roles, &mongodb.Role{Database: p.Database, Role: role})
+			}
+		}
+		_, err = mongodb.NewMongoDBOperationClient(conn).CreateUser(ctx, req)
+	case upmv1alpha1.ClickHouseType:
+		req := &clickhouse.CreateUserRequest{
+			Username: spec.AdminUsername,
+			User:     user,
+			Password: password,
+		}
+		for _, p := range spec.Privileges {
+			req.Privileges = append(req.Privileges, &clickhouse.Privilege{Database: p.Database, Table: p.Table, Privileges: p.Privileges})
+		}
+		for _, p := range revokes {
+			req.Revokes = append(req.Revokes, &clickhouse.Privilege{Database: p.Database, Table: p.Table, Privileges: p.Privileges})
+		}
+		_, err = clickhouse.NewClickHouseOperationClient(conn).CreateUser(ctx, req)
+	default:
+		return fmt.Errorf("unsupported type %q for database user", spec.Type)
+	}
+
+	return err
+}
+
+// dropUser drops the account described by the DatabaseUser on one unit.
+func dropUser(ctx context.Context, conn grpc.ClientConnInterface, instance *upmv1alpha1.DatabaseUser) error {
+	spec := instance.Spec
+	user := instance.AccountName()
+
+	var err error
+	switch spec.Type {
+	case upmv1alpha1.MysqlType:
+		req := &mysql.DropUserRequest{Username: spec.AdminUsername, User: user}
+		if spec.Mysql != nil {
+			req.Host = spec.Mysql.Host
+		}
+		_, err = mysql.NewMysqlOperationClient(conn).DropUser(ctx, req)
+	case upmv1alpha1.PostgresqlType:
+		_, err = postgresql.NewPostgresqlOperationClient(conn).DropUser(ctx, &postgresql.DropUserRequest{Username: spec.AdminUsername, User: user})
+	case upmv1alpha1.ProxysqlType:
+		_, err = proxysql.NewProxysqlOperationClient(conn).DropUser(ctx, &proxysql.DropUserRequest{Username: spec.AdminUsername, User: user})
+	case upmv1alpha1.RedisType:
+		_, err = redis.NewRedisOperationClient(conn).DropUser(ctx, &redis.DropUserRequest{Username: spec.AdminUsername, User: user})
+	case upmv1alpha1.MongoDBType:
+		req := &mongodb.DropUserRequest{Username: spec.AdminUsername, User: user}
+		if spec.MongoDB != nil {
+			req.AuthDatabase = spec.MongoDB.AuthDatabase
+		}
+		_, err = mongodb.NewMongoDBOperationClient(conn).DropUser(ctx, req)
+	case upmv1alpha1.ClickHouseType:
+		_, err = clickhouse.NewClickHouseOperationClient(conn).DropUser(ctx, &clickhouse.DropUserRequest{Username: spec.AdminUsername, User: user})
+	default:
+		return fmt.Errorf("unsupported type %q for database user", spec.Type)
+	}
+
+	return err
+}
+
+// createDatabase creates the database described by the Database on one unit.
+func createDatabase(ctx context.Context, conn grpc.ClientConnInterface, instance *upmv1alpha1.Database) error {
+	spec := instance.Spec
+	name := instance.DatabaseName()
+
+	var err error
+	switch spec.Type {
+	case upmv1alpha1.MysqlType:
+		req := &mysql.CreateDatabaseRequest{Username: spec.AdminUsername, Database: name}
+		if spec.Mysql != nil {
+			req.CharacterSet = spec.Mysql.CharacterSet
+			req.Collation = spec.Mysql.Collation
+		}
+		_, err = mysql.NewMysqlOperationClient(conn).CreateDatabase(ctx, req)
+	case upmv1alpha1.PostgresqlType:
+		req := &postgresql.CreateDatabaseRequest{Username: spec.AdminUsername, Database: name}
+		if spec.Postgresql != nil {
+			req.Owner = spec.Postgresql.Owner
+			req.Encoding = spec.Postgresql.Encoding
+		}
+		_, err = postgresql.NewPostgresqlOperationClient(conn).CreateDatabase(ctx, req)
+	case upmv1alpha1.ClickHouseType:
+		req := &clickhouse.CreateDatabaseRequest{Username: spec.AdminUsername, Database: name}
+		if spec.ClickHouse != nil {
+			req.Engine = spec.ClickHouse.Engine
+		}
+		_, err = clickhouse.NewClickHouseOperationClient(conn).CreateDatabase(ctx, req)
+	default:
+		return fmt.Errorf("unsupported type %q for database", spec.Type)
+	}
+
+	return err
+}
+
+// rotatePassword sets the new password of a user of the CredentialRotation on one unit.
+func rotatePassword(ctx context.Context, conn grpc.ClientConnInterface, instance *upmv1alpha1.CredentialRotation, user upmv1alpha1.CredentialRotationUser, password string) error {
+	spec := instance.Spec
+	retain := spec.RetainCurrentPassword == nil || *spec.RetainCurrentPassword
+
+	var err error
+	switch spec.Type {
+	case upmv1alpha1.MysqlType:
+		_, err = mysql.NewMysqlOperationClient(conn).RotatePassword(ctx, &mysql.RotatePasswordRequest{
+			Username:              spec.AdminUsername,
+			User:                  user.Name,
+			Host:                  user.Host,
+			Password:              password,
+			RetainCurrentPassword: retain,
+		})
+	case upmv1alpha1.PostgresqlType:
+		_, err = postgresql.NewPostgresqlOperationClient(conn).RotatePassword(ctx, &postgresql.RotatePasswordRequest{
+			Username: spec.AdminUsername,
+			User:     user.Name,
+			Password: password,
+		})
+	case upmv1alpha1.RedisType:
+		_, err = redis.NewRedisOperationClient(conn).RotatePassword(ctx, &redis.RotatePasswordRequest{
+			Username:              spec.AdminUsername,
+			User:                  user.Name,
+			Password:              password,
+			RetainCurrentPassword: retain,
+		})
+	case upmv1alpha1.MongoDBType:
+		_, err = mongodb.NewMongoDBOperationClient(conn).RotatePassword(ctx, &mongodb.RotatePasswordRequest{
+			Username:     spec.AdminUsername,
+			User:         user.Name,
+			Password:     password,
+			AuthDatabase: user.AuthDatabase,
+		})
+	default:
+		return fmt.Errorf("unsupported type %q for credential rotation", spec.Type)
+	}
+
+	return err
+}
+
+// syncProxysqlBackend registers the backends and query rules of the ProxysqlBackend on one ProxySQL unit.
+func syncProxysqlBackend(ctx context.Context, conn grpc.ClientConnInterface, instance *upmv1alpha1.ProxysqlBackend, backends []*proxysql.Server) error {
+	spec := instance.Spec
+	client := proxysql.NewProxysqlOperationClient(conn)
+
+	if _, err := client.SyncServers(ctx, &proxysql.SyncServersRequest{
+		Username: spec.AdminUsername,
+		Servers:  backends,
+		ReplicationHostgroup: &proxysql.ReplicationHostgroup{
+			WriterHostgroup: writerHostgroup(instance),
+			ReaderHostgroup: readerHostgroup(instance),
+			CheckType:       spec.CheckType,
+			Comment:         instance.Name,
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to sync servers: %v", err)
+	}
+
+	if spec.QueryRules == nil {
+		return nil
+	}
+
+	rules := make([]*proxysql.QueryRule, 0, len(spec.QueryRules))
+	for _, rule := range spec.QueryRules {
+		rules = append(rules, &proxysql.QueryRule{
+			RuleId:               rule.RuleID,
+			Active:               rule.Active == nil || *rule.Active,
+			User:                 rule.User,
+			Schemaname:           rule.Schemaname,
+			MatchDigest:          rule.MatchDigest,
+			MatchPattern:         rule.MatchPattern,
+			DestinationHostgroup: rule.DestinationHostgroup,
+			Apply:                rule.Apply,
+			Comment:              rule.Comment,
+		})
+	}
+
+	if _, err := client.SyncQueryRules(ctx, &proxysql.SyncQueryRulesRequest{
+		Username: spec.AdminUsername,
+		Rules:    rules,
+	}); err != nil {
+		return fmt.Errorf("failed to sync query rules: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/mysql"
+)
+
+type fakeMysqlAgent struct {
+	mysql.UnimplementedMysqlOperationServer
+
+	mu        sync.Mutex
+	creates   []*mysql.CreateUserRequest
+	drops     []*mysql.DropUserRequest
+	databases []*mysql.CreateDatabaseRequest
+	rotations []*mysql.RotatePasswordRequest
+}
+
+func (f *fakeMysqlAgent) CreateUser(_ context.Context, req *mysql.CreateUserRequest) (*common.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.creates = append(f.creates, req)
+	return &common.Empty{}, nil
+}
+
+func (f *fakeMysqlAgent) DropUser(_ context.Context, req *mysql.DropUserRequest) (*common.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.drops = append(f.drops, req)
+	return &common.Empty{}, nil
+}
+
+func (f *fakeMysqlAgent) CreateDatabase(_ context.Context, req *mysql.CreateDatabaseRequest) (*common.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.databases = append(f.databases, req)
+	return &common.Empty{}, nil
+}
+
+func (f *fakeMysqlAgent) RotatePassword(_ context.Context, req *mysql.RotatePasswordRequest) (*common.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rotations = append(f.rotations, req)
+	return &common.Empty{}, nil
+}
+
+func newFakeMysqlAgent(t *testing.T) (*fakeMysqlAgent, grpc.ClientConnInterface) {
+	t.Helper()
+
+	agent := &fakeMysqlAgent{}
+	dial := startTestAgent(t, func(s *grpc.Server) {
+		mysql.RegisterMysqlOperationServer(s, agent)
+	})
+
+	conn, err := dial(nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return agent, conn
+}
+
+func newTestDatabaseUser(userType upmv1alpha1.UnitType) *upmv1alpha1.DatabaseUser {
+	return &upmv1alpha1.DatabaseUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: upmv1alpha1.DatabaseUserSpec{
+			UnitSet:       "mysql",
+			Type:          userType,
+			AdminUsername: "root",
+			Privileges: []upmv1alpha1.DatabasePrivilege{
+				{Database: "shop", Privileges: []string{"SELECT", "INSERT"}},
+			},
+			Mysql: &upmv1alpha1.MysqlUserOptions{Host: "10.%"},
+		},
+	}
+}
+
+func TestCreateUserMysql(t *testing.T) {
+	agent, conn := newFakeMysqlAgent(t)
+
+	require.NoError(t, createUser(context.Background(), conn, newTestDatabaseUser(upmv1alpha1.MysqlType), "secret",
+		[]upmv1alpha1.DatabasePrivilege{{Database: "shop", Privileges: []string{"DELETE"}}}))
+
+	require.Len(t, agent.creates, 1)
+	req := agent.creates[0]
+	assert.Equal(t, "root", req.GetUsername())
+	assert.Equal(t, "app", req.GetUser())
+	assert.Equal(t, "10.%", req.GetHost())
+	assert.Equal(t, "secret", req.GetPassword())
+	require.Len(t, req.GetPrivileges(), 1)
+	assert.Equal(t, "shop", req.GetPrivileges()[0].GetDatabase())
+	assert.Equal(t, []string{"SELECT", "INSERT"}, req.GetPrivileges()[0].GetPrivileges())
+	require.Len(t, req.GetRevokes(), 1)
+	assert.Equal(t, []string{"DELETE"}, req.GetRevokes()[0].GetPrivileges())
+}
+
+func TestDropUserMysql(t *testing.T) {
+	agent, conn := newFakeMysqlAgent(t)
+
+	require.NoError(t, dropUser(context.Background(), conn, newTestDatabaseUser(upmv1alpha1.MysqlType)))
+
+	require.Len(t, agent.drops, 1)
+	assert.Equal(t, "app", agent.drops[0].GetUser())
+	assert.Equal(t, "10.%", agent.drops[0].GetHost())
+}
+
+func TestCreateDatabaseMysql(t *testing.T) {
+	agent, conn := newFakeMysqlAgent(t)
+
+	instance := &upmv1alpha1.Database{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+		Spec: upmv1alpha1.DatabaseSpec{
+			UnitSet:       "mysql",
+			Type:          upmv1alpha1.MysqlType,
+			AdminUsername: "root",
+			Mysql:         &upmv1alpha1.MysqlDatabaseOptions{CharacterSet: "utf8mb4"},
+		},
+	}
+
+	require.NoError(t, createDatabase(context.Background(), conn, instance))
+
+	require.Len(t, agent.databases, 1)
+	assert.Equal(t, "shop", agent.databases[0].GetDatabase())
+	assert.Equal(t, "utf8mb4", agent.databases[0].GetCharacterSet())
+}
+
+func TestUnsupportedType(t *testing.T) {
+	_, conn := newFakeMysqlAgent(t)
+
+	instance := newTestDatabaseUser(upmv1alpha1.UnitType("milvus"))
+	assert.Error(t, createUser(context.Background(), conn, instance, "secret", nil))
+	assert.Error(t, dropUser(context.Background(), conn, instance))
+}
@@ -0,0 +1,106 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	unitAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// agentCallTimeout bounds a single call to a unit-agent
+	agentCallTimeout = 30 * time.Second
+)
+
+// listUnitSetUnits returns the units of the UnitSet sorted by name, it fails when the UnitSet has no unit.
+func listUnitSetUnits(ctx context.Context, c client.Client, namespace, unitSet string) ([]upmv1alpha2.Unit, error) {
+	units, err := listUnits(ctx, c, namespace, unitSet)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(units) == 0 {
+		return nil, fmt.Errorf("no unit found for unitset [%s/%s]", namespace, unitSet)
+	}
+
+	return units, nil
+}
+
+// listUnits returns the units of the UnitSet sorted by name, which may be none.
+func listUnits(ctx context.Context, c client.Client, namespace, unitSet string) ([]upmv1alpha2.Unit, error) {
+	units := &upmv1alpha2.UnitList{}
+	if err := c.List(ctx, units, client.InNamespace(namespace), client.MatchingLabels{upmv1alpha2.UnitsetName: unitSet}); err != nil {
+		return nil, fmt.Errorf("failed to list units of unitset [%s/%s]: %v", namespace, unitSet, err)
+	}
+
+	sort.Slice(units.Items, func(i, j int) bool {
+		return units.Items[i].Name < units.Items[j].Name
+	})
+
+	return units.Items, nil
+}
+
+// unitNames returns the names of the units.
+func unitNames(units []upmv1alpha2.Unit) []string {
+	names := make([]string, 0, len(units))
+	for i := range units {
+		names = append(names, units[i].Name)
+	}
+
+	return names
+}
+
+// readSecretKey returns the value of the key of the Secret together with the Secret resource version.
+func readSecretKey(ctx context.Context, c client.Client, namespace string, selector corev1.SecretKeySelector) (string, string, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, secret); err != nil {
+		return "", "", fmt.Errorf("failed to fetch secret [%s/%s]: %v", namespace, selector.Name, err)
+	}
+
+	value, ok := secret.Data[selector.Key]
+	if !ok || len(value) == 0 {
+		return "", "", fmt.Errorf("key [%s] not found in secret [%s/%s]", selector.Key, namespace, selector.Name)
+	}
+
+	return string(value), secret.ResourceVersion, nil
+}
+
+// forEachUnit calls fn with a connection to the unit-agent of every unit,
+// errors of all units are aggregated.
+func forEachUnit(
+	ctx context.Context,
+	units []upmv1alpha2.Unit,
+	dial unitAgent.DialFunc,
+	fn func(context.Context, grpc.ClientConnInterface) error,
+) error {
+	var errs []error
+	for i := range units {
+		unit := &units[i]
+
+		if err := unitAgent.Call(ctx, unit, dial, agentCallTimeout, fn); err != nil {
+			errs = append(errs, fmt.Errorf("unit [%s]: %v", unit.Name, err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
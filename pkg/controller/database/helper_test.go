@@ -0,0 +1,123 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/vars"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, upmv1alpha1.AddToScheme(s))
+	require.NoError(t, upmv1alpha2.AddToScheme(s))
+	return s
+}
+
+func newTestUnit(name, unitSet string) *upmv1alpha2.Unit {
+	return &upmv1alpha2.Unit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{upmv1alpha2.UnitsetName: unitSet},
+		},
+		Spec: upmv1alpha2.UnitSpec{
+			Template: upmv1alpha2.UnitPodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  vars.UnitAgentName,
+							Ports: []corev1.ContainerPort{{Name: vars.UnitAgentName, ContainerPort: 2214}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// startTestAgent serves the registered services on a local listener and
+// returns a dial function connecting every unit to it.
+func startTestAgent(t *testing.T, register func(*grpc.Server)) func(*upmv1alpha2.Unit) (*grpc.ClientConn, error) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	register(server)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	return func(*upmv1alpha2.Unit) (*grpc.ClientConn, error) {
+		return grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+}
+
+func TestListUnitSetUnits(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(
+		newTestUnit("mysql-1", "mysql"),
+		newTestUnit("mysql-0", "mysql"),
+		newTestUnit("redis-0", "redis"),
+	).Build()
+
+	units, err := listUnitSetUnits(context.Background(), c, "default", "mysql")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mysql-0", "mysql-1"}, unitNames(units))
+
+	_, err = listUnitSetUnits(context.Background(), c, "default", "missing")
+	assert.Error(t, err)
+}
+
+func TestReadSecretKey(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(secret).Build()
+
+	selector := corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "app"}, Key: "password"}
+	value, version, err := readSecretKey(context.Background(), c, "default", selector)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", value)
+	assert.NotEmpty(t, version)
+
+	selector.Key = "missing"
+	_, _, err = readSecretKey(context.Background(), c, "default", selector)
+	assert.Error(t, err)
+
+	selector.Name = "missing"
+	_, _, err = readSecretKey(context.Background(), c, "default", selector)
+	assert.Error(t, err)
+}
@@ -0,0 +1,231 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"time"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/proxysql"
+	unitAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	proxysqlBackendAppName = "proxysql-backend"
+
+	defaultMysqlPort       = 3306
+	defaultWriterHostgroup = 10
+	defaultReaderHostgroup = 20
+)
+
+// ReconcileProxysqlBackend reconciles ProxysqlBackend resources.
+type ReconcileProxysqlBackend struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	dial     unitAgent.DialFunc
+}
+
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=proxysqlbackends,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=proxysqlbackends/status,verbs=get;update;patch
+
+func (r *ReconcileProxysqlBackend) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.Infof("start reconciling proxysql backend instance [%s]", req.String())
+	startTime := time.Now()
+
+	defer func() {
+		klog.Infof("finished reconciliation proxysql backend instance [%s], duration [%v]", req.String(), time.Since(startTime))
+	}()
+
+	instance := &upmv1alpha1.ProxysqlBackend{}
+	if err := r.client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			klog.Infof("proxysql backend instance [%s] not found, probably deleted.", req.String())
+			return reconcile.Result{}, nil
+		}
+
+		klog.Errorf("failed to fetch proxysql backend instance [%s]: [%v]", req.String(), err.Error())
+		return reconcile.Result{}, err
+	}
+
+	// Deleting a ProxysqlBackend leaves the servers registered, so that traffic keeps flowing
+	if !instance.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	oldStatus := instance.Status.DeepCopy()
+
+	err := func() error {
+		mysqlUnits, err := listUnitSetUnits(ctx, r.client, instance.Namespace, instance.Spec.MysqlUnitSet)
+		if err != nil {
+			return err
+		}
+
+		units, err := listUnitSetUnits(ctx, r.client, instance.Namespace, instance.Spec.UnitSet)
+		if err != nil {
+			return err
+		}
+
+		backends := proxysqlBackends(instance, mysqlUnits)
+		addresses := backendAddresses(backends)
+
+		if instance.Status.Result == upmv1alpha1.SuccessResult &&
+			instance.Status.ObservedGeneration == instance.Generation &&
+			reflect.DeepEqual(instance.Status.Units, unitNames(units)) &&
+			reflect.DeepEqual(instance.Status.Backends, addresses) {
+			return nil
+		}
+
+		if err := forEachUnit(ctx, units, r.dial, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			return syncProxysqlBackend(ctx, conn, instance, backends)
+		}); err != nil {
+			return fmt.Errorf("failed to sync proxysql backends: %v", err)
+		}
+
+		now := metav1.Now()
+		instance.Status.ObservedGeneration = instance.Generation
+		instance.Status.Units = unitNames(units)
+		instance.Status.Backends = addresses
+		instance.Status.LastSyncTime = &now
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, "SyncSucceeded",
+			"backends %v synchronized to units %v", addresses, unitNames(units))
+
+		return nil
+	}()
+
+	result := reconcile.Result{}
+	if err != nil {
+		instance.Status.Result = upmv1alpha1.FailedResult
+		instance.Status.Message = err.Error()
+		r.recorder.Event(instance, corev1.EventTypeWarning, "SyncFailed", err.Error())
+		result.RequeueAfter = retryInterval
+	} else {
+		instance.Status.Result = upmv1alpha1.SuccessResult
+		instance.Status.Message = ""
+	}
+
+	if !reflect.DeepEqual(oldStatus, &instance.Status) {
+		if err := r.client.Status().Update(ctx, instance); err != nil {
+			klog.Errorf("failed to update proxysql backend [%s] status: %v", req.String(), err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// proxysqlBackends returns the MySQL units as servers of the reader hostgroup,
+// the ProxySQL monitor moves the writable source into the writer hostgroup.
+func proxysqlBackends(instance *upmv1alpha1.ProxysqlBackend, units []upmv1alpha2.Unit) []*proxysql.Server {
+	port := instance.Spec.MysqlPort
+	if port == 0 {
+		port = defaultMysqlPort
+	}
+
+	backends := make([]*proxysql.Server, 0, len(units))
+	for i := range units {
+		backends = append(backends, &proxysql.Server{
+			HostgroupId:       readerHostgroup(instance),
+			Hostname:          unitAgent.UnitHost(&units[i]),
+			Port:              port,
+			MaxConnections:    instance.Spec.MaxConnections,
+			MaxReplicationLag: instance.Spec.MaxReplicationLag,
+			Comment:           units[i].Name,
+		})
+	}
+
+	return backends
+}
+
+// backendAddresses returns the host:port of the backends.
+func backendAddresses(backends []*proxysql.Server) []string {
+	addresses := make([]string, 0, len(backends))
+	for _, backend := range backends {
+		addresses = append(addresses, net.JoinHostPort(backend.GetHostname(), strconv.FormatInt(backend.GetPort(), 10)))
+	}
+
+	return addresses
+}
+
+func writerHostgroup(instance *upmv1alpha1.ProxysqlBackend) int64 {
+	if instance.Spec.WriterHostgroup == 0 {
+		return defaultWriterHostgroup
+	}
+
+	return instance.Spec.WriterHostgroup
+}
+
+func readerHostgroup(instance *upmv1alpha1.ProxysqlBackend) int64 {
+	if instance.Spec.ReaderHostgroup == 0 {
+		return defaultReaderHostgroup
+	}
+
+	return instance.Spec.ReaderHostgroup
+}
+
+// unitToProxysqlBackends maps a Unit to the ProxysqlBackends of its UnitSet, either
+// as ProxySQL unit or as MySQL backend, so that scaling is followed.
+func (r *ReconcileProxysqlBackend) unitToProxysqlBackends(ctx context.Context, obj client.Object) []reconcile.Request {
+	unitSet := obj.GetLabels()[upmv1alpha2.UnitsetName]
+	if unitSet == "" {
+		return nil
+	}
+
+	backends := &upmv1alpha1.ProxysqlBackendList{}
+	if err := r.client.List(ctx, backends, client.InNamespace(obj.GetNamespace())); err != nil {
+		klog.Errorf("failed to list proxysql backends in namespace [%s]: %v", obj.GetNamespace(), err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, backend := range backends.Items {
+		if backend.Spec.UnitSet == unitSet || backend.Spec.MysqlUnitSet == unitSet {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: backend.Namespace, Name: backend.Name}})
+		}
+	}
+
+	return requests
+}
+
+func setupProxysqlBackend(mgr ctrl.Manager) error {
+	r := &ReconcileProxysqlBackend{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor(proxysqlBackendAppName),
+		dial:     unitAgent.Dial,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&upmv1alpha1.ProxysqlBackend{}).
+		Watches(&upmv1alpha2.Unit{}, handler.EnqueueRequestsFromMapFunc(r.unitToProxysqlBackends)).
+		Complete(r)
+}
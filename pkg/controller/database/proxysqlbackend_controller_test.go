@@ -0,0 +1,166 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/proxysql"
+)
+
+type fakeProxysqlAgent struct {
+	proxysql.UnimplementedProxysqlOperationServer
+
+	mu      sync.Mutex
+	servers []*proxysql.SyncServersRequest
+	rules   []*proxysql.SyncQueryRulesRequest
+}
+
+func (a *fakeProxysqlAgent) SyncServers(_ context.Context, req *proxysql.SyncServersRequest) (*common.Empty, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.servers = append(a.servers, req)
+	return &common.Empty{}, nil
+}
+
+func (a *fakeProxysqlAgent) SyncQueryRules(_ context.Context, req *proxysql.SyncQueryRulesRequest) (*common.Empty, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rules = append(a.rules, req)
+	return &common.Empty{}, nil
+}
+
+func newTestProxysqlBackend() *upmv1alpha1.ProxysqlBackend {
+	return &upmv1alpha1.ProxysqlBackend{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "default"},
+		Spec: upmv1alpha1.ProxysqlBackendSpec{
+			UnitSet:       "proxysql",
+			AdminUsername: "admin",
+			MysqlUnitSet:  "mysql",
+			CheckType:     "read_only",
+		},
+	}
+}
+
+func newTestProxysqlBackendReconciler(t *testing.T, agent *fakeProxysqlAgent, objs ...client.Object) *ReconcileProxysqlBackend {
+	t.Helper()
+
+	s := newTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithStatusSubresource(&upmv1alpha1.ProxysqlBackend{}).
+		WithObjects(objs...).
+		Build()
+
+	return &ReconcileProxysqlBackend{
+		client:   c,
+		scheme:   s,
+		recorder: record.NewFakeRecorder(10),
+		dial: startTestAgent(t, func(s *grpc.Server) {
+			proxysql.RegisterProxysqlOperationServer(s, agent)
+		}),
+	}
+}
+
+func TestProxysqlBackends(t *testing.T) {
+	instance := newTestProxysqlBackend()
+	instance.Spec.MaxConnections = 500
+
+	backends := proxysqlBackends(instance, nil)
+	assert.Empty(t, backends)
+
+	units := []upmv1alpha2.Unit{*newTestUnit("mysql-0", "mysql"), *newTestUnit("mysql-1", "mysql")}
+	backends = proxysqlBackends(instance, units)
+	require.Len(t, backends, 2)
+	for _, backend := range backends {
+		assert.Equal(t, int64(defaultReaderHostgroup), backend.HostgroupId)
+		assert.Equal(t, int64(defaultMysqlPort), backend.Port)
+		assert.Equal(t, int64(500), backend.MaxConnections)
+	}
+	assert.Equal(t, []string{
+		"mysql-0.mysql-headless-svc.default.svc:3306",
+		"mysql-1.mysql-headless-svc.default.svc:3306",
+	}, backendAddresses(backends))
+}
+
+func TestReconcileProxysqlBackendSync(t *testing.T) {
+	agent := &fakeProxysqlAgent{}
+	r := newTestProxysqlBackendReconciler(t, agent, newTestProxysqlBackend(),
+		newTestUnit("mysql-0", "mysql"), newTestUnit("mysql-1", "mysql"),
+		newTestUnit("proxysql-0", "proxysql"), newTestUnit("proxysql-1", "proxysql"))
+
+	key := types.NamespacedName{Namespace: "default", Name: "backend"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	require.Len(t, agent.servers, 2)
+	req := agent.servers[0]
+	assert.Equal(t, "admin", req.Username)
+	require.Len(t, req.Servers, 2)
+	assert.Equal(t, "mysql-0.mysql-headless-svc.default.svc", req.Servers[0].Hostname)
+	require.NotNil(t, req.ReplicationHostgroup)
+	assert.Equal(t, int64(defaultWriterHostgroup), req.ReplicationHostgroup.WriterHostgroup)
+	assert.Equal(t, int64(defaultReaderHostgroup), req.ReplicationHostgroup.ReaderHostgroup)
+	assert.Equal(t, "backend", req.ReplicationHostgroup.Comment)
+	assert.Empty(t, agent.rules)
+
+	instance := &upmv1alpha1.ProxysqlBackend{}
+	require.NoError(t, r.client.Get(context.Background(), key, instance))
+	assert.Equal(t, upmv1alpha1.SuccessResult, instance.Status.Result)
+	assert.Equal(t, []string{"proxysql-0", "proxysql-1"}, instance.Status.Units)
+	assert.Len(t, instance.Status.Backends, 2)
+	assert.NotNil(t, instance.Status.LastSyncTime)
+
+	// an unchanged topology is not synchronized again
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Len(t, agent.servers, 2)
+}
+
+func TestReconcileProxysqlBackendQueryRules(t *testing.T) {
+	instance := newTestProxysqlBackend()
+	instance.Spec.QueryRules = []upmv1alpha1.ProxysqlQueryRule{
+		{RuleID: 1, MatchDigest: "^SELECT .* FOR UPDATE$", DestinationHostgroup: 10, Apply: true},
+		{RuleID: 2, MatchDigest: "^SELECT", DestinationHostgroup: 20, Apply: true},
+	}
+
+	agent := &fakeProxysqlAgent{}
+	r := newTestProxysqlBackendReconciler(t, agent, instance,
+		newTestUnit("mysql-0", "mysql"), newTestUnit("proxysql-0", "proxysql"))
+
+	key := types.NamespacedName{Namespace: "default", Name: "backend"}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.Len(t, agent.rules, 1)
+	rules := agent.rules[0].Rules
+	require.Len(t, rules, 2)
+	assert.True(t, rules[0].Active)
+	assert.Equal(t, int64(20), rules[1].DestinationHostgroup)
+}
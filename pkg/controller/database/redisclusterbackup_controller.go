@@ -0,0 +1,595 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/rediscluster"
+	unitAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	redisClusterBackupAppName = "redis-cluster-backup"
+
+	// redisClusterManifestName is the object, under the backup prefix, holding the slot map
+	redisClusterManifestName = "cluster.json"
+
+	// transferPollInterval is how often the unit-agent operations transferring the shards are polled
+	transferPollInterval = 5 * time.Second
+
+	defaultPauseTimeout = 10 * time.Second
+
+	defaultBackoffLimit = 3
+
+	clusterSlots = 16384
+
+	accessKeyName = "accessKey"
+	secretKeyName = "secretKey"
+)
+
+// redisClusterManifest is the cluster.json stored alongside the shard snapshots.
+type redisClusterManifest struct {
+	UnitSet   string                          `json:"unitSet"`
+	CreatedAt metav1.Time                     `json:"createdAt"`
+	Shards    []upmv1alpha1.RedisClusterShard `json:"shards"`
+}
+
+// ReconcileRedisClusterBackup reconciles RedisClusterBackup resources.
+type ReconcileRedisClusterBackup struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	dial     unitAgent.DialFunc
+	storage  func(*common.ObjectStorage) (common.ObjectStorageFactory, error)
+}
+
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=redisclusterbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=redisclusterbackups/status,verbs=get;update;patch
+
+func (r *ReconcileRedisClusterBackup) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.Infof("start reconciling redis cluster backup instance [%s]", req.String())
+	startTime := time.Now()
+
+	defer func() {
+		klog.Infof("finished reconciliation redis cluster backup instance [%s], duration [%v]", req.String(), time.Since(startTime))
+	}()
+
+	instance := &upmv1alpha1.RedisClusterBackup{}
+	if err := r.client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Infof("redis cluster backup instance [%s] not found, probably deleted.", req.String())
+			return reconcile.Result{}, nil
+		}
+
+		klog.Errorf("failed to fetch redis cluster backup instance [%s]: [%v]", req.String(), err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if !instance.DeletionTimestamp.IsZero() ||
+		instance.Status.Phase == upmv1alpha1.RedisClusterBackupSucceeded ||
+		instance.Status.Phase == upmv1alpha1.RedisClusterBackupFailed {
+		return reconcile.Result{}, nil
+	}
+
+	oldStatus := instance.Status.DeepCopy()
+	if instance.Status.StartTime == nil {
+		now := metav1.Now()
+		instance.Status.StartTime = &now
+	}
+
+	requeueAfter, err := r.backup(ctx, instance)
+
+	result := reconcile.Result{RequeueAfter: requeueAfter}
+	if err != nil {
+		instance.Status.Attempts++
+		instance.Status.Result = upmv1alpha1.FailedResult
+		instance.Status.Message = err.Error()
+		r.recorder.Event(instance, corev1.EventTypeWarning, "BackupFailed", err.Error())
+
+		if limit := backoffLimit(instance); instance.Status.Attempts >= limit {
+			instance.Status.Phase = upmv1alpha1.RedisClusterBackupFailed
+			r.recorder.Eventf(instance, corev1.EventTypeWarning, "BackoffLimitExceeded", "backup failed %d attempts, giving up", limit)
+			result.RequeueAfter = 0
+		} else {
+			result.RequeueAfter = retryInterval
+		}
+	} else {
+		instance.Status.Result = upmv1alpha1.SuccessResult
+		instance.Status.Message = ""
+		if instance.Status.Phase == upmv1alpha1.RedisClusterBackupSucceeded {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, "BackupSucceeded",
+				"%d shards backed up to %s", len(instance.Status.Shards), instance.Status.Manifest)
+		}
+	}
+
+	if !reflect.DeepEqual(oldStatus, &instance.Status) {
+		if err := r.client.Status().Update(ctx, instance); err != nil {
+			klog.Errorf("failed to update redis cluster backup [%s] status: %v", req.String(), err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// backup runs the current phase and advances to the next one once it is done,
+// it returns when the backup should be reconciled again.
+func (r *ReconcileRedisClusterBackup) backup(ctx context.Context, instance *upmv1alpha1.RedisClusterBackup) (time.Duration, error) {
+	switch instance.Status.Phase {
+	case "", upmv1alpha1.RedisClusterBackupSnapshotting:
+		instance.Status.Phase = upmv1alpha1.RedisClusterBackupSnapshotting
+		if err := r.snapshot(ctx, instance); err != nil {
+			return 0, err
+		}
+
+		// The writes are never paused again from here on
+		instance.Status.Phase = upmv1alpha1.RedisClusterBackupUploading
+		return time.Second, nil
+
+	case upmv1alpha1.RedisClusterBackupUploading:
+		done, err := r.upload(ctx, instance)
+		if err != nil {
+			return 0, err
+		}
+		if !done {
+			return transferPollInterval, nil
+		}
+
+		if err := r.putManifest(ctx, instance); err != nil {
+			return 0, err
+		}
+
+		now := metav1.Now()
+		instance.Status.Phase = upmv1alpha1.RedisClusterBackupSucceeded
+		instance.Status.CompletionTime = &now
+	}
+
+	return 0, nil
+}
+
+// snapshot snapshots every master shard in one write pause and records the shards to upload.
+func (r *ReconcileRedisClusterBackup) snapshot(ctx context.Context, instance *upmv1alpha1.RedisClusterBackup) error {
+	units, err := listUnitSetUnits(ctx, r.client, instance.Namespace, instance.Spec.UnitSet)
+	if err != nil {
+		return err
+	}
+
+	masters, err := clusterMasters(ctx, r.dial, instance.Spec.Username, units)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := r.snapshotShards(ctx, instance, masters)
+	if err != nil {
+		return err
+	}
+
+	prefix := backupPrefix(instance)
+	shards := make([]upmv1alpha1.RedisClusterShard, 0, len(snapshots))
+	transfers := make([]upmv1alpha1.RedisClusterTransfer, 0, len(snapshots))
+	for i, snapshot := range snapshots {
+		shards = append(shards, upmv1alpha1.RedisClusterShard{
+			NodeID: snapshot.GetNodeId(),
+			Unit:   masters[i].Name,
+			Slots:  slotRangeStrings(snapshot.GetSlots()),
+			Keys:   snapshot.GetKeys(),
+			Object: path.Join(prefix, fmt.Sprintf("shard-%d.rdb", i)),
+		})
+		transfers = append(transfers, upmv1alpha1.RedisClusterTransfer{
+			Unit:     masters[i].Name,
+			LastSave: snapshot.GetLastSave(),
+		})
+	}
+
+	instance.Status.Shards = shards
+	instance.Status.Transfers = transfers
+	return nil
+}
+
+// putManifest uploads the manifest of the uploaded shards.
+func (r *ReconcileRedisClusterBackup) putManifest(ctx context.Context, instance *upmv1alpha1.RedisClusterBackup) error {
+	storage, err := objectStorage(ctx, r.client, instance.Namespace, instance.Spec.ObjectStorage)
+	if err != nil {
+		return err
+	}
+
+	factory, err := r.storage(storage)
+	if err != nil {
+		return fmt.Errorf("failed to generate storage factory: %v", err)
+	}
+
+	data, err := json.Marshal(&redisClusterManifest{
+		UnitSet:   instance.Spec.UnitSet,
+		CreatedAt: metav1.Now(),
+		Shards:    instance.Status.Shards,
+	})
+	if err != nil {
+		return err
+	}
+
+	manifest := path.Join(backupPrefix(instance), redisClusterManifestName)
+	if err := factory.PutObject(ctx, storage.GetBucket(), manifest, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("failed to put manifest: %v", err)
+	}
+
+	instance.Status.Manifest = manifest
+	return nil
+}
+
+// snapshotShards pauses the writes of every master before forking the snapshot of any, so that
+// no write on a shard can follow a write missing from the snapshot of another shard.
+func (r *ReconcileRedisClusterBackup) snapshotShards(
+	ctx context.Context,
+	instance *upmv1alpha1.RedisClusterBackup,
+	masters []upmv1alpha2.Unit,
+) ([]*rediscluster.SnapshotShardResponse, error) {
+	username := instance.Spec.Username
+	timeout := instance.Spec.PauseTimeout.Duration
+	if timeout <= 0 {
+		timeout = defaultPauseTimeout
+	}
+
+	unpause := func() {
+		_ = forEachUnit(ctx, masters, r.dial, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			_, err := rediscluster.NewRedisClusterOperationClient(conn).UnpauseWrites(ctx, &rediscluster.UnpauseWritesRequest{Username: username})
+			return err
+		})
+	}
+
+	pausedAt := time.Now()
+	if err := forEachUnit(ctx, masters, r.dial, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+		_, err := rediscluster.NewRedisClusterOperationClient(conn).PauseWrites(ctx, &rediscluster.PauseWritesRequest{
+			Username:  username,
+			TimeoutMs: timeout.Milliseconds(),
+		})
+		return err
+	}); err != nil {
+		unpause()
+		return nil, fmt.Errorf("failed to pause writes: %v", err)
+	}
+
+	snapshots := make([]*rediscluster.SnapshotShardResponse, 0, len(masters))
+	for i := range masters {
+		if err := unitAgent.Call(ctx, &masters[i], r.dial, agentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			resp, err := rediscluster.NewRedisClusterOperationClient(conn).SnapshotShard(ctx, &rediscluster.SnapshotShardRequest{Username: username})
+			if err != nil {
+				return err
+			}
+
+			snapshots = append(snapshots, resp)
+			return nil
+		}); err != nil {
+			unpause()
+			return nil, fmt.Errorf("failed to snapshot unit [%s]: %v", masters[i].Name, err)
+		}
+	}
+
+	if elapsed := time.Since(pausedAt); elapsed >= timeout {
+		return nil, fmt.Errorf("write pause of %v expired before every shard was snapshotted, took %v", timeout, elapsed)
+	}
+
+	return snapshots, nil
+}
+
+// upload starts the upload of every shard not uploaded yet as a unit-agent operation and polls
+// the uploads in progress, all shards in parallel. It reports done once every shard was uploaded.
+func (r *ReconcileRedisClusterBackup) upload(ctx context.Context, instance *upmv1alpha1.RedisClusterBackup) (bool, error) {
+	storage, err := objectStorage(ctx, r.client, instance.Namespace, instance.Spec.ObjectStorage)
+	if err != nil {
+		return false, err
+	}
+
+	units, err := listUnitSetUnits(ctx, r.client, instance.Namespace, instance.Spec.UnitSet)
+	if err != nil {
+		return false, err
+	}
+
+	return transferShards(ctx, r.dial, units, instance.Status.Transfers, instance.UID,
+		func(i int, _ *upmv1alpha2.Unit) transferCall {
+			req := &rediscluster.UploadShardRequest{
+				Username:      instance.Spec.Username,
+				BackupFile:    instance.Status.Shards[i].Object,
+				ObjectStorage: storage,
+				LastSave:      instance.Status.Transfers[i].LastSave,
+			}
+
+			return func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) error {
+				_, err := rediscluster.NewRedisClusterOperationClient(conn).UploadShard(ctx, req, opts...)
+				return err
+			}
+		})
+}
+
+// transferCall starts the transfer of a shard on the unit-agent connected by conn.
+type transferCall func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) error
+
+// transferShards runs transferShard for every transfer not completed yet, all in parallel, call
+// returns the call starting the transfer i of the unit. It reports done once every transfer completed.
+func transferShards(
+	ctx context.Context,
+	dial unitAgent.DialFunc,
+	units []upmv1alpha2.Unit,
+	transfers []upmv1alpha1.RedisClusterTransfer,
+	owner types.UID,
+	call func(i int, unit *upmv1alpha2.Unit) transferCall,
+) (bool, error) {
+	byName := make(map[string]*upmv1alpha2.Unit, len(units))
+	for i := range units {
+		byName[units[i].Name] = &units[i]
+	}
+
+	errs := make([]error, len(transfers))
+
+	var wg sync.WaitGroup
+	for i := range transfers {
+		if transfers[i].Completed {
+			continue
+		}
+
+		unit, ok := byName[transfers[i].Unit]
+		if !ok {
+			errs[i] = fmt.Errorf("unit [%s] not found", transfers[i].Unit)
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = transferShard(ctx, dial, unit, &transfers[i], owner, call(i, unit))
+		}(i)
+	}
+	wg.Wait()
+
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		return false, err
+	}
+
+	for _, transfer := range transfers {
+		if !transfer.Completed {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// transferShard starts the transfer of a shard as a unit-agent operation, unless one is in
+// progress already, and polls it. An operation which failed is forgotten, so that the next
+// reconciliation starts a new one.
+func transferShard(
+	ctx context.Context,
+	dial unitAgent.DialFunc,
+	unit *upmv1alpha2.Unit,
+	transfer *upmv1alpha1.RedisClusterTransfer,
+	owner types.UID,
+	call transferCall,
+) error {
+	if transfer.OperationID == "" {
+		id := transferOperationID(owner, unit.Name, transfer.Failures)
+		started, err := unitAgent.StartOperation(ctx, unit, dial, agentCallTimeout, id, call)
+		if err != nil {
+			return fmt.Errorf("failed to start the transfer of unit [%s]: %v", unit.Name, err)
+		}
+
+		// The unit-agent ran the transfer synchronously
+		if started == "" {
+			transfer.Completed = true
+			return nil
+		}
+
+		transfer.OperationID = started
+	}
+
+	done, _, err := unitAgent.PollOperation(ctx, unit, dial, agentCallTimeout, transfer.OperationID)
+	if err != nil {
+		if errors.Is(err, unitAgent.ErrOperationFailed) {
+			transfer.OperationID = ""
+			transfer.Failures++
+		}
+		return err
+	}
+
+	transfer.Completed = done
+	return nil
+}
+
+// transferOperationID returns the id of the unit-agent operation transferring the shard of the
+// unit. A start retried after an operator restart joins the operation, an operation started after
+// a failed one gets a new id.
+func transferOperationID(owner types.UID, unit string, failures int32) string {
+	return fmt.Sprintf("%s-%s-%d", owner, unit, failures)
+}
+
+// clusterMasters returns the units serving slots as masters, the slots of all masters must
+// cover the whole cluster. Units that cannot be reached only fail the backup when they leave
+// slots uncovered.
+func clusterMasters(
+	ctx context.Context,
+	dial unitAgent.DialFunc,
+	username string,
+	units []upmv1alpha2.Unit,
+) ([]upmv1alpha2.Unit, error) {
+	var (
+		masters []upmv1alpha2.Unit
+		slots   []*rediscluster.SlotRange
+		errs    []error
+	)
+
+	for i := range units {
+		var nodes *rediscluster.ClusterNodesResponse
+		if err := unitAgent.Call(ctx, &units[i], dial, agentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			resp, err := rediscluster.NewRedisClusterOperationClient(conn).ClusterNodes(ctx, &rediscluster.ClusterNodesRequest{Username: username})
+			nodes = resp
+			return err
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("unit [%s]: %v", units[i].Name, err))
+			continue
+		}
+
+		myself := nodes.Myself()
+		if !myself.IsMaster() || len(myself.GetSlots()) == 0 {
+			continue
+		}
+
+		masters = append(masters, units[i])
+		slots = append(slots, myself.GetSlots()...)
+	}
+
+	if covered := slotCount(slots); covered != clusterSlots {
+		errs = append(errs, fmt.Errorf("masters serve %d of %d slots", covered, clusterSlots))
+		return nil, utilerrors.NewAggregate(errs)
+	}
+
+	return masters, nil
+}
+
+// objectStorage returns the object storage of the spec with the credentials of its Secret.
+func objectStorage(ctx context.Context, c client.Client, namespace string, spec upmv1alpha1.ObjectStorageSpec) (*common.ObjectStorage, error) {
+	storageType := common.ObjectStorageType_Minio
+	if spec.Type != "" && spec.Type != "minio" {
+		return nil, fmt.Errorf("unsupported object storage type [%s]", spec.Type)
+	}
+
+	accessKey, _, err := readSecretKey(ctx, c, namespace, corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: spec.CredentialsSecret},
+		Key:                  accessKeyName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	secretKey, _, err := readSecretKey(ctx, c, namespace, corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: spec.CredentialsSecret},
+		Key:                  secretKeyName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.ObjectStorage{
+		Endpoint:  spec.Endpoint,
+		Bucket:    spec.Bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		Ssl:       spec.SSL,
+		Type:      storageType,
+	}, nil
+}
+
+func backoffLimit(instance *upmv1alpha1.RedisClusterBackup) int32 {
+	if instance.Spec.BackoffLimit > 0 {
+		return instance.Spec.BackoffLimit
+	}
+
+	return defaultBackoffLimit
+}
+
+func backupPrefix(instance *upmv1alpha1.RedisClusterBackup) string {
+	if instance.Spec.Prefix != "" {
+		return strings.Trim(instance.Spec.Prefix, "/")
+	}
+
+	return path.Join(instance.Namespace, instance.Name)
+}
+
+// slotRangeStrings formats slot ranges as "start-end".
+func slotRangeStrings(ranges []*rediscluster.SlotRange) []string {
+	values := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		values = append(values, fmt.Sprintf("%d-%d", r.GetStart(), r.GetEnd()))
+	}
+
+	return values
+}
+
+// parseSlotRanges parses slot ranges formatted by slotRangeStrings.
+func parseSlotRanges(values []string) ([]*rediscluster.SlotRange, error) {
+	ranges := make([]*rediscluster.SlotRange, 0, len(values))
+	for _, value := range values {
+		start, end, found := strings.Cut(value, "-")
+		if !found {
+			end = start
+		}
+
+		s, err := strconv.ParseInt(start, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slot range [%s]", value)
+		}
+
+		e, err := strconv.ParseInt(end, 10, 64)
+		if err != nil || s < 0 || e >= clusterSlots || s > e {
+			return nil, fmt.Errorf("invalid slot range [%s]", value)
+		}
+
+		ranges = append(ranges, &rediscluster.SlotRange{Start: s, End: e})
+	}
+
+	return ranges, nil
+}
+
+// slotCount returns the number of distinct slots of the ranges.
+func slotCount(ranges []*rediscluster.SlotRange) int {
+	var slots [clusterSlots]bool
+	count := 0
+	for _, r := range ranges {
+		for i := max(r.GetStart(), 0); i <= r.GetEnd() && i < clusterSlots; i++ {
+			if !slots[i] {
+				slots[i] = true
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+func setupRedisClusterBackup(mgr ctrl.Manager) error {
+	r := &ReconcileRedisClusterBackup{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor(redisClusterBackupAppName),
+		dial:     unitAgent.Dial,
+		storage:  (*common.ObjectStorage).GenerateFactory,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&upmv1alpha1.RedisClusterBackup{}).
+		Complete(r)
+}
@@ -0,0 +1,447 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
+	"github.com/upmio/unit-operator/pkg/agent/app/rediscluster"
+	"github.com/upmio/unit-operator/pkg/agent/app/slm"
+)
+
+// fakeClusterNode is the unit-agent of a single Redis Cluster node.
+type fakeClusterNode struct {
+	rediscluster.UnimplementedRedisClusterOperationServer
+	slm.UnimplementedServiceLifecycleServer
+	operation.UnimplementedOperationsServer
+
+	mu      sync.Mutex
+	id      string
+	master  bool
+	running bool
+	// async runs the transfers as operations, which stay running until finishOperation
+	async      bool
+	operations map[string]*operation.Operation
+	slots      []*rediscluster.SlotRange
+	known      int
+	paused     int
+	uploads    []*rediscluster.UploadShardRequest
+	restores   []*rediscluster.RestoreShardRequest
+	meets      []*rediscluster.MeetNodeRequest
+	assigned   []*rediscluster.AssignSlotsRequest
+	replicas   []*rediscluster.ReplicateRequest
+}
+
+func (n *fakeClusterNode) ClusterNodes(context.Context, *rediscluster.ClusterNodesRequest) (*rediscluster.ClusterNodesResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	flags := []string{"myself", "slave"}
+	if n.master {
+		flags = []string{"myself", "master"}
+	}
+
+	resp := &rediscluster.ClusterNodesResponse{
+		MyId:  n.id,
+		State: "ok",
+		Nodes: []*rediscluster.ClusterNode{{Id: n.id, Flags: flags, Slots: n.slots}},
+	}
+	for i := 1; i < n.known; i++ {
+		resp.Nodes = append(resp.Nodes, &rediscluster.ClusterNode{Id: "peer", Flags: []string{"master"}})
+	}
+
+	return resp, nil
+}
+
+func (n *fakeClusterNode) CheckProcessStopped(context.Context, *common.Empty) (*common.Empty, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.running {
+		return nil, fmt.Errorf("process is not stopped")
+	}
+	return &common.Empty{}, nil
+}
+
+func (n *fakeClusterNode) PauseWrites(context.Context, *rediscluster.PauseWritesRequest) (*common.Empty, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.paused++
+	return &common.Empty{}, nil
+}
+
+func (n *fakeClusterNode) SnapshotShard(context.Context, *rediscluster.SnapshotShardRequest) (*rediscluster.SnapshotShardResponse, error) {
+	return &rediscluster.SnapshotShardResponse{NodeId: n.id, Slots: n.slots, Keys: 10, LastSave: 100}, nil
+}
+
+func (n *fakeClusterNode) UploadShard(ctx context.Context, req *rediscluster.UploadShardRequest) (*common.Empty, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.uploads = append(n.uploads, req)
+	return n.startOperation(ctx)
+}
+
+func (n *fakeClusterNode) RestoreShard(ctx context.Context, req *rediscluster.RestoreShardRequest) (*common.Empty, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.restores = append(n.restores, req)
+	return n.startOperation(ctx)
+}
+
+// startOperation starts the call as the operation asked for by its metadata when the node is async.
+func (n *fakeClusterNode) startOperation(ctx context.Context) (*common.Empty, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if !n.async || len(md.Get(operation.AsyncMetadataKey)) == 0 {
+		return &common.Empty{}, nil
+	}
+
+	id := md.Get(operation.IDMetadataKey)[0]
+	if n.operations == nil {
+		n.operations = make(map[string]*operation.Operation)
+	}
+	if _, ok := n.operations[id]; !ok {
+		n.operations[id] = &operation.Operation{Id: id, State: operation.State_RUNNING}
+	}
+
+	return &common.Empty{}, grpc.SetHeader(ctx, metadata.Pairs(operation.IDMetadataKey, id))
+}
+
+// finishOperations moves the running operations of the node to state.
+func (n *fakeClusterNode) finishOperations(state operation.State) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, op := range n.operations {
+		if op.State == operation.State_RUNNING {
+			op.State = state
+		}
+	}
+}
+
+func (n *fakeClusterNode) GetOperation(_ context.Context, req *operation.GetOperationRequest) (*operation.Operation, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	op, ok := n.operations[req.GetId()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "operation %s not found", req.GetId())
+	}
+	return proto.Clone(op).(*operation.Operation), nil
+}
+
+func (n *fakeClusterNode) MeetNode(_ context.Context, req *rediscluster.MeetNodeRequest) (*common.Empty, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.meets = append(n.meets, req)
+	return &common.Empty{}, nil
+}
+
+func (n *fakeClusterNode) AssignSlots(_ context.Context, req *rediscluster.AssignSlotsRequest) (*common.Empty, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.assigned = append(n.assigned, req)
+	return &common.Empty{}, nil
+}
+
+func (n *fakeClusterNode) Replicate(_ context.Context, req *rediscluster.ReplicateRequest) (*common.Empty, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.replicas = append(n.replicas, req)
+	return &common.Empty{}, nil
+}
+
+// fakeObjectStorage keeps the objects in memory.
+type fakeObjectStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *fakeObjectStorage) PutFile(context.Context, string, string, string) error { return nil }
+
+func (s *fakeObjectStorage) GetFile(context.Context, string, string, string) error { return nil }
+
+func (s *fakeObjectStorage) PutObject(_ context.Context, bucket, objectName string, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[bucket+"/"+objectName] = data
+	return nil
+}
+
+func (s *fakeObjectStorage) GetObject(_ context.Context, bucket, objectName string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return io.NopCloser(bytes.NewReader(s.objects[bucket+"/"+objectName])), nil
+}
+
+// startClusterNodes starts an agent per node and returns a dial function routing each unit to its agent.
+func startClusterNodes(t *testing.T, nodes map[string]*fakeClusterNode) func(*upmv1alpha2.Unit) (*grpc.ClientConn, error) {
+	t.Helper()
+
+	dials := make(map[string]func(*upmv1alpha2.Unit) (*grpc.ClientConn, error), len(nodes))
+	for name, node := range nodes {
+		dials[name] = startTestAgent(t, func(s *grpc.Server) {
+			rediscluster.RegisterRedisClusterOperationServer(s, node)
+			slm.RegisterServiceLifecycleServer(s, node)
+			operation.RegisterOperationsServer(s, node)
+		})
+	}
+
+	return func(unit *upmv1alpha2.Unit) (*grpc.ClientConn, error) {
+		return dials[unit.Name](unit)
+	}
+}
+
+func newTestCredentialsSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3", Namespace: "default"},
+		Data:       map[string][]byte{accessKeyName: []byte("ak"), secretKeyName: []byte("sk")},
+	}
+}
+
+func newTestObjectStorageSpec() upmv1alpha1.ObjectStorageSpec {
+	return upmv1alpha1.ObjectStorageSpec{Endpoint: "minio:9000", Bucket: "backup", CredentialsSecret: "s3"}
+}
+
+func newTestClusterNodes() map[string]*fakeClusterNode {
+	return map[string]*fakeClusterNode{
+		"redis-0": {id: "node-0", master: true, slots: []*rediscluster.SlotRange{{Start: 0, End: 8191}}, known: 3},
+		"redis-1": {id: "node-1", master: true, slots: []*rediscluster.SlotRange{{Start: 8192, End: 16383}}, known: 3},
+		"redis-2": {id: "node-2", known: 3},
+	}
+}
+
+func newTestRedisClusterBackupReconciler(
+	t *testing.T,
+	nodes map[string]*fakeClusterNode,
+	storage *fakeObjectStorage,
+	objs ...client.Object,
+) *ReconcileRedisClusterBackup {
+	t.Helper()
+
+	s := newTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithStatusSubresource(&upmv1alpha1.RedisClusterBackup{}).
+		WithObjects(objs...).
+		Build()
+
+	return &ReconcileRedisClusterBackup{
+		client:   c,
+		scheme:   s,
+		recorder: record.NewFakeRecorder(10),
+		dial:     startClusterNodes(t, nodes),
+		storage: func(*common.ObjectStorage) (common.ObjectStorageFactory, error) {
+			return storage, nil
+		},
+	}
+}
+
+func newTestRedisClusterBackup() *upmv1alpha1.RedisClusterBackup {
+	return &upmv1alpha1.RedisClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default", UID: "backup-uid"},
+		Spec: upmv1alpha1.RedisClusterBackupSpec{
+			UnitSet:       "redis",
+			Username:      "admin",
+			ObjectStorage: newTestObjectStorageSpec(),
+		},
+	}
+}
+
+func TestReconcileRedisClusterBackup(t *testing.T) {
+	nodes := newTestClusterNodes()
+	storage := &fakeObjectStorage{objects: map[string][]byte{}}
+	r := newTestRedisClusterBackupReconciler(t, nodes, storage, newTestRedisClusterBackup(), newTestCredentialsSecret(),
+		newTestUnit("redis-0", "redis"), newTestUnit("redis-1", "redis"), newTestUnit("redis-2", "redis"))
+
+	key := types.NamespacedName{Namespace: "default", Name: "nightly"}
+	instance := &upmv1alpha1.RedisClusterBackup{}
+	step := func(phase upmv1alpha1.RedisClusterBackupPhase) ctrl.Result {
+		t.Helper()
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		require.NoError(t, r.client.Get(context.Background(), key, instance))
+		require.Equal(t, phase, instance.Status.Phase, instance.Status.Message)
+		return result
+	}
+
+	step(upmv1alpha1.RedisClusterBackupUploading)
+	assert.Equal(t, 1, nodes["redis-0"].paused)
+	assert.Equal(t, 1, nodes["redis-1"].paused)
+	assert.Zero(t, nodes["redis-2"].paused)
+	assert.Empty(t, nodes["redis-0"].uploads)
+	require.Len(t, instance.Status.Transfers, 2)
+	assert.Equal(t, upmv1alpha1.RedisClusterTransfer{Unit: "redis-0", LastSave: 100}, instance.Status.Transfers[0])
+
+	result := step(upmv1alpha1.RedisClusterBackupSucceeded)
+	assert.Zero(t, result.RequeueAfter)
+
+	require.Len(t, nodes["redis-0"].uploads, 1)
+	upload := nodes["redis-0"].uploads[0]
+	assert.Equal(t, "default/nightly/shard-0.rdb", upload.BackupFile)
+	assert.Equal(t, int64(100), upload.LastSave)
+	assert.Equal(t, "ak", upload.ObjectStorage.AccessKey)
+	require.Len(t, nodes["redis-1"].uploads, 1)
+	assert.Equal(t, "default/nightly/shard-1.rdb", nodes["redis-1"].uploads[0].BackupFile)
+	assert.Empty(t, nodes["redis-2"].uploads)
+
+	manifest := &redisClusterManifest{}
+	require.NoError(t, json.Unmarshal(storage.objects["backup/default/nightly/cluster.json"], manifest))
+	require.Len(t, manifest.Shards, 2)
+	assert.Equal(t, "node-1", manifest.Shards[1].NodeID)
+	assert.Equal(t, []string{"8192-16383"}, manifest.Shards[1].Slots)
+
+	assert.Equal(t, upmv1alpha1.SuccessResult, instance.Status.Result)
+	assert.Equal(t, "default/nightly/cluster.json", instance.Status.Manifest)
+	assert.Equal(t, manifest.Shards, instance.Status.Shards)
+	assert.NotNil(t, instance.Status.CompletionTime)
+
+	// a completed backup is not taken again
+	step(upmv1alpha1.RedisClusterBackupSucceeded)
+	assert.Equal(t, 1, nodes["redis-0"].paused)
+	assert.Len(t, nodes["redis-0"].uploads, 1)
+}
+
+func TestReconcileRedisClusterBackupRetriesFailedUpload(t *testing.T) {
+	nodes := newTestClusterNodes()
+	for _, node := range nodes {
+		node.async = true
+	}
+	storage := &fakeObjectStorage{objects: map[string][]byte{}}
+	r := newTestRedisClusterBackupReconciler(t, nodes, storage, newTestRedisClusterBackup(), newTestCredentialsSecret(),
+		newTestUnit("redis-0", "redis"), newTestUnit("redis-1", "redis"), newTestUnit("redis-2", "redis"))
+
+	key := types.NamespacedName{Namespace: "default", Name: "nightly"}
+	instance := &upmv1alpha1.RedisClusterBackup{}
+	reconcileBackup := func() ctrl.Result {
+		t.Helper()
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		require.NoError(t, r.client.Get(context.Background(), key, instance))
+		return result
+	}
+
+	reconcileBackup()
+	require.Equal(t, upmv1alpha1.RedisClusterBackupUploading, instance.Status.Phase)
+
+	// the uploads run as operations on the unit-agents
+	result := reconcileBackup()
+	assert.Equal(t, transferPollInterval, result.RequeueAfter)
+	assert.Equal(t, "backup-uid-redis-0-0", instance.Status.Transfers[0].OperationID)
+	assert.Equal(t, "backup-uid-redis-1-0", instance.Status.Transfers[1].OperationID)
+
+	nodes["redis-0"].finishOperations(operation.State_FAILED)
+	nodes["redis-1"].finishOperations(operation.State_SUCCEEDED)
+
+	result = reconcileBackup()
+	assert.Equal(t, retryInterval, result.RequeueAfter)
+	assert.Equal(t, upmv1alpha1.RedisClusterBackupUploading, instance.Status.Phase)
+	assert.Equal(t, upmv1alpha1.FailedResult, instance.Status.Result)
+	assert.Equal(t, int32(1), instance.Status.Attempts)
+	assert.Empty(t, instance.Status.Transfers[0].OperationID)
+	assert.Equal(t, int32(1), instance.Status.Transfers[0].Failures)
+	assert.True(t, instance.Status.Transfers[1].Completed)
+
+	// the retry uploads the failed shard only and does not pause the writes again
+	reconcileBackup()
+	assert.Equal(t, "backup-uid-redis-0-1", instance.Status.Transfers[0].OperationID)
+	assert.Len(t, nodes["redis-0"].uploads, 2)
+	assert.Len(t, nodes["redis-1"].uploads, 1)
+	assert.Equal(t, 1, nodes["redis-0"].paused)
+
+	nodes["redis-0"].finishOperations(operation.State_SUCCEEDED)
+
+	reconcileBackup()
+	assert.Equal(t, upmv1alpha1.RedisClusterBackupSucceeded, instance.Status.Phase)
+	assert.Contains(t, storage.objects, "backup/default/nightly/cluster.json")
+	assert.Equal(t, 1, nodes["redis-0"].paused)
+}
+
+func TestReconcileRedisClusterBackupUncoveredSlots(t *testing.T) {
+	nodes := newTestClusterNodes()
+	nodes["redis-1"].master = false
+	storage := &fakeObjectStorage{objects: map[string][]byte{}}
+	backup := newTestRedisClusterBackup()
+	backup.Spec.BackoffLimit = 2
+	r := newTestRedisClusterBackupReconciler(t, nodes, storage, backup, newTestCredentialsSecret(),
+		newTestUnit("redis-0", "redis"), newTestUnit("redis-1", "redis"), newTestUnit("redis-2", "redis"))
+
+	key := types.NamespacedName{Namespace: "default", Name: "nightly"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, retryInterval, result.RequeueAfter)
+	assert.Zero(t, nodes["redis-0"].paused)
+
+	instance := &upmv1alpha1.RedisClusterBackup{}
+	require.NoError(t, r.client.Get(context.Background(), key, instance))
+	assert.Equal(t, upmv1alpha1.RedisClusterBackupSnapshotting, instance.Status.Phase)
+	assert.Equal(t, upmv1alpha1.FailedResult, instance.Status.Result)
+	assert.Contains(t, instance.Status.Message, "masters serve 8192 of 16384 slots")
+	assert.Equal(t, int32(1), instance.Status.Attempts)
+	assert.Nil(t, instance.Status.CompletionTime)
+
+	// the backup gives up once BackoffLimit attempts failed
+	result, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	require.NoError(t, r.client.Get(context.Background(), key, instance))
+	assert.Equal(t, upmv1alpha1.RedisClusterBackupFailed, instance.Status.Phase)
+	assert.Equal(t, int32(2), instance.Status.Attempts)
+
+	nodes["redis-1"].master = true
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Zero(t, nodes["redis-0"].paused)
+}
+
+func TestParseSlotRanges(t *testing.T) {
+	ranges, err := parseSlotRanges([]string{"0-100", "200"})
+	require.NoError(t, err)
+	assert.Equal(t, []*rediscluster.SlotRange{{Start: 0, End: 100}, {Start: 200, End: 200}}, ranges)
+	assert.Equal(t, []string{"0-100", "200-200"}, slotRangeStrings(ranges))
+	assert.Equal(t, 102, slotCount(append(ranges, &rediscluster.SlotRange{Start: 50, End: 60})))
+
+	for _, value := range []string{"a-1", "1-b", "10-5", "0-16384"} {
+		_, err := parseSlotRanges([]string{value})
+		assert.Error(t, err, value)
+	}
+}
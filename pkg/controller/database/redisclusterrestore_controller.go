@@ -0,0 +1,476 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/rediscluster"
+	"github.com/upmio/unit-operator/pkg/agent/app/slm"
+	unitAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	redisClusterRestoreAppName = "redis-cluster-restore"
+
+	// restorePollInterval is how often a restore waiting for the cluster is reconciled again
+	restorePollInterval = 5 * time.Second
+
+	defaultRedisPort = 6379
+
+	// nodesConfKey is the key the rediscluster daemon of the unit-agent backs nodes.conf up under
+	nodesConfKey = "nodes.conf"
+)
+
+// ReconcileRedisClusterRestore reconciles RedisClusterRestore resources.
+type ReconcileRedisClusterRestore struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	dial     unitAgent.DialFunc
+	storage  func(*common.ObjectStorage) (common.ObjectStorageFactory, error)
+}
+
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=redisclusterrestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=redisclusterrestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=units,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;update
+
+func (r *ReconcileRedisClusterRestore) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.Infof("start reconciling redis cluster restore instance [%s]", req.String())
+	startTime := time.Now()
+
+	defer func() {
+		klog.Infof("finished reconciliation redis cluster restore instance [%s], duration [%v]", req.String(), time.Since(startTime))
+	}()
+
+	instance := &upmv1alpha1.RedisClusterRestore{}
+	if err := r.client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			klog.Infof("redis cluster restore instance [%s] not found, probably deleted.", req.String())
+			return reconcile.Result{}, nil
+		}
+
+		klog.Errorf("failed to fetch redis cluster restore instance [%s]: [%v]", req.String(), err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if !instance.DeletionTimestamp.IsZero() || instance.Status.Phase == upmv1alpha1.RedisClusterRestoreSucceeded {
+		return reconcile.Result{}, nil
+	}
+
+	oldStatus := instance.Status.DeepCopy()
+	if instance.Status.StartTime == nil {
+		now := metav1.Now()
+		instance.Status.StartTime = &now
+	}
+
+	phase := instance.Status.Phase
+	requeueAfter, err := r.restore(ctx, instance)
+
+	result := reconcile.Result{RequeueAfter: requeueAfter}
+	if err != nil {
+		instance.Status.Result = upmv1alpha1.FailedResult
+		instance.Status.Message = err.Error()
+		r.recorder.Event(instance, corev1.EventTypeWarning, "RestoreFailed", err.Error())
+		result.RequeueAfter = retryInterval
+	} else {
+		instance.Status.Result = upmv1alpha1.SuccessResult
+		instance.Status.Message = ""
+		if instance.Status.Phase != phase {
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, "PhaseChanged", "restore phase changed to %s", instance.Status.Phase)
+		}
+	}
+
+	if !reflect.DeepEqual(oldStatus, &instance.Status) {
+		if err := r.client.Status().Update(ctx, instance); err != nil {
+			klog.Errorf("failed to update redis cluster restore [%s] status: %v", req.String(), err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// restore runs the current phase and advances to the next one once it is done,
+// it returns when the restore should be reconciled again.
+func (r *ReconcileRedisClusterRestore) restore(ctx context.Context, instance *upmv1alpha1.RedisClusterRestore) (time.Duration, error) {
+	units, err := listUnitSetUnits(ctx, r.client, instance.Namespace, instance.Spec.UnitSet)
+	if err != nil {
+		return 0, err
+	}
+
+	switch instance.Status.Phase {
+	case "":
+		if err := r.plan(ctx, instance, units); err != nil {
+			return 0, err
+		}
+
+		instance.Status.Phase = upmv1alpha1.RedisClusterRestoreStopping
+
+	case upmv1alpha1.RedisClusterRestoreStopping:
+		if err := setUnitsStartup(ctx, r.client, units, false); err != nil {
+			return 0, err
+		}
+
+		// The snapshots must not be loaded under a running process
+		if !r.processesStopped(ctx, units) {
+			return restorePollInterval, nil
+		}
+
+		instance.Status.Phase = upmv1alpha1.RedisClusterRestoreLoading
+
+	case upmv1alpha1.RedisClusterRestoreLoading:
+		done, err := r.load(ctx, instance, units)
+		if err != nil {
+			return 0, err
+		}
+		if !done {
+			return transferPollInterval, nil
+		}
+
+		instance.Status.Phase = upmv1alpha1.RedisClusterRestoreStarting
+
+	case upmv1alpha1.RedisClusterRestoreStarting:
+		if err := setUnitsStartup(ctx, r.client, units, true); err != nil {
+			return 0, err
+		}
+
+		instance.Status.Phase = upmv1alpha1.RedisClusterRestoreJoining
+
+	case upmv1alpha1.RedisClusterRestoreJoining:
+		done, err := r.join(ctx, instance, units)
+		if err != nil {
+			return 0, err
+		}
+		if !done {
+			return restorePollInterval, nil
+		}
+
+		now := metav1.Now()
+		instance.Status.Phase = upmv1alpha1.RedisClusterRestoreSucceeded
+		instance.Status.CompletionTime = &now
+		return 0, nil
+	}
+
+	return time.Second, nil
+}
+
+// plan reads the manifest of the backup and assigns the shards to the units in name order,
+// the remaining units become replicas.
+func (r *ReconcileRedisClusterRestore) plan(ctx context.Context, instance *upmv1alpha1.RedisClusterRestore, units []upmv1alpha2.Unit) error {
+	storage, err := objectStorage(ctx, r.client, instance.Namespace, instance.Spec.ObjectStorage)
+	if err != nil {
+		return err
+	}
+
+	factory, err := r.storage(storage)
+	if err != nil {
+		return fmt.Errorf("failed to generate storage factory: %v", err)
+	}
+
+	manifestName := path.Join(strings.Trim(instance.Spec.Prefix, "/"), redisClusterManifestName)
+	obj, err := factory.GetObject(ctx, storage.GetBucket(), manifestName)
+	if err != nil {
+		return fmt.Errorf("failed to get manifest [%s]: %v", manifestName, err)
+	}
+	defer func() { _ = obj.Close() }()
+
+	manifest := &redisClusterManifest{}
+	if err := json.NewDecoder(obj).Decode(manifest); err != nil {
+		return fmt.Errorf("failed to decode manifest [%s]: %v", manifestName, err)
+	}
+
+	var slots []*rediscluster.SlotRange
+	for _, shard := range manifest.Shards {
+		ranges, err := parseSlotRanges(shard.Slots)
+		if err != nil {
+			return fmt.Errorf("manifest [%s]: %v", manifestName, err)
+		}
+		slots = append(slots, ranges...)
+	}
+
+	if covered := slotCount(slots); covered != clusterSlots {
+		return fmt.Errorf("manifest [%s] covers %d of %d slots", manifestName, covered, clusterSlots)
+	}
+
+	if len(units) < len(manifest.Shards) {
+		return fmt.Errorf("unitset [%s] has %d units, the backup has %d shards", instance.Spec.UnitSet, len(units), len(manifest.Shards))
+	}
+
+	shards := make([]upmv1alpha1.RedisClusterShard, 0, len(manifest.Shards))
+	for i, shard := range manifest.Shards {
+		shard.Unit = units[i].Name
+		shards = append(shards, shard)
+	}
+
+	instance.Status.Shards = shards
+	instance.Status.Replicas = unitNames(units[len(shards):])
+	return nil
+}
+
+// load resets every stopped unit and loads the snapshot of its shard, replicas are left empty.
+// The loads run as unit-agent operations, all units in parallel, it reports done once every unit
+// was loaded. The nodes.conf backup is dropped first, so that the unit-agent does not bring back
+// the old identity.
+func (r *ReconcileRedisClusterRestore) load(ctx context.Context, instance *upmv1alpha1.RedisClusterRestore, units []upmv1alpha2.Unit) (bool, error) {
+	storage, err := objectStorage(ctx, r.client, instance.Namespace, instance.Spec.ObjectStorage)
+	if err != nil {
+		return false, err
+	}
+
+	objects := make(map[string]string, len(instance.Status.Shards))
+	for _, shard := range instance.Status.Shards {
+		objects[shard.Unit] = shard.Object
+	}
+
+	if len(instance.Status.Transfers) == 0 {
+		for i := range units {
+			instance.Status.Transfers = append(instance.Status.Transfers, upmv1alpha1.RedisClusterTransfer{Unit: units[i].Name})
+		}
+	}
+
+	return transferShards(ctx, r.dial, units, instance.Status.Transfers, instance.UID,
+		func(_ int, unit *upmv1alpha2.Unit) transferCall {
+			req := &rediscluster.RestoreShardRequest{
+				BackupFile:    objects[unit.Name],
+				ObjectStorage: storage,
+			}
+
+			return func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) error {
+				if err := dropNodesConfBackup(ctx, r.client, unit); err != nil {
+					return err
+				}
+
+				_, err := rediscluster.NewRedisClusterOperationClient(conn).RestoreShard(ctx, req, opts...)
+				return err
+			}
+		})
+}
+
+// join meets every unit with the first shard unit, assigns the slots of every shard and the
+// replicas once all units know each other. It reports done when every unit sees the cluster ok.
+func (r *ReconcileRedisClusterRestore) join(ctx context.Context, instance *upmv1alpha1.RedisClusterRestore, units []upmv1alpha2.Unit) (bool, error) {
+	username := instance.Spec.Username
+	port := instance.Spec.Port
+	if port == 0 {
+		port = defaultRedisPort
+	}
+
+	byName := make(map[string]*upmv1alpha2.Unit, len(units))
+	for i := range units {
+		byName[units[i].Name] = &units[i]
+	}
+
+	nodes := make(map[string]*rediscluster.ClusterNodesResponse, len(units))
+	for i := range units {
+		if err := unitAgent.Call(ctx, &units[i], r.dial, agentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			resp, err := rediscluster.NewRedisClusterOperationClient(conn).ClusterNodes(ctx, &rediscluster.ClusterNodesRequest{Username: username})
+			nodes[units[i].Name] = resp
+			return err
+		}); err != nil {
+			return false, fmt.Errorf("failed to query cluster nodes of unit [%s]: %v", units[i].Name, err)
+		}
+	}
+
+	seed, ok := byName[instance.Status.Shards[0].Unit]
+	if !ok {
+		return false, fmt.Errorf("unit [%s] not found", instance.Status.Shards[0].Unit)
+	}
+
+	joined := true
+	for i := range units {
+		unit := &units[i]
+		if knownNodes(nodes[unit.Name]) >= len(units) {
+			continue
+		}
+
+		joined = false
+		if unit.Name == seed.Name {
+			continue
+		}
+
+		if err := unitAgent.Call(ctx, unit, r.dial, agentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			_, err := rediscluster.NewRedisClusterOperationClient(conn).MeetNode(ctx, &rediscluster.MeetNodeRequest{
+				Username: username,
+				Host:     unitAgent.UnitHost(seed),
+				Port:     port,
+			})
+			return err
+		}); err != nil {
+			return false, fmt.Errorf("failed to meet unit [%s]: %v", unit.Name, err)
+		}
+	}
+
+	for _, shard := range instance.Status.Shards {
+		slots, err := parseSlotRanges(shard.Slots)
+		if err != nil {
+			return false, err
+		}
+
+		unit, ok := byName[shard.Unit]
+		if !ok {
+			return false, fmt.Errorf("unit [%s] not found", shard.Unit)
+		}
+
+		if err := unitAgent.Call(ctx, unit, r.dial, agentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			_, err := rediscluster.NewRedisClusterOperationClient(conn).AssignSlots(ctx, &rediscluster.AssignSlotsRequest{
+				Username: username,
+				Slots:    slots,
+			})
+			return err
+		}); err != nil {
+			return false, fmt.Errorf("failed to assign slots to unit [%s]: %v", shard.Unit, err)
+		}
+	}
+
+	// A replica can only follow a master it already knows
+	if !joined {
+		return false, nil
+	}
+
+	for i, name := range instance.Status.Replicas {
+		unit, ok := byName[name]
+		if !ok {
+			return false, fmt.Errorf("unit [%s] not found", name)
+		}
+
+		master := instance.Status.Shards[i%len(instance.Status.Shards)].Unit
+		if err := unitAgent.Call(ctx, unit, r.dial, agentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			_, err := rediscluster.NewRedisClusterOperationClient(conn).Replicate(ctx, &rediscluster.ReplicateRequest{
+				Username: username,
+				MasterId: nodes[master].GetMyId(),
+			})
+			return err
+		}); err != nil {
+			return false, fmt.Errorf("failed to replicate unit [%s] to [%s]: %v", name, master, err)
+		}
+	}
+
+	for _, resp := range nodes {
+		if resp.GetState() != "ok" {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// knownNodes returns the number of nodes past the handshake.
+func knownNodes(resp *rediscluster.ClusterNodesResponse) int {
+	count := 0
+	for _, node := range resp.GetNodes() {
+		handshake := false
+		for _, flag := range node.GetFlags() {
+			if flag == "handshake" || flag == "noaddr" {
+				handshake = true
+			}
+		}
+
+		if !handshake {
+			count++
+		}
+	}
+
+	return count
+}
+
+// processesStopped reports whether the unit-agent of every unit reports its process stopped,
+// a unit which cannot be reached counts as not stopped yet.
+func (r *ReconcileRedisClusterRestore) processesStopped(ctx context.Context, units []upmv1alpha2.Unit) bool {
+	for i := range units {
+		if err := unitAgent.Call(ctx, &units[i], r.dial, agentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			_, err := slm.NewServiceLifecycleClient(conn).CheckProcessStopped(ctx, &common.Empty{})
+			return err
+		}); err != nil {
+			klog.Infof("waiting for the process of unit [%s] to stop: %v", units[i].Name, err)
+			return false
+		}
+	}
+
+	return true
+}
+
+// setUnitsStartup starts or stops the units through the Unit controller.
+func setUnitsStartup(ctx context.Context, c client.Client, units []upmv1alpha2.Unit, startup bool) error {
+	for i := range units {
+		unit := &units[i]
+		if unit.Spec.Startup == startup {
+			continue
+		}
+
+		patch := client.MergeFrom(unit.DeepCopy())
+		unit.Spec.Startup = startup
+		if err := c.Patch(ctx, unit, patch); err != nil {
+			return fmt.Errorf("failed to set startup of unit [%s] to %v: %v", unit.Name, startup, err)
+		}
+	}
+
+	return nil
+}
+
+// dropNodesConfBackup removes nodes.conf from the config backup of the unit.
+func dropNodesConfBackup(ctx context.Context, c client.Client, unit *upmv1alpha2.Unit) error {
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: unit.Namespace, Name: unit.Name + "-config-backup"}, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch config backup of unit [%s]: %v", unit.Name, err)
+	}
+
+	if _, ok := configMap.Data[nodesConfKey]; !ok {
+		return nil
+	}
+
+	delete(configMap.Data, nodesConfKey)
+	if err := c.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update config backup of unit [%s]: %v", unit.Name, err)
+	}
+
+	return nil
+}
+
+func setupRedisClusterRestore(mgr ctrl.Manager) error {
+	r := &ReconcileRedisClusterRestore{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor(redisClusterRestoreAppName),
+		dial:     unitAgent.Dial,
+		storage:  (*common.ObjectStorage).GenerateFactory,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&upmv1alpha1.RedisClusterRestore{}).
+		Complete(r)
+}
@@ -0,0 +1,174 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
+	"github.com/upmio/unit-operator/pkg/agent/app/rediscluster"
+)
+
+func TestReconcileRedisClusterRestore(t *testing.T) {
+	manifest, err := json.Marshal(&redisClusterManifest{
+		UnitSet: "redis",
+		Shards: []upmv1alpha1.RedisClusterShard{
+			{NodeID: "old-0", Slots: []string{"0-8191"}, Object: "nightly/shard-0.rdb"},
+			{NodeID: "old-1", Slots: []string{"8192-16383"}, Object: "nightly/shard-1.rdb"},
+		},
+	})
+	require.NoError(t, err)
+	storage := &fakeObjectStorage{objects: map[string][]byte{"backup/nightly/cluster.json": manifest}}
+
+	nodes := map[string]*fakeClusterNode{
+		"redis-0": {id: "new-0", master: true, known: 1},
+		"redis-1": {id: "new-1", master: true, known: 1, async: true},
+		"redis-2": {id: "new-2", master: true, known: 1, running: true},
+	}
+
+	units := make([]*upmv1alpha2.Unit, 0, len(nodes))
+	for _, name := range []string{"redis-0", "redis-1", "redis-2"} {
+		unit := newTestUnit(name, "redis")
+		unit.Spec.Startup = true
+		units = append(units, unit)
+	}
+
+	configBackup := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "redis-0-config-backup", Namespace: "default"},
+		Data:       map[string]string{nodesConfKey: "old", "redis.conf": "port 6379"},
+	}
+
+	instance := &upmv1alpha1.RedisClusterRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "default", UID: "restore-uid"},
+		Spec: upmv1alpha1.RedisClusterRestoreSpec{
+			UnitSet:       "redis",
+			Username:      "admin",
+			ObjectStorage: newTestObjectStorageSpec(),
+			Prefix:        "nightly",
+		},
+	}
+
+	s := newTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithStatusSubresource(&upmv1alpha1.RedisClusterRestore{}).
+		WithObjects(instance, newTestCredentialsSecret(), configBackup, units[0], units[1], units[2]).
+		Build()
+
+	r := &ReconcileRedisClusterRestore{
+		client:   c,
+		scheme:   s,
+		recorder: record.NewFakeRecorder(10),
+		dial:     startClusterNodes(t, nodes),
+		storage: func(*common.ObjectStorage) (common.ObjectStorageFactory, error) {
+			return storage, nil
+		},
+	}
+
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "default", Name: "restore"}
+	step := func(phase upmv1alpha1.RedisClusterRestorePhase) {
+		t.Helper()
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		require.NoError(t, c.Get(ctx, key, instance))
+		require.Equal(t, phase, instance.Status.Phase, instance.Status.Message)
+	}
+	startup := func(name string) bool {
+		t.Helper()
+		unit := &upmv1alpha2.Unit{}
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, unit))
+		return unit.Spec.Startup
+	}
+
+	step(upmv1alpha1.RedisClusterRestoreStopping)
+	require.Len(t, instance.Status.Shards, 2)
+	assert.Equal(t, "redis-0", instance.Status.Shards[0].Unit)
+	assert.Equal(t, "redis-1", instance.Status.Shards[1].Unit)
+	assert.Equal(t, []string{"redis-2"}, instance.Status.Replicas)
+
+	// the shards are not loaded before every process stopped
+	step(upmv1alpha1.RedisClusterRestoreStopping)
+	assert.False(t, startup("redis-0"))
+	assert.False(t, startup("redis-2"))
+
+	nodes["redis-2"].running = false
+	step(upmv1alpha1.RedisClusterRestoreLoading)
+
+	// the load of redis-1 runs as an operation
+	step(upmv1alpha1.RedisClusterRestoreLoading)
+	require.Len(t, instance.Status.Transfers, 3)
+	assert.True(t, instance.Status.Transfers[0].Completed)
+	assert.Equal(t, "restore-uid-redis-1-0", instance.Status.Transfers[1].OperationID)
+	assert.False(t, instance.Status.Transfers[1].Completed)
+
+	nodes["redis-1"].finishOperations(operation.State_SUCCEEDED)
+	step(upmv1alpha1.RedisClusterRestoreStarting)
+	require.Len(t, nodes["redis-0"].restores, 1)
+	require.Len(t, nodes["redis-1"].restores, 1)
+	assert.Equal(t, "nightly/shard-1.rdb", nodes["redis-1"].restores[0].BackupFile)
+	require.Len(t, nodes["redis-2"].restores, 1)
+	assert.Empty(t, nodes["redis-2"].restores[0].BackupFile)
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "redis-0-config-backup"}, configBackup))
+	assert.NotContains(t, configBackup.Data, nodesConfKey)
+	assert.Contains(t, configBackup.Data, "redis.conf")
+
+	step(upmv1alpha1.RedisClusterRestoreJoining)
+	assert.True(t, startup("redis-1"))
+
+	// the units do not know each other yet
+	step(upmv1alpha1.RedisClusterRestoreJoining)
+	assert.Empty(t, nodes["redis-0"].meets)
+	require.Len(t, nodes["redis-2"].meets, 1)
+	assert.Equal(t, "redis-0.redis-headless-svc.default.svc", nodes["redis-2"].meets[0].Host)
+	assert.Equal(t, int64(defaultRedisPort), int64(nodes["redis-2"].meets[0].Port))
+	require.NotEmpty(t, nodes["redis-1"].assigned)
+	assert.Equal(t, []*rediscluster.SlotRange{{Start: 8192, End: 16383}}, nodes["redis-1"].assigned[0].Slots)
+	assert.Empty(t, nodes["redis-2"].replicas)
+
+	for _, node := range nodes {
+		node.known = 3
+	}
+
+	step(upmv1alpha1.RedisClusterRestoreSucceeded)
+	require.Len(t, nodes["redis-2"].replicas, 1)
+	assert.Equal(t, "new-0", nodes["redis-2"].replicas[0].MasterId)
+	assert.NotNil(t, instance.Status.CompletionTime)
+}
+
+func TestKnownNodes(t *testing.T) {
+	resp := &rediscluster.ClusterNodesResponse{Nodes: []*rediscluster.ClusterNode{
+		{Flags: []string{"myself", "master"}},
+		{Flags: []string{"handshake"}},
+		{Flags: []string{"master", "noaddr"}},
+		{Flags: []string{"slave"}},
+	}}
+
+	assert.Equal(t, 2, knownNodes(resp))
+	assert.Zero(t, knownNodes(nil))
+}
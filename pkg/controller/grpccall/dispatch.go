@@ -0,0 +1,309 @@
+package grpccall
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// actionMethods maps the actions of every unit type to the unit-agent method
+// they call. The descriptors of these methods are registered by the agent
+// packages imported by the Client.
+var actionMethods = map[upmv1alpha1.UnitType]map[upmv1alpha1.Action]string{
+	upmv1alpha1.MysqlType: {
+		upmv1alpha1.PhysicalBackupAction: "mysql.MysqlOperation/PhysicalBackup",
+		upmv1alpha1.LogicalBackupAction:  "mysql.MysqlOperation/LogicalBackup",
+		upmv1alpha1.CloneAction:          "mysql.MysqlOperation/Clone",
+		upmv1alpha1.GtidPurgeAction:      "mysql.MysqlOperation/GtidPurge",
+		upmv1alpha1.SetVariableAction:    "mysql.MysqlOperation/SetVariable",
+		upmv1alpha1.RestoreAction:        "mysql.MysqlOperation/Restore",
+	},
+	upmv1alpha1.PostgresqlType: {
+		upmv1alpha1.PhysicalBackupAction: "postgresql.PostgresqlOperation/PhysicalBackup",
+		upmv1alpha1.LogicalBackupAction:  "postgresql.PostgresqlOperation/LogicalBackup",
+		upmv1alpha1.RestoreAction:        "postgresql.PostgresqlOperation/Restore",
+		upmv1alpha1.SetVariableAction:    "postgresql.PostgresqlOperation/SetVariable",
+	},
+	upmv1alpha1.ProxysqlType: {
+		upmv1alpha1.SetVariableAction: "proxysql.ProxysqlOperation/SetVariable",
+	},
+	upmv1alpha1.RedisType: {
+		upmv1alpha1.SetVariableAction: "redis.RedisOperation/SetVariable",
+		upmv1alpha1.BackupAction:      "redis.RedisOperation/Backup",
+		upmv1alpha1.RestoreAction:     "redis.RedisOperation/Restore",
+	},
+	upmv1alpha1.RedisClusterType: {
+		upmv1alpha1.AddNodeAction:       "rediscluster.RedisClusterOperation/AddNode",
+		upmv1alpha1.ReplicateAction:     "rediscluster.RedisClusterOperation/Replicate",
+		upmv1alpha1.RebalanceAction:     "rediscluster.RedisClusterOperation/Rebalance",
+		upmv1alpha1.FailoverAction:      "rediscluster.RedisClusterOperation/Failover",
+		upmv1alpha1.ClusterHealthAction: "rediscluster.RedisClusterOperation/ClusterHealth",
+	},
+	upmv1alpha1.SentinelType: {
+		upmv1alpha1.SetVariableAction:  "sentinel.SentinelOperation/SetVariable",
+		upmv1alpha1.MonitorAction:      "sentinel.SentinelOperation/Monitor",
+		upmv1alpha1.RemoveMasterAction: "sentinel.SentinelOperation/Remove",
+		upmv1alpha1.ResetAction:        "sentinel.SentinelOperation/Reset",
+		upmv1alpha1.ListMastersAction:  "sentinel.SentinelOperation/ListMasters",
+		upmv1alpha1.ListReplicasAction: "sentinel.SentinelOperation/ListReplicas",
+		upmv1alpha1.FailoverAction:     "sentinel.SentinelOperation/Failover",
+	},
+	upmv1alpha1.MilvusType: {
+		upmv1alpha1.BackupAction:       "milvus.MilvusOperation/Backup",
+		upmv1alpha1.RestoreAction:      "milvus.MilvusOperation/Restore",
+		upmv1alpha1.ListBackupsAction:  "milvus.MilvusOperation/ListBackups",
+		upmv1alpha1.GetBackupAction:    "milvus.MilvusOperation/GetBackup",
+		upmv1alpha1.DeleteBackupAction: "milvus.MilvusOperation/DeleteBackup",
+		upmv1alpha1.SetVariableAction:  "milvus.MilvusOperation/SetVariable",
+	},
+	upmv1alpha1.MongoDBType: {
+		upmv1alpha1.BackupAction:           "mongodb.MongoDBOperation/Backup",
+		upmv1alpha1.RestoreAction:          "mongodb.MongoDBOperation/Restore",
+		upmv1alpha1.SetVariableAction:      "mongodb.MongoDBOperation/SetVariable",
+		upmv1alpha1.InitiateAction:         "mongodb.MongoDBOperation/InitiateReplicaSet",
+		upmv1alpha1.AddMemberAction:        "mongodb.MongoDBOperation/AddMember",
+		upmv1alpha1.RemoveMemberAction:     "mongodb.MongoDBOperation/RemoveMember",
+		upmv1alpha1.SetMemberAction:        "mongodb.MongoDBOperation/SetMemberConfig",
+		upmv1alpha1.StepDownAction:         "mongodb.MongoDBOperation/StepDown",
+		upmv1alpha1.ReplicaSetStatusAction: "mongodb.MongoDBOperation/ReplicaSetStatus",
+		upmv1alpha1.ArchiveOplogAction:     "mongodb.MongoDBOperation/ArchiveOplog",
+	},
+	upmv1alpha1.ClickHouseType: {
+		upmv1alpha1.LogicalBackupAction: "clickhouse.ClickHouseOperation/LogicalBackup",
+		upmv1alpha1.RestoreAction:       "clickhouse.ClickHouseOperation/Restore",
+		upmv1alpha1.BackupStatusAction:  "clickhouse.ClickHouseOperation/BackupStatus",
+		upmv1alpha1.SetVariableAction:   "clickhouse.ClickHouseOperation/SetVariable",
+	},
+}
+
+// setVariablesMethod is called in place of the SetVariable method of a
+// set-variable action whose parameters carry a variables map.
+const setVariablesMethod = "SetVariables"
+
+// typeResolver resolves the message types of a method.
+type typeResolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// agentMethod is a unit-agent method resolved for a GrpcCall, with the types
+// of its messages: the generated types for the methods the operator was built
+// with, dynamic types for the methods resolved through server reflection.
+type agentMethod struct {
+	desc  protoreflect.MethodDescriptor
+	types typeResolver
+}
+
+// name returns the method as "<package>.<Service>/<Method>".
+func (m *agentMethod) name() string {
+	return fmt.Sprintf("%s/%s", m.desc.Parent().FullName(), m.desc.Name())
+}
+
+func (m *agentMethod) newMessage(desc protoreflect.MessageDescriptor) proto.Message {
+	if mt, err := m.types.FindMessageByName(desc.FullName()); err == nil {
+		return mt.New().Interface()
+	}
+	return dynamicpb.NewMessage(desc)
+}
+
+// newRequest builds the request of the method from the parameters of a
+// GrpcCall, after checking they are fields of the request message.
+func (m *agentMethod) newRequest(params map[string]apiextensionsv1.JSON) (proto.Message, error) {
+	if err := validateParams(params, m.desc.Input()); err != nil {
+		return nil, err
+	}
+
+	req := m.newMessage(m.desc.Input())
+	if err := unmarshalParams(params, req); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal parameters into %s: %v", m.desc.Input().FullName(), err)
+	}
+
+	return req, nil
+}
+
+// invoke calls the method on the unit-agent.
+func (m *agentMethod) invoke(ctx context.Context, conn grpc.ClientConnInterface, req proto.Message, opts ...grpc.CallOption) (proto.Message, error) {
+	resp := m.newMessage(m.desc.Output())
+	if err := conn.Invoke(ctx, "/"+m.name(), req, resp, opts...); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// methodName returns the unit-agent method a GrpcCall calls, as
+// "<package>.<Service>/<Method>", from its method or from its action.
+func methodName(instance *upmv1alpha1.GrpcCall) (string, error) {
+	spec := instance.Spec
+
+	switch {
+	case spec.Action != "" && spec.Method != "":
+		return "", fmt.Errorf("action and method are mutually exclusive")
+	case spec.Method != "":
+		return strings.TrimPrefix(spec.Method, "/"), nil
+	case spec.Action == "":
+		return "", fmt.Errorf("one of action and method must be set")
+	}
+
+	if method, ok := lifecycleMethods[spec.Action]; ok {
+		return method, nil
+	}
+
+	methods, ok := actionMethods[spec.Type]
+	if !ok {
+		return "", fmt.Errorf("unsupported unit type %q", spec.Type)
+	}
+
+	method, ok := methods[spec.Action]
+	if !ok {
+		return "", fmt.Errorf("unsupported action %q for type %q", spec.Action, spec.Type)
+	}
+
+	if spec.Action == upmv1alpha1.SetVariableAction && isBatchSetVariable(instance) {
+		service, _, _ := strings.Cut(method, "/")
+		method = service + "/" + setVariablesMethod
+	}
+
+	return method, nil
+}
+
+// resolveMethod resolves the unit-agent method of a GrpcCall from the
+// descriptors the operator was built with, falling back to the gRPC server
+// reflection of the unit-agent for the methods they do not have.
+func resolveMethod(ctx context.Context, instance *upmv1alpha1.GrpcCall, c *Client) (*agentMethod, error) {
+	name, err := methodName(instance)
+	if err != nil {
+		return nil, &codeError{code: codes.InvalidArgument, err: err}
+	}
+
+	service, method, ok := strings.Cut(name, "/")
+	if !ok || service == "" || method == "" {
+		return nil, &codeError{code: codes.InvalidArgument, err: fmt.Errorf("method %q is not <package>.<Service>/<Method>", name)}
+	}
+
+	if desc := findMethod(protoregistry.GlobalFiles, service, method); desc != nil {
+		return &agentMethod{desc: desc, types: protoregistry.GlobalTypes}, nil
+	}
+
+	files, err := c.reflectFiles(ctx, service)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, &codeError{code: codes.Unimplemented, err: fmt.Errorf("service %s not found on unit agent", service)}
+		}
+		return nil, fmt.Errorf("failed to resolve method %s on unit agent: %w", name, err)
+	}
+
+	desc := findMethod(files, service, method)
+	if desc == nil {
+		return nil, &codeError{code: codes.Unimplemented, err: fmt.Errorf("method %s not found on unit agent", name)}
+	}
+
+	return &agentMethod{desc: desc, types: dynamicpb.NewTypes(files)}, nil
+}
+
+// findMethod returns the descriptor of the method of the service, or nil.
+func findMethod(files interface {
+	FindDescriptorByName(protoreflect.FullName) (protoreflect.Descriptor, error)
+}, service, method string) protoreflect.MethodDescriptor {
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil
+	}
+
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil
+	}
+
+	return sd.Methods().ByName(protoreflect.Name(method))
+}
+
+// reflectFiles fetches the descriptors of the file defining symbol, and of the
+// files it depends on, through the gRPC server reflection of the unit-agent.
+func (c *Client) reflectFiles(ctx context.Context, symbol string) (*protoregistry.Files, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := rpb.NewServerReflectionClient(c.conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.Send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	}); err != nil {
+		return nil, err
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	_ = stream.CloseSend()
+
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, status.Error(codes.Code(e.GetErrorCode()), e.GetErrorMessage())
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fd := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(raw, fd); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal file descriptor: %v", err)
+		}
+		set.File = append(set.File, fd)
+	}
+
+	return protodesc.NewFiles(set)
+}
+
+// validateParams checks every parameter of a GrpcCall names a field of the
+// request message, by its JSON or proto name.
+func validateParams(params map[string]apiextensionsv1.JSON, desc protoreflect.MessageDescriptor) error {
+	fields := desc.Fields()
+
+	var unknown []string
+	for key := range params {
+		if fields.ByJSONName(key) == nil && fields.ByTextName(key) == nil {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+
+	if fields.Len() == 0 {
+		return fmt.Errorf("unknown parameters %s for %s, which has no fields", strings.Join(unknown, ", "), desc.FullName())
+	}
+
+	names := make([]string, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		names = append(names, string(fields.Get(i).Name()))
+	}
+
+	return fmt.Errorf("unknown parameters %s for %s, its fields are %s",
+		strings.Join(unknown, ", "), desc.FullName(), strings.Join(names, ", "))
+}
+
+// callName names the call of a GrpcCall in messages, by its action or its method.
+func callName(instance *upmv1alpha1.GrpcCall) string {
+	if instance.Spec.Action != "" {
+		return string(instance.Spec.Action)
+	}
+	return instance.Spec.Method
+}
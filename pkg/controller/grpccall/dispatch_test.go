@@ -0,0 +1,147 @@
+package grpccall
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	"github.com/upmio/unit-operator/pkg/agent/app/mysql"
+)
+
+func TestActionMethodsAreRegistered(t *testing.T) {
+	for unitType, methods := range actionMethods {
+		for action, method := range methods {
+			service, name, _ := strings.Cut(method, "/")
+			assert.NotNil(t, findMethod(protoregistry.GlobalFiles, service, name), "%s %s: %s", action, unitType, method)
+		}
+	}
+
+	for action, method := range lifecycleMethods {
+		service, name, _ := strings.Cut(method, "/")
+		assert.NotNil(t, findMethod(protoregistry.GlobalFiles, service, name), "%s: %s", action, method)
+	}
+}
+
+func TestMethodName(t *testing.T) {
+	variables := map[string]apiextensionsv1.JSON{"variables": {Raw: []byte(`{"max_connections":"500"}`)}}
+
+	tests := []struct {
+		name   string
+		spec   upmv1alpha1.GrpcCallSpec
+		want   string
+		errMsg string
+	}{
+		{name: "action", spec: upmv1alpha1.GrpcCallSpec{Type: upmv1alpha1.MongoDBType, Action: upmv1alpha1.SetMemberAction},
+			want: "mongodb.MongoDBOperation/SetMemberConfig"},
+		{name: "batch set-variable", spec: upmv1alpha1.GrpcCallSpec{Type: upmv1alpha1.RedisType, Action: upmv1alpha1.SetVariableAction, Parameters: variables},
+			want: "redis.RedisOperation/SetVariables"},
+		{name: "lifecycle", spec: upmv1alpha1.GrpcCallSpec{Type: upmv1alpha1.ClickHouseType, Action: upmv1alpha1.RestartAction},
+			want: "service.ServiceLifecycle/RestartProcess"},
+		{name: "method", spec: upmv1alpha1.GrpcCallSpec{Type: upmv1alpha1.MysqlType, Method: "/mysql.MysqlOperation/Clone"},
+			want: "mysql.MysqlOperation/Clone"},
+		{name: "action and method", spec: upmv1alpha1.GrpcCallSpec{Type: upmv1alpha1.MysqlType, Action: upmv1alpha1.CloneAction, Method: "mysql.MysqlOperation/Clone"},
+			errMsg: "action and method are mutually exclusive"},
+		{name: "neither", spec: upmv1alpha1.GrpcCallSpec{Type: upmv1alpha1.MysqlType},
+			errMsg: "one of action and method must be set"},
+		{name: "unsupported action", spec: upmv1alpha1.GrpcCallSpec{Type: upmv1alpha1.ProxysqlType, Action: upmv1alpha1.CloneAction},
+			errMsg: `unsupported action "clone" for type "proxysql"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := methodName(&upmv1alpha1.GrpcCall{Spec: tt.spec})
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewRequestValidatesParameters(t *testing.T) {
+	method, err := resolveMethod(context.Background(), &upmv1alpha1.GrpcCall{
+		Spec: upmv1alpha1.GrpcCallSpec{Type: upmv1alpha1.MysqlType, Action: upmv1alpha1.GtidPurgeAction},
+	}, &Client{})
+	require.NoError(t, err)
+
+	_, err = method.newRequest(map[string]apiextensionsv1.JSON{"archive": {Raw: []byte(`true`)}, "username": {Raw: []byte(`"root"`)}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown parameters archive for mysql.GtidPurgeRequest, its fields are ")
+
+	method, err = resolveMethod(context.Background(), &upmv1alpha1.GrpcCall{
+		Spec: upmv1alpha1.GrpcCallSpec{Type: upmv1alpha1.MysqlType, Action: upmv1alpha1.LogicalBackupAction},
+	}, &Client{})
+	require.NoError(t, err)
+
+	// fields are accepted by their proto and JSON names
+	req, err := method.newRequest(map[string]apiextensionsv1.JSON{"backup_file": {Raw: []byte(`"full.sql"`)}, "objectStorage": {Raw: []byte(`{}`)}})
+	require.NoError(t, err)
+	assert.Equal(t, "full.sql", req.(*mysql.LogicalBackupRequest).GetBackupFile())
+
+	_, err = method.newRequest(map[string]apiextensionsv1.JSON{"backup_file": {Raw: []byte(`42`)}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to unmarshal parameters into mysql.LogicalBackupRequest")
+}
+
+func TestResolveMethodThroughReflection(t *testing.T) {
+	c := startMysqlAgent(t, &fakeMysqlAgent{}, false)
+	ctx := context.Background()
+
+	files, err := c.reflectFiles(ctx, "mysql.MysqlOperation")
+	require.NoError(t, err)
+
+	desc := findMethod(files, "mysql.MysqlOperation", "LogicalBackup")
+	require.NotNil(t, desc)
+
+	// a method resolved through reflection is called with dynamic messages
+	method := &agentMethod{desc: desc, types: dynamicpb.NewTypes(files)}
+	req, err := method.newRequest(map[string]apiextensionsv1.JSON{"backup_file": {Raw: []byte(`"mysql-0/full.sql"`)}})
+	require.NoError(t, err)
+	require.IsType(t, &dynamicpb.Message{}, req)
+
+	resp, err := method.invoke(ctx, c.conn, req)
+	require.NoError(t, err)
+	require.IsType(t, &dynamicpb.Message{}, resp)
+
+	outputs, err := responseOutputs(resp)
+	require.NoError(t, err)
+	assert.Equal(t, apiextensionsv1.JSON{Raw: []byte(`"mysql-0/full.sql"`)}, outputs["object"])
+	assert.Equal(t, apiextensionsv1.JSON{Raw: []byte(`1048576`)}, outputs["size_bytes"])
+
+	_, err = resolveMethod(ctx, &upmv1alpha1.GrpcCall{Spec: upmv1alpha1.GrpcCallSpec{Method: "mysql.MysqlOperation/Flush"}}, c)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	assert.EqualError(t, err, "method mysql.MysqlOperation/Flush not found on unit agent")
+
+	_, err = resolveMethod(ctx, &upmv1alpha1.GrpcCall{Spec: upmv1alpha1.GrpcCallSpec{Method: "tidb.TidbOperation/Backup"}}, c)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	assert.EqualError(t, err, "service tidb.TidbOperation not found on unit agent")
+}
+
+func TestHandleGrpcCallByMethod(t *testing.T) {
+	c := startMysqlAgent(t, &fakeMysqlAgent{}, false)
+
+	r := &ReconcileGrpcCall{}
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default", UID: "0b7d4e1a-backup"},
+		Spec: upmv1alpha1.GrpcCallSpec{TargetUnit: "mysql-0", Type: upmv1alpha1.MysqlType, Method: "mysql.MysqlOperation/LogicalBackup",
+			Parameters: map[string]apiextensionsv1.JSON{"backup_file": {Raw: []byte(`"mysql-0/full.sql"`)}}},
+	}
+
+	finished, err := r.handleGrpcCall(context.Background(), instance, c, "0b7d4e1a-backup")
+	require.NoError(t, err)
+	require.True(t, finished)
+	assert.Equal(t, upmv1alpha1.SuccessResult, instance.Status.Result)
+	assert.Equal(t, "mysql.MysqlOperation/LogicalBackup mysql successfully, wrote 1048576 bytes to mysql-0/full.sql", instance.Status.Message)
+}
@@ -15,18 +15,23 @@ package grpccall
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,6 +40,14 @@ import (
 const (
 	appName   = "grpc-call"
 	agentName = "unit-agent"
+
+	// a running unit-agent operation is polled after operationPollInitialInterval,
+	// doubling with its age up to operationPollMaxInterval
+	operationPollInitialInterval = time.Second
+	operationPollMaxInterval     = 10 * time.Second
+
+	// FinalizerGrpcCall cancels the unit-agent operation when an active GrpcCall is deleted
+	FinalizerGrpcCall = "unit-operator/grpc-call"
 )
 
 // ReconcileGrpcCall reconciles GrpcCall resources.
@@ -49,6 +62,9 @@ type ReconcileGrpcCall struct {
 // +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=grpccalls/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=grpccalls/finalizers,verbs=update
 // +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=units,verbs=get;list;update;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=mysqlreplications;postgresreplications,verbs=get;list;watch
 
 func (r *ReconcileGrpcCall) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	reqLogger := r.logger.WithValues("request.namespace", req.Namespace, "request.name", req.Name)
@@ -66,7 +82,7 @@ func (r *ReconcileGrpcCall) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	// Fetch the GrpcCall instance
 	instance := &upmv1alpha1.GrpcCall{}
 	if err := r.client.Get(ctx, req.NamespacedName, instance); err != nil {
-		if errors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) {
 			//reqLogger.Info("can't found grpc call instance")
 			klog.Errorf("grpc call instance [%s] not found, probably deleted.", req.String())
 
@@ -79,11 +95,222 @@ func (r *ReconcileGrpcCall) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return reconcile.Result{}, err
 	}
 
+	if !instance.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, r.reconcileDelete(ctx, instance, reqLogger)
+	}
+
+	// GrpcCalls created before phases were introduced derive theirs from the times
+	phase := instance.Status.Phase
+	if phase == "" {
+		phase = initialPhase(&instance.Status)
+	}
+
+	// the finalizer cancels the unit-agent operation of a deleted active call
+	if !isFinishedPhase(phase) && controllerutil.AddFinalizer(instance, FinalizerGrpcCall) {
+		if err := r.client.Update(ctx, instance); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to add finalizer to grpc call [%s]: %v", req.String(), err)
+		}
+	}
+
 	oldStatus := instance.Status.DeepCopy()
+	instance.Status.Phase = phase
+
+	if isFinishedPhase(phase) {
+		return r.reconcileFinished(ctx, req, instance, oldStatus, reqLogger)
+	}
+
+	if err := validateTarget(instance); err != nil {
+		r.finishGrpcCall(instance, upmv1alpha1.FailedResult, err.Error())
+		r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
+
+		return r.requeueFinished(instance), nil
+	}
+
+	// the GrpcCalls targeting each unit enforce the deadlines and retries
+	if isFanOut(instance) {
+		return r.reconcileFanOut(ctx, instance, oldStatus, reqLogger)
+	}
+
+	if instance.Spec.Suspend {
+		r.cancelOperation(ctx, instance, reqLogger)
+		completeAttempt(instance, codes.Canceled, "suspended")
+		r.finishGrpcCall(instance, upmv1alpha1.CancelledResult, "grpc call is suspended")
+		r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
+
+		return r.requeueFinished(instance), nil
+	}
+
+	activeDeadline, hasActiveDeadline := activeDeadline(instance)
+	if hasActiveDeadline && !time.Now().Before(activeDeadline) {
+		r.cancelOperation(ctx, instance, reqLogger)
+		msg := fmt.Sprintf("exceeded the active deadline of %d seconds", *instance.Spec.ActiveDeadlineSeconds)
+		completeAttempt(instance, codes.DeadlineExceeded, msg)
+		r.finishGrpcCall(instance, upmv1alpha1.TimedOutResult, msg)
+		r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
+
+		return r.requeueFinished(instance), nil
+	}
+
+	if phase == upmv1alpha1.GrpcCallPending {
+		if oldStatus.Phase == "" {
+			if err := r.client.Status().Update(ctx, instance); err != nil {
+				klog.Errorf("failed to update grpc call [%s] status: %v", instance.Name, err)
+				return reconcile.Result{}, err
+			}
+
+			return reconcile.Result{Requeue: true}, nil
+		}
+
+		if next := instance.Status.NextAttemptTime; next != nil && time.Now().Before(next.Time) {
+			return reconcile.Result{RequeueAfter: time.Until(next.Time)}, nil
+		}
+
+		// Running is recorded before the call is sent, so that a call
+		// interrupted by an operator restart is resumed rather than lost
+		startAttempt(instance)
+		if instance.Spec.TargetUnitSet != nil {
+			unit, err := selectTargetUnit(ctx, r.client, instance)
+			if err != nil {
+				result := r.failAttempt(instance, status.Code(err), upmv1alpha1.FailedResult, err.Error())
+				r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
+
+				return result, nil
+			}
+
+			instance.Status.Unit = unit
+			currentAttempt(instance).Unit = unit
+		}
+
+		if err := r.client.Status().Update(ctx, instance); err != nil {
+			klog.Errorf("failed to update grpc call [%s] status: %v", instance.Name, err)
+			return reconcile.Result{}, err
+		}
+		oldStatus = instance.Status.DeepCopy()
+	}
+
+	attempt := currentAttempt(instance)
+
+	deadline, hasDeadline := attemptDeadline(instance, attempt)
+	if hasDeadline && !time.Now().Before(deadline) {
+		r.cancelOperation(ctx, instance, reqLogger)
+		result := r.failAttempt(instance, codes.DeadlineExceeded, upmv1alpha1.TimedOutResult,
+			fmt.Sprintf("timed out after %d seconds", *instance.Spec.TimeoutSeconds))
+		r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
+
+		return result, nil
+	}
+
+	polling := instance.Status.OperationID != ""
+
+	// Pipeline-style error handling
+	finished, err := func() (bool, error) {
+		host, port, err := gatherUnitAgentEndpoint(ctx, r.client, instance, reqLogger)
+		if err != nil {
+			return !polling, &codeError{code: codes.Unavailable, err: fmt.Errorf("failed to gather unit agent endpoint: %v", err)}
+		}
+
+		c, err := newGrpcClient(host, port)
+		if err != nil {
+			return !polling, &codeError{code: codes.Unavailable, err: fmt.Errorf("failed to initialize grpc client: %v", err)}
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+
+		if polling {
+			return r.pollOperation(ctx, instance, c)
+		}
+
+		callCtx := ctx
+		if hasDeadline {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
+
+		finished, err := r.handleGrpcCall(callCtx, instance, c, attemptOperationID(instance, attempt.Attempt))
+		if finished || err != nil {
+			return finished, err
+		}
+
+		// a short operation is often done by now
+		return r.pollOperation(ctx, instance, c)
+	}()
+	attempt.OperationID = instance.Status.OperationID
 
-	if oldStatus.CompletionTime != nil &&
+	// an operation which can not be polled yet is retried on the next poll
+	if !finished {
+		if err != nil {
+			klog.Errorf("failed to poll grpc call instance [%s] operation: %v", req.String(), err)
+		}
+
+		r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
+
+		requeueAfter := operationPollDelay(time.Since(attempt.StartTime.Time))
+		for _, d := range []struct {
+			at  time.Time
+			set bool
+		}{{deadline, hasDeadline}, {activeDeadline, hasActiveDeadline}} {
+			if d.set && time.Until(d.at) < requeueAfter {
+				requeueAfter = time.Until(d.at)
+			}
+		}
+
+		return reconcile.Result{
+			Requeue:      true,
+			RequeueAfter: requeueAfter,
+		}, nil
+	}
+
+	// Centralized error handling
+	var result ctrl.Result
+	switch {
+	case err == nil:
+		completeAttempt(instance, codes.OK, "")
+		r.finishGrpcCall(instance, upmv1alpha1.SuccessResult, instance.Status.Message)
+		result = r.requeueFinished(instance)
+	case errors.Is(err, errOperationCancelled):
+		completeAttempt(instance, codes.Canceled, err.Error())
+		r.finishGrpcCall(instance, upmv1alpha1.CancelledResult, err.Error())
+		result = r.requeueFinished(instance)
+	case hasDeadline && !time.Now().Before(deadline):
+		result = r.failAttempt(instance, codes.DeadlineExceeded, upmv1alpha1.TimedOutResult,
+			fmt.Sprintf("timed out after %d seconds: %v", *instance.Spec.TimeoutSeconds, err))
+	default:
+		result = r.failAttempt(instance, status.Code(err), upmv1alpha1.FailedResult, err.Error())
+	}
+
+	r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
+
+	return result, nil
+}
+
+// reconcileDelete cancels the unit-agent operation of an active GrpcCall
+// before letting it go.
+func (r *ReconcileGrpcCall) reconcileDelete(ctx context.Context, instance *upmv1alpha1.GrpcCall, reqLogger logr.Logger) error {
+	if !controllerutil.ContainsFinalizer(instance, FinalizerGrpcCall) {
+		return nil
+	}
+
+	if !isFinishedPhase(instance.Status.Phase) {
+		r.cancelOperation(ctx, instance, reqLogger)
+	}
+
+	controllerutil.RemoveFinalizer(instance, FinalizerGrpcCall)
+	return r.client.Update(ctx, instance)
+}
+
+// reconcileFinished deletes a finished GrpcCall once its TTL expired.
+func (r *ReconcileGrpcCall) reconcileFinished(
+	ctx context.Context,
+	req ctrl.Request,
+	instance *upmv1alpha1.GrpcCall,
+	oldStatus *upmv1alpha1.GrpcCallStatus,
+	reqLogger logr.Logger,
+) (ctrl.Result, error) {
+	if instance.Status.CompletionTime != nil &&
 		instance.Spec.TTLSecondsAfterFinished != nil &&
-		time.Since(oldStatus.CompletionTime.Time).Seconds() >= float64(*instance.Spec.TTLSecondsAfterFinished) {
+		time.Since(instance.Status.CompletionTime.Time).Seconds() >= float64(*instance.Spec.TTLSecondsAfterFinished) {
 		klog.Infof(
 			"grpc call instance [%s] is marked for automatic deletion: completed at [%s], TTL = %d seconds",
 			req.String(),
@@ -95,61 +322,99 @@ func (r *ReconcileGrpcCall) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return reconcile.Result{}, err
 	}
 
-	if oldStatus.StartTime != nil {
-		klog.Infof("grpc call instance [%s] is already started", req.String())
+	klog.Infof("grpc call instance [%s] is already finished", req.String())
+	r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
 
-		return reconcile.Result{
-			Requeue:      true,
-			RequeueAfter: time.Hour,
-		}, nil
-	} else {
+	if controllerutil.RemoveFinalizer(instance, FinalizerGrpcCall) {
+		if err := r.client.Update(ctx, instance); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to remove finalizer from grpc call [%s]: %v", req.String(), err)
+		}
+	}
+
+	return r.requeueFinished(instance), nil
+}
+
+// finishGrpcCall records the result of the call and the phase it maps to.
+func (r *ReconcileGrpcCall) finishGrpcCall(instance *upmv1alpha1.GrpcCall, result upmv1alpha1.Result, message string) {
+	instance.Status.Result = result
+	instance.Status.Message = message
+
+	switch result {
+	case upmv1alpha1.SuccessResult:
+		instance.Status.Phase = upmv1alpha1.GrpcCallSucceeded
+		r.recorder.Event(instance, corev1.EventTypeNormal, "OperationSucceeded", "process grpc call successfully")
+	case upmv1alpha1.CancelledResult:
+		instance.Status.Phase = upmv1alpha1.GrpcCallCancelled
+		r.recorder.Event(instance, corev1.EventTypeWarning, "OperationCancelled", message)
+	default:
+		instance.Status.Phase = upmv1alpha1.GrpcCallFailed
+		r.recorder.Event(instance, corev1.EventTypeWarning, "OperationFailed", message)
+	}
+
+	if instance.Status.CompletionTime == nil {
 		now := metav1.Now()
-		instance.Status.StartTime = &now
+		instance.Status.CompletionTime = &now
+	}
+}
+
+// cancelOperation cancels the unit-agent operation of the call, if any. The
+// call is finished regardless, an unreachable agent is only logged.
+func (r *ReconcileGrpcCall) cancelOperation(ctx context.Context, instance *upmv1alpha1.GrpcCall, reqLogger logr.Logger) {
+	if instance.Status.OperationID == "" {
+		return
 	}
 
-	// Pipeline-style error handling
 	err := func() error {
 		host, port, err := gatherUnitAgentEndpoint(ctx, r.client, instance, reqLogger)
 		if err != nil {
-			return fmt.Errorf("failed to gather unit agent endpoint: %v", err)
+			return err
 		}
 
 		c, err := newGrpcClient(host, port)
 		if err != nil {
-			return fmt.Errorf("failed to initialize grpc client: %v", err)
+			return err
 		}
 		defer func() {
 			_ = c.Close()
 		}()
 
-		if err = r.handleGrpcCall(ctx, instance, c); err != nil {
-			return err
-		} else {
-			r.recorder.Event(instance, corev1.EventTypeNormal,
-				"OperationSucceeded",
-				"process grpc call successfully")
-		}
-		return nil
+		_, err = c.Operation().CancelOperation(ctx, &operation.CancelOperationRequest{Id: instance.Status.OperationID})
+		return err
 	}()
-
-	// Centralized error handling
 	if err != nil {
-		instance.Status.Result = upmv1alpha1.FailedResult
-		instance.Status.Message = err.Error()
-		r.recorder.Event(instance, corev1.EventTypeWarning, "OperationFailed", err.Error())
+		klog.Errorf("failed to cancel grpc call [%s] operation %s: %v", instance.Name, instance.Status.OperationID, err)
 	}
+}
 
-	if instance.Status.CompletionTime == nil {
-		now := metav1.Now()
-		instance.Status.CompletionTime = &now
+// requeueFinished requeues a finished GrpcCall when its TTL expires.
+func (r *ReconcileGrpcCall) requeueFinished(instance *upmv1alpha1.GrpcCall) ctrl.Result {
+	if instance.Status.CompletionTime == nil || instance.Spec.TTLSecondsAfterFinished == nil {
+		return reconcile.Result{}
 	}
 
-	r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
+	ttl := time.Duration(*instance.Spec.TTLSecondsAfterFinished) * time.Second
+	remaining := time.Until(instance.Status.CompletionTime.Add(ttl))
+	if remaining < time.Second {
+		remaining = time.Second
+	}
 
-	return reconcile.Result{
-		Requeue:      true,
-		RequeueAfter: time.Hour,
-	}, nil
+	return reconcile.Result{RequeueAfter: remaining}
+}
+
+// initialPhase derives the phase of a GrpcCall which has none yet.
+func initialPhase(callStatus *upmv1alpha1.GrpcCallStatus) upmv1alpha1.GrpcCallPhase {
+	switch {
+	case callStatus.CompletionTime != nil && callStatus.Result == upmv1alpha1.SuccessResult:
+		return upmv1alpha1.GrpcCallSucceeded
+	case callStatus.CompletionTime != nil && callStatus.Result == upmv1alpha1.CancelledResult:
+		return upmv1alpha1.GrpcCallCancelled
+	case callStatus.CompletionTime != nil:
+		return upmv1alpha1.GrpcCallFailed
+	case callStatus.StartTime != nil:
+		return upmv1alpha1.GrpcCallRunning
+	default:
+		return upmv1alpha1.GrpcCallPending
+	}
 }
 
 func Setup(mgr ctrl.Manager) error {
@@ -162,5 +427,6 @@ func Setup(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&upmv1alpha1.GrpcCall{}).
+		Owns(&upmv1alpha1.GrpcCall{}).
 		Complete(r)
 }
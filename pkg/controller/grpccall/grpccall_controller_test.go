@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -259,8 +260,9 @@ func TestReconcileGrpcCall_Reconcile_TTLExpired(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
-func TestReconcileGrpcCall_Reconcile_AlreadyStarted(t *testing.T) {
+func TestReconcileGrpcCall_Reconcile_ResumesStartedCall(t *testing.T) {
 	mockClient := &MockClient{}
+	mockStatusWriter := &MockStatusWriter{}
 	mockRecorder := &MockEventRecorder{}
 
 	reconciler := &ReconcileGrpcCall{
@@ -277,12 +279,16 @@ func TestReconcileGrpcCall_Reconcile_AlreadyStarted(t *testing.T) {
 		},
 	}
 
+	// started before an operator restart, without a phase
 	startTime := metav1.Now()
 	instance := &upmv1alpha1.GrpcCall{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-grpccall",
 			Namespace: "default",
 		},
+		Spec: upmv1alpha1.GrpcCallSpec{
+			TargetUnit: "mysql-0",
+		},
 		Status: upmv1alpha1.GrpcCallStatus{
 			StartTime: &startTime,
 		},
@@ -293,13 +299,221 @@ func TestReconcileGrpcCall_Reconcile_AlreadyStarted(t *testing.T) {
 			obj := args.Get(2).(*upmv1alpha1.GrpcCall)
 			*obj = *instance
 		}).Return(nil)
+	mockClient.On("Get", mock.Anything, types.NamespacedName{Name: "mysql-0", Namespace: "default"}, mock.AnythingOfType("*v1alpha2.Unit"), mock.Anything).
+		Return(errors.NewNotFound(schema.GroupResource{Resource: "units"}, "mysql-0"))
+	mockClient.On("Status").Return(mockStatusWriter)
+
+	var updated *upmv1alpha1.GrpcCall
+	mockStatusWriter.On("Update", mock.Anything, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated = args.Get(1).(*upmv1alpha1.GrpcCall).DeepCopy()
+		}).Return(nil)
+	mockRecorder.On("Event", mock.Anything, corev1.EventTypeWarning, "OperationFailed", mock.Anything).Return()
+
+	mockClient.On("Update", mock.Anything, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).Return(nil)
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Equal(t, upmv1alpha1.GrpcCallFailed, updated.Status.Phase)
+	assert.Equal(t, upmv1alpha1.FailedResult, updated.Status.Result)
+	assert.Contains(t, updated.Status.Message, "failed to gather unit agent endpoint")
+	assert.NotNil(t, updated.Status.CompletionTime)
+	mockClient.AssertExpectations(t)
+}
+
+func TestReconcileGrpcCall_Reconcile_RecordsPendingThenRunning(t *testing.T) {
+	mockClient := &MockClient{}
+	mockStatusWriter := &MockStatusWriter{}
+
+	reconciler := &ReconcileGrpcCall{
+		client:   mockClient,
+		scheme:   runtime.NewScheme(),
+		recorder: &MockEventRecorder{},
+		logger:   zap.New().WithName("test"),
+	}
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "test-grpccall",
+			Namespace: "default",
+		},
+	}
+
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-grpccall",
+			Namespace: "default",
+		},
+		Spec: upmv1alpha1.GrpcCallSpec{
+			TargetUnit: "mysql-0",
+		},
+	}
+
+	mockClient.On("Get", mock.Anything, req.NamespacedName, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*upmv1alpha1.GrpcCall)
+			*obj = *instance
+		}).Return(nil)
+	mockClient.On("Status").Return(mockStatusWriter)
+	mockStatusWriter.On("Update", mock.Anything, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			instance = args.Get(1).(*upmv1alpha1.GrpcCall).DeepCopy()
+		}).Return(nil).Once()
+
+	mockClient.On("Update", mock.Anything, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).Return(nil)
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, reconcile.Result{Requeue: true}, result)
+	assert.Equal(t, upmv1alpha1.GrpcCallPending, instance.Status.Phase)
+	assert.Nil(t, instance.Status.StartTime)
+
+	// Running is recorded before the unit agent is called, a failure to record it
+	// leaves the call Pending
+	mockStatusWriter.On("Update", mock.Anything, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).
+		Return(fmt.Errorf("conflict")).Once()
+
+	_, err = reconciler.Reconcile(context.Background(), req)
+
+	assert.EqualError(t, err, "conflict")
+	mockStatusWriter.AssertExpectations(t)
+}
+
+func TestReconcileGrpcCall_Reconcile_TimesOut(t *testing.T) {
+	mockClient := &MockClient{}
+	mockStatusWriter := &MockStatusWriter{}
+	mockRecorder := &MockEventRecorder{}
+
+	reconciler := &ReconcileGrpcCall{
+		client:   mockClient,
+		scheme:   runtime.NewScheme(),
+		recorder: mockRecorder,
+		logger:   zap.New().WithName("test"),
+	}
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "test-grpccall",
+			Namespace: "default",
+		},
+	}
+
+	timeout := int32(60)
+	ttl := int32(600)
+	startTime := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-grpccall",
+			Namespace: "default",
+		},
+		Spec: upmv1alpha1.GrpcCallSpec{
+			TargetUnit:              "mysql-0",
+			TimeoutSeconds:          &timeout,
+			TTLSecondsAfterFinished: &ttl,
+		},
+		Status: upmv1alpha1.GrpcCallStatus{
+			Phase:     upmv1alpha1.GrpcCallRunning,
+			StartTime: &startTime,
+		},
+	}
+
+	mockClient.On("Get", mock.Anything, req.NamespacedName, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*upmv1alpha1.GrpcCall)
+			*obj = *instance
+		}).Return(nil)
+	mockClient.On("Status").Return(mockStatusWriter)
+
+	var updated *upmv1alpha1.GrpcCall
+	mockStatusWriter.On("Update", mock.Anything, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated = args.Get(1).(*upmv1alpha1.GrpcCall).DeepCopy()
+		}).Return(nil)
+	mockRecorder.On("Event", mock.Anything, corev1.EventTypeWarning, "OperationFailed", "timed out after 60 seconds").Return()
+
+	mockClient.On("Update", mock.Anything, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).Return(nil)
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.InDelta(t, (10 * time.Minute).Seconds(), result.RequeueAfter.Seconds(), 5)
+	assert.Equal(t, upmv1alpha1.GrpcCallFailed, updated.Status.Phase)
+	assert.Equal(t, upmv1alpha1.TimedOutResult, updated.Status.Result)
+	mockRecorder.AssertExpectations(t)
+}
+
+func TestInitialPhase(t *testing.T) {
+	now := metav1.Now()
+
+	assert.Equal(t, upmv1alpha1.GrpcCallPending, initialPhase(&upmv1alpha1.GrpcCallStatus{}))
+	assert.Equal(t, upmv1alpha1.GrpcCallRunning, initialPhase(&upmv1alpha1.GrpcCallStatus{StartTime: &now}))
+	assert.Equal(t, upmv1alpha1.GrpcCallSucceeded, initialPhase(&upmv1alpha1.GrpcCallStatus{
+		StartTime: &now, CompletionTime: &now, Result: upmv1alpha1.SuccessResult}))
+	assert.Equal(t, upmv1alpha1.GrpcCallFailed, initialPhase(&upmv1alpha1.GrpcCallStatus{
+		StartTime: &now, CompletionTime: &now, Result: upmv1alpha1.FailedResult}))
+}
+
+func TestReconcileGrpcCall_Reconcile_PollsOperation(t *testing.T) {
+	mockClient := &MockClient{}
+	mockRecorder := &MockEventRecorder{}
+
+	reconciler := &ReconcileGrpcCall{
+		client:   mockClient,
+		scheme:   runtime.NewScheme(),
+		recorder: mockRecorder,
+		logger:   zap.New().WithName("test"),
+	}
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "test-grpccall",
+			Namespace: "default",
+		},
+	}
+
+	startTime := metav1.Now()
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-grpccall",
+			Namespace: "default",
+		},
+		Spec: upmv1alpha1.GrpcCallSpec{
+			TargetUnit: "mysql-0",
+		},
+		Status: upmv1alpha1.GrpcCallStatus{
+			Phase:       upmv1alpha1.GrpcCallRunning,
+			StartTime:   &startTime,
+			OperationID: "op-1",
+			Message:     "physical-backup mysql operation op-1 running",
+		},
+	}
+
+	mockClient.On("Get", mock.Anything, req.NamespacedName, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*upmv1alpha1.GrpcCall)
+			*obj = *instance
+		}).Return(nil)
+	// the unit agent is unreachable, the operation is polled again later
+	mockClient.On("Get", mock.Anything, types.NamespacedName{Name: "mysql-0", Namespace: "default"}, mock.AnythingOfType("*v1alpha2.Unit"), mock.Anything).
+		Return(fmt.Errorf("connection refused"))
+
+	// the attempt of a call started before attempts were recorded is recorded
+	mockStatusWriter := &MockStatusWriter{}
+	mockClient.On("Status").Return(mockStatusWriter)
+	mockStatusWriter.On("Update", mock.Anything, mock.MatchedBy(func(obj *upmv1alpha1.GrpcCall) bool {
+		return len(obj.Status.Attempts) == 1 && obj.Status.Attempts[0].OperationID == "op-1"
+	}), mock.Anything).Return(nil)
+	mockClient.On("Update", mock.Anything, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).Return(nil)
 
 	result, err := reconciler.Reconcile(context.Background(), req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, reconcile.Result{
 		Requeue:      true,
-		RequeueAfter: time.Hour,
+		RequeueAfter: operationPollInitialInterval,
 	}, result)
 	mockClient.AssertExpectations(t)
 }
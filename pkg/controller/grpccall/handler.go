@@ -3,26 +3,48 @@ package grpccall
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
 	"github.com/upmio/unit-operator/pkg/agent/app/clickhouse"
 	"github.com/upmio/unit-operator/pkg/agent/app/common"
 	"github.com/upmio/unit-operator/pkg/agent/app/milvus"
 	"github.com/upmio/unit-operator/pkg/agent/app/mongodb"
 	"github.com/upmio/unit-operator/pkg/agent/app/mysql"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
 	"github.com/upmio/unit-operator/pkg/agent/app/postgresql"
 	"github.com/upmio/unit-operator/pkg/agent/app/proxysql"
 	"github.com/upmio/unit-operator/pkg/agent/app/redis"
+	"github.com/upmio/unit-operator/pkg/agent/app/rediscluster"
 	"github.com/upmio/unit-operator/pkg/agent/app/sentinel"
+	"github.com/upmio/unit-operator/pkg/agent/app/slm"
+	"github.com/upmio/unit-operator/pkg/utils/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+	yaml "gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // unmarshalParams serializes the raw Parameters map to JSON
 // and unmarshals into the provided proto message.
 func unmarshalParams(params map[string]apiextensionsv1.JSON, msg proto.Message) error {
+	if len(params) == 0 {
+		return nil
+	}
+
 	data, err := json.Marshal(params)
 	if err != nil {
 		return err
@@ -33,207 +55,483 @@ func unmarshalParams(params map[string]apiextensionsv1.JSON, msg proto.Message)
 	return nil
 }
 
-// handleGrpcCall processes a GrpcCall CR by routing to the proper client
-// stub based on unit type and action, handling request construction, execution,
-// and status updates in a DRY manner.
+// errOperationCancelled is returned for unit-agent operations which were cancelled.
+var errOperationCancelled = errors.New("cancelled")
+
+// handleGrpcCall processes a GrpcCall CR by resolving its unit-agent method,
+// constructing the request and starting it as the unit-agent operation
+// operationID. The response of a unit-agent which ran the call synchronously is
+// handled at once, otherwise the operation is recorded in the status and polled
+// by pollOperation.
 func (r *ReconcileGrpcCall) handleGrpcCall(
 	ctx context.Context,
 	instance *upmv1alpha1.GrpcCall,
 	c *Client,
+	operationID string,
+) (bool, error) {
+	method, err := resolveMethod(ctx, instance, c)
+	if err != nil {
+		return true, err
+	}
+
+	req, err := method.newRequest(instance.Spec.Parameters)
+	if err != nil {
+		return true, &codeError{code: codes.InvalidArgument, err: err}
+	}
+
+	if err := r.holdProcessState(ctx, instance, method.name()); err != nil {
+		var ce *codeError
+		return errors.As(err, &ce), err
+	}
+
+	// a start retried after an operator restart joins the operation
+	var header metadata.MD
+	resp, err := method.invoke(operation.AsyncContext(ctx, operationID), c.conn, req, grpc.Header(&header))
+	if err != nil {
+		return true, err
+	}
+
+	if id := operation.IDFromHeader(header); id != "" {
+		instance.Status.OperationID = id
+		instance.Status.Message = fmt.Sprintf("%s %s operation %s started", callName(instance), instance.Spec.Type, id)
+		return false, nil
+	}
+
+	return true, r.handleGrpcCallResponse(ctx, instance, req, resp)
+}
+
+// pollOperation gets the unit-agent operation of a GrpcCall, handling its
+// response once it succeeded.
+func (r *ReconcileGrpcCall) pollOperation(
+	ctx context.Context,
+	instance *upmv1alpha1.GrpcCall,
+	c *Client,
+) (bool, error) {
+	id := instance.Status.OperationID
+
+	op, err := c.Operation().GetOperation(ctx, &operation.GetOperationRequest{Id: id})
+	if err != nil {
+		// lost with a unit-agent which does not persist its operations
+		if status.Code(err) == codes.NotFound {
+			return true, &codeError{code: codes.Aborted, err: fmt.Errorf("operation %s not found on unit agent", id)}
+		}
+		return false, fmt.Errorf("failed to get operation %s: %v", id, err)
+	}
+
+	switch op.GetState() {
+	case operation.State_SUCCEEDED:
+	case operation.State_CANCELLED:
+		return true, fmt.Errorf("%w: operation %s: %s", errOperationCancelled, id, op.GetError())
+	case operation.State_FAILED:
+		return true, &codeError{code: codes.Code(op.GetCode()), err: fmt.Errorf("operation %s failed: %s", id, op.GetError())}
+	default:
+		instance.Status.Message = operationMessage(instance, op)
+		return false, nil
+	}
+
+	method, err := resolveMethod(ctx, instance, c)
+	if err != nil {
+		return true, err
+	}
+
+	req, err := method.newRequest(instance.Spec.Parameters)
+	if err != nil {
+		return true, err
+	}
+
+	var resp proto.Message
+	if op.GetResponse() != nil {
+		if resp, err = anypb.UnmarshalNew(op.GetResponse(), proto.UnmarshalOptions{Resolver: method.types}); err != nil {
+			return true, fmt.Errorf("failed to unmarshal response of operation %s: %v", id, err)
+		}
+	}
+
+	return true, r.handleGrpcCallResponse(ctx, instance, req, resp)
+}
+
+// handleGrpcCallResponse updates the status from the response of the call.
+func (r *ReconcileGrpcCall) handleGrpcCallResponse(
+	ctx context.Context,
+	instance *upmv1alpha1.GrpcCall,
+	req proto.Message,
+	resp proto.Message,
 ) error {
-	var (
-		newReq func() proto.Message
-		callFn func(ctx context.Context, msg proto.Message) (*common.Empty, error)
-	)
-
-	switch instance.Spec.Type {
-	case upmv1alpha1.MysqlType:
-		mc := c.Mysql()
-		switch instance.Spec.Action {
-		case upmv1alpha1.PhysicalBackupAction:
-			newReq = func() proto.Message { return &mysql.PhysicalBackupRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.PhysicalBackup(ctx, msg.(*mysql.PhysicalBackupRequest))
-			}
-		case upmv1alpha1.LogicalBackupAction:
-			newReq = func() proto.Message { return &mysql.LogicalBackupRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.LogicalBackup(ctx, msg.(*mysql.LogicalBackupRequest))
-			}
-		case upmv1alpha1.CloneAction:
-			newReq = func() proto.Message { return &mysql.CloneRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.Clone(ctx, msg.(*mysql.CloneRequest))
-			}
-		case upmv1alpha1.GtidPurgeAction:
-			newReq = func() proto.Message { return &mysql.GtidPurgeRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.GtidPurge(ctx, msg.(*mysql.GtidPurgeRequest))
-			}
-		case upmv1alpha1.SetVariableAction:
-			newReq = func() proto.Message { return &mysql.SetVariableRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.SetVariable(ctx, msg.(*mysql.SetVariableRequest))
-			}
-		case upmv1alpha1.RestoreAction:
-			newReq = func() proto.Message { return &mysql.RestoreRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.Restore(ctx, msg.(*mysql.RestoreRequest))
-			}
-		default:
-			return fmt.Errorf("unsupported action %q for type %q", instance.Spec.Action, instance.Spec.Type)
-		}
-	case upmv1alpha1.PostgresqlType:
-		pc := c.Postgresql()
-		switch instance.Spec.Action {
-		case upmv1alpha1.PhysicalBackupAction:
-			newReq = func() proto.Message { return &postgresql.PhysicalBackupRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return pc.PhysicalBackup(ctx, msg.(*postgresql.PhysicalBackupRequest))
-			}
-		case upmv1alpha1.LogicalBackupAction:
-			newReq = func() proto.Message { return &postgresql.LogicalBackupRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return pc.LogicalBackup(ctx, msg.(*postgresql.LogicalBackupRequest))
-			}
-		case upmv1alpha1.RestoreAction:
-			newReq = func() proto.Message { return &postgresql.RestoreRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return pc.Restore(ctx, msg.(*postgresql.RestoreRequest))
-			}
-		case upmv1alpha1.SetVariableAction:
-			newReq = func() proto.Message { return &postgresql.SetVariableRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return pc.SetVariable(ctx, msg.(*postgresql.SetVariableRequest))
-			}
-		default:
-			return fmt.Errorf("unsupported action %q for type %q", instance.Spec.Action, instance.Spec.Type)
-		}
-	case upmv1alpha1.ProxysqlType:
-		pc := c.Proxysql()
-		switch instance.Spec.Action {
-		case upmv1alpha1.SetVariableAction:
-			newReq = func() proto.Message { return &proxysql.SetVariableRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return pc.SetVariable(ctx, msg.(*proxysql.SetVariableRequest))
-			}
-		default:
-			return fmt.Errorf("unsupported action %q for type %q", instance.Spec.Action, instance.Spec.Type)
-		}
-	case upmv1alpha1.RedisType:
-		rc := c.Redis()
-		switch instance.Spec.Action {
-		case upmv1alpha1.SetVariableAction:
-			newReq = func() proto.Message { return &redis.SetVariableRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return rc.SetVariable(ctx, msg.(*redis.SetVariableRequest))
-			}
-		case upmv1alpha1.BackupAction:
-			newReq = func() proto.Message { return &redis.BackupRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return rc.Backup(ctx, msg.(*redis.BackupRequest))
-			}
-		case upmv1alpha1.RestoreAction:
-			newReq = func() proto.Message { return &redis.RestoreRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return rc.Restore(ctx, msg.(*redis.RestoreRequest))
+	instance.Status.Message = fmt.Sprintf("%s %s successfully", callName(instance), instance.Spec.Type)
+
+	switch svr := resp.(type) {
+	case *common.SetVariableResponse:
+		if svr.GetPersisted() {
+			if err := r.persistConfigValues(ctx, instance, setVariableValues(req)); err != nil {
+				return fmt.Errorf("failed to persist config value: %v", err)
 			}
-		default:
-			return fmt.Errorf("unsupported action %q for type %q", instance.Spec.Action, instance.Spec.Type)
-		}
-	case upmv1alpha1.SentinelType:
-		sc := c.Sentinel()
-		switch instance.Spec.Action {
-		case upmv1alpha1.SetVariableAction:
-			newReq = func() proto.Message { return &sentinel.SetVariableRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return sc.SetVariable(ctx, msg.(*sentinel.SetVariableRequest))
+		}
+		if svr.GetChanged() {
+			instance.Status.Message += fmt.Sprintf(", changed from %q to %q", svr.GetPreviousValue(), svr.GetCurrentValue())
+		}
+		if svr.GetRestartRequired() {
+			instance.Status.Message += ", restart required"
+		}
+	case *common.SetVariablesResponse:
+		if err := svr.FailedError(); err != nil {
+			return err
+		}
+		if svr.GetPersisted() && !svr.GetDryRun() {
+			if err := r.persistConfigValues(ctx, instance, appliedVariableValues(req, svr)); err != nil {
+				return fmt.Errorf("failed to persist config value: %v", err)
 			}
-		default:
-			return fmt.Errorf("unsupported action %q for type %q", instance.Spec.Action, instance.Spec.Type)
 		}
+		instance.Status.Message = setVariablesMessage(instance, svr)
+	case *rediscluster.RebalanceResponse:
+		instance.Status.Message += fmt.Sprintf(", migrated %d slots (%d keys), %d slots remaining",
+			svr.GetMigratedSlots(), svr.GetMigratedKeys(), svr.GetRemainingSlots())
+	case *rediscluster.ClusterHealthResponse:
+		instance.Status.Message = clusterHealthMessage(svr)
+	case *sentinel.ResetResponse:
+		instance.Status.Message += fmt.Sprintf(", reset %d masters", svr.GetResetMasters())
+	case *sentinel.ListMastersResponse:
+		instance.Status.Message = sentinelInstancesMessage("masters", svr.GetMasters())
+	case *sentinel.ListReplicasResponse:
+		instance.Status.Message = sentinelInstancesMessage("replicas", svr.GetReplicas())
+	case *mongodb.ReplicaSetStatusResponse:
+		instance.Status.Message = replicaSetStatusMessage(svr)
+	case *clickhouse.BackupOperation:
+		if clickhouse.IsBackupOperationFailed(svr.GetStatus()) {
+			return fmt.Errorf("backup operation %s %s: %s", svr.GetId(), svr.GetStatus(), svr.GetError())
+		}
+		instance.Status.Message += backupOperationMessage(svr)
+	case *slm.ProcessStatusResponse:
+		instance.Status.Message = processStatusMessage(instance, svr)
+	case *milvus.ListBackupsResponse:
+		instance.Status.Message = milvusBackupsMessage(svr.GetBackups())
+	case *milvus.BackupInfo:
+		instance.Status.Message = milvusBackupsMessage([]*milvus.BackupInfo{svr})
+	case *mongodb.ArchiveOplogResponse:
+		if svr.GetObject() == "" {
+			instance.Status.Message += fmt.Sprintf(", no new oplog entries after %s", svr.GetEnd())
+		} else {
+			instance.Status.Message += fmt.Sprintf(", archived oplog up to %s as %s (%d chunks)", svr.GetEnd(), svr.GetObject(), svr.GetChunks())
+		}
+	case *common.BackupResponse:
+		instance.Status.Message += fmt.Sprintf(", wrote %d bytes to %s", svr.GetSizeBytes(), svr.GetObject())
+		if svr.GetPosition() != "" {
+			instance.Status.Message += fmt.Sprintf(" at %s", svr.GetPosition())
+		}
+	case *common.RestoreResponse:
+		instance.Status.Message += fmt.Sprintf(", read %d bytes from %s", svr.GetSizeBytes(), svr.GetObject())
+		if svr.GetPosition() != "" {
+			instance.Status.Message += fmt.Sprintf(" up to %s", svr.GetPosition())
+		}
+	case *mysql.CloneResponse:
+		instance.Status.Message += fmt.Sprintf(", cloned %d bytes from %s", svr.GetSizeBytes(), svr.GetSource())
+		if svr.GetPosition() != "" {
+			instance.Status.Message += fmt.Sprintf(" up to %s", svr.GetPosition())
+		}
+	}
 
-	case upmv1alpha1.MilvusType:
-		mc := c.Milvus()
-		switch instance.Spec.Action {
-		case upmv1alpha1.BackupAction:
-			newReq = func() proto.Message { return &milvus.BackupRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.Backup(ctx, msg.(*milvus.BackupRequest))
-			}
-		case upmv1alpha1.RestoreAction:
-			newReq = func() proto.Message { return &milvus.RestoreRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.Restore(ctx, msg.(*milvus.RestoreRequest))
-			}
-		case upmv1alpha1.SetVariableAction:
-			newReq = func() proto.Message { return &milvus.SetVariableRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.SetVariable(ctx, msg.(*milvus.SetVariableRequest))
+	outputs, err := responseOutputs(resp)
+	if err != nil {
+		return fmt.Errorf("failed to convert response to outputs: %v", err)
+	}
+	instance.Status.Outputs = outputs
+
+	instance.Status.Result = upmv1alpha1.SuccessResult
+
+	return nil
+}
+
+// responseOutputs converts the populated top-level fields of a response to
+// the outputs of a GrpcCall, keyed by their proto names. 64-bit integers,
+// which protojson writes as strings, are kept as JSON numbers.
+func responseOutputs(resp proto.Message) (map[string]apiextensionsv1.JSON, error) {
+	if resp == nil || !resp.ProtoReflect().IsValid() {
+		return nil, nil
+	}
+
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	descriptors := resp.ProtoReflect().Descriptor().Fields()
+	outputs := make(map[string]apiextensionsv1.JSON, len(fields))
+	for name, raw := range fields {
+		if fd := descriptors.ByName(protoreflect.Name(name)); fd != nil && !fd.IsList() && !fd.IsMap() && is64BitInteger(fd.Kind()) {
+			var number string
+			if err := json.Unmarshal(raw, &number); err == nil {
+				raw = json.RawMessage(number)
 			}
-		default:
-			return fmt.Errorf("unsupported action %q for type %q", instance.Spec.Action, instance.Spec.Type)
 		}
+		outputs[name] = apiextensionsv1.JSON{Raw: raw}
+	}
 
-	case upmv1alpha1.MongoDBType:
-		mc := c.MongoDB()
-		switch instance.Spec.Action {
-		case upmv1alpha1.BackupAction:
-			newReq = func() proto.Message { return &mongodb.BackupRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.Backup(ctx, msg.(*mongodb.BackupRequest))
-			}
-		case upmv1alpha1.RestoreAction:
-			newReq = func() proto.Message { return &mongodb.RestoreRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.Restore(ctx, msg.(*mongodb.RestoreRequest))
-			}
-		case upmv1alpha1.SetVariableAction:
-			newReq = func() proto.Message { return &mongodb.SetVariableRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return mc.SetVariable(ctx, msg.(*mongodb.SetVariableRequest))
-			}
-		default:
-			return fmt.Errorf("unsupported action %q for type %q", instance.Spec.Action, instance.Spec.Type)
-		}
-	case upmv1alpha1.ClickHouseType:
-		chc := c.ClickHouse()
-		switch instance.Spec.Action {
-		case upmv1alpha1.LogicalBackupAction:
-			newReq = func() proto.Message { return &clickhouse.LogicalBackupRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return chc.LogicalBackup(ctx, msg.(*clickhouse.LogicalBackupRequest))
-			}
-		case upmv1alpha1.RestoreAction:
-			newReq = func() proto.Message { return &clickhouse.RestoreRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return chc.Restore(ctx, msg.(*clickhouse.RestoreRequest))
-			}
-		case upmv1alpha1.SetVariableAction:
-			newReq = func() proto.Message { return &clickhouse.SetVariableRequest{} }
-			callFn = func(ctx context.Context, msg proto.Message) (*common.Empty, error) {
-				return chc.SetVariable(ctx, msg.(*clickhouse.SetVariableRequest))
-			}
-		default:
-			return fmt.Errorf("unsupported action %q for type %q", instance.Spec.Action, instance.Spec.Type)
+	return outputs, nil
+}
+
+func is64BitInteger(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return true
+	}
+	return false
+}
+
+// isBatchSetVariable reports whether the set-variable parameters carry a
+// variables map instead of a single key and value.
+func isBatchSetVariable(instance *upmv1alpha1.GrpcCall) bool {
+	_, ok := instance.Spec.Parameters["variables"]
+	return ok
+}
+
+// setVariableValues extracts the config value keys and values from a
+// SetVariable request, returning nil for requests that carry none.
+func setVariableValues(msg proto.Message) map[string]string {
+	switch req := msg.(type) {
+	case *mysql.SetVariableRequest:
+		return map[string]string{req.GetKey(): req.GetValue()}
+	case *postgresql.SetVariableRequest:
+		return map[string]string{req.GetKey(): req.GetValue()}
+	case *redis.SetVariableRequest:
+		return map[string]string{req.GetKey(): req.GetValue()}
+	case *proxysql.SetVariableRequest:
+		return map[string]string{req.GetSection() + "-" + req.GetKey(): req.GetValue()}
+	default:
+		return nil
+	}
+}
+
+// appliedVariableValues returns the applied keys of a SetVariables batch with
+// their desired values, for the engines rendered from the config value.
+func appliedVariableValues(msg proto.Message, resp *common.SetVariablesResponse) map[string]string {
+	switch msg.(type) {
+	case *mysql.SetVariablesRequest, *postgresql.SetVariablesRequest, *redis.SetVariablesRequest, *proxysql.SetVariablesRequest:
+	default:
+		return nil
+	}
+
+	values := make(map[string]string)
+	for _, result := range resp.GetResults() {
+		if result.GetApplied() {
+			values[result.GetKey()] = result.GetDesiredValue()
+		}
+	}
+
+	return values
+}
+
+// setVariablesMessage summarizes a SetVariables batch, listing the diff of a dry run.
+func setVariablesMessage(instance *upmv1alpha1.GrpcCall, resp *common.SetVariablesResponse) string {
+	var changed []string
+	restartRequired := false
+	for _, result := range resp.GetResults() {
+		if result.GetChanged() {
+			changed = append(changed, fmt.Sprintf("%s: %q -> %q", result.GetKey(), result.GetCurrentValue(), result.GetDesiredValue()))
+		}
+		if result.GetRestartRequired() {
+			restartRequired = true
+		}
+	}
+
+	if resp.GetDryRun() {
+		msg := fmt.Sprintf("dry run %s %s, %d of %d variables would change", callName(instance), instance.Spec.Type, len(changed), len(resp.GetResults()))
+		if len(changed) > 0 {
+			msg += ": " + strings.Join(changed, ", ")
+		}
+		return msg
+	}
+
+	msg := fmt.Sprintf("%s %s successfully, %d of %d variables changed", callName(instance), instance.Spec.Type, len(changed), len(resp.GetResults()))
+	if restartRequired {
+		msg += ", restart required"
+	}
+	return msg
+}
+
+// clusterHealthMessage summarizes the health of a Redis Cluster.
+func clusterHealthMessage(resp *rediscluster.ClusterHealthResponse) string {
+	msg := fmt.Sprintf("cluster state %s, %d of 16384 slots ok, %d known nodes", resp.GetState(), resp.GetSlotsOk(), resp.GetKnownNodes())
+
+	if n := len(resp.GetUncoveredSlots()); n > 0 {
+		ranges := make([]string, 0, n)
+		for _, r := range resp.GetUncoveredSlots() {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", r.GetStart(), r.GetEnd()))
+		}
+		msg += ", uncovered slots " + strings.Join(ranges, ",")
+	}
+
+	if n := len(resp.GetFailingNodes()); n > 0 {
+		nodes := make([]string, 0, n)
+		for _, node := range resp.GetFailingNodes() {
+			nodes = append(nodes, fmt.Sprintf("%s (%s)", node.GetId(), node.GetAddress()))
+		}
+		msg += ", failing nodes " + strings.Join(nodes, ",")
+	}
+
+	if n := len(resp.GetOpenSlots()); n > 0 {
+		msg += fmt.Sprintf(", %d slots migrating", n)
+	}
+
+	return msg
+}
+
+// sentinelInstancesMessage lists the sentinel instances with their address and flags.
+func sentinelInstancesMessage(kind string, instances []*sentinel.SentinelInstance) string {
+	if len(instances) == 0 {
+		return "no " + kind
+	}
+
+	items := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		items = append(items, fmt.Sprintf("%s (%s:%d %s)", instance.GetName(), instance.GetHost(), instance.GetPort(), strings.Join(instance.GetFlags(), ",")))
+	}
+
+	return fmt.Sprintf("%d %s: %s", len(instances), kind, strings.Join(items, ", "))
+}
+
+// replicaSetStatusMessage summarizes the member states of a MongoDB replica set.
+func replicaSetStatusMessage(resp *mongodb.ReplicaSetStatusResponse) string {
+	if !resp.GetInitiated() {
+		return fmt.Sprintf("replica set %s is not initiated", resp.GetSet())
+	}
+
+	members := make([]string, 0, len(resp.GetMembers()))
+	for _, member := range resp.GetMembers() {
+		members = append(members, fmt.Sprintf("%s %s", member.GetHost(), member.GetState()))
+	}
+
+	return fmt.Sprintf("replica set %s primary %q, max lag %ds, members %s",
+		resp.GetSet(), resp.GetPrimary(), resp.GetMaxLagSeconds(), strings.Join(members, ", "))
+}
+
+// backupOperationMessage reports the id, status and sizes of a ClickHouse
+// backup or restore, an operation started with ASYNC has no sizes yet.
+func backupOperationMessage(op *clickhouse.BackupOperation) string {
+	if op.GetId() == "" {
+		return ""
+	}
+
+	msg := fmt.Sprintf(", %s %s", op.GetId(), op.GetStatus())
+	if op.GetNumFiles() > 0 {
+		msg += fmt.Sprintf(", %d files, %d bytes (%d compressed)", op.GetNumFiles(), op.GetTotalSize(), op.GetCompressedSize())
+	}
+	if op.GetFilesRead() > 0 {
+		msg += fmt.Sprintf(", read %d files (%d bytes)", op.GetFilesRead(), op.GetBytesRead())
+	}
+
+	return msg
+}
+
+// milvusBackupsMessage summarizes milvus backups, with their state, size and
+// collections when they were described.
+func milvusBackupsMessage(backups []*milvus.BackupInfo) string {
+	items := make([]string, 0, len(backups))
+	for _, backup := range backups {
+		if backup.GetState() == "" {
+			items = append(items, backup.GetName())
+			continue
+		}
+
+		collections := make([]string, 0, len(backup.GetCollections()))
+		for _, collection := range backup.GetCollections() {
+			collections = append(collections, collection.GetDatabase()+"."+collection.GetName())
 		}
+		items = append(items, fmt.Sprintf("%s %s %d bytes created %s [%s]", backup.GetName(), backup.GetState(), backup.GetSize(),
+			time.UnixMilli(backup.GetStartTime()).UTC().Format(time.RFC3339), strings.Join(collections, ",")))
+	}
+
+	return fmt.Sprintf("%d backups: %s", len(backups), strings.Join(items, ", "))
+}
+
+// operationMessage reports a unit-agent operation which is still running.
+func operationMessage(instance *upmv1alpha1.GrpcCall, op *operation.Operation) string {
+	msg := fmt.Sprintf("%s %s operation %s %s", callName(instance), instance.Spec.Type, op.GetId(), strings.ToLower(op.GetState().String()))
+	if op.GetProgress() != "" {
+		msg += ": " + op.GetProgress()
+	}
+
+	return msg
+}
+
+// configValue decodes a variable value as a YAML scalar so numbers and
+// booleans keep their type in the config value, falling back to the raw string.
+func configValue(value string) interface{} {
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(value), &v); err != nil {
+		return value
+	}
+	switch v.(type) {
+	case int, int64, float64, bool:
+		return v
 	default:
-		return fmt.Errorf("unsupported unit type %q", instance.Spec.Type)
+		return value
+	}
+}
+
+// persistConfigValues writes persisted variables back into the target unit's
+// config value ConfigMap so that confd renders the same values on restart.
+// Keys that are not already managed by the config value are left untouched.
+func (r *ReconcileGrpcCall) persistConfigValues(
+	ctx context.Context,
+	instance *upmv1alpha1.GrpcCall,
+	values map[string]string,
+) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	unit := &upmv1alpha2.Unit{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: targetUnitName(instance), Namespace: instance.Namespace}, unit); err != nil {
+		return fmt.Errorf("failed to fetch unit [%s]: %v", targetUnitName(instance), err)
 	}
 
-	// Construct request, execute call, update status
-	req := newReq()
-	if err := unmarshalParams(instance.Spec.Parameters, req); err != nil {
-		return fmt.Errorf("failed to unmarshal parameters: %v", err)
+	if unit.Spec.ConfigValueName == "" {
+		return nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: unit.Spec.ConfigValueName, Namespace: unit.Namespace}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch config value configmap [%s]: %v", unit.Spec.ConfigValueName, err)
 	}
 
-	_, err := callFn(ctx, req)
+	dataKey := unit.MainContainerName()
+	content, ok := cm.Data[dataKey]
+	if !ok {
+		return nil
+	}
+
+	configer, err := config.NewViper(content, "yaml")
 	if err != nil {
 		return err
 	}
 
-	instance.Status.Message = fmt.Sprintf("%s %s successfully", instance.Spec.Action, instance.Spec.Type)
-	instance.Status.Result = upmv1alpha1.SuccessResult
+	for key, value := range values {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" || !configer.IsSet(key) {
+			continue
+		}
+		configer.Set(key, configValue(value))
+	}
 
-	return nil
+	newContent, err := config.Viper2String(configer)
+	if err != nil {
+		return err
+	}
+	if newContent == content {
+		return nil
+	}
+
+	cm.Data[dataKey] = newContent
+	return r.client.Update(ctx, cm)
 }
@@ -1,13 +1,37 @@
 package grpccall
 
 import (
+	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app"
 	"github.com/upmio/unit-operator/pkg/agent/app/clickhouse"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/milvus"
+	"github.com/upmio/unit-operator/pkg/agent/app/mongodb"
 	"github.com/upmio/unit-operator/pkg/agent/app/mysql"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
+	"github.com/upmio/unit-operator/pkg/agent/app/proxysql"
+	"github.com/upmio/unit-operator/pkg/agent/app/rediscluster"
+	"github.com/upmio/unit-operator/pkg/agent/app/sentinel"
 )
 
 // Test unmarshalParams function
@@ -187,3 +211,332 @@ func TestUnmarshalParams_ClickHouseLogicalBackup(t *testing.T) {
 	assert.Equal(t, "admin", msg.GetUsername())
 	assert.Equal(t, "clickhouse", msg.GetObjectStorage().GetBucket())
 }
+
+func TestSetVariableValues(t *testing.T) {
+	assert.Equal(t, map[string]string{"max_connections": "500"},
+		setVariableValues(&mysql.SetVariableRequest{Key: "max_connections", Value: "500"}))
+	assert.Equal(t, map[string]string{"mysql-max_connections": "2048"},
+		setVariableValues(&proxysql.SetVariableRequest{Section: "mysql", Key: "max_connections", Value: "2048"}))
+	assert.Nil(t, setVariableValues(&sentinel.SetVariableRequest{}))
+}
+
+func TestAppliedVariableValues(t *testing.T) {
+	resp := &common.SetVariablesResponse{Results: []*common.VariableResult{
+		{Key: "max_connections", DesiredValue: "500", Applied: true},
+		{Key: "sql_mode", DesiredValue: "ANSI"},
+	}}
+
+	assert.Equal(t, map[string]string{"max_connections": "500"}, appliedVariableValues(&mysql.SetVariablesRequest{}, resp))
+	assert.Nil(t, appliedVariableValues(&sentinel.SetVariablesRequest{}, resp))
+}
+
+func TestIsBatchSetVariable(t *testing.T) {
+	instance := &upmv1alpha1.GrpcCall{Spec: upmv1alpha1.GrpcCallSpec{
+		Parameters: map[string]apiextensionsv1.JSON{"key": {Raw: []byte(`"max_connections"`)}},
+	}}
+	assert.False(t, isBatchSetVariable(instance))
+
+	instance.Spec.Parameters["variables"] = apiextensionsv1.JSON{Raw: []byte(`{"max_connections":"500"}`)}
+	assert.True(t, isBatchSetVariable(instance))
+
+	req := &mysql.SetVariablesRequest{}
+	assert.NoError(t, unmarshalParams(map[string]apiextensionsv1.JSON{
+		"variables": instance.Spec.Parameters["variables"],
+		"dryRun":    {Raw: []byte(`true`)},
+	}, req))
+	assert.Equal(t, map[string]string{"max_connections": "500"}, req.GetVariables())
+	assert.True(t, req.GetDryRun())
+}
+
+func TestSetVariablesMessage(t *testing.T) {
+	instance := &upmv1alpha1.GrpcCall{Spec: upmv1alpha1.GrpcCallSpec{
+		Type:   upmv1alpha1.PostgresqlType,
+		Action: upmv1alpha1.SetVariableAction,
+	}}
+	resp := &common.SetVariablesResponse{
+		DryRun: true,
+		Results: []*common.VariableResult{
+			common.NewVariableResult("shared_buffers", "128MB", "1GB"),
+			common.NewVariableResult("work_mem", "4MB", "4MB"),
+		},
+	}
+
+	assert.Equal(t, `dry run set-variable postgresql, 1 of 2 variables would change: shared_buffers: "128MB" -> "1GB"`,
+		setVariablesMessage(instance, resp))
+
+	resp.DryRun = false
+	resp.Results[0].RestartRequired = true
+	assert.Equal(t, "set-variable postgresql successfully, 1 of 2 variables changed, restart required",
+		setVariablesMessage(instance, resp))
+}
+
+func TestClusterHealthMessage(t *testing.T) {
+	resp := &rediscluster.ClusterHealthResponse{State: "ok", SlotsOk: 16384, KnownNodes: 6}
+	assert.Equal(t, "cluster state ok, 16384 of 16384 slots ok, 6 known nodes", clusterHealthMessage(resp))
+
+	resp.State = "fail"
+	resp.SlotsOk = 10923
+	resp.UncoveredSlots = []*rediscluster.SlotRange{{Start: 10923, End: 16383}}
+	resp.FailingNodes = []*rediscluster.ClusterNode{{Id: "c", Address: "10.0.0.3:6379"}}
+	resp.OpenSlots = []*rediscluster.MigratingSlot{{Slot: 5461, NodeId: "b"}}
+	assert.Equal(t, "cluster state fail, 10923 of 16384 slots ok, 6 known nodes, uncovered slots 10923-16383, "+
+		"failing nodes c (10.0.0.3:6379), 1 slots migrating", clusterHealthMessage(resp))
+}
+
+func TestSentinelInstancesMessage(t *testing.T) {
+	assert.Equal(t, "no masters", sentinelInstancesMessage("masters", nil))
+	assert.Equal(t, "2 masters: cache (10.0.0.1:6379 master), session (10.0.0.2:6379 master,o_down)",
+		sentinelInstancesMessage("masters", []*sentinel.SentinelInstance{
+			{Name: "cache", Host: "10.0.0.1", Port: 6379, Flags: []string{"master"}},
+			{Name: "session", Host: "10.0.0.2", Port: 6379, Flags: []string{"master", "o_down"}},
+		}))
+}
+
+func TestReplicaSetStatusMessage(t *testing.T) {
+	assert.Equal(t, "replica set demo is not initiated", replicaSetStatusMessage(&mongodb.ReplicaSetStatusResponse{Set: "demo"}))
+	assert.Equal(t, `replica set demo primary "a:27017", max lag 3s, members a:27017 PRIMARY, b:27017 SECONDARY`,
+		replicaSetStatusMessage(&mongodb.ReplicaSetStatusResponse{
+			Initiated:     true,
+			Set:           "demo",
+			Primary:       "a:27017",
+			MaxLagSeconds: 3,
+			Members: []*mongodb.MemberStatus{
+				{Host: "a:27017", State: "PRIMARY"},
+				{Host: "b:27017", State: "SECONDARY", LagSeconds: 3},
+			},
+		}))
+}
+
+func TestPersistConfigValue(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(s))
+	assert.NoError(t, upmv1alpha1.AddToScheme(s))
+	assert.NoError(t, upmv1alpha2.AddToScheme(s))
+
+	unit := &upmv1alpha2.Unit{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql-0", Namespace: "default"},
+		Spec: upmv1alpha2.UnitSpec{
+			ConfigValueName: "mysql-0-config-value",
+			Template: upmv1alpha2.UnitPodTemplateSpec{
+				Annotations: map[string]string{upmv1alpha2.AnnotationMainContainerName: "mysql"},
+				Spec:        corev1.PodSpec{Containers: []corev1.Container{{Name: "mysql"}}},
+			},
+		},
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql-0-config-value", Namespace: "default"},
+		Data:       map[string]string{unit.MainContainerName(): "max_connections: 100\nport: 3306\n"},
+	}
+
+	r := &ReconcileGrpcCall{client: fake.NewClientBuilder().WithScheme(s).WithObjects(unit, cm).Build()}
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{Name: "set", Namespace: "default"},
+		Spec:       upmv1alpha1.GrpcCallSpec{TargetUnit: "mysql-0"},
+	}
+
+	ctx := context.Background()
+	assert.NoError(t, r.persistConfigValues(ctx, instance, map[string]string{"MAX_CONNECTIONS": "500", "unmanaged": "1"}))
+
+	got := &corev1.ConfigMap{}
+	assert.NoError(t, r.client.Get(ctx, types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, got))
+	content := got.Data[unit.MainContainerName()]
+	assert.Contains(t, content, "max_connections: 500")
+	assert.NotContains(t, content, "unmanaged")
+}
+
+func TestMilvusBackupsMessage(t *testing.T) {
+	assert.Equal(t, "2 backups: b1, b2", milvusBackupsMessage([]*milvus.BackupInfo{{Name: "b1"}, {Name: "b2"}}))
+	assert.Equal(t, "1 backups: b1 success 4096 bytes created 2024-05-01T10:00:00Z [default.books,shop.items]",
+		milvusBackupsMessage([]*milvus.BackupInfo{{
+			Name:      "b1",
+			State:     "success",
+			Size:      4096,
+			StartTime: 1714557600000,
+			Collections: []*milvus.BackupCollection{
+				{Database: "default", Name: "books"},
+				{Database: "shop", Name: "items"},
+			},
+		}}))
+}
+
+func TestBackupOperationMessage(t *testing.T) {
+	assert.Equal(t, "", backupOperationMessage(&clickhouse.BackupOperation{}))
+	assert.Equal(t, ", b1 CREATING_BACKUP", backupOperationMessage(&clickhouse.BackupOperation{Id: "b1", Status: "CREATING_BACKUP"}))
+	assert.Equal(t, ", b1 BACKUP_CREATED, 12 files, 4096 bytes (4000 compressed)", backupOperationMessage(&clickhouse.BackupOperation{
+		Id: "b1", Status: "BACKUP_CREATED", NumFiles: 12, TotalSize: 4096, CompressedSize: 4000,
+	}))
+	assert.Equal(t, ", r1 RESTORING, read 3 files (1024 bytes)", backupOperationMessage(&clickhouse.BackupOperation{
+		Id: "r1", Status: "RESTORING", FilesRead: 3, BytesRead: 1024,
+	}))
+}
+
+// fakeMysqlAgent blocks GtidPurge until release is closed.
+type fakeMysqlAgent struct {
+	mysql.UnimplementedMysqlOperationServer
+	release chan struct{}
+}
+
+func (f *fakeMysqlAgent) GtidPurge(_ context.Context, _ *mysql.GtidPurgeRequest) (*common.Empty, error) {
+	<-f.release
+	return &common.Empty{}, nil
+}
+
+func (f *fakeMysqlAgent) LogicalBackup(_ context.Context, req *mysql.LogicalBackupRequest) (*common.BackupResponse, error) {
+	return &common.BackupResponse{Bucket: "backups", Object: req.GetBackupFile(), SizeBytes: 1048576, DurationMs: 1200}, nil
+}
+
+// startMysqlAgent serves the fake mysql agent, through the operation
+// interceptor and with the operation app when async is set.
+func startMysqlAgent(t *testing.T, agent *fakeMysqlAgent, async bool) *Client {
+	var opts []grpc.ServerOption
+	operations := app.GetGrpcApp("operation")
+	if async {
+		require.NoError(t, operations.Config())
+		opts = append(opts, grpc.UnaryInterceptor(operation.UnaryServerInterceptor()))
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(opts...)
+	mysql.RegisterMysqlOperationServer(server, agent)
+	operations.Registry(server)
+	reflection.Register(server)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return &Client{conn: conn}
+}
+
+func TestHandleGrpcCallPollsAgentOperation(t *testing.T) {
+	agent := &fakeMysqlAgent{release: make(chan struct{})}
+	c := startMysqlAgent(t, agent, true)
+
+	r := &ReconcileGrpcCall{}
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{Name: "purge", Namespace: "default", UID: "6f1c2a8e-purge"},
+		Spec: upmv1alpha1.GrpcCallSpec{TargetUnit: "mysql-0", Type: upmv1alpha1.MysqlType, Action: upmv1alpha1.GtidPurgeAction,
+			Parameters: map[string]apiextensionsv1.JSON{}},
+	}
+
+	ctx := context.Background()
+	finished, err := r.handleGrpcCall(ctx, instance, c, "6f1c2a8e-purge")
+	require.NoError(t, err)
+	require.False(t, finished)
+	require.Equal(t, "6f1c2a8e-purge", instance.Status.OperationID)
+
+	// a retried start joins the running operation
+	finished, err = r.handleGrpcCall(ctx, instance, c, "6f1c2a8e-purge")
+	require.NoError(t, err)
+	require.False(t, finished)
+
+	finished, err = r.pollOperation(ctx, instance, c)
+	require.NoError(t, err)
+	require.False(t, finished)
+	require.Contains(t, instance.Status.Message, "gtid-purge mysql operation 6f1c2a8e-purge")
+
+	close(agent.release)
+	require.Eventually(t, func() bool {
+		finished, err = r.pollOperation(ctx, instance, c)
+		return finished
+	}, 5*time.Second, 20*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, upmv1alpha1.SuccessResult, instance.Status.Result)
+	require.Equal(t, "gtid-purge mysql successfully", instance.Status.Message)
+
+	instance.Status.OperationID = "missing"
+	finished, err = r.pollOperation(ctx, instance, c)
+	require.True(t, finished)
+	require.EqualError(t, err, "operation missing not found on unit agent")
+}
+
+func TestHandleGrpcCallRunsSynchronouslyOnAgentsWithoutOperations(t *testing.T) {
+	agent := &fakeMysqlAgent{release: make(chan struct{})}
+	close(agent.release)
+	c := startMysqlAgent(t, agent, false)
+
+	r := &ReconcileGrpcCall{}
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{Name: "purge", Namespace: "default", UID: "6f1c2a8e-purge"},
+		Spec: upmv1alpha1.GrpcCallSpec{TargetUnit: "mysql-0", Type: upmv1alpha1.MysqlType, Action: upmv1alpha1.GtidPurgeAction,
+			Parameters: map[string]apiextensionsv1.JSON{}},
+	}
+
+	finished, err := r.handleGrpcCall(context.Background(), instance, c, "6f1c2a8e-purge")
+	require.NoError(t, err)
+	require.True(t, finished)
+	require.Empty(t, instance.Status.OperationID)
+	require.Equal(t, upmv1alpha1.SuccessResult, instance.Status.Result)
+}
+
+func TestHandleGrpcCallRecordsOutputsOfAgentOperation(t *testing.T) {
+	c := startMysqlAgent(t, &fakeMysqlAgent{}, true)
+
+	r := &ReconcileGrpcCall{}
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default", UID: "0b7d4e1a-backup"},
+		Spec: upmv1alpha1.GrpcCallSpec{TargetUnit: "mysql-0", Type: upmv1alpha1.MysqlType, Action: upmv1alpha1.LogicalBackupAction,
+			Parameters: map[string]apiextensionsv1.JSON{"backup_file": {Raw: []byte(`"mysql-0/full.sql"`)}}},
+	}
+
+	ctx := context.Background()
+	finished, err := r.handleGrpcCall(ctx, instance, c, "0b7d4e1a-backup")
+	require.NoError(t, err)
+	require.False(t, finished)
+
+	require.Eventually(t, func() bool {
+		finished, err = r.pollOperation(ctx, instance, c)
+		return finished
+	}, 5*time.Second, 20*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, "logical-backup mysql successfully, wrote 1048576 bytes to mysql-0/full.sql", instance.Status.Message)
+	require.Equal(t, map[string]apiextensionsv1.JSON{
+		"bucket":      {Raw: []byte(`"backups"`)},
+		"object":      {Raw: []byte(`"mysql-0/full.sql"`)},
+		"size_bytes":  {Raw: []byte(`1048576`)},
+		"duration_ms": {Raw: []byte(`1200`)},
+	}, instance.Status.Outputs)
+}
+
+func TestHandleGrpcCallResponseDescribesChange(t *testing.T) {
+	r := &ReconcileGrpcCall{}
+	instance := &upmv1alpha1.GrpcCall{
+		Spec: upmv1alpha1.GrpcCallSpec{Type: upmv1alpha1.MysqlType, Action: upmv1alpha1.SetVariableAction},
+	}
+
+	resp := common.NewSetVariableResponse("151", "500")
+	require.NoError(t, r.handleGrpcCallResponse(context.Background(), instance, &mysql.SetVariableRequest{}, resp))
+	require.Equal(t, `set-variable mysql successfully, changed from "151" to "500"`, instance.Status.Message)
+	require.JSONEq(t, `"151"`, string(instance.Status.Outputs["previous_value"].Raw))
+	require.JSONEq(t, `"500"`, string(instance.Status.Outputs["current_value"].Raw))
+
+	instance.Spec.Action = upmv1alpha1.CloneAction
+	clone := &mysql.CloneResponse{Source: "mysql-0:3306", SizeBytes: 2048, DurationMs: 900, Position: "uuid:1-5"}
+	require.NoError(t, r.handleGrpcCallResponse(context.Background(), instance, &mysql.CloneRequest{}, clone))
+	require.Equal(t, "clone mysql successfully, cloned 2048 bytes from mysql-0:3306 up to uuid:1-5", instance.Status.Message)
+	require.JSONEq(t, `2048`, string(instance.Status.Outputs["size_bytes"].Raw))
+}
+
+func TestResponseOutputs(t *testing.T) {
+	outputs, err := responseOutputs(&common.Empty{})
+	require.NoError(t, err)
+	require.Nil(t, outputs)
+
+	outputs, err = responseOutputs(nil)
+	require.NoError(t, err)
+	require.Nil(t, outputs)
+
+	outputs, err = responseOutputs(&common.SetVariablesResponse{
+		Persisted: true,
+		Results:   []*common.VariableResult{{Key: "max_connections", CurrentValue: "151", DesiredValue: "500", Changed: true, Applied: true}},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `true`, string(outputs["persisted"].Raw))
+	require.JSONEq(t, `[{"key":"max_connections","current_value":"151","desired_value":"500","changed":true,"applied":true}]`,
+		string(outputs["results"].Raw))
+	require.NotContains(t, outputs, "dry_run")
+}
@@ -2,8 +2,6 @@ package grpccall
 
 import (
 	"fmt"
-	"strconv"
-	"time"
 
 	"github.com/go-logr/logr"
 	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
@@ -12,17 +10,18 @@ import (
 	"github.com/upmio/unit-operator/pkg/agent/app/milvus"
 	"github.com/upmio/unit-operator/pkg/agent/app/mongodb"
 	"github.com/upmio/unit-operator/pkg/agent/app/mysql"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
 	"github.com/upmio/unit-operator/pkg/agent/app/postgresql"
 	"github.com/upmio/unit-operator/pkg/agent/app/proxysql"
 	"github.com/upmio/unit-operator/pkg/agent/app/redis"
+	"github.com/upmio/unit-operator/pkg/agent/app/rediscluster"
 	"github.com/upmio/unit-operator/pkg/agent/app/sentinel"
-	"google.golang.org/grpc/credentials/insecure"
-	corev1 "k8s.io/api/core/v1"
+	"github.com/upmio/unit-operator/pkg/agent/app/slm"
+	unitAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"context"
-	"net"
 
 	"google.golang.org/grpc"
 )
@@ -33,16 +32,7 @@ type Client struct {
 
 // newGrpcClient builds grpc client to call unit agent interface
 func newGrpcClient(host, port string) (*Client, error) {
-	addr := net.JoinHostPort(host, port)
-
-	conn, err := grpc.NewClient(
-		addr,
-		//grpc.WithTransportCredentials(creds),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithConnectParams(grpc.ConnectParams{
-			MinConnectTimeout: 5 * time.Second,
-		}),
-	)
+	conn, err := unitAgent.NewConn(host, port)
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +66,11 @@ func (c *Client) Redis() redis.RedisOperationClient {
 	return redis.NewRedisOperationClient(c.conn)
 }
 
+// RedisCluster sdk
+func (c *Client) RedisCluster() rediscluster.RedisClusterOperationClient {
+	return rediscluster.NewRedisClusterOperationClient(c.conn)
+}
+
 // Sentinel sdk
 func (c *Client) Sentinel() sentinel.SentinelOperationClient {
 	return sentinel.NewSentinelOperationClient(c.conn)
@@ -96,6 +91,16 @@ func (c *Client) ClickHouse() clickhouse.ClickHouseOperationClient {
 	return clickhouse.NewClickHouseOperationClient(c.conn)
 }
 
+// ServiceLifecycle sdk
+func (c *Client) ServiceLifecycle() slm.ServiceLifecycleClient {
+	return slm.NewServiceLifecycleClient(c.conn)
+}
+
+// Operation sdk
+func (c *Client) Operation() operation.OperationsClient {
+	return operation.NewOperationsClient(c.conn)
+}
+
 // gatherUnitAgentEndpoint retrieves and returns the host and port for the unit-agent container.
 func gatherUnitAgentEndpoint(
 	ctx context.Context,
@@ -106,43 +111,13 @@ func gatherUnitAgentEndpoint(
 	// 1. Retrieve the Unit object
 	unit := &upmv1alpha2.Unit{}
 	key := types.NamespacedName{
-		Name:      instance.Spec.TargetUnit,
+		Name:      targetUnitName(instance),
 		Namespace: instance.Namespace,
 	}
 	if err := client.Get(ctx, key, unit); err != nil {
 		return "", "", fmt.Errorf("failed to fetch unit [%s]: %v", key, err)
 	}
 
-	// 2. Construct the host DNS name
-	host := fmt.Sprintf("%s.%s.%s.svc", unit.Name, upmv1alpha2.UnitsetHeadlessSvcName(unit), unit.Namespace)
-
-	// 3. Find the container named "unit-agent"
-	var agent *corev1.Container
-	for i := range unit.Spec.Template.Spec.Containers {
-		c := &unit.Spec.Template.Spec.Containers[i]
-		if c.Name == agentName {
-			agent = c
-			break
-		}
-	}
-	if agent == nil {
-		return "", "", fmt.Errorf("container %q not found in unit %q", "unit-agent", key)
-	}
-
-	// 4. Locate the port named "unit-agent" within the container
-	var agentPort *corev1.ContainerPort
-	for i := range agent.Ports {
-		p := &agent.Ports[i]
-		if p.Name == agentName {
-			agentPort = p
-			break
-		}
-	}
-	if agentPort == nil {
-		return "", "", fmt.Errorf("port %s not found in container", agentName)
-	}
-
-	// 5. Return the host and port as strings
-	port := strconv.Itoa(int(agentPort.ContainerPort))
-	return host, port, nil
+	// 2. Return the host and port of its unit-agent container
+	return unitAgent.Endpoint(unit)
 }
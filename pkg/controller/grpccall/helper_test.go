@@ -100,6 +100,9 @@ func TestClient_ServiceClients(t *testing.T) {
 
 	clickHouseClient := client.ClickHouse()
 	assert.NotNil(t, clickHouseClient)
+
+	redisClusterClient := client.RedisCluster()
+	assert.NotNil(t, redisClusterClient)
 }
 
 func TestGatherUnitAgentEndpoint_Success(t *testing.T) {
@@ -0,0 +1,94 @@
+package grpccall
+
+import (
+	"context"
+	"fmt"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/slm"
+	"google.golang.org/grpc/codes"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// lifecycleMethods maps the lifecycle actions to the ServiceLifecycle method
+// they call, which the unit-agent of every unit type serves.
+var lifecycleMethods = map[upmv1alpha1.Action]string{
+	upmv1alpha1.StartAction:   "service.ServiceLifecycle/StartProcess",
+	upmv1alpha1.StopAction:    "service.ServiceLifecycle/StopProcess",
+	upmv1alpha1.RestartAction: "service.ServiceLifecycle/RestartProcess",
+	upmv1alpha1.StatusAction:  "service.ServiceLifecycle/ProcessStatus",
+}
+
+// holdProcessState records on the target unit that a stop action stopped its
+// process, so that the unit controller does not start it again, and clears
+// the record before a start or restart action. The record is kept when the
+// stop fails, until a start or restart action. A start or restart of a unit
+// whose spec.startup is false fails, as the unit controller would stop the
+// process again.
+func (r *ReconcileGrpcCall) holdProcessState(ctx context.Context, instance *upmv1alpha1.GrpcCall, method string) error {
+	var action upmv1alpha1.Action
+	switch method {
+	case lifecycleMethods[upmv1alpha1.StopAction]:
+		action = upmv1alpha1.StopAction
+	case lifecycleMethods[upmv1alpha1.StartAction], lifecycleMethods[upmv1alpha1.RestartAction]:
+		action = upmv1alpha1.StartAction
+	default:
+		return nil
+	}
+
+	name := targetUnitName(instance)
+
+	unit := &upmv1alpha2.Unit{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, unit); err != nil {
+		return fmt.Errorf("failed to fetch unit [%s]: %v", name, err)
+	}
+
+	stoppedBy, stopped := unit.Annotations[upmv1alpha2.AnnotationProcessStoppedBy]
+	patch := client.MergeFrom(unit.DeepCopy())
+
+	switch action {
+	case upmv1alpha1.StopAction:
+		if stoppedBy == instance.Name {
+			return nil
+		}
+		if unit.Annotations == nil {
+			unit.Annotations = make(map[string]string)
+		}
+		unit.Annotations[upmv1alpha2.AnnotationProcessStoppedBy] = instance.Name
+	case upmv1alpha1.StartAction:
+		if !unit.Spec.Startup {
+			return &codeError{
+				code: codes.FailedPrecondition,
+				err:  fmt.Errorf("unit [%s] spec.startup is false, the unit controller would stop the process again", name),
+			}
+		}
+		if !stopped {
+			return nil
+		}
+		delete(unit.Annotations, upmv1alpha2.AnnotationProcessStoppedBy)
+	}
+
+	if err := r.client.Patch(ctx, unit, patch); err != nil {
+		return fmt.Errorf("failed to patch unit [%s]: %v", name, err)
+	}
+
+	return nil
+}
+
+// processStatusMessage reports the state of the process of a unit.
+func processStatusMessage(instance *upmv1alpha1.GrpcCall, resp *slm.ProcessStatusResponse) string {
+	msg := fmt.Sprintf("%s process %s", instance.Spec.Type, resp.GetState())
+	if resp.GetPid() > 0 {
+		msg += fmt.Sprintf(", pid %d, up %ds", resp.GetPid(), resp.GetUptimeSeconds())
+	}
+	if resp.GetExitStatus() != 0 {
+		msg += fmt.Sprintf(", exit status %d", resp.GetExitStatus())
+	}
+	if resp.GetSpawnError() != "" {
+		msg += ", spawn error: " + resp.GetSpawnError()
+	}
+
+	return msg
+}
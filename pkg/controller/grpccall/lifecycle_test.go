@@ -0,0 +1,97 @@
+package grpccall
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/slm"
+)
+
+func newLifecycleGrpcCall(name string, unitType upmv1alpha1.UnitType, action upmv1alpha1.Action) *upmv1alpha1.GrpcCall {
+	return &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: upmv1alpha1.GrpcCallSpec{
+			TargetUnit: "mysql-0",
+			Type:       unitType,
+			Action:     action,
+		},
+	}
+}
+
+func TestLifecycleMethodsForEveryType(t *testing.T) {
+	for _, unitType := range []upmv1alpha1.UnitType{
+		upmv1alpha1.MysqlType, upmv1alpha1.PostgresqlType, upmv1alpha1.ProxysqlType, upmv1alpha1.RedisType,
+		upmv1alpha1.RedisClusterType, upmv1alpha1.SentinelType, upmv1alpha1.MongoDBType,
+		upmv1alpha1.MilvusType, upmv1alpha1.ClickHouseType,
+	} {
+		for action, want := range map[upmv1alpha1.Action]interface{}{
+			upmv1alpha1.StartAction:   &common.Empty{},
+			upmv1alpha1.StopAction:    &slm.StopProcessRequest{},
+			upmv1alpha1.RestartAction: &slm.RestartProcessRequest{},
+			upmv1alpha1.StatusAction:  &common.Empty{},
+		} {
+			method, err := resolveMethod(context.Background(), newLifecycleGrpcCall("call", unitType, action), &Client{})
+			require.NoError(t, err, "%s %s", action, unitType)
+			req, err := method.newRequest(nil)
+			require.NoError(t, err)
+			assert.IsType(t, want, req, "%s %s", action, unitType)
+		}
+	}
+}
+
+func TestStopParameters(t *testing.T) {
+	req := &slm.StopProcessRequest{}
+	require.NoError(t, unmarshalParams(map[string]apiextensionsv1.JSON{"gracefulTimeoutSeconds": {Raw: []byte(`120`)}}, req))
+	assert.Equal(t, int32(120), req.GetGracefulTimeoutSeconds())
+}
+
+func TestHoldProcessState(t *testing.T) {
+	unit := &upmv1alpha2.Unit{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql-0", Namespace: "default"},
+		Spec:       upmv1alpha2.UnitSpec{Startup: true},
+	}
+	c := fake.NewClientBuilder().WithScheme(newTargetTestScheme(t)).WithObjects(unit).Build()
+	r := &ReconcileGrpcCall{client: c}
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "mysql-0", Namespace: "default"}
+
+	require.NoError(t, r.holdProcessState(ctx, newLifecycleGrpcCall("stop-mysql", upmv1alpha1.MysqlType, upmv1alpha1.StopAction), lifecycleMethods[upmv1alpha1.StopAction]))
+	require.NoError(t, c.Get(ctx, key, unit))
+	assert.Equal(t, "stop-mysql", unit.Annotations[upmv1alpha2.AnnotationProcessStoppedBy])
+
+	// a status action leaves the unit alone
+	require.NoError(t, r.holdProcessState(ctx, newLifecycleGrpcCall("status-mysql", upmv1alpha1.MysqlType, upmv1alpha1.StatusAction), lifecycleMethods[upmv1alpha1.StatusAction]))
+	require.NoError(t, c.Get(ctx, key, unit))
+	assert.Contains(t, unit.Annotations, upmv1alpha2.AnnotationProcessStoppedBy)
+
+	require.NoError(t, r.holdProcessState(ctx, newLifecycleGrpcCall("start-mysql", upmv1alpha1.MysqlType, upmv1alpha1.StartAction), lifecycleMethods[upmv1alpha1.StartAction]))
+	require.NoError(t, c.Get(ctx, key, unit))
+	assert.NotContains(t, unit.Annotations, upmv1alpha2.AnnotationProcessStoppedBy)
+
+	unit.Spec.Startup = false
+	require.NoError(t, c.Update(ctx, unit))
+	err := r.holdProcessState(ctx, newLifecycleGrpcCall("restart-mysql", upmv1alpha1.MysqlType, upmv1alpha1.RestartAction), lifecycleMethods[upmv1alpha1.RestartAction])
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestProcessStatusMessage(t *testing.T) {
+	instance := newLifecycleGrpcCall("status-mysql", upmv1alpha1.MysqlType, upmv1alpha1.StatusAction)
+
+	assert.Equal(t, "mysql process RUNNING, pid 42, up 60s",
+		processStatusMessage(instance, &slm.ProcessStatusResponse{State: "RUNNING", Pid: 42, UptimeSeconds: 60}))
+	assert.Equal(t, "mysql process FATAL, exit status 1, spawn error: Exited too quickly",
+		processStatusMessage(instance, &slm.ProcessStatusResponse{State: "FATAL", ExitStatus: 1, SpawnError: "Exited too quickly"}))
+}
@@ -0,0 +1,237 @@
+package grpccall
+
+import (
+	"fmt"
+	"time"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	defaultMaxAttempts       = 3
+	defaultBackoffSeconds    = 10
+	defaultMaxBackoffSeconds = 300
+)
+
+// defaultRetryableCodes are retried when the retry policy lists no codes
+var defaultRetryableCodes = []codes.Code{codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted}
+
+// codeError is an error carrying the gRPC code its retry is decided by.
+type codeError struct {
+	code codes.Code
+	err  error
+}
+
+func (e *codeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codeError) Unwrap() error {
+	return e.err
+}
+
+func (e *codeError) GRPCStatus() *status.Status {
+	return status.New(e.code, e.err.Error())
+}
+
+func isFinishedPhase(phase upmv1alpha1.GrpcCallPhase) bool {
+	switch phase {
+	case upmv1alpha1.GrpcCallSucceeded, upmv1alpha1.GrpcCallFailed, upmv1alpha1.GrpcCallCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// startAttempt records a new attempt and moves the call to Running.
+func startAttempt(instance *upmv1alpha1.GrpcCall) {
+	now := metav1.Now()
+	instance.Status.Attempts = append(instance.Status.Attempts, upmv1alpha1.GrpcCallAttempt{
+		Attempt:   int32(len(instance.Status.Attempts) + 1),
+		StartTime: now,
+	})
+	instance.Status.Phase = upmv1alpha1.GrpcCallRunning
+	instance.Status.OperationID = ""
+	instance.Status.NextAttemptTime = nil
+	if instance.Spec.TargetUnitSet != nil {
+		instance.Status.Unit = ""
+	}
+	if instance.Status.StartTime == nil {
+		instance.Status.StartTime = &now
+	}
+}
+
+// currentAttempt returns the running attempt, recording the first one of a
+// call started before attempts were recorded.
+func currentAttempt(instance *upmv1alpha1.GrpcCall) *upmv1alpha1.GrpcCallAttempt {
+	if len(instance.Status.Attempts) == 0 {
+		startTime := metav1.Now()
+		if instance.Status.StartTime != nil {
+			startTime = *instance.Status.StartTime
+		}
+
+		instance.Status.Attempts = append(instance.Status.Attempts, upmv1alpha1.GrpcCallAttempt{
+			Attempt:     1,
+			OperationID: instance.Status.OperationID,
+			StartTime:   startTime,
+		})
+	}
+
+	return &instance.Status.Attempts[len(instance.Status.Attempts)-1]
+}
+
+// completeAttempt records the end of the running attempt, if any.
+func completeAttempt(instance *upmv1alpha1.GrpcCall, code codes.Code, message string) {
+	if len(instance.Status.Attempts) == 0 {
+		return
+	}
+
+	attempt := &instance.Status.Attempts[len(instance.Status.Attempts)-1]
+	if attempt.CompletionTime != nil {
+		return
+	}
+
+	now := metav1.Now()
+	attempt.CompletionTime = &now
+	attempt.Message = message
+	if code != codes.OK {
+		attempt.Code = upmv1alpha1.GrpcCode(code.String())
+	}
+}
+
+// failAttempt records the failure of the running attempt and schedules the
+// next one when the code is retryable, there are attempts left and the next
+// one starts before the active deadline. Otherwise the call finishes with result.
+func (r *ReconcileGrpcCall) failAttempt(
+	instance *upmv1alpha1.GrpcCall,
+	code codes.Code,
+	result upmv1alpha1.Result,
+	message string,
+) ctrl.Result {
+	completeAttempt(instance, code, message)
+
+	attempts := int32(len(instance.Status.Attempts))
+	maxAttempts := maxAttempts(instance.Spec.RetryPolicy)
+	if isRetryable(instance.Spec.RetryPolicy, code) && attempts < maxAttempts {
+		backoff := retryBackoff(instance.Spec.RetryPolicy, attempts)
+		next := metav1.NewTime(time.Now().Add(backoff))
+
+		if deadline, ok := activeDeadline(instance); !ok || next.Before(&metav1.Time{Time: deadline}) {
+			instance.Status.Phase = upmv1alpha1.GrpcCallPending
+			instance.Status.OperationID = ""
+			instance.Status.NextAttemptTime = &next
+			instance.Status.Message = fmt.Sprintf("attempt %d of %d failed, retrying in %s: %s", attempts, maxAttempts, backoff, message)
+			r.recorder.Event(instance, corev1.EventTypeWarning, "OperationRetrying", instance.Status.Message)
+
+			return reconcile.Result{RequeueAfter: backoff}
+		}
+	}
+
+	r.finishGrpcCall(instance, result, message)
+
+	return r.requeueFinished(instance)
+}
+
+// attemptOperationID is the unit-agent operation id of an attempt. Retrying
+// a start of the same attempt joins its operation, a new attempt gets its own.
+func attemptOperationID(instance *upmv1alpha1.GrpcCall, attempt int32) string {
+	if attempt <= 1 {
+		return string(instance.UID)
+	}
+
+	return fmt.Sprintf("%s-%d", instance.UID, attempt)
+}
+
+// attemptDeadline returns when the attempt times out.
+func attemptDeadline(instance *upmv1alpha1.GrpcCall, attempt *upmv1alpha1.GrpcCallAttempt) (time.Time, bool) {
+	if instance.Spec.TimeoutSeconds == nil {
+		return time.Time{}, false
+	}
+
+	return attempt.StartTime.Add(time.Duration(*instance.Spec.TimeoutSeconds) * time.Second), true
+}
+
+// activeDeadline returns when the call times out across all attempts.
+func activeDeadline(instance *upmv1alpha1.GrpcCall) (time.Time, bool) {
+	if instance.Spec.ActiveDeadlineSeconds == nil || instance.Status.StartTime == nil {
+		return time.Time{}, false
+	}
+
+	return instance.Status.StartTime.Add(time.Duration(*instance.Spec.ActiveDeadlineSeconds) * time.Second), true
+}
+
+func maxAttempts(policy *upmv1alpha1.RetryPolicy) int32 {
+	switch {
+	case policy == nil:
+		return 1
+	case policy.MaxAttempts <= 0:
+		return defaultMaxAttempts
+	default:
+		return policy.MaxAttempts
+	}
+}
+
+func isRetryable(policy *upmv1alpha1.RetryPolicy, code codes.Code) bool {
+	if policy == nil {
+		return false
+	}
+
+	if len(policy.RetryableCodes) == 0 {
+		for _, retryable := range defaultRetryableCodes {
+			if code == retryable {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, retryable := range policy.RetryableCodes {
+		if string(retryable) == code.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// retryBackoff returns the delay after the given number of failed attempts,
+// doubling from BackoffSeconds up to MaxBackoffSeconds.
+func retryBackoff(policy *upmv1alpha1.RetryPolicy, failed int32) time.Duration {
+	backoff, maxBackoff := int64(defaultBackoffSeconds), int64(defaultMaxBackoffSeconds)
+	if policy != nil && policy.BackoffSeconds > 0 {
+		backoff = int64(policy.BackoffSeconds)
+	}
+	if policy != nil && policy.MaxBackoffSeconds > 0 {
+		maxBackoff = int64(policy.MaxBackoffSeconds)
+	}
+
+	for i := int32(1); i < failed && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	return time.Duration(backoff) * time.Second
+}
+
+// operationPollDelay returns the delay before polling an operation running
+// for the given time, doubling from operationPollInitialInterval so that a
+// short operation is noticed soon and a long one is not polled needlessly.
+func operationPollDelay(running time.Duration) time.Duration {
+	delay := operationPollInitialInterval
+	for delay < running && delay < operationPollMaxInterval {
+		delay *= 2
+	}
+	if delay > operationPollMaxInterval {
+		delay = operationPollMaxInterval
+	}
+
+	return delay
+}
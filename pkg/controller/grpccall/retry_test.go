@@ -0,0 +1,213 @@
+package grpccall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, retryBackoff(nil, 1))
+	assert.Equal(t, 40*time.Second, retryBackoff(nil, 3))
+	assert.Equal(t, 300*time.Second, retryBackoff(nil, 20))
+
+	policy := &upmv1alpha1.RetryPolicy{BackoffSeconds: 5, MaxBackoffSeconds: 15}
+	assert.Equal(t, 5*time.Second, retryBackoff(policy, 1))
+	assert.Equal(t, 10*time.Second, retryBackoff(policy, 2))
+	assert.Equal(t, 15*time.Second, retryBackoff(policy, 3))
+}
+
+func TestOperationPollDelay(t *testing.T) {
+	assert.Equal(t, time.Second, operationPollDelay(0))
+	assert.Equal(t, time.Second, operationPollDelay(time.Second))
+	assert.Equal(t, 2*time.Second, operationPollDelay(1500*time.Millisecond))
+	assert.Equal(t, 8*time.Second, operationPollDelay(5*time.Second))
+	assert.Equal(t, 10*time.Second, operationPollDelay(time.Hour))
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.False(t, isRetryable(nil, codes.Unavailable))
+	assert.Equal(t, int32(1), maxAttempts(nil))
+
+	policy := &upmv1alpha1.RetryPolicy{}
+	assert.Equal(t, int32(defaultMaxAttempts), maxAttempts(policy))
+	assert.True(t, isRetryable(policy, codes.Unavailable))
+	assert.True(t, isRetryable(policy, codes.Aborted))
+	assert.False(t, isRetryable(policy, codes.InvalidArgument))
+	assert.False(t, isRetryable(policy, codes.Unknown))
+
+	policy.RetryableCodes = []upmv1alpha1.GrpcCode{"Unknown"}
+	assert.True(t, isRetryable(policy, codes.Unknown))
+	assert.False(t, isRetryable(policy, codes.Unavailable))
+}
+
+func TestCodeError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &codeError{code: codes.Unavailable, err: errors.New("pod restarting")})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, "wrapped: pod restarting", err.Error())
+}
+
+func TestAttemptOperationID(t *testing.T) {
+	instance := &upmv1alpha1.GrpcCall{ObjectMeta: metav1.ObjectMeta{UID: "uid"}}
+	assert.Equal(t, "uid", attemptOperationID(instance, 1))
+	assert.Equal(t, "uid-3", attemptOperationID(instance, 3))
+}
+
+func TestFailAttemptRetriesUntilMaxAttempts(t *testing.T) {
+	r := &ReconcileGrpcCall{recorder: record.NewFakeRecorder(10)}
+
+	instance := &upmv1alpha1.GrpcCall{
+		Spec: upmv1alpha1.GrpcCallSpec{
+			RetryPolicy: &upmv1alpha1.RetryPolicy{MaxAttempts: 2, BackoffSeconds: 30},
+		},
+	}
+
+	startAttempt(instance)
+	instance.Status.OperationID = "uid"
+	result := r.failAttempt(instance, codes.Unavailable, upmv1alpha1.FailedResult, "unit agent unavailable")
+
+	assert.Equal(t, reconcile.Result{RequeueAfter: 30 * time.Second}, result)
+	assert.Equal(t, upmv1alpha1.GrpcCallPending, instance.Status.Phase)
+	assert.Empty(t, instance.Status.OperationID)
+	assert.NotNil(t, instance.Status.NextAttemptTime)
+	assert.Equal(t, "attempt 1 of 2 failed, retrying in 30s: unit agent unavailable", instance.Status.Message)
+	assert.Equal(t, upmv1alpha1.GrpcCode("Unavailable"), instance.Status.Attempts[0].Code)
+	assert.NotNil(t, instance.Status.Attempts[0].CompletionTime)
+
+	startAttempt(instance)
+	assert.Equal(t, int32(2), currentAttempt(instance).Attempt)
+	assert.Nil(t, instance.Status.NextAttemptTime)
+
+	result = r.failAttempt(instance, codes.Unavailable, upmv1alpha1.FailedResult, "unit agent unavailable")
+
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Equal(t, upmv1alpha1.GrpcCallFailed, instance.Status.Phase)
+	assert.Equal(t, upmv1alpha1.FailedResult, instance.Status.Result)
+	assert.Len(t, instance.Status.Attempts, 2)
+}
+
+func TestFailAttemptDoesNotRetryPastActiveDeadline(t *testing.T) {
+	r := &ReconcileGrpcCall{recorder: record.NewFakeRecorder(10)}
+
+	deadline := int64(20)
+	instance := &upmv1alpha1.GrpcCall{
+		Spec: upmv1alpha1.GrpcCallSpec{
+			ActiveDeadlineSeconds: &deadline,
+			RetryPolicy:           &upmv1alpha1.RetryPolicy{MaxAttempts: 5, BackoffSeconds: 30},
+		},
+	}
+
+	startAttempt(instance)
+	r.failAttempt(instance, codes.DeadlineExceeded, upmv1alpha1.TimedOutResult, "timed out after 10 seconds")
+
+	assert.Equal(t, upmv1alpha1.GrpcCallFailed, instance.Status.Phase)
+	assert.Equal(t, upmv1alpha1.TimedOutResult, instance.Status.Result)
+}
+
+func TestReconcileGrpcCall_Reconcile_Suspended(t *testing.T) {
+	mockClient := &MockClient{}
+	mockStatusWriter := &MockStatusWriter{}
+
+	reconciler := &ReconcileGrpcCall{
+		client:   mockClient,
+		scheme:   runtime.NewScheme(),
+		recorder: record.NewFakeRecorder(10),
+		logger:   zap.New().WithName("test"),
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-grpccall", Namespace: "default"}}
+
+	startTime := metav1.Now()
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-grpccall",
+			Namespace:  "default",
+			Finalizers: []string{FinalizerGrpcCall},
+		},
+		Spec: upmv1alpha1.GrpcCallSpec{TargetUnit: "mysql-0", Suspend: true},
+		Status: upmv1alpha1.GrpcCallStatus{
+			Phase:       upmv1alpha1.GrpcCallRunning,
+			StartTime:   &startTime,
+			OperationID: "op-1",
+			Attempts:    []upmv1alpha1.GrpcCallAttempt{{Attempt: 1, OperationID: "op-1", StartTime: startTime}},
+		},
+	}
+
+	mockClient.On("Get", mock.Anything, req.NamespacedName, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*upmv1alpha1.GrpcCall) = *instance
+		}).Return(nil)
+	// the unit agent is gone, the call is cancelled regardless
+	mockClient.On("Get", mock.Anything, types.NamespacedName{Name: "mysql-0", Namespace: "default"}, mock.AnythingOfType("*v1alpha2.Unit"), mock.Anything).
+		Return(fmt.Errorf("connection refused"))
+	mockClient.On("Status").Return(mockStatusWriter)
+
+	var updated *upmv1alpha1.GrpcCall
+	mockStatusWriter.On("Update", mock.Anything, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated = args.Get(1).(*upmv1alpha1.GrpcCall).DeepCopy()
+		}).Return(nil)
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, upmv1alpha1.GrpcCallCancelled, updated.Status.Phase)
+	assert.Equal(t, upmv1alpha1.CancelledResult, updated.Status.Result)
+	assert.Equal(t, upmv1alpha1.GrpcCode("Canceled"), updated.Status.Attempts[0].Code)
+}
+
+func TestReconcileGrpcCall_Reconcile_DeletedRemovesFinalizer(t *testing.T) {
+	mockClient := &MockClient{}
+
+	reconciler := &ReconcileGrpcCall{
+		client:   mockClient,
+		scheme:   runtime.NewScheme(),
+		recorder: record.NewFakeRecorder(10),
+		logger:   zap.New().WithName("test"),
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-grpccall", Namespace: "default"}}
+
+	now := metav1.Now()
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-grpccall",
+			Namespace:         "default",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{FinalizerGrpcCall},
+		},
+		Spec:   upmv1alpha1.GrpcCallSpec{TargetUnit: "mysql-0"},
+		Status: upmv1alpha1.GrpcCallStatus{Phase: upmv1alpha1.GrpcCallRunning, OperationID: "op-1"},
+	}
+
+	mockClient.On("Get", mock.Anything, req.NamespacedName, mock.AnythingOfType("*v1alpha1.GrpcCall"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*upmv1alpha1.GrpcCall) = *instance
+		}).Return(nil)
+	mockClient.On("Get", mock.Anything, types.NamespacedName{Name: "mysql-0", Namespace: "default"}, mock.AnythingOfType("*v1alpha2.Unit"), mock.Anything).
+		Return(fmt.Errorf("connection refused"))
+	mockClient.On("Update", mock.Anything, mock.MatchedBy(func(obj *upmv1alpha1.GrpcCall) bool {
+		return len(obj.Finalizers) == 0
+	}), mock.Anything).Return(nil)
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	mockClient.AssertExpectations(t)
+}
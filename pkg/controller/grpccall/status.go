@@ -5,6 +5,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/upmio/compose-operator/pkg/utils"
 	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/klog/v2"
 )
 
@@ -22,6 +23,11 @@ func (r *ReconcileGrpcCall) updateInstanceIfNeed(instance *upmv1alpha1.GrpcCall,
 }
 
 func compareStatus(new, old *upmv1alpha1.GrpcCallStatus, reqLogger logr.Logger) bool {
+	if utils.CompareStringValue("Phase", string(old.Phase), string(new.Phase), reqLogger) {
+		klog.Infof("found status.Phase changed: the old one is %s, new one is %s", old.Phase, new.Phase)
+		return true
+	}
+
 	if utils.CompareStringValue("Result", string(old.Result), string(new.Result), reqLogger) {
 		//reqLogger.Info(fmt.Sprintf("found status.Result changed: the old one is %s, new one is %s", old.Result, new.Result))
 		klog.Infof("found status.Result changed: the old one is %s, new one is %s", old.Result, new.Result)
@@ -35,13 +41,43 @@ func compareStatus(new, old *upmv1alpha1.GrpcCallStatus, reqLogger logr.Logger)
 		return true
 	}
 
-	if new.StartTime.Time != old.StartTime.Time {
-		klog.Infof("found status.StartTime changed: the old one is %s, new one is %s", old.StartTime.String(), new.StartTime.String())
+	if new.OperationID != old.OperationID {
+		klog.Infof("found status.OperationID changed: the old one is %s, new one is %s", old.OperationID, new.OperationID)
+		return true
+	}
+
+	if !new.StartTime.Equal(old.StartTime) {
+		klog.Infof("found status.StartTime changed: the old one is %v, new one is %v", old.StartTime, new.StartTime)
+		return true
+	}
+
+	if !new.NextAttemptTime.Equal(old.NextAttemptTime) {
+		klog.Infof("found status.NextAttemptTime changed: the old one is %v, new one is %v", old.NextAttemptTime, new.NextAttemptTime)
+		return true
+	}
+
+	if !equality.Semantic.DeepEqual(new.Attempts, old.Attempts) {
+		klog.Infof("found status.Attempts changed: %d attempts", len(new.Attempts))
+		return true
+	}
+
+	if new.Unit != old.Unit {
+		klog.Infof("found status.Unit changed: the old one is %s, new one is %s", old.Unit, new.Unit)
+		return true
+	}
+
+	if !equality.Semantic.DeepEqual(new.Units, old.Units) {
+		klog.Infof("found status.Units changed: %d units", len(new.Units))
+		return true
+	}
+
+	if !equality.Semantic.DeepEqual(new.Outputs, old.Outputs) {
+		klog.Infof("found status.Outputs changed: %d outputs", len(new.Outputs))
 		return true
 	}
 
-	if new.CompletionTime.Time != old.CompletionTime.Time {
-		klog.Infof("found status.CompletionTime changed: the old one is %s, new one is %s", old.CompletionTime.String(), new.CompletionTime.String())
+	if !new.CompletionTime.Equal(old.CompletionTime) {
+		klog.Infof("found status.CompletionTime changed: the old one is %v, new one is %v", old.CompletionTime, new.CompletionTime)
 		return true
 	}
 
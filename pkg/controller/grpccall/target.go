@@ -0,0 +1,382 @@
+package grpccall
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	composev1alpha1 "github.com/upmio/compose-operator/api/v1alpha1"
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// compose-operator labels the pods of its replications with the name of the
+// replication and whether the pod is a read-only replica
+const (
+	composeLabelPrefix           = "compose-operator/"
+	replicationReadOnlySuffix    = "-replication.readonly"
+	mysqlReplicationNameLabel    = "compose-operator/mysql-replication.name"
+	postgresReplicationNameLabel = "compose-operator/postgres-replication.name"
+
+	// LabelParentGrpcCall is the name of the GrpcCall a GrpcCall targeting a
+	// single unit was fanned out from
+	LabelParentGrpcCall = "unit-operator/parent-grpc-call"
+)
+
+// targetUnitName returns the unit the current attempt of the call is sent to.
+func targetUnitName(instance *upmv1alpha1.GrpcCall) string {
+	if instance.Spec.TargetUnit != "" {
+		return instance.Spec.TargetUnit
+	}
+
+	return instance.Status.Unit
+}
+
+// validateTarget checks that the call targets either a unit or a UnitSet.
+func validateTarget(instance *upmv1alpha1.GrpcCall) error {
+	switch {
+	case instance.Spec.TargetUnit == "" && instance.Spec.TargetUnitSet == nil:
+		return fmt.Errorf("one of targetUnit and targetUnitSet must be set")
+	case instance.Spec.TargetUnit != "" && instance.Spec.TargetUnitSet != nil:
+		return fmt.Errorf("targetUnit and targetUnitSet are mutually exclusive")
+	case instance.Spec.TargetUnitSet != nil && instance.Spec.TargetUnitSet.Name == "":
+		return fmt.Errorf("targetUnitSet.name must be set")
+	}
+
+	return nil
+}
+
+// isFanOut reports whether the call is sent to all the selected units of its UnitSet.
+func isFanOut(instance *upmv1alpha1.GrpcCall) bool {
+	return instance.Spec.TargetUnitSet != nil && instance.Spec.TargetUnitSet.Policy == upmv1alpha1.AllPolicy
+}
+
+// selectedUnit is a unit matching the selector of a UnitSet target, with its pod.
+type selectedUnit struct {
+	unit *upmv1alpha2.Unit
+	pod  *corev1.Pod
+}
+
+// selectUnits returns the units of the target UnitSet matching its role and
+// readiness selector, sorted by name. Finding none fails with Unavailable, so
+// that the call is retried once a unit matches again.
+func selectUnits(ctx context.Context, c client.Client, instance *upmv1alpha1.GrpcCall) ([]selectedUnit, error) {
+	target := instance.Spec.TargetUnitSet
+
+	units := &upmv1alpha2.UnitList{}
+	if err := c.List(ctx, units, client.InNamespace(instance.Namespace), client.MatchingLabels{upmv1alpha2.UnitsetName: target.Name}); err != nil {
+		return nil, &codeError{code: codes.Unavailable, err: fmt.Errorf("failed to list units of unitset [%s]: %v", target.Name, err)}
+	}
+
+	sort.Slice(units.Items, func(i, j int) bool {
+		return units.Items[i].Name < units.Items[j].Name
+	})
+
+	selected := make([]selectedUnit, 0, len(units.Items))
+	for i := range units.Items {
+		unit := &units.Items[i]
+		if !unit.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if target.Ready && unit.Status.Phase != upmv1alpha2.UnitReady {
+			continue
+		}
+
+		pod := &corev1.Pod{}
+		if err := c.Get(ctx, types.NamespacedName{Name: unit.Name, Namespace: unit.Namespace}, pod); err != nil {
+			if target.Role != "" || target.Policy == upmv1alpha1.LeastLaggedPolicy {
+				continue
+			}
+			pod = nil
+		}
+
+		if target.Role != "" && unitRole(pod) != target.Role {
+			continue
+		}
+
+		selected = append(selected, selectedUnit{unit: unit, pod: pod})
+	}
+
+	if len(selected) == 0 {
+		return nil, &codeError{code: codes.Unavailable, err: fmt.Errorf("no unit of unitset [%s] matches %s", target.Name, describeTarget(target))}
+	}
+
+	return selected, nil
+}
+
+// selectTargetUnit picks the unit the next attempt of the call is sent to.
+func selectTargetUnit(ctx context.Context, c client.Client, instance *upmv1alpha1.GrpcCall) (string, error) {
+	selected, err := selectUnits(ctx, c, instance)
+	if err != nil {
+		return "", err
+	}
+
+	target := instance.Spec.TargetUnitSet
+	if target.Policy != upmv1alpha1.LeastLaggedPolicy {
+		return selected[0].unit.Name, nil
+	}
+
+	// the primary never lags, the policy picks the replica closest to it
+	replicas := make([]selectedUnit, 0, len(selected))
+	for _, s := range selected {
+		if unitRole(s.pod) != upmv1alpha1.PrimaryRole {
+			replicas = append(replicas, s)
+		}
+	}
+	if len(replicas) == 0 {
+		return "", &codeError{code: codes.Unavailable, err: fmt.Errorf("no replica of unitset [%s] matches %s", target.Name, describeTarget(target))}
+	}
+
+	best, bestLag := replicas[0].unit.Name, int64(-1)
+	for _, s := range replicas {
+		lag, ok, err := replicationLag(ctx, c, s)
+		if err != nil {
+			return "", &codeError{code: codes.Unavailable, err: err}
+		}
+		if ok && (bestLag < 0 || lag < bestLag) {
+			best, bestLag = s.unit.Name, lag
+		}
+	}
+
+	return best, nil
+}
+
+// unitRole returns the replication role compose-operator labelled the pod
+// of a unit with, or an empty role for a unit outside of a replication.
+func unitRole(pod *corev1.Pod) upmv1alpha1.UnitRole {
+	if pod == nil {
+		return ""
+	}
+
+	for key, value := range pod.Labels {
+		if !strings.HasPrefix(key, composeLabelPrefix) || !strings.HasSuffix(key, replicationReadOnlySuffix) {
+			continue
+		}
+
+		switch value {
+		case "true":
+			return upmv1alpha1.ReplicaRole
+		case "false":
+			return upmv1alpha1.PrimaryRole
+		}
+	}
+
+	return ""
+}
+
+// replicationLag returns how far the unit lags behind its replication source,
+// in seconds for MySQL and in WAL bytes for PostgreSQL, according to the
+// topology of the compose-operator replication its pod is labelled with.
+func replicationLag(ctx context.Context, c client.Client, s selectedUnit) (int64, bool, error) {
+	if name := s.pod.Labels[mysqlReplicationNameLabel]; name != "" {
+		replication := &composev1alpha1.MysqlReplication{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: s.pod.Namespace}, replication); err != nil {
+			return 0, false, fmt.Errorf("failed to fetch mysql replication [%s]: %v", name, err)
+		}
+
+		node, ok := replication.Status.Topology[s.unit.Name]
+		if !ok || node == nil || node.SecondsBehindSource == nil {
+			return 0, false, nil
+		}
+		return int64(*node.SecondsBehindSource), true, nil
+	}
+
+	if name := s.pod.Labels[postgresReplicationNameLabel]; name != "" {
+		replication := &composev1alpha1.PostgresReplication{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: s.pod.Namespace}, replication); err != nil {
+			return 0, false, fmt.Errorf("failed to fetch postgres replication [%s]: %v", name, err)
+		}
+
+		node, ok := replication.Status.Topology[s.unit.Name]
+		if !ok || node == nil || node.WalDiff == nil {
+			return 0, false, nil
+		}
+		return int64(*node.WalDiff), true, nil
+	}
+
+	return 0, false, nil
+}
+
+// describeTarget describes the selector of a UnitSet target for messages.
+func describeTarget(target *upmv1alpha1.UnitSetTarget) string {
+	var selectors []string
+	if target.Role != "" {
+		selectors = append(selectors, fmt.Sprintf("role=%s", target.Role))
+	}
+	if target.Ready {
+		selectors = append(selectors, "ready=true")
+	}
+	if len(selectors) == 0 {
+		return "any selector"
+	}
+
+	return strings.Join(selectors, ", ")
+}
+
+// reconcileFanOut sends the call to all the selected units of its UnitSet
+// through a GrpcCall targeting each of them, and finishes once they all
+// finished. The units are selected once, when the call starts.
+func (r *ReconcileGrpcCall) reconcileFanOut(
+	ctx context.Context,
+	instance *upmv1alpha1.GrpcCall,
+	oldStatus *upmv1alpha1.GrpcCallStatus,
+	reqLogger logr.Logger,
+) (ctrl.Result, error) {
+	if instance.Status.Units == nil {
+		if instance.Spec.Suspend {
+			r.finishGrpcCall(instance, upmv1alpha1.CancelledResult, "grpc call is suspended")
+			r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
+
+			return r.requeueFinished(instance), nil
+		}
+
+		if next := instance.Status.NextAttemptTime; next != nil && time.Now().Before(next.Time) {
+			return reconcile.Result{RequeueAfter: time.Until(next.Time)}, nil
+		}
+
+		// a selection matching no unit is an attempt of the call, retried
+		// under its retry policy until a unit matches again
+		selected, err := selectUnits(ctx, r.client, instance)
+		if err != nil {
+			startAttempt(instance)
+			result := r.failAttempt(instance, status.Code(err), upmv1alpha1.FailedResult, err.Error())
+			r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
+
+			return result, nil
+		}
+
+		now := metav1.Now()
+		instance.Status.Phase = upmv1alpha1.GrpcCallRunning
+		instance.Status.NextAttemptTime = nil
+		if instance.Status.StartTime == nil {
+			instance.Status.StartTime = &now
+		}
+		instance.Status.Message = fmt.Sprintf("sending grpc call to %d units of unitset [%s]", len(selected), instance.Spec.TargetUnitSet.Name)
+		instance.Status.Units = make([]upmv1alpha1.GrpcCallUnitStatus, 0, len(selected))
+		for _, s := range selected {
+			instance.Status.Units = append(instance.Status.Units, upmv1alpha1.GrpcCallUnitStatus{
+				Unit:     s.unit.Name,
+				GrpcCall: fmt.Sprintf("%s-%s", instance.Name, s.unit.Name),
+				Phase:    upmv1alpha1.GrpcCallPending,
+			})
+		}
+
+		// the selection is recorded before the calls are created, so that
+		// the same units are targeted after an operator restart
+		if err := r.client.Status().Update(ctx, instance); err != nil {
+			klog.Errorf("failed to update grpc call [%s] status: %v", instance.Name, err)
+			return reconcile.Result{}, err
+		}
+		oldStatus = instance.Status.DeepCopy()
+	}
+
+	var running, failed, cancelled []string
+	for i := range instance.Status.Units {
+		unitStatus := &instance.Status.Units[i]
+
+		child := &upmv1alpha1.GrpcCall{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: unitStatus.GrpcCall, Namespace: instance.Namespace}, child)
+		switch {
+		case apierrors.IsNotFound(err) && !isFinishedPhase(unitStatus.Phase):
+			if instance.Spec.Suspend {
+				unitStatus.Phase = upmv1alpha1.GrpcCallCancelled
+				unitStatus.Result = upmv1alpha1.CancelledResult
+				break
+			}
+
+			if err := r.createUnitGrpcCall(ctx, instance, unitStatus); err != nil {
+				return reconcile.Result{}, err
+			}
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return reconcile.Result{}, fmt.Errorf("failed to fetch grpc call [%s]: %v", unitStatus.GrpcCall, err)
+		default:
+			if instance.Spec.Suspend && !child.Spec.Suspend && !isFinishedPhase(child.Status.Phase) {
+				child.Spec.Suspend = true
+				if err := r.client.Update(ctx, child); err != nil {
+					return reconcile.Result{}, fmt.Errorf("failed to suspend grpc call [%s]: %v", child.Name, err)
+				}
+			}
+
+			unitStatus.Phase = child.Status.Phase
+			unitStatus.Result = child.Status.Result
+			unitStatus.Message = child.Status.Message
+			unitStatus.Outputs = child.Status.Outputs
+		}
+
+		switch unitStatus.Phase {
+		case upmv1alpha1.GrpcCallSucceeded:
+		case upmv1alpha1.GrpcCallFailed:
+			failed = append(failed, fmt.Sprintf("%s: %s", unitStatus.Unit, unitStatus.Message))
+		case upmv1alpha1.GrpcCallCancelled:
+			cancelled = append(cancelled, unitStatus.Unit)
+		default:
+			running = append(running, unitStatus.Unit)
+		}
+	}
+
+	total := len(instance.Status.Units)
+	switch {
+	case len(running) > 0:
+		instance.Status.Message = fmt.Sprintf("%d of %d units finished", total-len(running), total)
+	case len(failed) > 0:
+		r.finishGrpcCall(instance, upmv1alpha1.FailedResult,
+			fmt.Sprintf("%d of %d units failed: %s", len(failed), total, strings.Join(failed, "; ")))
+	case len(cancelled) > 0:
+		r.finishGrpcCall(instance, upmv1alpha1.CancelledResult,
+			fmt.Sprintf("%d of %d units cancelled: %s", len(cancelled), total, strings.Join(cancelled, ", ")))
+	default:
+		r.finishGrpcCall(instance, upmv1alpha1.SuccessResult, fmt.Sprintf("all %d units succeeded", total))
+	}
+
+	r.updateInstanceIfNeed(instance, oldStatus, reqLogger)
+
+	// the owned GrpcCalls requeue the call as they progress
+	return r.requeueFinished(instance), nil
+}
+
+// createUnitGrpcCall creates the GrpcCall sending the call to a single unit.
+// It is owned by the fanned out call, and deleted along with it.
+func (r *ReconcileGrpcCall) createUnitGrpcCall(
+	ctx context.Context,
+	instance *upmv1alpha1.GrpcCall,
+	unitStatus *upmv1alpha1.GrpcCallUnitStatus,
+) error {
+	child := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      unitStatus.GrpcCall,
+			Namespace: instance.Namespace,
+			Labels:    map[string]string{LabelParentGrpcCall: instance.Name},
+		},
+		Spec: *instance.Spec.DeepCopy(),
+	}
+	child.Spec.TargetUnit = unitStatus.Unit
+	child.Spec.TargetUnitSet = nil
+	child.Spec.TTLSecondsAfterFinished = nil
+
+	if err := controllerutil.SetControllerReference(instance, child, r.scheme); err != nil {
+		return fmt.Errorf("failed to set owner of grpc call [%s]: %v", child.Name, err)
+	}
+
+	if err := r.client.Create(ctx, child); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create grpc call [%s]: %v", child.Name, err)
+	}
+
+	klog.Infof("created grpc call [%s] targeting unit [%s]", child.Name, unitStatus.Unit)
+	return nil
+}
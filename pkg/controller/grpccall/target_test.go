@@ -0,0 +1,320 @@
+package grpccall
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	composev1alpha1 "github.com/upmio/compose-operator/api/v1alpha1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+)
+
+func newTargetTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, upmv1alpha1.AddToScheme(s))
+	require.NoError(t, upmv1alpha2.AddToScheme(s))
+	require.NoError(t, composev1alpha1.AddToScheme(s))
+	return s
+}
+
+// newReplicationUnit returns a unit of the mysql UnitSet with its pod,
+// labelled by compose-operator as a member of the mysql replication.
+func newReplicationUnit(name string, readOnly, ready bool) []client.Object {
+	unit := &upmv1alpha2.Unit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{upmv1alpha2.UnitsetName: "mysql"},
+		},
+	}
+	if ready {
+		unit.Status.Phase = upmv1alpha2.UnitReady
+	}
+
+	readOnlyLabel := "false"
+	if readOnly {
+		readOnlyLabel = "true"
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				mysqlReplicationNameLabel:                     "mysql-replication",
+				"compose-operator/mysql-replication.readonly": readOnlyLabel,
+			},
+		},
+	}
+
+	return []client.Object{unit, pod}
+}
+
+func newReplicationObjects() []client.Object {
+	var objects []client.Object
+	objects = append(objects, newReplicationUnit("mysql-0", false, true)...)
+	objects = append(objects, newReplicationUnit("mysql-1", true, true)...)
+	objects = append(objects, newReplicationUnit("mysql-2", true, true)...)
+	objects = append(objects, newReplicationUnit("mysql-3", true, false)...)
+
+	return append(objects, &composev1alpha1.MysqlReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql-replication", Namespace: "default"},
+		Status: composev1alpha1.MysqlReplicationStatus{
+			Topology: composev1alpha1.MysqlReplicationTopology{
+				"mysql-0": {},
+				"mysql-1": {SecondsBehindSource: ptr.To(5)},
+				"mysql-2": {SecondsBehindSource: ptr.To(1)},
+				"mysql-3": {SecondsBehindSource: ptr.To(0)},
+			},
+		},
+	})
+}
+
+func TestSelectTargetUnit(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newTargetTestScheme(t)).WithObjects(newReplicationObjects()...).Build()
+
+	tests := []struct {
+		name   string
+		target upmv1alpha1.UnitSetTarget
+		want   string
+	}{
+		{
+			name:   "first unit of any role",
+			target: upmv1alpha1.UnitSetTarget{Name: "mysql"},
+			want:   "mysql-0",
+		},
+		{
+			name:   "first ready replica",
+			target: upmv1alpha1.UnitSetTarget{Name: "mysql", Role: upmv1alpha1.ReplicaRole, Ready: true},
+			want:   "mysql-1",
+		},
+		{
+			name:   "least lagged ready replica",
+			target: upmv1alpha1.UnitSetTarget{Name: "mysql", Role: upmv1alpha1.ReplicaRole, Ready: true, Policy: upmv1alpha1.LeastLaggedPolicy},
+			want:   "mysql-2",
+		},
+		{
+			name:   "least lagged replica, ready or not",
+			target: upmv1alpha1.UnitSetTarget{Name: "mysql", Role: upmv1alpha1.ReplicaRole, Policy: upmv1alpha1.LeastLaggedPolicy},
+			want:   "mysql-3",
+		},
+		{
+			name:   "least lagged unit of any role is a replica",
+			target: upmv1alpha1.UnitSetTarget{Name: "mysql", Ready: true, Policy: upmv1alpha1.LeastLaggedPolicy},
+			want:   "mysql-2",
+		},
+		{
+			name:   "primary",
+			target: upmv1alpha1.UnitSetTarget{Name: "mysql", Role: upmv1alpha1.PrimaryRole},
+			want:   "mysql-0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &upmv1alpha1.GrpcCall{
+				ObjectMeta: metav1.ObjectMeta{Name: "call", Namespace: "default"},
+				Spec:       upmv1alpha1.GrpcCallSpec{TargetUnitSet: &tt.target},
+			}
+
+			unit, err := selectTargetUnit(context.Background(), c, instance)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, unit)
+		})
+	}
+}
+
+func TestSelectTargetUnitNoMatch(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newTargetTestScheme(t)).WithObjects(newReplicationUnit("mysql-0", false, false)...).Build()
+
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{Name: "call", Namespace: "default"},
+		Spec: upmv1alpha1.GrpcCallSpec{
+			TargetUnitSet: &upmv1alpha1.UnitSetTarget{Name: "mysql", Role: upmv1alpha1.PrimaryRole, Ready: true},
+		},
+	}
+
+	_, err := selectTargetUnit(context.Background(), c, instance)
+	assert.EqualError(t, err, "no unit of unitset [mysql] matches role=primary, ready=true")
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestSelectTargetUnitLeastLaggedSkipsPrimary(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newTargetTestScheme(t)).WithObjects(newReplicationObjects()...).Build()
+
+	instance := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{Name: "call", Namespace: "default"},
+		Spec: upmv1alpha1.GrpcCallSpec{
+			TargetUnitSet: &upmv1alpha1.UnitSetTarget{Name: "mysql", Role: upmv1alpha1.PrimaryRole, Policy: upmv1alpha1.LeastLaggedPolicy},
+		},
+	}
+
+	_, err := selectTargetUnit(context.Background(), c, instance)
+	assert.EqualError(t, err, "no replica of unitset [mysql] matches role=primary")
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestValidateTarget(t *testing.T) {
+	assert.Error(t, validateTarget(&upmv1alpha1.GrpcCall{}))
+	assert.Error(t, validateTarget(&upmv1alpha1.GrpcCall{Spec: upmv1alpha1.GrpcCallSpec{
+		TargetUnit:    "mysql-0",
+		TargetUnitSet: &upmv1alpha1.UnitSetTarget{Name: "mysql"},
+	}}))
+	assert.NoError(t, validateTarget(&upmv1alpha1.GrpcCall{Spec: upmv1alpha1.GrpcCallSpec{TargetUnit: "mysql-0"}}))
+	assert.NoError(t, validateTarget(&upmv1alpha1.GrpcCall{Spec: upmv1alpha1.GrpcCallSpec{
+		TargetUnitSet: &upmv1alpha1.UnitSetTarget{Name: "mysql"},
+	}}))
+}
+
+func TestReconcileGrpcCall_Reconcile_FanOut(t *testing.T) {
+	s := newTargetTestScheme(t)
+
+	parent := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{Name: "flush", Namespace: "default", UID: "uid"},
+		Spec: upmv1alpha1.GrpcCallSpec{
+			Type:   upmv1alpha1.MysqlType,
+			Action: upmv1alpha1.SetVariableAction,
+			TargetUnitSet: &upmv1alpha1.UnitSetTarget{
+				Name:   "mysql",
+				Role:   upmv1alpha1.ReplicaRole,
+				Ready:  true,
+				Policy: upmv1alpha1.AllPolicy,
+			},
+			TTLSecondsAfterFinished: ptr.To(int32(60)),
+		},
+	}
+
+	objects := append(newReplicationObjects(), parent)
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).
+		WithStatusSubresource(&upmv1alpha1.GrpcCall{}).Build()
+
+	reconciler := &ReconcileGrpcCall{
+		client:   c,
+		scheme:   s,
+		recorder: record.NewFakeRecorder(10),
+		logger:   zap.New().WithName("test"),
+	}
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "flush", Namespace: "default"}}
+
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	got := &upmv1alpha1.GrpcCall{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, got))
+	assert.Equal(t, upmv1alpha1.GrpcCallRunning, got.Status.Phase)
+	require.Len(t, got.Status.Units, 2)
+	assert.Equal(t, "mysql-1", got.Status.Units[0].Unit)
+	assert.Equal(t, "mysql-2", got.Status.Units[1].Unit)
+
+	for i, unit := range []string{"mysql-1", "mysql-2"} {
+		child := &upmv1alpha1.GrpcCall{}
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "flush-" + unit, Namespace: "default"}, child))
+		assert.Equal(t, unit, child.Spec.TargetUnit)
+		assert.Nil(t, child.Spec.TargetUnitSet)
+		assert.Nil(t, child.Spec.TTLSecondsAfterFinished)
+		assert.Equal(t, "flush", child.Labels[LabelParentGrpcCall])
+		assert.True(t, metav1.IsControlledBy(child, got))
+
+		child.Status.Phase = upmv1alpha1.GrpcCallSucceeded
+		child.Status.Result = upmv1alpha1.SuccessResult
+		if i == 1 {
+			child.Status.Phase = upmv1alpha1.GrpcCallFailed
+			child.Status.Result = upmv1alpha1.FailedResult
+			child.Status.Message = "unit agent unavailable"
+		}
+		require.NoError(t, c.Status().Update(ctx, child))
+	}
+
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Get(ctx, req.NamespacedName, got))
+	assert.Equal(t, upmv1alpha1.GrpcCallFailed, got.Status.Phase)
+	assert.Equal(t, upmv1alpha1.FailedResult, got.Status.Result)
+	assert.Equal(t, "1 of 2 units failed: mysql-2: unit agent unavailable", got.Status.Message)
+	assert.Equal(t, upmv1alpha1.GrpcCallSucceeded, got.Status.Units[0].Phase)
+	assert.Equal(t, upmv1alpha1.GrpcCallFailed, got.Status.Units[1].Phase)
+}
+
+func TestReconcileGrpcCall_Reconcile_FanOutRetriesEmptySelection(t *testing.T) {
+	s := newTargetTestScheme(t)
+
+	parent := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{Name: "flush", Namespace: "default", UID: "uid"},
+		Spec: upmv1alpha1.GrpcCallSpec{
+			Type:   upmv1alpha1.MysqlType,
+			Action: upmv1alpha1.SetVariableAction,
+			TargetUnitSet: &upmv1alpha1.UnitSetTarget{
+				Name:   "mysql",
+				Ready:  true,
+				Policy: upmv1alpha1.AllPolicy,
+			},
+			RetryPolicy: &upmv1alpha1.RetryPolicy{MaxAttempts: 2, BackoffSeconds: 1},
+		},
+	}
+
+	objects := append(newReplicationUnit("mysql-0", false, false), parent)
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).
+		WithStatusSubresource(&upmv1alpha1.GrpcCall{}, &upmv1alpha2.Unit{}).Build()
+
+	reconciler := &ReconcileGrpcCall{
+		client:   c,
+		scheme:   s,
+		recorder: record.NewFakeRecorder(10),
+		logger:   zap.New().WithName("test"),
+	}
+
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "flush", Namespace: "default"}}
+
+	// no unit is ready, the selection is retried
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, result.RequeueAfter)
+
+	got := &upmv1alpha1.GrpcCall{}
+	require.NoError(t, c.Get(ctx, req.NamespacedName, got))
+	assert.Equal(t, upmv1alpha1.GrpcCallPending, got.Status.Phase)
+	require.NotNil(t, got.Status.NextAttemptTime)
+	require.Len(t, got.Status.Attempts, 1)
+	assert.Equal(t, upmv1alpha1.GrpcCode(codes.Unavailable.String()), got.Status.Attempts[0].Code)
+	assert.Nil(t, got.Status.Units)
+
+	unit := &upmv1alpha2.Unit{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "mysql-0", Namespace: "default"}, unit))
+	unit.Status.Phase = upmv1alpha2.UnitReady
+	require.NoError(t, c.Status().Update(ctx, unit))
+
+	got.Status.NextAttemptTime = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	require.NoError(t, c.Status().Update(ctx, got))
+
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Get(ctx, req.NamespacedName, got))
+	assert.Equal(t, upmv1alpha1.GrpcCallRunning, got.Status.Phase)
+	assert.Nil(t, got.Status.NextAttemptTime)
+	require.Len(t, got.Status.Units, 1)
+	assert.Equal(t, "mysql-0", got.Status.Units[0].Unit)
+}
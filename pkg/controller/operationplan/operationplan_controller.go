@@ -0,0 +1,332 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package operationplan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+)
+
+const (
+	appName = "operation-plan"
+
+	// lifecyclePollInterval is how often the process state of a unit is checked
+	// while a lifecycle action waits for it
+	lifecyclePollInterval = 5 * time.Second
+
+	// LabelOperationPlan is the name of the OperationPlan a GrpcCall runs a step of
+	LabelOperationPlan = "unit-operator/operation-plan"
+)
+
+// ReconcileOperationPlan reconciles OperationPlan resources.
+type ReconcileOperationPlan struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=operationplans,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=operationplans/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=grpccalls,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=units,verbs=get;list
+
+func (r *ReconcileOperationPlan) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.Infof("start reconciling operation plan instance [%s]", req.String())
+	startTime := time.Now()
+
+	defer func() {
+		klog.Infof("finished reconciliation operation plan instance [%s], duration [%v]", req.String(), time.Since(startTime))
+	}()
+
+	instance := &upmv1alpha1.OperationPlan{}
+	if err := r.client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Infof("operation plan instance [%s] not found, probably deleted.", req.String())
+			return reconcile.Result{}, nil
+		}
+
+		klog.Errorf("failed to fetch operation plan instance [%s]: [%v]", req.String(), err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if !instance.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	if instance.Status.CompletionTime != nil {
+		return r.reconcileFinished(ctx, instance)
+	}
+
+	oldStatus := instance.Status.DeepCopy()
+
+	result, err := r.reconcilePlan(ctx, instance)
+	if err != nil {
+		klog.Errorf("failed to reconcile operation plan instance [%s]: %v", req.String(), err)
+	}
+
+	if !reflect.DeepEqual(oldStatus, &instance.Status) {
+		if err := r.client.Status().Update(ctx, instance); err != nil {
+			klog.Errorf("failed to update operation plan [%s] status: %v", req.String(), err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if instance.Status.CompletionTime != nil {
+		return r.requeueFinished(instance), nil
+	}
+
+	return result, nil
+}
+
+// reconcilePlan advances the steps of the plan, then its rollbacks, and
+// finishes the plan once nothing is left to run.
+func (r *ReconcileOperationPlan) reconcilePlan(ctx context.Context, instance *upmv1alpha1.OperationPlan) (ctrl.Result, error) {
+	if instance.Status.Steps == nil {
+		if err := validatePlan(instance); err != nil {
+			r.finishPlan(instance, upmv1alpha1.OperationPlanFailed, err.Error())
+			return reconcile.Result{}, nil
+		}
+
+		now := metav1.Now()
+		instance.Status.Phase = upmv1alpha1.OperationPlanRunning
+		instance.Status.StartTime = &now
+		instance.Status.Steps = make([]upmv1alpha1.OperationStepStatus, 0, len(instance.Spec.Steps))
+		for _, step := range instance.Spec.Steps {
+			instance.Status.Steps = append(instance.Status.Steps, upmv1alpha1.OperationStepStatus{
+				Name:                  step.Name,
+				OperationActionStatus: upmv1alpha1.OperationActionStatus{Phase: upmv1alpha1.StepPending},
+			})
+		}
+	}
+
+	if instance.Spec.Suspend {
+		return reconcile.Result{}, r.cancelPlan(ctx, instance)
+	}
+
+	result := reconcile.Result{}
+	aborted, rollback := false, false
+	for _, stage := range planStages(instance.Spec.Steps) {
+		running := false
+		for _, i := range stage {
+			step := &instance.Spec.Steps[i]
+			state := &instance.Status.Steps[i].OperationActionStatus
+
+			if state.Phase == upmv1alpha1.StepPending {
+				if !shouldRun(step.When, aborted) {
+					state.Phase = upmv1alpha1.StepSkipped
+					continue
+				}
+
+				if err := r.startAction(ctx, instance, &step.OperationAction, state, stepObjectName(instance, step.Name)); err != nil {
+					return reconcile.Result{}, err
+				}
+				klog.Infof("started step [%s] of operation plan [%s/%s]", step.Name, instance.Namespace, instance.Name)
+			}
+
+			if state.Phase == upmv1alpha1.StepRunning {
+				requeueAfter, err := r.progressAction(ctx, instance, &step.OperationAction, state)
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+				result = minRequeue(result, requeueAfter)
+
+				if actionFailed(state.Phase) {
+					r.recorder.Eventf(instance, corev1.EventTypeWarning, "StepFailed", "step %s failed: %s", step.Name, failureDetail(state))
+				}
+			}
+
+			if state.Phase == upmv1alpha1.StepRunning {
+				running = true
+			}
+		}
+
+		if running {
+			instance.Status.Message = fmt.Sprintf("running %s", strings.Join(stageStepNames(instance, stage), ", "))
+			return result, nil
+		}
+
+		for _, i := range stage {
+			step := &instance.Spec.Steps[i]
+			if !actionFailed(instance.Status.Steps[i].Phase) || step.OnFailure == upmv1alpha1.ContinuePolicy {
+				continue
+			}
+
+			aborted = true
+			if step.OnFailure == upmv1alpha1.RollbackPolicy {
+				rollback = true
+			}
+		}
+	}
+
+	if rollback {
+		instance.Status.Phase = upmv1alpha1.OperationPlanRollingBack
+
+		done, requeueAfter, err := r.rollbackSteps(ctx, instance)
+		if err != nil || !done {
+			return minRequeue(result, requeueAfter), err
+		}
+	}
+
+	if aborted {
+		r.finishPlan(instance, upmv1alpha1.OperationPlanFailed, failureMessage(instance, rollback))
+		return reconcile.Result{}, nil
+	}
+
+	r.finishPlan(instance, upmv1alpha1.OperationPlanSucceeded, successMessage(instance))
+	return reconcile.Result{}, nil
+}
+
+// rollbackSteps rolls back the succeeded steps with a rollback, one at a
+// time, in the reverse order of the steps.
+func (r *ReconcileOperationPlan) rollbackSteps(ctx context.Context, instance *upmv1alpha1.OperationPlan) (bool, time.Duration, error) {
+	for i := len(instance.Spec.Steps) - 1; i >= 0; i-- {
+		step := &instance.Spec.Steps[i]
+		stepStatus := &instance.Status.Steps[i]
+		if step.Rollback == nil || stepStatus.Phase != upmv1alpha1.StepSucceeded {
+			continue
+		}
+
+		if stepStatus.Rollback == nil {
+			stepStatus.Rollback = &upmv1alpha1.OperationActionStatus{Phase: upmv1alpha1.StepPending}
+		}
+
+		state := stepStatus.Rollback
+		if state.Phase == upmv1alpha1.StepPending {
+			if err := r.startAction(ctx, instance, step.Rollback, state, stepObjectName(instance, step.Name+rollbackSuffix)); err != nil {
+				return false, 0, err
+			}
+			klog.Infof("started rollback of step [%s] of operation plan [%s/%s]", step.Name, instance.Namespace, instance.Name)
+		}
+
+		if state.Phase == upmv1alpha1.StepRunning {
+			requeueAfter, err := r.progressAction(ctx, instance, step.Rollback, state)
+			if err != nil {
+				return false, 0, err
+			}
+
+			if state.Phase == upmv1alpha1.StepRunning {
+				instance.Status.Message = fmt.Sprintf("rolling back %s", step.Name)
+				return false, requeueAfter, nil
+			}
+
+			if actionFailed(state.Phase) {
+				r.recorder.Eventf(instance, corev1.EventTypeWarning, "RollbackFailed", "rollback of step %s failed: %s", step.Name, failureDetail(state))
+			}
+		}
+	}
+
+	return true, 0, nil
+}
+
+// cancelPlan cancels the running steps of a suspended plan and skips the others.
+func (r *ReconcileOperationPlan) cancelPlan(ctx context.Context, instance *upmv1alpha1.OperationPlan) error {
+	for i := range instance.Status.Steps {
+		stepStatus := &instance.Status.Steps[i]
+
+		for _, state := range []*upmv1alpha1.OperationActionStatus{&stepStatus.OperationActionStatus, stepStatus.Rollback} {
+			if state == nil {
+				continue
+			}
+
+			switch state.Phase {
+			case upmv1alpha1.StepPending:
+				state.Phase = upmv1alpha1.StepSkipped
+			case upmv1alpha1.StepRunning:
+				if err := r.cancelAction(ctx, instance, state); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	r.finishPlan(instance, upmv1alpha1.OperationPlanCancelled, "operation plan is suspended")
+	return nil
+}
+
+// finishPlan records the outcome of the plan.
+func (r *ReconcileOperationPlan) finishPlan(instance *upmv1alpha1.OperationPlan, phase upmv1alpha1.OperationPlanPhase, message string) {
+	now := metav1.Now()
+	instance.Status.Phase = phase
+	instance.Status.Message = message
+	instance.Status.CompletionTime = &now
+
+	switch phase {
+	case upmv1alpha1.OperationPlanSucceeded:
+		r.recorder.Event(instance, corev1.EventTypeNormal, "PlanSucceeded", message)
+	case upmv1alpha1.OperationPlanCancelled:
+		r.recorder.Event(instance, corev1.EventTypeWarning, "PlanCancelled", message)
+	default:
+		r.recorder.Event(instance, corev1.EventTypeWarning, "PlanFailed", message)
+	}
+}
+
+// reconcileFinished deletes a finished OperationPlan once its TTL expired.
+func (r *ReconcileOperationPlan) reconcileFinished(ctx context.Context, instance *upmv1alpha1.OperationPlan) (ctrl.Result, error) {
+	if instance.Spec.TTLSecondsAfterFinished != nil &&
+		time.Since(instance.Status.CompletionTime.Time).Seconds() >= float64(*instance.Spec.TTLSecondsAfterFinished) {
+		klog.Infof("operation plan instance [%s/%s] is marked for automatic deletion: completed at [%s], TTL = %d seconds",
+			instance.Namespace, instance.Name, instance.Status.CompletionTime.Format(time.RFC3339), *instance.Spec.TTLSecondsAfterFinished)
+
+		return reconcile.Result{}, client.IgnoreNotFound(r.client.Delete(ctx, instance))
+	}
+
+	return r.requeueFinished(instance), nil
+}
+
+// requeueFinished requeues a finished OperationPlan when its TTL expires.
+func (r *ReconcileOperationPlan) requeueFinished(instance *upmv1alpha1.OperationPlan) ctrl.Result {
+	if instance.Status.CompletionTime == nil || instance.Spec.TTLSecondsAfterFinished == nil {
+		return reconcile.Result{}
+	}
+
+	ttl := time.Duration(*instance.Spec.TTLSecondsAfterFinished) * time.Second
+	remaining := time.Until(instance.Status.CompletionTime.Add(ttl))
+	if remaining < time.Second {
+		remaining = time.Second
+	}
+
+	return reconcile.Result{RequeueAfter: remaining}
+}
+
+func Setup(mgr ctrl.Manager) error {
+	r := &ReconcileOperationPlan{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor(appName),
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&upmv1alpha1.OperationPlan{}).
+		Owns(&upmv1alpha1.GrpcCall{}).
+		Complete(r)
+}
@@ -0,0 +1,416 @@
+/*
+ * UPM for Enterprise
+ *
+ * Copyright (c) 2009-2025 SYNTROPY Pte. Ltd.
+ * All rights reserved.
+ *
+ * This software is the confidential and proprietary information of
+ * SYNTROPY Pte. Ltd. ("Confidential Information"). You shall not
+ * disclose such Confidential Information and shall use it only in
+ * accordance with the terms of the license agreement you entered
+ * into with SYNTROPY.
+ */
+
+package operationplan
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+)
+
+func newTestReconciler(t *testing.T, objects ...client.Object) (*ReconcileOperationPlan, client.Client) {
+	t.Helper()
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, upmv1alpha1.AddToScheme(s))
+	require.NoError(t, upmv1alpha2.AddToScheme(s))
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).
+		WithStatusSubresource(&upmv1alpha1.OperationPlan{}, &upmv1alpha1.GrpcCall{}, &upmv1alpha2.Unit{}).Build()
+
+	return &ReconcileOperationPlan{client: c, scheme: s, recorder: record.NewFakeRecorder(100)}, c
+}
+
+func grpcCallStep(name string, action upmv1alpha1.Action) upmv1alpha1.OperationStep {
+	return upmv1alpha1.OperationStep{
+		Name: name,
+		OperationAction: upmv1alpha1.OperationAction{
+			GrpcCall: &upmv1alpha1.GrpcCallTemplate{TargetUnit: "mysql-0", Type: upmv1alpha1.MysqlType, Action: action},
+		},
+	}
+}
+
+func lifecycleStep(name string, action upmv1alpha1.LifecycleActionType) upmv1alpha1.OperationStep {
+	return upmv1alpha1.OperationStep{
+		Name: name,
+		OperationAction: upmv1alpha1.OperationAction{
+			Lifecycle: &upmv1alpha1.LifecycleAction{Unit: "mysql-0", Action: action},
+		},
+	}
+}
+
+func newTestPlan(steps ...upmv1alpha1.OperationStep) *upmv1alpha1.OperationPlan {
+	return &upmv1alpha1.OperationPlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "default", UID: "plan-uid"},
+		Spec:       upmv1alpha1.OperationPlanSpec{Steps: steps},
+	}
+}
+
+func reconcilePlan(t *testing.T, r *ReconcileOperationPlan, c client.Client) *upmv1alpha1.OperationPlan {
+	t.Helper()
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "restore", Namespace: "default"}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	plan := &upmv1alpha1.OperationPlan{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, plan))
+	return plan
+}
+
+func finishGrpcCall(t *testing.T, c client.Client, name string, phase upmv1alpha1.GrpcCallPhase, message string) {
+	t.Helper()
+
+	grpcCall := &upmv1alpha1.GrpcCall{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, grpcCall))
+
+	grpcCall.Status.Phase = phase
+	grpcCall.Status.Message = message
+	if phase == upmv1alpha1.GrpcCallSucceeded {
+		grpcCall.Status.Outputs = map[string]apiextensionsv1.JSON{"position": {Raw: []byte(`"mysql-bin.000003:154"`)}}
+	}
+	require.NoError(t, c.Status().Update(context.Background(), grpcCall))
+}
+
+func setProcessState(t *testing.T, c client.Client, state string, phase upmv1alpha2.UnitPhase) {
+	t.Helper()
+
+	unit := &upmv1alpha2.Unit{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "mysql-0", Namespace: "default"}, unit))
+
+	unit.Status.ProcessState = state
+	unit.Status.Phase = phase
+	require.NoError(t, c.Status().Update(context.Background(), unit))
+}
+
+func TestPlanStages(t *testing.T) {
+	steps := []upmv1alpha1.OperationStep{
+		{Name: "a"}, {Name: "b", Parallel: true}, {Name: "c"}, {Name: "d"}, {Name: "e", Parallel: true},
+	}
+	assert.Equal(t, [][]int{{0, 1}, {2}, {3, 4}}, planStages(steps))
+}
+
+func TestShouldRun(t *testing.T) {
+	assert.True(t, shouldRun("", false))
+	assert.False(t, shouldRun(upmv1alpha1.SucceededCondition, true))
+	assert.True(t, shouldRun(upmv1alpha1.FailedCondition, true))
+	assert.False(t, shouldRun(upmv1alpha1.FailedCondition, false))
+	assert.True(t, shouldRun(upmv1alpha1.AlwaysCondition, true))
+}
+
+func TestValidatePlan(t *testing.T) {
+	assert.NoError(t, validatePlan(newTestPlan(grpcCallStep("a", upmv1alpha1.RestoreAction), lifecycleStep("b", upmv1alpha1.StartLifecycleAction))))
+	assert.EqualError(t, validatePlan(newTestPlan(grpcCallStep("a", upmv1alpha1.RestoreAction), grpcCallStep("a", upmv1alpha1.GtidPurgeAction))),
+		`step name "a" is not unique`)
+	assert.EqualError(t, validatePlan(newTestPlan(upmv1alpha1.OperationStep{Name: "a"})),
+		"step a: one of grpcCall and lifecycle must be set")
+
+	assert.EqualError(t, validatePlan(newTestPlan(grpcCallStep("a", upmv1alpha1.RestoreAction), grpcCallStep("a-rollback", upmv1alpha1.GtidPurgeAction))),
+		`step name "a-rollback" must not end with "-rollback"`)
+
+	long := newTestPlan(grpcCallStep("a", upmv1alpha1.RestoreAction))
+	long.Name = strings.Repeat("a", 64)
+	assert.ErrorContains(t, validatePlan(long), "is not a valid label value")
+
+	step := grpcCallStep("a", upmv1alpha1.RestoreAction)
+	step.Rollback = &upmv1alpha1.OperationAction{GrpcCall: &upmv1alpha1.GrpcCallTemplate{Type: upmv1alpha1.MysqlType}}
+	assert.EqualError(t, validatePlan(newTestPlan(step)),
+		"rollback of step a: exactly one of grpcCall.targetUnit and grpcCall.targetUnitSet must be set")
+}
+
+func TestReconcileOperationPlan_RunsStepsInOrder(t *testing.T) {
+	unit := &upmv1alpha2.Unit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "mysql-0",
+			Namespace:   "default",
+			Annotations: map[string]string{upmv1alpha2.AnnotationMainContainerName: "mysql"},
+		},
+		Spec:   upmv1alpha2.UnitSpec{Startup: true},
+		Status: upmv1alpha2.UnitStatus{ProcessState: "starting", Phase: upmv1alpha2.UnitReady},
+	}
+
+	start := lifecycleStep("start", upmv1alpha1.StartLifecycleAction)
+	start.When = upmv1alpha1.AlwaysCondition
+	plan := newTestPlan(
+		lifecycleStep("stop", upmv1alpha1.StopLifecycleAction),
+		grpcCallStep("restore", upmv1alpha1.RestoreAction),
+		start,
+	)
+
+	r, c := newTestReconciler(t, unit, plan)
+	ctx := context.Background()
+
+	got := reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanRunning, got.Status.Phase)
+	assert.Equal(t, upmv1alpha1.StepRunning, got.Status.Steps[0].Phase)
+	assert.Equal(t, "restore-stop", got.Status.Steps[0].GrpcCall)
+	assert.Equal(t, upmv1alpha1.StepPending, got.Status.Steps[1].Phase)
+	assert.Equal(t, "running stop", got.Status.Message)
+
+	// the process is stopped through a GrpcCall, leaving spec.startup as is
+	grpcCall := &upmv1alpha1.GrpcCall{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "restore-stop", Namespace: "default"}, grpcCall))
+	assert.Equal(t, "mysql-0", grpcCall.Spec.TargetUnit)
+	assert.Equal(t, upmv1alpha1.MysqlType, grpcCall.Spec.Type)
+	assert.Equal(t, upmv1alpha1.StopAction, grpcCall.Spec.Action)
+	assert.Equal(t, int32(defaultLifecycleTimeoutSeconds), *grpcCall.Spec.TimeoutSeconds)
+	assert.True(t, metav1.IsControlledBy(grpcCall, got))
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "mysql-0", Namespace: "default"}, unit))
+	assert.True(t, unit.Spec.Startup)
+
+	// the step waits for the unit to report the process stopped
+	finishGrpcCall(t, c, "restore-stop", upmv1alpha1.GrpcCallSucceeded, "stop mysql successfully")
+	got = reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.StepRunning, got.Status.Steps[0].Phase)
+	assert.Equal(t, "waiting for the process of unit [mysql-0] to be stopped", got.Status.Steps[0].Message)
+
+	// the restore starts once the unit controller reports the process stopped
+	setProcessState(t, c, "unknown", upmv1alpha2.UnitReady)
+	got = reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.StepSucceeded, got.Status.Steps[0].Phase)
+	assert.Empty(t, got.Status.Steps[0].Outputs)
+	assert.Equal(t, upmv1alpha1.StepRunning, got.Status.Steps[1].Phase)
+	assert.Equal(t, "restore-restore", got.Status.Steps[1].GrpcCall)
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "restore-restore", Namespace: "default"}, grpcCall))
+	assert.Equal(t, "mysql-0", grpcCall.Spec.TargetUnit)
+	assert.Equal(t, upmv1alpha1.RestoreAction, grpcCall.Spec.Action)
+	assert.Equal(t, "restore", grpcCall.Labels[LabelOperationPlan])
+	assert.True(t, metav1.IsControlledBy(grpcCall, got))
+
+	finishGrpcCall(t, c, "restore-restore", upmv1alpha1.GrpcCallSucceeded, "restore mysql successfully")
+	got = reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.StepSucceeded, got.Status.Steps[1].Phase)
+	assert.Contains(t, got.Status.Steps[1].Outputs, "position")
+	assert.Equal(t, upmv1alpha1.StepRunning, got.Status.Steps[2].Phase)
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "restore-start", Namespace: "default"}, grpcCall))
+	assert.Equal(t, upmv1alpha1.StartAction, grpcCall.Spec.Action)
+
+	finishGrpcCall(t, c, "restore-start", upmv1alpha1.GrpcCallSucceeded, "start mysql successfully")
+	setProcessState(t, c, "starting", upmv1alpha2.UnitReady)
+	got = reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanSucceeded, got.Status.Phase)
+	assert.Equal(t, "3 steps succeeded", got.Status.Message)
+	assert.NotNil(t, got.Status.CompletionTime)
+}
+
+func TestReconcileOperationPlan_LifecycleFailsWithGrpcCall(t *testing.T) {
+	unit := &upmv1alpha2.Unit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "mysql-0",
+			Namespace:   "default",
+			Annotations: map[string]string{upmv1alpha2.AnnotationMainContainerName: "mysql"},
+		},
+	}
+	plan := newTestPlan(lifecycleStep("start", upmv1alpha1.StartLifecycleAction))
+	r, c := newTestReconciler(t, unit, plan)
+
+	reconcilePlan(t, r, c)
+	finishGrpcCall(t, c, "restore-start", upmv1alpha1.GrpcCallFailed,
+		"unit [mysql-0] spec.startup is false, the unit controller would stop the process again")
+
+	got := reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanFailed, got.Status.Phase)
+	assert.Equal(t, upmv1alpha1.StepFailed, got.Status.Steps[0].Phase)
+	assert.Contains(t, got.Status.Steps[0].Message, "spec.startup is false")
+}
+
+func TestReconcileOperationPlan_LifecycleUnitWithoutType(t *testing.T) {
+	unit := &upmv1alpha2.Unit{ObjectMeta: metav1.ObjectMeta{Name: "mysql-0", Namespace: "default"}}
+	plan := newTestPlan(lifecycleStep("stop", upmv1alpha1.StopLifecycleAction))
+	r, c := newTestReconciler(t, unit, plan)
+
+	got := reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanFailed, got.Status.Phase)
+	assert.Equal(t, upmv1alpha1.StepFailed, got.Status.Steps[0].Phase)
+	assert.Contains(t, got.Status.Steps[0].Message, "is missing annotation")
+	assert.Empty(t, got.Status.Steps[0].GrpcCall)
+}
+
+func TestReconcileOperationPlan_RunsParallelSteps(t *testing.T) {
+	second := grpcCallStep("second", upmv1alpha1.LogicalBackupAction)
+	second.Parallel = true
+	plan := newTestPlan(grpcCallStep("first", upmv1alpha1.PhysicalBackupAction), second, grpcCallStep("third", upmv1alpha1.GtidPurgeAction))
+
+	r, c := newTestReconciler(t, plan)
+
+	got := reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.StepRunning, got.Status.Steps[0].Phase)
+	assert.Equal(t, upmv1alpha1.StepRunning, got.Status.Steps[1].Phase)
+	assert.Equal(t, upmv1alpha1.StepPending, got.Status.Steps[2].Phase)
+	assert.Equal(t, "running first, second", got.Status.Message)
+
+	// a failure with the Continue policy does not stop the plan
+	finishGrpcCall(t, c, "restore-first", upmv1alpha1.GrpcCallSucceeded, "")
+	got = reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.StepPending, got.Status.Steps[2].Phase)
+
+	got.Spec.Steps[1].OnFailure = upmv1alpha1.ContinuePolicy
+	require.NoError(t, c.Update(context.Background(), got))
+	finishGrpcCall(t, c, "restore-second", upmv1alpha1.GrpcCallFailed, "unit agent unavailable")
+	got = reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.StepFailed, got.Status.Steps[1].Phase)
+	assert.Equal(t, upmv1alpha1.StepRunning, got.Status.Steps[2].Phase)
+
+	finishGrpcCall(t, c, "restore-third", upmv1alpha1.GrpcCallSucceeded, "")
+	got = reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanSucceeded, got.Status.Phase)
+	assert.Equal(t, "2 steps succeeded, 1 failed and continued: second", got.Status.Message)
+}
+
+func TestReconcileOperationPlan_RollsBackSucceededSteps(t *testing.T) {
+	first := grpcCallStep("set-variable", upmv1alpha1.SetVariableAction)
+	first.Rollback = &upmv1alpha1.OperationAction{
+		GrpcCall: &upmv1alpha1.GrpcCallTemplate{TargetUnit: "mysql-0", Type: upmv1alpha1.MysqlType, Action: upmv1alpha1.SetVariableAction},
+	}
+	second := grpcCallStep("restore", upmv1alpha1.RestoreAction)
+	second.OnFailure = upmv1alpha1.RollbackPolicy
+	diagnose := grpcCallStep("diagnose", upmv1alpha1.LogicalBackupAction)
+	diagnose.When = upmv1alpha1.FailedCondition
+	plan := newTestPlan(first, second, grpcCallStep("purge", upmv1alpha1.GtidPurgeAction), diagnose)
+
+	r, c := newTestReconciler(t, plan)
+	ctx := context.Background()
+
+	reconcilePlan(t, r, c)
+	finishGrpcCall(t, c, "restore-set-variable", upmv1alpha1.GrpcCallSucceeded, "")
+	reconcilePlan(t, r, c)
+	finishGrpcCall(t, c, "restore-restore", upmv1alpha1.GrpcCallFailed, "backup not found")
+
+	got := reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.StepFailed, got.Status.Steps[1].Phase)
+	assert.Equal(t, upmv1alpha1.StepSkipped, got.Status.Steps[2].Phase)
+	assert.Equal(t, upmv1alpha1.StepRunning, got.Status.Steps[3].Phase)
+
+	err := c.Get(ctx, types.NamespacedName{Name: "restore-purge", Namespace: "default"}, &upmv1alpha1.GrpcCall{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	finishGrpcCall(t, c, "restore-diagnose", upmv1alpha1.GrpcCallSucceeded, "")
+	got = reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanRollingBack, got.Status.Phase)
+	require.NotNil(t, got.Status.Steps[0].Rollback)
+	assert.Equal(t, upmv1alpha1.StepRunning, got.Status.Steps[0].Rollback.Phase)
+	assert.Equal(t, "restore-set-variable-rollback", got.Status.Steps[0].Rollback.GrpcCall)
+
+	finishGrpcCall(t, c, "restore-set-variable-rollback", upmv1alpha1.GrpcCallSucceeded, "")
+	got = reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanFailed, got.Status.Phase)
+	assert.Equal(t, "1 steps failed: restore: backup not found, rolled back 1 steps", got.Status.Message)
+}
+
+func TestReconcileOperationPlan_CancelledStepAbortsPlan(t *testing.T) {
+	plan := newTestPlan(grpcCallStep("restore", upmv1alpha1.RestoreAction), grpcCallStep("purge", upmv1alpha1.GtidPurgeAction))
+	r, c := newTestReconciler(t, plan)
+	ctx := context.Background()
+
+	reconcilePlan(t, r, c)
+	finishGrpcCall(t, c, "restore-restore", upmv1alpha1.GrpcCallCancelled, "grpc call is suspended")
+
+	got := reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanFailed, got.Status.Phase)
+	assert.Equal(t, upmv1alpha1.StepCancelled, got.Status.Steps[0].Phase)
+	assert.Equal(t, upmv1alpha1.StepSkipped, got.Status.Steps[1].Phase)
+	assert.Equal(t, "1 steps failed: restore: cancelled, grpc call is suspended", got.Status.Message)
+
+	err := c.Get(ctx, types.NamespacedName{Name: "restore-purge", Namespace: "default"}, &upmv1alpha1.GrpcCall{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileOperationPlan_CancelledStepContinues(t *testing.T) {
+	restore := grpcCallStep("restore", upmv1alpha1.RestoreAction)
+	restore.OnFailure = upmv1alpha1.ContinuePolicy
+	plan := newTestPlan(restore, grpcCallStep("purge", upmv1alpha1.GtidPurgeAction))
+	r, c := newTestReconciler(t, plan)
+
+	reconcilePlan(t, r, c)
+	finishGrpcCall(t, c, "restore-restore", upmv1alpha1.GrpcCallCancelled, "operation cancelled")
+	reconcilePlan(t, r, c)
+	finishGrpcCall(t, c, "restore-purge", upmv1alpha1.GrpcCallSucceeded, "")
+
+	got := reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanSucceeded, got.Status.Phase)
+	assert.Equal(t, "1 steps succeeded, 1 failed and continued: restore", got.Status.Message)
+}
+
+func TestReconcileOperationPlan_GrpcCallOwnedByAnother(t *testing.T) {
+	leftover := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "restore-restore",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: upmv1alpha1.GroupVersion.String(),
+				Kind:       "OperationPlan",
+				Name:       "restore",
+				UID:        "deleted-plan-uid",
+				Controller: ptr.To(true),
+			}},
+		},
+		Status: upmv1alpha1.GrpcCallStatus{Phase: upmv1alpha1.GrpcCallSucceeded},
+	}
+	plan := newTestPlan(grpcCallStep("restore", upmv1alpha1.RestoreAction))
+	r, c := newTestReconciler(t, leftover, plan)
+
+	got := reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanFailed, got.Status.Phase)
+	assert.Equal(t, upmv1alpha1.StepFailed, got.Status.Steps[0].Phase)
+	assert.Equal(t, "grpc call [restore-restore] already exists and is not owned by this operation plan", got.Status.Steps[0].Message)
+}
+
+func TestReconcileOperationPlan_Suspended(t *testing.T) {
+	plan := newTestPlan(grpcCallStep("restore", upmv1alpha1.RestoreAction), grpcCallStep("purge", upmv1alpha1.GtidPurgeAction))
+	r, c := newTestReconciler(t, plan)
+	ctx := context.Background()
+
+	got := reconcilePlan(t, r, c)
+	got.Spec.Suspend = true
+	require.NoError(t, c.Update(ctx, got))
+
+	got = reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanCancelled, got.Status.Phase)
+	assert.Equal(t, upmv1alpha1.StepCancelled, got.Status.Steps[0].Phase)
+	assert.Equal(t, upmv1alpha1.StepSkipped, got.Status.Steps[1].Phase)
+
+	grpcCall := &upmv1alpha1.GrpcCall{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "restore-restore", Namespace: "default"}, grpcCall))
+	assert.True(t, grpcCall.Spec.Suspend)
+}
+
+func TestReconcileOperationPlan_InvalidPlan(t *testing.T) {
+	plan := newTestPlan(upmv1alpha1.OperationStep{Name: "restore"})
+	r, c := newTestReconciler(t, plan)
+
+	got := reconcilePlan(t, r, c)
+	assert.Equal(t, upmv1alpha1.OperationPlanFailed, got.Status.Phase)
+	assert.Equal(t, "step restore: one of grpcCall and lifecycle must be set", got.Status.Message)
+}
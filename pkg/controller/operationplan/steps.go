@@ -0,0 +1,427 @@
+package operationplan
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	upmv1alpha1 "github.com/upmio/unit-operator/api/v1alpha1"
+	upmv1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+)
+
+const (
+	defaultLifecycleTimeoutSeconds = 300
+
+	// rollbackSuffix names the GrpcCall of the rollback of a step
+	rollbackSuffix = "-rollback"
+)
+
+// validatePlan checks the steps of the plan before any of them starts.
+func validatePlan(instance *upmv1alpha1.OperationPlan) error {
+	// the GrpcCalls of the plan are labelled with its name
+	if errs := validation.IsValidLabelValue(instance.Name); len(errs) > 0 {
+		return fmt.Errorf("plan name %q is not a valid label value: %s", instance.Name, strings.Join(errs, "; "))
+	}
+
+	names := make(map[string]bool, len(instance.Spec.Steps))
+	for _, step := range instance.Spec.Steps {
+		if names[step.Name] {
+			return fmt.Errorf("step name %q is not unique", step.Name)
+		}
+		names[step.Name] = true
+
+		// the GrpcCall of a rollback is named after its step with this suffix
+		if strings.HasSuffix(step.Name, rollbackSuffix) {
+			return fmt.Errorf("step name %q must not end with %q", step.Name, rollbackSuffix)
+		}
+
+		if err := validateAction(&step.OperationAction); err != nil {
+			return fmt.Errorf("step %s: %v", step.Name, err)
+		}
+
+		if step.Rollback != nil {
+			if err := validateAction(step.Rollback); err != nil {
+				return fmt.Errorf("rollback of step %s: %v", step.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateAction(action *upmv1alpha1.OperationAction) error {
+	switch {
+	case action.GrpcCall == nil && action.Lifecycle == nil:
+		return fmt.Errorf("one of grpcCall and lifecycle must be set")
+	case action.GrpcCall != nil && action.Lifecycle != nil:
+		return fmt.Errorf("grpcCall and lifecycle are mutually exclusive")
+	case action.GrpcCall != nil && (action.GrpcCall.TargetUnit == "") == (action.GrpcCall.TargetUnitSet == nil):
+		return fmt.Errorf("exactly one of grpcCall.targetUnit and grpcCall.targetUnitSet must be set")
+	case action.GrpcCall != nil && (action.GrpcCall.Action == "") == (action.GrpcCall.Method == ""):
+		return fmt.Errorf("exactly one of grpcCall.action and grpcCall.method must be set")
+	}
+
+	return nil
+}
+
+// planStages groups the indexes of the steps running together, in order.
+func planStages(steps []upmv1alpha1.OperationStep) [][]int {
+	var stages [][]int
+	for i, step := range steps {
+		if !step.Parallel || len(stages) == 0 {
+			stages = append(stages, nil)
+		}
+		stages[len(stages)-1] = append(stages[len(stages)-1], i)
+	}
+
+	return stages
+}
+
+// shouldRun reports whether a step with the condition runs, depending on
+// whether a step before it aborted the plan.
+func shouldRun(when upmv1alpha1.StepCondition, aborted bool) bool {
+	switch when {
+	case upmv1alpha1.AlwaysCondition:
+		return true
+	case upmv1alpha1.FailedCondition:
+		return aborted
+	default:
+		return !aborted
+	}
+}
+
+// stepObjectName is the name of the GrpcCall running a step of the plan.
+func stepObjectName(instance *upmv1alpha1.OperationPlan, step string) string {
+	return fmt.Sprintf("%s-%s", instance.Name, step)
+}
+
+func stageStepNames(instance *upmv1alpha1.OperationPlan, stage []int) []string {
+	names := make([]string, 0, len(stage))
+	for _, i := range stage {
+		if instance.Status.Steps[i].Phase == upmv1alpha1.StepRunning {
+			names = append(names, instance.Spec.Steps[i].Name)
+		}
+	}
+
+	return names
+}
+
+// minRequeue requeues the plan after the shortest of the delays.
+func minRequeue(result reconcile.Result, requeueAfter time.Duration) reconcile.Result {
+	if requeueAfter > 0 && (result.RequeueAfter == 0 || requeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = requeueAfter
+	}
+
+	return result
+}
+
+// failureMessage summarizes the failed steps and rollbacks of an aborted plan.
+func failureMessage(instance *upmv1alpha1.OperationPlan, rollback bool) string {
+	var failed, rollbackFailed []string
+	rolledBack := 0
+	for _, stepStatus := range instance.Status.Steps {
+		if actionFailed(stepStatus.Phase) {
+			failed = append(failed, fmt.Sprintf("%s: %s", stepStatus.Name, failureDetail(&stepStatus.OperationActionStatus)))
+		}
+
+		if stepStatus.Rollback == nil {
+			continue
+		}
+		switch {
+		case stepStatus.Rollback.Phase == upmv1alpha1.StepSucceeded:
+			rolledBack++
+		case actionFailed(stepStatus.Rollback.Phase):
+			rollbackFailed = append(rollbackFailed, fmt.Sprintf("%s: %s", stepStatus.Name, failureDetail(stepStatus.Rollback)))
+		}
+	}
+
+	message := fmt.Sprintf("%d steps failed: %s", len(failed), strings.Join(failed, "; "))
+	if rollback {
+		message += fmt.Sprintf(", rolled back %d steps", rolledBack)
+	}
+	if len(rollbackFailed) > 0 {
+		message += fmt.Sprintf(", %d rollbacks failed: %s", len(rollbackFailed), strings.Join(rollbackFailed, "; "))
+	}
+
+	return message
+}
+
+// successMessage summarizes a plan which was not aborted.
+func successMessage(instance *upmv1alpha1.OperationPlan) string {
+	succeeded, skipped := 0, 0
+	var failed []string
+	for _, stepStatus := range instance.Status.Steps {
+		switch {
+		case stepStatus.Phase == upmv1alpha1.StepSucceeded:
+			succeeded++
+		case stepStatus.Phase == upmv1alpha1.StepSkipped:
+			skipped++
+		case actionFailed(stepStatus.Phase):
+			failed = append(failed, stepStatus.Name)
+		}
+	}
+
+	message := fmt.Sprintf("%d steps succeeded", succeeded)
+	if skipped > 0 {
+		message += fmt.Sprintf(", %d skipped", skipped)
+	}
+	if len(failed) > 0 {
+		message += fmt.Sprintf(", %d failed and continued: %s", len(failed), strings.Join(failed, ", "))
+	}
+
+	return message
+}
+
+// actionFailed reports whether an action failed or was cancelled. A plan only
+// cancels its own actions when it is suspended, which finishes it at once, so
+// a cancelled action seen while the plan runs was cancelled outside of it.
+func actionFailed(phase upmv1alpha1.StepPhase) bool {
+	return phase == upmv1alpha1.StepFailed || phase == upmv1alpha1.StepCancelled
+}
+
+func failureDetail(state *upmv1alpha1.OperationActionStatus) string {
+	if state.Phase == upmv1alpha1.StepCancelled {
+		return fmt.Sprintf("cancelled, %s", state.Message)
+	}
+
+	return state.Message
+}
+
+// startAction starts the action of a step, or of its rollback.
+func (r *ReconcileOperationPlan) startAction(
+	ctx context.Context,
+	instance *upmv1alpha1.OperationPlan,
+	action *upmv1alpha1.OperationAction,
+	state *upmv1alpha1.OperationActionStatus,
+	name string,
+) error {
+	now := metav1.Now()
+	state.Phase = upmv1alpha1.StepRunning
+	state.StartTime = &now
+
+	template := action.GrpcCall
+	if action.Lifecycle != nil {
+		unit := &upmv1alpha2.Unit{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: action.Lifecycle.Unit, Namespace: instance.Namespace}, unit); err != nil {
+			if apierrors.IsNotFound(err) {
+				completeAction(state, upmv1alpha1.StepFailed, fmt.Sprintf("unit [%s] not found", action.Lifecycle.Unit))
+				return nil
+			}
+			return fmt.Errorf("failed to fetch unit [%s]: %v", action.Lifecycle.Unit, err)
+		}
+
+		unitType := unit.Annotations[upmv1alpha2.AnnotationMainContainerName]
+		if unitType == "" {
+			completeAction(state, upmv1alpha1.StepFailed, fmt.Sprintf("unit [%s] is missing annotation %q for its type",
+				unit.Name, upmv1alpha2.AnnotationMainContainerName))
+			return nil
+		}
+
+		template = lifecycleGrpcCall(action.Lifecycle, upmv1alpha1.UnitType(unitType))
+	}
+
+	grpcCall := &upmv1alpha1.GrpcCall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels:    map[string]string{LabelOperationPlan: instance.Name},
+		},
+		Spec: upmv1alpha1.GrpcCallSpec{
+			TargetUnit:     template.TargetUnit,
+			TargetUnitSet:  template.TargetUnitSet.DeepCopy(),
+			Type:           template.Type,
+			Action:         template.Action,
+			Method:         template.Method,
+			TimeoutSeconds: template.TimeoutSeconds,
+			RetryPolicy:    template.RetryPolicy.DeepCopy(),
+			Parameters:     template.Parameters,
+		},
+	}
+	if grpcCall.Spec.Parameters == nil {
+		grpcCall.Spec.Parameters = map[string]apiextensionsv1.JSON{}
+	}
+
+	if err := controllerutil.SetControllerReference(instance, grpcCall, r.scheme); err != nil {
+		return fmt.Errorf("failed to set owner of grpc call [%s]: %v", name, err)
+	}
+
+	// a step started again after an operator restart finds its GrpcCall
+	if err := r.client.Create(ctx, grpcCall); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create grpc call [%s]: %v", name, err)
+		}
+
+		existing := &upmv1alpha1.GrpcCall{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, existing); err != nil {
+			return fmt.Errorf("failed to fetch grpc call [%s]: %v", name, err)
+		}
+
+		if owner := metav1.GetControllerOf(existing); owner == nil || owner.UID != instance.UID {
+			completeAction(state, upmv1alpha1.StepFailed, fmt.Sprintf("grpc call [%s] already exists and is not owned by this operation plan", name))
+			return nil
+		}
+	}
+
+	state.GrpcCall = name
+	state.Message = fmt.Sprintf("%s %s started", cmp.Or(string(template.Action), template.Method), template.Type)
+	return nil
+}
+
+// progressAction records the progress of a running action, returning when
+// to check it again if it does not notify the plan of its progress.
+func (r *ReconcileOperationPlan) progressAction(
+	ctx context.Context,
+	instance *upmv1alpha1.OperationPlan,
+	action *upmv1alpha1.OperationAction,
+	state *upmv1alpha1.OperationActionStatus,
+) (time.Duration, error) {
+	grpcCall := &upmv1alpha1.GrpcCall{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: state.GrpcCall, Namespace: instance.Namespace}, grpcCall); err != nil {
+		if apierrors.IsNotFound(err) {
+			completeAction(state, upmv1alpha1.StepFailed, fmt.Sprintf("grpc call [%s] not found", state.GrpcCall))
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to fetch grpc call [%s]: %v", state.GrpcCall, err)
+	}
+
+	switch grpcCall.Status.Phase {
+	case upmv1alpha1.GrpcCallSucceeded:
+		if action.Lifecycle != nil {
+			return r.progressLifecycle(ctx, instance, action.Lifecycle, state)
+		}
+		state.Outputs = grpcCall.Status.Outputs
+		completeAction(state, upmv1alpha1.StepSucceeded, grpcCall.Status.Message)
+	case upmv1alpha1.GrpcCallFailed:
+		completeAction(state, upmv1alpha1.StepFailed, grpcCall.Status.Message)
+	case upmv1alpha1.GrpcCallCancelled:
+		completeAction(state, upmv1alpha1.StepCancelled, grpcCall.Status.Message)
+	default:
+		if grpcCall.Status.Message != "" {
+			state.Message = grpcCall.Status.Message
+		}
+	}
+
+	// the owned GrpcCall requeues the plan as it progresses
+	return 0, nil
+}
+
+// progressLifecycle waits for the unit to report its process started and the
+// unit ready, or its process stopped, failing the action after its timeout.
+func (r *ReconcileOperationPlan) progressLifecycle(
+	ctx context.Context,
+	instance *upmv1alpha1.OperationPlan,
+	lifecycle *upmv1alpha1.LifecycleAction,
+	state *upmv1alpha1.OperationActionStatus,
+) (time.Duration, error) {
+	unit := &upmv1alpha2.Unit{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: lifecycle.Unit, Namespace: instance.Namespace}, unit); err != nil {
+		if apierrors.IsNotFound(err) {
+			completeAction(state, upmv1alpha1.StepFailed, fmt.Sprintf("unit [%s] not found", lifecycle.Unit))
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to fetch unit [%s]: %v", lifecycle.Unit, err)
+	}
+
+	want := lifecycleState(lifecycle)
+	if lifecycleDone(lifecycle, unit) {
+		completeAction(state, upmv1alpha1.StepSucceeded, fmt.Sprintf("process of unit [%s] is %s", unit.Name, want))
+		return 0, nil
+	}
+
+	timeout := time.Duration(lifecycle.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultLifecycleTimeoutSeconds * time.Second
+	}
+
+	if remaining := time.Until(state.StartTime.Add(timeout)); remaining > 0 {
+		state.Message = fmt.Sprintf("waiting for the process of unit [%s] to be %s", unit.Name, want)
+		return min(remaining, lifecyclePollInterval), nil
+	}
+
+	completeAction(state, upmv1alpha1.StepFailed, fmt.Sprintf("process of unit [%s] is not %s after %s, process state %q, unit phase %q",
+		unit.Name, want, timeout, unit.Status.ProcessState, unit.Status.Phase))
+	return 0, nil
+}
+
+// cancelAction cancels a running action. Its GrpcCall is suspended, which
+// cancels its unit-agent operation.
+func (r *ReconcileOperationPlan) cancelAction(
+	ctx context.Context,
+	instance *upmv1alpha1.OperationPlan,
+	state *upmv1alpha1.OperationActionStatus,
+) error {
+	if state.GrpcCall != "" {
+		grpcCall := &upmv1alpha1.GrpcCall{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: state.GrpcCall, Namespace: instance.Namespace}, grpcCall)
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return fmt.Errorf("failed to fetch grpc call [%s]: %v", state.GrpcCall, err)
+		case !grpcCall.Spec.Suspend:
+			grpcCall.Spec.Suspend = true
+			if err := r.client.Update(ctx, grpcCall); err != nil {
+				return fmt.Errorf("failed to suspend grpc call [%s]: %v", state.GrpcCall, err)
+			}
+		}
+	}
+
+	completeAction(state, upmv1alpha1.StepCancelled, "operation plan is suspended")
+	return nil
+}
+
+func completeAction(state *upmv1alpha1.OperationActionStatus, phase upmv1alpha1.StepPhase, message string) {
+	now := metav1.Now()
+	state.Phase = phase
+	state.Message = message
+	state.CompletionTime = &now
+}
+
+// lifecycleGrpcCall is the start or stop GrpcCall running a lifecycle action,
+// which holds the process in that state until another start or stop.
+func lifecycleGrpcCall(lifecycle *upmv1alpha1.LifecycleAction, unitType upmv1alpha1.UnitType) *upmv1alpha1.GrpcCallTemplate {
+	action := upmv1alpha1.StopAction
+	if lifecycle.Action == upmv1alpha1.StartLifecycleAction {
+		action = upmv1alpha1.StartAction
+	}
+
+	timeoutSeconds := lifecycle.TimeoutSeconds
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = defaultLifecycleTimeoutSeconds
+	}
+
+	return &upmv1alpha1.GrpcCallTemplate{
+		TargetUnit:     lifecycle.Unit,
+		Type:           unitType,
+		Action:         action,
+		TimeoutSeconds: &timeoutSeconds,
+	}
+}
+
+func lifecycleState(lifecycle *upmv1alpha1.LifecycleAction) string {
+	if lifecycle.Action == upmv1alpha1.StartLifecycleAction {
+		return "started"
+	}
+
+	return "stopped"
+}
+
+// lifecycleDone reports whether the unit reached the state of the lifecycle
+// action, as the unit controller tells a started process apart.
+func lifecycleDone(lifecycle *upmv1alpha1.LifecycleAction, unit *upmv1alpha2.Unit) bool {
+	started := unit.Status.ProcessState == "running" || unit.Status.ProcessState == "starting"
+	if lifecycle.Action == upmv1alpha1.StartLifecycleAction {
+		return started && unit.Status.Phase == upmv1alpha2.UnitReady
+	}
+
+	return !started
+}
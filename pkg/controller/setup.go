@@ -14,7 +14,9 @@
 package controller
 
 import (
+	"github.com/upmio/unit-operator/pkg/controller/database"
 	"github.com/upmio/unit-operator/pkg/controller/grpccall"
+	"github.com/upmio/unit-operator/pkg/controller/operationplan"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -24,6 +26,8 @@ import (
 func Setup(mgr ctrl.Manager) error {
 	for _, setup := range []func(ctrl.Manager) error{
 		grpccall.Setup,
+		operationplan.Setup,
+		database.Setup,
 	} {
 		if err := setup(mgr); err != nil {
 			return err
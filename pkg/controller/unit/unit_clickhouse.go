@@ -0,0 +1,169 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/clickhouse"
+	"github.com/upmio/unit-operator/pkg/vars"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ClickHouseReplicasHealthyCondition reports readonly, lagging or queued replicated tables
+	ClickHouseReplicasHealthyCondition = "ClickHouseReplicasHealthy"
+	// ClickHouseKeeperConnectedCondition reports the Keeper/ZooKeeper session of the server
+	ClickHouseKeeperConnectedCondition = "ClickHouseKeeperConnected"
+	// ClickHouseDisksHealthyCondition reports disks running out of free space
+	ClickHouseDisksHealthyCondition = "ClickHouseDisksHealthy"
+
+	clickHouseType = "clickhouse"
+	// clickHouseDefaultUser is used when the UnitSet sets no ADM_USER
+	clickHouseDefaultUser = "admin"
+)
+
+// reconcileClickHouseHealth folds the health reported by the unit-agent of a ready ClickHouse unit
+// into the unit conditions. An unreachable agent turns the conditions Unknown rather than failing
+// the reconcile.
+func (r *UnitReconciler) reconcileClickHouseHealth(ctx context.Context, req ctrl.Request, unit *upmiov1alpha2.Unit) error {
+	if unit.Status.Phase != upmiov1alpha2.UnitReady {
+		return nil
+	}
+
+	unitsetName := unit.Labels[upmiov1alpha2.UnitsetName]
+	if unitsetName == "" {
+		return nil
+	}
+
+	unitset := &upmiov1alpha2.UnitSet{}
+	if err := r.Get(ctx, client.ObjectKey{Name: unitsetName, Namespace: req.Namespace}, unitset); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("get unitset [%s] failed: %v", unitsetName, err)
+	}
+
+	if unitset.Spec.Type != clickHouseType {
+		return nil
+	}
+
+	agentHost := unit.Name
+	if vars.UnitAgentHostType == "ip" {
+		if len(unit.Status.PodIPs) == 0 {
+			return nil
+		}
+		agentHost = unit.Status.PodIPs[0].IP
+	}
+
+	username := clickHouseDefaultUser
+	for _, env := range unitset.Spec.Env {
+		if env.Name == "ADM_USER" && env.Value != "" {
+			username = env.Value
+		}
+	}
+
+	agent := r.Agent
+	if agent == nil {
+		agent = defaultUnitAgentClient{}
+	}
+
+	health, err := agent.ClickHouseHealth(
+		vars.UnitAgentHostType,
+		upmiov1alpha2.UnitsetHeadlessSvcName(unit),
+		agentHost,
+		req.Namespace,
+		"2214",
+		username)
+	if err != nil {
+		klog.Errorf("get unit [%s] clickhouse health failed, error: [%s]", req.String(), err.Error())
+	}
+
+	conditions := clickHouseHealthConditions(health, err)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &upmiov1alpha2.Unit{}
+		if err := r.Get(ctx, client.ObjectKey{Name: unit.Name, Namespace: req.Namespace}, latest); err != nil {
+			return err
+		}
+
+		changed := false
+		for _, condition := range conditions {
+			condition.ObservedGeneration = latest.Generation
+			if meta.SetStatusCondition(&latest.Status.Conditions, condition) {
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+
+		if err := r.Status().Update(ctx, latest); err != nil {
+			return err
+		}
+
+		unit.Status.Conditions = latest.Status.Conditions
+		return nil
+	})
+}
+
+// clickHouseHealthConditions maps the health report, or the error getting it, to the unit conditions.
+func clickHouseHealthConditions(health *clickhouse.HealthResponse, err error) []metav1.Condition {
+	if err != nil {
+		conditions := make([]metav1.Condition, 0, 3)
+		for _, conditionType := range []string{
+			ClickHouseReplicasHealthyCondition,
+			ClickHouseKeeperConnectedCondition,
+			ClickHouseDisksHealthyCondition,
+		} {
+			conditions = append(conditions, metav1.Condition{
+				Type:    conditionType,
+				Status:  metav1.ConditionUnknown,
+				Reason:  "AgentUnavailable",
+				Message: err.Error(),
+			})
+		}
+		return conditions
+	}
+
+	keeper := healthCondition(ClickHouseKeeperConnectedCondition, health.GetKeeperProblems(), "Disconnected")
+	if keeper.Status == metav1.ConditionTrue {
+		keeper.Reason = "Connected"
+		keeper.Message = fmt.Sprintf("session to %s:%d", health.GetKeeper().GetHost(), health.GetKeeper().GetPort())
+		if !health.GetKeeper().GetConfigured() {
+			keeper.Status = metav1.ConditionFalse
+			keeper.Reason = "NotConfigured"
+			keeper.Message = "no keeper session is configured"
+		}
+	}
+
+	return []metav1.Condition{
+		healthCondition(ClickHouseReplicasHealthyCondition, health.GetReplicaProblems(), "ReplicasUnhealthy"),
+		keeper,
+		healthCondition(ClickHouseDisksHealthyCondition, health.GetDiskProblems(), "LowDiskSpace"),
+	}
+}
+
+func healthCondition(conditionType string, problems []string, reason string) metav1.Condition {
+	if len(problems) > 0 {
+		return metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: strings.Join(problems, "; "),
+		}
+	}
+
+	return metav1.Condition{
+		Type:   conditionType,
+		Status: metav1.ConditionTrue,
+		Reason: "Healthy",
+	}
+}
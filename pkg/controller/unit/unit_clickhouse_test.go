@@ -0,0 +1,122 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/clickhouse"
+	"github.com/upmio/unit-operator/pkg/vars"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// clickHouseHealthAgent reports a fixed health and records the username asked for.
+type clickHouseHealthAgent struct {
+	fakeUnitAgentClient
+
+	health   *clickhouse.HealthResponse
+	err      error
+	username string
+}
+
+func (a *clickHouseHealthAgent) ClickHouseHealth(_, _, _, _, _, username string) (*clickhouse.HealthResponse, error) {
+	a.username = username
+	return a.health, a.err
+}
+
+func TestReconcileClickHouseHealth(t *testing.T) {
+	vars.UnitAgentHostType = "domain"
+
+	agent := &clickHouseHealthAgent{health: &clickhouse.HealthResponse{
+		Keeper:          &clickhouse.KeeperSession{Configured: true, Host: "keeper-0", Port: 9181},
+		ReplicaProblems: []string{"readonly replicas [sales.orders]"},
+	}}
+	r, unit := newClickHouseHealthReconciler(t, "clickhouse", agent)
+
+	require.NoError(t, r.reconcileClickHouseHealth(context.Background(), unitRequest(unit), unit))
+	require.Equal(t, "operator", agent.username)
+
+	latest := &upmiov1alpha2.Unit{}
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(unit), latest))
+
+	replicas := meta.FindStatusCondition(latest.Status.Conditions, ClickHouseReplicasHealthyCondition)
+	require.NotNil(t, replicas)
+	require.Equal(t, metav1.ConditionFalse, replicas.Status)
+	require.Equal(t, "readonly replicas [sales.orders]", replicas.Message)
+	require.True(t, meta.IsStatusConditionTrue(latest.Status.Conditions, ClickHouseKeeperConnectedCondition))
+	require.True(t, meta.IsStatusConditionTrue(latest.Status.Conditions, ClickHouseDisksHealthyCondition))
+
+	agent.health, agent.err = nil, errors.New("connection refused")
+	require.NoError(t, r.reconcileClickHouseHealth(context.Background(), unitRequest(unit), unit))
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(unit), latest))
+	for _, condition := range latest.Status.Conditions {
+		require.Equal(t, metav1.ConditionUnknown, condition.Status, condition.Type)
+		require.Equal(t, "AgentUnavailable", condition.Reason)
+	}
+}
+
+func TestReconcileClickHouseHealthSkipsOtherTypes(t *testing.T) {
+	agent := &clickHouseHealthAgent{err: errors.New("unexpected call")}
+	r, unit := newClickHouseHealthReconciler(t, "mysql", agent)
+
+	require.NoError(t, r.reconcileClickHouseHealth(context.Background(), unitRequest(unit), unit))
+	require.Empty(t, agent.username)
+
+	latest := &upmiov1alpha2.Unit{}
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(unit), latest))
+	require.Empty(t, latest.Status.Conditions)
+}
+
+func TestClickHouseHealthConditionsWithoutKeeper(t *testing.T) {
+	conditions := clickHouseHealthConditions(&clickhouse.HealthResponse{Keeper: &clickhouse.KeeperSession{}}, nil)
+
+	keeper := meta.FindStatusCondition(conditions, ClickHouseKeeperConnectedCondition)
+	require.NotNil(t, keeper)
+	require.Equal(t, metav1.ConditionFalse, keeper.Status)
+	require.Equal(t, "NotConfigured", keeper.Reason)
+}
+
+func newClickHouseHealthReconciler(t *testing.T, unitsetType string, agent UnitAgentClient) (*UnitReconciler, *upmiov1alpha2.Unit) {
+	t.Helper()
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, upmiov1alpha2.AddToScheme(s))
+
+	unitset := &upmiov1alpha2.UnitSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "ch", Namespace: "default"},
+		Spec: upmiov1alpha2.UnitSetSpec{
+			Type: unitsetType,
+			Env:  []v1.EnvVar{{Name: "ADM_USER", Value: "operator"}},
+		},
+	}
+	unit := &upmiov1alpha2.Unit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ch-0",
+			Namespace: "default",
+			Labels:    map[string]string{upmiov1alpha2.UnitsetName: unitset.Name},
+		},
+		Status: upmiov1alpha2.UnitStatus{Phase: upmiov1alpha2.UnitReady},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(unitset, unit).
+		WithStatusSubresource(&upmiov1alpha2.Unit{}).
+		Build()
+
+	return &UnitReconciler{Client: c, Scheme: s, Agent: agent}, unit
+}
+
+func unitRequest(unit *upmiov1alpha2.Unit) ctrl.Request {
+	return ctrl.Request{NamespacedName: types.NamespacedName{Name: unit.Name, Namespace: unit.Namespace}}
+}
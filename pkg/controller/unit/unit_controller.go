@@ -23,6 +23,7 @@ import (
 	"time"
 
 	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/clickhouse"
 	internalAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
 	"github.com/upmio/unit-operator/pkg/utils/patch"
 	v1 "k8s.io/api/core/v1"
@@ -49,6 +50,8 @@ type UnitReconciler struct {
 // UnitAgentClient abstracts unit-agent RPCs for testability.
 type UnitAgentClient interface {
 	GetServiceProcessState(agentHostType, unitsetHeadlessSvc, host, namespace, port string) (string, error)
+	ClickHouseHealth(agentHostType, unitsetHeadlessSvc, host, namespace, port, username string) (*clickhouse.HealthResponse, error)
+	ServiceLifecycleManagement(agentHostType, unitsetHeadlessSvc, host, namespace, port, actionType string) (string, error)
 }
 
 type defaultUnitAgentClient struct{}
@@ -57,6 +60,14 @@ func (defaultUnitAgentClient) GetServiceProcessState(agentHostType, unitsetHeadl
 	return internalAgent.GetServiceProcessState(agentHostType, unitsetHeadlessSvc, host, namespace, port)
 }
 
+func (defaultUnitAgentClient) ClickHouseHealth(agentHostType, unitsetHeadlessSvc, host, namespace, port, username string) (*clickhouse.HealthResponse, error) {
+	return internalAgent.ClickHouseHealth(agentHostType, unitsetHeadlessSvc, host, namespace, port, username)
+}
+
+func (defaultUnitAgentClient) ServiceLifecycleManagement(agentHostType, unitsetHeadlessSvc, host, namespace, port, actionType string) (string, error) {
+	return internalAgent.ServiceLifecycleManagement(agentHostType, unitsetHeadlessSvc, host, namespace, port, actionType)
+}
+
 var (
 	controllerKind          = upmiov1alpha2.GroupVersion.WithKind("Unit")
 	maxConcurrentReconciles = 10
@@ -237,6 +248,12 @@ func (r *UnitReconciler) reconcileUnit(ctx context.Context, req ctrl.Request, un
 		return fmt.Errorf("failed to reconcile UnitStatus, err: [%v]", err.Error())
 	}
 
+	err = r.reconcileClickHouseHealth(ctx, req, unit)
+	if err != nil {
+		klog.Errorf("failed to reconcile ClickHouse health [%s], err: [%v]", req.String(), err.Error())
+		return fmt.Errorf("failed to reconcile ClickHouse health, err: [%v]", err.Error())
+	}
+
 	return nil
 }
 
@@ -33,6 +33,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/clickhouse"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -224,3 +225,11 @@ type fakeUnitAgentClient struct{}
 func (fakeUnitAgentClient) GetServiceProcessState(_, _, _, _, _ string) (string, error) {
 	return "running", nil
 }
+
+func (fakeUnitAgentClient) ClickHouseHealth(_, _, _, _, _, _ string) (*clickhouse.HealthResponse, error) {
+	return &clickhouse.HealthResponse{}, nil
+}
+
+func (fakeUnitAgentClient) ServiceLifecycleManagement(_, _, _, _, _, _ string) (string, error) {
+	return "", nil
+}
@@ -6,9 +6,10 @@ import (
 	"time"
 
 	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
-	internalAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
 	podutil "github.com/upmio/unit-operator/pkg/utils/pod"
 	"github.com/upmio/unit-operator/pkg/vars"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
@@ -65,7 +66,19 @@ func (r *UnitReconciler) reconcileUnitServer(ctx context.Context, req ctrl.Reque
 		agentHost = pod.Status.PodIPs[0].IP
 	}
 
+	agent := r.Agent
+	if agent == nil {
+		agent = defaultUnitAgentClient{}
+	}
+
 	if unit.Spec.Startup {
+		// the process was stopped by a grpc call, until a grpc call starts it again
+		if stoppedBy, ok := unit.GetAnnotations()[upmiov1alpha2.AnnotationProcessStoppedBy]; ok {
+			klog.Infof("[reconcileUnitServer] unit:[%s] process stopped by grpccall [%s], skip [start]",
+				req.NamespacedName.String(), stoppedBy)
+			return nil
+		}
+
 		if podutil.IsContainerRunningAndReady(pod, unit.MainContainerName()) &&
 			(unit.Status.ProcessState == "running" || unit.Status.ProcessState == "starting") {
 			return nil
@@ -74,7 +87,7 @@ func (r *UnitReconciler) reconcileUnitServer(ctx context.Context, req ctrl.Reque
 		klog.Infof("[reconcileUnitServer] unit:[%s] unit.spec.startup=true, will execute [start]",
 			req.NamespacedName.String())
 
-		resp, startErr := internalAgent.ServiceLifecycleManagement(
+		resp, startErr := agent.ServiceLifecycleManagement(
 			vars.UnitAgentHostType,
 			upmiov1alpha2.UnitsetHeadlessSvcName(unit),
 			agentHost,
@@ -82,6 +95,13 @@ func (r *UnitReconciler) reconcileUnitServer(ctx context.Context, req ctrl.Reque
 			"2214",
 			"start")
 
+		if isOperationConflict(startErr) {
+			klog.Infof("[reconcileUnitServer] unit:[%s] unit agent is running a conflicting operation, skip [start]: %s",
+				req.NamespacedName.String(), startErr.Error())
+			r.Recorder.Eventf(unit, v1.EventTypeNormal, "StartUp", "unit agent is running a conflicting operation, skip [start]")
+			return nil
+		}
+
 		if startErr != nil {
 			klog.Errorf("[reconcileUnitServer] unit:[%s] EXECUTE [start] error:[%s]",
 				req.NamespacedName.String(), startErr.Error())
@@ -122,7 +142,7 @@ func (r *UnitReconciler) reconcileUnitServer(ctx context.Context, req ctrl.Reque
 
 			r.Recorder.Eventf(unit, v1.EventTypeWarning, "StartUp", "[start up] timeout, will trrigger [stop] and then redo [start]")
 
-			stopMessage, stopErr := internalAgent.ServiceLifecycleManagement(
+			stopMessage, stopErr := agent.ServiceLifecycleManagement(
 				vars.UnitAgentHostType,
 				upmiov1alpha2.UnitsetHeadlessSvcName(unit),
 				agentHost,
@@ -130,6 +150,12 @@ func (r *UnitReconciler) reconcileUnitServer(ctx context.Context, req ctrl.Reque
 				"2214",
 				"stop")
 
+			if isOperationConflict(stopErr) {
+				klog.Infof("[reconcileUnitServer] unit:[%s] unit agent is running a conflicting operation, skip [stop]: %s",
+					req.String(), stopErr.Error())
+				return nil
+			}
+
 			if stopErr != nil {
 				return fmt.Errorf("fail to stop unit: message:[%s], error:[%s]", stopMessage, stopErr.Error())
 			}
@@ -149,16 +175,30 @@ func (r *UnitReconciler) reconcileUnitServer(ctx context.Context, req ctrl.Reque
 		return nil
 	}
 
-	resp, err := internalAgent.ServiceLifecycleManagement(
+	resp, err := agent.ServiceLifecycleManagement(
 		vars.UnitAgentHostType,
 		upmiov1alpha2.UnitsetHeadlessSvcName(unit),
 		agentHost,
 		unit.Namespace,
 		"2214",
 		"stop")
+	if isOperationConflict(err) {
+		klog.Infof("[reconcileUnitServer] unit:[%s] unit agent is running a conflicting operation, skip [stop]: %s",
+			req.NamespacedName.String(), err.Error())
+		r.Recorder.Eventf(unit, v1.EventTypeNormal, "ShutDown", "unit agent is running a conflicting operation, skip [stop]")
+		return nil
+	}
+
 	if err != nil {
 		return fmt.Errorf("fail to stop unit: message:[%s], error:[%s]", resp, err.Error())
 	}
 
 	return nil
 }
+
+// isOperationConflict reports whether the unit agent rejected an RPC as it
+// conflicts with an operation in progress, e.g. a restore, which must not be
+// interrupted by starting or stopping the process.
+func isOperationConflict(err error) bool {
+	return status.Code(err) == codes.FailedPrecondition
+}
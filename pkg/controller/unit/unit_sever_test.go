@@ -280,6 +280,24 @@ var _ = Describe("UnitServer Reconciler", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("should not start service when stopped by a grpc call", func() {
+			unit.Annotations[upmiov1alpha2.AnnotationProcessStoppedBy] = "stop-mysql"
+			Expect(k8sClient.Create(ctx, unit)).To(Succeed())
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+			current := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: unitName, Namespace: "default"}, current)).To(Succeed())
+			current.Status = pod.Status
+			Expect(k8sClient.Status().Update(ctx, current)).To(Succeed())
+
+			agent := &lifecycleAgent{}
+			reconciler.Agent = agent
+
+			err := reconciler.reconcileUnitServer(ctx, req, unit)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.actions).To(BeEmpty())
+		})
+
 		//It("should return error when service start fails", func() {
 		//	Expect(k8sClient.Create(ctx, unit)).To(Succeed())
 		//	Expect(k8sClient.Create(ctx, pod)).To(Succeed())
@@ -476,3 +494,15 @@ var _ = Describe("UnitServer Reconciler", func() {
 		})
 	})
 })
+
+// lifecycleAgent records the lifecycle actions asked of the unit agent.
+type lifecycleAgent struct {
+	fakeUnitAgentClient
+
+	actions []string
+}
+
+func (a *lifecycleAgent) ServiceLifecycleManagement(_, _, _, _, _, actionType string) (string, error) {
+	a.actions = append(a.actions, actionType)
+	return "", nil
+}
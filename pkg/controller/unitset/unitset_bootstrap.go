@@ -0,0 +1,353 @@
+package unitset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/mysql"
+	"github.com/upmio/unit-operator/pkg/agent/app/postgresql"
+	"github.com/upmio/unit-operator/pkg/agent/app/redis"
+	unitAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
+	podutil "github.com/upmio/unit-operator/pkg/utils/pod"
+	"github.com/upmio/unit-operator/pkg/vars"
+	"google.golang.org/grpc"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// BootstrappedCondition reports whether the units of a UnitSet bootstrapped from a backup are initialized
+	BootstrappedCondition = "Bootstrapped"
+
+	// bootstrapRestoreTimeout bounds the start of the restore of the backup into a unit, the whole
+	// restore when the unit-agent runs it synchronously
+	bootstrapRestoreTimeout = 2 * time.Hour
+
+	// bootstrapRestoreOperation prefixes the key of the restore of a unit in the UnitSet status
+	bootstrapRestoreOperation = "bootstrap-restore"
+
+	// bootstrapDefaultUser is used when neither the bootstrap nor the UnitSet sets a user
+	bootstrapDefaultUser = "admin"
+
+	objectStorageAccessKey = "accessKey"
+	objectStorageSecretKey = "secretKey"
+)
+
+// isBootstrapping reports whether the units of the UnitSet are still to be restored from a backup.
+func isBootstrapping(unitset *upmiov1alpha2.UnitSet) bool {
+	return unitset.Spec.Bootstrap != nil && unitset.Spec.Bootstrap.FromBackup != nil &&
+		!meta.IsStatusConditionTrue(unitset.Status.Conditions, BootstrappedCondition)
+}
+
+// markUnitBootstrapping keeps a unit created while the UnitSet bootstraps stopped until the backup is restored into it.
+func markUnitBootstrapping(unitset *upmiov1alpha2.UnitSet, unit *upmiov1alpha2.Unit) {
+	if !isBootstrapping(unitset) {
+		return
+	}
+
+	if unit.Annotations == nil {
+		unit.Annotations = make(map[string]string)
+	}
+
+	unit.Spec.Startup = false
+	unit.Annotations[upmiov1alpha2.AnnotationBootstrapPhase] = upmiov1alpha2.BootstrapRestoring
+}
+
+func bootstrapUsername(unitset *upmiov1alpha2.UnitSet) string {
+	if username := unitset.Spec.Bootstrap.FromBackup.Username; username != "" {
+		return username
+	}
+	if username := unitsetEnv(unitset, "ADM_USER"); username != "" {
+		return username
+	}
+
+	return bootstrapDefaultUser
+}
+
+// validateBootstrap reports the unitset types whose units can not be restored before their first start.
+func validateBootstrap(unitset *upmiov1alpha2.UnitSet) error {
+	switch unitset.Spec.Type {
+	case "mysql", "postgresql":
+		return nil
+	case "redis":
+		if isRedisCluster(unitset) {
+			return fmt.Errorf("redis cluster unitsets are restored by a RedisClusterRestore")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unitset type [%s] does not support bootstrap from backup", unitset.Spec.Type)
+	}
+}
+
+// reconcileBootstrap restores the backup of spec.bootstrap.fromBackup into the units created stopped for it.
+// Once a unit-agent is up the backup is restored as a unit-agent operation, recorded in the UnitSet status
+// and polled by the following reconciles, then the unit is started and the post-restore steps of the
+// engine run, GtidPurge for mysql. The progress of a unit is kept in its bootstrap phase annotation, the
+// Bootstrapped condition turns true once every unit completed, later units start as usual.
+func (r *UnitSetReconciler) reconcileBootstrap(ctx context.Context, req ctrl.Request, unitset *upmiov1alpha2.UnitSet) error {
+	if !isBootstrapping(unitset) {
+		return nil
+	}
+
+	if err := validateBootstrap(unitset); err != nil {
+		return r.setUnitsetCondition(ctx, unitset, BootstrappedCondition, metav1.ConditionFalse, "Unsupported", err.Error())
+	}
+
+	units, err := r.unitsBelongUnitset(ctx, unitset)
+	if err != nil {
+		return fmt.Errorf("[reconcileBootstrap] list units err:[%v]", err)
+	}
+
+	if len(units) != unitset.Spec.Units {
+		return nil
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].Name < units[j].Name
+	})
+
+	backup := unitset.Spec.Bootstrap.FromBackup
+	var storage *common.ObjectStorage
+	var waiting []string
+	for _, unit := range units {
+		switch unit.Annotations[upmiov1alpha2.AnnotationBootstrapPhase] {
+		case upmiov1alpha2.BootstrapRestoring:
+			ready, err := r.isUnitAgentReady(ctx, unit)
+			if err != nil {
+				return fmt.Errorf("[reconcileBootstrap] %v", err)
+			}
+			if !ready {
+				waiting = append(waiting, unit.Name)
+				continue
+			}
+
+			done, err := r.restoreBootstrapUnit(ctx, unitset, unit, &storage)
+			if err != nil {
+				return err
+			}
+			waiting = append(waiting, unit.Name)
+			if !done {
+				continue
+			}
+
+			if err := r.setUnitBootstrapPhase(ctx, unit, upmiov1alpha2.BootstrapRestored); err != nil {
+				return fmt.Errorf("[reconcileBootstrap] %v", err)
+			}
+
+			r.Recorder.Eventf(unitset, v1.EventTypeNormal, "BootstrapRestored", "restored backup [%s] into unit [%s]", backup.BackupFile, unit.Name)
+
+		case upmiov1alpha2.BootstrapRestored:
+			if unit.Status.Phase != upmiov1alpha2.UnitReady {
+				waiting = append(waiting, unit.Name)
+				continue
+			}
+
+			if err := r.callUnitAgent(ctx, unit, unitAgentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+				return postRestoreBootstrap(ctx, conn, unitset.Spec.Type, bootstrapUsername(unitset))
+			}); err != nil {
+				return r.bootstrapFailed(ctx, unitset, "PostRestoreFailed", fmt.Errorf("failed to run the post-restore steps of unit [%s]: %v", unit.Name, err))
+			}
+
+			if err := r.setUnitBootstrapPhase(ctx, unit, upmiov1alpha2.BootstrapCompleted); err != nil {
+				return fmt.Errorf("[reconcileBootstrap] %v", err)
+			}
+
+			r.Recorder.Eventf(unitset, v1.EventTypeNormal, "BootstrapCompleted", "unit [%s] is initialized from backup [%s]", unit.Name, backup.BackupFile)
+		}
+	}
+
+	if len(waiting) > 0 {
+		return r.setUnitsetCondition(ctx, unitset, BootstrappedCondition, metav1.ConditionFalse, "Restoring",
+			fmt.Sprintf("waiting for units [%s] to be restored from backup [%s]", strings.Join(waiting, ","), backup.BackupFile))
+	}
+
+	return r.setUnitsetCondition(ctx, unitset, BootstrappedCondition, metav1.ConditionTrue, "Completed",
+		fmt.Sprintf("%d units initialized from backup [%s]", len(units), backup.BackupFile))
+}
+
+// restoreBootstrapUnit starts the restore of the backup into the unit, or polls the restore already
+// started, and reports whether it succeeded. A failed restore is started again by the next reconcile.
+func (r *UnitSetReconciler) restoreBootstrapUnit(
+	ctx context.Context,
+	unitset *upmiov1alpha2.UnitSet,
+	unit *upmiov1alpha2.Unit,
+	storage **common.ObjectStorage,
+) (bool, error) {
+	key := fmt.Sprintf("%s/%s", bootstrapRestoreOperation, unit.Name)
+
+	if op, ok := unitset.Status.Operations[key]; ok {
+		done, progress, err := unitAgent.PollOperation(ctx, unit, r.agentDialer(), unitAgentCallTimeout, op.ID)
+		if errors.Is(err, unitAgent.ErrOperationFailed) {
+			if clearErr := r.setUnitsetOperation(ctx, unitset, key, nil); clearErr != nil {
+				return false, fmt.Errorf("[reconcileBootstrap] %v", clearErr)
+			}
+			return false, r.bootstrapFailed(ctx, unitset, "RestoreFailed", fmt.Errorf("failed to restore unit [%s]: %v", unit.Name, err))
+		}
+		if err != nil {
+			return false, fmt.Errorf("[reconcileBootstrap] %v", err)
+		}
+		if !done {
+			if progress != "" {
+				klog.Infof("[reconcileBootstrap] unitset [%s/%s] restore of unit [%s]: %s", unitset.Namespace, unitset.Name, unit.Name, progress)
+			}
+			return false, nil
+		}
+
+		return true, r.setUnitsetOperation(ctx, unitset, key, nil)
+	}
+
+	if *storage == nil {
+		var err error
+		if *storage, err = r.bootstrapObjectStorage(ctx, unitset); err != nil {
+			return false, r.bootstrapFailed(ctx, unitset, "ObjectStorageInvalid", err)
+		}
+	}
+
+	// The operation is recorded first, a start lost on its way is found missing by the next poll
+	backupFile := unitset.Spec.Bootstrap.FromBackup.BackupFile
+	op := &upmiov1alpha2.UnitSetOperation{
+		Unit: unit.Name,
+		ID:   fmt.Sprintf("%s-%s-%d", bootstrapRestoreOperation, unit.Name, time.Now().UnixNano()),
+	}
+	if err := r.setUnitsetOperation(ctx, unitset, key, op); err != nil {
+		return false, fmt.Errorf("[reconcileBootstrap] %v", err)
+	}
+
+	klog.Infof("[reconcileBootstrap] unitset [%s/%s] restoring backup [%s] into unit [%s]", unitset.Namespace, unitset.Name, backupFile, unit.Name)
+	id, err := unitAgent.StartOperation(ctx, unit, r.agentDialer(), bootstrapRestoreTimeout, op.ID,
+		func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) error {
+			return restoreBootstrapBackup(ctx, conn, unitset.Spec.Type, backupFile, *storage, opts...)
+		})
+	if err != nil {
+		return false, fmt.Errorf("[reconcileBootstrap] failed to start the restore of unit [%s]: %v", unit.Name, err)
+	}
+
+	// the unit-agent restored the backup synchronously
+	if id == "" {
+		return true, r.setUnitsetOperation(ctx, unitset, key, nil)
+	}
+
+	return false, nil
+}
+
+func (r *UnitSetReconciler) bootstrapFailed(ctx context.Context, unitset *upmiov1alpha2.UnitSet, reason string, err error) error {
+	r.Recorder.Eventf(unitset, v1.EventTypeWarning, "Bootstrap"+reason, err.Error())
+	if condErr := r.setUnitsetCondition(ctx, unitset, BootstrappedCondition, metav1.ConditionFalse, reason, err.Error()); condErr != nil {
+		klog.Errorf("[reconcileBootstrap] failed to set condition of unitset [%s]: %v", unitset.Name, condErr)
+	}
+
+	return fmt.Errorf("[reconcileBootstrap] %v", err)
+}
+
+// isUnitAgentReady reports whether the unit-agent of the unit accepts calls, the main process stays stopped.
+func (r *UnitSetReconciler) isUnitAgentReady(ctx context.Context, unit *upmiov1alpha2.Unit) (bool, error) {
+	pod := &v1.Pod{}
+	if err := r.Get(ctx, client.ObjectKey{Name: unit.Name, Namespace: unit.Namespace}, pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("get pod of unit [%s] failed: %v", unit.Name, err)
+	}
+
+	return podutil.IsContainerRunningAndReady(pod, vars.UnitAgentName), nil
+}
+
+// setUnitBootstrapPhase advances the bootstrap phase of the unit, a restored unit is started.
+func (r *UnitSetReconciler) setUnitBootstrapPhase(ctx context.Context, unit *upmiov1alpha2.Unit, phase string) error {
+	patch := client.MergeFrom(unit.DeepCopy())
+	if unit.Annotations == nil {
+		unit.Annotations = make(map[string]string)
+	}
+
+	unit.Annotations[upmiov1alpha2.AnnotationBootstrapPhase] = phase
+	unit.Spec.Startup = true
+	if err := r.Patch(ctx, unit, patch); err != nil {
+		return fmt.Errorf("failed to set bootstrap phase of unit [%s] to %s: %v", unit.Name, phase, err)
+	}
+
+	return nil
+}
+
+// bootstrapObjectStorage returns the object storage of the backup with the credentials of its secret.
+func (r *UnitSetReconciler) bootstrapObjectStorage(ctx context.Context, unitset *upmiov1alpha2.UnitSet) (*common.ObjectStorage, error) {
+	spec := unitset.Spec.Bootstrap.FromBackup.ObjectStorage
+	if spec.Type != "" && spec.Type != "minio" {
+		return nil, fmt.Errorf("unsupported object storage type [%s]", spec.Type)
+	}
+
+	secret := &v1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Name: spec.CredentialsSecret, Namespace: unitset.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to fetch secret [%s/%s]: %v", unitset.Namespace, spec.CredentialsSecret, err)
+	}
+
+	for _, key := range []string{objectStorageAccessKey, objectStorageSecretKey} {
+		if len(secret.Data[key]) == 0 {
+			return nil, fmt.Errorf("key [%s] not found in secret [%s/%s]", key, unitset.Namespace, spec.CredentialsSecret)
+		}
+	}
+
+	return &common.ObjectStorage{
+		Endpoint:  spec.Endpoint,
+		Bucket:    spec.Bucket,
+		AccessKey: string(secret.Data[objectStorageAccessKey]),
+		SecretKey: string(secret.Data[objectStorageSecretKey]),
+		Ssl:       spec.SSL,
+		Type:      common.ObjectStorageType_Minio,
+	}, nil
+}
+
+// restoreBootstrapBackup restores the backup into the stopped unit through the engine service of its unit-agent.
+func restoreBootstrapBackup(
+	ctx context.Context,
+	conn grpc.ClientConnInterface,
+	unitType, backupFile string,
+	storage *common.ObjectStorage,
+	opts ...grpc.CallOption,
+) error {
+	var err error
+	switch unitType {
+	case "mysql":
+		_, err = mysql.NewMysqlOperationClient(conn).Restore(ctx, &mysql.RestoreRequest{
+			BackupFile:    backupFile,
+			Tool:          mysql.Tool_Xtrabackup,
+			ObjectStorage: storage,
+		}, opts...)
+	case "postgresql":
+		_, err = postgresql.NewPostgresqlOperationClient(conn).Restore(ctx, &postgresql.RestoreRequest{
+			BackupFile:    backupFile,
+			ObjectStorage: storage,
+		}, opts...)
+	case "redis":
+		_, err = redis.NewRedisOperationClient(conn).Restore(ctx, &redis.RestoreRequest{
+			BackupFile:    backupFile,
+			ObjectStorage: storage,
+		}, opts...)
+	default:
+		err = fmt.Errorf("unitset type [%s] does not support bootstrap from backup", unitType)
+	}
+
+	return err
+}
+
+// postRestoreBootstrap runs the steps a restored unit needs once started, the purge of the gtids of the
+// backup for mysql.
+func postRestoreBootstrap(ctx context.Context, conn grpc.ClientConnInterface, unitType, username string) error {
+	switch unitType {
+	case "mysql":
+		_, err := mysql.NewMysqlOperationClient(conn).GtidPurge(ctx, &mysql.GtidPurgeRequest{Username: username})
+		return err
+	default:
+		return nil
+	}
+}
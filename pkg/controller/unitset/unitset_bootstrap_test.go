@@ -0,0 +1,295 @@
+package unitset
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/mysql"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
+	"github.com/upmio/unit-operator/pkg/vars"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeMySQLBootstrap records the restores and gtid purges of the mysql agents.
+type fakeMySQLBootstrap struct {
+	mu       sync.Mutex
+	restored map[string]*mysql.RestoreRequest
+	purged   map[string]string
+
+	// operations are the restores started asynchronously, left running until finished
+	operations map[string]*operation.Operation
+}
+
+type fakeMySQLAgent struct {
+	mysql.UnimplementedMysqlOperationServer
+	operation.UnimplementedOperationsServer
+
+	unit      string
+	bootstrap *fakeMySQLBootstrap
+}
+
+func (a *fakeMySQLAgent) Restore(ctx context.Context, req *mysql.RestoreRequest) (*common.RestoreResponse, error) {
+	a.bootstrap.mu.Lock()
+	defer a.bootstrap.mu.Unlock()
+
+	a.bootstrap.restored[a.unit] = req
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if a.bootstrap.operations == nil || len(md.Get(operation.AsyncMetadataKey)) == 0 {
+		return &common.RestoreResponse{Object: req.GetBackupFile()}, nil
+	}
+
+	id := md.Get(operation.IDMetadataKey)[0]
+	a.bootstrap.operations[id] = &operation.Operation{Id: id, State: operation.State_RUNNING, Progress: "downloading"}
+	return &common.RestoreResponse{}, grpc.SetHeader(ctx, metadata.Pairs(operation.IDMetadataKey, id))
+}
+
+func (a *fakeMySQLAgent) GetOperation(_ context.Context, req *operation.GetOperationRequest) (*operation.Operation, error) {
+	a.bootstrap.mu.Lock()
+	defer a.bootstrap.mu.Unlock()
+
+	op, ok := a.bootstrap.operations[req.GetId()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "operation %s not found", req.GetId())
+	}
+	return op, nil
+}
+
+// finish ends the running restores in state.
+func (b *fakeMySQLBootstrap) finish(state operation.State) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, op := range b.operations {
+		if op.GetState() == operation.State_RUNNING {
+			op.State = state
+		}
+	}
+}
+
+func (a *fakeMySQLAgent) GtidPurge(_ context.Context, req *mysql.GtidPurgeRequest) (*common.Empty, error) {
+	a.bootstrap.mu.Lock()
+	defer a.bootstrap.mu.Unlock()
+
+	a.bootstrap.purged[a.unit] = req.GetUsername()
+	return &common.Empty{}, nil
+}
+
+func newBootstrapUnitSet(unitType string, units int) *upmiov1alpha2.UnitSet {
+	return &upmiov1alpha2.UnitSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "default"},
+		Spec: upmiov1alpha2.UnitSetSpec{
+			Type:  unitType,
+			Units: units,
+			Env:   []v1.EnvVar{{Name: "ADM_USER", Value: "root"}},
+			Bootstrap: &upmiov1alpha2.BootstrapSpec{
+				FromBackup: &upmiov1alpha2.BootstrapFromBackup{
+					BackupFile: "prod-20250501",
+					ObjectStorage: upmiov1alpha2.BackupObjectStorageSpec{
+						Endpoint:          "minio:9000",
+						Bucket:            "backups",
+						CredentialsSecret: "backup-credentials",
+					},
+				},
+			},
+		},
+	}
+}
+
+// startBootstrapUnit marks the unit as created for the bootstrap and runs the pod of its unit-agent.
+func startBootstrapUnit(t *testing.T, r *UnitSetReconciler, unit *upmiov1alpha2.Unit, withPod bool) {
+	t.Helper()
+	ctx := context.Background()
+
+	patch := client.MergeFrom(unit.DeepCopy())
+	unit.Annotations = map[string]string{upmiov1alpha2.AnnotationBootstrapPhase: upmiov1alpha2.BootstrapRestoring}
+	require.NoError(t, r.Patch(ctx, unit, patch))
+
+	if !withPod {
+		return
+	}
+
+	require.NoError(t, r.Create(ctx, &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: unit.Name, Namespace: unit.Namespace},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:  vars.UnitAgentName,
+				Ready: true,
+				State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+			}},
+		},
+	}))
+}
+
+func reconcileBootstrapOnce(t *testing.T, r *UnitSetReconciler) *metav1.Condition {
+	t.Helper()
+
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "default", Name: "staging"}
+
+	unitset := &upmiov1alpha2.UnitSet{}
+	require.NoError(t, r.Get(ctx, key, unitset))
+	require.NoError(t, r.reconcileBootstrap(ctx, ctrl.Request{NamespacedName: key}, unitset))
+	require.NoError(t, r.Get(ctx, key, unitset))
+
+	return meta.FindStatusCondition(unitset.Status.Conditions, BootstrappedCondition)
+}
+
+func getBootstrapUnit(t *testing.T, r *UnitSetReconciler, name string) *upmiov1alpha2.Unit {
+	t.Helper()
+
+	unit := &upmiov1alpha2.Unit{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, unit))
+	return unit
+}
+
+func TestReconcileBootstrapFromMySQLBackup(t *testing.T) {
+	bootstrap := &fakeMySQLBootstrap{restored: map[string]*mysql.RestoreRequest{}, purged: map[string]string{}}
+	units := []string{"staging-0", "staging-1"}
+	dial := startUnitAgents(t, func(unit string, server *grpc.Server) {
+		mysql.RegisterMysqlOperationServer(server, &fakeMySQLAgent{unit: unit, bootstrap: bootstrap})
+	}, units...)
+
+	r, kUnits := newUnitAgentReconciler(t, dial, newBootstrapUnitSet("mysql", 2), units...)
+	require.NoError(t, r.Create(context.Background(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"accessKey": []byte("ak"), "secretKey": []byte("sk")},
+	}))
+	startBootstrapUnit(t, r, kUnits[0], true)
+	startBootstrapUnit(t, r, kUnits[1], false)
+
+	// the unit whose agent is up is restored and started
+	condition := reconcileBootstrapOnce(t, r)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "Restoring", condition.Reason)
+	require.Len(t, bootstrap.restored, 1)
+	assert.Equal(t, "prod-20250501", bootstrap.restored["staging-0"].GetBackupFile())
+	assert.Equal(t, mysql.Tool_Xtrabackup, bootstrap.restored["staging-0"].GetTool())
+	assert.Equal(t, "sk", bootstrap.restored["staging-0"].GetObjectStorage().GetSecretKey())
+	unit := getBootstrapUnit(t, r, "staging-0")
+	assert.True(t, unit.Spec.Startup)
+	assert.Equal(t, upmiov1alpha2.BootstrapRestored, unit.Annotations[upmiov1alpha2.AnnotationBootstrapPhase])
+	assert.False(t, getBootstrapUnit(t, r, "staging-1").Spec.Startup)
+
+	// the started unit purges the gtids of the backup, the other one is restored
+	startBootstrapUnit(t, r, getBootstrapUnit(t, r, "staging-1"), true)
+	condition = reconcileBootstrapOnce(t, r)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, map[string]string{"staging-0": "root"}, bootstrap.purged)
+	assert.Equal(t, upmiov1alpha2.BootstrapCompleted, getBootstrapUnit(t, r, "staging-0").Annotations[upmiov1alpha2.AnnotationBootstrapPhase])
+	assert.Len(t, bootstrap.restored, 2)
+
+	condition = reconcileBootstrapOnce(t, r)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "Completed", condition.Reason)
+	assert.Len(t, bootstrap.purged, 2)
+
+	// units created once bootstrapped start as usual
+	unitset := &upmiov1alpha2.UnitSet{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "staging"}, unitset))
+	scaled := &upmiov1alpha2.Unit{Spec: upmiov1alpha2.UnitSpec{Startup: true}}
+	markUnitBootstrapping(unitset, scaled)
+	assert.True(t, scaled.Spec.Startup)
+	assert.Empty(t, scaled.Annotations)
+}
+
+func TestReconcileBootstrapRestoresAsOperation(t *testing.T) {
+	bootstrap := &fakeMySQLBootstrap{restored: map[string]*mysql.RestoreRequest{}, purged: map[string]string{},
+		operations: map[string]*operation.Operation{}}
+	units := []string{"staging-0", "staging-1"}
+	dial := startUnitAgents(t, func(unit string, server *grpc.Server) {
+		agent := &fakeMySQLAgent{unit: unit, bootstrap: bootstrap}
+		mysql.RegisterMysqlOperationServer(server, agent)
+		operation.RegisterOperationsServer(server, agent)
+	}, units...)
+
+	r, kUnits := newUnitAgentReconciler(t, dial, newBootstrapUnitSet("mysql", 2), units...)
+	require.NoError(t, r.Create(context.Background(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"accessKey": []byte("ak"), "secretKey": []byte("sk")},
+	}))
+	startBootstrapUnit(t, r, kUnits[0], true)
+	startBootstrapUnit(t, r, kUnits[1], true)
+
+	operations := func() map[string]upmiov1alpha2.UnitSetOperation {
+		unitset := &upmiov1alpha2.UnitSet{}
+		require.NoError(t, r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "staging"}, unitset))
+		return unitset.Status.Operations
+	}
+
+	// the restores of every unit start in the same reconcile and are recorded in the status
+	condition := reconcileBootstrapOnce(t, r)
+	assert.Equal(t, "Restoring", condition.Reason)
+	assert.Len(t, bootstrap.restored, 2)
+	recorded := operations()
+	require.Len(t, recorded, 2)
+	assert.Equal(t, "staging-0", recorded["bootstrap-restore/staging-0"].Unit)
+	assert.Contains(t, bootstrap.operations, recorded["bootstrap-restore/staging-1"].ID)
+	assert.False(t, getBootstrapUnit(t, r, "staging-0").Spec.Startup)
+
+	// running restores are polled, not started again
+	bootstrap.restored = map[string]*mysql.RestoreRequest{}
+	reconcileBootstrapOnce(t, r)
+	assert.Empty(t, bootstrap.restored)
+	assert.Equal(t, recorded, operations())
+
+	// a failed restore fails the reconcile, it is dropped and started again by the next one
+	bootstrap.finish(operation.State_FAILED)
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "default", Name: "staging"}
+	for _, failed := range units {
+		unitset := &upmiov1alpha2.UnitSet{}
+		require.NoError(t, r.Get(ctx, key, unitset))
+		err := r.reconcileBootstrap(ctx, ctrl.Request{NamespacedName: key}, unitset)
+		require.ErrorContains(t, err, fmt.Sprintf("failed to restore unit [%s]", failed))
+	}
+	reconcileBootstrapOnce(t, r)
+	assert.Len(t, bootstrap.restored, 2)
+	assert.NotEqual(t, recorded, operations())
+
+	// the succeeded restores start the units
+	bootstrap.finish(operation.State_SUCCEEDED)
+	reconcileBootstrapOnce(t, r)
+	assert.Empty(t, operations())
+	for _, name := range units {
+		unit := getBootstrapUnit(t, r, name)
+		assert.True(t, unit.Spec.Startup)
+		assert.Equal(t, upmiov1alpha2.BootstrapRestored, unit.Annotations[upmiov1alpha2.AnnotationBootstrapPhase])
+	}
+}
+
+func TestMarkUnitBootstrapping(t *testing.T) {
+	unit := &upmiov1alpha2.Unit{Spec: upmiov1alpha2.UnitSpec{Startup: true}}
+	markUnitBootstrapping(newBootstrapUnitSet("mysql", 1), unit)
+	assert.False(t, unit.Spec.Startup)
+	assert.Equal(t, upmiov1alpha2.BootstrapRestoring, unit.Annotations[upmiov1alpha2.AnnotationBootstrapPhase])
+
+	unit = &upmiov1alpha2.Unit{Spec: upmiov1alpha2.UnitSpec{Startup: true}}
+	markUnitBootstrapping(&upmiov1alpha2.UnitSet{}, unit)
+	assert.True(t, unit.Spec.Startup)
+}
+
+func TestReconcileBootstrapUnsupportedType(t *testing.T) {
+	r, _ := newUnitAgentReconciler(t, nil, newBootstrapUnitSet("mongodb", 1), "staging-0")
+
+	condition := reconcileBootstrapOnce(t, r)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "Unsupported", condition.Reason)
+}
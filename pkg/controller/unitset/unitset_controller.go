@@ -32,6 +32,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 
 	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	unitAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,6 +52,9 @@ type UnitSetReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// dialAgent connects to the unit-agent of a unit, unitAgent.Dial when nil
+	dialAgent unitAgent.DialFunc
 }
 
 // +kubebuilder:rbac:groups=upm.syntropycloud.io,resources=unitsets,verbs=get;list;watch;create;update;patch;delete
@@ -262,6 +266,21 @@ func (r *UnitSetReconciler) reconcileUnitset(ctx context.Context, req ctrl.Reque
 		return fmt.Errorf("failed to reconcile UnitsetStatus, err: [%v]", err.Error())
 	}
 
+	err = r.reconcileBootstrap(ctx, req, unitset)
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileRedisCluster(ctx, req, unitset)
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileMongoDBReplicaSet(ctx, req, unitset)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,249 @@
+package unitset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/mongodb"
+	"google.golang.org/grpc"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// MongoDBReplicaSetScaledCondition reports whether the units of a MongoDB UnitSet are all members of the replica set
+	MongoDBReplicaSetScaledCondition = "MongoDBReplicaSetScaled"
+
+	mongoDBUnitType = "mongodb"
+
+	// mongoDBDefaultUser is used when the UnitSet sets no ADM_USER
+	mongoDBDefaultUser = "admin"
+	mongoDBPort        = 27017
+
+	// mongoDBReconfigTimeout bounds a membership change, a primary leaving steps down first
+	mongoDBReconfigTimeout = 3 * time.Minute
+)
+
+// isMongoDBReplicaSet reports whether the units of the UnitSet form a MongoDB replica set.
+func isMongoDBReplicaSet(unitset *upmiov1alpha2.UnitSet) bool {
+	return unitset.Spec.Type == mongoDBUnitType
+}
+
+func mongoDBUsername(unitset *upmiov1alpha2.UnitSet) string {
+	if username := unitsetEnv(unitset, "ADM_USER"); username != "" {
+		return username
+	}
+
+	return mongoDBDefaultUser
+}
+
+// mongoDBMemberHost returns the replica set member address of the unit, as the unit-agent builds it.
+func mongoDBMemberHost(unit *upmiov1alpha2.Unit) string {
+	return fmt.Sprintf("%s.%s.%s:%d", unit.Name, upmiov1alpha2.UnitsetHeadlessSvcName(unit), unit.Namespace, mongoDBPort)
+}
+
+// isMongoDBUnitsetMember reports whether the member host belongs to a unit of the UnitSet.
+func isMongoDBUnitsetMember(unitset *upmiov1alpha2.UnitSet, host string) bool {
+	return strings.HasSuffix(host, fmt.Sprintf(".%s-headless-svc.%s:%d", unitset.Name, unitset.Namespace, mongoDBPort))
+}
+
+// reconcileMongoDBReplicaSet takes the units of a scale out into the replica set once every unit is ready.
+// The members are added one per reconcile, the replica set accepts a single voting member change at a
+// time, and members left behind by a scale in are removed. The progress is reported by the
+// MongoDBReplicaSetScaled condition. A replica set not initiated yet is left alone.
+func (r *UnitSetReconciler) reconcileMongoDBReplicaSet(ctx context.Context, req ctrl.Request, unitset *upmiov1alpha2.UnitSet) error {
+	if !isMongoDBReplicaSet(unitset) {
+		return nil
+	}
+
+	units, err := r.unitsBelongUnitset(ctx, unitset)
+	if err != nil {
+		return fmt.Errorf("[reconcileMongoDBReplicaSet] list units err:[%v]", err)
+	}
+
+	if len(units) != unitset.Spec.Units {
+		return nil
+	}
+
+	for _, unit := range units {
+		if unit.Status.Phase != upmiov1alpha2.UnitReady {
+			return nil
+		}
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].Name < units[j].Name
+	})
+
+	username := mongoDBUsername(unitset)
+
+	var seed *upmiov1alpha2.Unit
+	var status *mongodb.ReplicaSetStatusResponse
+	for _, unit := range units {
+		if err := r.callUnitAgent(ctx, unit, unitAgentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			var err error
+			status, err = mongodb.NewMongoDBOperationClient(conn).ReplicaSetStatus(ctx, &mongodb.ReplicaSetStatusRequest{Username: username})
+			return err
+		}); err != nil {
+			return fmt.Errorf("[reconcileMongoDBReplicaSet] failed to query replica set status of unit [%s]: %v", unit.Name, err)
+		}
+
+		if status.GetInitiated() {
+			seed = unit
+			break
+		}
+	}
+	if seed == nil {
+		return nil
+	}
+
+	members := make(map[string]*mongodb.MemberStatus, len(status.GetMembers()))
+	for _, member := range status.GetMembers() {
+		members[member.GetHost()] = member
+	}
+
+	hosts := make(map[string]bool, len(units))
+	for _, unit := range units {
+		hosts[mongoDBMemberHost(unit)] = true
+	}
+
+	// Members of units removed while the unit-agents could not be reached
+	for _, member := range status.GetMembers() {
+		if hosts[member.GetHost()] || !isMongoDBUnitsetMember(unitset, member.GetHost()) {
+			continue
+		}
+
+		if err := r.removeMongoDBMember(ctx, unitset, seed, username, member.GetHost()); err != nil {
+			return fmt.Errorf("[reconcileMongoDBReplicaSet] %v", err)
+		}
+
+		return r.setUnitsetCondition(ctx, unitset, MongoDBReplicaSetScaledCondition, metav1.ConditionFalse, "MembersLeaving",
+			fmt.Sprintf("removed member [%s] of a deleted unit", member.GetHost()))
+	}
+
+	var joining []*upmiov1alpha2.Unit
+	var syncing []string
+	for _, unit := range units {
+		member, ok := members[mongoDBMemberHost(unit)]
+		if !ok {
+			joining = append(joining, unit)
+			continue
+		}
+
+		switch member.GetState() {
+		case "PRIMARY", "SECONDARY", "ARBITER":
+		default:
+			syncing = append(syncing, fmt.Sprintf("%s(%s)", unit.Name, member.GetState()))
+		}
+	}
+
+	if len(joining) > 0 {
+		unit, host := joining[0], mongoDBMemberHost(joining[0])
+		if err := r.callUnitAgent(ctx, seed, mongoDBReconfigTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			_, err := mongodb.NewMongoDBOperationClient(conn).AddMember(ctx, &mongodb.AddMemberRequest{
+				Username: username,
+				Member:   &mongodb.ReplicaSetMember{Host: host},
+			})
+			return err
+		}); err != nil {
+			return fmt.Errorf("[reconcileMongoDBReplicaSet] failed to add unit [%s] to the replica set: %v", unit.Name, err)
+		}
+
+		r.Recorder.Eventf(unitset, v1.EventTypeNormal, "MongoDBMemberAdded", "unit [%s] joined the replica set as [%s]", unit.Name, host)
+
+		names := make([]string, 0, len(joining))
+		for _, unit := range joining {
+			names = append(names, unit.Name)
+		}
+		return r.setUnitsetCondition(ctx, unitset, MongoDBReplicaSetScaledCondition, metav1.ConditionFalse, "MembersJoining",
+			fmt.Sprintf("waiting for units [%s] to join the replica set", strings.Join(names, ",")))
+	}
+
+	if len(syncing) > 0 {
+		return r.setUnitsetCondition(ctx, unitset, MongoDBReplicaSetScaledCondition, metav1.ConditionFalse, "MembersSyncing",
+			fmt.Sprintf("waiting for members [%s] to sync", strings.Join(syncing, ",")))
+	}
+
+	klog.V(4).Infof("[reconcileMongoDBReplicaSet] unitset [%s] replica set [%s] primary [%s], max lag %ds",
+		req.String(), status.GetSet(), status.GetPrimary(), status.GetMaxLagSeconds())
+
+	return r.setUnitsetCondition(ctx, unitset, MongoDBReplicaSetScaledCondition, metav1.ConditionTrue, "Synced",
+		fmt.Sprintf("%d units are members of the replica set", len(units)))
+}
+
+// leaveMongoDBReplicaSet removes the member of a unit about to be deleted by a scale in through a ready
+// unit that stays, so the remaining members keep their majority. A replica set not initiated yet, or a
+// unit which is no member, is left alone.
+func (r *UnitSetReconciler) leaveMongoDBReplicaSet(
+	ctx context.Context,
+	unitset *upmiov1alpha2.UnitSet,
+	units []*upmiov1alpha2.Unit,
+	leaving *upmiov1alpha2.Unit,
+) error {
+	if !isMongoDBReplicaSet(unitset) {
+		return nil
+	}
+
+	var seed *upmiov1alpha2.Unit
+	for _, unit := range units {
+		serialNumber, err := strconv.Atoi(unit.Labels[upmiov1alpha2.UnitSn])
+		if err != nil || serialNumber+1 > unitset.Spec.Units || unit.Status.Phase != upmiov1alpha2.UnitReady {
+			continue
+		}
+		if seed == nil || unit.Name < seed.Name {
+			seed = unit
+		}
+	}
+	if seed == nil {
+		klog.Warningf("[leaveMongoDBReplicaSet] no ready unit of unitset [%s/%s] left to remove unit [%s] from the replica set",
+			unitset.Namespace, unitset.Name, leaving.Name)
+		return nil
+	}
+
+	username := mongoDBUsername(unitset)
+	host := mongoDBMemberHost(leaving)
+
+	var status *mongodb.ReplicaSetStatusResponse
+	if err := r.callUnitAgent(ctx, seed, unitAgentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+		var err error
+		status, err = mongodb.NewMongoDBOperationClient(conn).ReplicaSetStatus(ctx, &mongodb.ReplicaSetStatusRequest{Username: username})
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to query replica set status of unit [%s]: %v", seed.Name, err)
+	}
+
+	for _, member := range status.GetMembers() {
+		if member.GetHost() == host {
+			return r.removeMongoDBMember(ctx, unitset, seed, username, host)
+		}
+	}
+
+	return nil
+}
+
+func (r *UnitSetReconciler) removeMongoDBMember(
+	ctx context.Context,
+	unitset *upmiov1alpha2.UnitSet,
+	seed *upmiov1alpha2.Unit,
+	username, host string,
+) error {
+	if err := r.callUnitAgent(ctx, seed, mongoDBReconfigTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+		_, err := mongodb.NewMongoDBOperationClient(conn).RemoveMember(ctx, &mongodb.RemoveMemberRequest{
+			Username: username,
+			Host:     host,
+		})
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to remove member [%s] from the replica set through unit [%s]: %v", host, seed.Name, err)
+	}
+
+	r.Recorder.Eventf(unitset, v1.EventTypeNormal, "MongoDBMemberRemoved", "member [%s] left the replica set", host)
+	return nil
+}
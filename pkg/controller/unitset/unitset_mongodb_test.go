@@ -0,0 +1,192 @@
+package unitset
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/mongodb"
+	"google.golang.org/grpc"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeReplicaSet is the replica set view shared by the agents of every unit.
+type fakeReplicaSet struct {
+	mu        sync.Mutex
+	initiated bool
+	members   []*mongodb.MemberStatus
+
+	added   []string
+	removed []string
+	// removedBy records the unit each removal went through
+	removedBy []string
+}
+
+type fakeMongoDBAgent struct {
+	mongodb.UnimplementedMongoDBOperationServer
+
+	unit string
+	rs   *fakeReplicaSet
+}
+
+func (a *fakeMongoDBAgent) ReplicaSetStatus(context.Context, *mongodb.ReplicaSetStatusRequest) (*mongodb.ReplicaSetStatusResponse, error) {
+	a.rs.mu.Lock()
+	defer a.rs.mu.Unlock()
+
+	if !a.rs.initiated {
+		return &mongodb.ReplicaSetStatusResponse{Set: "mongo"}, nil
+	}
+	return &mongodb.ReplicaSetStatusResponse{Initiated: true, Set: "mongo", Members: a.rs.members}, nil
+}
+
+func (a *fakeMongoDBAgent) AddMember(_ context.Context, req *mongodb.AddMemberRequest) (*common.Empty, error) {
+	a.rs.mu.Lock()
+	defer a.rs.mu.Unlock()
+	a.rs.added = append(a.rs.added, req.GetMember().GetHost())
+	return &common.Empty{}, nil
+}
+
+func (a *fakeMongoDBAgent) RemoveMember(_ context.Context, req *mongodb.RemoveMemberRequest) (*common.Empty, error) {
+	a.rs.mu.Lock()
+	defer a.rs.mu.Unlock()
+
+	a.rs.removed = append(a.rs.removed, req.GetHost())
+	a.rs.removedBy = append(a.rs.removedBy, a.unit)
+	for i, member := range a.rs.members {
+		if member.GetHost() == req.GetHost() {
+			a.rs.members = append(a.rs.members[:i], a.rs.members[i+1:]...)
+			break
+		}
+	}
+	return &common.Empty{}, nil
+}
+
+func (rs *fakeReplicaSet) set(host, state string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for _, member := range rs.members {
+		if member.GetHost() == host {
+			member.State = state
+			return
+		}
+	}
+	rs.members = append(rs.members, &mongodb.MemberStatus{Host: host, State: state, Healthy: true})
+}
+
+func startMongoDBAgents(t *testing.T, rs *fakeReplicaSet, units ...string) func(*upmiov1alpha2.Unit) (*grpc.ClientConn, error) {
+	return startUnitAgents(t, func(unit string, server *grpc.Server) {
+		mongodb.RegisterMongoDBOperationServer(server, &fakeMongoDBAgent{unit: unit, rs: rs})
+	}, units...)
+}
+
+func newMongoDBUnitSet(units int) *upmiov1alpha2.UnitSet {
+	return &upmiov1alpha2.UnitSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "mongo", Namespace: "default"},
+		Spec:       upmiov1alpha2.UnitSetSpec{Type: "mongodb", Units: units},
+	}
+}
+
+func reconcileMongoDBReplicaSetOnce(t *testing.T, r *UnitSetReconciler) *metav1.Condition {
+	t.Helper()
+
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "default", Name: "mongo"}
+
+	unitset := &upmiov1alpha2.UnitSet{}
+	require.NoError(t, r.Get(ctx, key, unitset))
+	require.NoError(t, r.reconcileMongoDBReplicaSet(ctx, ctrl.Request{NamespacedName: key}, unitset))
+	require.NoError(t, r.Get(ctx, key, unitset))
+
+	return meta.FindStatusCondition(unitset.Status.Conditions, MongoDBReplicaSetScaledCondition)
+}
+
+func TestReconcileMongoDBReplicaSetScaleOut(t *testing.T) {
+	rs := &fakeReplicaSet{}
+	units := []string{"mongo-0", "mongo-1", "mongo-2"}
+	r, _ := newUnitAgentReconciler(t, startMongoDBAgents(t, rs, units...), newMongoDBUnitSet(3), units...)
+
+	// a replica set not initiated yet is left alone
+	assert.Nil(t, reconcileMongoDBReplicaSetOnce(t, r))
+	assert.Empty(t, rs.added)
+
+	rs.initiated = true
+	rs.set("mongo-0.mongo-headless-svc.default:27017", "PRIMARY")
+	rs.set("mongo-1.mongo-headless-svc.default:27017", "SECONDARY")
+	rs.set("mongo-3.mongo-headless-svc.default:27017", "(not reachable/healthy)")
+	rs.set("backup.example.com:27017", "SECONDARY")
+
+	// the member of a deleted unit is removed, members of other hosts are left alone
+	condition := reconcileMongoDBReplicaSetOnce(t, r)
+	require.NotNil(t, condition)
+	assert.Equal(t, "MembersLeaving", condition.Reason)
+	assert.Equal(t, []string{"mongo-3.mongo-headless-svc.default:27017"}, rs.removed)
+
+	// the new unit joins through the first initiated unit
+	condition = reconcileMongoDBReplicaSetOnce(t, r)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "MembersJoining", condition.Reason)
+	assert.Equal(t, []string{"mongo-2.mongo-headless-svc.default:27017"}, rs.added)
+
+	rs.set("mongo-2.mongo-headless-svc.default:27017", "STARTUP2")
+	condition = reconcileMongoDBReplicaSetOnce(t, r)
+	assert.Equal(t, "MembersSyncing", condition.Reason)
+	assert.Equal(t, "waiting for members [mongo-2(STARTUP2)] to sync", condition.Message)
+
+	rs.set("mongo-2.mongo-headless-svc.default:27017", "SECONDARY")
+	condition = reconcileMongoDBReplicaSetOnce(t, r)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "Synced", condition.Reason)
+	assert.Len(t, rs.added, 1)
+}
+
+func TestReconcileMongoDBReplicaSetAddsOneMemberAtATime(t *testing.T) {
+	rs := &fakeReplicaSet{initiated: true}
+	rs.set("mongo-0.mongo-headless-svc.default:27017", "PRIMARY")
+
+	units := []string{"mongo-0", "mongo-1", "mongo-2"}
+	r, _ := newUnitAgentReconciler(t, startMongoDBAgents(t, rs, units...), newMongoDBUnitSet(3), units...)
+
+	condition := reconcileMongoDBReplicaSetOnce(t, r)
+	assert.Equal(t, "waiting for units [mongo-1,mongo-2] to join the replica set", condition.Message)
+	assert.Equal(t, []string{"mongo-1.mongo-headless-svc.default:27017"}, rs.added)
+}
+
+func TestRemoveUnitsLeavesMongoDBReplicaSet(t *testing.T) {
+	rs := &fakeReplicaSet{initiated: true}
+	rs.set("mongo-0.mongo-headless-svc.default:27017", "SECONDARY")
+	rs.set("mongo-1.mongo-headless-svc.default:27017", "SECONDARY")
+	rs.set("mongo-2.mongo-headless-svc.default:27017", "PRIMARY")
+
+	units := []string{"mongo-0", "mongo-1", "mongo-2", "mongo-3"}
+	r, kUnits := newUnitAgentReconciler(t, startMongoDBAgents(t, rs, units...), newMongoDBUnitSet(2), units...)
+
+	out, err := r.removeUnits(context.Background(), newMongoDBUnitSet(2), kUnits)
+	require.NoError(t, err)
+	assert.Len(t, out, 2)
+
+	// the members leave through the first remaining unit, mongo-3 never joined
+	assert.Equal(t, []string{"mongo-2.mongo-headless-svc.default:27017"}, rs.removed)
+	assert.Equal(t, []string{"mongo-0"}, rs.removedBy)
+
+	for _, name := range []string{"mongo-2", "mongo-3"} {
+		err := r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, &upmiov1alpha2.Unit{})
+		assert.True(t, apierrors.IsNotFound(err), name)
+	}
+}
+
+func TestIsMongoDBUnitsetMember(t *testing.T) {
+	unitset := newMongoDBUnitSet(3)
+	assert.True(t, isMongoDBUnitsetMember(unitset, "mongo-3.mongo-headless-svc.default:27017"))
+	assert.False(t, isMongoDBUnitsetMember(unitset, "mongo-3.other-headless-svc.default:27017"))
+	assert.False(t, isMongoDBUnitsetMember(unitset, "10.0.0.3:27017"))
+}
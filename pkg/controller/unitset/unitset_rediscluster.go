@@ -0,0 +1,651 @@
+package unitset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/rediscluster"
+	"github.com/upmio/unit-operator/pkg/agent/vars"
+	unitAgent "github.com/upmio/unit-operator/pkg/client/unit-agent"
+	"google.golang.org/grpc"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// RedisClusterScaledCondition reports whether the units of a Redis Cluster UnitSet all serve the cluster
+	RedisClusterScaledCondition = "RedisClusterScaled"
+
+	redisClusterArchMode = "cluster"
+
+	// redisClusterDefaultUser is used when the UnitSet sets no ADM_USER
+	redisClusterDefaultUser = "default"
+	redisClusterPort        = 6379
+
+	// redisClusterRebalanceBatch bounds the slots moved by a single reconcile
+	redisClusterRebalanceBatch = 128
+
+	// redisClusterRebalanceTimeout bounds the start of a batch, the whole batch when the unit-agent runs it synchronously
+	redisClusterRebalanceTimeout = 5 * time.Minute
+
+	// redisClusterRebalanceOperation is the key of the running batch of the rebalance in the UnitSet status
+	redisClusterRebalanceOperation = "redis-cluster-rebalance"
+
+	// unitAgentCallTimeout bounds a single call to a unit-agent
+	unitAgentCallTimeout = 30 * time.Second
+)
+
+// isRedisCluster reports whether the UnitSet runs Redis in cluster mode.
+func isRedisCluster(unitset *upmiov1alpha2.UnitSet) bool {
+	return unitset.Spec.Type == "redis" && unitsetEnv(unitset, vars.ArchModeEnvKey) == redisClusterArchMode
+}
+
+func unitsetEnv(unitset *upmiov1alpha2.UnitSet, name string) string {
+	for _, env := range unitset.Spec.Env {
+		if env.Name == name {
+			return env.Value
+		}
+	}
+
+	return ""
+}
+
+// reconcileRedisCluster takes the units of a scale out into the Redis Cluster once every unit is ready.
+// The new nodes meet the cluster, then become replicas of the masters short of the replicas asked by
+// the unit-operator/redis-cluster.replicas annotation, and the slots are rebalanced over the masters
+// in batches run as unit-agent operations, the progress is reported by the RedisClusterScaled condition.
+// The nodes whose unit a scale in removed are forgotten once the cluster failed them. A cluster not created yet, no unit owns slots,
+// is left alone.
+func (r *UnitSetReconciler) reconcileRedisCluster(ctx context.Context, req ctrl.Request, unitset *upmiov1alpha2.UnitSet) error {
+	if !isRedisCluster(unitset) {
+		return nil
+	}
+
+	units, err := r.unitsBelongUnitset(ctx, unitset)
+	if err != nil {
+		return fmt.Errorf("[reconcileRedisCluster] list units err:[%v]", err)
+	}
+
+	if len(units) != unitset.Spec.Units {
+		return nil
+	}
+
+	for _, unit := range units {
+		if unit.Status.Phase != upmiov1alpha2.UnitReady {
+			return nil
+		}
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].Name < units[j].Name
+	})
+
+	username := redisClusterUsername(unitset)
+
+	views := make(map[string]*rediscluster.ClusterNodesResponse, len(units))
+	for _, unit := range units {
+		if err := r.callUnitAgent(ctx, unit, unitAgentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			resp, err := rediscluster.NewRedisClusterOperationClient(conn).ClusterNodes(ctx, &rediscluster.ClusterNodesRequest{Username: username})
+			views[unit.Name] = resp
+			return err
+		}); err != nil {
+			return fmt.Errorf("[reconcileRedisCluster] failed to query cluster nodes of unit [%s]: %v", unit.Name, err)
+		}
+	}
+
+	var seed *upmiov1alpha2.Unit
+	for _, unit := range units {
+		if len(views[unit.Name].Myself().GetSlots()) > 0 {
+			seed = unit
+			break
+		}
+	}
+	if seed == nil {
+		return nil
+	}
+
+	known := make(map[string]*rediscluster.ClusterNode)
+	for _, node := range views[seed.Name].GetNodes() {
+		known[node.GetId()] = node
+	}
+
+	// The nodes of the units a scale in removed leave the cluster before it is changed any further
+	leaving, err := r.forgetRedisClusterNodes(ctx, unitset, units, views, seed, username)
+	if err != nil {
+		return err
+	}
+	if len(leaving) > 0 {
+		return r.setRedisClusterCondition(ctx, unitset, metav1.ConditionFalse, "NodesLeaving",
+			fmt.Sprintf("waiting for the cluster to fail nodes [%s] no unit runs anymore", strings.Join(leaving, ",")))
+	}
+
+	// The units the seed does not know yet are empty nodes of a scale out
+	var joining []string
+	for _, unit := range units {
+		node, ok := known[views[unit.Name].GetMyId()]
+		if ok && !hasClusterFlag(node, "handshake") {
+			continue
+		}
+
+		joining = append(joining, unit.Name)
+		if ok {
+			continue
+		}
+
+		if err := r.callUnitAgent(ctx, unit, unitAgentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			_, err := rediscluster.NewRedisClusterOperationClient(conn).AddNode(ctx, &rediscluster.AddNodeRequest{
+				Username: username,
+				Host:     unitAgent.UnitHost(seed),
+				Port:     redisClusterPort,
+			})
+			return err
+		}); err != nil {
+			return fmt.Errorf("[reconcileRedisCluster] failed to add unit [%s] to the cluster: %v", unit.Name, err)
+		}
+
+		r.Recorder.Eventf(unitset, v1.EventTypeNormal, "RedisClusterNodeAdded", "unit [%s] met the redis cluster through [%s]", unit.Name, seed.Name)
+	}
+
+	if len(joining) > 0 {
+		return r.setRedisClusterCondition(ctx, unitset, metav1.ConditionFalse, "NodesJoining",
+			fmt.Sprintf("waiting for units [%s] to join the cluster", strings.Join(joining, ",")))
+	}
+
+	replicas, err := redisClusterReplicas(unitset)
+	if err != nil {
+		return err
+	}
+
+	assignments := assignRedisClusterReplicas(units, views, known, replicas)
+	for _, unit := range units {
+		masterID, ok := assignments[unit.Name]
+		if !ok {
+			continue
+		}
+
+		if err := r.callUnitAgent(ctx, unit, unitAgentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			_, err := rediscluster.NewRedisClusterOperationClient(conn).Replicate(ctx, &rediscluster.ReplicateRequest{
+				Username: username,
+				MasterId: masterID,
+			})
+			return err
+		}); err != nil {
+			return fmt.Errorf("[reconcileRedisCluster] failed to replicate unit [%s] to master [%s]: %v", unit.Name, masterID, err)
+		}
+
+		r.Recorder.Eventf(unitset, v1.EventTypeNormal, "RedisClusterReplicaAssigned", "unit [%s] replicates master [%s]", unit.Name, masterID)
+	}
+
+	if len(assignments) > 0 {
+		return r.setRedisClusterCondition(ctx, unitset, metav1.ConditionFalse, "AssigningReplicas",
+			fmt.Sprintf("%d units assigned as replicas", len(assignments)))
+	}
+
+	// Rebalance once a master of the scale out holds no slot, and until the rebalance completed
+	emptyMaster := false
+	for _, node := range known {
+		if hasClusterFlag(node, "master") && !hasClusterFlag(node, "fail") && len(node.GetSlots()) == 0 {
+			emptyMaster = true
+		}
+	}
+
+	_, running := unitset.Status.Operations[redisClusterRebalanceOperation]
+	condition := meta.FindStatusCondition(unitset.Status.Conditions, RedisClusterScaledCondition)
+	if !running && !emptyMaster && (condition == nil || condition.Status == metav1.ConditionTrue) {
+		return nil
+	}
+
+	resp, err := r.rebalanceRedisCluster(ctx, unitset, units, seed, username, nil)
+	if err != nil {
+		return fmt.Errorf("[reconcileRedisCluster] failed to rebalance the cluster through unit [%s]: %v", seed.Name, err)
+	}
+	if resp == nil {
+		return r.setRedisClusterCondition(ctx, unitset, metav1.ConditionFalse, "Rebalancing",
+			"a batch of the rebalance is running")
+	}
+
+	klog.Infof("[reconcileRedisCluster] unitset [%s] migrated %d slots (%d keys), %d slots remaining",
+		req.String(), resp.GetMigratedSlots(), resp.GetMigratedKeys(), resp.GetRemainingSlots())
+
+	if resp.GetRemainingSlots() > 0 {
+		return r.setRedisClusterCondition(ctx, unitset, metav1.ConditionFalse, "Rebalancing",
+			fmt.Sprintf("migrated %d slots (%d keys) in the last batch, %d slots remaining",
+				resp.GetMigratedSlots(), resp.GetMigratedKeys(), resp.GetRemainingSlots()))
+	}
+
+	r.Recorder.Eventf(unitset, v1.EventTypeNormal, "RedisClusterRebalanced", "redis cluster slots are balanced over the masters")
+	return r.setRedisClusterCondition(ctx, unitset, metav1.ConditionTrue, "Balanced",
+		fmt.Sprintf("%d units serve the cluster", len(units)))
+}
+
+// drainRedisCluster hands the slots of the masters of a scale in over to the masters kept, in batches
+// run as unit-agent operations. It reports whether the units of the scale in own no slot anymore and
+// can be removed, reconcileRedisCluster forgets their nodes once they are gone. A unit which is not
+// ready is removed as is, the cluster fails its replica over.
+func (r *UnitSetReconciler) drainRedisCluster(ctx context.Context, unitset *upmiov1alpha2.UnitSet, units []*upmiov1alpha2.Unit) (bool, error) {
+	if !isRedisCluster(unitset) {
+		return true, nil
+	}
+
+	var (
+		seed    *upmiov1alpha2.Unit
+		leaving []*upmiov1alpha2.Unit
+	)
+	for _, unit := range units {
+		serialNumber, err := strconv.Atoi(unit.Labels[upmiov1alpha2.UnitSn])
+		if err != nil {
+			return false, fmt.Errorf("get unit:[%s] serial number error:[%s]", unit.Name, err.Error())
+		}
+
+		if unit.Status.Phase != upmiov1alpha2.UnitReady {
+			continue
+		}
+
+		if serialNumber+1 > unitset.Spec.Units {
+			leaving = append(leaving, unit)
+			continue
+		}
+
+		if seed == nil || unit.Name < seed.Name {
+			seed = unit
+		}
+	}
+	if seed == nil {
+		klog.Warningf("[drainRedisCluster] no ready unit of unitset [%s/%s] left to take the slots of the scale in",
+			unitset.Namespace, unitset.Name)
+		return true, nil
+	}
+
+	username := redisClusterUsername(unitset)
+
+	// A batch still running finishes before the slots left are looked at again
+	var drain []string
+	if _, running := unitset.Status.Operations[redisClusterRebalanceOperation]; !running {
+		var err error
+		if drain, err = r.redisClusterMastersWithSlots(ctx, seed, leaving, username); err != nil {
+			return false, err
+		}
+
+		if len(drain) == 0 {
+			return true, nil
+		}
+	}
+
+	resp, err := r.rebalanceRedisCluster(ctx, unitset, units, seed, username, drain)
+	if err != nil {
+		return false, fmt.Errorf("failed to drain the cluster through unit [%s]: %v", seed.Name, err)
+	}
+
+	message := "a batch of the rebalance is running"
+	if resp != nil {
+		message = fmt.Sprintf("migrated %d slots (%d keys) off the units of the scale in in the last batch",
+			resp.GetMigratedSlots(), resp.GetMigratedKeys())
+	}
+
+	return false, r.setRedisClusterCondition(ctx, unitset, metav1.ConditionFalse, "Draining", message)
+}
+
+// redisClusterMastersWithSlots returns the ids of the nodes of the units which still own slots.
+func (r *UnitSetReconciler) redisClusterMastersWithSlots(
+	ctx context.Context,
+	seed *upmiov1alpha2.Unit,
+	units []*upmiov1alpha2.Unit,
+	username string,
+) ([]string, error) {
+	if len(units) == 0 {
+		return nil, nil
+	}
+
+	var nodes *rediscluster.ClusterNodesResponse
+	if err := r.callUnitAgent(ctx, seed, unitAgentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+		var err error
+		nodes, err = rediscluster.NewRedisClusterOperationClient(conn).ClusterNodes(ctx, &rediscluster.ClusterNodesRequest{Username: username})
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to query cluster nodes of unit [%s]: %v", seed.Name, err)
+	}
+
+	owned := make(map[string]bool)
+	for _, node := range nodes.GetNodes() {
+		if len(node.GetSlots()) > 0 {
+			owned[node.GetId()] = true
+		}
+	}
+
+	var ids []string
+	for _, unit := range units {
+		var myID string
+		if err := r.callUnitAgent(ctx, unit, unitAgentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			resp, err := rediscluster.NewRedisClusterOperationClient(conn).ClusterNodes(ctx, &rediscluster.ClusterNodesRequest{Username: username})
+			myID = resp.GetMyId()
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("failed to query cluster nodes of unit [%s]: %v", unit.Name, err)
+		}
+
+		if owned[myID] {
+			ids = append(ids, myID)
+		}
+	}
+
+	return ids, nil
+}
+
+// forgetRedisClusterNodes makes the cluster forget the nodes no unit runs anymore, the nodes of the
+// units a scale in removed, once the cluster failed them. It returns the nodes still waited for.
+func (r *UnitSetReconciler) forgetRedisClusterNodes(
+	ctx context.Context,
+	unitset *upmiov1alpha2.UnitSet,
+	units []*upmiov1alpha2.Unit,
+	views map[string]*rediscluster.ClusterNodesResponse,
+	seed *upmiov1alpha2.Unit,
+	username string,
+) ([]string, error) {
+	running := make(map[string]bool, len(units))
+	for _, unit := range units {
+		running[views[unit.Name].GetMyId()] = true
+	}
+
+	var gone, leaving []string
+	for _, node := range views[seed.Name].GetNodes() {
+		if running[node.GetId()] || hasClusterFlag(node, "handshake") {
+			continue
+		}
+
+		if len(node.GetSlots()) == 0 && (hasClusterFlag(node, "fail") || hasClusterFlag(node, "noaddr")) {
+			gone = append(gone, node.GetId())
+			continue
+		}
+
+		leaving = append(leaving, node.GetId())
+	}
+	sort.Strings(gone)
+	sort.Strings(leaving)
+
+	for _, id := range gone {
+		if err := r.callUnitAgent(ctx, seed, unitAgentCallTimeout, func(ctx context.Context, conn grpc.ClientConnInterface) error {
+			_, err := rediscluster.NewRedisClusterOperationClient(conn).ForgetNode(ctx, &rediscluster.ForgetNodeRequest{
+				Username: username,
+				NodeId:   id,
+			})
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("[reconcileRedisCluster] failed to forget node [%s] through unit [%s]: %v", id, seed.Name, err)
+		}
+
+		r.Recorder.Eventf(unitset, v1.EventTypeNormal, "RedisClusterNodeForgotten", "the redis cluster forgot node [%s] no unit runs anymore", id)
+	}
+
+	return leaving, nil
+}
+
+// rebalanceRedisCluster runs a batch of the rebalance as a unit-agent operation of the seed, recorded in
+// the UnitSet status so that the following reconciles poll it instead of starting another batch. It
+// returns the response of the batch once it finished, nil while it runs.
+func (r *UnitSetReconciler) rebalanceRedisCluster(
+	ctx context.Context,
+	unitset *upmiov1alpha2.UnitSet,
+	units []*upmiov1alpha2.Unit,
+	seed *upmiov1alpha2.Unit,
+	username string,
+	drain []string,
+) (*rediscluster.RebalanceResponse, error) {
+	resp := &rediscluster.RebalanceResponse{}
+
+	if op, ok := unitset.Status.Operations[redisClusterRebalanceOperation]; ok {
+		var unit *upmiov1alpha2.Unit
+		for _, one := range units {
+			if one.Name == op.Unit {
+				unit = one
+			}
+		}
+		// the operation went away with its unit
+		if unit == nil {
+			return nil, r.setUnitsetOperation(ctx, unitset, redisClusterRebalanceOperation, nil)
+		}
+
+		done, _, err := unitAgent.PollOperationResult(ctx, unit, r.agentDialer(), unitAgentCallTimeout, op.ID, resp)
+		if errors.Is(err, unitAgent.ErrOperationFailed) {
+			if clearErr := r.setUnitsetOperation(ctx, unitset, redisClusterRebalanceOperation, nil); clearErr != nil {
+				return nil, clearErr
+			}
+			return nil, err
+		}
+		if err != nil || !done {
+			return nil, err
+		}
+
+		return resp, r.setUnitsetOperation(ctx, unitset, redisClusterRebalanceOperation, nil)
+	}
+
+	// The operation is recorded first, a start lost on its way is found missing by the next poll
+	op := &upmiov1alpha2.UnitSetOperation{
+		Unit: seed.Name,
+		ID:   fmt.Sprintf("%s-%d", redisClusterRebalanceOperation, time.Now().UnixNano()),
+	}
+	if err := r.setUnitsetOperation(ctx, unitset, redisClusterRebalanceOperation, op); err != nil {
+		return nil, err
+	}
+
+	id, err := unitAgent.StartOperation(ctx, seed, r.agentDialer(), redisClusterRebalanceTimeout, op.ID,
+		func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) error {
+			var err error
+			resp, err = rediscluster.NewRedisClusterOperationClient(conn).Rebalance(ctx, &rediscluster.RebalanceRequest{
+				Username: username,
+				MaxSlots: redisClusterRebalanceBatch,
+				DrainIds: drain,
+			}, opts...)
+			return err
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// the unit-agent ran the batch synchronously
+	if id == "" {
+		return resp, r.setUnitsetOperation(ctx, unitset, redisClusterRebalanceOperation, nil)
+	}
+
+	return nil, nil
+}
+
+// assignRedisClusterReplicas returns the master each master without slots should replicate, in unit name
+// order the units go to the master with the fewest replicas as long as it has less than replicas.
+func assignRedisClusterReplicas(
+	units []*upmiov1alpha2.Unit,
+	views map[string]*rediscluster.ClusterNodesResponse,
+	known map[string]*rediscluster.ClusterNode,
+	replicas int,
+) map[string]string {
+	assignments := make(map[string]string)
+	if replicas <= 0 {
+		return assignments
+	}
+
+	counts := make(map[string]int)
+	for _, node := range known {
+		if hasClusterFlag(node, "master") && len(node.GetSlots()) > 0 {
+			counts[node.GetId()] = 0
+		}
+	}
+	for _, node := range known {
+		if _, ok := counts[node.GetMasterId()]; ok {
+			counts[node.GetMasterId()]++
+		}
+	}
+
+	masters := make([]string, 0, len(counts))
+	for id := range counts {
+		masters = append(masters, id)
+	}
+
+	for _, unit := range units {
+		node := known[views[unit.Name].GetMyId()]
+		if !hasClusterFlag(node, "master") || len(node.GetSlots()) > 0 {
+			continue
+		}
+
+		sort.Slice(masters, func(i, j int) bool {
+			if counts[masters[i]] != counts[masters[j]] {
+				return counts[masters[i]] < counts[masters[j]]
+			}
+			return masters[i] < masters[j]
+		})
+
+		if len(masters) == 0 || counts[masters[0]] >= replicas {
+			break
+		}
+
+		assignments[unit.Name] = masters[0]
+		counts[masters[0]]++
+	}
+
+	return assignments
+}
+
+func redisClusterUsername(unitset *upmiov1alpha2.UnitSet) string {
+	if username := unitsetEnv(unitset, "ADM_USER"); username != "" {
+		return username
+	}
+
+	return redisClusterDefaultUser
+}
+
+func redisClusterReplicas(unitset *upmiov1alpha2.UnitSet) (int, error) {
+	value, ok := unitset.Annotations[upmiov1alpha2.AnnotationRedisClusterReplicas]
+	if !ok || value == "" {
+		return 0, nil
+	}
+
+	replicas, err := strconv.Atoi(value)
+	if err != nil || replicas < 0 {
+		return 0, fmt.Errorf("invalid annotation %s: %q", upmiov1alpha2.AnnotationRedisClusterReplicas, value)
+	}
+
+	return replicas, nil
+}
+
+func hasClusterFlag(node *rediscluster.ClusterNode, flag string) bool {
+	for _, f := range node.GetFlags() {
+		if f == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setRedisClusterCondition sets the RedisClusterScaled condition on the latest UnitSet.
+func (r *UnitSetReconciler) setRedisClusterCondition(
+	ctx context.Context,
+	unitset *upmiov1alpha2.UnitSet,
+	status metav1.ConditionStatus,
+	reason, message string,
+) error {
+	return r.setUnitsetCondition(ctx, unitset, RedisClusterScaledCondition, status, reason, message)
+}
+
+// setUnitsetCondition sets the condition on the latest UnitSet, the status of the UnitSet
+// being reconciled may be stale by now.
+func (r *UnitSetReconciler) setUnitsetCondition(
+	ctx context.Context,
+	unitset *upmiov1alpha2.UnitSet,
+	conditionType string,
+	status metav1.ConditionStatus,
+	reason, message string,
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &upmiov1alpha2.UnitSet{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(unitset), latest); err != nil {
+			return err
+		}
+
+		if !meta.SetStatusCondition(&latest.Status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: latest.Generation,
+		}) {
+			return nil
+		}
+
+		return r.Status().Update(ctx, latest)
+	})
+}
+
+// setUnitsetOperation records the operation under key in the status of the latest UnitSet, a nil
+// operation removes the key. The UnitSet being reconciled is updated alike.
+func (r *UnitSetReconciler) setUnitsetOperation(
+	ctx context.Context,
+	unitset *upmiov1alpha2.UnitSet,
+	key string,
+	op *upmiov1alpha2.UnitSetOperation,
+) error {
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &upmiov1alpha2.UnitSet{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(unitset), latest); err != nil {
+			return err
+		}
+
+		if !setOperation(&latest.Status, key, op) {
+			return nil
+		}
+
+		return r.Status().Update(ctx, latest)
+	}); err != nil {
+		return fmt.Errorf("failed to record operation [%s] of unitset [%s/%s]: %v", key, unitset.Namespace, unitset.Name, err)
+	}
+
+	setOperation(&unitset.Status, key, op)
+	return nil
+}
+
+// setOperation sets or removes the operation under key, it reports whether the status changed.
+func setOperation(status *upmiov1alpha2.UnitSetStatus, key string, op *upmiov1alpha2.UnitSetOperation) bool {
+	current, ok := status.Operations[key]
+	if op == nil {
+		delete(status.Operations, key)
+		return ok
+	}
+
+	if ok && current == *op {
+		return false
+	}
+
+	if status.Operations == nil {
+		status.Operations = make(map[string]upmiov1alpha2.UnitSetOperation)
+	}
+	status.Operations[key] = *op
+	return true
+}
+
+// callUnitAgent calls fn with a connection to the unit-agent of the unit, bounded by timeout.
+func (r *UnitSetReconciler) callUnitAgent(
+	ctx context.Context,
+	unit *upmiov1alpha2.Unit,
+	timeout time.Duration,
+	fn func(context.Context, grpc.ClientConnInterface) error,
+) error {
+	return unitAgent.Call(ctx, unit, r.agentDialer(), timeout, fn)
+}
+
+// agentDialer returns how the reconciler connects to the unit-agents.
+func (r *UnitSetReconciler) agentDialer() unitAgent.DialFunc {
+	if r.dialAgent != nil {
+		return r.dialAgent
+	}
+
+	return unitAgent.Dial
+}
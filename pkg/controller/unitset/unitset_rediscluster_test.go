@@ -0,0 +1,453 @@
+package unitset
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	upmiov1alpha2 "github.com/upmio/unit-operator/api/v1alpha2"
+	"github.com/upmio/unit-operator/pkg/agent/app/common"
+	"github.com/upmio/unit-operator/pkg/agent/app/operation"
+	"github.com/upmio/unit-operator/pkg/agent/app/rediscluster"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeRedisCluster is the cluster view shared by the agents of every unit.
+type fakeRedisCluster struct {
+	mu sync.Mutex
+	// known is the nodes the cluster knows, by id
+	known map[string]*rediscluster.ClusterNode
+
+	added      map[string]*rediscluster.AddNodeRequest
+	replicated map[string]string
+	rebalances []*rediscluster.RebalanceRequest
+	remaining  int64
+	forgotten  []string
+
+	// async runs the rebalances as operations left running until finished
+	async      bool
+	operations map[string]*operation.Operation
+}
+
+// fakeRedisClusterNode is the unit-agent of a single node.
+type fakeRedisClusterNode struct {
+	rediscluster.UnimplementedRedisClusterOperationServer
+	operation.UnimplementedOperationsServer
+
+	id      string
+	cluster *fakeRedisCluster
+}
+
+func (n *fakeRedisClusterNode) ClusterNodes(context.Context, *rediscluster.ClusterNodesRequest) (*rediscluster.ClusterNodesResponse, error) {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+
+	resp := &rediscluster.ClusterNodesResponse{MyId: n.id, State: "ok"}
+	if _, ok := n.cluster.known[n.id]; !ok {
+		resp.Nodes = append(resp.Nodes, &rediscluster.ClusterNode{Id: n.id, Flags: []string{"myself", "master"}})
+		return resp, nil
+	}
+
+	for _, node := range n.cluster.known {
+		resp.Nodes = append(resp.Nodes, node)
+	}
+	return resp, nil
+}
+
+func (n *fakeRedisClusterNode) AddNode(_ context.Context, req *rediscluster.AddNodeRequest) (*common.Empty, error) {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	n.cluster.added[n.id] = req
+	return &common.Empty{}, nil
+}
+
+func (n *fakeRedisClusterNode) Replicate(_ context.Context, req *rediscluster.ReplicateRequest) (*common.Empty, error) {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	n.cluster.replicated[n.id] = req.GetMasterId()
+	return &common.Empty{}, nil
+}
+
+func (n *fakeRedisClusterNode) Rebalance(ctx context.Context, req *rediscluster.RebalanceRequest) (*rediscluster.RebalanceResponse, error) {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	n.cluster.rebalances = append(n.cluster.rebalances, req)
+	resp := &rediscluster.RebalanceResponse{PlannedSlots: 128 + n.cluster.remaining, MigratedSlots: 128, MigratedKeys: 1000, RemainingSlots: n.cluster.remaining}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if !n.cluster.async || len(md.Get(operation.AsyncMetadataKey)) == 0 {
+		return resp, nil
+	}
+
+	// the response is the one of the operation once finished
+	id := md.Get(operation.IDMetadataKey)[0]
+	response, err := anypb.New(resp)
+	if err != nil {
+		return nil, err
+	}
+	n.cluster.operations[id] = &operation.Operation{Id: id, State: operation.State_RUNNING, Response: response}
+	return &rediscluster.RebalanceResponse{}, grpc.SetHeader(ctx, metadata.Pairs(operation.IDMetadataKey, id))
+}
+
+func (n *fakeRedisClusterNode) GetOperation(_ context.Context, req *operation.GetOperationRequest) (*operation.Operation, error) {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+
+	op, ok := n.cluster.operations[req.GetId()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "operation %s not found", req.GetId())
+	}
+	return op, nil
+}
+
+func (n *fakeRedisClusterNode) ForgetNode(_ context.Context, req *rediscluster.ForgetNodeRequest) (*common.Empty, error) {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	n.cluster.forgotten = append(n.cluster.forgotten, req.GetNodeId())
+	delete(n.cluster.known, req.GetNodeId())
+	return &common.Empty{}, nil
+}
+
+// finishOperations ends the running operations in state.
+func (c *fakeRedisCluster) finishOperations(state operation.State) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, op := range c.operations {
+		if op.GetState() == operation.State_RUNNING {
+			op.State = state
+		}
+	}
+}
+
+func (c *fakeRedisCluster) join(id string, flags []string, masterID string, slots ...*rediscluster.SlotRange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.known[id] = &rediscluster.ClusterNode{Id: id, Flags: flags, MasterId: masterID, Slots: slots}
+}
+
+// startUnitAgents serves a fake agent per unit, registered by register, and returns a dial function
+// routing each unit to its agent.
+func startUnitAgents(t *testing.T, register func(unit string, server *grpc.Server), units ...string) func(*upmiov1alpha2.Unit) (*grpc.ClientConn, error) {
+	t.Helper()
+
+	addrs := make(map[string]string, len(units))
+	for _, name := range units {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		server := grpc.NewServer()
+		register(name, server)
+		go func() { _ = server.Serve(lis) }()
+		t.Cleanup(server.Stop)
+
+		addrs[name] = lis.Addr().String()
+	}
+
+	return func(unit *upmiov1alpha2.Unit) (*grpc.ClientConn, error) {
+		return grpc.NewClient(addrs[unit.Name], grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+}
+
+func startRedisClusterAgents(t *testing.T, cluster *fakeRedisCluster, units ...string) func(*upmiov1alpha2.Unit) (*grpc.ClientConn, error) {
+	return startUnitAgents(t, func(unit string, server *grpc.Server) {
+		node := &fakeRedisClusterNode{id: "node-" + unit, cluster: cluster}
+		rediscluster.RegisterRedisClusterOperationServer(server, node)
+		operation.RegisterOperationsServer(server, node)
+	}, units...)
+}
+
+func newRedisClusterUnitSet(units int) *upmiov1alpha2.UnitSet {
+	return &upmiov1alpha2.UnitSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "redis", Namespace: "default"},
+		Spec: upmiov1alpha2.UnitSetSpec{
+			Type:  "redis",
+			Units: units,
+			Env:   []v1.EnvVar{{Name: "ARCH_MODE", Value: "cluster"}, {Name: "ADM_USER", Value: "admin"}},
+		},
+	}
+}
+
+func newRedisClusterReconciler(t *testing.T, dial func(*upmiov1alpha2.Unit) (*grpc.ClientConn, error), unitset *upmiov1alpha2.UnitSet, units ...string) *UnitSetReconciler {
+	t.Helper()
+
+	r, _ := newUnitAgentReconciler(t, dial, unitset, units...)
+	return r
+}
+
+// newUnitAgentReconciler returns a reconciler calling the unit-agents through dial, the ready units
+// of the UnitSet are numbered in order.
+func newUnitAgentReconciler(
+	t *testing.T,
+	dial func(*upmiov1alpha2.Unit) (*grpc.ClientConn, error),
+	unitset *upmiov1alpha2.UnitSet,
+	units ...string,
+) (*UnitSetReconciler, []*upmiov1alpha2.Unit) {
+	t.Helper()
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, upmiov1alpha2.AddToScheme(s))
+
+	objs := []client.Object{unitset}
+	kUnits := make([]*upmiov1alpha2.Unit, 0, len(units))
+	for i, name := range units {
+		unit := &upmiov1alpha2.Unit{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					upmiov1alpha2.UnitsetName: unitset.Name,
+					upmiov1alpha2.UnitSn:      strconv.Itoa(i),
+				},
+			},
+			Status: upmiov1alpha2.UnitStatus{Phase: upmiov1alpha2.UnitReady},
+		}
+		objs = append(objs, unit)
+		kUnits = append(kUnits, unit)
+	}
+
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithStatusSubresource(&upmiov1alpha2.UnitSet{}, &upmiov1alpha2.Unit{}).
+		WithObjects(objs...).
+		Build()
+
+	return &UnitSetReconciler{Client: c, Scheme: s, Recorder: record.NewFakeRecorder(20), dialAgent: dial}, kUnits
+}
+
+func reconcileRedisClusterOnce(t *testing.T, r *UnitSetReconciler) *upmiov1alpha2.UnitSet {
+	t.Helper()
+
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "default", Name: "redis"}
+
+	unitset := &upmiov1alpha2.UnitSet{}
+	require.NoError(t, r.Get(ctx, key, unitset))
+	require.NoError(t, r.reconcileRedisCluster(ctx, ctrl.Request{NamespacedName: key}, unitset))
+	require.NoError(t, r.Get(ctx, key, unitset))
+
+	return unitset
+}
+
+func TestReconcileRedisClusterScaleOutWithReplicas(t *testing.T) {
+	cluster := &fakeRedisCluster{known: map[string]*rediscluster.ClusterNode{}, added: map[string]*rediscluster.AddNodeRequest{}, replicated: map[string]string{}}
+	cluster.join("node-redis-0", []string{"master"}, "", &rediscluster.SlotRange{Start: 0, End: 8191})
+	cluster.join("node-redis-1", []string{"master"}, "", &rediscluster.SlotRange{Start: 8192, End: 16383})
+
+	units := []string{"redis-0", "redis-1", "redis-2", "redis-3"}
+	unitset := newRedisClusterUnitSet(4)
+	unitset.Annotations = map[string]string{upmiov1alpha2.AnnotationRedisClusterReplicas: "1"}
+	r := newRedisClusterReconciler(t, startRedisClusterAgents(t, cluster, units...), unitset, units...)
+
+	// the new units meet the cluster through the first unit owning slots
+	unitset = reconcileRedisClusterOnce(t, r)
+	require.Len(t, cluster.added, 2)
+	assert.Equal(t, "redis-0.redis-headless-svc.default.svc", cluster.added["node-redis-2"].GetHost())
+	assert.Equal(t, int64(6379), cluster.added["node-redis-3"].GetPort())
+	assert.Equal(t, "admin", cluster.added["node-redis-3"].GetUsername())
+	condition := meta.FindStatusCondition(unitset.Status.Conditions, RedisClusterScaledCondition)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "NodesJoining", condition.Reason)
+
+	// the joined units replicate the masters short of a replica
+	cluster.join("node-redis-2", []string{"master"}, "")
+	cluster.join("node-redis-3", []string{"master"}, "")
+	unitset = reconcileRedisClusterOnce(t, r)
+	assert.Equal(t, map[string]string{"node-redis-2": "node-redis-0", "node-redis-3": "node-redis-1"}, cluster.replicated)
+	assert.Equal(t, "AssigningReplicas", meta.FindStatusCondition(unitset.Status.Conditions, RedisClusterScaledCondition).Reason)
+	assert.Empty(t, cluster.rebalances)
+
+	// the scale out completes with a rebalance finding nothing to move
+	cluster.join("node-redis-2", []string{"slave"}, "node-redis-0")
+	cluster.join("node-redis-3", []string{"slave"}, "node-redis-1")
+	unitset = reconcileRedisClusterOnce(t, r)
+	require.Len(t, cluster.rebalances, 1)
+	condition = meta.FindStatusCondition(unitset.Status.Conditions, RedisClusterScaledCondition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "Balanced", condition.Reason)
+
+	// a balanced cluster is not rebalanced again
+	reconcileRedisClusterOnce(t, r)
+	assert.Len(t, cluster.rebalances, 1)
+}
+
+func TestReconcileRedisClusterRebalancesInBatches(t *testing.T) {
+	cluster := &fakeRedisCluster{known: map[string]*rediscluster.ClusterNode{}, added: map[string]*rediscluster.AddNodeRequest{}, replicated: map[string]string{}}
+	cluster.join("node-redis-0", []string{"master"}, "", &rediscluster.SlotRange{Start: 0, End: 16383})
+	cluster.join("node-redis-1", []string{"master"}, "")
+	cluster.remaining = 8064
+
+	units := []string{"redis-0", "redis-1"}
+	r := newRedisClusterReconciler(t, startRedisClusterAgents(t, cluster, units...), newRedisClusterUnitSet(2), units...)
+
+	unitset := reconcileRedisClusterOnce(t, r)
+	require.Len(t, cluster.rebalances, 1)
+	assert.Equal(t, int64(redisClusterRebalanceBatch), cluster.rebalances[0].GetMaxSlots())
+	assert.Empty(t, cluster.replicated)
+	condition := meta.FindStatusCondition(unitset.Status.Conditions, RedisClusterScaledCondition)
+	assert.Equal(t, "Rebalancing", condition.Reason)
+	assert.Equal(t, "migrated 128 slots (1000 keys) in the last batch, 8064 slots remaining", condition.Message)
+
+	// the rebalance goes on while the condition is false, even once no master is empty anymore
+	cluster.join("node-redis-1", []string{"master"}, "", &rediscluster.SlotRange{Start: 16256, End: 16383})
+	cluster.remaining = 0
+	unitset = reconcileRedisClusterOnce(t, r)
+	assert.Len(t, cluster.rebalances, 2)
+	assert.Equal(t, metav1.ConditionTrue, meta.FindStatusCondition(unitset.Status.Conditions, RedisClusterScaledCondition).Status)
+}
+
+func TestReconcileRedisClusterRebalancesAsOperation(t *testing.T) {
+	cluster := &fakeRedisCluster{known: map[string]*rediscluster.ClusterNode{}, added: map[string]*rediscluster.AddNodeRequest{}, replicated: map[string]string{},
+		async: true, operations: map[string]*operation.Operation{}}
+	cluster.join("node-redis-0", []string{"master"}, "", &rediscluster.SlotRange{Start: 0, End: 16383})
+	cluster.join("node-redis-1", []string{"master"}, "")
+
+	units := []string{"redis-0", "redis-1"}
+	r := newRedisClusterReconciler(t, startRedisClusterAgents(t, cluster, units...), newRedisClusterUnitSet(2), units...)
+
+	// the batch is started on the seed and recorded in the status
+	unitset := reconcileRedisClusterOnce(t, r)
+	require.Len(t, cluster.rebalances, 1)
+	op, ok := unitset.Status.Operations[redisClusterRebalanceOperation]
+	require.True(t, ok)
+	assert.Equal(t, "redis-0", op.Unit)
+	assert.Contains(t, cluster.operations, op.ID)
+	assert.Equal(t, "Rebalancing", meta.FindStatusCondition(unitset.Status.Conditions, RedisClusterScaledCondition).Reason)
+
+	// a running batch is polled, not started again
+	unitset = reconcileRedisClusterOnce(t, r)
+	assert.Len(t, cluster.rebalances, 1)
+	assert.Contains(t, unitset.Status.Operations, redisClusterRebalanceOperation)
+
+	// a failed batch is dropped and started again under a new id
+	cluster.finishOperations(operation.State_FAILED)
+	key := types.NamespacedName{Namespace: "default", Name: "redis"}
+	require.NoError(t, r.Get(context.Background(), key, unitset))
+	require.Error(t, r.reconcileRedisCluster(context.Background(), ctrl.Request{NamespacedName: key}, unitset))
+	unitset = reconcileRedisClusterOnce(t, r)
+	require.Len(t, cluster.rebalances, 2)
+	assert.NotEqual(t, op.ID, unitset.Status.Operations[redisClusterRebalanceOperation].ID)
+
+	// the response of the finished batch completes the scale out
+	cluster.finishOperations(operation.State_SUCCEEDED)
+	unitset = reconcileRedisClusterOnce(t, r)
+	assert.Empty(t, unitset.Status.Operations)
+	condition := meta.FindStatusCondition(unitset.Status.Conditions, RedisClusterScaledCondition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "Balanced", condition.Reason)
+}
+
+func TestRemoveUnitsDrainsRedisCluster(t *testing.T) {
+	cluster := &fakeRedisCluster{known: map[string]*rediscluster.ClusterNode{}, added: map[string]*rediscluster.AddNodeRequest{}, replicated: map[string]string{}}
+	cluster.join("node-redis-0", []string{"master"}, "", &rediscluster.SlotRange{Start: 0, End: 8191})
+	cluster.join("node-redis-1", []string{"master"}, "", &rediscluster.SlotRange{Start: 8192, End: 12287})
+	cluster.join("node-redis-2", []string{"master"}, "", &rediscluster.SlotRange{Start: 12288, End: 16383})
+
+	units := []string{"redis-0", "redis-1", "redis-2"}
+	r, kUnits := newUnitAgentReconciler(t, startRedisClusterAgents(t, cluster, units...), newRedisClusterUnitSet(2), units...)
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "default", Name: "redis"}
+
+	// the unit of the scale in is kept while its master owns slots
+	unitset := &upmiov1alpha2.UnitSet{}
+	require.NoError(t, r.Get(ctx, key, unitset))
+	_, err := r.removeUnits(ctx, unitset, kUnits)
+	require.NoError(t, err)
+	require.Len(t, cluster.rebalances, 1)
+	assert.Equal(t, []string{"node-redis-2"}, cluster.rebalances[0].GetDrainIds())
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Namespace: "default", Name: "redis-2"}, &upmiov1alpha2.Unit{}))
+	require.NoError(t, r.Get(ctx, key, unitset))
+	assert.Equal(t, "Draining", meta.FindStatusCondition(unitset.Status.Conditions, RedisClusterScaledCondition).Reason)
+
+	// and removed once drained
+	cluster.join("node-redis-2", []string{"master"}, "")
+	_, err = r.removeUnits(ctx, unitset, kUnits)
+	require.NoError(t, err)
+	assert.Len(t, cluster.rebalances, 1)
+	err = r.Get(ctx, types.NamespacedName{Namespace: "default", Name: "redis-2"}, &upmiov1alpha2.Unit{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileRedisClusterForgetsRemovedNodes(t *testing.T) {
+	cluster := &fakeRedisCluster{known: map[string]*rediscluster.ClusterNode{}, added: map[string]*rediscluster.AddNodeRequest{}, replicated: map[string]string{}}
+	cluster.join("node-redis-0", []string{"master"}, "", &rediscluster.SlotRange{Start: 0, End: 8191})
+	cluster.join("node-redis-1", []string{"master"}, "", &rediscluster.SlotRange{Start: 8192, End: 16383})
+	cluster.join("node-redis-2", []string{"master", "fail?"}, "")
+
+	units := []string{"redis-0", "redis-1"}
+	r := newRedisClusterReconciler(t, startRedisClusterAgents(t, cluster, units...), newRedisClusterUnitSet(2), units...)
+
+	// the node of the removed unit is waited for until the cluster failed it
+	unitset := reconcileRedisClusterOnce(t, r)
+	assert.Empty(t, cluster.forgotten)
+	assert.Empty(t, cluster.rebalances)
+	assert.Equal(t, "NodesLeaving", meta.FindStatusCondition(unitset.Status.Conditions, RedisClusterScaledCondition).Reason)
+
+	cluster.join("node-redis-2", []string{"master", "fail"}, "")
+	unitset = reconcileRedisClusterOnce(t, r)
+	assert.Equal(t, []string{"node-redis-2"}, cluster.forgotten)
+	assert.NotContains(t, cluster.known, "node-redis-2")
+	assert.Equal(t, "Balanced", meta.FindStatusCondition(unitset.Status.Conditions, RedisClusterScaledCondition).Reason)
+}
+
+func TestReconcileRedisClusterSkipped(t *testing.T) {
+	cluster := &fakeRedisCluster{known: map[string]*rediscluster.ClusterNode{}, added: map[string]*rediscluster.AddNodeRequest{}, replicated: map[string]string{}}
+	units := []string{"redis-0", "redis-1"}
+	dial := startRedisClusterAgents(t, cluster, units...)
+
+	// no unit owns slots, the cluster is not created yet
+	r := newRedisClusterReconciler(t, dial, newRedisClusterUnitSet(2), units...)
+	unitset := reconcileRedisClusterOnce(t, r)
+	assert.Empty(t, cluster.added)
+	assert.Empty(t, unitset.Status.Conditions)
+
+	// units still being created
+	cluster.join("node-redis-0", []string{"master"}, "", &rediscluster.SlotRange{Start: 0, End: 16383})
+	r = newRedisClusterReconciler(t, dial, newRedisClusterUnitSet(3), units...)
+	reconcileRedisClusterOnce(t, r)
+	assert.Empty(t, cluster.added)
+
+	// not a redis cluster
+	unitset = newRedisClusterUnitSet(2)
+	unitset.Spec.Env = nil
+	r = newRedisClusterReconciler(t, dial, unitset, units...)
+	reconcileRedisClusterOnce(t, r)
+	assert.Empty(t, cluster.added)
+}
+
+func TestRedisClusterReplicas(t *testing.T) {
+	unitset := newRedisClusterUnitSet(3)
+
+	replicas, err := redisClusterReplicas(unitset)
+	require.NoError(t, err)
+	assert.Zero(t, replicas)
+
+	unitset.Annotations = map[string]string{upmiov1alpha2.AnnotationRedisClusterReplicas: "2"}
+	replicas, err = redisClusterReplicas(unitset)
+	require.NoError(t, err)
+	assert.Equal(t, 2, replicas)
+
+	unitset.Annotations[upmiov1alpha2.AnnotationRedisClusterReplicas] = "-1"
+	_, err = redisClusterReplicas(unitset)
+	assert.Error(t, err)
+}
@@ -55,6 +55,7 @@ func (r *UnitSetReconciler) reconcileUnit(
 				}
 
 				unit := fillUnitPersonalizedInfo(unitTemplate, unitset, unitNamesWithIndex, unitName)
+				markUnitBootstrapping(unitset, unit)
 				needUpdateObservedGeneration.Store(true)
 
 				err = r.Create(uctx, unit)
@@ -107,6 +108,15 @@ func (r *UnitSetReconciler) removeUnits(ctx context.Context, unitset *upmiov1alp
 		return kUnits, nil
 	}
 
+	// the masters leaving a Redis Cluster hand their slots over before any unit is removed
+	drained, err := r.drainRedisCluster(ctx, unitset, kUnits)
+	if err != nil {
+		return nil, fmt.Errorf("[removeUnits] drain redis cluster error:[%s]", err.Error())
+	}
+	if !drained {
+		return kUnits, nil
+	}
+
 	out := []*upmiov1alpha2.Unit{}
 	for _, one := range kUnits {
 		serialNumber, err := strconv.Atoi(one.Labels[upmiov1alpha2.UnitSn])
@@ -119,6 +129,11 @@ func (r *UnitSetReconciler) removeUnits(ctx context.Context, unitset *upmiov1alp
 			continue
 		}
 
+		// the member leaves the replica set while the remaining members still hold the majority
+		if err := r.leaveMongoDBReplicaSet(ctx, unitset, kUnits, one); err != nil {
+			return nil, fmt.Errorf("[removeUnits] unit:[%s] leave replica set error:[%s]", one.Name, err.Error())
+		}
+
 		err = r.Delete(ctx, one)
 		if err != nil {
 			if apierrors.IsNotFound(err) {